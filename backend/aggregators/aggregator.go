@@ -0,0 +1,142 @@
+// Package aggregators is a registry of pluggable bank-data providers
+// (Plaid, GoCardless/Nordigen, TrueLayer), mirroring the way
+// controllers/aidriver lets each AI backend register itself under a name
+// instead of controllers hardcoding one vendor's SDK. It replaces
+// BankConnection.BankEndpoint == "plaid://api" and Plaid-shaped
+// Metadata keys ("access_token", "item_id") being the only bank-linking
+// path, so a user whose bank Plaid doesn't cover (e.g. most Norwegian
+// banks) can still link through a PSD2/Open Banking aggregator instead.
+package aggregators
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LinkSession is what a caller hands to its link-initiation UI - Plaid
+// Link's token, or the redirect URL a requisition/redirect-based flow
+// (GoCardless, TrueLayer) needs instead.
+type LinkSession struct {
+	Token       string
+	RedirectURL string
+	ExpiresAt   time.Time
+}
+
+// Credentials is whatever an aggregator needs on every subsequent call
+// against one linked item - Plaid's access_token, GoCardless's requisition
+// ID, TrueLayer's refresh token, etc. Stored verbatim in
+// BankConnection.Metadata, the same way Plaid's access_token/item_id
+// already are.
+type Credentials map[string]interface{}
+
+// RawAccount is one account as an aggregator reports it, before
+// controllers maps it to a models.BankAccount/models.Account pair.
+type RawAccount struct {
+	ID           string
+	Name         string
+	Currency     string
+	Type         string
+	BalanceCents int64
+}
+
+// RawTransaction is one transaction as an aggregator reports it, before
+// controllers maps it to a models.Transaction.
+type RawTransaction struct {
+	ID          string
+	AccountID   string
+	Name        string
+	AmountCents int64
+	Date        string // YYYY-MM-DD
+	Categories  []string
+}
+
+// SyncDelta is one page of an incremental transaction sync, mirroring
+// Plaid's /transactions/sync response shape since it's the richest of the
+// three providers' sync semantics.
+type SyncDelta struct {
+	Added      []RawTransaction
+	Modified   []RawTransaction
+	RemovedIDs []string
+	NextCursor string
+	HasMore    bool
+}
+
+// Config configures a BankAggregator instance - a client ID/secret/
+// environment triple is enough for every built-in aggregator.
+type Config struct {
+	ClientID    string
+	Secret      string
+	Environment string
+}
+
+// BankAggregator is implemented once per upstream bank-data provider so
+// controllers can link/sync an account without hardcoding which provider a
+// user picked. See aggregators/plaid, aggregators/gocardless,
+// aggregators/truelayer.
+type BankAggregator interface {
+	Name() string
+	CreateLinkSession(ctx context.Context, userID uint) (LinkSession, error)
+	ExchangeToken(ctx context.Context, publicToken string) (Credentials, error)
+	ListAccounts(ctx context.Context, cred Credentials) ([]RawAccount, error)
+	SyncTransactions(ctx context.Context, cred Credentials, cursor string) (SyncDelta, error)
+	Disconnect(ctx context.Context, cred Credentials) error
+}
+
+// Factory constructs a BankAggregator from Config - called once per
+// Activate, so e.g. a sandbox vs. production Plaid setup never shares a
+// client.
+type Factory func(Config) (BankAggregator, error)
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+	active    = make(map[string]BankAggregator)
+)
+
+// Register adds a named aggregator factory. Built-in aggregators call this
+// from an init() in their own package, the same way each
+// migrations/NNNN_*.go file registers itself. Registering the same name
+// twice overwrites the previous factory.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories[name] = factory
+}
+
+// Activate constructs the named aggregator via its registered factory and
+// makes it available to Get. Call once per provider at startup with that
+// provider's credentials (see main.go); a provider with no credentials
+// configured is simply never activated.
+func Activate(name string, cfg Config) error {
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("aggregators: unknown provider %q", name)
+	}
+
+	agg, err := factory(cfg)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	active[name] = agg
+	mu.Unlock()
+	return nil
+}
+
+// Get returns the active aggregator for provider, or an error if it was
+// never Activated (e.g. missing credentials at startup, or an unknown
+// provider name).
+func Get(provider string) (BankAggregator, error) {
+	mu.RLock()
+	agg, ok := active[provider]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("aggregators: provider %q not active", provider)
+	}
+	return agg, nil
+}
@@ -0,0 +1,48 @@
+// Package gocardless is a stub aggregators.BankAggregator for GoCardless
+// Bank Account Data (formerly Nordigen), the PSD2/Open Banking aggregator
+// covering the Norwegian, UK and wider EU markets already listed in
+// CreateLinkToken's plaid.CountryCode set - the real requisition/account
+// HTTP client isn't wired up yet, so every method returns an error until it
+// is. Registering it now still lets BankConnection.Provider == "gocardless"
+// round-trip through the rest of the stack (routes, migrations) ahead of
+// the real implementation landing.
+package gocardless
+
+import (
+	"context"
+	"errors"
+
+	"Personal-Finance-Tracker-backend/aggregators"
+)
+
+var errNotImplemented = errors.New("gocardless aggregator not implemented yet")
+
+func init() {
+	aggregators.Register("gocardless", func(cfg aggregators.Config) (aggregators.BankAggregator, error) {
+		return &aggregator{}, nil
+	})
+}
+
+type aggregator struct{}
+
+func (a *aggregator) Name() string { return "gocardless" }
+
+func (a *aggregator) CreateLinkSession(ctx context.Context, userID uint) (aggregators.LinkSession, error) {
+	return aggregators.LinkSession{}, errNotImplemented
+}
+
+func (a *aggregator) ExchangeToken(ctx context.Context, publicToken string) (aggregators.Credentials, error) {
+	return nil, errNotImplemented
+}
+
+func (a *aggregator) ListAccounts(ctx context.Context, cred aggregators.Credentials) ([]aggregators.RawAccount, error) {
+	return nil, errNotImplemented
+}
+
+func (a *aggregator) SyncTransactions(ctx context.Context, cred aggregators.Credentials, cursor string) (aggregators.SyncDelta, error) {
+	return aggregators.SyncDelta{}, errNotImplemented
+}
+
+func (a *aggregator) Disconnect(ctx context.Context, cred aggregators.Credentials) error {
+	return errNotImplemented
+}
@@ -0,0 +1,135 @@
+// Package plaid implements aggregators.BankAggregator on top of the Plaid
+// API, wrapping the same calls controllers/plaid_api.go makes directly.
+// It owns its own *plaidsdk.APIClient rather than sharing
+// controllers.PlaidClient, since aggregators/* can't import controllers
+// without an import cycle (controllers dispatches into this package
+// through the aggregators registry this file's init() adds it to).
+package plaid
+
+import (
+	"context"
+	"fmt"
+
+	"Personal-Finance-Tracker-backend/aggregators"
+
+	plaidsdk "github.com/plaid/plaid-go/v29/plaid"
+)
+
+func init() {
+	aggregators.Register("plaid", func(cfg aggregators.Config) (aggregators.BankAggregator, error) {
+		var env plaidsdk.Environment
+		switch cfg.Environment {
+		case "production":
+			env = plaidsdk.Production
+		default:
+			env = plaidsdk.Sandbox
+		}
+
+		configuration := plaidsdk.NewConfiguration()
+		configuration.AddDefaultHeader("PLAID-CLIENT-ID", cfg.ClientID)
+		configuration.AddDefaultHeader("PLAID-SECRET", cfg.Secret)
+		configuration.UseEnvironment(env)
+
+		return &aggregator{client: plaidsdk.NewAPIClient(configuration)}, nil
+	})
+}
+
+type aggregator struct {
+	client *plaidsdk.APIClient
+}
+
+func (a *aggregator) Name() string { return "plaid" }
+
+func (a *aggregator) CreateLinkSession(ctx context.Context, userID uint) (aggregators.LinkSession, error) {
+	user := plaidsdk.LinkTokenCreateRequestUser{ClientUserId: fmt.Sprintf("user_%d", userID)}
+	request := plaidsdk.NewLinkTokenCreateRequest(
+		"Personal Finance Tracker",
+		"en",
+		[]plaidsdk.CountryCode{plaidsdk.COUNTRYCODE_NO, plaidsdk.COUNTRYCODE_GB, plaidsdk.COUNTRYCODE_US},
+		user,
+	)
+	request.SetProducts([]plaidsdk.Products{plaidsdk.PRODUCTS_AUTH, plaidsdk.PRODUCTS_TRANSACTIONS})
+
+	resp, _, err := a.client.PlaidApi.LinkTokenCreate(ctx).LinkTokenCreateRequest(*request).Execute()
+	if err != nil {
+		return aggregators.LinkSession{}, err
+	}
+	return aggregators.LinkSession{Token: resp.GetLinkToken(), ExpiresAt: resp.GetExpiration()}, nil
+}
+
+func (a *aggregator) ExchangeToken(ctx context.Context, publicToken string) (aggregators.Credentials, error) {
+	request := plaidsdk.NewItemPublicTokenExchangeRequest(publicToken)
+	resp, _, err := a.client.PlaidApi.ItemPublicTokenExchange(ctx).ItemPublicTokenExchangeRequest(*request).Execute()
+	if err != nil {
+		return nil, err
+	}
+	return aggregators.Credentials{
+		"access_token": resp.GetAccessToken(),
+		"item_id":      resp.GetItemId(),
+	}, nil
+}
+
+func (a *aggregator) ListAccounts(ctx context.Context, cred aggregators.Credentials) ([]aggregators.RawAccount, error) {
+	accessToken, _ := cred["access_token"].(string)
+	request := plaidsdk.NewAccountsGetRequest(accessToken)
+	resp, _, err := a.client.PlaidApi.AccountsGet(ctx).AccountsGetRequest(*request).Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]aggregators.RawAccount, 0, len(resp.GetAccounts()))
+	for _, acc := range resp.GetAccounts() {
+		balances := acc.GetBalances()
+		accounts = append(accounts, aggregators.RawAccount{
+			ID:           acc.GetAccountId(),
+			Name:         acc.GetName(),
+			Currency:     balances.GetIsoCurrencyCode(),
+			Type:         string(acc.GetSubtype()),
+			BalanceCents: int64(balances.GetCurrent() * 100),
+		})
+	}
+	return accounts, nil
+}
+
+func (a *aggregator) SyncTransactions(ctx context.Context, cred aggregators.Credentials, cursor string) (aggregators.SyncDelta, error) {
+	accessToken, _ := cred["access_token"].(string)
+	request := plaidsdk.NewTransactionsSyncRequest(accessToken)
+	if cursor != "" {
+		request.SetCursor(cursor)
+	}
+
+	resp, _, err := a.client.PlaidApi.TransactionsSync(ctx).TransactionsSyncRequest(*request).Execute()
+	if err != nil {
+		return aggregators.SyncDelta{}, err
+	}
+
+	delta := aggregators.SyncDelta{NextCursor: resp.GetNextCursor(), HasMore: resp.GetHasMore()}
+	for _, txn := range resp.GetAdded() {
+		delta.Added = append(delta.Added, toRawTransaction(txn))
+	}
+	for _, txn := range resp.GetModified() {
+		delta.Modified = append(delta.Modified, toRawTransaction(txn))
+	}
+	for _, txn := range resp.GetRemoved() {
+		delta.RemovedIDs = append(delta.RemovedIDs, txn.GetTransactionId())
+	}
+	return delta, nil
+}
+
+func (a *aggregator) Disconnect(ctx context.Context, cred aggregators.Credentials) error {
+	accessToken, _ := cred["access_token"].(string)
+	request := plaidsdk.NewItemRemoveRequest(accessToken)
+	_, _, err := a.client.PlaidApi.ItemRemove(ctx).ItemRemoveRequest(*request).Execute()
+	return err
+}
+
+func toRawTransaction(txn plaidsdk.Transaction) aggregators.RawTransaction {
+	return aggregators.RawTransaction{
+		ID:          txn.GetTransactionId(),
+		AccountID:   txn.GetAccountId(),
+		Name:        txn.GetName(),
+		AmountCents: int64(-txn.GetAmount() * 100),
+		Date:        txn.GetDate(),
+		Categories:  txn.GetCategory(),
+	}
+}
@@ -0,0 +1,46 @@
+// Package saltedge is a stub aggregators.BankAggregator for SaltEdge, the
+// OAuth-style Open Banking aggregator - see aggregators/gocardless's doc
+// comment for why a stub is registered ahead of the real HTTP client
+// landing. Registering it now still lets BankConnection.Provider ==
+// "saltedge" round-trip through the rest of the stack (routes, migrations)
+// ahead of the real implementation landing.
+package saltedge
+
+import (
+	"context"
+	"errors"
+
+	"Personal-Finance-Tracker-backend/aggregators"
+)
+
+var errNotImplemented = errors.New("saltedge aggregator not implemented yet")
+
+func init() {
+	aggregators.Register("saltedge", func(cfg aggregators.Config) (aggregators.BankAggregator, error) {
+		return &aggregator{}, nil
+	})
+}
+
+type aggregator struct{}
+
+func (a *aggregator) Name() string { return "saltedge" }
+
+func (a *aggregator) CreateLinkSession(ctx context.Context, userID uint) (aggregators.LinkSession, error) {
+	return aggregators.LinkSession{}, errNotImplemented
+}
+
+func (a *aggregator) ExchangeToken(ctx context.Context, publicToken string) (aggregators.Credentials, error) {
+	return nil, errNotImplemented
+}
+
+func (a *aggregator) ListAccounts(ctx context.Context, cred aggregators.Credentials) ([]aggregators.RawAccount, error) {
+	return nil, errNotImplemented
+}
+
+func (a *aggregator) SyncTransactions(ctx context.Context, cred aggregators.Credentials, cursor string) (aggregators.SyncDelta, error) {
+	return aggregators.SyncDelta{}, errNotImplemented
+}
+
+func (a *aggregator) Disconnect(ctx context.Context, cred aggregators.Credentials) error {
+	return errNotImplemented
+}
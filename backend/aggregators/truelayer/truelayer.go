@@ -0,0 +1,43 @@
+// Package truelayer is a stub aggregators.BankAggregator for TrueLayer, the
+// UK/EU Open Banking aggregator - see aggregators/gocardless's doc comment
+// for why a stub is registered ahead of the real HTTP client landing.
+package truelayer
+
+import (
+	"context"
+	"errors"
+
+	"Personal-Finance-Tracker-backend/aggregators"
+)
+
+var errNotImplemented = errors.New("truelayer aggregator not implemented yet")
+
+func init() {
+	aggregators.Register("truelayer", func(cfg aggregators.Config) (aggregators.BankAggregator, error) {
+		return &aggregator{}, nil
+	})
+}
+
+type aggregator struct{}
+
+func (a *aggregator) Name() string { return "truelayer" }
+
+func (a *aggregator) CreateLinkSession(ctx context.Context, userID uint) (aggregators.LinkSession, error) {
+	return aggregators.LinkSession{}, errNotImplemented
+}
+
+func (a *aggregator) ExchangeToken(ctx context.Context, publicToken string) (aggregators.Credentials, error) {
+	return nil, errNotImplemented
+}
+
+func (a *aggregator) ListAccounts(ctx context.Context, cred aggregators.Credentials) ([]aggregators.RawAccount, error) {
+	return nil, errNotImplemented
+}
+
+func (a *aggregator) SyncTransactions(ctx context.Context, cred aggregators.Credentials, cursor string) (aggregators.SyncDelta, error) {
+	return aggregators.SyncDelta{}, errNotImplemented
+}
+
+func (a *aggregator) Disconnect(ctx context.Context, cred aggregators.Credentials) error {
+	return errNotImplemented
+}
@@ -0,0 +1,35 @@
+package config
+
+import "os"
+
+// EnvProvider resolves configuration from process environment variables -
+// the default for local development and single-instance deployments.
+type EnvProvider struct{}
+
+func (EnvProvider) Get(key string) (string, bool) {
+	return os.LookupEnv(envKey(key))
+}
+
+// Watch is a no-op: environment variables can't change for a running
+// process, so there's nothing to hot-reload.
+func (EnvProvider) Watch(prefix string, onChange func(key, value string)) error {
+	return nil
+}
+
+// envKey maps a dotted config key (e.g. "auth.registration_open") to the
+// SCREAMING_SNAKE_CASE convention this codebase already uses for
+// environment variables (DB_HOST, JWT_SECRET, GOOGLE_CLIENT_ID, ...).
+func envKey(key string) string {
+	out := make([]byte, 0, len(key))
+	for _, r := range key {
+		switch {
+		case r == '.':
+			out = append(out, '_')
+		case r >= 'a' && r <= 'z':
+			out = append(out, byte(r-'a'+'A'))
+		default:
+			out = append(out, byte(r))
+		}
+	}
+	return string(out)
+}
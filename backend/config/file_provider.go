@@ -0,0 +1,99 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileProvider resolves configuration from a flat KEY=VALUE file (one pair
+// per line, blank lines and '#' comments ignored), re-reading it every
+// WatchPollInterval so an operator can hot-reload flags by editing the
+// file without restarting the process.
+type FileProvider struct {
+	path string
+
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewFileProvider loads path immediately, so a missing or unreadable file
+// fails fast at startup rather than on the first Get.
+func NewFileProvider(path string) (*FileProvider, error) {
+	p := &FileProvider{path: path}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *FileProvider) Get(key string) (string, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	v, ok := p.values[key]
+	return v, ok
+}
+
+func (p *FileProvider) reload() error {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.values = values
+	p.mu.Unlock()
+	return nil
+}
+
+// Watch polls the file every WatchPollInterval and calls onChange for any
+// key under prefix whose value changed since the previous read.
+func (p *FileProvider) Watch(prefix string, onChange func(key, value string)) error {
+	go func() {
+		for {
+			time.Sleep(WatchPollInterval)
+
+			p.mu.RLock()
+			before := p.values
+			p.mu.RUnlock()
+
+			if err := p.reload(); err != nil {
+				continue
+			}
+
+			p.mu.RLock()
+			after := p.values
+			p.mu.RUnlock()
+
+			for key, value := range after {
+				if !strings.HasPrefix(key, prefix) {
+					continue
+				}
+				if before[key] != value {
+					onChange(key, value)
+				}
+			}
+		}
+	}()
+	return nil
+}
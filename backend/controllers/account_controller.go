@@ -1,17 +1,99 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"Personal-Finance-Tracker-backend/db"
-	"Personal-Finance-Tracker-backend/models"
 	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/cascade"
+	"Personal-Finance-Tracker-backend/services/psd2"
 
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
-// GetAccounts retrieves all accounts for the authenticated user
+// validAccountTypes is shared by CreateAccount and UpdateAccount so adding a
+// new models.AccountType only means touching this one list.
+var validAccountTypes = map[models.AccountType]bool{
+	models.AccountCash:       true,
+	models.AccountChecking:   true,
+	models.AccountSavings:    true,
+	models.AccountCredit:     true,
+	models.AccountInvestment: true,
+	models.AccountOther:      true,
+	models.AccountBank:       true,
+	models.AccountAsset:      true,
+	models.AccountLiability:  true,
+	models.AccountIncome:     true,
+	models.AccountExpense:    true,
+	models.AccountTrading:    true,
+	models.AccountEquity:     true,
+	models.AccountReceivable: true,
+	models.AccountPayable:    true,
+}
+
+// accountIsAncestorOf reports whether candidateID appears somewhere above
+// accountID in the ParentAccountID chain, i.e. whether making candidateID a
+// child of accountID (or reparenting accountID under it) would close a
+// cycle. Walks from accountID upward rather than the other way, since the
+// parent chain is the direction Account.ParentAccountID actually points.
+func accountIsAncestorOf(accountID, candidateID uint) bool {
+	current := accountID
+	for i := 0; i < 1000; i++ { // defensive bound against a pre-existing cycle
+		var account models.Account
+		if err := db.DB.Select("parent_account_id").First(&account, current).Error; err != nil {
+			return false
+		}
+		if account.ParentAccountID == nil {
+			return false
+		}
+		if *account.ParentAccountID == candidateID {
+			return true
+		}
+		current = *account.ParentAccountID
+	}
+	return false
+}
+
+// descendantAccountIDs returns every account (recursively) nested under
+// accountID via ParentAccountID, not including accountID itself - used so
+// UpdateAccountBalance and GetAccountBalanceAsOf can fold a parent
+// account's descendants' activity into its own balance.
+func descendantAccountIDs(userID, accountID uint) []uint {
+	var children []uint
+	db.DB.Model(&models.Account{}).
+		Where("user_id = ? AND parent_account_id = ?", userID, accountID).
+		Pluck("id", &children)
+
+	descendants := append([]uint{}, children...)
+	for _, childID := range children {
+		descendants = append(descendants, descendantAccountIDs(userID, childID)...)
+	}
+	return descendants
+}
+
+// accountSortColumns maps GetAccounts' ?sort= values to the column they
+// order by; a leading "-" (e.g. "-balance") reverses to descending.
+var accountSortColumns = map[string]string{
+	"name":       "name",
+	"balance":    "current_balance",
+	"created_at": "created_at",
+}
+
+// GetAccounts searches the authenticated user's accounts with a query
+// modeled on PhotoPrism's form.AccountSearch: ?q= substring-matches
+// name/description, ?type= (repeatable) filters by AccountType,
+// ?min_balance_cents=/?max_balance_cents= bound Account.CurrentBalance, ?sort=
+// orders by name|balance|created_at (prefix "-" for descending), and
+// ?count=/?offset= page the results. Pass ?include_archived=true to also
+// see soft-deleted accounts.
 func GetAccounts(c *gin.Context) {
 	// Extract JWT claims from context
 	claims, exists := c.Get("user")
@@ -22,16 +104,146 @@ func GetAccounts(c *gin.Context) {
 
 	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
 
+	query := db.DB.Model(&models.Account{}).Where("user_id = ?", userID)
+	if c.Query("include_archived") == "true" {
+		query = query.Unscoped()
+	}
+
+	if q := c.Query("q"); q != "" {
+		like := "%" + q + "%"
+		query = query.Where("name LIKE ? OR description LIKE ?", like, like)
+	}
+	if types := c.QueryArray("type"); len(types) > 0 {
+		query = query.Where("type IN ?", types)
+	}
+	if raw := c.Query("min_balance_cents"); raw != "" {
+		if min, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			query = query.Where("current_balance >= ?", decimal.NewFromInt(min).Div(decimal.NewFromInt(100)))
+		}
+	}
+	if raw := c.Query("max_balance_cents"); raw != "" {
+		if max, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			query = query.Where("current_balance <= ?", decimal.NewFromInt(max).Div(decimal.NewFromInt(100)))
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch accounts"})
+		return
+	}
+
+	count := defaultPageLimit
+	if raw := c.Query("count"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			count = n
+		}
+	}
+	if count > maxPageLimit {
+		count = maxPageLimit
+	}
+	offset := 0
+	if raw := c.Query("offset"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	sort := "created_at"
+	desc := false
+	if raw := c.Query("sort"); raw != "" {
+		if strings.HasPrefix(raw, "-") {
+			desc = true
+			raw = raw[1:]
+		}
+		if column, ok := accountSortColumns[raw]; ok {
+			sort = column
+		}
+	}
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
 	var accounts []models.Account
-	result := db.DB.Where("user_id = ?", userID).Find(&accounts)
-	if result.Error != nil {
+	if err := query.Order(sort + " " + direction).Limit(count).Offset(offset).Find(&accounts).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch accounts"})
 		return
 	}
 
+	c.Header("X-Count", strconv.FormatInt(total, 10))
+	c.Header("X-Limit", strconv.Itoa(count))
+	c.Header("X-Offset", strconv.Itoa(offset))
+	if int64(offset+count) < total {
+		nextURL := *c.Request.URL
+		nextQuery := nextURL.Query()
+		nextQuery.Set("offset", strconv.Itoa(offset+count))
+		nextQuery.Set("count", strconv.Itoa(count))
+		nextURL.RawQuery = nextQuery.Encode()
+		c.Header("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextURL.String()))
+	}
+
 	c.JSON(http.StatusOK, accounts)
 }
 
+// accountTypeSummary is one row of GetAccountsSummary's per-type breakdown.
+type accountTypeSummary struct {
+	Type    models.AccountType `json:"type"`
+	Count   int64              `json:"count"`
+	Balance decimal.Decimal    `json:"balance"`
+}
+
+// assetAccountTypes/liabilityAccountTypes classify AccountType values for
+// GetAccountsSummary's net-worth rollup.
+var assetAccountTypes = []models.AccountType{
+	models.AccountCash, models.AccountChecking, models.AccountSavings,
+	models.AccountInvestment, models.AccountBank, models.AccountAsset,
+	models.AccountReceivable, models.AccountTrading,
+}
+var liabilityAccountTypes = []models.AccountType{
+	models.AccountCredit, models.AccountLiability, models.AccountPayable,
+}
+
+// GetAccountsSummary returns the authenticated user's account balances
+// grouped by AccountType, plus asset/liability/net-worth totals, computed
+// in SQL so the frontend doesn't need to fetch and sum every account.
+func GetAccountsSummary(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var byType []accountTypeSummary
+	if err := db.DB.Model(&models.Account{}).
+		Select("type, COUNT(*) AS count, COALESCE(SUM(current_balance), 0) AS balance").
+		Where("user_id = ?", userID).
+		Group("type").
+		Scan(&byType).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute account summary"})
+		return
+	}
+
+	var assetTotal, liabilityTotal decimal.NullDecimal
+	db.DB.Model(&models.Account{}).
+		Where("user_id = ? AND type IN ?", userID, assetAccountTypes).
+		Select("COALESCE(SUM(current_balance), 0)").
+		Scan(&assetTotal)
+	db.DB.Model(&models.Account{}).
+		Where("user_id = ? AND type IN ?", userID, liabilityAccountTypes).
+		Select("COALESCE(SUM(current_balance), 0)").
+		Scan(&liabilityTotal)
+	assetCents, liabilityCents := centsOf(assetTotal.Decimal), centsOf(liabilityTotal.Decimal)
+
+	c.JSON(http.StatusOK, gin.H{
+		"by_type":         byType,
+		"asset_cents":     assetCents,
+		"liability_cents": liabilityCents,
+		"net_worth_cents": assetCents - liabilityCents,
+	})
+}
+
 // GetAccount retrieves a specific account by ID for the authenticated user
 func GetAccount(c *gin.Context) {
 	// Extract JWT claims from context
@@ -70,6 +282,14 @@ func CreateAccount(c *gin.Context) {
 		AccountType         models.AccountType `json:"account_type" binding:"required"`
 		InitialBalanceCents int64              `json:"initial_balance_cents"`
 		Description         string             `json:"description"`
+		ParentAccountID     *uint              `json:"parent_account_id"`
+		OFXURL              string             `json:"ofx_url"`
+		OFXOrg              string             `json:"ofx_org"`
+		OFXFID              string             `json:"ofx_fid"`
+		OFXUser             string             `json:"ofx_user"`
+		OFXPassword         string             `json:"ofx_password"`
+		OFXBankID           string             `json:"ofx_bank_id"`
+		OFXAcctType         string             `json:"ofx_acct_type"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -77,28 +297,44 @@ func CreateAccount(c *gin.Context) {
 		return
 	}
 
-	// Validate account type
-	validTypes := map[models.AccountType]bool{
-		models.AccountCash:       true,
-		models.AccountChecking:   true,
-		models.AccountSavings:    true,
-		models.AccountCredit:     true,
-		models.AccountInvestment: true,
-		models.AccountOther:      true,
-	}
-
-	if !validTypes[request.AccountType] {
+	if !validAccountTypes[request.AccountType] {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account type"})
 		return
 	}
 
+	if request.ParentAccountID != nil {
+		var parent models.Account
+		if err := db.DB.Where("id = ? AND user_id = ?", *request.ParentAccountID, userID).First(&parent).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "parent account not found or does not belong to user"})
+			return
+		}
+	}
+
+	var encryptedOFXPassword string
+	if request.OFXPassword != "" {
+		encrypted, err := psd2.EncryptSecret(request.OFXPassword)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encrypt OFX password"})
+			return
+		}
+		encryptedOFXPassword = encrypted
+	}
+
 	account := models.Account{
 		UserID:              userID,
 		Name:                request.Name,
 		Type:                request.AccountType,
 		InitialBalanceCents: request.InitialBalanceCents,
-		CurrentBalanceCents: request.InitialBalanceCents, // Start with initial balance
+		CurrentBalance:      decimal.NewFromInt(request.InitialBalanceCents).Div(decimal.NewFromInt(100)), // Start with initial balance
 		Description:         request.Description,
+		ParentAccountID:     request.ParentAccountID,
+		OFXURL:              request.OFXURL,
+		OFXOrg:              request.OFXOrg,
+		OFXFID:              request.OFXFID,
+		OFXUser:             request.OFXUser,
+		OFXPassword:         encryptedOFXPassword,
+		OFXBankID:           request.OFXBankID,
+		OFXAcctType:         request.OFXAcctType,
 	}
 
 	result := db.DB.Create(&account)
@@ -136,6 +372,15 @@ func UpdateAccount(c *gin.Context) {
 		AccountType         models.AccountType `json:"account_type"`
 		InitialBalanceCents *int64             `json:"initial_balance_cents"`
 		Description         string             `json:"description"`
+		ParentAccountID     *uint              `json:"parent_account_id"`
+		ClearParent         bool               `json:"clear_parent"`
+		OFXURL              *string            `json:"ofx_url"`
+		OFXOrg              *string            `json:"ofx_org"`
+		OFXFID              *string            `json:"ofx_fid"`
+		OFXUser             *string            `json:"ofx_user"`
+		OFXPassword         *string            `json:"ofx_password"`
+		OFXBankID           *string            `json:"ofx_bank_id"`
+		OFXAcctType         *string            `json:"ofx_acct_type"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -145,16 +390,7 @@ func UpdateAccount(c *gin.Context) {
 
 	// Validate account type if provided
 	if request.AccountType != "" {
-		validTypes := map[models.AccountType]bool{
-			models.AccountCash:       true,
-			models.AccountChecking:   true,
-			models.AccountSavings:    true,
-			models.AccountCredit:     true,
-			models.AccountInvestment: true,
-			models.AccountOther:      true,
-		}
-
-		if !validTypes[request.AccountType] {
+		if !validAccountTypes[request.AccountType] {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account type"})
 			return
 		}
@@ -167,11 +403,60 @@ func UpdateAccount(c *gin.Context) {
 
 	account.Description = request.Description
 
+	if request.ClearParent {
+		account.ParentAccountID = nil
+	} else if request.ParentAccountID != nil {
+		if *request.ParentAccountID == account.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "account cannot be its own parent"})
+			return
+		}
+
+		var parent models.Account
+		if err := db.DB.Where("id = ? AND user_id = ?", *request.ParentAccountID, userID).First(&parent).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "parent account not found or does not belong to user"})
+			return
+		}
+
+		if accountIsAncestorOf(account.ID, *request.ParentAccountID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "reparenting here would create a cycle"})
+			return
+		}
+
+		account.ParentAccountID = request.ParentAccountID
+	}
+
 	// Update initial balance if provided
 	if request.InitialBalanceCents != nil {
 		account.InitialBalanceCents = *request.InitialBalanceCents
 	}
 
+	if request.OFXURL != nil {
+		account.OFXURL = *request.OFXURL
+	}
+	if request.OFXOrg != nil {
+		account.OFXOrg = *request.OFXOrg
+	}
+	if request.OFXFID != nil {
+		account.OFXFID = *request.OFXFID
+	}
+	if request.OFXUser != nil {
+		account.OFXUser = *request.OFXUser
+	}
+	if request.OFXBankID != nil {
+		account.OFXBankID = *request.OFXBankID
+	}
+	if request.OFXAcctType != nil {
+		account.OFXAcctType = *request.OFXAcctType
+	}
+	if request.OFXPassword != nil {
+		encrypted, err := psd2.EncryptSecret(*request.OFXPassword)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encrypt OFX password"})
+			return
+		}
+		account.OFXPassword = encrypted
+	}
+
 	result = db.DB.Save(&account)
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update account"})
@@ -191,7 +476,12 @@ func UpdateAccount(c *gin.Context) {
 	c.JSON(http.StatusOK, account)
 }
 
-// DeleteAccount deletes an account for the authenticated user
+// DeleteAccount archives an account for the authenticated user: by default
+// it soft-deletes the account and its transactions (see services/cascade's
+// "account" graph), restorable via RestoreAccount. Pass ?purge=true with a
+// body of {"confirm":"<account name>"} to permanently hard-delete instead -
+// the confirm field guards against a stray ?purge=true wiping history the
+// caller meant to keep.
 func DeleteAccount(c *gin.Context) {
 	// Extract JWT claims from context
 	claims, exists := c.Get("user")
@@ -210,50 +500,189 @@ func DeleteAccount(c *gin.Context) {
 		return
 	}
 
-	// Check if account has transactions
-	var transactionCount int64
-	db.DB.Model(&models.Transaction{}).Where("account_id = ?", accountID).Count(&transactionCount)
+	var childCount int64
+	db.DB.Model(&models.Account{}).Where("parent_account_id = ?", account.ID).Count(&childCount)
+	if childCount > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account has child accounts; reparent or delete them first"})
+		return
+	}
 
-	if transactionCount > 0 {
-		// Delete all transactions for this account first
-		if err := db.DB.Where("account_id = ?", accountID).Delete(&models.Transaction{}).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete account transactions"})
+	purge := c.Query("purge") == "true"
+	if purge {
+		var body struct {
+			Confirm string `json:"confirm"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil || body.Confirm != account.Name {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "purge requires {\"confirm\": \"<account name>\"} matching the account's name"})
 			return
 		}
 	}
 
+	var transactionCount int64
+	db.DB.Model(&models.Transaction{}).Where("account_id = ?", accountID).Count(&transactionCount)
+
 	// Also unlink any bank accounts that reference this internal account
 	db.DB.Model(&models.BankAccount{}).Where("internal_account_id = ?", accountID).Update("internal_account_id", nil)
 
-	// Now delete the account
-	result = db.DB.Delete(&account)
-	if result.Error != nil {
+	tx := db.DB.Begin()
+	if err := cascade.Execute(c.Request.Context(), tx, "account", account.ID, purge); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete account"})
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete account"})
 		return
 	}
 
+	if purge {
+		c.JSON(http.StatusOK, gin.H{
+			"message":              "Account permanently deleted",
+			"transactions_deleted": transactionCount,
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message":              "Account deleted successfully",
+		"message":              "Account archived, restorable until purge_after",
+		"purge_after":          time.Now().Add(cascade.DefaultPurgeGracePeriod),
 		"transactions_deleted": transactionCount,
 	})
 }
 
-// UpdateAccountBalance recalculates the current balance for an account based on transactions
+// UpdateAccountBalance recalculates the current balance for an account
+// based on its own transactions plus, recursively, every descendant
+// account's activity (see Account.ParentAccountID/descendantAccountIDs) -
+// so a parent "Assets" account's balance always reflects the sub-accounts
+// nested under it, the way a real chart of accounts expects.
 func UpdateAccountBalance(accountID uint) error {
 	var account models.Account
 	if err := db.DB.First(&account, accountID).Error; err != nil {
 		return err
 	}
 
-	// Calculate total transaction amount for this account
-	var totalTransactions int64
+	accountIDs := append([]uint{accountID}, descendantAccountIDs(account.UserID, accountID)...)
+
+	// Calculate total transaction amount across this account and its
+	// descendants. Voided transactions are excluded - they never happened
+	// as far as the ledger is concerned, even though they remain visible in
+	// GetTransactions for audit purposes.
+	var totalTransactions decimal.NullDecimal
 	db.DB.Model(&models.Transaction{}).
-		Where("account_id = ?", accountID).
-		Select("COALESCE(SUM(amount_cents), 0)").
+		Where("account_id IN ? AND status <> ?", accountIDs, models.TransactionVoided).
+		Select("COALESCE(SUM(amount), 0)").
 		Scan(&totalTransactions)
 
-	// Current balance = initial balance + transactions
-	account.CurrentBalanceCents = account.InitialBalanceCents + totalTransactions
+	// Splits carrying their own AccountID are a second ledger leg on that
+	// account (e.g. the cash side of an ATM withdrawal) and count towards
+	// its balance too; splits with no AccountID are pure category
+	// breakdowns of the parent's own amount, already counted above.
+	var totalSplits decimal.NullDecimal
+	db.DB.Model(&models.TransactionSplit{}).
+		Joins("JOIN transactions ON transactions.id = transaction_splits.parent_txn_id").
+		Where("transaction_splits.account_id IN ? AND transactions.status <> ?", accountIDs, models.TransactionVoided).
+		Select("COALESCE(SUM(transaction_splits.amount), 0)").
+		Scan(&totalSplits)
+
+	// Current balance = this account's own initial balance + transactions/
+	// splits across itself and every descendant. Descendant accounts keep
+	// their own InitialBalanceCents out of the parent's total, since each
+	// descendant already counts its own initial balance in its own
+	// UpdateAccountBalance call.
+	account.CurrentBalance = decimal.NewFromInt(account.InitialBalanceCents).Div(decimal.NewFromInt(100)).
+		Add(totalTransactions.Decimal).Add(totalSplits.Decimal)
 
 	return db.DB.Save(&account).Error
 }
+
+// ReconcileAccount checks an account's Cleared transactions against a bank
+// statement and, if they balance, atomically flips them all to Reconciled.
+// The expected balance is the account's initial balance, plus every
+// already-Reconciled transaction (from prior reconciliations, whatever their
+// date), plus every Cleared transaction dated on or before the statement
+// date - Voided transactions never count. If that doesn't match the
+// statement's ending balance the request fails with nothing changed, so a
+// miskeyed statement balance can't silently misreconcile the account.
+func ReconcileAccount(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	accountID := c.Param("id")
+
+	var account models.Account
+	if err := db.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Account not found"})
+		return
+	}
+
+	var input struct {
+		StatementDate         string `json:"statement_date" binding:"required"` // YYYY-MM-DD
+		StatementBalanceCents int64  `json:"statement_balance_cents"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statementDate, err := time.Parse("2006-01-02", input.StatementDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+		return
+	}
+
+	var reconciliation models.AccountReconciliation
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		var reconciledTotal decimal.NullDecimal
+		if err := tx.Model(&models.Transaction{}).
+			Where("account_id = ? AND status = ?", account.ID, models.TransactionReconciled).
+			Select("COALESCE(SUM(amount), 0)").Scan(&reconciledTotal).Error; err != nil {
+			return err
+		}
+
+		var cleared []models.Transaction
+		if err := tx.Where("account_id = ? AND status = ? AND txn_date <= ?", account.ID, models.TransactionCleared, statementDate).
+			Find(&cleared).Error; err != nil {
+			return err
+		}
+
+		clearedTotal := decimal.Zero
+		for _, txn := range cleared {
+			clearedTotal = clearedTotal.Add(txn.Amount)
+		}
+
+		expectedBalance := centsOf(decimal.NewFromInt(account.InitialBalanceCents).Div(decimal.NewFromInt(100)).
+			Add(reconciledTotal.Decimal).Add(clearedTotal))
+		if expectedBalance != input.StatementBalanceCents {
+			return fmt.Errorf("statement balance %d does not match expected balance %d", input.StatementBalanceCents, expectedBalance)
+		}
+
+		if len(cleared) > 0 {
+			var clearedIDs []uint
+			for _, txn := range cleared {
+				clearedIDs = append(clearedIDs, txn.ID)
+			}
+			if err := tx.Model(&models.Transaction{}).Where("id IN ?", clearedIDs).
+				Update("status", models.TransactionReconciled).Error; err != nil {
+				return err
+			}
+		}
+
+		reconciliation = models.AccountReconciliation{
+			AccountID:             account.ID,
+			UserID:                userID,
+			StatementDate:         statementDate,
+			StatementBalanceCents: input.StatementBalanceCents,
+			TransactionCount:      len(cleared),
+		}
+		return tx.Create(&reconciliation).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
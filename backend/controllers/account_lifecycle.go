@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"net/http"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/cascade"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// RestoreAccount undoes a pending-purge DeleteAccount call for the
+// authenticated user, restoring the account and its cascaded transactions
+// within their grace period (see services/cascade.DefaultPurgeGracePeriod).
+func RestoreAccount(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	accountID := c.Param("id")
+
+	var account models.Account
+	if err := db.DB.Unscoped().Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+	if !account.DeletedAt.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account is not archived"})
+		return
+	}
+
+	tx := db.DB.Begin()
+	if err := cascade.Restore(tx, &models.Transaction{}, "account_id = ?", []interface{}{account.ID}); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore account transactions"})
+		return
+	}
+	if err := cascade.Restore(tx, &models.Account{}, "id = ?", []interface{}{account.ID}); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore account"})
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit account restore"})
+		return
+	}
+
+	db.DB.First(&account, account.ID)
+	c.JSON(http.StatusOK, account)
+}
+
+// MergeAccounts consolidates the authenticated user's account :id into
+// ?into=<other_id>: every Transaction and BankAccount link pointing at :id
+// is reassigned to the target account in a single DB transaction, then :id
+// is archived (the same soft-delete DeleteAccount uses), leaving the merge
+// reversible via RestoreAccount if it turns out to be a mistake.
+func MergeAccounts(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	sourceID := c.Param("id")
+	targetID := c.Query("into")
+
+	if targetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "?into=<account_id> is required"})
+		return
+	}
+	if targetID == sourceID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot merge an account into itself"})
+		return
+	}
+
+	var source, target models.Account
+	if err := db.DB.Where("id = ? AND user_id = ?", sourceID, userID).First(&source).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "source account not found"})
+		return
+	}
+	if err := db.DB.Where("id = ? AND user_id = ?", targetID, userID).First(&target).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "target account not found or does not belong to user"})
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Transaction{}).Where("account_id = ?", source.ID).Update("account_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.BankAccount{}).Where("internal_account_id = ?", source.ID).Update("internal_account_id", target.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&source).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to merge accounts"})
+		return
+	}
+
+	if err := UpdateAccountBalance(target.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "merged accounts, but failed to recalculate target balance"})
+		return
+	}
+
+	db.DB.First(&target, target.ID)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "accounts merged",
+		"target":  target,
+	})
+}
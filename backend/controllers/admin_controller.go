@@ -1,26 +1,82 @@
 package controllers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
 	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/cascade"
+	"Personal-Finance-Tracker-backend/services/fx"
+	"Personal-Finance-Tracker-backend/store"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 )
 
-// GetAllUsers returns all users (admin only)
+// AdminStore is the store.AdminStore implementation admin handlers use for
+// the aggregate queries that don't fit the per-user/per-transaction
+// TransactionStore. Same package-variable-for-mock-injection convention as
+// TransactionStore.
+var AdminStore func() store.AdminStore = func() store.AdminStore {
+	return store.NewGormStore(db.DB)
+}
+
+// GetAllUsers returns a keyset-paginated page of users (admin only).
+// Supports ?cursor=, ?limit=, ?q= (search username/email/name),
+// ?date_from=, ?date_to= (created_at range) and ?sort=.
 func GetAllUsers(c *gin.Context) {
-	var users []models.User
+	cursor, limit, err := ParseCursor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sortCol, desc, err := parseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := db.DB.Model(&models.User{})
+
+	if q := c.Query("q"); q != "" {
+		like := "%" + q + "%"
+		query = query.Where("username LIKE ? OR email LIKE ? OR name LIKE ?", like, like, like)
+	}
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		query = query.Where("created_at >= ?", dateFrom)
+	}
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		query = query.Where("created_at <= ?", dateTo)
+	}
+	if cursor != "" {
+		pc, _ := decodeCursor(cursor)
+		clause, clauseArgs := keysetClause("", sortCol, desc)
+		query = query.Where(clause, clauseArgs(*pc)...)
+	}
 
-	// Get all users with basic info (password hash excluded by json:"-" tag)
-	if err := db.DB.Find(&users).Error; err != nil {
+	// Basic info only (password hash excluded by json:"-" tag)
+	var users []models.User
+	if err := query.Order(orderClause("", sortCol, desc)).Limit(limit + 1).Find(&users).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch users"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"users": users})
+	hasMore := len(users) > limit
+	if hasMore {
+		users = users[:limit]
+	}
+	var nextCursor string
+	if hasMore {
+		last := users[len(users)-1]
+		nextCursor = encodeCursor(last.ID, last.CreatedAt)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": users, "next_cursor": nextCursor, "has_more": hasMore})
 }
 
 // GetUserDetails returns detailed info about a specific user (admin only)
@@ -37,59 +93,113 @@ func GetUserDetails(c *gin.Context) {
 		return
 	}
 
-	// Get user's accounts count
-	var accountCount int64
-	db.DB.Model(&models.Account{}).Where("user_id = ?", userID).Count(&accountCount)
-
-	// Get user's transactions count
-	var transactionCount int64
-	db.DB.Model(&models.Transaction{}).Where("user_id = ?", userID).Count(&transactionCount)
-
-	// Get user's categories count
-	var categoryCount int64
-	db.DB.Model(&models.Category{}).Where("user_id = ?", userID).Count(&categoryCount)
-
-	// Get user's budgets count
-	var budgetCount int64
-	db.DB.Model(&models.Budget{}).Where("user_id = ?", userID).Count(&budgetCount)
+	counts, err := AdminStore().GetUserAggregateCounts(c.Request.Context(), uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch user statistics"})
+		return
+	}
 
 	response := gin.H{
 		"user": user,
 		"statistics": gin.H{
-			"accounts":     accountCount,
-			"transactions": transactionCount,
-			"categories":   categoryCount,
-			"budgets":      budgetCount,
+			"accounts":     counts.Accounts,
+			"transactions": counts.Transactions,
+			"categories":   counts.Categories,
+			"budgets":      counts.Budgets,
 		},
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-// GetAllTransactions returns all transactions from all users (admin only)
+// GetAllTransactions returns a keyset-paginated page of transactions from
+// all users (admin only). Supports ?cursor=, ?limit=, ?user_id=,
+// ?type= (income|expense), ?date_from=, ?date_to= (txn_date range),
+// ?min_cents=, ?max_cents= (absolute amount range), ?q= (search
+// description) and ?sort=.
 func GetAllTransactions(c *gin.Context) {
 	type TransactionWithUser struct {
-		ID           uint    `json:"id"`
-		Description  string  `json:"description"`
-		AmountCents  int64   `json:"amount_cents"`
-		Amount       float64 `json:"amount"`
-		Type         string  `json:"type"`
-		TxnDate      string  `json:"txn_date"`
-		CreatedAt    string  `json:"created_at"`
-		UserID       uint    `json:"user_id"`
-		UserUsername string  `json:"user_username"`
-		UserEmail    string  `json:"user_email"`
-		AccountID    uint    `json:"account_id"`
-		AccountName  string  `json:"account_name"`
-		CategoryID   *uint   `json:"category_id"`
-		CategoryName string  `json:"category_name"`
+		ID           uint            `json:"id"`
+		Description  string          `json:"description"`
+		AmountCents  int64           `json:"amount_cents"`
+		Amount       float64         `json:"amount"`
+		AmountRaw    decimal.Decimal `json:"-"`
+		Type         string          `json:"type"`
+		TxnDate      string          `json:"txn_date"`
+		CreatedAt    time.Time       `json:"created_at"`
+		UserID       uint            `json:"user_id"`
+		UserUsername string          `json:"user_username"`
+		UserEmail    string          `json:"user_email"`
+		AccountID    uint            `json:"account_id"`
+		AccountName  string          `json:"account_name"`
+		CategoryID   *uint           `json:"category_id"`
+		CategoryName string          `json:"category_name"`
 	}
 
-	var results []TransactionWithUser
+	cursor, limit, err := ParseCursor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sortCol, desc, err := parseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	where := []string{"t.deleted_at IS NULL"}
+	var args []interface{}
+
+	if userID := c.Query("user_id"); userID != "" {
+		where = append(where, "t.user_id = ?")
+		args = append(args, userID)
+	}
+	switch c.Query("type") {
+	case "income":
+		where = append(where, "t.amount > 0")
+	case "expense":
+		where = append(where, "t.amount < 0")
+	}
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		where = append(where, "t.txn_date >= ?")
+		args = append(args, dateFrom)
+	}
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		where = append(where, "t.txn_date <= ?")
+		args = append(args, dateTo)
+	}
+	if minCents := c.Query("min_cents"); minCents != "" {
+		n, convErr := strconv.ParseInt(minCents, 10, 64)
+		if convErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_cents"})
+			return
+		}
+		where = append(where, "ABS(t.amount) >= ?")
+		args = append(args, decimal.NewFromInt(n).Div(decimal.NewFromInt(100)))
+	}
+	if maxCents := c.Query("max_cents"); maxCents != "" {
+		n, convErr := strconv.ParseInt(maxCents, 10, 64)
+		if convErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid max_cents"})
+			return
+		}
+		where = append(where, "ABS(t.amount) <= ?")
+		args = append(args, decimal.NewFromInt(n).Div(decimal.NewFromInt(100)))
+	}
+	if q := c.Query("q"); q != "" {
+		where = append(where, "t.description LIKE ?")
+		args = append(args, "%"+q+"%")
+	}
+	if cursor != "" {
+		pc, _ := decodeCursor(cursor)
+		clause, clauseArgs := keysetClause("t", sortCol, desc)
+		where = append(where, clause)
+		args = append(args, clauseArgs(*pc)...)
+	}
 
 	query := `
-		SELECT 
-			t.id, t.description, t.amount_cents, t.txn_date, t.created_at,
+		SELECT
+			t.id, t.description, t.amount as amount_raw, t.txn_date, t.created_at,
 			t.user_id, u.username as user_username, u.email as user_email,
 			t.account_id, a.name as account_name,
 			t.category_id, COALESCE(c.name, 'Uncategorized') as category_name
@@ -97,168 +207,409 @@ func GetAllTransactions(c *gin.Context) {
 		LEFT JOIN users u ON t.user_id = u.id
 		LEFT JOIN accounts a ON t.account_id = a.id
 		LEFT JOIN categories c ON t.category_id = c.id
-		ORDER BY t.created_at DESC
 	`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY " + orderClause("t", sortCol, desc) + " LIMIT ?"
+	args = append(args, limit+1)
 
-	if err := db.DB.Raw(query).Scan(&results).Error; err != nil {
+	var results []TransactionWithUser
+	if err := db.DB.Raw(query, args...).Scan(&results).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch transactions"})
 		return
 	}
 
-	// Convert amount_cents to amount and determine type
+	// Convert amount_raw to amount_cents/amount and determine type
 	for i := range results {
-		results[i].Amount = float64(results[i].AmountCents) / 100.0
-		if results[i].AmountCents > 0 {
+		results[i].AmountCents = centsOf(results[i].AmountRaw)
+		results[i].Amount = results[i].AmountRaw.InexactFloat64()
+		if results[i].AmountRaw.IsPositive() {
 			results[i].Type = "income"
 		} else {
 			results[i].Type = "expense"
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"transactions": results})
+	hasMore := len(results) > limit
+	if hasMore {
+		results = results[:limit]
+	}
+	var nextCursor string
+	if hasMore {
+		last := results[len(results)-1]
+		nextCursor = encodeCursor(last.ID, last.CreatedAt)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": results, "next_cursor": nextCursor, "has_more": hasMore})
 }
 
-// GetAllAccounts returns all accounts from all users (admin only)
+// GetAllAccounts returns a keyset-paginated page of accounts from all users
+// (admin only). Supports ?cursor=, ?limit=, ?user_id=, ?type= (account
+// type), ?date_from=, ?date_to=, ?q= (search name) and ?sort=. Pass
+// ?display_currency=USD to additionally convert each account's current
+// balance into that currency as of today, alongside the native amount and
+// the fx rate used.
 func GetAllAccounts(c *gin.Context) {
 	type AccountWithUser struct {
-		ID                  uint    `json:"id"`
-		Name                string  `json:"name"`
-		AccountType         string  `json:"account_type"`
-		Currency            string  `json:"currency"`
-		InitialBalanceCents int64   `json:"initial_balance_cents"`
-		CurrentBalanceCents int64   `json:"current_balance_cents"`
-		Balance             float64 `json:"balance"`
-		CreatedAt           string  `json:"created_at"`
-		UserID              uint    `json:"user_id"`
-		UserUsername        string  `json:"user_username"`
-		UserEmail           string  `json:"user_email"`
+		ID                    uint            `json:"id"`
+		Name                  string          `json:"name"`
+		AccountType           string          `json:"account_type"`
+		Currency              string          `json:"currency"`
+		InitialBalanceCents   int64           `json:"initial_balance_cents"`
+		CurrentBalanceRaw     decimal.Decimal `json:"-"`
+		CurrentBalanceCents   int64           `json:"current_balance_cents"`
+		Balance               float64         `json:"balance"`
+		CreatedAt             time.Time       `json:"created_at"`
+		UserID                uint            `json:"user_id"`
+		UserUsername          string          `json:"user_username"`
+		UserEmail             string          `json:"user_email"`
+		DisplayCurrency       string          `json:"display_currency,omitempty"`
+		ConvertedBalanceCents *int64          `json:"converted_balance_cents,omitempty"`
+		FxRate                *float64        `json:"fx_rate,omitempty"`
 	}
 
-	var results []AccountWithUser
+	cursor, limit, err := ParseCursor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sortCol, desc, err := parseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	where := []string{"a.deleted_at IS NULL"}
+	var args []interface{}
+
+	if userID := c.Query("user_id"); userID != "" {
+		where = append(where, "a.user_id = ?")
+		args = append(args, userID)
+	}
+	if accType := c.Query("type"); accType != "" {
+		where = append(where, "a.type = ?")
+		args = append(args, accType)
+	}
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		where = append(where, "a.created_at >= ?")
+		args = append(args, dateFrom)
+	}
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		where = append(where, "a.created_at <= ?")
+		args = append(args, dateTo)
+	}
+	if q := c.Query("q"); q != "" {
+		where = append(where, "a.name LIKE ?")
+		args = append(args, "%"+q+"%")
+	}
+	if cursor != "" {
+		pc, _ := decodeCursor(cursor)
+		clause, clauseArgs := keysetClause("a", sortCol, desc)
+		where = append(where, clause)
+		args = append(args, clauseArgs(*pc)...)
+	}
 
 	query := `
-		SELECT 
-			a.id, a.name, a.type as account_type, a.currency, 
-			a.initial_balance_cents, a.current_balance_cents, a.created_at,
+		SELECT
+			a.id, a.name, a.type as account_type, a.currency,
+			a.initial_balance_cents, a.current_balance as current_balance_raw, a.created_at,
 			a.user_id, u.username as user_username, u.email as user_email
 		FROM accounts a
 		LEFT JOIN users u ON a.user_id = u.id
-		ORDER BY a.created_at DESC
 	`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY " + orderClause("a", sortCol, desc) + " LIMIT ?"
+	args = append(args, limit+1)
 
-	if err := db.DB.Raw(query).Scan(&results).Error; err != nil {
+	var results []AccountWithUser
+	if err := db.DB.Raw(query, args...).Scan(&results).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch accounts"})
 		return
 	}
 
-	// Convert balance_cents to balance
+	// Convert current_balance_raw to balance_cents/balance
 	for i := range results {
-		results[i].Balance = float64(results[i].CurrentBalanceCents) / 100.0
+		results[i].CurrentBalanceCents = centsOf(results[i].CurrentBalanceRaw)
+		results[i].Balance = results[i].CurrentBalanceRaw.InexactFloat64()
+	}
+
+	if displayCurrency := c.Query("display_currency"); displayCurrency != "" {
+		now := time.Now()
+		for i := range results {
+			converted, rate, err := convertedAmount(results[i].CurrentBalanceCents, results[i].Currency, displayCurrency, now)
+			if err != nil {
+				log.Printf("fx: converting account %d balance %s->%s: %v", results[i].ID, results[i].Currency, displayCurrency, err)
+				continue
+			}
+			results[i].DisplayCurrency = displayCurrency
+			results[i].ConvertedBalanceCents = &converted
+			results[i].FxRate = &rate
+		}
+	}
+
+	hasMore := len(results) > limit
+	if hasMore {
+		results = results[:limit]
+	}
+	var nextCursor string
+	if hasMore {
+		last := results[len(results)-1]
+		nextCursor = encodeCursor(last.ID, last.CreatedAt)
 	}
 
-	c.JSON(http.StatusOK, gin.H{"accounts": results})
+	c.JSON(http.StatusOK, gin.H{"items": results, "next_cursor": nextCursor, "has_more": hasMore})
 }
 
-// GetAllCategories returns all categories from all users (admin only)
+// GetAllCategories returns a keyset-paginated page of categories from all
+// users (admin only). Supports ?cursor=, ?limit=, ?user_id=, ?type=
+// (category kind), ?date_from=, ?date_to=, ?q= (search name) and ?sort=.
 func GetAllCategories(c *gin.Context) {
 	type CategoryWithUser struct {
-		ID           uint   `json:"id"`
-		Name         string `json:"name"`
-		Kind         string `json:"kind"`
-		Type         string `json:"type"` // Alias for kind to match frontend
-		ParentID     *uint  `json:"parent_id"`
-		ParentName   string `json:"parent_name"`
-		CreatedAt    string `json:"created_at"`
-		UserID       uint   `json:"user_id"`
-		UserUsername string `json:"user_username"`
-		UserEmail    string `json:"user_email"`
+		ID           uint      `json:"id"`
+		Name         string    `json:"name"`
+		Kind         string    `json:"kind"`
+		Type         string    `json:"type"` // Alias for kind to match frontend
+		ParentID     *uint     `json:"parent_id"`
+		ParentName   string    `json:"parent_name"`
+		CreatedAt    time.Time `json:"created_at"`
+		UserID       uint      `json:"user_id"`
+		UserUsername string    `json:"user_username"`
+		UserEmail    string    `json:"user_email"`
 	}
 
-	var results []CategoryWithUser
+	cursor, limit, err := ParseCursor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sortCol, desc, err := parseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	where := []string{"c.deleted_at IS NULL"}
+	var args []interface{}
+
+	if userID := c.Query("user_id"); userID != "" {
+		where = append(where, "c.user_id = ?")
+		args = append(args, userID)
+	}
+	if kind := c.Query("type"); kind != "" {
+		where = append(where, "c.kind = ?")
+		args = append(args, kind)
+	}
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		where = append(where, "c.created_at >= ?")
+		args = append(args, dateFrom)
+	}
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		where = append(where, "c.created_at <= ?")
+		args = append(args, dateTo)
+	}
+	if q := c.Query("q"); q != "" {
+		where = append(where, "c.name LIKE ?")
+		args = append(args, "%"+q+"%")
+	}
+	if cursor != "" {
+		pc, _ := decodeCursor(cursor)
+		clause, clauseArgs := keysetClause("c", sortCol, desc)
+		where = append(where, clause)
+		args = append(args, clauseArgs(*pc)...)
+	}
 
 	query := `
-		SELECT 
+		SELECT
 			c.id, c.name, c.kind, c.kind as type, c.parent_id, c.created_at,
 			c.user_id, u.username as user_username, u.email as user_email,
 			COALESCE(pc.name, '') as parent_name
 		FROM categories c
 		LEFT JOIN users u ON c.user_id = u.id
 		LEFT JOIN categories pc ON c.parent_id = pc.id
-		ORDER BY c.created_at DESC
 	`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY " + orderClause("c", sortCol, desc) + " LIMIT ?"
+	args = append(args, limit+1)
 
-	if err := db.DB.Raw(query).Scan(&results).Error; err != nil {
+	var results []CategoryWithUser
+	if err := db.DB.Raw(query, args...).Scan(&results).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch categories"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"categories": results})
+	hasMore := len(results) > limit
+	if hasMore {
+		results = results[:limit]
+	}
+	var nextCursor string
+	if hasMore {
+		last := results[len(results)-1]
+		nextCursor = encodeCursor(last.ID, last.CreatedAt)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": results, "next_cursor": nextCursor, "has_more": hasMore})
 }
 
-// GetAllBudgets returns all budgets from all users (admin only)
+// GetAllBudgets returns a keyset-paginated page of budgets from all users
+// (admin only). Supports ?cursor=, ?limit=, ?user_id=, ?date_from=,
+// ?date_to= and ?sort=. Pass ?display_currency=USD to additionally convert
+// each budget's planned and spent totals into that currency as of the
+// budget's period_end, alongside the native amounts and the fx rate used.
 func GetAllBudgets(c *gin.Context) {
 	type BudgetWithUser struct {
-		ID           uint    `json:"id"`
-		PeriodStart  string  `json:"period_start"`
-		PeriodEnd    string  `json:"period_end"`
-		StartDate    string  `json:"start_date"` // Alias for frontend
-		EndDate      string  `json:"end_date"`   // Alias for frontend
-		Currency     string  `json:"currency"`
-		CreatedAt    string  `json:"created_at"`
-		UserID       uint    `json:"user_id"`
-		UserUsername string  `json:"user_username"`
-		UserEmail    string  `json:"user_email"`
-		Name         string  `json:"name"`   // Computed name
-		Amount       float64 `json:"amount"` // Total planned amount
-		Spent        float64 `json:"spent"`  // Total spent (placeholder)
+		ID           uint      `json:"id"`
+		PeriodStart  string    `json:"period_start"`
+		PeriodEnd    string    `json:"period_end"`
+		StartDate    string    `json:"start_date"` // Alias for frontend
+		EndDate      string    `json:"end_date"`   // Alias for frontend
+		Currency     string    `json:"currency"`
+		CreatedAt    time.Time `json:"created_at"`
+		UserID       uint      `json:"user_id"`
+		UserUsername string    `json:"user_username"`
+		UserEmail    string    `json:"user_email"`
+		Name         string    `json:"name"`   // Computed name
+		Amount       float64   `json:"amount"` // Total planned amount
+		Spent        float64   `json:"spent"`  // Total spent (placeholder)
+
+		DisplayCurrency string   `json:"display_currency,omitempty"`
+		ConvertedAmount *float64 `json:"converted_amount,omitempty"`
+		ConvertedSpent  *float64 `json:"converted_spent,omitempty"`
+		FxRate          *float64 `json:"fx_rate,omitempty"`
 	}
 
-	var results []BudgetWithUser
+	cursor, limit, err := ParseCursor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sortCol, desc, err := parseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	where := []string{"b.deleted_at IS NULL"}
+	var args []interface{}
+
+	if userID := c.Query("user_id"); userID != "" {
+		where = append(where, "b.user_id = ?")
+		args = append(args, userID)
+	}
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		where = append(where, "b.created_at >= ?")
+		args = append(args, dateFrom)
+	}
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		where = append(where, "b.created_at <= ?")
+		args = append(args, dateTo)
+	}
+	if cursor != "" {
+		pc, _ := decodeCursor(cursor)
+		clause, clauseArgs := keysetClause("b", sortCol, desc)
+		where = append(where, clause)
+		args = append(args, clauseArgs(*pc)...)
+	}
 
 	query := `
-		SELECT 
+		SELECT
 			b.id, b.period_start, b.period_end, b.currency, b.created_at,
 			b.user_id, u.username as user_username, u.email as user_email,
 			b.period_start as start_date, b.period_end as end_date
 		FROM budgets b
 		LEFT JOIN users u ON b.user_id = u.id
-		ORDER BY b.created_at DESC
 	`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY " + orderClause("b", sortCol, desc) + " LIMIT ?"
+	args = append(args, limit+1)
 
-	if err := db.DB.Raw(query).Scan(&results).Error; err != nil {
+	var results []BudgetWithUser
+	if err := db.DB.Raw(query, args...).Scan(&results).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch budgets"})
 		return
 	}
 
-	// Calculate totals and create names for each budget
+	hasMore := len(results) > limit
+	if hasMore {
+		results = results[:limit]
+	}
+
+	// Precompute planned/spent totals for the whole page in two grouped
+	// queries instead of two per budget - see store.GetBudgetPlannedTotals
+	// and store.GetBudgetSpentTotals.
+	budgetIDs := make([]uint, len(results))
+	budgetPeriods := make([]store.BudgetPeriod, len(results))
+	for i, r := range results {
+		budgetIDs[i] = r.ID
+		periodStart, _ := time.Parse("2006-01-02", r.PeriodStart)
+		periodEnd, _ := time.Parse("2006-01-02", r.PeriodEnd)
+		budgetPeriods[i] = store.BudgetPeriod{ID: r.ID, UserID: r.UserID, PeriodStart: periodStart, PeriodEnd: periodEnd}
+	}
+
+	plannedTotals, err := AdminStore().GetBudgetPlannedTotals(c.Request.Context(), budgetIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch budget planned totals"})
+		return
+	}
+	spentTotals, err := AdminStore().GetBudgetSpentTotals(c.Request.Context(), budgetPeriods)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch budget spent totals"})
+		return
+	}
+
 	for i := range results {
 		// Create a simple name based on the period
 		results[i].Name = "Budget " + results[i].PeriodStart[:7] // "Budget 2024-01"
+		results[i].Amount = float64(plannedTotals[results[i].ID]) / 100.0
+		results[i].Spent = float64(spentTotals[results[i].ID]) / 100.0
+	}
 
-		// Get total planned amount from budget items
-		var totalPlannedCents int64
-		db.DB.Table("budget_items").
-			Where("budget_id = ?", results[i].ID).
-			Select("COALESCE(SUM(planned_cents), 0)").
-			Scan(&totalPlannedCents)
-
-		results[i].Amount = float64(totalPlannedCents) / 100.0
-
-		// Calculate spent amount from transactions in the budget period
-		var totalSpentCents int64
-		db.DB.Table("transactions").
-			Where("user_id = ? AND txn_date >= ? AND txn_date <= ? AND amount_cents < 0",
-				results[i].UserID, results[i].PeriodStart, results[i].PeriodEnd).
-			Select("COALESCE(SUM(ABS(amount_cents)), 0)").
-			Scan(&totalSpentCents)
+	if displayCurrency := c.Query("display_currency"); displayCurrency != "" {
+		for i := range results {
+			periodEnd, perr := time.Parse("2006-01-02", results[i].PeriodEnd)
+			if perr != nil {
+				continue
+			}
+			convertedPlanned, rate, err := convertedAmount(plannedTotals[results[i].ID], results[i].Currency, displayCurrency, periodEnd)
+			if err != nil {
+				log.Printf("fx: converting budget %d planned %s->%s: %v", results[i].ID, results[i].Currency, displayCurrency, err)
+				continue
+			}
+			convertedSpent, _, err := convertedAmount(spentTotals[results[i].ID], results[i].Currency, displayCurrency, periodEnd)
+			if err != nil {
+				log.Printf("fx: converting budget %d spent %s->%s: %v", results[i].ID, results[i].Currency, displayCurrency, err)
+				continue
+			}
+			plannedAmount := float64(convertedPlanned) / 100.0
+			spentAmount := float64(convertedSpent) / 100.0
+			results[i].DisplayCurrency = displayCurrency
+			results[i].ConvertedAmount = &plannedAmount
+			results[i].ConvertedSpent = &spentAmount
+			results[i].FxRate = &rate
+		}
+	}
 
-		results[i].Spent = float64(totalSpentCents) / 100.0
+	var nextCursor string
+	if hasMore {
+		last := results[len(results)-1]
+		nextCursor = encodeCursor(last.ID, last.CreatedAt)
 	}
 
-	c.JSON(http.StatusOK, gin.H{"budgets": results})
+	c.JSON(http.StatusOK, gin.H{"items": results, "next_cursor": nextCursor, "has_more": hasMore})
 }
 
-// DeleteUserAdmin allows admin to delete any user and all their data
+// DeleteUserAdmin soft-deletes a user and their cascaded data (accounts,
+// categories, transactions, budgets), leaving them restorable for
+// cascade.DefaultPurgeGracePeriod. Pass ?force=true to hard-delete
+// immediately, or ?dry_run=true to preview the cascade plan without deleting
+// anything. Requires ?confirm_token= from a prior GET .../usage call
+// matching the current data (see requireConfirmToken).
 func DeleteUserAdmin(c *gin.Context) {
 	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -273,75 +624,255 @@ func DeleteUserAdmin(c *gin.Context) {
 		return
 	}
 
+	force := c.Query("force") == "true"
+
 	// Check if user exists
+	lookup := db.DB
+	if force {
+		lookup = lookup.Unscoped()
+	}
 	var user models.User
-	if err := db.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+	if err := lookup.Where("id = ?", userID).First(&user).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 		return
 	}
 
+	// ?dry_run=true returns the cascade plan - what would be deleted and how
+	// many rows - without touching any data.
+	if c.Query("dry_run") == "true" {
+		plan, err := cascade.CascadePlan(c.Request.Context(), db.DB, "user", uint(userID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute cascade plan"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "plan": plan})
+		return
+	}
+
+	// Require a confirm_token from GET .../usage matching the current data,
+	// so a stale preview can't be used to blow away rows the admin never saw.
+	snap, err := buildUserUsageSnapshot(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute usage snapshot"})
+		return
+	}
+	if !requireConfirmToken(c, adminUser.(models.User).ID, uint(userID), snapshotHash(snap)) {
+		return
+	}
+
 	// Start transaction for atomic deletion
 	tx := db.DB.Begin()
 
-	// Delete user's budget items first (foreign key constraint)
-	if err := tx.Where("budget_id IN (SELECT id FROM budgets WHERE user_id = ?)", userID).Delete(&models.BudgetItem{}).Error; err != nil {
+	if err := cascade.Execute(c.Request.Context(), tx, "user", uint(userID), force); err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user budget items"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user"})
 		return
 	}
 
-	// Delete user's budgets
-	if err := tx.Where("user_id = ?", userID).Delete(&models.Budget{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user budgets"})
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit user deletion"})
 		return
 	}
 
-	// Delete user's transaction splits
-	if err := tx.Where("parent_txn_id IN (SELECT id FROM transactions WHERE user_id = ?)", userID).Delete(&models.TransactionSplit{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user transaction splits"})
+	if force {
+		c.JSON(http.StatusOK, gin.H{"message": "user permanently deleted"})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully, restorable until purge_after", "purge_after": time.Now().Add(cascade.DefaultPurgeGracePeriod)})
+}
 
-	// Delete user's transactions
-	if err := tx.Where("user_id = ?", userID).Delete(&models.Transaction{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user transactions"})
+// RestoreUserAdmin undoes a pending-purge DeleteUserAdmin call, restoring the
+// user and their cascaded accounts/categories/transactions/budgets.
+func RestoreUserAdmin(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
 		return
 	}
 
-	// Delete user's categories
-	if err := tx.Where("user_id = ?", userID).Delete(&models.Category{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user categories"})
+	var user models.User
+	if err := db.DB.Unscoped().Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 		return
 	}
+	if !user.DeletedAt.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user is not deleted"})
+		return
+	}
+
+	tx := db.DB.Begin()
 
-	// Delete user's accounts
-	if err := tx.Where("user_id = ?", userID).Delete(&models.Account{}).Error; err != nil {
+	if err := cascade.Restore(tx, &models.Account{}, "user_id = ?", []interface{}{userID}); err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user accounts"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore user accounts"})
 		return
 	}
-
-	// Finally delete the user
-	if err := tx.Delete(&user).Error; err != nil {
+	if err := cascade.Restore(tx, &models.Category{}, "user_id = ?", []interface{}{userID}); err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore user categories"})
+		return
+	}
+	if err := cascade.Restore(tx, &models.Transaction{}, "user_id = ?", []interface{}{userID}); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore user transactions"})
+		return
+	}
+	if err := cascade.Restore(tx, &models.Budget{}, "user_id = ?", []interface{}{userID}); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore user budgets"})
+		return
+	}
+	if err := cascade.Restore(tx, &models.User{}, "id = ?", []interface{}{userID}); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore user"})
 		return
 	}
 
-	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit user deletion"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit user restore"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "user restored successfully"})
 }
 
-// DeleteTransactionAdmin allows admin to delete any transaction
+// UnlockUserLogin clears a user's /auth/login brute-force lockout (see
+// controllers/login_lockout.go), for when a legitimate user gets locked out
+// and doesn't want to wait out the backoff.
+func UnlockUserLogin(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.Unscoped().First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	clearLoginLockout(user.Username)
+
+	c.JSON(http.StatusOK, gin.H{"message": "login lockout cleared"})
+}
+
+// pendingPurgeUser is one row of GetPendingPurgeUsers' response.
+type pendingPurgeUser struct {
+	ID         uint      `json:"id"`
+	Username   string    `json:"username"`
+	Email      string    `json:"email"`
+	DeletedAt  time.Time `json:"deleted_at"`
+	PurgeAfter time.Time `json:"purge_after"`
+}
+
+// GetPendingPurgeUsers lists soft-deleted user accounts (self-service
+// DeleteUserAccount or admin DeleteUserAdmin) still inside their grace
+// period, ordered by how soon they'll be hard-deleted by the purge worker.
+func GetPendingPurgeUsers(c *gin.Context) {
+	var users []models.User
+	if err := db.DB.Unscoped().
+		Where("deleted_at IS NOT NULL AND purge_after IS NOT NULL AND purge_after > ?", time.Now()).
+		Order("purge_after ASC").
+		Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list pending-purge users"})
+		return
+	}
+
+	results := make([]pendingPurgeUser, 0, len(users))
+	for _, u := range users {
+		results = append(results, pendingPurgeUser{
+			ID:         u.ID,
+			Username:   u.Username,
+			Email:      u.Email,
+			DeletedAt:  u.DeletedAt.Time,
+			PurgeAfter: *u.PurgeAfter,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": results})
+}
+
+// dryRunCascadePlan handles ?dry_run=true for entities guarded by
+// requireCascadeConfirm: it writes the plan plus the confirm hash the caller
+// must echo back via ?confirm= to actually delete, and reports whether it
+// handled the request (the handler should return immediately if so).
+func dryRunCascadePlan(c *gin.Context, entity string, id uint) bool {
+	if c.Query("dry_run") != "true" {
+		return false
+	}
+	plan, err := cascade.CascadePlan(c.Request.Context(), db.DB, entity, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute cascade plan"})
+		return true
+	}
+	hash, err := cascade.PlanHash(plan)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to hash cascade plan"})
+		return true
+	}
+	c.JSON(http.StatusOK, gin.H{"dry_run": true, "plan": plan, "confirm": hash})
+	return true
+}
+
+// requireCascadeConfirm guards entities that don't have their own
+// confirm_token/usage-snapshot flow (transactions, categories, budgets - see
+// requireConfirmToken for users/accounts): the caller must echo back
+// ?confirm=<hash> of the exact cascade.Plan a prior ?dry_run=true returned,
+// so a stale preview can't be used to delete rows the admin never saw.
+func requireCascadeConfirm(c *gin.Context, entity string, id uint) bool {
+	plan, err := cascade.CascadePlan(c.Request.Context(), db.DB, entity, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute cascade plan"})
+		return false
+	}
+	hash, err := cascade.PlanHash(plan)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to hash cascade plan"})
+		return false
+	}
+	if confirm := c.Query("confirm"); confirm != hash {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "confirm parameter missing or stale; re-fetch ?dry_run=true and resubmit its plan hash via ?confirm=",
+			"plan":    plan,
+			"confirm": hash,
+		})
+		return false
+	}
+	return true
+}
+
+// categoryDeletePreview counts the rows DeleteCategoryAdmin would touch: the
+// transactions and child categories that get their category/parent
+// reference nulled out, and the budget items that get hard-deleted outright.
+func categoryDeletePreview(categoryID uint) (cascade.Plan, error) {
+	var transactions, budgetItems, childCategories int64
+	if err := db.DB.Model(&models.Transaction{}).Where("category_id = ?", categoryID).Count(&transactions).Error; err != nil {
+		return cascade.Plan{}, err
+	}
+	if err := db.DB.Model(&models.BudgetItem{}).Where("category_id = ?", categoryID).Count(&budgetItems).Error; err != nil {
+		return cascade.Plan{}, err
+	}
+	if err := db.DB.Model(&models.Category{}).Where("parent_id = ?", categoryID).Count(&childCategories).Error; err != nil {
+		return cascade.Plan{}, err
+	}
+
+	return cascade.Plan{
+		Entity: "category",
+		ID:     categoryID,
+		Steps: []cascade.DeleteStep{
+			{Model: "transactions_nullified", Count: transactions},
+			{Model: "budget_items", Count: budgetItems},
+			{Model: "categories_nullified", Count: childCategories},
+		},
+	}, nil
+}
+
+// DeleteTransactionAdmin soft-deletes a transaction (restorable until
+// purge_after), or hard-deletes it immediately with ?force=true. Pass
+// ?dry_run=true to preview the cascade plan without deleting anything, and
+// ?confirm=<hash> (from that preview's response) to actually delete.
 func DeleteTransactionAdmin(c *gin.Context) {
 	transactionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -349,29 +880,76 @@ func DeleteTransactionAdmin(c *gin.Context) {
 		return
 	}
 
+	force := c.Query("force") == "true"
+
 	// Check if transaction exists
+	lookup := db.DB
+	if force {
+		lookup = lookup.Unscoped()
+	}
 	var transaction models.Transaction
-	if err := db.DB.Where("id = ?", transactionID).First(&transaction).Error; err != nil {
+	if err := lookup.Where("id = ?", transactionID).First(&transaction).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
 		return
 	}
 
-	// Delete transaction splits first
-	if err := db.DB.Where("parent_txn_id = ?", transactionID).Delete(&models.TransactionSplit{}).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete transaction splits"})
+	if dryRunCascadePlan(c, "transaction", uint(transactionID)) {
+		return
+	}
+
+	if !requireCascadeConfirm(c, "transaction", uint(transactionID)) {
 		return
 	}
 
-	// Delete the transaction
-	if err := db.DB.Delete(&transaction).Error; err != nil {
+	tx := db.DB.Begin()
+	if err := cascade.Execute(c.Request.Context(), tx, "transaction", uint(transactionID), force); err != nil {
+		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete transaction"})
 		return
 	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit transaction deletion"})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "transaction deleted successfully"})
+	if force {
+		c.JSON(http.StatusOK, gin.H{"message": "transaction permanently deleted"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "transaction deleted successfully, restorable until purge_after", "purge_after": time.Now().Add(cascade.DefaultPurgeGracePeriod)})
 }
 
-// DeleteAccountAdmin allows admin to delete any account
+// RestoreTransactionAdmin undoes a pending-purge DeleteTransactionAdmin call.
+func RestoreTransactionAdmin(c *gin.Context) {
+	transactionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction ID"})
+		return
+	}
+
+	var transaction models.Transaction
+	if err := db.DB.Unscoped().Where("id = ?", transactionID).First(&transaction).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+		return
+	}
+	if !transaction.DeletedAt.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "transaction is not deleted"})
+		return
+	}
+
+	if err := cascade.Restore(db.DB, &models.Transaction{}, "id = ?", []interface{}{transactionID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "transaction restored successfully"})
+}
+
+// DeleteAccountAdmin soft-deletes an account and its transactions
+// (restorable until purge_after), or hard-deletes them immediately with
+// ?force=true. Pass ?dry_run=true to preview the cascade plan without
+// deleting anything. Requires ?confirm_token= from a prior GET .../usage
+// call matching the current data (see requireConfirmToken).
 func DeleteAccountAdmin(c *gin.Context) {
 	accountID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -379,47 +957,111 @@ func DeleteAccountAdmin(c *gin.Context) {
 		return
 	}
 
+	force := c.Query("force") == "true"
+
 	// Check if account exists
+	lookup := db.DB
+	if force {
+		lookup = lookup.Unscoped()
+	}
 	var account models.Account
-	if err := db.DB.Where("id = ?", accountID).First(&account).Error; err != nil {
+	if err := lookup.Where("id = ?", accountID).First(&account).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
 		return
 	}
 
+	if c.Query("dry_run") == "true" {
+		plan, err := cascade.CascadePlan(c.Request.Context(), db.DB, "account", uint(accountID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute cascade plan"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "plan": plan})
+		return
+	}
+
+	// Require a confirm_token from GET .../usage matching the current data,
+	// so a stale preview can't be used to blow away rows the admin never saw.
+	snap, err := buildAccountUsageSnapshot(uint(accountID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute usage snapshot"})
+		return
+	}
+	adminUser, _ := c.Get("adminUser")
+	if !requireConfirmToken(c, adminUser.(models.User).ID, uint(accountID), snapshotHash(snap)) {
+		return
+	}
+
 	// Start transaction for atomic deletion
 	tx := db.DB.Begin()
 
-	// Delete transaction splits first
-	if err := tx.Where("parent_txn_id IN (SELECT id FROM transactions WHERE account_id = ?)", accountID).Delete(&models.TransactionSplit{}).Error; err != nil {
+	if err := cascade.Execute(c.Request.Context(), tx, "account", uint(accountID), force); err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete transaction splits"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete account"})
 		return
 	}
 
-	// Delete transactions associated with this account
-	if err := tx.Where("account_id = ?", accountID).Delete(&models.Transaction{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete account transactions"})
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit account deletion"})
+		return
+	}
+
+	if force {
+		c.JSON(http.StatusOK, gin.H{"message": "account permanently deleted"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "account deleted successfully, restorable until purge_after", "purge_after": time.Now().Add(cascade.DefaultPurgeGracePeriod)})
+}
+
+// RestoreAccountAdmin undoes a pending-purge DeleteAccountAdmin call,
+// restoring the account and its cascaded transactions.
+func RestoreAccountAdmin(c *gin.Context) {
+	accountID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account ID"})
 		return
 	}
 
-	// Delete the account
-	if err := tx.Delete(&account).Error; err != nil {
+	var account models.Account
+	if err := db.DB.Unscoped().Where("id = ?", accountID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+	if !account.DeletedAt.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account is not deleted"})
+		return
+	}
+
+	tx := db.DB.Begin()
+
+	if err := cascade.Restore(tx, &models.Transaction{}, "account_id = ?", []interface{}{accountID}); err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete account"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore account transactions"})
+		return
+	}
+	if err := cascade.Restore(tx, &models.Account{}, "id = ?", []interface{}{accountID}); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore account"})
 		return
 	}
 
-	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit account deletion"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit account restore"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "account deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "account restored successfully"})
 }
 
-// DeleteCategoryAdmin allows admin to delete any category
+// DeleteCategoryAdmin soft-deletes a category (restorable until
+// purge_after), or hard-deletes it immediately with ?force=true. Unlike the
+// other admin deletes, this doesn't cascade-delete dependents - it nullifies
+// the category reference on transactions/child categories and hard-deletes
+// only the now-orphaned budget items, so it isn't expressed as a
+// services/cascade graph. Pass ?dry_run=true to preview the affected row
+// counts without changing anything, and ?confirm=<hash> (from that
+// preview's response) to actually delete.
 func DeleteCategoryAdmin(c *gin.Context) {
 	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -427,13 +1069,44 @@ func DeleteCategoryAdmin(c *gin.Context) {
 		return
 	}
 
+	force := c.Query("force") == "true"
+
 	// Check if category exists
+	lookup := db.DB
+	if force {
+		lookup = lookup.Unscoped()
+	}
 	var category models.Category
-	if err := db.DB.Where("id = ?", categoryID).First(&category).Error; err != nil {
+	if err := lookup.Where("id = ?", categoryID).First(&category).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
 		return
 	}
 
+	preview, err := categoryDeletePreview(uint(categoryID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute deletion preview"})
+		return
+	}
+	hash, err := cascade.PlanHash(preview)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to hash deletion preview"})
+		return
+	}
+
+	if c.Query("dry_run") == "true" {
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "plan": preview, "confirm": hash})
+		return
+	}
+
+	if confirm := c.Query("confirm"); confirm != hash {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "confirm parameter missing or stale; re-fetch ?dry_run=true and resubmit its plan hash via ?confirm=",
+			"plan":    preview,
+			"confirm": hash,
+		})
+		return
+	}
+
 	// Start transaction for atomic deletion
 	tx := db.DB.Begin()
 
@@ -459,7 +1132,7 @@ func DeleteCategoryAdmin(c *gin.Context) {
 	}
 
 	// Delete the category
-	if err := tx.Delete(&category).Error; err != nil {
+	if err := cascade.SoftDelete(tx, &models.Category{}, "id = ?", []interface{}{categoryID}, force); err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete category"})
 		return
@@ -471,10 +1144,44 @@ func DeleteCategoryAdmin(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "category deleted successfully"})
+	if force {
+		c.JSON(http.StatusOK, gin.H{"message": "category permanently deleted"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "category deleted successfully, restorable until purge_after", "purge_after": time.Now().Add(cascade.DefaultPurgeGracePeriod)})
+}
+
+// RestoreCategoryAdmin undoes a pending-purge DeleteCategoryAdmin call. Note
+// that the category's prior transaction/budget-item references are not
+// reattached - those were cleared at delete time, not recoverable.
+func RestoreCategoryAdmin(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+
+	var category models.Category
+	if err := db.DB.Unscoped().Where("id = ?", categoryID).First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		return
+	}
+	if !category.DeletedAt.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "category is not deleted"})
+		return
+	}
+
+	if err := cascade.Restore(db.DB, &models.Category{}, "id = ?", []interface{}{categoryID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category restored successfully"})
 }
 
-// DeleteBudgetAdmin allows admin to delete any budget
+// DeleteBudgetAdmin soft-deletes a budget (restorable until purge_after), or
+// hard-deletes it immediately with ?force=true. Pass ?dry_run=true to
+// preview the cascade plan without deleting anything.
 func DeleteBudgetAdmin(c *gin.Context) {
 	budgetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -482,25 +1189,31 @@ func DeleteBudgetAdmin(c *gin.Context) {
 		return
 	}
 
+	force := c.Query("force") == "true"
+
 	// Check if budget exists
+	lookup := db.DB
+	if force {
+		lookup = lookup.Unscoped()
+	}
 	var budget models.Budget
-	if err := db.DB.Where("id = ?", budgetID).First(&budget).Error; err != nil {
+	if err := lookup.Where("id = ?", budgetID).First(&budget).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
 		return
 	}
 
-	// Start transaction for atomic deletion
-	tx := db.DB.Begin()
+	if dryRunCascadePlan(c, "budget", uint(budgetID)) {
+		return
+	}
 
-	// Delete budget items first
-	if err := tx.Where("budget_id = ?", budgetID).Delete(&models.BudgetItem{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete budget items"})
+	if !requireCascadeConfirm(c, "budget", uint(budgetID)) {
 		return
 	}
 
-	// Delete the budget
-	if err := tx.Delete(&budget).Error; err != nil {
+	// Start transaction for atomic deletion
+	tx := db.DB.Begin()
+
+	if err := cascade.Execute(c.Request.Context(), tx, "budget", uint(budgetID), force); err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete budget"})
 		return
@@ -512,7 +1225,38 @@ func DeleteBudgetAdmin(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "budget deleted successfully"})
+	if force {
+		c.JSON(http.StatusOK, gin.H{"message": "budget permanently deleted"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "budget deleted successfully, restorable until purge_after", "purge_after": time.Now().Add(cascade.DefaultPurgeGracePeriod)})
+}
+
+// RestoreBudgetAdmin undoes a pending-purge DeleteBudgetAdmin call. Note that
+// budget items deleted at that time are not restorable.
+func RestoreBudgetAdmin(c *gin.Context) {
+	budgetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget ID"})
+		return
+	}
+
+	var budget models.Budget
+	if err := db.DB.Unscoped().Where("id = ?", budgetID).First(&budget).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+		return
+	}
+	if !budget.DeletedAt.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "budget is not deleted"})
+		return
+	}
+
+	if err := cascade.Restore(db.DB, &models.Budget{}, "id = ?", []interface{}{budgetID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore budget"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "budget restored successfully"})
 }
 
 // GetBudgetDetails returns detailed info about a specific budget (admin only)
@@ -544,31 +1288,30 @@ func GetBudgetDetails(c *gin.Context) {
 		ProgressPercentage float64 `json:"progress_percentage"`
 	}
 
+	categoryIDs := make([]uint, len(budget.Items))
+	for i, item := range budget.Items {
+		categoryIDs[i] = item.CategoryID
+	}
+	// One grouped query for every category's spend/count in this budget
+	// period, instead of two queries per budget item - see
+	// store.GetCategorySpendAggregates.
+	spendByCategory, err := AdminStore().GetCategorySpendAggregates(c.Request.Context(), budget.UserID, categoryIDs, budget.PeriodStart, budget.PeriodEnd)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch category spend"})
+		return
+	}
+
 	var categories []CategoryProgress
 	totalPlanned := 0.0
 	totalSpent := 0.0
 
 	// Process each budget item (category)
 	for _, item := range budget.Items {
-		plannedAmount := float64(item.PlannedCents) / 100.0
+		plannedAmount := item.PlannedAmount.InexactFloat64()
 		totalPlanned += plannedAmount
 
-		// Calculate spent amount for this category in the budget period
-		var spentCents int64
-		var transactionCount int64
-
-		db.DB.Model(&models.Transaction{}).
-			Where("user_id = ? AND category_id = ? AND txn_date >= ? AND txn_date <= ? AND amount_cents < 0",
-				budget.UserID, item.CategoryID, budget.PeriodStart, budget.PeriodEnd).
-			Count(&transactionCount)
-
-		db.DB.Table("transactions").
-			Where("user_id = ? AND category_id = ? AND txn_date >= ? AND txn_date <= ? AND amount_cents < 0",
-				budget.UserID, item.CategoryID, budget.PeriodStart, budget.PeriodEnd).
-			Select("COALESCE(SUM(ABS(amount_cents)), 0)").
-			Scan(&spentCents)
-
-		spentAmount := float64(spentCents) / 100.0
+		spend := spendByCategory[item.CategoryID]
+		spentAmount := float64(spend.SpentCents) / 100.0
 		totalSpent += spentAmount
 
 		progressPercentage := 0.0
@@ -581,7 +1324,7 @@ func GetBudgetDetails(c *gin.Context) {
 			Name:               item.Category.Name,
 			PlannedAmount:      plannedAmount,
 			SpentAmount:        spentAmount,
-			TransactionCount:   transactionCount,
+			TransactionCount:   spend.TransactionCount,
 			ProgressPercentage: progressPercentage,
 		})
 	}
@@ -625,14 +1368,19 @@ func GetBudgetDetails(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// GetDashboardStats returns dashboard statistics for admin
+// GetDashboardStats returns dashboard statistics for admin. Pass
+// ?display_currency=USD to additionally total every account's current
+// balance converted into that currency as of today, making the total
+// meaningful across users who don't all bank in the same currency.
 func GetDashboardStats(c *gin.Context) {
 	var stats struct {
-		TotalUsers        int64 `json:"totalUsers"`
-		TotalTransactions int64 `json:"totalTransactions"`
-		TotalAccounts     int64 `json:"totalAccounts"`
-		TotalCategories   int64 `json:"totalCategories"`
-		TotalBudgets      int64 `json:"totalBudgets"`
+		TotalUsers        int64  `json:"totalUsers"`
+		TotalTransactions int64  `json:"totalTransactions"`
+		TotalAccounts     int64  `json:"totalAccounts"`
+		TotalCategories   int64  `json:"totalCategories"`
+		TotalBudgets      int64  `json:"totalBudgets"`
+		DisplayCurrency   string `json:"displayCurrency,omitempty"`
+		TotalBalanceCents *int64 `json:"totalBalanceCents,omitempty"`
 	}
 
 	// Count users
@@ -650,10 +1398,34 @@ func GetDashboardStats(c *gin.Context) {
 	// Count budgets
 	db.DB.Model(&models.Budget{}).Count(&stats.TotalBudgets)
 
+	if displayCurrency := c.Query("display_currency"); displayCurrency != "" {
+		var rows []struct {
+			Currency       string
+			CurrentBalance decimal.Decimal
+		}
+		db.DB.Model(&models.Account{}).Select("currency, current_balance").Scan(&rows)
+
+		now := time.Now()
+		var total int64
+		for _, row := range rows {
+			converted, err := fx.ConvertCents(centsOf(row.CurrentBalance), row.Currency, displayCurrency, now)
+			if err != nil {
+				log.Printf("fx: converting dashboard total %s->%s: %v", row.Currency, displayCurrency, err)
+				continue
+			}
+			total += converted
+		}
+		stats.DisplayCurrency = displayCurrency
+		stats.TotalBalanceCents = &total
+	}
+
 	c.JSON(http.StatusOK, stats)
 }
 
-// UpdateUserRole allows admin to change user roles
+// UpdateUserRole allows a privileged admin to change a user's role.
+// Granting SuperAdmin requires the actor to already be SuperAdmin, and a
+// SuperAdmin may not strip their own SuperAdmin role if they're the only
+// one left - the generalized form of "cannot demote self".
 func UpdateUserRole(c *gin.Context) {
 	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -671,23 +1443,113 @@ func UpdateUserRole(c *gin.Context) {
 	}
 
 	// Validate role
-	if input.Role != models.UserRoleUser && input.Role != models.UserRoleAdmin {
+	if !isAssignableRole(input.Role) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role"})
 		return
 	}
 
-	// Prevent admin from demoting themselves
-	adminUser, _ := c.Get("adminUser")
-	if adminUser.(models.User).ID == uint(userID) && input.Role != models.UserRoleAdmin {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot change your own admin role"})
+	adminUser := c.MustGet("adminUser").(models.User)
+
+	// Only a SuperAdmin can grant the SuperAdmin role to anyone
+	if input.Role == models.UserRoleSuperAdmin && adminUser.Role != models.UserRoleSuperAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only a SuperAdmin can grant the SuperAdmin role"})
 		return
 	}
 
+	// The last remaining SuperAdmin's role may not be demoted away, whether
+	// they're doing it to themselves or another SuperAdmin is doing it to
+	// them - either way it would leave nobody able to grant SuperAdmin or
+	// manage role permissions.
+	if input.Role != models.UserRoleSuperAdmin {
+		var targetUser models.User
+		if err := db.DB.First(&targetUser, userID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		if targetUser.Role == models.UserRoleSuperAdmin {
+			var superAdminCount int64
+			db.DB.Model(&models.User{}).Where("role = ?", models.UserRoleSuperAdmin).Count(&superAdminCount)
+			if superAdminCount <= 1 {
+				message := "cannot remove the only SuperAdmin's role"
+				if adminUser.ID == uint(userID) {
+					message = "cannot remove your own SuperAdmin role: you are the only SuperAdmin"
+				}
+				c.JSON(http.StatusBadRequest, gin.H{"error": message})
+				return
+			}
+		}
+	}
+
 	// Update user role
 	if err := db.DB.Model(&models.User{}).Where("id = ?", userID).Update("role", input.Role).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update user role"})
 		return
 	}
 
+	middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+		UserID:    uint(userID),
+		EventType: "role_change",
+		Outcome:   "success",
+		Details:   map[string]interface{}{"new_role": input.Role, "changed_by": adminUser.ID},
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+
 	c.JSON(http.StatusOK, gin.H{"message": "user role updated successfully"})
 }
+
+// UpdateUserScopes replaces a user's direct Permission grants (see
+// middleware.RequireScope) - permissions granted on top of whatever their
+// Role already carries via role_permissions, without promoting them to a
+// new role.
+func UpdateUserScopes(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var input struct {
+		Scopes []models.Permission `json:"scopes" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	valid := make(map[models.Permission]bool, len(models.AllPermissions))
+	for _, perm := range models.AllPermissions {
+		valid[perm] = true
+	}
+	scopes := make(models.StringList, 0, len(input.Scopes))
+	for _, perm := range input.Scopes {
+		if !valid[perm] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown scope: " + string(perm)})
+			return
+		}
+		scopes = append(scopes, string(perm))
+	}
+
+	var targetUser models.User
+	if err := db.DB.First(&targetUser, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	if err := db.DB.Model(&targetUser).Update("scopes", scopes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update user scopes"})
+		return
+	}
+
+	adminUser := c.MustGet("adminUser").(models.User)
+	middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+		UserID:    uint(userID),
+		EventType: "scopes_change",
+		Outcome:   "success",
+		Details:   map[string]interface{}{"new_scopes": input.Scopes, "changed_by": adminUser.ID},
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "user scopes updated successfully", "scopes": scopes})
+}
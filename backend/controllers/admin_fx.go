@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"time"
+
+	"Personal-Finance-Tracker-backend/services/fx"
+)
+
+// convertedAmount converts amountCents (in native's minor units) into
+// display as of on, returning the converted amount and the rate used so
+// callers can surface both the native and converted values plus the rate,
+// per admin listing's ?display_currency= contract.
+func convertedAmount(amountCents int64, native, display string, on time.Time) (converted int64, rate float64, err error) {
+	rate, err = fx.RateOn(native, display, on)
+	if err != nil {
+		return 0, 0, err
+	}
+	converted, err = fx.ConvertCents(amountCents, native, display, on)
+	if err != nil {
+		return 0, 0, err
+	}
+	return converted, rate, nil
+}
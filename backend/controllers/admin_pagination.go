@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// pageCursor is the decoded form of an admin listing cursor: the
+// (created_at, id) of the last row seen, used for keyset pagination so
+// pages stay stable under concurrent writes (unlike OFFSET).
+type pageCursor struct {
+	ID uint  `json:"id"`
+	TS int64 `json:"ts"`
+}
+
+// ParseCursor reads ?cursor= and ?limit= off the request, validating both.
+// limit defaults to defaultPageLimit and is capped at maxPageLimit. The
+// returned cursor is the raw opaque token as supplied by the client (or ""
+// for the first page) - decode it with decodeCursor once you're ready to
+// build the keyset WHERE clause.
+func ParseCursor(c *gin.Context) (cursor string, limit int, err error) {
+	limit = defaultPageLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, convErr := strconv.Atoi(raw)
+		if convErr != nil || n <= 0 {
+			return "", 0, errors.New("invalid limit")
+		}
+		if n > maxPageLimit {
+			n = maxPageLimit
+		}
+		limit = n
+	}
+
+	cursor = c.Query("cursor")
+	if cursor != "" {
+		if _, decErr := decodeCursor(cursor); decErr != nil {
+			return "", 0, errors.New("invalid cursor")
+		}
+	}
+
+	return cursor, limit, nil
+}
+
+// decodeCursor turns an opaque cursor token back into the (id, created_at)
+// pair it was encoded from.
+func decodeCursor(cursor string) (*pageCursor, error) {
+	raw, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	var pc pageCursor
+	if err := json.Unmarshal(raw, &pc); err != nil {
+		return nil, err
+	}
+	return &pc, nil
+}
+
+// encodeCursor produces the opaque cursor token for a row, to be handed
+// back to the client as next_cursor.
+func encodeCursor(id uint, createdAt time.Time) string {
+	raw, _ := json.Marshal(pageCursor{ID: id, TS: createdAt.Unix()})
+	return base64.URLEncoding.EncodeToString(raw)
+}
+
+// adminSortColumns whitelists the columns admin listing endpoints may sort
+// by. It's deliberately limited to columns present in the cursor payload -
+// sorting by anything else would make keyset pagination inconsistent.
+var adminSortColumns = map[string]bool{"created_at": true, "id": true}
+
+// parseSort reads ?sort= (e.g. "created_at", "-created_at", "id", "-id").
+// A leading "-" means ascending; no prefix means descending, matching the
+// endpoints' existing newest-first default.
+func parseSort(c *gin.Context) (column string, desc bool, err error) {
+	sort := c.Query("sort")
+	if sort == "" {
+		return "created_at", true, nil
+	}
+	desc = true
+	if sort[0] == '-' {
+		desc = false
+		sort = sort[1:]
+	}
+	if !adminSortColumns[sort] {
+		return "", false, errors.New("invalid sort column")
+	}
+	return sort, desc, nil
+}
+
+// qualify prefixes column with "alias." when alias is non-empty, so the
+// same helpers work for plain GORM queries and aliased raw-SQL joins.
+func qualify(alias, column string) string {
+	if alias == "" {
+		return column
+	}
+	return alias + "." + column
+}
+
+// orderClause builds an ORDER BY expression for sortCol, always adding id
+// as a tiebreaker so paging stays deterministic when rows share a value.
+func orderClause(alias, sortCol string, desc bool) string {
+	dir := "DESC"
+	if !desc {
+		dir = "ASC"
+	}
+	if sortCol == "id" {
+		return qualify(alias, "id") + " " + dir
+	}
+	return qualify(alias, sortCol) + " " + dir + ", " + qualify(alias, "id") + " " + dir
+}
+
+// keysetClause returns the WHERE fragment selecting rows strictly after a
+// cursor for the given sort column/direction, plus a function that turns a
+// decoded cursor into the args it expects, in order.
+func keysetClause(alias, sortCol string, desc bool) (clause string, args func(pageCursor) []interface{}) {
+	op := "<"
+	if !desc {
+		op = ">"
+	}
+	id := qualify(alias, "id")
+	if sortCol == "id" {
+		return id + " " + op + " ?", func(pc pageCursor) []interface{} { return []interface{}{pc.ID} }
+	}
+	createdAt := qualify(alias, "created_at")
+	clause = "(" + createdAt + " " + op + " ? OR (" + createdAt + " = ? AND " + id + " " + op + " ?))"
+	return clause, func(pc pageCursor) []interface{} {
+		ts := time.Unix(pc.TS, 0)
+		return []interface{}{ts, ts, pc.ID}
+	}
+}
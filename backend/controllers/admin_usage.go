@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// confirmTokenTTL is how long a usage preview's confirm_token remains valid.
+// Short enough that a stale preview is unlikely to still "look right" to the
+// admin by the time they act on it.
+const confirmTokenTTL = 5 * time.Minute
+
+// errConfirmTokenStale means the token's signature and admin/target binding
+// check out, but the usage snapshot it was issued for no longer matches the
+// current data - something changed between preview and confirm.
+var errConfirmTokenStale = errors.New("usage snapshot has changed since preview; re-fetch the usage endpoint and retry")
+
+// userUsageSnapshot summarizes the rows a DeleteUserAdmin call would affect,
+// returned by GetUserUsageAdmin so an admin can see the blast radius before
+// confirming.
+type userUsageSnapshot struct {
+	AccountCount        int64      `json:"account_count"`
+	CategoryCount       int64      `json:"category_count"`
+	TransactionCount    int64      `json:"transaction_count"`
+	BudgetCount         int64      `json:"budget_count"`
+	BudgetItemCount     int64      `json:"budget_item_count"`
+	SplitCount          int64      `json:"split_count"`
+	PositiveAmountCents int64      `json:"positive_amount_cents"`
+	NegativeAmountCents int64      `json:"negative_amount_cents"`
+	OldestTxnDate       *time.Time `json:"oldest_transaction_date,omitempty"`
+	NewestTxnDate       *time.Time `json:"newest_transaction_date,omitempty"`
+	HasNonZeroBalance   bool       `json:"has_nonzero_balance_accounts"`
+}
+
+// accountUsageSnapshot summarizes the rows a DeleteAccountAdmin call would
+// affect, returned by GetAccountUsageAdmin.
+type accountUsageSnapshot struct {
+	TransactionCount    int64      `json:"transaction_count"`
+	SplitCount          int64      `json:"split_count"`
+	PositiveAmountCents int64      `json:"positive_amount_cents"`
+	NegativeAmountCents int64      `json:"negative_amount_cents"`
+	OldestTxnDate       *time.Time `json:"oldest_transaction_date,omitempty"`
+	NewestTxnDate       *time.Time `json:"newest_transaction_date,omitempty"`
+	HasNonZeroBalance   bool       `json:"has_nonzero_balance"`
+}
+
+// buildUserUsageSnapshot gathers the counts and totals DeleteUserAdmin is
+// about to wipe out for the given user.
+func buildUserUsageSnapshot(userID uint) (userUsageSnapshot, error) {
+	var snap userUsageSnapshot
+
+	if err := db.DB.Model(&models.Account{}).Where("user_id = ?", userID).Count(&snap.AccountCount).Error; err != nil {
+		return snap, err
+	}
+	if err := db.DB.Model(&models.Category{}).Where("user_id = ?", userID).Count(&snap.CategoryCount).Error; err != nil {
+		return snap, err
+	}
+	if err := db.DB.Model(&models.Transaction{}).Where("user_id = ?", userID).Count(&snap.TransactionCount).Error; err != nil {
+		return snap, err
+	}
+	if err := db.DB.Model(&models.Budget{}).Where("user_id = ?", userID).Count(&snap.BudgetCount).Error; err != nil {
+		return snap, err
+	}
+	if err := db.DB.Model(&models.BudgetItem{}).Where("budget_id IN (SELECT id FROM budgets WHERE user_id = ?)", userID).Count(&snap.BudgetItemCount).Error; err != nil {
+		return snap, err
+	}
+	if err := db.DB.Model(&models.TransactionSplit{}).Where("parent_txn_id IN (SELECT id FROM transactions WHERE user_id = ?)", userID).Count(&snap.SplitCount).Error; err != nil {
+		return snap, err
+	}
+	var positive, negative decimal.NullDecimal
+	if err := db.DB.Model(&models.Transaction{}).Where("user_id = ? AND amount > 0", userID).Select("COALESCE(SUM(amount), 0)").Scan(&positive).Error; err != nil {
+		return snap, err
+	}
+	if err := db.DB.Model(&models.Transaction{}).Where("user_id = ? AND amount < 0", userID).Select("COALESCE(SUM(amount), 0)").Scan(&negative).Error; err != nil {
+		return snap, err
+	}
+	snap.PositiveAmountCents = centsOf(positive.Decimal)
+	snap.NegativeAmountCents = centsOf(negative.Decimal)
+
+	var oldest, newest models.Transaction
+	if err := db.DB.Where("user_id = ?", userID).Order("txn_date ASC").First(&oldest).Error; err == nil {
+		snap.OldestTxnDate = &oldest.TxnDate
+	}
+	if err := db.DB.Where("user_id = ?", userID).Order("txn_date DESC").First(&newest).Error; err == nil {
+		snap.NewestTxnDate = &newest.TxnDate
+	}
+
+	var nonZeroAccounts int64
+	if err := db.DB.Model(&models.Account{}).Where("user_id = ? AND current_balance <> 0", userID).Count(&nonZeroAccounts).Error; err != nil {
+		return snap, err
+	}
+	snap.HasNonZeroBalance = nonZeroAccounts > 0
+
+	return snap, nil
+}
+
+// buildAccountUsageSnapshot gathers the counts and totals DeleteAccountAdmin
+// is about to wipe out for the given account.
+func buildAccountUsageSnapshot(accountID uint) (accountUsageSnapshot, error) {
+	var snap accountUsageSnapshot
+
+	if err := db.DB.Model(&models.Transaction{}).Where("account_id = ?", accountID).Count(&snap.TransactionCount).Error; err != nil {
+		return snap, err
+	}
+	if err := db.DB.Model(&models.TransactionSplit{}).Where("parent_txn_id IN (SELECT id FROM transactions WHERE account_id = ?)", accountID).Count(&snap.SplitCount).Error; err != nil {
+		return snap, err
+	}
+	var positive, negative decimal.NullDecimal
+	if err := db.DB.Model(&models.Transaction{}).Where("account_id = ? AND amount > 0", accountID).Select("COALESCE(SUM(amount), 0)").Scan(&positive).Error; err != nil {
+		return snap, err
+	}
+	if err := db.DB.Model(&models.Transaction{}).Where("account_id = ? AND amount < 0", accountID).Select("COALESCE(SUM(amount), 0)").Scan(&negative).Error; err != nil {
+		return snap, err
+	}
+	snap.PositiveAmountCents = centsOf(positive.Decimal)
+	snap.NegativeAmountCents = centsOf(negative.Decimal)
+
+	var oldest, newest models.Transaction
+	if err := db.DB.Where("account_id = ?", accountID).Order("txn_date ASC").First(&oldest).Error; err == nil {
+		snap.OldestTxnDate = &oldest.TxnDate
+	}
+	if err := db.DB.Where("account_id = ?", accountID).Order("txn_date DESC").First(&newest).Error; err == nil {
+		snap.NewestTxnDate = &newest.TxnDate
+	}
+
+	var account models.Account
+	if err := db.DB.Where("id = ?", accountID).First(&account).Error; err == nil {
+		snap.HasNonZeroBalance = !account.CurrentBalance.IsZero()
+	}
+
+	return snap, nil
+}
+
+// snapshotHash hashes a usage snapshot so a confirm_token can bind to it
+// without embedding the whole payload.
+func snapshotHash(snapshot interface{}) string {
+	payload, _ := json.Marshal(snapshot)
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// generateConfirmToken signs (adminID, targetID, snapshotHash, expiry) with
+// the server's JWT secret, so DeleteUserAdmin/DeleteAccountAdmin can require
+// proof that this admin previewed this exact snapshot before destroying it.
+func generateConfirmToken(adminID, targetID uint, snapHash string) string {
+	exp := time.Now().Add(confirmTokenTTL).Unix()
+	payload := fmt.Sprintf("%d:%d:%s:%d", adminID, targetID, snapHash, exp)
+
+	mac := hmac.New(sha256.New, middleware.JWTSecret())
+	mac.Write([]byte(payload))
+	sig := hex.EncodeToString(mac.Sum(nil))
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload + ":" + sig))
+}
+
+// verifyConfirmToken checks a confirm_token's signature, admin/target
+// binding and expiry, then compares its snapshot hash against currentHash.
+// A signature/binding/expiry failure is a caller error (bad token); a hash
+// mismatch is errConfirmTokenStale (stale preview, data changed underneath).
+func verifyConfirmToken(token string, adminID, targetID uint, currentHash string) error {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return errors.New("malformed confirm_token")
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 5 {
+		return errors.New("malformed confirm_token")
+	}
+	tokAdminID, tokTargetID, tokHash, tokExp, sig := parts[0], parts[1], parts[2], parts[3], parts[4]
+
+	mac := hmac.New(sha256.New, middleware.JWTSecret())
+	mac.Write([]byte(strings.Join(parts[:4], ":")))
+	expectedSig := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(sig), []byte(expectedSig)) {
+		return errors.New("invalid confirm_token")
+	}
+
+	if tokAdminID != strconv.FormatUint(uint64(adminID), 10) || tokTargetID != strconv.FormatUint(uint64(targetID), 10) {
+		return errors.New("confirm_token does not match this admin or target")
+	}
+
+	exp, err := strconv.ParseInt(tokExp, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return errors.New("confirm_token has expired; re-fetch the usage endpoint and retry")
+	}
+
+	if tokHash != currentHash {
+		return errConfirmTokenStale
+	}
+	return nil
+}
+
+// requireConfirmToken reads ?confirm_token= off the request and verifies it
+// for (adminID, targetID) against currentHash, the caller's hash of the live
+// usage snapshot, writing the appropriate error response itself on failure. Returns
+// false when the caller should stop handling the request.
+func requireConfirmToken(c *gin.Context, adminID, targetID uint, currentHash string) bool {
+	token := c.Query("confirm_token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "confirm_token is required; preview the usage endpoint first"})
+		return false
+	}
+	if err := verifyConfirmToken(token, adminID, targetID, currentHash); err != nil {
+		if errors.Is(err, errConfirmTokenStale) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return false
+	}
+	return true
+}
+
+// GetUserUsageAdmin previews the blast radius of DeleteUserAdmin: counts and
+// monetary totals of everything cascading from the user, plus a confirm_token
+// that call must echo back via ?confirm_token= to prove it's acting on this
+// exact snapshot.
+func GetUserUsageAdmin(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	snap, err := buildUserUsageSnapshot(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute usage snapshot"})
+		return
+	}
+
+	adminUser, _ := c.Get("adminUser")
+	token := generateConfirmToken(adminUser.(models.User).ID, uint(userID), snapshotHash(snap))
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":        userID,
+		"usage":          snap,
+		"confirm_token":  token,
+		"expires_in_sec": int(confirmTokenTTL.Seconds()),
+	})
+}
+
+// GetAccountUsageAdmin previews the blast radius of DeleteAccountAdmin,
+// mirroring GetUserUsageAdmin at the account level.
+func GetAccountUsageAdmin(c *gin.Context) {
+	accountID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account ID"})
+		return
+	}
+
+	var account models.Account
+	if err := db.DB.Where("id = ?", accountID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	snap, err := buildAccountUsageSnapshot(uint(accountID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute usage snapshot"})
+		return
+	}
+
+	adminUser, _ := c.Get("adminUser")
+	token := generateConfirmToken(adminUser.(models.User).ID, uint(accountID), snapshotHash(snap))
+
+	c.JSON(http.StatusOK, gin.H{
+		"account_id":     accountID,
+		"usage":          snap,
+		"confirm_token":  token,
+		"expires_in_sec": int(confirmTokenTTL.Seconds()),
+	})
+}
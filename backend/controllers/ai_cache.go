@@ -0,0 +1,460 @@
+package controllers
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/pkg/money"
+	"Personal-Finance-Tracker-backend/pkg/timeutil"
+	"Personal-Finance-Tracker-backend/services/ai"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// Circuit-breaker and cache tuning, exported so tests can shrink the
+// window/cooldown instead of sleeping out the production defaults.
+var (
+	AIBreakerFailureThreshold = 5
+	AIBreakerWindow           = 2 * time.Minute
+	AIBreakerCooldown         = 30 * time.Second
+	aiPredictionCacheCapacity = 500
+)
+
+// AIPredictionCacheTTL bounds how long a cached prediction is served
+// without calling AIPredictor again, read once at startup from
+// AI_PREDICTION_CACHE_TTL (seconds). Exported so tests can shrink it
+// instead of sleeping out the production default.
+var AIPredictionCacheTTL = aiPredictionCacheTTLDefault()
+
+const defaultAIPredictionCacheTTL = time.Hour
+
+func aiPredictionCacheTTLDefault() time.Duration {
+	if raw := os.Getenv("AI_PREDICTION_CACHE_TTL"); raw != "" {
+		if seconds, err := strconv.Atoi(raw); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultAIPredictionCacheTTL
+}
+
+// predictionCacheKey identifies one cached GetBudgetPrediction result.
+// transactionsHash folds in transactionsFingerprint's summary of the
+// user's own transaction history, so an added, edited, or removed
+// transaction misses the cache instead of serving a now-stale prediction
+// for the rest of its TTL.
+type predictionCacheKey struct {
+	userID           uint
+	targetPeriod     timeutil.YearMonth
+	historicalMonths int
+	transactionsHash string
+}
+
+// string renders key as a single string for the singleflight group, which
+// needs a comparable map key that doesn't depend on predictionCacheKey
+// remaining hashable on its own.
+func (k predictionCacheKey) string() string {
+	return fmt.Sprintf("%d|%s|%d|%s", k.userID, k.targetPeriod, k.historicalMonths, k.transactionsHash)
+}
+
+type predictionCacheEntry struct {
+	key       predictionCacheKey
+	response  ai.PredictResponse
+	cachedAt  time.Time
+	expiresAt time.Time
+}
+
+// predictionCache is a bounded LRU of recent predictions, so an open
+// breaker or a failed Predict call can serve the user's last known
+// prediction (flagged stale) instead of a bare error.
+type predictionCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front = most recently used
+	items    map[predictionCacheKey]*list.Element
+}
+
+func newPredictionCache(capacity int) *predictionCache {
+	return &predictionCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[predictionCacheKey]*list.Element),
+	}
+}
+
+func (c *predictionCache) get(key predictionCacheKey) (predictionCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return predictionCacheEntry{}, false
+	}
+	c.order.MoveToFront(el)
+	return *el.Value.(*predictionCacheEntry), true
+}
+
+func (c *predictionCache) set(key predictionCacheKey, resp ai.PredictResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	expiresAt := now.Add(AIPredictionCacheTTL)
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*predictionCacheEntry)
+		entry.response = resp
+		entry.cachedAt = now
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&predictionCacheEntry{key: key, response: resp, cachedAt: now, expiresAt: expiresAt})
+	c.items[key] = el
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*predictionCacheEntry).key)
+	}
+}
+
+// fresh reports whether get's returned entry is still within its TTL. A
+// refresh request or an expired entry both fall through to a fresh
+// AIPredictor call; degradedPrediction still serves a stale entry rather
+// than nothing, since that path never consults freshness.
+func (e predictionCacheEntry) fresh() bool {
+	return time.Now().Before(e.expiresAt)
+}
+
+var aiPredictionCache = newPredictionCache(aiPredictionCacheCapacity)
+
+// breakerState is one of the three states circuitBreaker's doc comment
+// describes.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker trips from closed to open after AIBreakerFailureThreshold
+// consecutive failures inside AIBreakerWindow, short-circuits every call
+// for AIBreakerCooldown, then lets exactly one half-open trial call
+// through - a success closes it again, a failure re-opens it.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	state    breakerState
+	failures []time.Time
+	openedAt time.Time
+}
+
+func newCircuitBreaker() *circuitBreaker {
+	return &circuitBreaker{state: breakerClosed}
+}
+
+// allow reports whether a call should be attempted right now, transitioning
+// open -> half-open once AIBreakerCooldown has elapsed.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerHalfOpen {
+		return false
+	}
+	if b.state == breakerOpen {
+		if time.Since(b.openedAt) < AIBreakerCooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+	}
+	return true
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = nil
+	b.state = breakerClosed
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerHalfOpen {
+		b.trip()
+		return
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-AIBreakerWindow)
+	kept := b.failures[:0]
+	for _, t := range b.failures {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	b.failures = append(kept, now)
+	if len(b.failures) >= AIBreakerFailureThreshold {
+		b.trip()
+	}
+}
+
+func (b *circuitBreaker) trip() {
+	b.state = breakerOpen
+	b.openedAt = time.Now()
+	b.failures = nil
+}
+
+var aiBreaker = newCircuitBreaker()
+
+// ResetAIResilienceState clears the prediction cache and re-closes the
+// circuit breaker. Exported for tests that need a clean slate between
+// resilience test cases; production code never calls this.
+func ResetAIResilienceState() {
+	aiBreaker = newCircuitBreaker()
+	aiPredictionCache = newPredictionCache(aiPredictionCacheCapacity)
+}
+
+// ClearPredictionCache empties the prediction cache on demand. It is the
+// production counterpart to ResetAIResilienceState, exposed to admins via
+// DELETE /api/admin/ai/predictions/cache for the rare case a stale
+// prediction needs to be evicted before its TTL expires.
+func ClearPredictionCache(c *gin.Context) {
+	aiPredictionCache = newPredictionCache(aiPredictionCacheCapacity)
+	c.JSON(http.StatusOK, gin.H{"message": "prediction cache cleared"})
+}
+
+// singleflightCall is one in-flight (or just-finished) Predict call that
+// other callers with the same key can wait on instead of triggering a
+// second upstream call.
+type singleflightCall struct {
+	wg   sync.WaitGroup
+	resp ai.PredictResponse
+	err  error
+}
+
+// singleflightGroup collapses concurrent callers sharing the same key into
+// a single underlying call, a hand-rolled stand-in for
+// golang.org/x/sync/singleflight since this tree has no go.mod to vendor it
+// through.
+type singleflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*singleflightCall
+}
+
+func newSingleflightGroup() *singleflightGroup {
+	return &singleflightGroup{calls: make(map[string]*singleflightCall)}
+}
+
+// do runs fn for the first caller with a given key and hands its result to
+// every other caller that arrives with the same key while fn is in flight.
+func (g *singleflightGroup) do(key string, fn func() (ai.PredictResponse, error)) (ai.PredictResponse, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.resp, call.err
+	}
+
+	call := &singleflightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.resp, call.err = fn()
+	call.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return call.resp, call.err
+}
+
+var aiPredictGroup = newSingleflightGroup()
+
+// transactionsFingerprint summarizes the user's transactions over the
+// prediction's historical window as a short hash, so predictionCacheKey
+// misses the cache as soon as a transaction in that window is added,
+// edited, or removed. Transaction has no UpdatedAt column, so the
+// fingerprint is built from count/sum/max-id rather than a last-modified
+// timestamp.
+func transactionsFingerprint(ctx context.Context, userID uint, historicalMonths int) string {
+	from := time.Now().AddDate(0, -historicalMonths, 0)
+
+	var summary struct {
+		Count int64
+		Sum   decimal.Decimal
+		MaxID uint
+	}
+	db.DB.WithContext(ctx).Table("transactions").
+		Select("COUNT(*) as count, COALESCE(SUM(amount), 0) as sum, COALESCE(MAX(id), 0) as max_id").
+		Where("user_id = ? AND txn_date >= ?", userID, from).
+		Scan(&summary)
+
+	raw := fmt.Sprintf("%d|%s|%d", summary.Count, summary.Sum.String(), summary.MaxID)
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// computePredictionETag hashes the fields GetBudgetPrediction actually
+// renders to the client, deliberately excluding GeneratedAt and Message -
+// both can change on every call even when the predictions themselves
+// haven't, which would defeat the point of an ETag.
+func computePredictionETag(resp ai.PredictResponse) string {
+	etagBody := struct {
+		Predictions          []ai.BudgetPrediction `json:"predictions"`
+		TargetPeriod         timeutil.YearMonth    `json:"target_period"`
+		UserID               uint                  `json:"user_id"`
+		HistoricalDataPoints int                   `json:"historical_data_points"`
+	}{resp.Predictions, resp.TargetPeriod, resp.UserID, resp.HistoricalDataPoints}
+
+	encoded, _ := json.Marshal(etagBody)
+	sum := sha256.Sum256(encoded)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// predictWithResilience wraps AIPredictor().Predict with the cache and
+// circuit breaker above: a healthy call is cached for AIPredictionCacheTTL;
+// a call the breaker short-circuits, or one that fails outright, falls back
+// to the last cached prediction (marked stale) and, if nothing is cached
+// yet, to a plain moving average computed straight from the user's own
+// transactions. The bool return is the response's "stale" flag.
+//
+// refresh bypasses a fresh cache entry (the caller passed ?refresh=true) and
+// forces a new upstream call. Either way, concurrent callers sharing the
+// same key collapse onto one upstream call via aiPredictGroup, so a
+// thundering herd of dashboard loads costs one AI call instead of many.
+func predictWithResilience(ctx context.Context, req ai.PredictRequest, refresh bool) (ai.PredictResponse, bool) {
+	key := predictionCacheKey{
+		userID:           req.UserID,
+		targetPeriod:     req.TargetPeriod,
+		historicalMonths: req.HistoricalMonths,
+		transactionsHash: transactionsFingerprint(ctx, req.UserID, req.HistoricalMonths),
+	}
+
+	if !refresh {
+		if entry, ok := aiPredictionCache.get(key); ok && entry.fresh() {
+			return entry.response, false
+		}
+	}
+
+	if !aiBreaker.allow() {
+		return degradedPrediction(ctx, key, req), true
+	}
+
+	resp, err := aiPredictGroup.do(key.string(), func() (ai.PredictResponse, error) {
+		return AIPredictor().Predict(ctx, req)
+	})
+	if err != nil {
+		aiBreaker.recordFailure()
+		return degradedPrediction(ctx, key, req), true
+	}
+
+	aiBreaker.recordSuccess()
+	aiPredictionCache.set(key, resp)
+	persistPredictions(ctx, resp)
+	return resp, false
+}
+
+// degradedPrediction is what predictWithResilience falls back to once the
+// breaker is open or a call has failed: the cached prediction if there is
+// one - stale data beats none - else a moving-average fallback computed
+// directly from GORM.
+func degradedPrediction(ctx context.Context, key predictionCacheKey, req ai.PredictRequest) ai.PredictResponse {
+	if entry, ok := aiPredictionCache.get(key); ok {
+		return entry.response
+	}
+	return movingAverageFallback(ctx, req)
+}
+
+// movingAverageFallback computes, per category, the plain mean of the
+// user's last HistoricalMonths of spend - no trend or seasonality, just
+// enough to keep the dashboard populated while AIPredictor recovers.
+func movingAverageFallback(ctx context.Context, req ai.PredictRequest) ai.PredictResponse {
+	from := time.Now().AddDate(0, -req.HistoricalMonths, 0)
+	to := time.Now()
+
+	type categoryMonthRow struct {
+		CategoryID   uint
+		CategoryName string
+		Month        string
+		Total        decimal.Decimal
+	}
+	var rows []categoryMonthRow
+	db.DB.WithContext(ctx).Table("transactions").
+		Select("categories.id as category_id, categories.name as category_name, "+aiCacheMonthGroupExpr("transactions.txn_date")+" as month, SUM(ABS(transactions.amount)) as total").
+		Joins("JOIN categories ON categories.id = transactions.category_id").
+		Where("transactions.user_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ? AND transactions.amount < 0", req.UserID, from, to).
+		Group("categories.id, categories.name, month").
+		Scan(&rows)
+
+	type categoryTotals struct {
+		name   string
+		sum    int64
+		months int64
+	}
+	byCategory := map[uint]*categoryTotals{}
+	var categoryIDs []uint
+	for _, row := range rows {
+		totals, ok := byCategory[row.CategoryID]
+		if !ok {
+			totals = &categoryTotals{name: row.CategoryName}
+			byCategory[row.CategoryID] = totals
+			categoryIDs = append(categoryIDs, row.CategoryID)
+		}
+		totals.sum += centsOf(row.Total)
+		totals.months++
+	}
+	sort.Slice(categoryIDs, func(i, j int) bool { return categoryIDs[i] < categoryIDs[j] })
+
+	predictions := make([]ai.BudgetPrediction, 0, len(categoryIDs))
+	var historicalDataPoints int
+	for _, categoryID := range categoryIDs {
+		totals := byCategory[categoryID]
+		historicalDataPoints += int(totals.months)
+		avg := money.FromCents(totals.sum / totals.months)
+
+		predictions = append(predictions, ai.BudgetPrediction{
+			CategoryID:      categoryID,
+			CategoryName:    totals.name,
+			PredictedAmount: avg,
+			ConfidenceScore: 0.3,
+			HistoricalAvg:   avg,
+			TrendDirection:  "stable",
+			Reasoning:       "Degraded mode: showing a plain historical average while the prediction service recovers.",
+		})
+	}
+
+	message := "Prediction service degraded - showing historical averages"
+	if len(predictions) == 0 {
+		message = "Prediction service unavailable and no historical data to fall back on"
+	}
+
+	return ai.PredictResponse{
+		Predictions:          predictions,
+		TargetPeriod:         req.TargetPeriod,
+		UserID:               req.UserID,
+		HistoricalDataPoints: historicalDataPoints,
+		Message:              message,
+		GeneratedAt:          time.Now().UTC(),
+	}
+}
+
+// aiCacheMonthGroupExpr mirrors the same helper in services/ai/ai.go,
+// controllers/reports.go and store/admin_store.go.
+func aiCacheMonthGroupExpr(column string) string {
+	if db.DB.Dialector.Name() == "sqlite" {
+		return "STRFTIME('%Y-%m', " + column + ")"
+	}
+	return "TO_CHAR(DATE_TRUNC('month', " + column + "), 'YYYY-MM')"
+}
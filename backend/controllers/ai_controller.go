@@ -1,226 +1,395 @@
 package controllers
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"os"
 	"strconv"
-	"time"
+	"strings"
+
+	"Personal-Finance-Tracker-backend/controllers/aidriver"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/pkg/timeutil"
+	"Personal-Finance-Tracker-backend/services/ai"
+	"Personal-Finance-Tracker-backend/services/anomaly"
 
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
 )
 
-// BudgetPrediction represents an AI-generated budget prediction
-type BudgetPrediction struct {
-	CategoryID             uint    `json:"category_id"`
-	CategoryName           string  `json:"category_name"`
-	PredictedAmountCents   int64   `json:"predicted_amount_cents"`
-	PredictedAmountDollars float64 `json:"predicted_amount_dollars"`
-	ConfidenceScore        float64 `json:"confidence_score"`
-	HistoricalAvgCents     int64   `json:"historical_avg_cents"`
-	HistoricalAvgDollars   float64 `json:"historical_avg_dollars"`
-	TrendDirection         string  `json:"trend_direction"`
-	Reasoning              string  `json:"reasoning"`
-}
+// aiService is the shared ai.Service instance the REST handlers below call
+// into. The forecasting logic lives in services/ai so a future gRPC
+// transport (see proto/ai.proto) can reuse it without drifting from REST.
+var aiService = ai.NewService()
+
+// AIPredictor is the ai.Predictor implementation predictWithResilience
+// (ai_cache.go) calls through to. Defaults to aiService; tests substitute a
+// mocks.MockAIPredictor to simulate upstream failures without touching the
+// database, the same way AdminStore is swapped in admin_controller_test.go.
+// main.go reassigns this at startup to an aidriver-backed adapter when
+// AI_DRIVER selects something other than the local in-process driver.
+var AIPredictor func() ai.Predictor = func() ai.Predictor { return aiService }
+
+// AIPatternsAnalyzer is the ai.PatternsAnalyzer implementation
+// GetSpendingPatterns/GetSpendingPatternsStream call through to. Defaults
+// to aiService; reassigned the same way as AIPredictor.
+var AIPatternsAnalyzer func() ai.PatternsAnalyzer = func() ai.PatternsAnalyzer { return aiService }
+
+// GetBudgetPrediction parses the request's query params and delegates to
+// predictWithResilience, which wraps ai.Service.Predict with a cache and
+// circuit breaker (ai_cache.go) so a struggling or failing prediction
+// service degrades to a stale cached result, or a plain historical
+// average, instead of a bare 500. A client that sends
+// Accept: text/event-stream is handed off to GetBudgetPredictionStream
+// instead, the same dispatch GetSpendingPatterns uses for its own stream.
+//
+// ?refresh=true bypasses the cache and forces a fresh upstream call. The
+// response carries an ETag computed from the predictions themselves
+// (ai_cache.go's computePredictionETag); a request whose If-None-Match
+// matches gets a bare 304 so the dashboard can revalidate without paying
+// for the JSON body again.
+func GetBudgetPrediction(c *gin.Context) {
+	if strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		GetBudgetPredictionStream(c)
+		return
+	}
 
-// PredictBudgetRequest represents the request payload for budget prediction
-type PredictBudgetRequest struct {
-	TargetMonth      int `json:"target_month"`
-	TargetYear       int `json:"target_year"`
-	HistoricalMonths int `json:"historical_months"`
-}
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	targetPeriod, historicalMonths := predictionQueryParams(c)
+	refresh := c.Query("refresh") == "true"
+
+	resp, stale := predictWithResilience(c.Request.Context(), ai.PredictRequest{
+		UserID:           userID,
+		TargetPeriod:     targetPeriod,
+		HistoricalMonths: historicalMonths,
+	}, refresh)
+
+	etag := computePredictionETag(resp)
+	if !refresh && c.GetHeader("If-None-Match") == etag {
+		c.Header("ETag", etag)
+		c.Status(http.StatusNotModified)
+		return
+	}
 
-// PredictBudgetResponse represents the AI service response
-type PredictBudgetResponse struct {
-	Predictions          []BudgetPrediction `json:"predictions"`
-	TargetMonth          int                `json:"target_month"`
-	TargetYear           int                `json:"target_year"`
-	UserID               uint               `json:"user_id"`
-	HistoricalDataPoints int                `json:"historical_data_points"`
-	Message              string             `json:"message"`
+	c.Header("ETag", etag)
+	c.JSON(http.StatusOK, gin.H{
+		"predictions":            resp.Predictions,
+		"target_period":          resp.TargetPeriod,
+		"user_id":                resp.UserID,
+		"historical_data_points": resp.HistoricalDataPoints,
+		"message":                resp.Message,
+		"generated_at":           resp.GeneratedAt,
+		"stale":                  stale,
+	})
 }
 
-// GetBudgetPrediction generates AI-powered budget predictions for the user
-func GetBudgetPrediction(c *gin.Context) {
-	// Step 1: Authenticate
+// GetSpendingPatterns parses the request's query params and delegates to
+// ai.Service.AnalyzePatterns. A client that sends Accept: text/event-stream
+// is handed off to GetSpendingPatternsStream instead, so the same URL works
+// for both a plain JSON fetch and an EventSource subscription.
+func GetSpendingPatterns(c *gin.Context) {
+	if strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		GetSpendingPatternsStream(c)
+		return
+	}
+
 	claims, exists := c.Get("user")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
-
 	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	historicalMonths := patternsHistoricalMonths(c)
 
-	// Step 2: Parse query parameters with defaults
-	targetMonth := time.Now().Month()
-	targetYear := time.Now().Year()
-	historicalMonths := 12
+	resp, err := AIPatternsAnalyzer().AnalyzePatterns(c.Request.Context(), ai.PatternsRequest{
+		UserID:           userID,
+		HistoricalMonths: historicalMonths,
+	})
+	if err != nil {
+		status, message := mapAIDriverError(err)
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
 
-	if monthStr := c.Query("target_month"); monthStr != "" {
-		if month, err := strconv.Atoi(monthStr); err == nil && month >= 1 && month <= 12 {
-			targetMonth = time.Month(month)
-		}
+	patterns := patternsJSON(resp.Patterns)
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":          resp.UserID,
+		"patterns":         patterns,
+		"insights":         resp.Insights,
+		"recommendations":  resp.Recommendations,
+		"analyzed_period":  resp.AnalyzedPeriod,
+		"confidence_score": resp.ConfidenceScore,
+	})
+}
+
+// mapAIDriverError classifies an error an HTTP-based aidriver.Driver
+// (openAIDriver, ollamaDriver) produced - a request that timed out, an
+// upstream that couldn't be reached, or a 4xx the driver passed straight
+// through - into the matching HTTP status and message, instead of
+// GetSpendingPatterns flattening every failure to 500.
+// GetBudgetPrediction doesn't use this: predictWithResilience (ai_cache.go)
+// already turns a driver error into a stale cached or moving-average
+// response rather than an error, which beats surfacing a 5xx to the
+// dashboard.
+func mapAIDriverError(err error) (int, string) {
+	var statusErr *aidriver.UpstreamStatusError
+	switch {
+	case errors.Is(err, aidriver.ErrUpstreamTimeout):
+		return http.StatusGatewayTimeout, err.Error()
+	case errors.Is(err, aidriver.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable, err.Error()
+	case errors.As(err, &statusErr):
+		return statusErr.StatusCode, err.Error()
+	default:
+		return http.StatusInternalServerError, err.Error()
 	}
+}
+
+// minPredictionYear/maxPredictionYear bound the target_period query param
+// GetBudgetPrediction and GetBudgetPredictionStream accept.
+const (
+	minPredictionYear = 2020
+	maxPredictionYear = 2030
+)
 
-	if yearStr := c.Query("target_year"); yearStr != "" {
-		if year, err := strconv.Atoi(yearStr); err == nil && year >= 2020 && year <= 2030 {
-			targetYear = year
+// predictionQueryParams parses the target_month/target_year/
+// historical_months query params shared by GetBudgetPrediction and
+// GetBudgetPredictionStream, defaulting to the current month/year and 12
+// months of history. target_month/target_year are still accepted
+// separately for backward compatibility; a combined target_period=YYYY-MM
+// takes precedence over either.
+func predictionQueryParams(c *gin.Context) (targetPeriod timeutil.YearMonth, historicalMonths int) {
+	targetPeriod = timeutil.Now()
+	historicalMonths = 12
+
+	if periodStr := c.Query("target_period"); periodStr != "" {
+		if parsed, err := timeutil.Parse(periodStr); err == nil && parsed.Validate(minPredictionYear, maxPredictionYear) == nil {
+			targetPeriod = parsed
+		}
+	} else {
+		if monthStr := c.Query("target_month"); monthStr != "" {
+			if month, err := strconv.Atoi(monthStr); err == nil && month >= 1 && month <= 12 {
+				targetPeriod.Month = month
+			}
+		}
+		if yearStr := c.Query("target_year"); yearStr != "" {
+			if year, err := strconv.Atoi(yearStr); err == nil && year >= minPredictionYear && year <= maxPredictionYear {
+				targetPeriod.Year = year
+			}
 		}
 	}
+	if monthsStr := c.Query("historical_months"); monthsStr != "" {
+		if months, err := strconv.Atoi(monthsStr); err == nil && months >= 1 && months <= 36 {
+			historicalMonths = months
+		}
+	}
+	return targetPeriod, historicalMonths
+}
 
+// patternsHistoricalMonths parses the historical_months query param shared
+// by GetSpendingPatterns and GetSpendingPatternsStream, defaulting to 12.
+func patternsHistoricalMonths(c *gin.Context) int {
+	historicalMonths := 12
 	if monthsStr := c.Query("historical_months"); monthsStr != "" {
 		if months, err := strconv.Atoi(monthsStr); err == nil && months >= 1 && months <= 36 {
 			historicalMonths = months
 		}
 	}
+	return historicalMonths
+}
 
-	// Step 3: Prepare request to AI service
-	aiRequest := map[string]interface{}{
-		"user_id":           userID,
-		"target_month":      int(targetMonth),
-		"target_year":       targetYear,
-		"historical_months": historicalMonths,
+// patternsJSON renders an ai.Patterns the same way in both
+// GetSpendingPatterns' JSON body and GetSpendingPatternsStream's "partial"
+// event.
+func patternsJSON(patterns ai.Patterns) gin.H {
+	if patterns == (ai.Patterns{}) {
+		return gin.H{}
+	}
+	return gin.H{
+		"spending_velocity":    patterns.SpendingVelocity,
+		"category_consistency": patterns.CategoryConsistency,
+		"seasonal_trends": gin.H{
+			"highest_month": patterns.SeasonalTrends.HighestMonth,
+			"lowest_month":  patterns.SeasonalTrends.LowestMonth,
+		},
+		"weekend_vs_weekday": gin.H{
+			"weekend_ratio": patterns.WeekendVsWeekday.WeekendRatio,
+			"weekday_ratio": patterns.WeekendVsWeekday.WeekdayRatio,
+		},
 	}
+}
 
-	jsonData, err := json.Marshal(aiRequest)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to prepare AI request"})
+// GetAnomalies flags the authenticated user's transactions that deviate
+// sharply from their own historical category distribution - see
+// services/anomaly.Detect for the MAD/robust-z-score math. Accepts
+// historical_months (default 12) and k (default anomaly.DefaultThreshold)
+// query params.
+func GetAnomalies(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
 
-	// Step 4: Call AI service
-	aiServiceURL := getAIServiceURL() + "/predict-budget"
-
-	resp, err := http.Post(aiServiceURL, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error":   "AI service unavailable",
-			"details": err.Error(),
-		})
-		return
+	historicalMonths := 12
+	if monthsStr := c.Query("historical_months"); monthsStr != "" {
+		if months, err := strconv.Atoi(monthsStr); err == nil && months >= 1 && months <= 36 {
+			historicalMonths = months
+		}
+	}
+	threshold := anomaly.DefaultThreshold
+	if kStr := c.Query("k"); kStr != "" {
+		if k, err := strconv.ParseFloat(kStr, 64); err == nil && k > 0 {
+			threshold = k
+		}
 	}
-	defer resp.Body.Close()
 
-	// Step 5: Parse AI service response
-	body, err := io.ReadAll(resp.Body)
+	anomalies, err := anomaly.Detect(c.Request.Context(), userID, historicalMonths, threshold)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read AI response"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to detect anomalies"})
 		return
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		var errorResp map[string]interface{}
-		if err := json.Unmarshal(body, &errorResp); err == nil {
-			c.JSON(resp.StatusCode, errorResp)
-		} else {
-			c.JSON(resp.StatusCode, gin.H{"error": "AI service error"})
-		}
-		return
+	results := make([]gin.H, 0, len(anomalies))
+	for _, a := range anomalies {
+		results = append(results, gin.H{
+			"category_id":    a.CategoryID,
+			"transaction_id": a.TransactionID,
+			"amount":         a.AmountCents,
+			"score":          a.Score,
+			"expected_range": []int64{a.ExpectedLow, a.ExpectedHigh},
+			"detected_at":    a.DetectedAt,
+		})
 	}
 
-	var aiResponse PredictBudgetResponse
-	if err := json.Unmarshal(body, &aiResponse); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse AI response"})
-		return
-	}
+	c.JSON(http.StatusOK, gin.H{"anomalies": results})
+}
 
-	// Step 6: Return predictions
-	c.JSON(http.StatusOK, gin.H{
-		"predictions":            aiResponse.Predictions,
-		"target_month":           aiResponse.TargetMonth,
-		"target_year":            aiResponse.TargetYear,
-		"user_id":                aiResponse.UserID,
-		"historical_data_points": aiResponse.HistoricalDataPoints,
-		"message":                aiResponse.Message,
-		"generated_at":           time.Now().UTC(),
-	})
+// anomalyWebhookInput is the CreateAnomalyWebhook request body.
+type anomalyWebhookInput struct {
+	URL      string  `json:"url"`
+	MinScore float64 `json:"min_score"`
 }
 
-// GetSpendingPatterns analyzes user spending patterns without generating predictions
-func GetSpendingPatterns(c *gin.Context) {
-	// Step 1: Authenticate
+// CreateAnomalyWebhook registers an endpoint to receive HMAC-signed POSTs
+// (see services/anomaly.NotifyAnomalies) whenever a detected anomaly meets
+// MinScore. The generated secret is returned exactly once, in the create
+// response - models.AnomalyWebhook never serializes it back afterward.
+func CreateAnomalyWebhook(c *gin.Context) {
 	claims, exists := c.Get("user")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
-
 	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
 
-	// Step 2: Parse query parameters
-	historicalMonths := 12
-	if monthsStr := c.Query("historical_months"); monthsStr != "" {
-		if months, err := strconv.Atoi(monthsStr); err == nil && months >= 1 && months <= 36 {
-			historicalMonths = months
-		}
+	var input anomalyWebhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-
-	// Step 3: Prepare request to AI service
-	aiRequest := map[string]interface{}{
-		"user_id":           userID,
-		"historical_months": historicalMonths,
+	if strings.TrimSpace(input.URL) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url is required"})
+		return
+	}
+	minScore := input.MinScore
+	if minScore <= 0 {
+		minScore = anomaly.DefaultThreshold
 	}
 
-	jsonData, err := json.Marshal(aiRequest)
+	secret, err := generateWebhookSecret()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to prepare AI request"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate webhook secret"})
 		return
 	}
 
-	// Step 4: Call AI service
-	aiServiceURL := getAIServiceURL() + "/analyze-patterns"
+	webhook := models.AnomalyWebhook{
+		UserID:   userID,
+		URL:      input.URL,
+		Secret:   secret,
+		MinScore: minScore,
+	}
+	if err := db.DB.Create(&webhook).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create anomaly webhook"})
+		return
+	}
 
-	resp, err := http.Post(aiServiceURL, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error":   "AI service unavailable",
-			"details": err.Error(),
-		})
+	c.JSON(http.StatusCreated, gin.H{
+		"id":         webhook.ID,
+		"url":        webhook.URL,
+		"min_score":  webhook.MinScore,
+		"secret":     secret,
+		"created_at": webhook.CreatedAt,
+	})
+}
+
+// DeleteAnomalyWebhook removes one of the authenticated user's registered
+// anomaly webhooks.
+func DeleteAnomalyWebhook(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
-	defer resp.Body.Close()
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
 
-	// Step 5: Parse and return response
-	body, err := io.ReadAll(resp.Body)
+	webhookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read AI response"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
 		return
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		var errorResp map[string]interface{}
-		if err := json.Unmarshal(body, &errorResp); err == nil {
-			c.JSON(resp.StatusCode, errorResp)
-		} else {
-			c.JSON(resp.StatusCode, gin.H{"error": "AI service error"})
-		}
+	result := db.DB.Where("id = ? AND user_id = ?", webhookID, userID).Delete(&models.AnomalyWebhook{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete anomaly webhook"})
 		return
 	}
-
-	var response map[string]interface{}
-	if err := json.Unmarshal(body, &response); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse AI response"})
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "anomaly webhook not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, gin.H{"message": "anomaly webhook deleted successfully"})
 }
 
-// getAIServiceURL returns the AI service URL from environment variables
-func getAIServiceURL() string {
-	aiServiceHost := os.Getenv("AI_SERVICE_HOST")
-	if aiServiceHost == "" {
-		aiServiceHost = "ai-service" // Default Docker service name
+// generateWebhookSecret returns a random 32-byte hex-encoded secret for
+// signing AnomalyWebhook deliveries.
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
 	}
+	return hex.EncodeToString(buf), nil
+}
 
-	aiServicePort := os.Getenv("AI_SERVICE_PORT")
-	if aiServicePort == "" {
-		aiServicePort = "5001" // Default port
+// checkTransactionForAnomalies re-runs anomaly detection for userID after a
+// new transaction is ingested and, if the new transaction itself comes back
+// flagged, hands it to anomaly.NotifyAnomalies for webhook delivery. Called
+// from CreateTransaction/BulkCreateTransactions the same way
+// ApplyCategoryRuleToTransaction is - best-effort, it never blocks or fails
+// the ingestion request.
+func checkTransactionForAnomalies(ctx context.Context, userID uint, transactionID uint) {
+	anomalies, err := anomaly.Detect(ctx, userID, 12, anomaly.DefaultThreshold)
+	if err != nil {
+		return
 	}
 
-	return fmt.Sprintf("http://%s:%s", aiServiceHost, aiServicePort)
+	var flagged []anomaly.Anomaly
+	for _, a := range anomalies {
+		if a.TransactionID == transactionID {
+			flagged = append(flagged, a)
+		}
+	}
+	anomaly.NotifyAnomalies(userID, flagged)
 }
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers/aidriver"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/ai"
+)
+
+// driverAdapter implements ai.Predictor and ai.PatternsAnalyzer on top of an
+// aidriver.Driver, so main.go can point AIPredictor/AIPatternsAnalyzer at
+// whichever backend AI_DRIVER selects without GetBudgetPrediction/
+// GetSpendingPatterns needing to know a driver registry exists. Defined in
+// controllers rather than aidriver to avoid an import cycle: this adapter
+// converts between the aidriver.* and ai.* request/response types, so it
+// needs both packages, and aidriver's own "local" driver already imports
+// services/ai.
+type driverAdapter struct {
+	defaultDriver aidriver.Driver
+}
+
+// NewDriverAdapter wraps defaultDriver, the driver main.go built from
+// AI_DRIVER at startup, as an ai.Predictor/ai.PatternsAnalyzer.
+func NewDriverAdapter(defaultDriver aidriver.Driver) *driverAdapter {
+	return &driverAdapter{defaultDriver: defaultDriver}
+}
+
+// driverFor resolves the aidriver.Driver to use for userID: a
+// models.UserAISetting override if that user has set both Driver and
+// APIKey, otherwise a.defaultDriver. An override that fails to construct
+// (unknown driver name, etc.) falls back to the default rather than
+// failing the request.
+func (a *driverAdapter) driverFor(userID uint) aidriver.Driver {
+	var setting models.UserAISetting
+	if err := db.DB.Where("user_id = ?", userID).First(&setting).Error; err != nil {
+		return a.defaultDriver
+	}
+	if setting.Driver == nil || setting.APIKey == nil {
+		return a.defaultDriver
+	}
+
+	d, err := aidriver.New(*setting.Driver, aidriver.Config{APIKey: *setting.APIKey})
+	if err != nil {
+		return a.defaultDriver
+	}
+	return d
+}
+
+// Predict satisfies ai.Predictor.
+func (a *driverAdapter) Predict(ctx context.Context, req ai.PredictRequest) (ai.PredictResponse, error) {
+	resp, err := a.driverFor(req.UserID).PredictBudget(ctx, aidriver.PredictRequest{
+		UserID:           req.UserID,
+		TargetPeriod:     req.TargetPeriod,
+		HistoricalMonths: req.HistoricalMonths,
+	})
+	if err != nil {
+		return ai.PredictResponse{}, err
+	}
+
+	predictions := make([]ai.BudgetPrediction, 0, len(resp.Predictions))
+	for _, p := range resp.Predictions {
+		predictions = append(predictions, ai.BudgetPrediction{
+			CategoryID:      p.CategoryID,
+			CategoryName:    p.CategoryName,
+			PredictedAmount: p.PredictedAmount,
+			ConfidenceScore: p.ConfidenceScore,
+			HistoricalAvg:   p.HistoricalAvg,
+			TrendDirection:  p.TrendDirection,
+			Reasoning:       p.Reasoning,
+		})
+	}
+
+	return ai.PredictResponse{
+		Predictions:          predictions,
+		TargetPeriod:         resp.TargetPeriod,
+		UserID:               resp.UserID,
+		HistoricalDataPoints: resp.HistoricalDataPoints,
+		Message:              resp.Message,
+		GeneratedAt:          time.Now().UTC(),
+	}, nil
+}
+
+// AnalyzePatterns satisfies ai.PatternsAnalyzer.
+func (a *driverAdapter) AnalyzePatterns(ctx context.Context, req ai.PatternsRequest) (ai.PatternsResponse, error) {
+	resp, err := a.driverFor(req.UserID).AnalyzePatterns(ctx, aidriver.PatternsRequest{
+		UserID:           req.UserID,
+		HistoricalMonths: req.HistoricalMonths,
+	})
+	if err != nil {
+		return ai.PatternsResponse{}, err
+	}
+
+	return ai.PatternsResponse{
+		UserID: resp.UserID,
+		Patterns: ai.Patterns{
+			SpendingVelocity:    resp.Patterns.SpendingVelocity,
+			CategoryConsistency: resp.Patterns.CategoryConsistency,
+			SeasonalTrends: ai.SeasonalTrends{
+				HighestMonth: resp.Patterns.SeasonalHighestMonth,
+				LowestMonth:  resp.Patterns.SeasonalLowestMonth,
+			},
+			WeekendVsWeekday: ai.WeekendVsWeekday{
+				WeekendRatio: resp.Patterns.WeekendRatio,
+				WeekdayRatio: resp.Patterns.WeekdayRatio,
+			},
+		},
+		Insights:        resp.Insights,
+		Recommendations: resp.Recommendations,
+		AnalyzedPeriod:  resp.AnalyzedPeriod,
+		ConfidenceScore: resp.ConfidenceScore,
+	}, nil
+}
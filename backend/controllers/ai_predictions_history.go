@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/ai"
+	"Personal-Finance-Tracker-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// predictionModelVersion identifies which driver produced a PredictResponse,
+// stored on every models.BudgetPrediction row so GetPredictionAccuracy can
+// eventually be broken down by model if AI_DRIVER changes over time.
+func predictionModelVersion() string {
+	if driver := os.Getenv("AI_DRIVER"); driver != "" {
+		return driver
+	}
+	return "local"
+}
+
+// persistPredictions stores one models.BudgetPrediction row per category in
+// resp, so GetPredictionHistory/GetPredictionAccuracy have something to
+// read once the target month's transactions land. A write failure is
+// logged and otherwise ignored, the same way a failed audit write never
+// fails the request it's auditing (see bank_webhook.go) - predictWithResilience
+// has already served its caller a response by the time this runs.
+func persistPredictions(ctx context.Context, resp ai.PredictResponse) {
+	if len(resp.Predictions) == 0 {
+		return
+	}
+
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		utils.Logger.Warn("persist predictions: marshal raw response failed", zap.Error(err))
+		return
+	}
+	var rawJSON models.JSONB
+	if err := json.Unmarshal(raw, &rawJSON); err != nil {
+		utils.Logger.Warn("persist predictions: unmarshal raw response failed", zap.Error(err))
+		return
+	}
+
+	modelVersion := predictionModelVersion()
+	rows := make([]models.BudgetPrediction, 0, len(resp.Predictions))
+	for _, prediction := range resp.Predictions {
+		rows = append(rows, models.BudgetPrediction{
+			UserID:          resp.UserID,
+			CategoryID:      prediction.CategoryID,
+			TargetMonth:     resp.TargetPeriod.Month,
+			TargetYear:      resp.TargetPeriod.Year,
+			PredictedCents:  prediction.PredictedAmount.Cents(),
+			ConfidenceScore: prediction.ConfidenceScore,
+			ModelVersion:    modelVersion,
+			GeneratedAt:     resp.GeneratedAt,
+			RawResponse:     rawJSON,
+		})
+	}
+
+	if err := db.DB.WithContext(ctx).Create(&rows).Error; err != nil {
+		utils.Logger.Warn("persist predictions: insert failed", zap.Error(err))
+	}
+}
+
+// GetPredictionHistory returns the caller's stored budget predictions, most
+// recent target month first, optionally filtered to one category_id - the
+// raw material GetPredictionAccuracy aggregates, exposed directly so the
+// dashboard can plot a model-vs-actual chart per category.
+func GetPredictionHistory(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	query := db.DB.Where("user_id = ?", userID)
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		categoryID, err := strconv.ParseUint(categoryIDStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id"})
+			return
+		}
+		query = query.Where("category_id = ?", uint(categoryID))
+	}
+
+	var predictions []models.BudgetPrediction
+	if err := query.Order("target_year DESC, target_month DESC, created_at DESC").Find(&predictions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch prediction history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"predictions": predictions})
+}
+
+// predictionAccuracyRow is GetPredictionAccuracy's per-category result: MAPE
+// (mean absolute percentage error) and RMSE summarize error magnitude,
+// BiasCents shows whether the model runs systematically over (positive) or
+// under (negative) the category's actual spend.
+type predictionAccuracyRow struct {
+	CategoryID   uint    `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Samples      int     `json:"samples"`
+	MAPEPercent  float64 `json:"mape_percent"`
+	RMSECents    float64 `json:"rmse_cents"`
+	BiasCents    float64 `json:"bias_cents"`
+}
+
+// GetPredictionAccuracy joins the last `months` months of stored
+// models.BudgetPrediction rows against what the user actually spent in the
+// same category/target month, and reports MAPE, RMSE, and bias per
+// category - the feedback signal needed to tell whether the model is
+// systematically over- or under-predicting, and eventually to send back to
+// AI_DRIVER for retraining. A prediction whose target month hasn't
+// realized any matching transactions yet is skipped rather than treated as
+// a zero-actual outlier.
+func GetPredictionAccuracy(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	months := 6
+	if monthsStr := c.Query("months"); monthsStr != "" {
+		if parsed, err := strconv.Atoi(monthsStr); err == nil && parsed >= 1 && parsed <= 36 {
+			months = parsed
+		}
+	}
+	since := time.Now().AddDate(0, -months, 0)
+
+	var predictions []models.BudgetPrediction
+	if err := db.DB.Where("user_id = ? AND created_at >= ?", userID, since).Find(&predictions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch stored predictions"})
+		return
+	}
+
+	type actualRow struct {
+		CategoryID uint
+		Month      string
+		Total      float64
+	}
+	var actuals []actualRow
+	db.DB.Table("transactions").
+		Select("transactions.category_id as category_id, "+aiCacheMonthGroupExpr("transactions.txn_date")+" as month, SUM(ABS(transactions.amount)) as total").
+		Where("transactions.user_id = ? AND transactions.category_id IS NOT NULL AND transactions.txn_date >= ? AND transactions.amount < 0", userID, since).
+		Group("transactions.category_id, month").
+		Scan(&actuals)
+
+	actualCentsByKey := make(map[string]int64, len(actuals))
+	for _, row := range actuals {
+		actualCentsByKey[fmt.Sprintf("%d|%s", row.CategoryID, row.Month)] = int64(math.Round(row.Total * 100))
+	}
+
+	type accumulator struct {
+		samples  int
+		sumAPE   float64
+		sumSqErr float64
+		sumBias  float64
+	}
+	byCategory := map[uint]*accumulator{}
+	var categoryIDs []uint
+	for _, prediction := range predictions {
+		month := fmt.Sprintf("%04d-%02d", prediction.TargetYear, prediction.TargetMonth)
+		actualCents, ok := actualCentsByKey[fmt.Sprintf("%d|%s", prediction.CategoryID, month)]
+		if !ok {
+			continue
+		}
+
+		acc, exists := byCategory[prediction.CategoryID]
+		if !exists {
+			acc = &accumulator{}
+			byCategory[prediction.CategoryID] = acc
+			categoryIDs = append(categoryIDs, prediction.CategoryID)
+		}
+
+		diff := float64(prediction.PredictedCents - actualCents)
+		acc.samples++
+		acc.sumSqErr += diff * diff
+		acc.sumBias += diff
+		if actualCents != 0 {
+			acc.sumAPE += math.Abs(diff) / math.Abs(float64(actualCents))
+		}
+	}
+	sort.Slice(categoryIDs, func(i, j int) bool { return categoryIDs[i] < categoryIDs[j] })
+
+	var categories []models.Category
+	db.DB.Where("id IN ?", categoryIDs).Find(&categories)
+	categoryNames := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	accuracy := make([]predictionAccuracyRow, 0, len(categoryIDs))
+	for _, categoryID := range categoryIDs {
+		acc := byCategory[categoryID]
+		accuracy = append(accuracy, predictionAccuracyRow{
+			CategoryID:   categoryID,
+			CategoryName: categoryNames[categoryID],
+			Samples:      acc.samples,
+			MAPEPercent:  math.Round(acc.sumAPE/float64(acc.samples)*10000) / 100,
+			RMSECents:    math.Round(math.Sqrt(acc.sumSqErr/float64(acc.samples))*100) / 100,
+			BiasCents:    math.Round(acc.sumBias/float64(acc.samples)*100) / 100,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"months": months, "accuracy": accuracy})
+}
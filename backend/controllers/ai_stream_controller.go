@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"Personal-Finance-Tracker-backend/services/ai"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// sseRetryMillis is the retry: field sent with every event, telling a
+// disconnected EventSource how long to wait before reconnecting.
+const sseRetryMillis = 3000
+
+// sseEvent is one line pair written by GetSpendingPatternsStream - event
+// name plus its JSON-encoded data payload.
+type sseEvent struct {
+	name string
+	data interface{}
+}
+
+// GetSpendingPatternsStream computes the same ai.Service.AnalyzePatterns
+// result as GetSpendingPatterns, but writes it as Server-Sent Events as
+// each piece becomes available - a "partial" event with the aggregate
+// patterns, one "insight" event per insight, one "recommendation" event
+// per recommendation, and a final "done" event with the full response -
+// instead of making the dashboard wait on one large buffered JSON body.
+// Each event carries an id: (so a reconnecting EventSource can resume with
+// Last-Event-ID) and a retry: hint.
+func GetSpendingPatternsStream(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	historicalMonths := patternsHistoricalMonths(c)
+
+	resp, err := AIPatternsAnalyzer().AnalyzePatterns(c.Request.Context(), ai.PatternsRequest{
+		UserID:           userID,
+		HistoricalMonths: historicalMonths,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	events := []sseEvent{
+		{name: "partial", data: gin.H{"patterns": patternsJSON(resp.Patterns)}},
+	}
+	for _, insight := range resp.Insights {
+		events = append(events, sseEvent{name: "insight", data: gin.H{"text": insight}})
+	}
+	for _, recommendation := range resp.Recommendations {
+		events = append(events, sseEvent{name: "recommendation", data: gin.H{"text": recommendation}})
+	}
+	events = append(events, sseEvent{name: "done", data: gin.H{
+		"user_id":          resp.UserID,
+		"patterns":         patternsJSON(resp.Patterns),
+		"insights":         resp.Insights,
+		"recommendations":  resp.Recommendations,
+		"analyzed_period":  resp.AnalyzedPeriod,
+		"confidence_score": resp.ConfidenceScore,
+	}})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	i := 0
+	c.Stream(func(w io.Writer) bool {
+		writeSSEEvent(w, i+1, events[i])
+		i++
+		return i < len(events)
+	})
+}
+
+// GetBudgetPredictionStream computes the same predictWithResilience result
+// as GetBudgetPrediction, but writes it as Server-Sent Events - one
+// "prediction" event per category, in the order predictWithResilience
+// returned them, followed by a final "done" event carrying the full
+// response - so the dashboard can render predictions incrementally instead
+// of waiting on one large buffered JSON body. The underlying
+// ai.Predictor/aidriver.Driver call is still a single batch request
+// (nothing upstream of this handler streams incrementally yet); this
+// endpoint streams the rendering of an already-computed result, the same
+// way GetSpendingPatternsStream does for AnalyzePatterns.
+func GetBudgetPredictionStream(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	targetPeriod, historicalMonths := predictionQueryParams(c)
+	refresh := c.Query("refresh") == "true"
+
+	resp, stale := predictWithResilience(c.Request.Context(), ai.PredictRequest{
+		UserID:           userID,
+		TargetPeriod:     targetPeriod,
+		HistoricalMonths: historicalMonths,
+	}, refresh)
+
+	events := make([]sseEvent, 0, len(resp.Predictions)+1)
+	for _, prediction := range resp.Predictions {
+		events = append(events, sseEvent{name: "prediction", data: prediction})
+	}
+	events = append(events, sseEvent{name: "done", data: gin.H{
+		"predictions":            resp.Predictions,
+		"target_period":          resp.TargetPeriod,
+		"user_id":                resp.UserID,
+		"historical_data_points": resp.HistoricalDataPoints,
+		"message":                resp.Message,
+		"generated_at":           resp.GeneratedAt,
+		"stale":                  stale,
+	}})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	i := 0
+	c.Stream(func(w io.Writer) bool {
+		writeSSEEvent(w, i+1, events[i])
+		i++
+		return i < len(events)
+	})
+}
+
+// writeSSEEvent writes one SSE frame (id:/retry:/event:/data:) to w.
+func writeSSEEvent(w io.Writer, id int, event sseEvent) {
+	payload, err := json.Marshal(event.data)
+	if err != nil {
+		payload = []byte(`{}`)
+	}
+	fmt.Fprintf(w, "id: %d\nretry: %d\nevent: %s\ndata: %s\n\n", id, sseRetryMillis, event.name, payload)
+}
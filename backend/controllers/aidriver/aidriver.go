@@ -0,0 +1,124 @@
+// Package aidriver is a registry of pluggable AI backends for budget
+// prediction and spending-pattern analysis, mirroring the way
+// migrations.register lets each migration file add itself to a shared
+// list. It replaces the old AI_SERVICE_HOST/AI_SERVICE_PORT scheme (a
+// single hard-coded Python sidecar) with a named set of drivers selected
+// at startup via AI_DRIVER, plus an optional per-user override (see
+// models.UserAISetting) so an end user can bring their own API key.
+package aidriver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"Personal-Finance-Tracker-backend/pkg/money"
+	"Personal-Finance-Tracker-backend/pkg/timeutil"
+)
+
+// Config configures a Driver instance - an API key/base URL pair is enough
+// for every built-in driver (driver_local.go needs neither).
+type Config struct {
+	APIKey  string
+	BaseURL string
+}
+
+// PredictRequest is Driver.PredictBudget's input, field-for-field the same
+// as ai.PredictRequest - kept as its own type so this package has no
+// dependency on services/ai and the two can evolve independently.
+type PredictRequest struct {
+	UserID           uint
+	TargetPeriod     timeutil.YearMonth
+	HistoricalMonths int
+}
+
+// BudgetPrediction is one category's forecast, mirroring ai.BudgetPrediction.
+type BudgetPrediction struct {
+	CategoryID      uint
+	CategoryName    string
+	PredictedAmount money.Money
+	ConfidenceScore float64
+	HistoricalAvg   money.Money
+	TrendDirection  string
+	Reasoning       string
+}
+
+// PredictResponse is Driver.PredictBudget's output, mirroring
+// ai.PredictResponse.
+type PredictResponse struct {
+	Predictions          []BudgetPrediction
+	TargetPeriod         timeutil.YearMonth
+	UserID               uint
+	HistoricalDataPoints int
+	Message              string
+}
+
+// PatternsRequest is Driver.AnalyzePatterns' input, mirroring
+// ai.PatternsRequest.
+type PatternsRequest struct {
+	UserID           uint
+	HistoricalMonths int
+}
+
+// Patterns mirrors ai.Patterns.
+type Patterns struct {
+	SpendingVelocity     string
+	CategoryConsistency  float64
+	SeasonalHighestMonth string
+	SeasonalLowestMonth  string
+	WeekendRatio         float64
+	WeekdayRatio         float64
+}
+
+// PatternsResponse is Driver.AnalyzePatterns' output, mirroring
+// ai.PatternsResponse.
+type PatternsResponse struct {
+	UserID          uint
+	Patterns        Patterns
+	Insights        []string
+	Recommendations []string
+	AnalyzedPeriod  string
+	ConfidenceScore float64
+}
+
+// Driver is one AI backend capable of both budget prediction and
+// spending-pattern analysis. controllers.ai_driver_adapter.go adapts a
+// Driver into the ai.Predictor/ai.PatternsAnalyzer interfaces the REST
+// handlers already depend on.
+type Driver interface {
+	PredictBudget(ctx context.Context, req PredictRequest) (PredictResponse, error)
+	AnalyzePatterns(ctx context.Context, req PatternsRequest) (PatternsResponse, error)
+}
+
+// Factory constructs a Driver from Config - called once per New, so a
+// per-user override (a different APIKey) gets its own Driver instance
+// rather than sharing one built at startup.
+type Factory func(Config) (Driver, error)
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+// Register adds a named driver factory. Built-in drivers call this from an
+// init() in their own file (driver_local.go, driver_openai.go,
+// driver_ollama.go), the same way each migrations/NNNN_*.go file registers
+// itself. Registering the same name twice overwrites the previous factory,
+// which lets tests install a fake driver under a built-in name.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories[name] = factory
+}
+
+// New constructs the named driver with cfg. Returns an error if name was
+// never registered.
+func New(name string, cfg Config) (Driver, error) {
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("aidriver: unknown driver %q", name)
+	}
+	return factory(cfg)
+}
@@ -0,0 +1,86 @@
+package aidriver
+
+import (
+	"context"
+
+	"Personal-Finance-Tracker-backend/services/ai"
+)
+
+func init() {
+	factory := func(Config) (Driver, error) {
+		return localDriver{service: ai.NewService()}, nil
+	}
+	Register("local", factory)
+	// "sidecar" is an alias for callers still setting AI_DRIVER=sidecar -
+	// see localDriver's doc comment for why it no longer shells out anywhere.
+	Register("sidecar", factory)
+}
+
+// localDriver runs prediction and pattern analysis in-process against this
+// server's own database via services/ai, with no network call out at all.
+// It is the default driver and the honest replacement for the old
+// "python-sidecar" name: chunk10-1 removed the Python sidecar process this
+// driver used to proxy to, folding its logic into services/ai, so there is
+// no longer a sidecar to select - AI_DRIVER=sidecar now resolves to this
+// same in-process implementation.
+type localDriver struct {
+	service *ai.Service
+}
+
+func (d localDriver) PredictBudget(ctx context.Context, req PredictRequest) (PredictResponse, error) {
+	resp, err := d.service.Predict(ctx, ai.PredictRequest{
+		UserID:           req.UserID,
+		TargetPeriod:     req.TargetPeriod,
+		HistoricalMonths: req.HistoricalMonths,
+	})
+	if err != nil {
+		return PredictResponse{}, err
+	}
+
+	predictions := make([]BudgetPrediction, 0, len(resp.Predictions))
+	for _, p := range resp.Predictions {
+		predictions = append(predictions, BudgetPrediction{
+			CategoryID:      p.CategoryID,
+			CategoryName:    p.CategoryName,
+			PredictedAmount: p.PredictedAmount,
+			ConfidenceScore: p.ConfidenceScore,
+			HistoricalAvg:   p.HistoricalAvg,
+			TrendDirection:  p.TrendDirection,
+			Reasoning:       p.Reasoning,
+		})
+	}
+
+	return PredictResponse{
+		Predictions:          predictions,
+		TargetPeriod:         resp.TargetPeriod,
+		UserID:               resp.UserID,
+		HistoricalDataPoints: resp.HistoricalDataPoints,
+		Message:              resp.Message,
+	}, nil
+}
+
+func (d localDriver) AnalyzePatterns(ctx context.Context, req PatternsRequest) (PatternsResponse, error) {
+	resp, err := d.service.AnalyzePatterns(ctx, ai.PatternsRequest{
+		UserID:           req.UserID,
+		HistoricalMonths: req.HistoricalMonths,
+	})
+	if err != nil {
+		return PatternsResponse{}, err
+	}
+
+	return PatternsResponse{
+		UserID: resp.UserID,
+		Patterns: Patterns{
+			SpendingVelocity:     resp.Patterns.SpendingVelocity,
+			CategoryConsistency:  resp.Patterns.CategoryConsistency,
+			SeasonalHighestMonth: resp.Patterns.SeasonalTrends.HighestMonth,
+			SeasonalLowestMonth:  resp.Patterns.SeasonalTrends.LowestMonth,
+			WeekendRatio:         resp.Patterns.WeekendVsWeekday.WeekendRatio,
+			WeekdayRatio:         resp.Patterns.WeekendVsWeekday.WeekdayRatio,
+		},
+		Insights:        resp.Insights,
+		Recommendations: resp.Recommendations,
+		AnalyzedPeriod:  resp.AnalyzedPeriod,
+		ConfidenceScore: resp.ConfidenceScore,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package aidriver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const defaultOllamaBaseURL = "http://localhost:11434"
+
+func init() {
+	Register("ollama", func(cfg Config) (Driver, error) {
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = defaultOllamaBaseURL
+		}
+		return &ollamaDriver{baseURL: baseURL}, nil
+	})
+}
+
+// ollamaDriver calls a local llama.cpp/ollama server's /api/generate
+// endpoint with format "json", so the model's entire response body is
+// already the JSON this driver unmarshals into PredictResponse/
+// PatternsResponse - no separate tool-calling step, since ollama's function
+// calling support varies by model. Requests go through the package's
+// sharedHTTPClient (http_client.go); unlike openAIDriver, ollama has no
+// per-instance API key of its own, so AI_SERVICE_API_KEY is sent as a
+// Bearer token when set - useful when baseURL points at a gateway sitting
+// in front of a shared ollama instance rather than localhost.
+type ollamaDriver struct {
+	baseURL string
+}
+
+type ollamaGenerateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Format string `json:"format"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaGenerateResponse struct {
+	Response string `json:"response"`
+}
+
+const ollamaModel = "llama3"
+
+func (d *ollamaDriver) PredictBudget(ctx context.Context, req PredictRequest) (PredictResponse, error) {
+	prompt := fmt.Sprintf(
+		"Forecast next month's per-category spend for user %d, target %s, using their last %d months of transaction history. "+
+			"Respond with ONLY a JSON object matching this shape: "+
+			`{"Predictions":[{"CategoryID":0,"CategoryName":"","PredictedAmount":{"cents":0},"ConfidenceScore":0,"HistoricalAvg":{"cents":0},"TrendDirection":"","Reasoning":""}],"TargetPeriod":"YYYY-MM","UserID":0,"HistoricalDataPoints":0,"Message":""}`,
+		req.UserID, req.TargetPeriod, req.HistoricalMonths,
+	)
+	var resp PredictResponse
+	if err := d.generate(ctx, prompt, &resp); err != nil {
+		return PredictResponse{}, err
+	}
+	return resp, nil
+}
+
+func (d *ollamaDriver) AnalyzePatterns(ctx context.Context, req PatternsRequest) (PatternsResponse, error) {
+	prompt := fmt.Sprintf(
+		"Analyze user %d's spending behavior over their last %d months of transaction history. "+
+			"Respond with ONLY a JSON object matching this shape: "+
+			`{"UserID":0,"Patterns":{"SpendingVelocity":"","CategoryConsistency":0,"SeasonalHighestMonth":"","SeasonalLowestMonth":"","WeekendRatio":0,"WeekdayRatio":0},"Insights":[],"Recommendations":[],"AnalyzedPeriod":"","ConfidenceScore":0}`,
+		req.UserID, req.HistoricalMonths,
+	)
+	var resp PatternsResponse
+	if err := d.generate(ctx, prompt, &resp); err != nil {
+		return PatternsResponse{}, err
+	}
+	return resp, nil
+}
+
+func (d *ollamaDriver) generate(ctx context.Context, prompt string, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, sharedHTTPClient.Timeout)
+	defer cancel()
+
+	reqBody, err := json.Marshal(ollamaGenerateRequest{
+		Model:  ollamaModel,
+		Prompt: prompt,
+		Format: "json",
+		Stream: false,
+	})
+	if err != nil {
+		return fmt.Errorf("aidriver: marshal ollama request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, d.baseURL+"/api/generate", bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("aidriver: build ollama request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	aiServiceAuthHeader(httpReq)
+
+	httpResp, err := doWithRetry(sharedHTTPClient, httpReq)
+	if err != nil {
+		return fmt.Errorf("aidriver: ollama request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= 300 {
+		return &UpstreamStatusError{StatusCode: httpResp.StatusCode}
+	}
+
+	var genResp ollamaGenerateResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&genResp); err != nil {
+		return fmt.Errorf("aidriver: decode ollama response: %w", err)
+	}
+	if err := json.Unmarshal([]byte(genResp.Response), out); err != nil {
+		return fmt.Errorf("aidriver: unmarshal ollama generated JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package aidriver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+func init() {
+	Register("openai", func(cfg Config) (Driver, error) {
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("aidriver: openai driver requires an API key")
+		}
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = defaultOpenAIBaseURL
+		}
+		return &openAIDriver{
+			apiKey:  cfg.APIKey,
+			baseURL: baseURL,
+		}, nil
+	})
+}
+
+// openAIDriver forces the chat-completions API to call a single tool whose
+// parameters schema matches PredictResponse/PatternsResponse, so the result
+// can be unmarshaled straight into those structs instead of parsing free
+// text. Requests go through the package's sharedHTTPClient (http_client.go)
+// rather than a client of its own, so timeout/retry/proxy behavior is
+// consistent across every HTTP-based driver.
+type openAIDriver struct {
+	apiKey  string
+	baseURL string
+}
+
+type openAIChatRequest struct {
+	Model      string           `json:"model"`
+	Messages   []openAIMessage  `json:"messages"`
+	Tools      []openAITool     `json:"tools"`
+	ToolChoice openAIToolChoice `json:"tool_choice"`
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+type openAIToolChoice struct {
+	Type     string                   `json:"type"`
+	Function openAIToolChoiceFunction `json:"function"`
+}
+
+type openAIToolChoiceFunction struct {
+	Name string `json:"name"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message struct {
+			ToolCalls []struct {
+				Function struct {
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+const predictBudgetToolName = "submit_budget_prediction"
+const analyzePatternsToolName = "submit_spending_patterns"
+
+func (d *openAIDriver) PredictBudget(ctx context.Context, req PredictRequest) (PredictResponse, error) {
+	var resp PredictResponse
+	prompt := fmt.Sprintf(
+		"Forecast next month's per-category spend for user %d, target %s, using their last %d months of transaction history. Call %s with the result.",
+		req.UserID, req.TargetPeriod, req.HistoricalMonths, predictBudgetToolName,
+	)
+	if err := d.callTool(ctx, prompt, openAITool{
+		Type: "function",
+		Function: openAIFunction{
+			Name:        predictBudgetToolName,
+			Description: "Report a budget prediction matching aidriver.PredictResponse.",
+			Parameters:  predictBudgetSchema,
+		},
+	}, &resp); err != nil {
+		return PredictResponse{}, err
+	}
+	return resp, nil
+}
+
+func (d *openAIDriver) AnalyzePatterns(ctx context.Context, req PatternsRequest) (PatternsResponse, error) {
+	var resp PatternsResponse
+	prompt := fmt.Sprintf(
+		"Analyze user %d's spending behavior over their last %d months of transaction history. Call %s with the result.",
+		req.UserID, req.HistoricalMonths, analyzePatternsToolName,
+	)
+	if err := d.callTool(ctx, prompt, openAITool{
+		Type: "function",
+		Function: openAIFunction{
+			Name:        analyzePatternsToolName,
+			Description: "Report spending patterns matching aidriver.PatternsResponse.",
+			Parameters:  analyzePatternsSchema,
+		},
+	}, &resp); err != nil {
+		return PatternsResponse{}, err
+	}
+	return resp, nil
+}
+
+// callTool sends prompt as a single user message, forces the model to call
+// tool, and unmarshals its arguments JSON into out.
+func (d *openAIDriver) callTool(ctx context.Context, prompt string, tool openAITool, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, sharedHTTPClient.Timeout)
+	defer cancel()
+
+	reqBody := openAIChatRequest{
+		Model:    "gpt-4o-mini",
+		Messages: []openAIMessage{{Role: "user", Content: prompt}},
+		Tools:    []openAITool{tool},
+		ToolChoice: openAIToolChoice{
+			Type:     "function",
+			Function: openAIToolChoiceFunction{Name: tool.Function.Name},
+		},
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("aidriver: marshal openai request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, d.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("aidriver: build openai request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+d.apiKey)
+
+	httpResp, err := doWithRetry(sharedHTTPClient, httpReq)
+	if err != nil {
+		return fmt.Errorf("aidriver: openai request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= 300 {
+		return &UpstreamStatusError{StatusCode: httpResp.StatusCode}
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&chatResp); err != nil {
+		return fmt.Errorf("aidriver: decode openai response: %w", err)
+	}
+	if len(chatResp.Choices) == 0 || len(chatResp.Choices[0].Message.ToolCalls) == 0 {
+		return fmt.Errorf("aidriver: openai response had no tool call")
+	}
+
+	args := chatResp.Choices[0].Message.ToolCalls[0].Function.Arguments
+	if err := json.Unmarshal([]byte(args), out); err != nil {
+		return fmt.Errorf("aidriver: unmarshal tool call arguments: %w", err)
+	}
+	return nil
+}
+
+var predictBudgetSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"Predictions": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"CategoryID":      map[string]interface{}{"type": "integer"},
+					"CategoryName":    map[string]interface{}{"type": "string"},
+					"PredictedAmount": moneySchema,
+					"ConfidenceScore": map[string]interface{}{"type": "number"},
+					"HistoricalAvg":   moneySchema,
+					"TrendDirection":  map[string]interface{}{"type": "string"},
+					"Reasoning":       map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+		"TargetPeriod":         map[string]interface{}{"type": "string", "description": "YYYY-MM"},
+		"UserID":               map[string]interface{}{"type": "integer"},
+		"HistoricalDataPoints": map[string]interface{}{"type": "integer"},
+		"Message":              map[string]interface{}{"type": "string"},
+	},
+	"required": []string{"Predictions", "TargetPeriod", "UserID"},
+}
+
+// moneySchema describes money.Money's wire format - the JSON shape
+// BudgetPrediction's PredictedAmount/HistoricalAvg fields unmarshal from -
+// so the model returns cents directly instead of a dollar figure this
+// driver would have to convert.
+var moneySchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"cents": map[string]interface{}{"type": "integer"},
+	},
+	"required": []string{"cents"},
+}
+
+var analyzePatternsSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"UserID": map[string]interface{}{"type": "integer"},
+		"Patterns": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"SpendingVelocity":     map[string]interface{}{"type": "string"},
+				"CategoryConsistency":  map[string]interface{}{"type": "number"},
+				"SeasonalHighestMonth": map[string]interface{}{"type": "string"},
+				"SeasonalLowestMonth":  map[string]interface{}{"type": "string"},
+				"WeekendRatio":         map[string]interface{}{"type": "number"},
+				"WeekdayRatio":         map[string]interface{}{"type": "number"},
+			},
+		},
+		"Insights":        map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		"Recommendations": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		"AnalyzedPeriod":  map[string]interface{}{"type": "string"},
+		"ConfidenceScore": map[string]interface{}{"type": "number"},
+	},
+	"required": []string{"UserID", "Patterns"},
+}
@@ -0,0 +1,166 @@
+package aidriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// defaultAITimeout/defaultAIRetries are AI_SERVICE_TIMEOUT/AI_SERVICE_RETRIES'
+// fallback values when those env vars are unset or invalid.
+const (
+	defaultAITimeout = 10 * time.Second
+	defaultAIRetries = 3
+)
+
+// retryMinBackoff/retryMaxBackoff bound doWithRetry's exponential backoff,
+// the same doubling shape services/reports/chore.retryDelay uses for
+// scheduled-report delivery.
+const (
+	retryMinBackoff = 250 * time.Millisecond
+	retryMaxBackoff = 4 * time.Second
+)
+
+// sharedHTTPClient is the single *http.Client every HTTP-based driver
+// (openAIDriver, ollamaDriver) issues requests through, instead of each
+// constructing its own bare client. Built once at package init from
+// AI_SERVICE_TIMEOUT, with a Transport that honors HTTP_PROXY/HTTPS_PROXY
+// via the standard library's ProxyFromEnvironment.
+var sharedHTTPClient = &http.Client{
+	Timeout:   aiServiceTimeout(),
+	Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+}
+
+func aiServiceTimeout() time.Duration {
+	if raw := os.Getenv("AI_SERVICE_TIMEOUT"); raw != "" {
+		if seconds, err := strconv.Atoi(raw); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultAITimeout
+}
+
+func aiServiceRetryCount() int {
+	if raw := os.Getenv("AI_SERVICE_RETRIES"); raw != "" {
+		if retries, err := strconv.Atoi(raw); err == nil && retries >= 0 {
+			return retries
+		}
+	}
+	return defaultAIRetries
+}
+
+// aiServiceAuthHeader sets Authorization: Bearer $AI_SERVICE_API_KEY on req
+// when that env var is configured, so a driver talking to a self-hosted AI
+// gateway sitting behind auth doesn't need its own per-request key handling
+// the way openAIDriver's own apiKey does for api.openai.com.
+func aiServiceAuthHeader(req *http.Request) {
+	if key := os.Getenv("AI_SERVICE_API_KEY"); key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+}
+
+// retryBackoff doubles from retryMinBackoff on each successive attempt,
+// capped at retryMaxBackoff.
+func retryBackoff(attempt int) time.Duration {
+	delay := retryMinBackoff
+	for i := 1; i < attempt && delay < retryMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxBackoff {
+		delay = retryMaxBackoff
+	}
+	return delay
+}
+
+// UpstreamStatusError is returned when an AI backend answers with a status
+// doWithRetry didn't treat as transient - any non-5xx status of 300 or
+// above. A 5xx that never recovered across retries is reported as
+// ErrUpstreamUnavailable instead. Callers (controllers.GetSpendingPatterns) use
+// StatusCode to proxy the same status back to their own caller instead of
+// flattening every driver error to 500.
+type UpstreamStatusError struct {
+	StatusCode int
+}
+
+func (e *UpstreamStatusError) Error() string {
+	return fmt.Sprintf("aidriver: upstream returned status %d", e.StatusCode)
+}
+
+// ErrUpstreamTimeout/ErrUpstreamUnavailable let controllers.GetSpendingPatterns
+// (and any future caller) distinguish a deadline from a connection failure
+// with errors.Is instead of parsing error strings.
+var (
+	ErrUpstreamTimeout     = errors.New("aidriver: upstream request timed out")
+	ErrUpstreamUnavailable = errors.New("aidriver: upstream unreachable")
+)
+
+// doWithRetry issues req through client, retrying up to AI_SERVICE_RETRIES
+// additional times - with exponential backoff - on a network error or a
+// 5xx response. It never retries a 4xx: that's a request the caller built
+// wrong, not a transient failure. req must have GetBody set (which
+// http.NewRequestWithContext does automatically for bytes.Reader/
+// bytes.Buffer/strings.Reader bodies, the only kinds this package's
+// drivers send) so a retried attempt can resend the same body. The
+// returned error is always one of ErrUpstreamTimeout, ErrUpstreamUnavailable,
+// or an *UpstreamStatusError, so callers never need to inspect anything
+// but those. Reads AI_SERVICE_RETRIES fresh on every call rather than once
+// at package init, so tests can vary it per case.
+func doWithRetry(client *http.Client, req *http.Request) (*http.Response, error) {
+	maxRetries := aiServiceRetryCount()
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-req.Context().Done():
+				return nil, classifyRequestError(req.Context().Err())
+			case <-time.After(retryBackoff(attempt)):
+			}
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= 500 {
+			resp.Body.Close()
+			lastErr = &UpstreamStatusError{StatusCode: resp.StatusCode}
+			continue
+		}
+		return resp, nil
+	}
+	return nil, classifyRequestError(lastErr)
+}
+
+// classifyRequestError turns the last error doWithRetry saw into
+// ErrUpstreamTimeout or ErrUpstreamUnavailable, leaving a 4xx
+// *UpstreamStatusError as-is since that's not a transient failure.
+func classifyRequestError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var statusErr *UpstreamStatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode < 500 {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", ErrUpstreamTimeout, err)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%w: %v", ErrUpstreamTimeout, err)
+	}
+	return fmt.Errorf("%w: %v", ErrUpstreamUnavailable, err)
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuditLogs lists audit records, filterable by actor, action, target type
+// and ID, and creation date range (admin only).
+func GetAuditLogs(c *gin.Context) {
+	query := db.DB.Model(&models.AuditLog{})
+
+	if actor := c.Query("actor"); actor != "" {
+		query = query.Where("actor_username = ?", actor)
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if targetType := c.Query("target_type"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if targetID := c.Query("target_id"); targetID != "" {
+		query = query.Where("target_id = ?", targetID)
+	}
+	if from := c.Query("date_from"); from != "" {
+		if t, err := time.Parse("2006-01-02", from); err == nil {
+			query = query.Where("created_at >= ?", t)
+		}
+	}
+	if to := c.Query("date_to"); to != "" {
+		if t, err := time.Parse("2006-01-02", to); err == nil {
+			query = query.Where("created_at <= ?", t.Add(24*time.Hour))
+		}
+	}
+
+	var logs []models.AuditLog
+	if err := query.Order("id DESC").Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch audit logs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"audit_logs": logs})
+}
+
+// GetAuditLog returns a single audit record by ID (admin only).
+func GetAuditLog(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid audit log ID"})
+		return
+	}
+
+	var log models.AuditLog
+	if err := db.DB.Where("id = ?", id).First(&log).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "audit log not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, log)
+}
+
+// VerifyAuditChain walks the audit log hash chain in order and reports the
+// first record whose PrevHash/Hash no longer matches what it should be,
+// which is the signal that the log has been tampered with.
+func VerifyAuditChain(c *gin.Context) {
+	var logs []models.AuditLog
+	if err := db.DB.Order("id ASC").Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify audit chain"})
+		return
+	}
+
+	prevHash := ""
+	for _, entry := range logs {
+		if entry.PrevHash != prevHash || middleware.ComputeAuditHash(prevHash, entry) != entry.Hash {
+			c.JSON(http.StatusOK, gin.H{
+				"valid":     false,
+				"broken_at": entry.ID,
+				"checked":   len(logs),
+			})
+			return
+		}
+		prevHash = entry.Hash
+	}
+
+	c.JSON(http.StatusOK, gin.H{"valid": true, "checked": len(logs)})
+}
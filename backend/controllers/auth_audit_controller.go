@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuthAuditEvents lists auth audit events, filterable by user_id,
+// event_type, and a created-at date range (admin only).
+func GetAuthAuditEvents(c *gin.Context) {
+	query := db.DB.Model(&models.AuthAuditEvent{})
+
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if eventType := c.Query("event_type"); eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	if from := c.Query("date_from"); from != "" {
+		if t, err := time.Parse("2006-01-02", from); err == nil {
+			query = query.Where("timestamp >= ?", t)
+		}
+	}
+	if to := c.Query("date_to"); to != "" {
+		if t, err := time.Parse("2006-01-02", to); err == nil {
+			query = query.Where("timestamp <= ?", t.Add(24*time.Hour))
+		}
+	}
+
+	var events []models.AuthAuditEvent
+	if err := query.Order("id DESC").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch auth audit events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+// GetAuthAuditEvent returns a single auth audit event by ID (admin only).
+func GetAuthAuditEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid auth audit event ID"})
+		return
+	}
+
+	var event models.AuthAuditEvent
+	if err := db.DB.Where("id = ?", id).First(&event).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "auth audit event not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// VerifyAuthAuditChain walks the auth audit hash chain in order and reports
+// the first event whose PrevHash/Hash no longer matches what it should be.
+func VerifyAuthAuditChain(c *gin.Context) {
+	var events []models.AuthAuditEvent
+	if err := db.DB.Order("id ASC").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify auth audit chain"})
+		return
+	}
+
+	prevHash := ""
+	for _, event := range events {
+		if event.PrevHash != prevHash || middleware.ComputeAuthAuditHash(prevHash, event) != event.Hash {
+			c.JSON(http.StatusOK, gin.H{
+				"valid":     false,
+				"broken_at": event.ID,
+				"checked":   len(events),
+			})
+			return
+		}
+		prevHash = event.Hash
+	}
+
+	c.JSON(http.StatusOK, gin.H{"valid": true, "checked": len(events)})
+}
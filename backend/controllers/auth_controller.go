@@ -1,10 +1,14 @@
 package controllers
 
 import (
+    "Personal-Finance-Tracker-backend/config"
     "Personal-Finance-Tracker-backend/db"
+    "Personal-Finance-Tracker-backend/middleware"
     "Personal-Finance-Tracker-backend/models"
+    "Personal-Finance-Tracker-backend/services/cascade"
     "Personal-Finance-Tracker-backend/utils"
     "net/http"
+    "strconv"
     "time"
 
     "github.com/gin-gonic/gin"
@@ -13,6 +17,11 @@ import (
 )
 
 func Register(c *gin.Context) {
+    if !config.IsEnabled("auth.registration_open", true) {
+        c.JSON(http.StatusForbidden, gin.H{"error": "registration is currently closed"})
+        return
+    }
+
     var input struct {
         Username string `json:"username" binding:"required"`
         Email    string `json:"email" binding:"required,email"`
@@ -51,6 +60,13 @@ func Register(c *gin.Context) {
             zap.String("email", input.Email),
             zap.String("ip", c.ClientIP()),
         )
+        middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+            EventType: "register",
+            Outcome:   "failure",
+            Details:   map[string]interface{}{"username": input.Username, "reason": "duplicate username or email"},
+            IP:        c.ClientIP(),
+            UserAgent: c.Request.UserAgent(),
+        })
         c.JSON(http.StatusBadRequest, gin.H{"error": "Username or Email already exists"})
         return
     }
@@ -62,6 +78,21 @@ func Register(c *gin.Context) {
         zap.String("ip", c.ClientIP()),
     )
 
+    if _, err := SeedDefaultCategories(user.ID, c.Query("locale")); err != nil {
+        utils.Logger.Warn("Failed to seed default categories for new user",
+            zap.Error(err),
+            zap.Uint("user_id", user.ID),
+        )
+    }
+
+    middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+        UserID:    user.ID,
+        EventType: "register",
+        Outcome:   "success",
+        IP:        c.ClientIP(),
+        UserAgent: c.Request.UserAgent(),
+    })
+
     c.JSON(http.StatusCreated, gin.H{"message": "Registration successful"})
 }
 
@@ -79,27 +110,138 @@ func Login(c *gin.Context) {
         return
     }
 
+    if lockedUntil, locked := checkLoginLockout(input.Username); locked {
+        utils.Logger.Warn("Login rejected - account temporarily locked out",
+            zap.String("username", input.Username),
+            zap.String("ip", c.ClientIP()),
+        )
+        middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+            EventType: "login",
+            Outcome:   "failure",
+            Details:   map[string]interface{}{"username": input.Username, "reason": "account locked out"},
+            IP:        c.ClientIP(),
+            UserAgent: c.Request.UserAgent(),
+        })
+        middleware.IncrementLoginAttempt("locked_out")
+        c.Header("Retry-After", strconv.Itoa(int(time.Until(lockedUntil).Seconds())))
+        c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many failed login attempts, account temporarily locked"})
+        return
+    }
+
     var user models.User
-    // Support login with username OR email
-    if err := db.DB.Where("username = ? OR email = ?", input.Username, input.Username).First(&user).Error; err != nil {
+    // Support login with username OR email. Unscoped so a user inside their
+    // post-delete grace period (see DeleteUserAccount) can still log back in
+    // to hit POST /user/account/undelete - AuthMiddleware keeps them from
+    // reaching anything else until they do.
+    loginAuditFailure := func(reason string) {
+        middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+            EventType: "login",
+            Outcome:   "failure",
+            Details:   map[string]interface{}{"username": input.Username, "reason": reason},
+            IP:        c.ClientIP(),
+            UserAgent: c.Request.UserAgent(),
+        })
+        middleware.IncrementLoginAttempt("failure")
+    }
+
+    if err := db.DB.Unscoped().Preload("OTP").Where("username = ? OR email = ?", input.Username, input.Username).First(&user).Error; err != nil {
+        recordLoginFailure(input.Username)
+        utils.Logger.Warn("Login failed - user not found",
+            zap.String("username", input.Username),
+            zap.String("ip", c.ClientIP()),
+        )
+        loginAuditFailure("user not found")
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+        return
+    }
+    if user.DeletedAt.Valid && (user.PurgeAfter == nil || time.Now().After(*user.PurgeAfter)) {
+        // Past its grace period - the purge worker just hasn't caught up
+        // yet. Treat it as gone rather than letting the password check leak
+        // whether the account once existed.
+        recordLoginFailure(input.Username)
         utils.Logger.Warn("Login failed - user not found",
             zap.String("username", input.Username),
             zap.String("ip", c.ClientIP()),
         )
+        loginAuditFailure("user not found")
         c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
         return
     }
 
-    if !VerifyPassword(input.Password, user.PasswordHash) {
+    if user.PasswordHash == "" {
+        recordLoginFailure(input.Username)
+        utils.Logger.Warn("Login rejected - account is oauth-only",
+            zap.String("username", input.Username),
+            zap.Uint("user_id", user.ID),
+            zap.String("ip", c.ClientIP()),
+        )
+        loginAuditFailure("oauth-only account")
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "This account signs in via a social login provider"})
+        return
+    }
+
+    passwordOK, needsRehash, _ := VerifyPassword(input.Password, user.PasswordHash)
+    if !passwordOK {
+        recordLoginFailure(input.Username)
         utils.Logger.Warn("Login failed - invalid password",
             zap.String("username", input.Username),
             zap.Uint("user_id", user.ID),
             zap.String("ip", c.ClientIP()),
         )
+        loginAuditFailure("invalid password")
         c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
         return
     }
 
+    clearLoginLockout(input.Username)
+
+    if needsRehash {
+        if freshHash, err := HashPassword(input.Password); err == nil {
+            if err := db.DB.Model(&user).Update("password_hash", freshHash).Error; err != nil {
+                utils.Logger.Warn("Failed to transparently rehash password on login",
+                    zap.Error(err),
+                    zap.Uint("user_id", user.ID),
+                )
+            } else {
+                user.PasswordHash = freshHash
+            }
+        }
+    }
+
+    if user.OTP != nil && user.OTP.ConfirmedAt != nil {
+        mfaToken, err := GenerateMFAToken(user.ID)
+        if err != nil {
+            utils.Logger.Error("Failed to generate MFA token",
+                zap.Error(err),
+                zap.Uint("user_id", user.ID),
+            )
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start mfa step-up"})
+            return
+        }
+
+        utils.Logger.Info("Password verified, awaiting MFA step-up",
+            zap.Uint("user_id", user.ID),
+            zap.String("username", user.Username),
+            zap.String("ip", c.ClientIP()),
+        )
+
+        middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+            UserID:    user.ID,
+            EventType: "login",
+            Outcome:   "success",
+            Details:   map[string]interface{}{"username": user.Username, "mfa_required": true},
+            IP:        c.ClientIP(),
+            UserAgent: c.Request.UserAgent(),
+        })
+        middleware.IncrementLoginAttempt("mfa_required")
+
+        c.JSON(http.StatusOK, gin.H{
+            "mfa_required": true,
+            "mfa_token":    mfaToken,
+        })
+        return
+    }
+
     // Generate JWT token
     token, err := GenerateToken(user.ID, user.Username, string(user.Role))
     if err != nil {
@@ -108,9 +250,13 @@ func Login(c *gin.Context) {
             zap.Uint("user_id", user.ID),
             zap.String("username", user.Username),
         )
+        middleware.IncrementTokenOperation("issue", "failure")
         c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
         return
     }
+    middleware.IncrementTokenOperation("issue", "success")
+
+    refreshToken := issueSession(user.ID, c.ClientIP(), c.Request.UserAgent())
 
     utils.Logger.Info("User logged in successfully",
         zap.Uint("user_id", user.ID),
@@ -119,10 +265,20 @@ func Login(c *gin.Context) {
         zap.String("ip", c.ClientIP()),
     )
 
-    // Return token and user object (not just username string)
-    c.JSON(http.StatusOK, gin.H{
-        "message": "Login successful",
-        "token":   token,
+    middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+        UserID:    user.ID,
+        EventType: "login",
+        Outcome:   "success",
+        Details:   map[string]interface{}{"username": user.Username},
+        IP:        c.ClientIP(),
+        UserAgent: c.Request.UserAgent(),
+    })
+    middleware.IncrementLoginAttempt("success")
+
+    response := gin.H{
+        "message":       "Login successful",
+        "token":         token,
+        "refresh_token": refreshToken,
         "user": gin.H{
             "id":       user.ID,
             "username": user.Username,
@@ -130,7 +286,13 @@ func Login(c *gin.Context) {
             "name":     user.Name,
             "role":     user.Role,
         },
-    })
+    }
+    if user.DeletedAt.Valid {
+        response["pending_deletion"] = true
+        response["purge_after"] = user.PurgeAfter
+        response["message"] = "Login successful, account is pending deletion - call POST /user/account/undelete to cancel it"
+    }
+    c.JSON(http.StatusOK, response)
 }
 
 // GetUserProfile returns the current user's profile information
@@ -269,7 +431,7 @@ func ChangePassword(c *gin.Context) {
     userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
 
     var user models.User
-    if err := db.DB.First(&user, userID).Error; err != nil {
+    if err := db.DB.Preload("OTP").First(&user, userID).Error; err != nil {
         utils.Logger.Warn("User not found for password change",
             zap.Error(err),
             zap.Uint("user_id", userID),
@@ -278,9 +440,19 @@ func ChangePassword(c *gin.Context) {
         return
     }
 
+    if user.PasswordHash == "" {
+        utils.Logger.Warn("Password change rejected - account is oauth-only",
+            zap.Uint("user_id", userID),
+            zap.String("ip", c.ClientIP()),
+        )
+        c.JSON(http.StatusForbidden, gin.H{"error": "This account signs in via a social login provider and has no password to change"})
+        return
+    }
+
     var input struct {
         CurrentPassword string `json:"current_password" binding:"required"`
         NewPassword     string `json:"new_password" binding:"required,min=6"`
+        TOTPCode        string `json:"totp_code"`
     }
 
     if err := c.ShouldBindJSON(&input); err != nil {
@@ -292,13 +464,25 @@ func ChangePassword(c *gin.Context) {
         return
     }
 
+    if !requireFreshTOTP(c, user, input.TOTPCode) {
+        return
+    }
+
     // Verify current password
-    if !VerifyPassword(input.CurrentPassword, user.PasswordHash) {
+    if ok, _, _ := VerifyPassword(input.CurrentPassword, user.PasswordHash); !ok {
         utils.Logger.Warn("Password change failed - incorrect current password",
             zap.Uint("user_id", userID),
             zap.String("username", user.Username),
             zap.String("ip", c.ClientIP()),
         )
+        middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+            UserID:    userID,
+            EventType: "password_change",
+            Outcome:   "failure",
+            Details:   map[string]interface{}{"reason": "incorrect current password"},
+            IP:        c.ClientIP(),
+            UserAgent: c.Request.UserAgent(),
+        })
         c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
         return
     }
@@ -326,16 +510,36 @@ func ChangePassword(c *gin.Context) {
         return
     }
 
+    // A changed password invalidates every outstanding refresh token, not
+    // just the one used to call this endpoint - otherwise a leaked refresh
+    // token would survive the very credential rotation meant to shake it off.
+    endAllSessions(userID)
+
     utils.Logger.Info("Password changed successfully",
         zap.Uint("user_id", userID),
         zap.String("username", user.Username),
         zap.String("ip", c.ClientIP()),
     )
 
+    middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+        UserID:    userID,
+        EventType: "password_change",
+        Outcome:   "success",
+        IP:        c.ClientIP(),
+        UserAgent: c.Request.UserAgent(),
+    })
+
     c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
 }
 
-// DeleteUserAccount deletes the current user and all associated data
+// DeleteUserAccount soft-deletes the current user and their budgets,
+// transactions, categories and accounts, leaving everything restorable
+// until purge_after (see cascade.DefaultPurgeGracePeriod) via POST
+// /user/account/undelete. The background purge worker (see
+// purge_scheduler.go) hard-deletes it once the grace period elapses. The
+// token used to call this endpoint is revoked immediately, and
+// AuthMiddleware rejects the account everywhere except the undelete route
+// for the rest of the grace period.
 func DeleteUserAccount(c *gin.Context) {
     claims, exists := c.Get("user")
     if !exists {
@@ -346,11 +550,12 @@ func DeleteUserAccount(c *gin.Context) {
         return
     }
 
-    userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+    jwtClaims := claims.(jwt.MapClaims)
+    userID := uint(jwtClaims["sub"].(float64))
 
     // Get user info before deletion for logging
     var user models.User
-    if err := db.DB.First(&user, userID).Error; err != nil {
+    if err := db.DB.Preload("OTP").First(&user, userID).Error; err != nil {
         utils.Logger.Warn("User not found for account deletion",
             zap.Error(err),
             zap.Uint("user_id", userID),
@@ -359,6 +564,10 @@ func DeleteUserAccount(c *gin.Context) {
         return
     }
 
+    if !requireFreshTOTP(c, user, c.Query("totp_code")) {
+        return
+    }
+
     utils.Logger.Info("Starting account deletion",
         zap.Uint("user_id", userID),
         zap.String("username", user.Username),
@@ -366,12 +575,12 @@ func DeleteUserAccount(c *gin.Context) {
         zap.String("ip", c.ClientIP()),
     )
 
-    // Start database transaction for atomicity
+    // Start database transaction for atomicity. Same cascade order and
+    // soft-delete helper as DeleteUserAdmin: budget_items and transaction
+    // splits aren't independently restorable so they're always hard-deleted
+    // alongside their parent budget/transaction.
     tx := db.DB.Begin()
 
-    // Delete all user data in correct order (respecting foreign key constraints)
-
-    // 1. Delete budget items first (they reference budgets)
     if err := tx.Exec("DELETE FROM budget_items WHERE budget_id IN (SELECT id FROM budgets WHERE user_id = ?)", userID).Error; err != nil {
         tx.Rollback()
         utils.Logger.Error("Failed to delete budget items",
@@ -382,8 +591,7 @@ func DeleteUserAccount(c *gin.Context) {
         return
     }
 
-    // 2. Delete budgets
-    if err := tx.Where("user_id = ?", userID).Delete(&models.Budget{}).Error; err != nil {
+    if err := cascade.SoftDelete(tx, &models.Budget{}, "user_id = ?", []interface{}{userID}, false); err != nil {
         tx.Rollback()
         utils.Logger.Error("Failed to delete budgets",
             zap.Error(err),
@@ -393,7 +601,6 @@ func DeleteUserAccount(c *gin.Context) {
         return
     }
 
-    // 3. Delete transaction splits (they reference transactions)
     if err := tx.Exec("DELETE FROM transaction_splits WHERE parent_txn_id IN (SELECT id FROM transactions WHERE user_id = ?)", userID).Error; err != nil {
         tx.Rollback()
         utils.Logger.Error("Failed to delete transaction splits",
@@ -404,8 +611,7 @@ func DeleteUserAccount(c *gin.Context) {
         return
     }
 
-    // 4. Delete transactions
-    if err := tx.Where("user_id = ?", userID).Delete(&models.Transaction{}).Error; err != nil {
+    if err := cascade.SoftDelete(tx, &models.Transaction{}, "user_id = ?", []interface{}{userID}, false); err != nil {
         tx.Rollback()
         utils.Logger.Error("Failed to delete transactions",
             zap.Error(err),
@@ -415,8 +621,7 @@ func DeleteUserAccount(c *gin.Context) {
         return
     }
 
-    // 5. Delete categories
-    if err := tx.Where("user_id = ?", userID).Delete(&models.Category{}).Error; err != nil {
+    if err := cascade.SoftDelete(tx, &models.Category{}, "user_id = ?", []interface{}{userID}, false); err != nil {
         tx.Rollback()
         utils.Logger.Error("Failed to delete categories",
             zap.Error(err),
@@ -426,8 +631,7 @@ func DeleteUserAccount(c *gin.Context) {
         return
     }
 
-    // 6. Delete accounts
-    if err := tx.Where("user_id = ?", userID).Delete(&models.Account{}).Error; err != nil {
+    if err := cascade.SoftDelete(tx, &models.Account{}, "user_id = ?", []interface{}{userID}, false); err != nil {
         tx.Rollback()
         utils.Logger.Error("Failed to delete accounts",
             zap.Error(err),
@@ -437,8 +641,7 @@ func DeleteUserAccount(c *gin.Context) {
         return
     }
 
-    // 7. Finally delete the user
-    if err := tx.Delete(&models.User{}, userID).Error; err != nil {
+    if err := cascade.SoftDelete(tx, &models.User{}, "id = ?", []interface{}{userID}, false); err != nil {
         tx.Rollback()
         utils.Logger.Error("Failed to delete user account",
             zap.Error(err),
@@ -458,12 +661,93 @@ func DeleteUserAccount(c *gin.Context) {
         return
     }
 
-    utils.Logger.Info("Account deleted successfully",
+    // Revoke the token used to make this call so it can't keep hitting the
+    // API for the rest of its natural lifetime - AuthMiddleware's
+    // DeletedAt-based gate covers every other issued token.
+    if jti, _ := jwtClaims["jti"].(string); jti != "" {
+        if expUnix, ok := jwtClaims["exp"].(float64); ok {
+            middleware.RevokeToken(jti, time.Unix(int64(expUnix), 0))
+        }
+    }
+    endAllSessions(userID)
+
+    purgeAfter := time.Now().Add(cascade.DefaultPurgeGracePeriod)
+    utils.Logger.Info("Account soft-deleted, restorable until purge_after",
         zap.Uint("user_id", userID),
         zap.String("username", user.Username),
         zap.String("email", user.Email),
         zap.String("ip", c.ClientIP()),
+        zap.Time("purge_after", purgeAfter),
+    )
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":     "Account deleted successfully, restorable until purge_after",
+        "purge_after": purgeAfter,
+    })
+}
+
+// UndeleteUserAccount reverses a pending DeleteUserAccount call within its
+// grace period. The caller must present a fresh token for the deleted
+// account (re-authenticate via POST /auth/login) - AuthMiddleware lets that
+// token reach this one route despite the account's DeletedAt being set.
+func UndeleteUserAccount(c *gin.Context) {
+    claims, exists := c.Get("user")
+    if !exists {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+        return
+    }
+    userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+    var user models.User
+    if err := db.DB.Unscoped().Where("id = ?", userID).First(&user).Error; err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+        return
+    }
+    if !user.DeletedAt.Valid {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "account is not pending deletion"})
+        return
+    }
+    if user.PurgeAfter != nil && time.Now().After(*user.PurgeAfter) {
+        c.JSON(http.StatusGone, gin.H{"error": "grace period has expired, account can no longer be restored"})
+        return
+    }
+
+    tx := db.DB.Begin()
+    if err := cascade.Restore(tx, &models.Account{}, "user_id = ?", []interface{}{userID}); err != nil {
+        tx.Rollback()
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore accounts"})
+        return
+    }
+    if err := cascade.Restore(tx, &models.Category{}, "user_id = ?", []interface{}{userID}); err != nil {
+        tx.Rollback()
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore categories"})
+        return
+    }
+    if err := cascade.Restore(tx, &models.Transaction{}, "user_id = ?", []interface{}{userID}); err != nil {
+        tx.Rollback()
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore transactions"})
+        return
+    }
+    if err := cascade.Restore(tx, &models.Budget{}, "user_id = ?", []interface{}{userID}); err != nil {
+        tx.Rollback()
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore budgets"})
+        return
+    }
+    if err := cascade.Restore(tx, &models.User{}, "id = ?", []interface{}{userID}); err != nil {
+        tx.Rollback()
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore user"})
+        return
+    }
+    if err := tx.Commit().Error; err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit account restore"})
+        return
+    }
+
+    utils.Logger.Info("Account deletion cancelled within grace period",
+        zap.Uint("user_id", userID),
+        zap.String("username", user.Username),
+        zap.String("ip", c.ClientIP()),
     )
 
-    c.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
+    c.JSON(http.StatusOK, gin.H{"message": "account restored successfully"})
 }
@@ -3,54 +3,135 @@ package controllers
 import (
 	"crypto/rand"
 	"encoding/base64"
-	"errors"
-	"net/http"
-	"os"
+	"encoding/hex"
 	"strconv"
 	"strings"
 	"time"
 
+	"Personal-Finance-Tracker-backend/config"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/argon2"
 )
 
-// Password hashing parameters (reasonable defaults)
-var (
-	argonTime    uint32 = 1
-	argonMemory  uint32 = 64 * 1024
-	argonThreads uint8  = 4
-	argonKeyLen  uint32 = 32
-)
+// PasswordPolicy is one named Argon2id parameter profile. Profiles are
+// versioned rather than mutated in place: bumping m/t/p for everyone at
+// once would mean VerifyPassword can no longer tell "this hash predates
+// the last tuning pass" from "this hash is already current". Instead a new
+// version is added to passwordPolicies and becomes active, and
+// VerifyPassword flags needsRehash for any hash whose embedded params
+// don't match the active profile - regardless of which old version they
+// came from.
+type PasswordPolicy struct {
+	Version string
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	KeyLen  uint32
+}
+
+// passwordPolicies are this app's Argon2id profiles, oldest first. v1 is
+// OWASP's minimum-recommended memory at t=1; v2 raises t to 3 for
+// accounts hashed (or rehashed) after this went live.
+var passwordPolicies = map[string]PasswordPolicy{
+	"v1": {Version: "v1", Time: 1, Memory: 64 * 1024, Threads: 4, KeyLen: 32},
+	"v2": {Version: "v2", Time: 3, Memory: 64 * 1024, Threads: 4, KeyLen: 32},
+}
+
+// defaultPasswordPolicyVersion is used when auth.argon2_profile is unset or
+// names a profile that doesn't exist.
+const defaultPasswordPolicyVersion = "v2"
+
+// activePasswordPolicy is the profile HashPassword hashes new passwords
+// with and VerifyPassword compares existing hashes against to decide
+// needsRehash. Resolved once at startup from auth.argon2_profile (see
+// config.Provider) - like accessTokenTTL, not expected to change without a
+// restart.
+var activePasswordPolicy = loadActivePasswordPolicy()
+
+func loadActivePasswordPolicy() PasswordPolicy {
+	name := config.GetOr("auth.argon2_profile", defaultPasswordPolicyVersion)
+	if policy, ok := passwordPolicies[name]; ok {
+		return policy
+	}
+	return passwordPolicies[defaultPasswordPolicyVersion]
+}
+
+// LogActivePasswordPolicy reports the Argon2id profile new password hashes
+// will be created under. Call once at startup, after utils.Logger is
+// initialized.
+func LogActivePasswordPolicy() {
+	utils.Logger.Info("Active password hashing profile",
+		zap.String("version", activePasswordPolicy.Version),
+		zap.Uint32("time", activePasswordPolicy.Time),
+		zap.Uint32("memory_kib", activePasswordPolicy.Memory),
+		zap.Uint8("threads", activePasswordPolicy.Threads),
+	)
+}
+
+// CalibrateArgon2 picks the smallest memory (in KiB, a multiple of 8 MiB)
+// that, with a time parameter of 1, takes at least targetDuration to hash
+// on this host, so operators can size auth.argon2_profile
+// for their own hardware instead of guessing. It is a one-off operational
+// tool, not called from any request path.
+func CalibrateArgon2(targetDuration time.Duration) PasswordPolicy {
+	const threads uint8 = 4
+	const step uint32 = 8 * 1024 // 8 MiB
+	memory := step
+
+	salt := make([]byte, 16)
+	_, _ = rand.Read(salt)
+
+	for {
+		start := time.Now()
+		argon2.IDKey([]byte("calibration-probe"), salt, 1, memory, threads, 32)
+		if time.Since(start) >= targetDuration {
+			return PasswordPolicy{Version: "calibrated", Time: 1, Memory: memory, Threads: threads, KeyLen: 32}
+		}
+		memory += step
+	}
+}
 
-// HashPassword returns a string which encodes the parameters, salt and hash.
+// HashPassword returns a string which encodes the active profile's
+// parameters, a random salt, and the derived hash.
 func HashPassword(password string) (string, error) {
 	salt := make([]byte, 16)
 	if _, err := rand.Read(salt); err != nil {
 		return "", err
 	}
-	hash := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	policy := activePasswordPolicy
+	hash := argon2.IDKey([]byte(password), salt, policy.Time, policy.Memory, policy.Threads, policy.KeyLen)
 	b64Salt := base64.RawStdEncoding.EncodeToString(salt)
 	b64Hash := base64.RawStdEncoding.EncodeToString(hash)
 	// store as: $argon2id$v=19$m=65536,t=1,p=4$<salt>$<hash>
-	encoded := strings.Join([]string{"$argon2id", "v=19", "m=" + strconv.Itoa(int(argonMemory)) + ",t=" + strconv.Itoa(int(argonTime)) + ",p=" + strconv.Itoa(int(argonThreads)), b64Salt, b64Hash}, "$")
+	encoded := strings.Join([]string{"$argon2id", "v=19", "m=" + strconv.Itoa(int(policy.Memory)) + ",t=" + strconv.Itoa(int(policy.Time)) + ",p=" + strconv.Itoa(int(policy.Threads)), b64Salt, b64Hash}, "$")
 	return encoded, nil
 }
 
-// VerifyPassword checks password against encoded hash
-func VerifyPassword(password, encoded string) bool {
+// VerifyPassword checks password against encoded hash. needsRehash is true
+// when the password matched but encoded's embedded m/t/p/keylen differ
+// from activePasswordPolicy - the caller (controllers.Login) should then
+// compute a fresh hash under the active profile and persist it, the same
+// way a bcrypt cost bump is usually rolled out.
+func VerifyPassword(password, encoded string) (ok bool, needsRehash bool, err error) {
 	parts := strings.Split(encoded, "$")
 	if len(parts) != 6 {
-		return false
+		return false, false, nil
 	}
 	// parts: "", "argon2id", "v=19", "m=...,t=...,p=...", salt, hash
 	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
 	if err != nil {
-		return false
+		return false, false, nil
 	}
 	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
 	if err != nil {
-		return false
+		return false, false, nil
 	}
 	// parse params
 	params := parts[3]
@@ -71,7 +152,13 @@ func VerifyPassword(password, encoded string) bool {
 		}
 	}
 	derived := argon2.IDKey([]byte(password), salt, uint32(t), uint32(m), uint8(p), uint32(len(hash)))
-	return subtleConstantTimeCompare(hash, derived)
+	if !subtleConstantTimeCompare(hash, derived) {
+		return false, false, nil
+	}
+
+	policy := activePasswordPolicy
+	stale := uint32(m) != policy.Memory || uint32(t) != policy.Time || uint8(p) != policy.Threads || uint32(len(hash)) != policy.KeyLen
+	return true, stale, nil
 }
 
 // constant time compare
@@ -86,67 +173,60 @@ func subtleConstantTimeCompare(a, b []byte) bool {
 	return diff == 0
 }
 
-// JWT helpers
-var jwtSecret []byte
+// newJTI returns a random session token identifier, used to target a single
+// token for revocation (see RevokeToken) without tracking every issued
+// token up front.
+func newJTI() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
 
-func getJWTSecret() []byte {
-	if jwtSecret != nil {
-		return jwtSecret
-	}
-	s := os.Getenv("JWT_SECRET")
-	if s == "" {
-		// fallback: generate ephemeral secret (not for production)
-		tmp := make([]byte, 32)
-		_, _ = rand.Read(tmp)
-		s = base64.RawStdEncoding.EncodeToString(tmp)
+// accessTokenTTL is short because a leaked access token is meant to be a
+// non-event - it expires on its own well before a stolen refresh token
+// (see session_store.go) could be rotated through very many times.
+const accessTokenTTL = 15 * time.Minute
+
+func GenerateToken(userID uint, username string, role string) (string, error) {
+	claims := jwt.MapClaims{
+		"sub":      userID,
+		"name":     username,
+		"role":     role,
+		"perm_ver": models.CurrentRoleVersion(db.DB, models.UserRole(role)),
+		"jti":      newJTI(),
+		"iat":      time.Now().Unix(),
+		"exp":      time.Now().Add(accessTokenTTL).Unix(),
 	}
-	jwtSecret = []byte(s)
-	return jwtSecret
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(middleware.JWTSecret())
 }
 
-func GenerateToken(userID uint, username string, role string) (string, error) {
+// GenerateMFAToken issues a short-lived token proving the password step of
+// login succeeded but TOTP step-up is still outstanding. It carries no
+// role/username - it is only ever exchanged at POST /login/mfa, never
+// accepted as a session token.
+func GenerateMFAToken(userID uint) (string, error) {
 	claims := jwt.MapClaims{
-		"sub":  userID,
-		"name": username,
-		"role": role,
-		"exp":  time.Now().Add(24 * time.Hour).Unix(),
+		"sub":     userID,
+		"purpose": "mfa",
+		"exp":     time.Now().Add(5 * time.Minute).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(getJWTSecret())
+	return token.SignedString(middleware.JWTSecret())
 }
 
+// ParseToken validates tokenStr against the shared JWT secret. It delegates
+// to middleware.ParseToken (see that package for why the secret and parsing
+// live there) and exists here only so existing call sites in controllers
+// don't need to import middleware themselves.
 func ParseToken(tokenStr string) (*jwt.Token, error) {
-	return jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return getJWTSecret(), nil
-	})
+	return middleware.ParseToken(tokenStr)
 }
 
-// AuthMiddleware enforces a valid JWT in Authorization header: "Bearer <token>"
+// AuthMiddleware enforces a valid, non-revoked JWT in the Authorization
+// header ("Bearer <token>"). It delegates to middleware.RequireAccessToken;
+// kept as a controllers-package wrapper for call-site compatibility with
+// main.go and existing tests.
 func AuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		auth := c.GetHeader("Authorization")
-		if auth == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing authorization header"})
-			return
-		}
-		parts := strings.SplitN(auth, " ", 2)
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header"})
-			return
-		}
-		tokStr := parts[1]
-		token, err := ParseToken(tokStr)
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			return
-		}
-		// attach claims
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Set("user", claims)
-		}
-		c.Next()
-	}
+	return middleware.RequireAccessToken()
 }
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// balanceReconcileBatchSize bounds how many accounts runBalanceReconciliation
+// loads per page, so a large install doesn't pull every account into memory
+// at once.
+const balanceReconcileBatchSize = 200
+
+// StartBalanceReconciler launches a background goroutine that periodically
+// recomputes every account's CurrentBalance from its own transactions
+// (see reconcileAccountBalance), so balances drift-proofed against any
+// write path that forgets to call UpdateAccountBalance.
+func StartBalanceReconciler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runBalanceReconciliation()
+		}
+	}()
+}
+
+// runBalanceReconciliation walks every account in batches, reconciling each
+// one's stored balance against the sum of its transactions, and reports the
+// pass's total absolute drift and duration via middleware's Prometheus
+// metrics.
+func runBalanceReconciliation() (accountsChecked int, totalDriftCents int64) {
+	start := time.Now()
+	outcome := "ok"
+
+	offset := 0
+	for {
+		var accounts []models.Account
+		if err := db.DB.Order("id").Limit(balanceReconcileBatchSize).Offset(offset).Find(&accounts).Error; err != nil {
+			utils.Logger.Error("balance reconciler: failed to load accounts batch", zap.Error(err))
+			outcome = "error"
+			break
+		}
+		if len(accounts) == 0 {
+			break
+		}
+
+		for _, account := range accounts {
+			delta, err := reconcileAccountBalance(account.ID)
+			if err != nil {
+				utils.Logger.Error("balance reconciler: failed to reconcile account",
+					zap.Uint("account_id", account.ID), zap.Error(err))
+				outcome = "error"
+				continue
+			}
+			accountsChecked++
+			if delta != 0 {
+				totalDriftCents += abs64(delta)
+				utils.Logger.Warn("balance reconciler: corrected drifted account balance",
+					zap.Uint("account_id", account.ID), zap.Int64("delta_cents", delta))
+			}
+		}
+
+		offset += balanceReconcileBatchSize
+	}
+
+	middleware.RecordBalanceReconciliation(outcome, totalDriftCents, time.Since(start))
+	return accountsChecked, totalDriftCents
+}
+
+// reconcileAccountBalance recomputes accountID's balance the same way
+// UpdateAccountBalance does (own transactions/splits plus every descendant's,
+// see descendantAccountIDs), saves it, and returns new-old as the delta
+// applied (0 if the stored balance was already correct).
+func reconcileAccountBalance(accountID uint) (delta int64, err error) {
+	var account models.Account
+	if err := db.DB.First(&account, accountID).Error; err != nil {
+		return 0, err
+	}
+
+	before := centsOf(account.CurrentBalance)
+	if err := UpdateAccountBalance(accountID); err != nil {
+		return 0, err
+	}
+
+	var after models.Account
+	if err := db.DB.First(&after, accountID).Error; err != nil {
+		return 0, err
+	}
+
+	return centsOf(after.CurrentBalance) - before, nil
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// RecomputeAccountBalance handles POST /accounts/:id/recompute: triggers an
+// on-demand reconciliation of one of the authenticated user's accounts and
+// returns the delta applied.
+func RecomputeAccountBalance(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	accountID := c.Param("id")
+
+	var account models.Account
+	if err := db.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	delta, err := reconcileAccountBalance(account.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to recompute account balance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"account_id": account.ID, "delta_cents": delta})
+}
+
+// RecomputeAllAccountBalances handles admin-only POST
+// /admin/accounts/recompute-all: runs a full reconciliation pass
+// synchronously, the same as StartBalanceReconciler's next tick would, and
+// returns how many accounts were checked and the total drift corrected.
+func RecomputeAllAccountBalances(c *gin.Context) {
+	accountsChecked, totalDriftCents := runBalanceReconciliation()
+	c.JSON(http.StatusOK, gin.H{
+		"accounts_checked":  accountsChecked,
+		"total_drift_cents": totalDriftCents,
+	})
+}
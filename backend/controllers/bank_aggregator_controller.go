@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/aggregators"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+)
+
+// CreateBankLinkSession starts a link flow with the aggregator named by
+// ?provider= (default "plaid"), looked up through the aggregators registry
+// so adding a new provider never touches this handler. This is the
+// provider-agnostic counterpart to CreateLinkToken, which only ever talks
+// to Plaid - it lets a user whose bank Plaid doesn't cover still link
+// through gocardless/truelayer once those aggregators are implemented.
+func CreateBankLinkSession(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	provider := c.DefaultQuery("provider", "plaid")
+	agg, err := aggregators.Get(provider)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := agg.CreateLinkSession(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create link session: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"provider":     provider,
+		"token":        session.Token,
+		"redirect_url": session.RedirectURL,
+		"expiration":   session.ExpiresAt,
+	})
+}
+
+// ExchangeBankToken completes a provider's link flow the way
+// ExchangePublicToken does for Plaid specifically, but through whichever
+// aggregator ?provider= (default "plaid") names. It persists the
+// resulting Credentials into BankConnection.Metadata and stamps
+// BankConnection.Provider instead of assuming "plaid://api" and
+// Plaid-shaped Metadata keys, then pulls the initial account list through
+// the same aggregator.
+func ExchangeBankToken(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	provider := c.DefaultQuery("provider", "plaid")
+
+	var req struct {
+		PublicToken string `json:"public_token" binding:"required"`
+		BankName    string `json:"bank_name"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	agg, err := aggregators.Get(provider)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cred, err := agg.ExchangeToken(c.Request.Context(), req.PublicToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to exchange token: " + err.Error()})
+		return
+	}
+
+	institutionName := req.BankName
+	if institutionName == "" {
+		institutionName = provider + " bank"
+	}
+
+	connection := models.BankConnection{
+		UserID:            userID,
+		BankName:          institutionName,
+		BankEndpoint:      provider + "://api",
+		Provider:          provider,
+		Status:            "connected",
+		ConsentValidUntil: time.Now().Add(90 * 24 * time.Hour),
+		Metadata:          models.JSONB(cred),
+	}
+	if itemID, ok := cred["item_id"].(string); ok {
+		connection.ConsentID = itemID
+	}
+
+	if err := db.DB.Create(&connection).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save bank connection: " + err.Error()})
+		return
+	}
+
+	accounts, err := agg.ListAccounts(c.Request.Context(), cred)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success":       true,
+			"message":       "bank connected, but failed to fetch accounts: " + err.Error(),
+			"connection_id": connection.ID,
+		})
+		return
+	}
+
+	for _, acc := range accounts {
+		bankAccount := models.BankAccount{
+			BankConnectionID: connection.ID,
+			AccountID:        acc.ID,
+			AccountName:      acc.Name,
+			Currency:         acc.Currency,
+			AccountType:      acc.Type,
+			IsActive:         true,
+		}
+		if err := db.DB.Create(&bankAccount).Error; err != nil {
+			continue
+		}
+
+		internalAccount := models.Account{
+			UserID:              userID,
+			Name:                acc.Name,
+			Type:                models.AccountChecking,
+			Currency:            acc.Currency,
+			InitialBalanceCents: acc.BalanceCents,
+			CurrentBalance:      decimal.NewFromInt(acc.BalanceCents).Div(decimal.NewFromInt(100)),
+		}
+		if err := db.DB.Create(&internalAccount).Error; err != nil {
+			continue
+		}
+
+		bankAccount.InternalAccountID = &internalAccount.ID
+		db.DB.Save(&bankAccount)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":       true,
+		"message":       "bank connected successfully via " + provider,
+		"connection_id": connection.ID,
+	})
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// recordBankAuditEvent writes one BankAuditEvent off the back of a bank
+// connection action, the same fire-and-forget way logPSD2Audit writes a
+// BankSyncLog row: a failure to record the audit trail shouldn't fail the
+// request it's auditing, just get logged.
+func recordBankAuditEvent(c *gin.Context, userID uint, connectionID *uint, action string, before, after models.JSONB) {
+	event := models.BankAuditEvent{
+		UserID:           userID,
+		BankConnectionID: connectionID,
+		Action:           action,
+		RemoteIP:         c.ClientIP(),
+		UserAgent:        c.Request.UserAgent(),
+		RequestID:        c.GetHeader("X-Request-ID"),
+		Before:           before,
+		After:            after,
+	}
+	if err := db.DB.Create(&event).Error; err != nil {
+		utils.Logger.Warn("bank_audit: failed to write audit event")
+	}
+}
+
+// GetBankConnectionAuditLog returns a paginated, caller-scoped history of
+// BankAuditEvent rows for one connection - the trace a disputed sync or
+// consent action can be reconstructed from, including events recorded
+// after the connection itself was soft-deleted by DisconnectBank.
+func GetBankConnectionAuditLog(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	connectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid connection id"})
+		return
+	}
+
+	// The connection row may already be soft-deleted (DisconnectBank), so
+	// ownership is checked against BankAuditEvent.UserID directly rather
+	// than re-loading the BankConnection.
+	cursor, limit, err := ParseCursor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := db.DB.Model(&models.BankAuditEvent{}).
+		Where("user_id = ? AND bank_connection_id = ?", userID, connectionID)
+	if cursor != "" {
+		pc, _ := decodeCursor(cursor)
+		clause, clauseArgs := keysetClause("", "created_at", true)
+		query = query.Where(clause, clauseArgs(*pc)...)
+	}
+
+	var events []models.BankAuditEvent
+	if err := query.Order(orderClause("", "created_at", true)).Limit(limit).Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch bank audit events"})
+		return
+	}
+
+	nextCursor := ""
+	if len(events) == limit {
+		last := events[len(events)-1]
+		nextCursor = encodeCursor(last.ID, last.CreatedAt)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events, "next_cursor": nextCursor})
+}
+
+// GetBankAuditEvents lists BankAuditEvent rows across every user (admin
+// only), optionally filtered by ?user_id= or ?action=.
+func GetBankAuditEvents(c *gin.Context) {
+	cursor, limit, err := ParseCursor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := db.DB.Model(&models.BankAuditEvent{})
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if cursor != "" {
+		pc, _ := decodeCursor(cursor)
+		clause, clauseArgs := keysetClause("", "created_at", true)
+		query = query.Where(clause, clauseArgs(*pc)...)
+	}
+
+	var events []models.BankAuditEvent
+	if err := query.Order(orderClause("", "created_at", true)).Limit(limit).Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch bank audit events"})
+		return
+	}
+
+	nextCursor := ""
+	if len(events) == limit {
+		last := events[len(events)-1]
+		nextCursor = encodeCursor(last.ID, last.CreatedAt)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events, "next_cursor": nextCursor})
+}
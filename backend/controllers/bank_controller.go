@@ -5,21 +5,19 @@ import (
 
 	"Personal-Finance-Tracker-backend/db"
 	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/banksync"
 
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
 )
 
-// CreateBankConnection - Deprecated endpoint, use Plaid Link instead
-func CreateBankConnection(c *gin.Context) {
-	c.JSON(400, gin.H{
-		"error":   "This endpoint is deprecated. Please use Plaid Link instead.",
-		"message": "Use /api/plaid/create_link_token to connect banks via Plaid",
-		"hint":    "All bank connections now use Plaid for security and reliability",
-	})
-}
+// CreateBankConnection has moved to bank_sync.go: it now initiates a real
+// PSD2 consent instead of returning a "use Plaid instead" stub.
 
-// GetBankConnections returns all bank connections for a user
+// GetBankConnections returns all bank connections for a user. A connection
+// whose circuit breaker (see services/banksync) is open after too many
+// consecutive sync failures is reported as "degraded" here instead of its
+// stored Status, without persisting that override.
 func GetBankConnections(c *gin.Context) {
 	claims, exists := c.Get("user")
 	if !exists {
@@ -37,6 +35,13 @@ func GetBankConnections(c *gin.Context) {
 		return
 	}
 
+	for i := range connections {
+		if state, _ := banksync.Status(connections[i].ID); state == banksync.BreakerOpen {
+			connections[i].Status = "degraded"
+		}
+	}
+
+	recordBankAuditEvent(c, userID, nil, models.BankAuditActionViewed, nil, models.JSONB{"count": len(connections)})
 	c.JSON(200, gin.H{"connections": connections})
 }
 
@@ -60,11 +65,14 @@ func DisconnectBank(c *gin.Context) {
 		return
 	}
 
+	before := models.JSONB{"status": connection.Status, "consent_status": connection.ConsentStatus}
+
 	// Delete from database (soft delete)
 	if err := db.DB.Delete(&connection).Error; err != nil {
 		c.JSON(500, gin.H{"error": "Failed to delete bank connection"})
 		return
 	}
 
+	recordBankAuditEvent(c, userID, &connection.ID, models.BankAuditActionDisconnected, before, nil)
 	c.JSON(200, gin.H{"message": "Bank connection deleted successfully"})
 }
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/banksync"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+type bankConnectionHealth struct {
+	ConnectionID         uint       `json:"connection_id"`
+	BankName             string     `json:"bank_name"`
+	BreakerState         string     `json:"breaker_state"`
+	ConsecutiveFailures  int        `json:"consecutive_failures"`
+	LastSuccessfulSyncAt *time.Time `json:"last_successful_sync_at"`
+	NextSyncAt           *time.Time `json:"next_sync_at"`
+}
+
+// GetBankHealth returns per-connection sync health for the caller: the
+// circuit breaker state and consecutive failure count tracked in
+// services/banksync, the last sync that actually succeeded (as opposed to
+// LastSyncAt, which advances on every attempt), and the connection's
+// computed NextSyncAt.
+func GetBankHealth(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var connections []models.BankConnection
+	if err := db.DB.Where("user_id = ?", userID).Find(&connections).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch bank connections"})
+		return
+	}
+
+	health := make([]bankConnectionHealth, 0, len(connections))
+	for _, connection := range connections {
+		state, failures := banksync.Status(connection.ID)
+
+		var lastSuccess *time.Time
+		var lastSuccessfulLog models.BankSyncLog
+		if err := db.DB.Where("bank_connection_id = ? AND status = ?", connection.ID, "success").
+			Order("created_at DESC").
+			First(&lastSuccessfulLog).Error; err == nil {
+			lastSuccess = &lastSuccessfulLog.CreatedAt
+		}
+
+		health = append(health, bankConnectionHealth{
+			ConnectionID:         connection.ID,
+			BankName:             connection.BankName,
+			BreakerState:         string(state),
+			ConsecutiveFailures:  failures,
+			LastSuccessfulSyncAt: lastSuccess,
+			NextSyncAt:           connection.NextSyncAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"connections": health})
+}
+
+// ResetBankConnectionBreaker manually closes a connection's circuit
+// breaker, for a user who has fixed whatever was causing syncs to fail and
+// doesn't want to wait out the breaker's own cooldown for a half-open probe.
+func ResetBankConnectionBreaker(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	connectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid connection id"})
+		return
+	}
+
+	var connection models.BankConnection
+	if err := db.DB.Where("id = ? AND user_id = ?", connectionID, userID).First(&connection).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bank connection not found"})
+		return
+	}
+
+	banksync.Reset(connection.ID)
+
+	c.JSON(http.StatusOK, gin.H{"connection_id": connection.ID, "breaker_state": string(banksync.BreakerClosed)})
+}
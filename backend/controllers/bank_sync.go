@@ -0,0 +1,536 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/banksync"
+	"Personal-Finance-Tracker-backend/services/psd2"
+	"Personal-Finance-Tracker-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+)
+
+// consentValidityDays is the Berlin Group access window requested for every
+// consent: 90 days of account list, balances, and transactions access,
+// re-authorized by the PSU via the SCA redirect whenever it lapses.
+const consentValidityDays = 90
+
+// callbackBaseURL returns the externally-reachable base URL the SCA redirect
+// calls back to, configured the same way the OAuth2 social login redirect
+// URLs are (see InitGoogleOAuth/InitGitHubOAuth): a single env var, because
+// it has to match whatever was registered with the bank ahead of time.
+func callbackBaseURL() string {
+	if base := os.Getenv("PSD2_CALLBACK_BASE_URL"); base != "" {
+		return base
+	}
+	return "http://localhost:8080"
+}
+
+// encryptedBankSecrets reads the TPP client certificate configured for
+// bankName, if any, and returns it AES-GCM encrypted under Metadata - this
+// is the only place a bank's client cert/OAuth secret is read, so nothing
+// downstream ever has to handle it in plaintext.
+func encryptedBankSecrets(bankName string) (models.JSONB, bool) {
+	cert := os.Getenv(strings.ToUpper(bankName) + "_CLIENT_CERT")
+	if cert == "" {
+		return nil, false
+	}
+
+	encrypted, err := psd2.EncryptSecret(cert)
+	if err != nil {
+		utils.Logger.Warn("bank_sync: failed to encrypt client cert, leaving connection without one")
+		return nil, false
+	}
+	return models.JSONB{"client_cert_enc": encrypted}, true
+}
+
+type createBankConnectionRequest struct {
+	BankName string `json:"bank_name" binding:"required"` // "sparebanken_norge" or "bulder_bank"
+}
+
+// CreateBankConnection initiates a Berlin Group NextGenPSD2 XS2A consent for
+// the requested bank and returns the SCA redirect URL the frontend sends the
+// PSU to. The connection is stored as "pending" until the PSU completes SCA
+// and GET /:id/callback finalizes it.
+func CreateBankConnection(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var req createBankConnectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint, ok := psd2.BankEndpoints[req.BankName]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported bank_name"})
+		return
+	}
+
+	connection := models.BankConnection{
+		UserID:          userID,
+		BankName:        req.BankName,
+		BankEndpoint:    endpoint,
+		ConsentStatus:   "initiating",
+		FrequencyPerDay: 4,
+		Status:          "pending",
+	}
+	if err := db.DB.Create(&connection).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create bank connection"})
+		return
+	}
+
+	validUntil := time.Now().AddDate(0, 0, consentValidityDays)
+	redirectURI := fmt.Sprintf("%s/api/banks/connections/%d/callback", callbackBaseURL(), connection.ID)
+
+	consent, err := psd2.NewClient(endpoint).InitiateConsent(redirectURI, validUntil, connection.FrequencyPerDay)
+	if err != nil {
+		utils.Logger.Warn("bank_sync: failed to initiate consent")
+		db.DB.Model(&connection).Updates(map[string]interface{}{"status": "failed", "consent_status": "failed"})
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to initiate consent with bank"})
+		return
+	}
+
+	connection.ConsentID = consent.ConsentID
+	connection.ConsentStatus = consent.Status
+	connection.ConsentValidUntil = validUntil
+	if metadata, ok := encryptedBankSecrets(req.BankName); ok {
+		connection.Metadata = metadata
+	}
+	if err := db.DB.Save(&connection).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store consent"})
+		return
+	}
+
+	recordBankAuditEvent(c, userID, &connection.ID, models.BankAuditActionConnected, nil,
+		models.JSONB{"bank_name": connection.BankName, "consent_status": connection.ConsentStatus})
+
+	c.JSON(http.StatusOK, gin.H{
+		"connection_id": connection.ID,
+		"redirect_url":  consent.RedirectURL,
+		"consent_id":    consent.ConsentID,
+	})
+}
+
+// reauthenticatableConsentStatuses are the only ConsentStatus values
+// ReauthenticateBankConnection accepts as a starting point: a connection
+// whose consent has lapsed ("expired") or is nearing its 90-day window
+// ("valid", re-authorized early). A "revoked" consent means the PSU
+// withdrew access at the bank, not just let the window lapse - that needs
+// DisconnectBank and a fresh CreateBankConnection, not a reauthentication.
+var reauthenticatableConsentStatuses = map[string]bool{
+	"valid":   true,
+	"expired": true,
+}
+
+// ReauthenticateBankConnection initiates a fresh Berlin Group consent for an
+// existing, expired-or-expiring BankConnection and returns the SCA redirect
+// URL, the same way CreateBankConnection does for a brand new one. Unlike
+// CreateBankConnection, no new BankConnection row is created: the existing
+// one is updated in place with the new ConsentID once initiated, so its
+// linked BankAccount rows and historical BankSyncLog entries carry over
+// untouched. BankConnectionCallback finalizes it exactly as it does for a
+// first-time connection.
+func ReauthenticateBankConnection(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	connectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid connection id"})
+		return
+	}
+
+	var connection models.BankConnection
+	if err := db.DB.Where("id = ? AND user_id = ?", connectionID, userID).First(&connection).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bank connection not found"})
+		return
+	}
+
+	if !reauthenticatableConsentStatuses[connection.ConsentStatus] {
+		c.JSON(http.StatusConflict, gin.H{"error": "connection consent status does not support reauthentication"})
+		return
+	}
+
+	before := models.JSONB{"consent_status": connection.ConsentStatus}
+
+	validUntil := time.Now().AddDate(0, 0, consentValidityDays)
+	redirectURI := fmt.Sprintf("%s/api/banks/connections/%d/callback", callbackBaseURL(), connection.ID)
+
+	consent, err := psd2.NewClient(connection.BankEndpoint).InitiateConsent(redirectURI, validUntil, connection.FrequencyPerDay)
+	if err != nil {
+		utils.Logger.Warn("bank_sync: failed to initiate reauthentication consent")
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to initiate consent with bank"})
+		return
+	}
+
+	connection.ConsentID = consent.ConsentID
+	connection.ConsentStatus = consent.Status
+	connection.ConsentValidUntil = validUntil
+	connection.Status = "pending"
+	if err := db.DB.Save(&connection).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store consent"})
+		return
+	}
+
+	recordBankAuditEvent(c, userID, &connection.ID, models.BankAuditActionReauthenticated, before,
+		models.JSONB{"consent_status": connection.ConsentStatus})
+
+	c.JSON(http.StatusOK, gin.H{
+		"connection_id": connection.ID,
+		"redirect_url":  consent.RedirectURL,
+		"consent_id":    consent.ConsentID,
+	})
+}
+
+// BankConnectionCallback finalizes a consent after the PSU completes SCA at
+// the bank: it re-checks the consent status, and once valid, lists the
+// accounts it now has access to and links them as BankAccount rows.
+func BankConnectionCallback(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	connectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid connection id"})
+		return
+	}
+
+	var connection models.BankConnection
+	if err := db.DB.Where("id = ? AND user_id = ?", connectionID, userID).First(&connection).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bank connection not found"})
+		return
+	}
+
+	client := psd2.NewClient(connection.BankEndpoint)
+	status, err := client.ConsentStatus(connection.ConsentID)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to check consent status"})
+		return
+	}
+	connection.ConsentStatus = status
+
+	if status != "valid" {
+		connection.Status = "failed"
+		db.DB.Save(&connection)
+		if status == "expired" {
+			recordBankAuditEvent(c, userID, &connection.ID, models.BankAuditActionConsentExpired, nil, models.JSONB{"consent_status": status})
+		}
+		c.JSON(http.StatusOK, gin.H{"status": status})
+		return
+	}
+
+	accounts, err := client.Accounts(connection.ConsentID)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to list accounts"})
+		return
+	}
+
+	for _, acct := range accounts {
+		bankAccount := models.BankAccount{
+			BankConnectionID: connection.ID,
+			AccountID:        acct.ResourceID,
+			IBAN:             acct.IBAN,
+			AccountName:      acct.Name,
+			Currency:         acct.Currency,
+			AccountType:      acct.Product,
+			IsActive:         true,
+		}
+		if err := db.DB.Where(models.BankAccount{BankConnectionID: connection.ID, AccountID: acct.ResourceID}).
+			FirstOrCreate(&bankAccount).Error; err != nil {
+			utils.Logger.Warn("bank_sync: failed to link account during callback")
+		}
+	}
+
+	nextSync := time.Now()
+	connection.Status = "connected"
+	connection.NextSyncAt = &nextSync
+	if err := db.DB.Save(&connection).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to finalize connection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "connected", "linked_accounts": len(accounts)})
+}
+
+// SyncBankConnection triggers an on-demand transactions pull, rejecting the
+// request if it would exceed the connection's FrequencyPerDay - the same
+// throttle the background scheduler honors for its own re-syncs.
+func SyncBankConnection(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	connectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid connection id"})
+		return
+	}
+
+	var connection models.BankConnection
+	if err := db.DB.Where("id = ? AND user_id = ?", connectionID, userID).First(&connection).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bank connection not found"})
+		return
+	}
+
+	if connection.ConsentStatus != "valid" {
+		c.JSON(http.StatusConflict, gin.H{"error": "connection does not have a valid consent"})
+		return
+	}
+
+	if connection.LastSyncAt != nil && time.Since(*connection.LastSyncAt) < minSyncInterval(connection.FrequencyPerDay) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "sync frequency limit reached for this connection"})
+		return
+	}
+
+	logEntry, err := syncConnection(&connection)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "sync failed: " + err.Error()})
+		return
+	}
+
+	recordBankAuditEvent(c, userID, &connection.ID, models.BankAuditActionSynced, nil,
+		models.JSONB{"status": logEntry.Status, "transactions_found": logEntry.TransactionsFound})
+
+	c.JSON(http.StatusOK, gin.H{"sync_log": logEntry})
+}
+
+// minSyncInterval converts FrequencyPerDay into the minimum gap between two
+// syncs; a connection with the default 4/day can sync at most every 6h.
+func minSyncInterval(frequencyPerDay int) time.Duration {
+	if frequencyPerDay <= 0 {
+		frequencyPerDay = 1
+	}
+	return 24 * time.Hour / time.Duration(frequencyPerDay)
+}
+
+// syncConnection pulls transactions for every BankAccount linked to
+// connection, writes a BankSyncLog row recording what happened, and
+// advances LastSyncAt/NextSyncAt/SyncCount. It is shared by the manual sync
+// endpoint and the background scheduler so both go through one code path.
+// A connection whose breaker (see services/banksync) is open after too many
+// consecutive failures is rejected up front, before any network call.
+func syncConnection(connection *models.BankConnection) (models.BankSyncLog, error) {
+	if !banksync.AllowSync(connection.ID) {
+		return models.BankSyncLog{}, fmt.Errorf("circuit breaker open for connection %d", connection.ID)
+	}
+
+	started := time.Now()
+	client := psd2.NewClient(connection.BankEndpoint)
+
+	var bankAccounts []models.BankAccount
+	if err := db.DB.Where("bank_connection_id = ? AND is_active = ?", connection.ID, true).Find(&bankAccounts).Error; err != nil {
+		return models.BankSyncLog{}, err
+	}
+
+	found, added, updated, apiCalls, succeeded := 0, 0, 0, 0, 0
+	var syncErr error
+
+	for _, bankAccount := range bankAccounts {
+		since := started.AddDate(0, 0, -30)
+		if bankAccount.LastTransactionSync != nil {
+			since = *bankAccount.LastTransactionSync
+		}
+
+		transactions, err := client.Transactions(connection.ConsentID, bankAccount.AccountID, since)
+		apiCalls++
+		if err != nil {
+			syncErr = err
+			continue
+		}
+		succeeded++
+
+		found += len(transactions)
+		accAdded, accUpdated := ingestBankTransactions(connection.UserID, bankAccount, transactions)
+		added += accAdded
+		updated += accUpdated
+
+		now := time.Now()
+		accountUpdates := map[string]interface{}{"last_transaction_sync": &now}
+		if len(transactions) > 0 {
+			accountUpdates["last_sync_cursor"] = transactions[len(transactions)-1].TransactionID
+		}
+		db.DB.Model(&bankAccount).Updates(accountUpdates)
+	}
+
+	status := "success"
+	errMessage := ""
+	if syncErr != nil {
+		status = "partial"
+		if succeeded == 0 {
+			status = "failed"
+		}
+		errMessage = syncErr.Error()
+	}
+
+	logEntry := models.BankSyncLog{
+		BankConnectionID:    connection.ID,
+		SyncType:            "transactions",
+		Status:              status,
+		TransactionsFound:   found,
+		TransactionsAdded:   added,
+		TransactionsUpdated: updated,
+		ErrorMessage:        errMessage,
+		APICallsUsed:        apiCalls,
+		SyncDuration:        int(time.Since(started).Milliseconds()),
+	}
+	if err := db.DB.Create(&logEntry).Error; err != nil {
+		return models.BankSyncLog{}, err
+	}
+	banksync.RecordResult(connection.ID, status != "failed")
+
+	now := time.Now()
+	next := now.Add(minSyncInterval(connection.FrequencyPerDay))
+	db.DB.Model(connection).Updates(map[string]interface{}{
+		"last_sync_at": &now,
+		"next_sync_at": &next,
+		"sync_count":   connection.SyncCount + 1,
+	})
+
+	return logEntry, syncErr
+}
+
+// ingestBankTransactions idempotently reconciles transactions fetched for
+// bankAccount against models.Transaction: it computes a stable ExternalID
+// per incoming transaction, batch-loads the ExternalHash of any rows that
+// already exist for those IDs, and only inserts the missing ones or updates
+// the ones whose hash changed. Re-running a sync over the same window is a
+// no-op, so it never double-counts spend against a budget.
+func ingestBankTransactions(userID uint, bankAccount models.BankAccount, transactions []psd2.Transaction) (added, updated int) {
+	if bankAccount.InternalAccountID == nil || len(transactions) == 0 {
+		return 0, 0
+	}
+
+	externalIDs := make([]string, len(transactions))
+	hashes := make([]string, len(transactions))
+	for i, txn := range transactions {
+		externalIDs[i] = externalTransactionID(bankAccount.AccountID, txn.TransactionID)
+		hashes[i] = externalTransactionHash(txn)
+	}
+
+	var existing []models.Transaction
+	db.DB.Select("external_id", "external_hash").
+		Where("user_id = ? AND external_id IN ?", userID, externalIDs).
+		Find(&existing)
+
+	existingHash := make(map[string]string, len(existing))
+	for _, e := range existing {
+		if e.ExternalID != nil {
+			existingHash[*e.ExternalID] = e.ExternalHash
+		}
+	}
+
+	for i, txn := range transactions {
+		externalID, hash := externalIDs[i], hashes[i]
+
+		priorHash, known := existingHash[externalID]
+		if !known {
+			if createBankTransaction(userID, bankAccount, txn, externalID, hash) {
+				added++
+			}
+			continue
+		}
+		if priorHash != hash {
+			if updateBankTransaction(externalID, txn, hash) {
+				updated++
+			}
+		}
+	}
+	return added, updated
+}
+
+// externalTransactionID is the stable, bank-txn-independent-of-us identity
+// a sync uses to recognize the same transaction across runs.
+func externalTransactionID(bankAccountExternalID, bankTxnID string) string {
+	sum := sha256.Sum256([]byte(bankAccountExternalID + bankTxnID))
+	return hex.EncodeToString(sum[:])
+}
+
+// externalTransactionHash covers every field a bank might correct after the
+// fact, so a re-sync can tell "already ingested, unchanged" apart from
+// "already ingested, needs updating".
+func externalTransactionHash(txn psd2.Transaction) string {
+	sum := sha256.Sum256([]byte(txn.Amount.Amount + "|" + txn.BookingDate + "|" + txn.RemittanceInformationUnstructured + "|" + txn.Counterparty()))
+	return hex.EncodeToString(sum[:])
+}
+
+func createBankTransaction(userID uint, bankAccount models.BankAccount, txn psd2.Transaction, externalID, hash string) bool {
+	amount, err := parseAmount(txn.Amount.Amount)
+	if err != nil {
+		return false
+	}
+	txnDate, err := time.Parse("2006-01-02", txn.BookingDate)
+	if err != nil {
+		txnDate = time.Now()
+	}
+
+	bankTxnID := txn.TransactionID
+	transaction := models.Transaction{
+		UserID:            userID,
+		AccountID:         *bankAccount.InternalAccountID,
+		Amount:            amount,
+		Description:       txn.RemittanceInformationUnstructured,
+		TxnDate:           txnDate,
+		BankTransactionID: &bankTxnID,
+		ExternalID:        &externalID,
+		ExternalHash:      hash,
+	}
+	return db.DB.Create(&transaction).Error == nil
+}
+
+func updateBankTransaction(externalID string, txn psd2.Transaction, hash string) bool {
+	amount, err := parseAmount(txn.Amount.Amount)
+	if err != nil {
+		return false
+	}
+	txnDate, err := time.Parse("2006-01-02", txn.BookingDate)
+	if err != nil {
+		txnDate = time.Now()
+	}
+
+	err = db.DB.Model(&models.Transaction{}).Where("external_id = ?", externalID).Updates(map[string]interface{}{
+		"amount":        amount,
+		"description":   txn.RemittanceInformationUnstructured,
+		"txn_date":      txnDate,
+		"external_hash": hash,
+	}).Error
+	return err == nil
+}
+
+// parseAmount converts a Berlin Group decimal amount string (e.g.
+// "-123.45") into a decimal.Decimal, the same representation the Plaid
+// sync now stores transaction amounts in.
+func parseAmount(amount string) (decimal.Decimal, error) {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("psd2: unparseable amount %q: %w", amount, err)
+	}
+	return value, nil
+}
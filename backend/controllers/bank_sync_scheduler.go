@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+)
+
+// StartBankSyncScheduler launches a background goroutine that re-syncs
+// "valid" bank connections whose NextSyncAt has passed, and expires
+// connections whose ConsentValidUntil has lapsed.
+func StartBankSyncScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			expireLapsedConsents()
+			runDueBankSyncs()
+		}
+	}()
+}
+
+func expireLapsedConsents() {
+	now := time.Now()
+	err := db.DB.Model(&models.BankConnection{}).
+		Where("consent_status = ? AND consent_valid_until <= ?", "valid", now).
+		Updates(map[string]interface{}{"consent_status": "expired", "status": "expired"}).Error
+	if err != nil {
+		utils.Logger.Warn("bank sync scheduler: failed to expire lapsed consents")
+	}
+}
+
+func runDueBankSyncs() {
+	var due []models.BankConnection
+	now := time.Now()
+
+	err := db.DB.Where("consent_status = ? AND next_sync_at IS NOT NULL AND next_sync_at <= ?", "valid", now).Find(&due).Error
+	if err != nil {
+		utils.Logger.Warn("bank sync scheduler: failed to load due connections")
+		return
+	}
+
+	for i := range due {
+		if _, err := syncConnection(&due[i]); err != nil {
+			utils.Logger.Warn("bank sync scheduler: sync failed for a connection")
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/psd2"
+	"Personal-Finance-Tracker-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// bankWebhookPayload is the JSON body of every inbound bank-initiated
+// webhook, across every provider: ConsentID identifies the BankConnection
+// the event belongs to (and so, which webhook_secret verifies it);
+// AccountID/Balance/Threshold are only populated for the event types that
+// need them.
+type bankWebhookPayload struct {
+	EventID   string `json:"event_id"`
+	EventType string `json:"event_type"` // consent.revoked, transaction.created, balance.threshold
+	ConsentID string `json:"consent_id"`
+	AccountID string `json:"account_id,omitempty"`
+	Balance   string `json:"balance,omitempty"`
+	Threshold string `json:"threshold,omitempty"`
+}
+
+// ReceiveBankWebhook handles POST /api/banks/webhooks/:provider, the
+// server-to-server push PSD2 ASPSPs and Plaid use for events that can't
+// wait for the next poll: a revoked consent, a new transaction, or a
+// crossed balance threshold. The signature is verified against the
+// per-connection secret in BankConnection.Metadata["webhook_secret"]
+// (mirrors deliverWebhook's X-Anomaly-Signature on the way out), and every
+// accepted event is deduped on (provider, event_id) before any of its side
+// effects run, so a retry of an event we already accepted is a no-op. The
+// actual work is dispatched onto a buffered channel and applied by
+// bankWebhookWorkerLoop so this handler can always answer well within the
+// provider's retry window, the same shape middleware/auth_audit.go uses to
+// keep login/register off the audit write's critical path.
+func ReceiveBankWebhook(c *gin.Context) {
+	provider := c.Param("provider")
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+		return
+	}
+
+	var payload bankWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil || payload.EventID == "" || payload.EventType == "" || payload.ConsentID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook payload"})
+		return
+	}
+
+	var connection models.BankConnection
+	if err := db.DB.Where("consent_id = ?", payload.ConsentID).First(&connection).Error; err != nil {
+		// Nothing to verify a signature against and nothing to act on - ack
+		// so the provider doesn't keep retrying an item we don't recognize.
+		c.JSON(http.StatusOK, gin.H{"acknowledged": true})
+		return
+	}
+
+	secret, _ := connection.Metadata["webhook_secret"].(string)
+	if secret == "" || !validBankWebhookSignature(c, body, secret) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid webhook signature"})
+		return
+	}
+
+	event := models.BankWebhookEvent{Provider: provider, EventID: payload.EventID}
+	result := db.DB.Where(models.BankWebhookEvent{Provider: provider, EventID: payload.EventID}).
+		Attrs(models.BankWebhookEvent{EventType: payload.EventType, BankConnectionID: &connection.ID}).
+		FirstOrCreate(&event)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record webhook event"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		// Already recorded - a retry that arrived before our original 2xx
+		// reached the provider, not a new event.
+		c.JSON(http.StatusOK, gin.H{"acknowledged": true})
+		return
+	}
+
+	enqueueBankWebhookJob(bankWebhookJob{EventID: event.ID, Payload: payload, ConnectionID: connection.ID})
+
+	c.JSON(http.StatusOK, gin.H{"acknowledged": true})
+}
+
+// validBankWebhookSignature checks the X-Webhook-Signature header (an
+// HTTP-header analogue of the X-Anomaly-Signature deliverWebhook sends on
+// the way out) as a constant-time HMAC-SHA256 compare over the raw body,
+// the same hmac.Equal idiom verifyConfirmToken uses.
+func validBankWebhookSignature(c *gin.Context, body []byte, secret string) bool {
+	sig := c.GetHeader("X-Webhook-Signature")
+	if sig == "" {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(sig), []byte(expected))
+}
+
+// bankWebhookBufferSize bounds how many accepted webhook events the worker
+// will queue before enqueueBankWebhookJob starts dropping them rather than
+// blocking ReceiveBankWebhook.
+const bankWebhookBufferSize = 256
+
+// bankWebhookJob is one accepted, deduped BankWebhookEvent still waiting to
+// have its side effects applied.
+type bankWebhookJob struct {
+	EventID      uint
+	Payload      bankWebhookPayload
+	ConnectionID uint
+}
+
+var (
+	bankWebhookCh     chan bankWebhookJob
+	bankWebhookWg     sync.WaitGroup
+	bankWebhookOnce   sync.Once
+	bankWebhookClosed bool
+	bankWebhookMu     sync.Mutex
+)
+
+// StartBankWebhookWorker starts the background goroutine that applies
+// accepted bank webhook events. It is a no-op if already started - safe to
+// call once from main at startup.
+func StartBankWebhookWorker() {
+	bankWebhookOnce.Do(func() {
+		bankWebhookCh = make(chan bankWebhookJob, bankWebhookBufferSize)
+		bankWebhookWg.Add(1)
+		go bankWebhookWorkerLoop()
+	})
+}
+
+// StopBankWebhookWorker closes the job channel and blocks until the worker
+// has drained every event still queued. Call it once, after the HTTP
+// server has stopped accepting new requests.
+func StopBankWebhookWorker() {
+	bankWebhookMu.Lock()
+	if bankWebhookCh == nil || bankWebhookClosed {
+		bankWebhookMu.Unlock()
+		return
+	}
+	bankWebhookClosed = true
+	close(bankWebhookCh)
+	bankWebhookMu.Unlock()
+
+	bankWebhookWg.Wait()
+}
+
+func bankWebhookWorkerLoop() {
+	defer bankWebhookWg.Done()
+	for job := range bankWebhookCh {
+		processBankWebhookJob(job)
+	}
+}
+
+// enqueueBankWebhookJob hands job to the background worker. It never
+// blocks the caller: if the worker hasn't been started (e.g. in tests) the
+// job runs inline instead, and if the buffer is full it's dropped and
+// logged so a slow DB can't back up the webhook endpoint into the
+// provider's retry storm it exists to avoid.
+func enqueueBankWebhookJob(job bankWebhookJob) {
+	bankWebhookMu.Lock()
+	ch := bankWebhookCh
+	closed := bankWebhookClosed
+	bankWebhookMu.Unlock()
+
+	if ch == nil || closed {
+		processBankWebhookJob(job)
+		return
+	}
+
+	select {
+	case ch <- job:
+	default:
+		utils.Logger.Warn("bank webhook buffer full, dropping event",
+			zap.String("event_type", job.Payload.EventType),
+			zap.Uint("connection_id", job.ConnectionID),
+		)
+	}
+}
+
+// processBankWebhookJob dispatches job by event type and marks the
+// BankWebhookEvent row processed once its side effects have run.
+func processBankWebhookJob(job bankWebhookJob) {
+	var connection models.BankConnection
+	if err := db.DB.First(&connection, job.ConnectionID).Error; err != nil {
+		utils.Logger.Warn("bank webhook: connection vanished before processing", zap.Uint("connection_id", job.ConnectionID))
+		return
+	}
+
+	switch job.Payload.EventType {
+	case models.BankWebhookEventConsentRevoked:
+		if err := db.DB.Model(&connection).Updates(map[string]interface{}{
+			"consent_status": "revoked",
+			"status":         "expired",
+		}).Error; err != nil {
+			utils.Logger.Warn("bank webhook: failed to revoke consent", zap.Uint("connection_id", connection.ID), zap.Error(err))
+		}
+	case models.BankWebhookEventTransactionCreated:
+		processBankWebhookTransactionCreated(job.Payload, &connection)
+	case models.BankWebhookEventBalanceThreshold:
+		processBankWebhookBalanceThreshold(job.Payload, &connection)
+	}
+
+	now := time.Now()
+	db.DB.Model(&models.BankWebhookEvent{}).Where("id = ?", job.EventID).Update("processed_at", &now)
+}
+
+// processBankWebhookTransactionCreated resyncs only the BankAccount the
+// event names, instead of the full syncConnection sweep every linked
+// account - a transaction.created push already tells us which account
+// changed.
+func processBankWebhookTransactionCreated(payload bankWebhookPayload, connection *models.BankConnection) {
+	if payload.AccountID == "" {
+		return
+	}
+
+	var bankAccount models.BankAccount
+	if err := db.DB.Where("bank_connection_id = ? AND account_id = ?", connection.ID, payload.AccountID).First(&bankAccount).Error; err != nil {
+		utils.Logger.Warn("bank webhook: transaction.created for unknown account",
+			zap.Uint("connection_id", connection.ID), zap.String("account_id", payload.AccountID))
+		return
+	}
+
+	if err := syncWebhookBankAccount(connection, &bankAccount); err != nil {
+		utils.Logger.Warn("bank webhook: targeted sync failed",
+			zap.Uint("connection_id", connection.ID), zap.String("account_id", payload.AccountID), zap.Error(err))
+	}
+}
+
+// syncWebhookBankAccount pulls and ingests transactions for a single
+// BankAccount, the same one-account-at-a-time logic syncConnection's loop
+// runs for every account on a connection, reused here for a webhook
+// targeting just one of them.
+func syncWebhookBankAccount(connection *models.BankConnection, bankAccount *models.BankAccount) error {
+	since := time.Now().AddDate(0, 0, -30)
+	if bankAccount.LastTransactionSync != nil {
+		since = *bankAccount.LastTransactionSync
+	}
+
+	transactions, err := psd2.NewClient(connection.BankEndpoint).Transactions(connection.ConsentID, bankAccount.AccountID, since)
+	if err != nil {
+		return err
+	}
+
+	ingestBankTransactions(connection.UserID, *bankAccount, transactions)
+
+	now := time.Now()
+	accountUpdates := map[string]interface{}{"last_transaction_sync": &now}
+	if len(transactions) > 0 {
+		accountUpdates["last_sync_cursor"] = transactions[len(transactions)-1].TransactionID
+	}
+	return db.DB.Model(bankAccount).Updates(accountUpdates).Error
+}
+
+// processBankWebhookBalanceThreshold records a BankBalanceAlert row for the
+// caller's UI to surface, rather than triggering a sync - a crossed
+// threshold is informational, not new data to pull.
+func processBankWebhookBalanceThreshold(payload bankWebhookPayload, connection *models.BankConnection) {
+	alert := models.BankBalanceAlert{
+		UserID:           connection.UserID,
+		BankConnectionID: connection.ID,
+		AccountID:        payload.AccountID,
+		Balance:          payload.Balance,
+		Threshold:        payload.Threshold,
+	}
+	if err := db.DB.Create(&alert).Error; err != nil {
+		utils.Logger.Warn("bank webhook: failed to record balance alert", zap.Uint("connection_id", connection.ID), zap.Error(err))
+	}
+}
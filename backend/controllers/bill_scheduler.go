@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+)
+
+// StartBillScheduler launches a background goroutine that periodically
+// scans for bills whose NextDueDate has passed and emits a reminder
+// BillPayment row (Reminder true) for each, so the frontend can surface an
+// upcoming/overdue-bills feed without polling every Bill row itself. It
+// never pays a bill automatically - that still requires the user to hit
+// POST /api/bills/:id/pay.
+func StartBillScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runDueBillReminders()
+		}
+	}()
+}
+
+// runDueBillReminders creates one reminder BillPayment per due bill that
+// doesn't already have a reminder for the current NextDueDate, so restarting
+// the process between ticks can't double up on reminders.
+func runDueBillReminders() {
+	now := time.Now()
+
+	var due []models.Bill
+	if err := db.DB.Where("next_due_date IS NOT NULL AND next_due_date <= ?", now).Find(&due).Error; err != nil {
+		utils.Logger.Warn("bill scheduler: failed to load due bills")
+		return
+	}
+
+	for _, bill := range due {
+		var existing models.BillPayment
+		err := db.DB.Where("bill_id = ? AND reminder = ? AND paid_at >= ?", bill.ID, true, bill.NextDueDate).
+			First(&existing).Error
+		if err == nil {
+			continue // already reminded for this due date
+		}
+
+		reminder := models.BillPayment{
+			BillID:   bill.ID,
+			PaidAt:   *bill.NextDueDate,
+			Reminder: true,
+		}
+		if err := db.DB.Create(&reminder).Error; err != nil {
+			utils.Logger.Warn("bill scheduler: failed to create reminder")
+		}
+	}
+}
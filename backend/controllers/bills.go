@@ -0,0 +1,314 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/recurring"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// findBillCategory looks up one of userID's own categories by
+// case-insensitive name (e.g. "utilities", "subscription"), the same kind
+// of best-effort auto-categorization matchPlaidCategory/matchByMerchantName
+// do for bank-synced transactions. Returns nil rather than an error when
+// nothing matches, since a bill's categorization is a convenience, not a
+// requirement.
+func findBillCategory(userID uint, name string) *uint {
+	var category models.Category
+	if err := db.DB.Where("user_id = ? AND LOWER(name) = ?", userID, strings.ToLower(name)).First(&category).Error; err != nil {
+		return nil
+	}
+	categoryID := category.ID
+	return &categoryID
+}
+
+// GetBillVendors lists the bill vendor catalog, optionally filtered to one
+// ?category= (e.g. "utilities", "subscription").
+func GetBillVendors(c *gin.Context) {
+	query := db.DB.Model(&models.BillVendor{})
+	if category := c.Query("category"); category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	var vendors []models.BillVendor
+	if err := query.Order("name ASC").Find(&vendors).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch bill vendors"})
+		return
+	}
+
+	c.JSON(http.StatusOK, vendors)
+}
+
+// GetBillVendorProducts lists the payable products a BillVendor offers.
+func GetBillVendorProducts(c *gin.Context) {
+	vendorID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vendor ID"})
+		return
+	}
+
+	var vendor models.BillVendor
+	if err := db.DB.First(&vendor, vendorID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bill vendor not found"})
+		return
+	}
+
+	var products []models.BillProduct
+	if err := db.DB.Where("bill_vendor_id = ?", vendorID).Order("name ASC").Find(&products).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch bill vendor products"})
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+// LookupBill validates a customer/account number against a vendor before a
+// Bill is created from it, the way a real bill-pay integration would
+// confirm the ref exists before the user commits to paying through it.
+// This tree has no real vendor integrations to call out to, so it's a
+// stub: any non-blank customer_ref against a known vendor is "found".
+func LookupBill(c *gin.Context) {
+	var input struct {
+		BillVendorID uint   `json:"bill_vendor_id" binding:"required"`
+		CustomerRef  string `json:"customer_ref" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var vendor models.BillVendor
+	if err := db.DB.First(&vendor, input.BillVendorID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bill vendor not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"found":        true,
+		"bill_vendor":  vendor,
+		"customer_ref": input.CustomerRef,
+	})
+}
+
+// GetBills lists the authenticated user's bills, soonest due first.
+func GetBills(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var bills []models.Bill
+	if err := db.DB.Where("user_id = ?", userID).
+		Preload("BillVendor").Preload("BillProduct").Preload("Account").
+		Order("next_due_date ASC, id ASC").Find(&bills).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch bills"})
+		return
+	}
+
+	c.JSON(http.StatusOK, bills)
+}
+
+// CreateBill registers a new bill the authenticated user wants to pay
+// through Account, optionally on a recurring RRule schedule.
+func CreateBill(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input struct {
+		BillVendorID  uint   `json:"bill_vendor_id" binding:"required"`
+		BillProductID *uint  `json:"bill_product_id"`
+		CustomerRef   string `json:"customer_ref"`
+		Nickname      string `json:"nickname"`
+		AmountCents   int64  `json:"amount_cents" binding:"required"`
+		AccountID     uint   `json:"account_id" binding:"required"`
+		IsRecurring   bool   `json:"is_recurring"`
+		RRule         string `json:"rrule"`
+		NextDueDate   string `json:"next_due_date"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var vendor models.BillVendor
+	if err := db.DB.First(&vendor, input.BillVendorID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bill vendor not found"})
+		return
+	}
+
+	if input.BillProductID != nil {
+		var product models.BillProduct
+		if err := db.DB.Where("id = ? AND bill_vendor_id = ?", *input.BillProductID, input.BillVendorID).First(&product).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bill product not found for this vendor"})
+			return
+		}
+	}
+
+	var account models.Account
+	if err := db.DB.Where("id = ? AND user_id = ?", input.AccountID, userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account not found or does not belong to user"})
+		return
+	}
+
+	if input.IsRecurring {
+		if _, err := recurring.Parse(input.RRule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rrule: " + err.Error()})
+			return
+		}
+	}
+
+	bill := models.Bill{
+		UserID:        userID,
+		BillVendorID:  input.BillVendorID,
+		BillProductID: input.BillProductID,
+		CustomerRef:   input.CustomerRef,
+		Nickname:      input.Nickname,
+		AmountCents:   input.AmountCents,
+		AccountID:     input.AccountID,
+		IsRecurring:   input.IsRecurring,
+		RRule:         input.RRule,
+	}
+
+	if input.NextDueDate != "" {
+		nextDue, err := time.Parse("2006-01-02", input.NextDueDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid next_due_date format, use YYYY-MM-DD"})
+			return
+		}
+		bill.NextDueDate = &nextDue
+	}
+
+	if err := db.DB.Create(&bill).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create bill"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, bill)
+}
+
+// PayBill debits Bill's Account for AmountCents (or the caller's override,
+// for a partial or larger-than-usual payment), posting a categorized
+// Transaction the same way MaterializeRecurringRule does and recording a
+// BillPayment. A recurring bill's NextDueDate is advanced by its RRule
+// instead of the bill being closed out.
+func PayBill(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	billID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bill ID"})
+		return
+	}
+
+	var input struct {
+		AmountCents int64  `json:"amount_cents"`
+		TxnDate     string `json:"txn_date"`
+	}
+	_ = c.ShouldBindJSON(&input)
+
+	var payment models.BillPayment
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		var bill models.Bill
+		if err := tx.Preload("BillVendor").Where("id = ? AND user_id = ?", billID, userID).First(&bill).Error; err != nil {
+			return err
+		}
+
+		amountCents := bill.AmountCents
+		if input.AmountCents != 0 {
+			amountCents = input.AmountCents
+		}
+
+		paidAt := time.Now()
+		if input.TxnDate != "" {
+			parsed, err := time.Parse("2006-01-02", input.TxnDate)
+			if err != nil {
+				return err
+			}
+			paidAt = parsed
+		}
+
+		categoryID := findBillCategory(userID, bill.BillVendor.Category)
+
+		transaction := models.Transaction{
+			UserID:      userID,
+			AccountID:   bill.AccountID,
+			CategoryID:  categoryID,
+			Amount:      decimal.NewFromInt(-amountCents).Div(decimal.NewFromInt(100)),
+			Description: "Bill payment: " + bill.Nickname,
+			TxnDate:     paidAt,
+		}
+		if err := tx.Create(&transaction).Error; err != nil {
+			return err
+		}
+
+		var total decimal.NullDecimal
+		tx.Model(&models.Transaction{}).
+			Where("account_id = ?", bill.AccountID).
+			Select("COALESCE(SUM(amount), 0)").
+			Scan(&total)
+
+		var account models.Account
+		if err := tx.First(&account, bill.AccountID).Error; err != nil {
+			return err
+		}
+		newBalance := decimal.NewFromInt(account.InitialBalanceCents).Div(decimal.NewFromInt(100)).Add(total.Decimal)
+		if err := tx.Model(&account).Update("current_balance", newBalance).Error; err != nil {
+			return err
+		}
+
+		payment = models.BillPayment{
+			BillID:        bill.ID,
+			TransactionID: &transaction.ID,
+			AmountCents:   amountCents,
+			PaidAt:        paidAt,
+		}
+		if err := tx.Create(&payment).Error; err != nil {
+			return err
+		}
+
+		bill.LastPaidAt = &paidAt
+		if bill.IsRecurring {
+			parsed, err := recurring.Parse(bill.RRule)
+			if err == nil {
+				from := paidAt
+				if bill.NextDueDate != nil {
+					from = *bill.NextDueDate
+				}
+				next := parsed.Next(from)
+				bill.NextDueDate = &next
+			}
+		} else {
+			bill.NextDueDate = nil
+		}
+
+		return tx.Save(&bill).Error
+	})
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to pay bill: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
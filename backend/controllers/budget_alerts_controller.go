@@ -0,0 +1,277 @@
+package controllers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/notifier"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// defaultBudgetAlertCooldown is how long evaluateBudgetAlerts suppresses a
+// refiring of a BudgetAlert whose CooldownSeconds is unset (0).
+const defaultBudgetAlertCooldown = 24 * time.Hour
+
+// budgetAlertNotifier resolves the notifier.Notifier a BudgetAlert's
+// Channel delivers through, or nil for Channel=in_app, whose
+// BudgetAlertEvent row is itself the notification. A package variable
+// (rather than a plain function) so tests can swap in a fake Notifier the
+// same way TransactionStore/BudgetProgressStore are swapped.
+var budgetAlertNotifier = func(alert models.BudgetAlert) notifier.Notifier {
+	switch alert.Channel {
+	case models.BudgetAlertChannelEmail:
+		return notifier.NewEmailNotifier()
+	case models.BudgetAlertChannelWebhook:
+		return notifier.NewWebhookNotifier(alert.Secret)
+	default:
+		return nil
+	}
+}
+
+// createBudgetAlertInput is the CreateBudgetAlert request body.
+type createBudgetAlertInput struct {
+	ThresholdPercent float64 `json:"threshold_percent" binding:"required,gt=0"`
+	Channel          string  `json:"channel" binding:"required"`
+	Target           string  `json:"target"`
+	Secret           string  `json:"secret"`
+	CooldownSeconds  int64   `json:"cooldown_seconds"`
+}
+
+// CreateBudgetAlert registers a threshold on one of the authenticated
+// user's BudgetItems. evaluateBudgetAlerts (run from GetBudgets) fires it
+// the next time that item's spend crosses ThresholdPercent.
+func CreateBudgetAlert(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	budgetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget ID"})
+		return
+	}
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget item ID"})
+		return
+	}
+
+	var item models.BudgetItem
+	if err := db.DB.Joins("JOIN budgets ON budgets.id = budget_items.budget_id").
+		Where("budget_items.id = ? AND budget_items.budget_id = ? AND budgets.user_id = ?", itemID, budgetID, userID).
+		First(&item).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget item not found"})
+		return
+	}
+
+	var input createBudgetAlertInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel := models.BudgetAlertChannel(input.Channel)
+	switch channel {
+	case models.BudgetAlertChannelEmail, models.BudgetAlertChannelWebhook, models.BudgetAlertChannelInApp:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "channel must be email, webhook, or in_app"})
+		return
+	}
+	if channel == models.BudgetAlertChannelWebhook && input.Target == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target is required for webhook alerts"})
+		return
+	}
+
+	alert := models.BudgetAlert{
+		UserID:           userID,
+		BudgetItemID:     item.ID,
+		ThresholdPercent: input.ThresholdPercent,
+		Channel:          channel,
+		Target:           input.Target,
+		Secret:           input.Secret,
+		CooldownSeconds:  input.CooldownSeconds,
+	}
+	if err := db.DB.Create(&alert).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create budget alert"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, alert)
+}
+
+// GetBudgetAlerts lists every BudgetAlert the authenticated user has
+// configured, across all of their budgets.
+func GetBudgetAlerts(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var alerts []models.BudgetAlert
+	if err := db.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&alerts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch budget alerts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
+
+// DeleteBudgetAlert removes one of the authenticated user's BudgetAlerts.
+func DeleteBudgetAlert(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	alertID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget alert ID"})
+		return
+	}
+
+	result := db.DB.Where("id = ? AND user_id = ?", alertID, userID).Delete(&models.BudgetAlert{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete budget alert"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget alert not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "budget alert deleted successfully"})
+}
+
+// evaluateBudgetAlerts checks budgetItem's configured BudgetAlerts against
+// its just-computed progress, delivering (and recording, for idempotency
+// and the in-app feed) any whose ThresholdPercent this item has crossed and
+// whose CooldownSeconds has elapsed since it last fired. Delivery and
+// persistence errors are logged rather than returned, so a notifier
+// failure never blocks GetBudgets from rendering the budget it's attached
+// to.
+func evaluateBudgetAlerts(userID uint, budgetItem models.BudgetItem, progress float64, spentCents, plannedCents int64, periodStart, periodEnd time.Time) {
+	var alerts []models.BudgetAlert
+	if err := db.DB.Where("user_id = ? AND budget_item_id = ?", userID, budgetItem.ID).Find(&alerts).Error; err != nil {
+		log.Printf("evaluateBudgetAlerts: failed to load alerts for budget item %d: %v", budgetItem.ID, err)
+		return
+	}
+	if len(alerts) == 0 {
+		return
+	}
+
+	payload := notifier.Alert{
+		BudgetItemID:     budgetItem.ID,
+		CategoryName:     budgetItem.Category.Name,
+		ProgressPercent:  progress,
+		SpentCents:       spentCents,
+		PlannedCents:     plannedCents,
+		PeriodStart:      periodStart,
+		PeriodEnd:        periodEnd,
+	}
+
+	for _, alert := range alerts {
+		if progress < alert.ThresholdPercent {
+			continue
+		}
+
+		cooldown := defaultBudgetAlertCooldown
+		if alert.CooldownSeconds > 0 {
+			cooldown = time.Duration(alert.CooldownSeconds) * time.Second
+		}
+		if alert.LastFiredAt != nil && time.Since(*alert.LastFiredAt) < cooldown {
+			continue
+		}
+
+		payload.ThresholdPercent = alert.ThresholdPercent
+		event := models.BudgetAlertEvent{
+			UserID:           userID,
+			BudgetAlertID:    alert.ID,
+			PeriodStart:      periodStart,
+			ThresholdPercent: alert.ThresholdPercent,
+			Message:          payload.Message(),
+		}
+
+		// The unique index on (budget_alert_id, period_start,
+		// threshold_percent) makes this Create the idempotency check: a
+		// second evaluation of the same crossing within the same period
+		// fails here and is skipped rather than notifying twice.
+		if err := db.DB.Create(&event).Error; err != nil {
+			continue
+		}
+		middleware.IncrementBudgetBreached()
+
+		if n := budgetAlertNotifier(alert); n != nil {
+			target := alert.Target
+			if alert.Channel == models.BudgetAlertChannelEmail && target == "" {
+				var user models.User
+				if err := db.DB.Select("email").First(&user, userID).Error; err == nil {
+					target = user.Email
+				}
+			}
+			if err := n.Notify(target, payload); err != nil {
+				log.Printf("evaluateBudgetAlerts: delivery failed for alert %d: %v", alert.ID, err)
+			}
+		}
+
+		now := time.Now()
+		if err := db.DB.Model(&models.BudgetAlert{}).Where("id = ?", alert.ID).Update("last_fired_at", now).Error; err != nil {
+			log.Printf("evaluateBudgetAlerts: failed to record last_fired_at for alert %d: %v", alert.ID, err)
+		}
+	}
+}
+
+// GetNotificationsStream replays the authenticated user's unread
+// BudgetAlertEvents as Server-Sent Events - one "alert" event per unread
+// row, oldest first, then a final "done" event - the same "compute once,
+// replay as a finite sequence" pattern GetSpendingPatternsStream/
+// GetBudgetPredictionStream use, since there's no websocket/pub-sub
+// infrastructure here for genuine live push.
+func GetNotificationsStream(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var events []models.BudgetAlertEvent
+	if err := db.DB.Where("user_id = ? AND read_at IS NULL", userID).
+		Order("created_at ASC").
+		Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch notifications"})
+		return
+	}
+
+	sseEvents := make([]sseEvent, 0, len(events)+1)
+	for _, event := range events {
+		sseEvents = append(sseEvents, sseEvent{name: "alert", data: event})
+	}
+	sseEvents = append(sseEvents, sseEvent{name: "done", data: gin.H{"count": len(events)}})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	i := 0
+	c.Stream(func(w io.Writer) bool {
+		writeSSEEvent(w, i+1, sseEvents[i])
+		i++
+		return i < len(sseEvents)
+	})
+}
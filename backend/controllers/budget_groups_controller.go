@@ -0,0 +1,367 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// isBudgetGroupMember reports whether userID belongs to groupID.
+func isBudgetGroupMember(groupID, userID uint) bool {
+	var count int64
+	db.DB.Model(&models.BudgetGroupMember{}).
+		Where("group_id = ? AND user_id = ?", groupID, userID).
+		Count(&count)
+	return count > 0
+}
+
+// CreateBudgetGroup handles POST /budget-groups: creates a new shared-budget
+// group owned by the authenticated user, who is enrolled as its first
+// member at weight 1.
+func CreateBudgetGroup(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group := models.BudgetGroup{Name: input.Name, OwnerID: userID}
+	if err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&group).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.BudgetGroupMember{GroupID: group.ID, UserID: userID, Weight: 1}).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create budget group"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// GetBudgetGroups handles GET /budget-groups: lists every group the
+// authenticated user belongs to.
+func GetBudgetGroups(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var memberships []models.BudgetGroupMember
+	if err := db.DB.Where("user_id = ?", userID).Find(&memberships).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch budget groups"})
+		return
+	}
+	groupIDs := make([]uint, len(memberships))
+	for i, m := range memberships {
+		groupIDs[i] = m.GroupID
+	}
+
+	var groups []models.BudgetGroup
+	if err := db.DB.Where("id IN ?", groupIDs).Preload("Members.User").Find(&groups).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch budget groups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// GetBudgetGroup handles GET /budget-groups/:id, restricted to members.
+func GetBudgetGroup(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	groupID := c.Param("id")
+
+	var group models.BudgetGroup
+	if err := db.DB.Preload("Members.User").First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget group not found"})
+		return
+	}
+	if !isBudgetGroupMember(group.ID, userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not a member of this budget group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// AddGroupMember handles POST /budget-groups/:id/members: only the group's
+// owner may enroll another user, optionally at a weight other than the
+// default 1 (see BudgetGroupMember.Weight).
+func AddGroupMember(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	groupID := c.Param("id")
+
+	var group models.BudgetGroup
+	if err := db.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget group not found"})
+		return
+	}
+	if group.OwnerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the group owner can add members"})
+		return
+	}
+
+	var input struct {
+		UserID uint    `json:"user_id" binding:"required"`
+		Weight float64 `json:"weight"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Weight <= 0 {
+		input.Weight = 1
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, input.UserID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	member := models.BudgetGroupMember{GroupID: group.ID, UserID: input.UserID, Weight: input.Weight}
+	if err := db.DB.Create(&member).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "user is already a member of this budget group"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// RemoveGroupMember handles DELETE /budget-groups/:id/members/:user_id:
+// only the owner may remove a member, and the owner can't remove
+// themselves without first deleting the group.
+func RemoveGroupMember(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	groupID := c.Param("id")
+	memberUserID := c.Param("user_id")
+
+	var group models.BudgetGroup
+	if err := db.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget group not found"})
+		return
+	}
+	if group.OwnerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the group owner can remove members"})
+		return
+	}
+	if memberUserID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+
+	if err := db.DB.Where("group_id = ? AND user_id = ?", group.ID, memberUserID).
+		Delete(&models.BudgetGroupMember{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member removed"})
+}
+
+// memberBalance is one BudgetGroupMember's position within a
+// GetGroupBalances response.
+type memberBalance struct {
+	UserID uint            `json:"user_id"`
+	Name   string          `json:"name"`
+	Weight float64         `json:"weight"`
+	Paid   decimal.Decimal `json:"paid"`
+	Share  decimal.Decimal `json:"share"`
+	Net    decimal.Decimal `json:"net"` // positive = owed money, negative = owes money
+}
+
+// settlement is a single "X owes Y $N" transfer GetGroupBalances proposes
+// to zero out every member's Net with as few payments as possible.
+type settlement struct {
+	FromUserID uint            `json:"from_user_id"`
+	ToUserID   uint            `json:"to_user_id"`
+	Amount     decimal.Decimal `json:"amount"`
+}
+
+// GetGroupBalances handles GET /budget-groups/:id/balances?from=&to=: sums
+// every TransactionSplitShared transaction tagged with this group in the
+// date range, allocates each member's fair share by
+// BudgetGroupMember.Weight, and proposes a minimal set of settlements via a
+// greedy largest-creditor/largest-debtor pairing.
+func GetGroupBalances(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	groupID := c.Param("id")
+
+	var group models.BudgetGroup
+	if err := db.DB.Preload("Members.User").First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget group not found"})
+		return
+	}
+	if !isBudgetGroupMember(group.ID, userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not a member of this budget group"})
+		return
+	}
+
+	query := db.DB.Model(&models.Transaction{}).
+		Where("budget_group_id = ? AND split_kind = ?", group.ID, models.TransactionSplitShared)
+
+	if from := c.Query("from"); from != "" {
+		if fromDate, err := time.Parse("2006-01-02", from); err == nil {
+			query = query.Where("txn_date >= ?", fromDate)
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if toDate, err := time.Parse("2006-01-02", to); err == nil {
+			query = query.Where("txn_date <= ?", toDate)
+		}
+	}
+
+	var transactions []models.Transaction
+	if err := query.Find(&transactions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch shared transactions"})
+		return
+	}
+
+	var groupWeight float64
+	paidByUser := make(map[uint]decimal.Decimal)
+	for _, m := range group.Members {
+		groupWeight += m.Weight
+		paidByUser[m.UserID] = decimal.Zero
+	}
+
+	totalShared := decimal.Zero
+	for _, txn := range transactions {
+		amount := txn.Amount.Abs()
+		paidByUser[txn.UserID] = paidByUser[txn.UserID].Add(amount)
+		totalShared = totalShared.Add(amount)
+	}
+
+	balances := make([]memberBalance, 0, len(group.Members))
+	for _, m := range group.Members {
+		share := decimal.Zero
+		if groupWeight > 0 {
+			share = totalShared.Mul(decimal.NewFromFloat(m.Weight)).Div(decimal.NewFromFloat(groupWeight))
+		}
+		paid := paidByUser[m.UserID]
+		balances = append(balances, memberBalance{
+			UserID: m.UserID,
+			Name:   m.User.Name,
+			Weight: m.Weight,
+			Paid:   paid,
+			Share:  share,
+			Net:    paid.Sub(share),
+		})
+	}
+
+	settlements := settleGroupBalances(balances)
+
+	pairwise := make([]gin.H, len(settlements))
+	byUser := make(map[uint]string, len(balances))
+	for _, b := range balances {
+		byUser[b.UserID] = b.Name
+	}
+	for i, s := range settlements {
+		pairwise[i] = gin.H{
+			"from_user_id": s.FromUserID,
+			"from_name":    byUser[s.FromUserID],
+			"to_user_id":   s.ToUserID,
+			"to_name":      byUser[s.ToUserID],
+			"amount":       s.Amount,
+			"summary":      byUser[s.FromUserID] + " owes " + byUser[s.ToUserID],
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"group_id":     group.ID,
+		"total_shared": totalShared,
+		"balances":     balances,
+		"settlements":  pairwise,
+	})
+}
+
+// settleGroupBalances computes a minimal set of transfers that zeroes out
+// every balance's NetCents, by repeatedly pairing the largest creditor
+// (highest positive net) with the largest debtor (lowest negative net)
+// until both run out. This doesn't minimize the transfer count optimally in
+// every case, but matches every member's fair share with at most
+// len(balances)-1 payments.
+func settleGroupBalances(balances []memberBalance) []settlement {
+	type party struct {
+		userID uint
+		amount decimal.Decimal // positive for both creditors and debtors; sign tracked separately
+	}
+
+	var creditors, debtors []party
+	for _, b := range balances {
+		if b.Net.IsPositive() {
+			creditors = append(creditors, party{b.UserID, b.Net})
+		} else if b.Net.IsNegative() {
+			debtors = append(debtors, party{b.UserID, b.Net.Neg()})
+		}
+	}
+
+	sort.Slice(creditors, func(i, j int) bool { return creditors[i].amount.GreaterThan(creditors[j].amount) })
+	sort.Slice(debtors, func(i, j int) bool { return debtors[i].amount.GreaterThan(debtors[j].amount) })
+
+	var settlements []settlement
+	i, j := 0, 0
+	for i < len(creditors) && j < len(debtors) {
+		creditor, debtor := &creditors[i], &debtors[j]
+		amount := creditor.amount
+		if debtor.amount.LessThan(amount) {
+			amount = debtor.amount
+		}
+		if amount.IsPositive() {
+			settlements = append(settlements, settlement{
+				FromUserID: debtor.userID,
+				ToUserID:   creditor.userID,
+				Amount:     amount,
+			})
+		}
+
+		creditor.amount = creditor.amount.Sub(amount)
+		debtor.amount = debtor.amount.Sub(amount)
+		if creditor.amount.IsZero() {
+			i++
+		}
+		if debtor.amount.IsZero() {
+			j++
+		}
+	}
+
+	return settlements
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// RolloverBudget materializes a BudgetTemplate's next period as a new
+// Budget+BudgetItem set, applying the template's RolloverMode against the
+// previous period's remaining_cents per category when one exists.
+func RolloverBudget(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget template ID"})
+		return
+	}
+
+	var template models.BudgetTemplate
+	if err := db.DB.Preload("Items").Where("id = ? AND user_id = ?", templateID, userID).First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget template not found"})
+		return
+	}
+
+	budget, err := MaterializeBudgetTemplate(&template)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, budget)
+}
+
+// MaterializeBudgetTemplate creates the template's next-period Budget and
+// BudgetItems and advances the template's LastBudgetID. Shared by the
+// manual RolloverBudget endpoint and the rollover scheduler.
+func MaterializeBudgetTemplate(template *models.BudgetTemplate) (*models.Budget, error) {
+	previousRemaining, err := previousPeriodRemaining(template)
+	if err != nil {
+		return nil, err
+	}
+
+	periodStart, periodEnd := nextTemplatePeriod(template)
+
+	budget := models.Budget{
+		UserID:      template.UserID,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Currency:    template.Currency,
+		TemplateID:  &template.ID,
+	}
+
+	var rolledOverCents int64
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&budget).Error; err != nil {
+			return err
+		}
+
+		for _, item := range template.Items {
+			planned := decimal.NewFromInt(item.PlannedCents).Div(decimal.NewFromInt(100))
+			remaining := previousRemaining[item.CategoryID]
+			carried := decimal.Zero
+
+			switch template.RolloverMode {
+			case models.BudgetRolloverCarryRemaining:
+				carried = remaining
+			case models.BudgetRolloverCarryOverspend:
+				if remaining.IsNegative() {
+					carried = remaining
+				}
+			}
+			planned = planned.Add(carried)
+			rolledOverCents += carried.Mul(decimal.NewFromInt(100)).Round(0).IntPart()
+
+			if planned.IsNegative() {
+				planned = decimal.Zero
+			}
+
+			budgetItem := models.BudgetItem{
+				BudgetID:      budget.ID,
+				CategoryID:    item.CategoryID,
+				PlannedAmount: planned,
+			}
+			if err := tx.Create(&budgetItem).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&budget).Update("rollover_unused_cents", rolledOverCents).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(template).Update("last_budget_id", budget.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	db.DB.Preload("Items.Category").First(&budget, budget.ID)
+	return &budget, nil
+}
+
+// previousPeriodRemaining looks up the template's last materialized Budget
+// and returns each category's remaining amount (planned minus actual
+// spend), empty when rollover is off or this is the template's first
+// period.
+func previousPeriodRemaining(template *models.BudgetTemplate) (map[uint]decimal.Decimal, error) {
+	remaining := make(map[uint]decimal.Decimal)
+	if template.RolloverMode == models.BudgetRolloverNone || template.LastBudgetID == nil {
+		return remaining, nil
+	}
+
+	var lastBudget models.Budget
+	if err := db.DB.Preload("Items").First(&lastBudget, *template.LastBudgetID).Error; err != nil {
+		return nil, err
+	}
+
+	for _, item := range lastBudget.Items {
+		spent := categorySpentCents(template.UserID, item.CategoryID, lastBudget.PeriodStart, lastBudget.PeriodEnd)
+		remaining[item.CategoryID] = item.PlannedAmount.Sub(decimal.NewFromInt(spent).Div(decimal.NewFromInt(100)))
+	}
+	return remaining, nil
+}
+
+// nextTemplatePeriod picks up the day after the last materialized Budget's
+// PeriodEnd, or the first of the current month if the template has never
+// been rolled over, then extends it by one Cadence.
+func nextTemplatePeriod(template *models.BudgetTemplate) (time.Time, time.Time) {
+	var start time.Time
+	if template.LastBudgetID != nil {
+		var last models.Budget
+		if err := db.DB.First(&last, *template.LastBudgetID).Error; err == nil {
+			start = last.PeriodEnd.AddDate(0, 0, 1)
+		}
+	}
+	if start.IsZero() {
+		now := time.Now()
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	}
+
+	end := addCadence(start, template.Cadence).AddDate(0, 0, -1)
+	return start, end
+}
+
+func addCadence(t time.Time, cadence models.BudgetCadence) time.Time {
+	switch cadence {
+	case models.BudgetCadenceWeekly:
+		return t.AddDate(0, 0, 7)
+	case models.BudgetCadenceQuarterly:
+		return t.AddDate(0, 3, 0)
+	case models.BudgetCadenceYearly:
+		return t.AddDate(1, 0, 0)
+	default:
+		return t.AddDate(0, 1, 0)
+	}
+}
+
+// categorySpentCents sums actual expense spend (direct transactions plus
+// transaction splits) for one category within a period, mirroring the
+// per-item spending calculation in GetBudget/GetBudgets.
+func categorySpentCents(userID, categoryID uint, periodStart, periodEnd time.Time) int64 {
+	var spent decimal.NullDecimal
+	db.DB.Model(&models.Transaction{}).
+		Where("user_id = ? AND category_id = ? AND txn_date >= ? AND txn_date <= ? AND amount < 0",
+			userID, categoryID, periodStart, periodEnd).
+		Select("COALESCE(SUM(ABS(amount)), 0)").
+		Scan(&spent)
+
+	var splitSpent decimal.NullDecimal
+	db.DB.Table("transaction_splits").
+		Joins("JOIN transactions ON transactions.id = transaction_splits.parent_txn_id").
+		Where("transactions.user_id = ? AND transaction_splits.category_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ? AND transaction_splits.amount < 0",
+			userID, categoryID, periodStart, periodEnd).
+		Select("COALESCE(SUM(ABS(transaction_splits.amount)), 0)").
+		Scan(&splitSpent)
+
+	return spent.Decimal.Add(splitSpent.Decimal).Mul(decimal.NewFromInt(100)).Round(0).IntPart()
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+)
+
+// StartBudgetRolloverScheduler launches a background goroutine that
+// periodically rolls over any BudgetTemplate whose last materialized Budget
+// period has ended (or that has never been materialized at all).
+func StartBudgetRolloverScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runDueBudgetRollovers()
+		}
+	}()
+}
+
+func runDueBudgetRollovers() {
+	var templates []models.BudgetTemplate
+	if err := db.DB.Preload("Items").Find(&templates).Error; err != nil {
+		utils.Logger.Warn("budget rollover scheduler: failed to load templates")
+		return
+	}
+
+	now := time.Now()
+	for _, template := range templates {
+		if !budgetTemplateDue(template, now) {
+			continue
+		}
+
+		template := template
+		if _, err := MaterializeBudgetTemplate(&template); err != nil {
+			utils.Logger.Warn("budget rollover scheduler: failed to materialize template")
+		}
+	}
+}
+
+func budgetTemplateDue(template models.BudgetTemplate, now time.Time) bool {
+	if template.LastBudgetID == nil {
+		return true
+	}
+
+	var last models.Budget
+	if err := db.DB.First(&last, *template.LastBudgetID).Error; err != nil {
+		return false
+	}
+	return !last.PeriodEnd.After(now)
+}
@@ -0,0 +1,307 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// GetBudgetTemplates lists the authenticated user's budget templates
+func GetBudgetTemplates(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var templates []models.BudgetTemplate
+	if err := db.DB.Preload("Items.Category").Where("user_id = ?", userID).Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch budget templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetBudgetTemplate retrieves a single budget template by ID
+func GetBudgetTemplate(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget template ID"})
+		return
+	}
+
+	var template models.BudgetTemplate
+	if err := db.DB.Preload("Items.Category").Where("id = ? AND user_id = ?", templateID, userID).First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// CreateBudgetTemplate creates a new budget template with per-category
+// planned amounts
+func CreateBudgetTemplate(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input struct {
+		Name         string                    `json:"name"`
+		Currency     string                    `json:"currency"`
+		Cadence      models.BudgetCadence      `json:"cadence" binding:"required"`
+		RolloverMode models.BudgetRolloverMode `json:"rollover_mode"`
+		Items        []struct {
+			CategoryID   uint  `json:"category_id" binding:"required"`
+			PlannedCents int64 `json:"planned_cents" binding:"required,gt=0"`
+		} `json:"items" binding:"required,min=1"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch input.Cadence {
+	case models.BudgetCadenceWeekly, models.BudgetCadenceMonthly, models.BudgetCadenceQuarterly, models.BudgetCadenceYearly:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cadence must be one of weekly, monthly, quarterly, yearly"})
+		return
+	}
+
+	rolloverMode := models.BudgetRolloverNone
+	if input.RolloverMode != "" {
+		switch input.RolloverMode {
+		case models.BudgetRolloverNone, models.BudgetRolloverCarryRemaining, models.BudgetRolloverCarryOverspend:
+			rolloverMode = input.RolloverMode
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "rollover_mode must be one of none, carry_remaining, carry_overspend"})
+			return
+		}
+	}
+
+	currency := "USD"
+	if input.Currency != "" {
+		currency = input.Currency
+	}
+
+	categoryMap := make(map[uint]bool)
+	for _, item := range input.Items {
+		if categoryMap[item.CategoryID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "duplicate category in budget template items"})
+			return
+		}
+		categoryMap[item.CategoryID] = true
+
+		var category models.Category
+		if err := db.DB.Where("id = ? AND user_id = ?", item.CategoryID, userID).First(&category).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "category not found or does not belong to user"})
+			return
+		}
+	}
+
+	template := models.BudgetTemplate{
+		UserID:       userID,
+		Name:         input.Name,
+		Currency:     currency,
+		Cadence:      input.Cadence,
+		RolloverMode: rolloverMode,
+	}
+
+	tx := db.DB.Begin()
+
+	if err := tx.Create(&template).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create budget template"})
+		return
+	}
+
+	for _, item := range input.Items {
+		templateItem := models.BudgetTemplateItem{
+			BudgetTemplateID: template.ID,
+			CategoryID:       item.CategoryID,
+			PlannedCents:     item.PlannedCents,
+		}
+		if err := tx.Create(&templateItem).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create budget template items"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit budget template"})
+		return
+	}
+
+	db.DB.Preload("Items.Category").First(&template, template.ID)
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// UpdateBudgetTemplate updates a budget template's cadence, rollover mode,
+// or planned items
+func UpdateBudgetTemplate(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget template ID"})
+		return
+	}
+
+	var template models.BudgetTemplate
+	if err := db.DB.Where("id = ? AND user_id = ?", templateID, userID).First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget template not found"})
+		return
+	}
+
+	var input struct {
+		Name         string                    `json:"name"`
+		Currency     string                    `json:"currency"`
+		Cadence      models.BudgetCadence      `json:"cadence"`
+		RolloverMode models.BudgetRolloverMode `json:"rollover_mode"`
+		Items        []struct {
+			CategoryID   uint  `json:"category_id" binding:"required"`
+			PlannedCents int64 `json:"planned_cents" binding:"required,gt=0"`
+		} `json:"items"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Name != "" {
+		template.Name = input.Name
+	}
+	if input.Currency != "" {
+		template.Currency = input.Currency
+	}
+	if input.Cadence != "" {
+		switch input.Cadence {
+		case models.BudgetCadenceWeekly, models.BudgetCadenceMonthly, models.BudgetCadenceQuarterly, models.BudgetCadenceYearly:
+			template.Cadence = input.Cadence
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cadence must be one of weekly, monthly, quarterly, yearly"})
+			return
+		}
+	}
+	if input.RolloverMode != "" {
+		switch input.RolloverMode {
+		case models.BudgetRolloverNone, models.BudgetRolloverCarryRemaining, models.BudgetRolloverCarryOverspend:
+			template.RolloverMode = input.RolloverMode
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "rollover_mode must be one of none, carry_remaining, carry_overspend"})
+			return
+		}
+	}
+
+	tx := db.DB.Begin()
+
+	if err := tx.Save(&template).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update budget template"})
+		return
+	}
+
+	if len(input.Items) > 0 {
+		if err := tx.Where("budget_template_id = ?", templateID).Delete(&models.BudgetTemplateItem{}).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update budget template items"})
+			return
+		}
+
+		for _, item := range input.Items {
+			var category models.Category
+			if err := tx.Where("id = ? AND user_id = ?", item.CategoryID, userID).First(&category).Error; err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusBadRequest, gin.H{"error": "category not found or does not belong to user"})
+				return
+			}
+
+			templateItem := models.BudgetTemplateItem{
+				BudgetTemplateID: template.ID,
+				CategoryID:       item.CategoryID,
+				PlannedCents:     item.PlannedCents,
+			}
+			if err := tx.Create(&templateItem).Error; err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create budget template items"})
+				return
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit budget template update"})
+		return
+	}
+
+	db.DB.Preload("Items.Category").First(&template, template.ID)
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteBudgetTemplate deletes a budget template and its items. It does not
+// touch any Budget already materialized from it.
+func DeleteBudgetTemplate(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget template ID"})
+		return
+	}
+
+	var template models.BudgetTemplate
+	if err := db.DB.Where("id = ? AND user_id = ?", templateID, userID).First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget template not found"})
+		return
+	}
+
+	tx := db.DB.Begin()
+
+	if err := tx.Where("budget_template_id = ?", templateID).Delete(&models.BudgetTemplateItem{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete budget template items"})
+		return
+	}
+
+	if err := tx.Delete(&template).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete budget template"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit budget template deletion"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "budget template deleted successfully"})
+}
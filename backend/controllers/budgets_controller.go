@@ -9,11 +9,22 @@ import (
 	"Personal-Finance-Tracker-backend/db"
 	"Personal-Finance-Tracker-backend/middleware"
 	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/fx"
+	"Personal-Finance-Tracker-backend/store/budgets"
 
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
 )
 
+// BudgetProgressStore is the budgets.BudgetStore implementation GetBudgets
+// uses to compute each item's spend, following the same package-variable
+// pattern as TransactionStore so tests can swap it out without
+// restructuring the router wiring.
+var BudgetProgressStore func() budgets.BudgetStore = func() budgets.BudgetStore {
+	return budgets.NewGormBudgetStore(db.DB)
+}
+
 // GetBudgets retrieves all budgets for the authenticated user
 func GetBudgets(c *gin.Context) {
 	// Step 1: Authenticate
@@ -70,9 +81,16 @@ func GetBudgets(c *gin.Context) {
 	// Add spending calculations to each budget
 	type BudgetItemWithSpending struct {
 		models.BudgetItem
-		SpentCents int64   `json:"spent_cents"`
-		Progress   float64 `json:"progress_percent"`
-		Status     string  `json:"status"`
+		// AmountCents is store/budgets.BudgetStore's cached sum of raw
+		// transaction amounts, with no cross-currency conversion applied.
+		AmountCents int64 `json:"amount_cents"`
+		// ConvertedCents is AmountCents' category converted into the
+		// budget's own currency as of each transaction's date (see
+		// categorySpentCentsConverted) - what Progress/Status/the
+		// response's totals are actually computed from.
+		ConvertedCents int64   `json:"converted_cents"`
+		Progress       float64 `json:"progress_percent"`
+		Status         string  `json:"status"`
 	}
 
 	type BudgetWithSpending struct {
@@ -88,33 +106,34 @@ func GetBudgets(c *gin.Context) {
 	var budgetsWithSpending []BudgetWithSpending
 
 	for _, budget := range budgets {
+		// ComputeProgress serves each item's spend from the same-day
+		// BudgetItemProgress cache where one exists, recomputing (and
+		// caching) the rest in one grouped query - see
+		// store/budgets.BudgetStore instead of this loop running a pair of
+		// subqueries per item.
+		spentByItem := map[uint]int64{}
+		items, err := BudgetProgressStore().ComputeProgress(c.Request.Context(), userID, budget.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute budget progress"})
+			return
+		}
+		for _, item := range items {
+			spentByItem[item.BudgetItemID] = item.SpentCents
+		}
+
 		var itemsWithSpending []BudgetItemWithSpending
 		var totalPlanned, totalSpent int64
 
 		for _, item := range budget.Items {
-			// Calculate actual spending for this category during budget period
-			var spentCents int64
-			db.DB.Model(&models.Transaction{}).
-				Where("user_id = ? AND category_id = ? AND txn_date >= ? AND txn_date <= ? AND amount_cents < 0",
-					userID, item.CategoryID, budget.PeriodStart, budget.PeriodEnd).
-				Select("COALESCE(SUM(ABS(amount_cents)), 0)").
-				Scan(&spentCents)
-
-			// Also check transaction splits
-			var splitSpent int64
-			db.DB.Table("transaction_splits").
-				Joins("JOIN transactions ON transactions.id = transaction_splits.parent_txn_id").
-				Where("transactions.user_id = ? AND transaction_splits.category_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ?",
-					userID, item.CategoryID, budget.PeriodStart, budget.PeriodEnd).
-				Select("COALESCE(SUM(ABS(transaction_splits.amount_cents)), 0)").
-				Scan(&splitSpent)
-
-			spentCents += splitSpent
-
-			// Calculate progress and status
+			plannedCents := centsOf(item.PlannedAmount)
+			amountCents := spentByItem[item.ID]
+			convertedCents := categorySpentCentsConverted(userID, item.CategoryID, budget.PeriodStart, budget.PeriodEnd, budget.Currency)
+
+			// Calculate progress and status off convertedCents, the only
+			// one of the two actually expressed in budget.Currency.
 			progress := 0.0
-			if item.PlannedCents > 0 {
-				progress = (float64(spentCents) / float64(item.PlannedCents)) * 100
+			if plannedCents > 0 {
+				progress = (float64(convertedCents) / float64(plannedCents)) * 100
 			}
 
 			status := "under_budget"
@@ -124,14 +143,17 @@ func GetBudgets(c *gin.Context) {
 				status = "on_track"
 			}
 
-			totalPlanned += item.PlannedCents
-			totalSpent += spentCents
+			totalPlanned += plannedCents
+			totalSpent += convertedCents
+
+			evaluateBudgetAlerts(userID, item, progress, convertedCents, plannedCents, budget.PeriodStart, budget.PeriodEnd)
 
 			itemsWithSpending = append(itemsWithSpending, BudgetItemWithSpending{
-				BudgetItem: item,
-				SpentCents: spentCents,
-				Progress:   progress,
-				Status:     status,
+				BudgetItem:     item,
+				AmountCents:    amountCents,
+				ConvertedCents: convertedCents,
+				Progress:       progress,
+				Status:         status,
 			})
 		}
 
@@ -176,9 +198,14 @@ func GetBudget(c *gin.Context) {
 	// Calculate actual spending for each budget item
 	type BudgetItemWithSpending struct {
 		models.BudgetItem
-		SpentCents int64   `json:"spent_cents"`
-		Remaining  int64   `json:"remaining_cents"`
-		Progress   float64 `json:"progress_percent"`
+		// AmountCents is this category's raw (non-FX-converted) spend.
+		AmountCents int64 `json:"amount_cents"`
+		// ConvertedCents is AmountCents converted into the budget's own
+		// currency as of each transaction's date - what Remaining/Progress
+		// are computed from.
+		ConvertedCents int64   `json:"converted_cents"`
+		Remaining      int64   `json:"remaining_cents"`
+		Progress       float64 `json:"progress_percent"`
 	}
 
 	type BudgetWithSpending struct {
@@ -194,51 +221,32 @@ func GetBudget(c *gin.Context) {
 	var totalSpent int64
 
 	for _, item := range budget.Items {
-		// Calculate actual spending for this category during budget period
-		// For expense transactions (negative amounts), sum the absolute values
-		var spentCents int64
-		err := db.DB.Model(&models.Transaction{}).
-			Where("user_id = ? AND category_id = ? AND txn_date >= ? AND txn_date <= ? AND amount_cents < 0",
-				userID, item.CategoryID, budget.PeriodStart, budget.PeriodEnd).
-			Select("COALESCE(SUM(ABS(amount_cents)), 0)").
-			Scan(&spentCents).Error
-
-		if err != nil {
-			log.Printf("Error calculating spending: %v", err)
-			spentCents = 0
-		}
-
-		// Also check transaction splits
-		var splitSpent int64
-		err = db.DB.Table("transaction_splits").
-			Joins("JOIN transactions ON transactions.id = transaction_splits.parent_txn_id").
-			Where("transactions.user_id = ? AND transaction_splits.category_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ? AND transaction_splits.amount_cents < 0",
-				userID, item.CategoryID, budget.PeriodStart, budget.PeriodEnd).
-			Select("COALESCE(SUM(ABS(transaction_splits.amount_cents)), 0)").
-			Scan(&splitSpent).Error
-
-		if err != nil {
-			log.Printf("Error calculating split spending: %v", err)
-			splitSpent = 0
-		}
-
-		spentCents += splitSpent
-
-		remaining := item.PlannedCents - spentCents
+		// Calculate actual spending for this category during the budget
+		// period, converting each transaction's amount from its account's
+		// currency into the budget's currency as of that transaction's date.
+		// This can't be served from store/budgets.BudgetStore's cache (used
+		// by GetBudgets): that cache sums raw amounts per category, with no
+		// per-transaction exchange rate to apply.
+		amountCents := categorySpentCents(userID, item.CategoryID, budget.PeriodStart, budget.PeriodEnd)
+		convertedCents := categorySpentCentsConverted(userID, item.CategoryID, budget.PeriodStart, budget.PeriodEnd, budget.Currency)
+		plannedCents := centsOf(item.PlannedAmount)
+
+		remaining := plannedCents - convertedCents
 		progress := 0.0
-		if item.PlannedCents > 0 {
-			progress = (float64(spentCents) / float64(item.PlannedCents)) * 100
+		if plannedCents > 0 {
+			progress = (float64(convertedCents) / float64(plannedCents)) * 100
 		}
 
 		itemsWithSpending = append(itemsWithSpending, BudgetItemWithSpending{
-			BudgetItem: item,
-			SpentCents: spentCents,
-			Remaining:  remaining,
-			Progress:   progress,
+			BudgetItem:     item,
+			AmountCents:    amountCents,
+			ConvertedCents: convertedCents,
+			Remaining:      remaining,
+			Progress:       progress,
 		})
 
-		totalPlanned += item.PlannedCents
-		totalSpent += spentCents
+		totalPlanned += plannedCents
+		totalSpent += convertedCents
 	}
 
 	response := BudgetWithSpending{
@@ -301,10 +309,14 @@ func CreateBudget(c *gin.Context) {
 	}
 
 	// Check for overlapping budgets (optional - depends on business rules)
-	// You might want one budget per month, or allow overlapping budgets
+	// You might want one budget per month, or allow overlapping budgets.
+	// Only manual (template_id IS NULL) budgets count here, since a
+	// recurring template materializing its own period via
+	// MaterializeBudgetTemplate shouldn't be blocked by - or block - a
+	// manually entered budget that happens to cover the same dates.
 	var overlappingCount int64
 	db.DB.Model(&models.Budget{}).
-		Where("user_id = ? AND ((period_start <= ? AND period_end >= ?) OR (period_start <= ? AND period_end >= ?))",
+		Where("user_id = ? AND template_id IS NULL AND ((period_start <= ? AND period_end >= ?) OR (period_start <= ? AND period_end >= ?))",
 			userID, periodStart, periodStart, periodEnd, periodEnd).
 		Count(&overlappingCount)
 
@@ -364,9 +376,9 @@ func CreateBudget(c *gin.Context) {
 	// Create budget items
 	for _, item := range input.Items {
 		budgetItem := models.BudgetItem{
-			BudgetID:     budget.ID,
-			CategoryID:   item.CategoryID,
-			PlannedCents: item.PlannedCents,
+			BudgetID:      budget.ID,
+			CategoryID:    item.CategoryID,
+			PlannedAmount: decimal.NewFromInt(item.PlannedCents).Div(decimal.NewFromInt(100)),
 		}
 		if err := tx.Create(&budgetItem).Error; err != nil {
 			tx.Rollback()
@@ -484,9 +496,9 @@ func UpdateBudget(c *gin.Context) {
 			}
 
 			budgetItem := models.BudgetItem{
-				BudgetID:     budget.ID,
-				CategoryID:   item.CategoryID,
-				PlannedCents: item.PlannedCents,
+				BudgetID:      budget.ID,
+				CategoryID:    item.CategoryID,
+				PlannedAmount: decimal.NewFromInt(item.PlannedCents).Div(decimal.NewFromInt(100)),
 			}
 			if err := tx.Create(&budgetItem).Error; err != nil {
 				tx.Rollback()
@@ -555,6 +567,141 @@ func DeleteBudget(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "budget deleted successfully"})
 }
 
+// defaultSeasonalLookbackPeriods is how many prior same-length periods
+// seasonalElapsedShare averages over when the caller doesn't override it.
+const defaultSeasonalLookbackPeriods = 6
+
+// GetBudgetForecast projects each category's end-of-period spend two ways:
+// a simple linear run-rate (spent_so_far * period_days / elapsed_days),
+// and a seasonally-adjusted projection (spent_so_far / f, where f is the
+// average fraction of total spend the last few same-length periods had
+// already accrued by the equivalent elapsed-days point - see
+// seasonalElapsedShare). A category that front-loads its spend early each
+// period gets a more realistic projection from the latter than a flat
+// linear rate would give it.
+func GetBudgetForecast(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	budgetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget ID"})
+		return
+	}
+
+	var budget models.Budget
+	if err := db.DB.
+		Preload("Items.Category").
+		Where("id = ? AND user_id = ?", budgetID, userID).
+		First(&budget).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+		return
+	}
+
+	now := time.Now()
+	periodDays := budget.PeriodEnd.Sub(budget.PeriodStart).Hours()/24 + 1
+	elapsedDays := now.Sub(budget.PeriodStart).Hours()/24 + 1
+	if elapsedDays < 1 {
+		elapsedDays = 1
+	}
+	if elapsedDays > periodDays {
+		elapsedDays = periodDays
+	}
+
+	type ItemForecast struct {
+		models.BudgetItem
+		SpentSoFarCents        int64    `json:"spent_so_far_cents"`
+		ProjectedCents         int64    `json:"projected_cents"`
+		SeasonalProjectedCents *int64   `json:"seasonal_projected_cents,omitempty"`
+		SeasonalElapsedShare   *float64 `json:"seasonal_elapsed_share,omitempty"`
+		ProjectedOverspend     bool     `json:"projected_overspend"`
+		RunRatePerDayCents     float64  `json:"run_rate_per_day_cents"`
+		DaysUntilExhausted     *float64 `json:"days_until_exhausted,omitempty"`
+		Risk                   string   `json:"risk"`
+	}
+
+	var items []ItemForecast
+	var totalSpentCents, totalLinearProjectedCents, totalSeasonalProjectedCents, totalPlannedCents int64
+	for _, item := range budget.Items {
+		spent := categorySpentCents(userID, item.CategoryID, budget.PeriodStart, now)
+		runRate := float64(spent) / elapsedDays
+		linearProjected := int64(runRate * periodDays)
+		plannedCents := centsOf(item.PlannedAmount)
+
+		// projected is the estimate risk/projected_overspend are derived
+		// from: the seasonal projection where enough history exists to
+		// compute one, the linear run-rate otherwise.
+		projected := linearProjected
+
+		var seasonalProjectedCents *int64
+		var seasonalShare *float64
+		if share, ok := seasonalElapsedShare(userID, item.CategoryID, budget.PeriodStart, periodDays, elapsedDays, defaultSeasonalLookbackPeriods); ok {
+			seasonalShare = &share
+			seasonal := int64(float64(spent) / share)
+			seasonalProjectedCents = &seasonal
+			projected = seasonal
+		}
+
+		var daysUntilExhausted *float64
+		if runRate > 0 {
+			remainingDays := (float64(plannedCents) - float64(spent)) / runRate
+			if remainingDays < 0 {
+				remainingDays = 0
+			}
+			daysUntilExhausted = &remainingDays
+		}
+
+		risk := "safe"
+		if plannedCents > 0 {
+			ratio := float64(projected) / float64(plannedCents)
+			switch {
+			case ratio > 1:
+				risk = "will_exceed"
+			case ratio >= 0.9:
+				risk = "warning"
+			}
+		}
+
+		items = append(items, ItemForecast{
+			BudgetItem:             item,
+			SpentSoFarCents:        spent,
+			ProjectedCents:         linearProjected,
+			SeasonalProjectedCents: seasonalProjectedCents,
+			SeasonalElapsedShare:   seasonalShare,
+			ProjectedOverspend:     projected > plannedCents,
+			RunRatePerDayCents:     runRate,
+			DaysUntilExhausted:     daysUntilExhausted,
+			Risk:                   risk,
+		})
+
+		totalSpentCents += spent
+		totalLinearProjectedCents += linearProjected
+		totalPlannedCents += plannedCents
+		if seasonalProjectedCents != nil {
+			totalSeasonalProjectedCents += *seasonalProjectedCents
+		} else {
+			totalSeasonalProjectedCents += linearProjected
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"budget_id":                      budget.ID,
+		"period_start":                   budget.PeriodStart,
+		"period_end":                     budget.PeriodEnd,
+		"elapsed_days":                   elapsedDays,
+		"period_days":                    periodDays,
+		"items":                          items,
+		"total_spent_so_far_cents":       totalSpentCents,
+		"total_planned_cents":            totalPlannedCents,
+		"total_projected_cents":          totalLinearProjectedCents,
+		"total_seasonal_projected_cents": totalSeasonalProjectedCents,
+	})
+}
+
 // GetCurrentBudget gets the active budget for current month
 func GetCurrentBudget(c *gin.Context) {
 	claims, exists := c.Get("user")
@@ -577,3 +724,175 @@ func GetCurrentBudget(c *gin.Context) {
 
 	c.JSON(http.StatusOK, budget)
 }
+
+// GetUpcomingBudgetPeriods previews the next period each of the
+// authenticated user's budget templates will materialize into, without
+// actually rolling any of them over.
+func GetUpcomingBudgetPeriods(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var templates []models.BudgetTemplate
+	if err := db.DB.Where("user_id = ?", userID).Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch budget templates"})
+		return
+	}
+
+	type upcomingPeriod struct {
+		TemplateID   uint                      `json:"template_id"`
+		Name         string                    `json:"name"`
+		Cadence      models.BudgetCadence      `json:"cadence"`
+		RolloverMode models.BudgetRolloverMode `json:"rollover_mode"`
+		PeriodStart  time.Time                 `json:"period_start"`
+		PeriodEnd    time.Time                 `json:"period_end"`
+	}
+
+	upcoming := make([]upcomingPeriod, 0, len(templates))
+	for _, template := range templates {
+		periodStart, periodEnd := nextTemplatePeriod(&template)
+		upcoming = append(upcoming, upcomingPeriod{
+			TemplateID:   template.ID,
+			Name:         template.Name,
+			Cadence:      template.Cadence,
+			RolloverMode: template.RolloverMode,
+			PeriodStart:  periodStart,
+			PeriodEnd:    periodEnd,
+		})
+	}
+
+	c.JSON(http.StatusOK, upcoming)
+}
+
+// RolloverBudgetByID materializes the next period for the recurring
+// template behind an existing, already-materialized Budget - a
+// budget-scoped counterpart to RolloverBudget, which takes a template ID
+// directly.
+func RolloverBudgetByID(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	budgetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget ID"})
+		return
+	}
+
+	var budget models.Budget
+	if err := db.DB.Where("id = ? AND user_id = ?", budgetID, userID).First(&budget).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+		return
+	}
+
+	if budget.TemplateID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "budget is not part of a recurring template"})
+		return
+	}
+
+	var template models.BudgetTemplate
+	if err := db.DB.Preload("Items").Where("id = ? AND user_id = ?", *budget.TemplateID, userID).First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget template not found"})
+		return
+	}
+
+	next, err := MaterializeBudgetTemplate(&template)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, next)
+}
+
+// seasonalElapsedShare averages, over the lookback same-length periods
+// immediately preceding periodStart, the fraction of each period's total
+// categoryID spend that had already occurred by its own elapsedDays-th
+// day. GetBudgetForecast divides the current period's spend-so-far by
+// this fraction instead of by a flat elapsedDays/periodDays ratio, so a
+// category whose spend is historically front- or back-loaded within its
+// period (rent on the 1st, a subscription renewal on the 28th) gets a
+// projection shaped like its own history instead of a straight line.
+// Returns ok=false when none of the lookback periods had any spend to
+// compute a fraction from, so the caller can fall back to the linear
+// projection.
+func seasonalElapsedShare(userID, categoryID uint, periodStart time.Time, periodDays, elapsedDays float64, lookback int) (float64, bool) {
+	periodLength := int(periodDays)
+	elapsedLength := int(elapsedDays)
+
+	var fractions []float64
+	for i := 1; i <= lookback; i++ {
+		histStart := periodStart.AddDate(0, 0, -periodLength*i)
+		histEnd := histStart.AddDate(0, 0, periodLength-1)
+		histElapsedEnd := histStart.AddDate(0, 0, elapsedLength-1)
+
+		total := categorySpentCents(userID, categoryID, histStart, histEnd)
+		if total <= 0 {
+			continue
+		}
+		partial := categorySpentCents(userID, categoryID, histStart, histElapsedEnd)
+		fractions = append(fractions, float64(partial)/float64(total))
+	}
+
+	if len(fractions) == 0 {
+		return 0, false
+	}
+
+	var sum float64
+	for _, f := range fractions {
+		sum += f
+	}
+	share := sum / float64(len(fractions))
+	if share <= 0 {
+		return 0, false
+	}
+	return share, true
+}
+
+// categorySpentCentsConverted sums actual expense spend (direct
+// transactions plus transaction splits) for one category within a period,
+// converting each transaction's amount from its account's currency into
+// targetCurrency as of that transaction's TxnDate before summing. A
+// transaction whose rate can't be resolved is skipped rather than failing
+// the whole budget, since a single bad/missing fx day shouldn't blank out
+// the rest of the period's spend.
+func categorySpentCentsConverted(userID, categoryID uint, periodStart, periodEnd time.Time, targetCurrency string) int64 {
+	type spendRow struct {
+		Amount   decimal.Decimal
+		Currency string
+		TxnDate  time.Time
+	}
+
+	var rows []spendRow
+	db.DB.Table("transactions").
+		Select("transactions.amount, accounts.currency, transactions.txn_date").
+		Joins("JOIN accounts ON accounts.id = transactions.account_id").
+		Where("transactions.user_id = ? AND transactions.category_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ? AND transactions.amount < 0",
+			userID, categoryID, periodStart, periodEnd).
+		Scan(&rows)
+
+	var splitRows []spendRow
+	db.DB.Table("transaction_splits").
+		Select("transaction_splits.amount, accounts.currency, transactions.txn_date").
+		Joins("JOIN transactions ON transactions.id = transaction_splits.parent_txn_id").
+		Joins("JOIN accounts ON accounts.id = transactions.account_id").
+		Where("transactions.user_id = ? AND transaction_splits.category_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ? AND transaction_splits.amount < 0",
+			userID, categoryID, periodStart, periodEnd).
+		Scan(&splitRows)
+
+	var total int64
+	for _, row := range append(rows, splitRows...) {
+		converted, err := fx.ConvertCents(-centsOf(row.Amount), row.Currency, targetCurrency, row.TxnDate)
+		if err != nil {
+			log.Printf("Error converting %s->%s spend on %s: %v", row.Currency, targetCurrency, row.TxnDate.Format("2006-01-02"), err)
+			continue
+		}
+		total += converted
+	}
+	return total
+}
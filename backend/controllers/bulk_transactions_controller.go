@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+)
+
+// BulkRowStatus is the per-row outcome returned by BulkCreateTransactions.
+type BulkRowStatus string
+
+const (
+	BulkRowCreated   BulkRowStatus = "created"
+	BulkRowDuplicate BulkRowStatus = "duplicate"
+	BulkRowError     BulkRowStatus = "error"
+)
+
+type bulkRowResult struct {
+	Status BulkRowStatus `json:"status"`
+	ID     uint          `json:"id,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// BulkCreateTransactions is the foundation any future OFX/QIF/CSV importer
+// needs (see controllers/import_controller.go for the file-upload path this
+// complements): it accepts a caller-parsed batch of transactions plus an
+// import_id (carried through for request tracing - the idempotency itself
+// is per row, via RemoteID) and processes the whole batch inside a single
+// db.DB.Begin() scope. Each row is validated the same way CreateTransaction
+// validates a single one (resolveTransactionAccountAndCategory); a row
+// whose (user, account, remote_id) already exists is skipped as a
+// duplicate instead of re-inserted, as moneygo's SplitAlreadyImported does,
+// so retrying a failed or partial batch never double-posts. Pass
+// ?dry_run=true to get the same per-row report without writing anything.
+func BulkCreateTransactions(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input struct {
+		ImportID     string `json:"import_id" binding:"required"`
+		Transactions []struct {
+			AccountID   uint            `json:"account_id" binding:"required"`
+			CategoryID  *uint           `json:"category_id"`
+			Amount      decimal.Decimal `json:"amount" binding:"required"`
+			Description string          `json:"description"`
+			TxnDate     string          `json:"txn_date" binding:"required"` // YYYY-MM-DD format
+			Notes       string          `json:"notes"`
+			RemoteID    string          `json:"remote_id" binding:"required"`
+		} `json:"transactions" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	tx := db.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start import"})
+		return
+	}
+
+	results := make([]bulkRowResult, 0, len(input.Transactions))
+	created := 0
+	touchedAccounts := map[uint]bool{}
+
+	for _, row := range input.Transactions {
+		var existing models.Transaction
+		if err := tx.Where("user_id = ? AND account_id = ? AND remote_id = ?", userID, row.AccountID, row.RemoteID).
+			First(&existing).Error; err == nil {
+			results = append(results, bulkRowResult{Status: BulkRowDuplicate, ID: existing.ID})
+			continue
+		}
+
+		txnDate, err := time.Parse("2006-01-02", row.TxnDate)
+		if err != nil {
+			results = append(results, bulkRowResult{Status: BulkRowError, Error: "invalid date format, use YYYY-MM-DD"})
+			continue
+		}
+
+		_, _, finalAmount, err := resolveTransactionAccountAndCategory(tx, userID, row.AccountID, row.CategoryID, row.Amount)
+		if err != nil {
+			results = append(results, bulkRowResult{Status: BulkRowError, Error: err.Error()})
+			continue
+		}
+
+		if dryRun {
+			results = append(results, bulkRowResult{Status: BulkRowCreated})
+			continue
+		}
+
+		remoteID := row.RemoteID
+		transaction := models.Transaction{
+			UserID:      userID,
+			AccountID:   row.AccountID,
+			CategoryID:  row.CategoryID,
+			Amount:      finalAmount,
+			Description: row.Description,
+			TxnDate:     txnDate,
+			Notes:       row.Notes,
+			RemoteID:    &remoteID,
+		}
+		if err := tx.Create(&transaction).Error; err != nil {
+			results = append(results, bulkRowResult{Status: BulkRowError, Error: err.Error()})
+			continue
+		}
+
+		created++
+		touchedAccounts[row.AccountID] = true
+		middleware.IncrementTransactionsCreated()
+		results = append(results, bulkRowResult{Status: BulkRowCreated, ID: transaction.ID})
+	}
+
+	if dryRun {
+		tx.Rollback()
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "import_id": input.ImportID, "results": results})
+		return
+	}
+
+	for accountID := range touchedAccounts {
+		var account models.Account
+		if err := tx.First(&account, accountID).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update account balance"})
+			return
+		}
+
+		var total decimal.NullDecimal
+		tx.Model(&models.Transaction{}).
+			Where("account_id = ? AND status <> ?", accountID, models.TransactionVoided).
+			Select("COALESCE(SUM(amount), 0)").
+			Scan(&total)
+
+		newBalance := decimal.NewFromInt(account.InitialBalanceCents).Div(decimal.NewFromInt(100)).Add(total.Decimal)
+		if err := tx.Model(&account).Update("current_balance", newBalance).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update account balance"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit import"})
+		return
+	}
+
+	for _, result := range results {
+		if result.Status == BulkRowCreated && result.ID != 0 {
+			checkTransactionForAnomalies(c.Request.Context(), userID, result.ID)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"dry_run": false, "import_id": input.ImportID, "created": created, "results": results})
+}
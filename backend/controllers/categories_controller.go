@@ -1,17 +1,30 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
+	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"Personal-Finance-Tracker-backend/db"
 	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/cascade"
 
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
-// GetCategories retrieves all categories for the authenticated user.
+// GetCategories retrieves every category visible to the authenticated
+// user: their own categories plus the shared system taxonomy
+// (Category.IsSystem), minus whatever system categories they've hidden
+// and with their own display name/description overrides (see
+// CategoryOverride) applied. Pass ?include_deleted=true to also list the
+// user's own soft-deleted categories (e.g. to find something to restore).
 func GetCategories(c *gin.Context) {
 	// Extract JWT claims from context
 	claims, exists := c.Get("user")
@@ -21,31 +34,72 @@ func GetCategories(c *gin.Context) {
 	}
 
 	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	includeDeleted := c.Query("include_deleted") == "true"
 
-	var categories []models.Category
-
-	// Always filter by user_id for multi-tenancy
-	query := db.DB.Where("user_id = ?", userID)
+	applyFilters := func(query *gorm.DB) *gorm.DB {
+		if includeDeleted {
+			query = query.Unscoped()
+		}
 
-	// Allow filtering by kind (income/expense)
-	if kind := c.Query("kind"); kind != "" {
-		query = query.Where("kind = ?", kind)
-	}
+		// Allow filtering by kind (income/expense)
+		if kind := c.Query("kind"); kind != "" {
+			query = query.Where("kind = ?", kind)
+		}
 
-	// Allow filtering by parent (get children or top-level)
-	if parentID := c.Query("parent_id"); parentID != "" {
-		if parentID == "null" {
-			query = query.Where("parent_id IS NULL")
-		} else {
-			query = query.Where("parent_id = ?", parentID)
+		// Allow filtering by parent (get children or top-level)
+		if parentID := c.Query("parent_id"); parentID != "" {
+			if parentID == "null" {
+				query = query.Where("parent_id IS NULL")
+			} else {
+				query = query.Where("parent_id = ?", parentID)
+			}
 		}
+		return query
 	}
 
-	if err := query.Order("kind, name").Find(&categories).Error; err != nil {
+	var categories []models.Category
+	if err := applyFilters(db.DB.Where("user_id = ? AND is_system = ?", userID, false)).Find(&categories).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch categories"})
 		return
 	}
 
+	var systemCategories []models.Category
+	if err := applyFilters(db.DB.Where("is_system = ?", true)).Find(&systemCategories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch system categories"})
+		return
+	}
+
+	if len(systemCategories) > 0 {
+		var overrides []models.CategoryOverride
+		db.DB.Where("user_id = ?", userID).Find(&overrides)
+		overrideBySystemCategoryID := make(map[uint]models.CategoryOverride, len(overrides))
+		for _, override := range overrides {
+			overrideBySystemCategoryID[override.SystemCategoryID] = override
+		}
+
+		for _, systemCategory := range systemCategories {
+			if override, ok := overrideBySystemCategoryID[systemCategory.ID]; ok {
+				if override.Hidden {
+					continue
+				}
+				if override.DisplayName != nil {
+					systemCategory.Name = *override.DisplayName
+				}
+				if override.Description != nil {
+					systemCategory.Description = override.Description
+				}
+			}
+			categories = append(categories, systemCategory)
+		}
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Kind != categories[j].Kind {
+			return categories[i].Kind < categories[j].Kind
+		}
+		return categories[i].Name < categories[j].Name
+	})
+
 	c.JSON(http.StatusOK, categories)
 }
 
@@ -95,12 +149,14 @@ func CreateCategory(c *gin.Context) {
 		return
 	}
 
+	var parent *models.Category
 	if input.ParentID != nil {
-		var parent models.Category
-		if err := db.DB.Where("id = ? AND user_id = ?", *input.ParentID, userID).First(&parent).Error; err != nil {
+		var p models.Category
+		if err := db.DB.Where("id = ? AND user_id = ?", *input.ParentID, userID).First(&p).Error; err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "parent category not found or does not belong to user"})
 			return
 		}
+		parent = &p
 
 		// Income categories cant have expense parents
 		if parent.Kind != input.Kind {
@@ -108,18 +164,10 @@ func CreateCategory(c *gin.Context) {
 			return
 		}
 
-		// Limit to 3 levels: Category -> Subcategory -> Sub-subcategory
-		depth := 1
-		currentParentID := parent.ParentID
-		for currentParentID != nil && depth < 3 {
-			var tempParent models.Category
-			if err := db.DB.Where("id = ?", *currentParentID).First(&tempParent).Error; err != nil {
-				break
-			}
-			currentParentID = tempParent.ParentID
-			depth++
-		}
-		if depth >= 3 {
+		// Limit to 3 levels: Category -> Subcategory -> Sub-subcategory.
+		// parent.Depth counts its own ancestors, so the new category (at
+		// parent.Depth+1) must land at Depth <= 2, or it'd be a 4th level.
+		if parent.Depth >= 2 {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "category nesting too deep (max 3 levels)"})
 			return
 		}
@@ -188,14 +236,23 @@ func UpdateCategory(c *gin.Context) {
 		return
 	}
 
+	var newParent *models.Category
 	if input.ParentID != nil {
 		if *input.ParentID == category.ID {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "category cannot be its own parent"})
 			return
 		}
 
-		// Prevent: A -> B -> C -> A (circular reference)
-		if isDescendant(category.ID, *input.ParentID) {
+		var p models.Category
+		if err := db.DB.Where("id = ? AND user_id = ?", *input.ParentID, userID).First(&p).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "parent category not found or does not belong to user"})
+			return
+		}
+		newParent = &p
+
+		// Prevent: A -> B -> C -> A (circular reference). The new parent's
+		// path can never legally contain the category itself.
+		if models.CategoryIsDescendantPath(newParent.Path, category.ID) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "circular reference detected - parent cannot be a descendant"})
 			return
 		}
@@ -219,9 +276,19 @@ func UpdateCategory(c *gin.Context) {
 	if input.Description != nil {
 		category.Description = input.Description
 	}
-	category.ParentID = input.ParentID // Always update
 
-	if err := db.DB.Save(&category).Error; err != nil {
+	reparenting := (input.ParentID == nil) != (category.ParentID == nil) ||
+		(input.ParentID != nil && category.ParentID != nil && *input.ParentID != *category.ParentID)
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if reparenting {
+			if err := db.ReparentCategory(tx, &category, newParent); err != nil {
+				return err
+			}
+		}
+		return tx.Save(&category).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed top update category"})
 		return
 	}
@@ -229,6 +296,29 @@ func UpdateCategory(c *gin.Context) {
 	c.JSON(http.StatusOK, category)
 }
 
+// categoryPurgeGracePeriod is how long a soft-deleted category stays
+// restorable before the purge scheduler (see purge_scheduler.go) hard-
+// deletes it. Configurable via CATEGORY_PURGE_RETENTION_DAYS (a count of
+// days), falling back to cascade.DefaultPurgeGracePeriod - the same 7-day
+// window admin soft-deletes use - if unset or invalid.
+func categoryPurgeGracePeriod() time.Duration {
+	if raw := os.Getenv("CATEGORY_PURGE_RETENTION_DAYS"); raw != "" {
+		if days, err := strconv.Atoi(raw); err == nil && days > 0 {
+			return time.Duration(days) * 24 * time.Hour
+		}
+	}
+	return cascade.DefaultPurgeGracePeriod
+}
+
+// DeleteCategory soft-deletes a category (restorable via RestoreCategory
+// until its grace period elapses - see categoryPurgeGracePeriod), or
+// skips straight to a hard delete with ?purge=true. ?force=true cascades
+// onto subcategories, transactions, splits, and budget items that would
+// otherwise block the delete; subcategories and transactions have their
+// own soft-delete column and are tombstoned the same way the category
+// itself is, so an accidental subtree wipe can still be undone within
+// the retention window (splits/budget items have no such column and are
+// removed outright, as before).
 func DeleteCategory(c *gin.Context) {
 	claims, exists := c.Get("user")
 	if !exists {
@@ -245,6 +335,7 @@ func DeleteCategory(c *gin.Context) {
 	}
 
 	force := c.Query("force") == "true"
+	purge := c.Query("purge") == "true"
 
 	var category models.Category
 	if err := db.DB.Where("id = ? AND user_id = ?", categoryID, userID).First(&category).Error; err != nil {
@@ -252,6 +343,11 @@ func DeleteCategory(c *gin.Context) {
 		return
 	}
 
+	if reassignToStr := c.Query("reassign_to"); reassignToStr != "" {
+		reassignToDelete(c, userID, category)
+		return
+	}
+
 	var childCount int64
 	db.DB.Model(&models.Category{}).Where("parent_id = ?", categoryID).Count(&childCount)
 	if childCount > 0 && !force {
@@ -283,29 +379,301 @@ func DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	// If force delete, delete all associated data
+	purgeAfter := time.Now().Add(categoryPurgeGracePeriod())
+
+	// If force delete, tombstone whatever has a soft-delete column of its
+	// own (transactions, subcategories) rather than erase it outright.
+	// TransactionSplit/BudgetItem have no DeletedAt, so they're hard-
+	// deleted either way, same as before.
 	if force {
-		// Delete transaction splits first
 		db.DB.Where("category_id = ?", categoryID).Delete(&models.TransactionSplit{})
-
-		// Delete budget items
 		db.DB.Where("category_id = ?", categoryID).Delete(&models.BudgetItem{})
 
-		// Delete transactions
+		db.DB.Model(&models.Transaction{}).Where("category_id = ?", categoryID).Update("purge_after", purgeAfter)
 		db.DB.Where("category_id = ?", categoryID).Delete(&models.Transaction{})
 
-		// Delete subcategories
+		db.DB.Model(&models.Category{}).Where("parent_id = ?", categoryID).Update("purge_after", purgeAfter)
 		db.DB.Where("parent_id = ?", categoryID).Delete(&models.Category{})
 	}
 
+	if purge {
+		if err := db.DB.Unscoped().Delete(&category).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete category"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "category purged successfully"})
+		return
+	}
+
+	if err := db.DB.Model(&category).Update("purge_after", purgeAfter).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete category"})
+		return
+	}
 	if err := db.DB.Delete(&category).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete category"})
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{"message": "category deleted successfully", "purge_after": purgeAfter})
+}
+
+// RestoreCategory undoes a DeleteCategory soft-delete within its grace
+// period. It refuses to restore a category whose parent is still
+// soft-deleted - restoring it would otherwise resurrect it into a
+// dangling ParentID - unless ?restore_ancestors=true, which restores
+// every soft-deleted ancestor up the chain first (read off Path, the
+// same materialized-path column GetCategoryTree and CategoryAncestors
+// use).
+func RestoreCategory(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+
+	var category models.Category
+	if err := db.DB.Unscoped().Where("id = ? AND user_id = ?", categoryID, userID).First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		return
+	}
+	if !category.DeletedAt.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "category is not deleted"})
+		return
+	}
+
+	if category.ParentID != nil {
+		var parent models.Category
+		if err := db.DB.Unscoped().Where("id = ? AND user_id = ?", *category.ParentID, userID).First(&parent).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to look up parent category"})
+			return
+		}
+
+		if parent.DeletedAt.Valid {
+			if c.Query("restore_ancestors") != "true" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "parent category is still deleted; pass ?restore_ancestors=true to restore it too"})
+				return
+			}
+
+			var ancestorIDs []uint
+			for _, segment := range strings.Split(strings.Trim(category.Path, "/"), "/") {
+				if id, err := strconv.ParseUint(segment, 10, 32); err == nil && uint(id) != category.ID {
+					ancestorIDs = append(ancestorIDs, uint(id))
+				}
+			}
+			if len(ancestorIDs) > 0 {
+				if err := db.DB.Unscoped().Model(&models.Category{}).Where("id IN ? AND user_id = ?", ancestorIDs, userID).
+					Updates(map[string]interface{}{"deleted_at": nil, "purge_after": nil}).Error; err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore ancestor categories"})
+					return
+				}
+			}
+		}
+	}
+
+	if err := db.DB.Unscoped().Model(&category).Updates(map[string]interface{}{"deleted_at": nil, "purge_after": nil}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category restored successfully"})
+}
+
+// reassignToDelete handles DeleteCategory's ?reassign_to=<categoryID>
+// path: a safer alternative to ?force=true that migrates everything
+// referencing the category to another one instead of destroying it.
+// Pass ?reparent_children_to_parent=true to reparent subcategories to the
+// deleted category's own parent instead of to the reassignment target.
+func reassignToDelete(c *gin.Context, userID uint, category models.Category) {
+	reassignToID, err := strconv.ParseUint(c.Query("reassign_to"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reassign_to category ID"})
+		return
+	}
+
+	var target models.Category
+	if err := db.DB.Where("id = ? AND user_id = ?", reassignToID, userID).First(&target).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reassign_to category not found or does not belong to user"})
+		return
+	}
+	if target.ID == category.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot reassign a category to itself"})
+		return
+	}
+	if target.Kind != category.Kind {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reassign_to category must have the same kind (income/expense)"})
+		return
+	}
+
+	reparentToGrandparent := c.Query("reparent_children_to_parent") == "true"
+	newParentID := &target.ID
+	if reparentToGrandparent {
+		newParentID = category.ParentID
+	}
+
+	var reassigned struct {
+		Transactions int64 `json:"transactions"`
+		Splits       int64 `json:"splits"`
+		BudgetItems  int64 `json:"budget_items"`
+		Children     int64 `json:"children"`
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		txnResult := tx.Model(&models.Transaction{}).Where("category_id = ?", category.ID).Update("category_id", target.ID)
+		if txnResult.Error != nil {
+			return txnResult.Error
+		}
+		reassigned.Transactions = txnResult.RowsAffected
+
+		splitResult := tx.Model(&models.TransactionSplit{}).Where("category_id = ?", category.ID).Update("category_id", target.ID)
+		if splitResult.Error != nil {
+			return splitResult.Error
+		}
+		reassigned.Splits = splitResult.RowsAffected
+
+		budgetResult := tx.Model(&models.BudgetItem{}).Where("category_id = ?", category.ID).Update("category_id", target.ID)
+		if budgetResult.Error != nil {
+			return budgetResult.Error
+		}
+		reassigned.BudgetItems = budgetResult.RowsAffected
+
+		childResult := tx.Model(&models.Category{}).Where("parent_id = ?", category.ID).Updates(map[string]interface{}{"parent_id": newParentID})
+		if childResult.Error != nil {
+			return childResult.Error
+		}
+		reassigned.Children = childResult.RowsAffected
+
+		return tx.Delete(&category).Error
+	})
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reassign and delete category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "category deleted and reassigned",
+		"reassigned": reassigned,
+	})
+
 	c.JSON(http.StatusOK, gin.H{"message": "category deleted successfully"})
 }
 
+// MergeCategory moves every transaction, transaction split, budget item, and
+// child category from the :id category ("source") into target_id
+// ("target"), then deletes source. Unlike DeleteCategory's ?reassign_to=,
+// which migrates off a category that's being removed anyway, this is the
+// user-facing workflow for cleaning up duplicate categories: both must
+// belong to the caller and share a CategoryKind, and target may not be a
+// descendant of source (merging an ancestor into its own descendant would
+// leave source's other children reparented onto a node that no longer has
+// a valid ancestor chain once source is gone).
+func MergeCategory(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	sourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+
+	var input struct {
+		TargetID uint `json:"target_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var source models.Category
+	if err := db.DB.Where("id = ? AND user_id = ?", sourceID, userID).First(&source).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		return
+	}
+	var target models.Category
+	if err := db.DB.Where("id = ? AND user_id = ?", input.TargetID, userID).First(&target).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target category not found or does not belong to user"})
+		return
+	}
+	if target.ID == source.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot merge a category into itself"})
+		return
+	}
+	if target.Kind != source.Kind {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target category must have the same kind (income/expense)"})
+		return
+	}
+
+	descendants, err := db.CategoryDescendants(userID, source.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check category hierarchy"})
+		return
+	}
+	for _, descendant := range descendants {
+		if descendant.ID == target.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot merge a category into its own descendant"})
+			return
+		}
+	}
+
+	var merged struct {
+		Transactions int64 `json:"transactions"`
+		Splits       int64 `json:"splits"`
+		BudgetItems  int64 `json:"budget_items"`
+		Children     int64 `json:"children"`
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		txnResult := tx.Model(&models.Transaction{}).Where("category_id = ?", source.ID).Update("category_id", target.ID)
+		if txnResult.Error != nil {
+			return txnResult.Error
+		}
+		merged.Transactions = txnResult.RowsAffected
+
+		splitResult := tx.Model(&models.TransactionSplit{}).Where("category_id = ?", source.ID).Update("category_id", target.ID)
+		if splitResult.Error != nil {
+			return splitResult.Error
+		}
+		merged.Splits = splitResult.RowsAffected
+
+		budgetResult := tx.Model(&models.BudgetItem{}).Where("category_id = ?", source.ID).Update("category_id", target.ID)
+		if budgetResult.Error != nil {
+			return budgetResult.Error
+		}
+		merged.BudgetItems = budgetResult.RowsAffected
+
+		childResult := tx.Model(&models.Category{}).Where("parent_id = ?", source.ID).Update("parent_id", target.ID)
+		if childResult.Error != nil {
+			return childResult.Error
+		}
+		merged.Children = childResult.RowsAffected
+
+		return tx.Delete(&source).Error
+	})
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to merge category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "category merged successfully",
+		"merged":  merged,
+		"target":  target,
+	})
+}
+
 // GetCategoryUsage returns usage statistics for a category
 func GetCategoryUsage(c *gin.Context) {
 	claims, exists := c.Get("user")
@@ -348,59 +716,145 @@ func GetCategoryUsage(c *gin.Context) {
 	c.JSON(http.StatusOK, usage)
 }
 
+// CategoryNode is one node of the tree GetCategoryTree returns. SelfCents,
+// SubtreeCents, and TxnCount are only populated when ?include_totals=true
+// is passed.
+type CategoryNode struct {
+	models.Category
+	SelfCents    int64           `json:"self_cents,omitempty"`
+	SubtreeCents int64           `json:"subtree_cents,omitempty"`
+	TxnCount     int64           `json:"txn_count,omitempty"`
+	Children     []*CategoryNode `json:"children,omitempty"`
+}
+
+// GetCategoryTree assembles the user's categories into a tree. Pass
+// ?include_totals=true&from=YYYY-MM-DD&to=YYYY-MM-DD to additionally sum
+// each category's transactions over that window (defaulting to the
+// current month) and roll child totals up into their parents. Pass
+// ?max_depth=N to flatten (but not drop) everything below level N: those
+// nodes' children are cleared from the response, while their spend is
+// still folded into their ancestor's subtree_cents.
 func GetCategoryTree(c *gin.Context) {
 	claims, exists := c.Get("user")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
 	}
 
 	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
 
 	var categories []models.Category
-	db.DB.Where("used_id = ?", userID).Order("kind, name").Find(&categories)
+	db.DB.Where("user_id = ?", userID).Order("kind, name").Find(&categories)
 
-	// Nested struct
-	type CategoryNode struct {
-		models.Category
-		Children []CategoryNode `json:"children,omitempty"`
+	// Pass 1: allocate every node up front, keyed by ID.
+	categoryMap := make(map[uint]*CategoryNode, len(categories))
+	for _, cat := range categories {
+		categoryMap[cat.ID] = &CategoryNode{Category: cat}
 	}
 
-	// Pass 1: Creat all nodes in a map
-	categoryMap := make(map[uint]*CategoryNode)
-	var rootCategories []CategoryNode
-
+	// Pass 2: wire each node into its parent's Children (or treat it as a
+	// root if it has no parent, or its parent wasn't found).
+	var roots []*CategoryNode
 	for _, cat := range categories {
 		node := categoryMap[cat.ID]
 		if cat.ParentID == nil {
-			rootCategories = append(rootCategories, *node)
+			roots = append(roots, node)
+			continue
+		}
+		if parent, ok := categoryMap[*cat.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
 		} else {
-			if parent, exists := categoryMap[*cat.ParentID]; exists {
-				parent.Children = append(parent.Children, *node)
+			roots = append(roots, node)
+		}
+	}
+
+	if c.Query("include_totals") == "true" {
+		from, to, err := categoryTreeDateRange(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		type categoryTotal struct {
+			CategoryID uint
+			Total      decimal.Decimal
+			Count      int64
+		}
+		var totals []categoryTotal
+		db.DB.Model(&models.Transaction{}).
+			Select("category_id, SUM(ABS(amount)) as total, COUNT(*) as count").
+			Where("user_id = ? AND category_id IS NOT NULL AND txn_date >= ? AND txn_date <= ? AND amount < 0", userID, from, to).
+			Group("category_id").
+			Scan(&totals)
+
+		for _, total := range totals {
+			if node, ok := categoryMap[total.CategoryID]; ok {
+				node.SelfCents = centsOf(total.Total)
+				node.TxnCount = total.Count
 			}
 		}
+
+		for _, root := range roots {
+			rollUpSubtreeCents(root)
+		}
 	}
 
-	c.JSON(http.StatusOK, rootCategories)
+	if maxDepthStr := c.Query("max_depth"); maxDepthStr != "" {
+		maxDepth, err := strconv.Atoi(maxDepthStr)
+		if err != nil || maxDepth < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "max_depth must be a positive integer"})
+			return
+		}
+		pruneCategoryDepth(roots, 1, maxDepth)
+	}
+
+	c.JSON(http.StatusOK, roots)
 }
 
-// Helper Functions
-func isDescendant(categoryID, potentialDescendantID uint) bool {
-	// Base case: fetch the potential descendant
-	var category models.Category
-	if err := db.DB.Where("id = ?", potentialDescendantID).First(&category).Error; err != nil {
-		return false // Doesn't exist, cant be a descendant
+// rollUpSubtreeCents performs a post-order traversal, setting each node's
+// SubtreeCents to its own SelfCents plus every descendant's SelfCents.
+func rollUpSubtreeCents(node *CategoryNode) int64 {
+	total := node.SelfCents
+	for _, child := range node.Children {
+		total += rollUpSubtreeCents(child)
 	}
+	node.SubtreeCents = total
+	return total
+}
 
-	// If no parent, its a root category
-	if category.ParentID == nil {
-		return false
+// pruneCategoryDepth clears Children on every node at depth == maxDepth so
+// the response doesn't descend past that level; SubtreeCents (already
+// computed before pruning) still reflects what was pruned away.
+func pruneCategoryDepth(nodes []*CategoryNode, depth, maxDepth int) {
+	for _, node := range nodes {
+		if depth >= maxDepth {
+			node.Children = nil
+			continue
+		}
+		pruneCategoryDepth(node.Children, depth+1, maxDepth)
 	}
+}
 
-	// Direct child check
-	if *category.ParentID == categoryID {
-		return true // Found it
+// categoryTreeDateRange parses the from/to query params GetCategoryTree
+// accepts for ?include_totals=true, defaulting to the current month.
+func categoryTreeDateRange(c *gin.Context) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	to := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date format, use YYYY-MM-DD")
+		}
+		from = parsed
 	}
-
-	// Recursive check: Is the parent a descendant
-	return isDescendant(categoryID, *category.ParentID)
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date format, use YYYY-MM-DD")
+		}
+		to = parsed
+	}
+	return from, to, nil
 }
@@ -0,0 +1,496 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// categoryImportRow is one row of a chart-of-accounts import or export,
+// whether it came from CSV, JSON, or a built-in starter template. Path is
+// the category's full hierarchical name with segments separated by "/"
+// (e.g. "Housing/Utilities/Electric"); every segment but the last must
+// already exist, or appear earlier in the same import, as that segment's
+// category.
+//
+// ExternalID/ParentExternalID make a round-trip idempotent across
+// environments where auto-increment IDs won't line up: a row with an
+// ExternalID that already exists for the user is updated in place instead
+// of compared by name. They're optional - rows without one (e.g. the
+// built-in templates below) fall back to resolving Path against existing
+// categories exactly as before.
+type categoryImportRow struct {
+	ExternalID       string              `json:"external_id,omitempty"`
+	ParentExternalID string              `json:"parent_external_id,omitempty"`
+	Path             string              `json:"path"`
+	Kind             models.CategoryKind `json:"kind"`
+	Description      string              `json:"description,omitempty"`
+}
+
+// CategoryImportRowStatus is the per-row outcome returned by
+// ImportCategories, mirroring ImportRowStatus for transaction imports.
+type CategoryImportRowStatus string
+
+const (
+	CategoryImportRowCreated CategoryImportRowStatus = "created"
+	CategoryImportRowUpdated CategoryImportRowStatus = "updated"
+	CategoryImportRowSkipped CategoryImportRowStatus = "skipped"
+	CategoryImportRowError   CategoryImportRowStatus = "error"
+)
+
+type categoryImportRowResult struct {
+	Path   string                  `json:"path"`
+	Status CategoryImportRowStatus `json:"status"`
+	Error  string                  `json:"error,omitempty"`
+}
+
+// categoryTemplates are built-in starter charts of accounts, importable
+// via POST /api/categories/import?template=<name> so new users don't
+// have to build a taxonomy from scratch.
+var categoryTemplates = map[string][]categoryImportRow{
+	"personal": {
+		{Path: "Housing", Kind: models.CategoryExpense},
+		{Path: "Housing/Rent", Kind: models.CategoryExpense},
+		{Path: "Housing/Utilities", Kind: models.CategoryExpense},
+		{Path: "Housing/Utilities/Electric", Kind: models.CategoryExpense},
+		{Path: "Housing/Utilities/Water", Kind: models.CategoryExpense},
+		{Path: "Food", Kind: models.CategoryExpense},
+		{Path: "Food/Groceries", Kind: models.CategoryExpense},
+		{Path: "Food/Dining Out", Kind: models.CategoryExpense},
+		{Path: "Transportation", Kind: models.CategoryExpense},
+		{Path: "Transportation/Fuel", Kind: models.CategoryExpense},
+		{Path: "Transportation/Public Transit", Kind: models.CategoryExpense},
+		{Path: "Income", Kind: models.CategoryIncome},
+		{Path: "Income/Salary", Kind: models.CategoryIncome},
+		{Path: "Income/Other Income", Kind: models.CategoryIncome},
+	},
+	"freelancer": {
+		{Path: "Income", Kind: models.CategoryIncome},
+		{Path: "Income/Client Revenue", Kind: models.CategoryIncome},
+		{Path: "Income/Reimbursements", Kind: models.CategoryIncome},
+		{Path: "Business Expenses", Kind: models.CategoryExpense},
+		{Path: "Business Expenses/Software & Subscriptions", Kind: models.CategoryExpense},
+		{Path: "Business Expenses/Equipment", Kind: models.CategoryExpense},
+		{Path: "Business Expenses/Marketing", Kind: models.CategoryExpense},
+		{Path: "Taxes", Kind: models.CategoryExpense},
+		{Path: "Taxes/Estimated Taxes", Kind: models.CategoryExpense},
+		{Path: "Home Office", Kind: models.CategoryExpense},
+	},
+	"small-business": {
+		{Path: "Revenue", Kind: models.CategoryIncome},
+		{Path: "Revenue/Product Sales", Kind: models.CategoryIncome},
+		{Path: "Revenue/Service Revenue", Kind: models.CategoryIncome},
+		{Path: "Cost of Goods Sold", Kind: models.CategoryExpense},
+		{Path: "Operating Expenses", Kind: models.CategoryExpense},
+		{Path: "Operating Expenses/Payroll", Kind: models.CategoryExpense},
+		{Path: "Operating Expenses/Rent", Kind: models.CategoryExpense},
+		{Path: "Operating Expenses/Utilities", Kind: models.CategoryExpense},
+		{Path: "Operating Expenses/Marketing", Kind: models.CategoryExpense},
+		{Path: "Taxes & Licenses", Kind: models.CategoryExpense},
+	},
+}
+
+// ImportCategories bulk-creates (or, for rows carrying an ExternalID,
+// updates) a user's chart of accounts from an uploaded CSV/JSON file, or
+// from a built-in starter template when ?template=personal|freelancer|
+// small-business is given instead of a file. All rows are resolved in a
+// single transaction; the existing 3-level depth limit applies per row
+// (by duplicate-name/path for rows without an ExternalID, by parent
+// chain depth via importCategoriesByExternalID for rows that have one),
+// and a row that fails it is reported back rather than aborting the rest
+// of the import.
+func ImportCategories(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var rows []categoryImportRow
+
+	if template := c.Query("template"); template != "" {
+		tmpl, ok := categoryTemplates[template]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown template, expected personal, freelancer, or small-business"})
+			return
+		}
+		rows = tmpl
+	} else {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing upload file (or pass ?template=personal|freelancer|small-business)"})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to open upload"})
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read upload"})
+			return
+		}
+
+		format := c.Query("format")
+		if format == "" {
+			if strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".json") {
+				format = "json"
+			} else {
+				format = "csv"
+			}
+		}
+
+		rows, err = parseCategoryImport(format, data)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Preload the user's existing categories so imported rows can nest
+	// under categories that already exist, not just ones created earlier
+	// in this same import.
+	var existing []models.Category
+	db.DB.Where("user_id = ?", userID).Find(&existing)
+	byID := make(map[uint]models.Category, len(existing))
+	for _, cat := range existing {
+		byID[cat.ID] = cat
+	}
+	pathToID := make(map[string]uint, len(existing))
+	for _, cat := range existing {
+		pathToID[categoryPath(cat, byID)] = cat.ID
+	}
+	byExternalID := make(map[string]models.Category, len(existing))
+	for _, cat := range existing {
+		if cat.ExternalID != "" {
+			byExternalID[cat.ExternalID] = cat
+		}
+	}
+
+	tx := db.DB.Begin()
+	results := make([]categoryImportRowResult, 0, len(rows))
+	created := 0
+
+	// Rows that carry an ExternalID round-trip through a previous export
+	// and are upserted by that stable ID in two passes below, so a child
+	// can reference a parent that appears later in the same file. Rows
+	// without one (the built-in templates, or a plain hand-written
+	// CSV/JSON) fall back to the original path-based resolution, since
+	// there's no stable ID to upsert on.
+	var idRows, pathRows []categoryImportRow
+	for _, row := range rows {
+		if row.ExternalID != "" {
+			idRows = append(idRows, row)
+		} else {
+			pathRows = append(pathRows, row)
+		}
+	}
+
+	for _, row := range pathRows {
+		segments := strings.Split(row.Path, "/")
+		name := segments[len(segments)-1]
+
+		if len(segments) > 3 {
+			results = append(results, categoryImportRowResult{Path: row.Path, Status: CategoryImportRowError, Error: "category nesting too deep (max 3 levels)"})
+			continue
+		}
+
+		var parentID *uint
+		if len(segments) > 1 {
+			parentPath := strings.Join(segments[:len(segments)-1], "/")
+			id, ok := pathToID[parentPath]
+			if !ok {
+				results = append(results, categoryImportRowResult{Path: row.Path, Status: CategoryImportRowError, Error: "parent path not found: " + parentPath})
+				continue
+			}
+			if parent, ok := byID[id]; ok && parent.Kind != row.Kind {
+				results = append(results, categoryImportRowResult{Path: row.Path, Status: CategoryImportRowError, Error: "parent category must have the same kind (income/expense)"})
+				continue
+			}
+			parentID = &id
+		}
+
+		var existingCount int64
+		dup := tx.Model(&models.Category{}).Where("user_id = ? AND name = ? AND kind = ?", userID, name, row.Kind)
+		if parentID != nil {
+			dup = dup.Where("parent_id = ?", *parentID)
+		} else {
+			dup = dup.Where("parent_id IS NULL")
+		}
+		dup.Count(&existingCount)
+		if existingCount > 0 {
+			results = append(results, categoryImportRowResult{Path: row.Path, Status: CategoryImportRowSkipped, Error: "category already exists"})
+			continue
+		}
+
+		var description *string
+		if row.Description != "" {
+			desc := row.Description
+			description = &desc
+		}
+
+		category := models.Category{UserID: userID, Name: name, Kind: row.Kind, ParentID: parentID, Description: description}
+		if err := tx.Create(&category).Error; err != nil {
+			results = append(results, categoryImportRowResult{Path: row.Path, Status: CategoryImportRowError, Error: err.Error()})
+			continue
+		}
+
+		byID[category.ID] = category
+		pathToID[row.Path] = category.ID
+		results = append(results, categoryImportRowResult{Path: row.Path, Status: CategoryImportRowCreated})
+	}
+
+	idResults, idCreated := importCategoriesByExternalID(tx, userID, idRows, byExternalID)
+	results = append(results, idResults...)
+	created += idCreated
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit import"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"created": created, "results": results})
+}
+
+// importCategoriesByExternalID upserts rows that carry a stable
+// ExternalID, first creating or updating every row's own fields (pass
+// one), then resolving each row's ParentExternalID into a ParentID via
+// db.ReparentCategory now that every row in the batch has a database ID
+// (pass two) - which is what makes forward references to a not-yet-seen
+// parent row work regardless of file order. byExternalID indexes the
+// user's pre-existing categories; it's extended in place as rows are
+// created so later callers (e.g. ExportCategories round-tripping its own
+// output) see a consistent view.
+func importCategoriesByExternalID(tx *gorm.DB, userID uint, rows []categoryImportRow, byExternalID map[string]models.Category) ([]categoryImportRowResult, int) {
+	results := make([]categoryImportRowResult, 0, len(rows))
+	created := 0
+
+	// Pass one: upsert each row's own fields, parent-less for now.
+	for _, row := range rows {
+		segments := strings.Split(row.Path, "/")
+		name := segments[len(segments)-1]
+		if name == "" {
+			name = row.Path
+		}
+
+		var description *string
+		if row.Description != "" {
+			desc := row.Description
+			description = &desc
+		}
+
+		if existingCat, ok := byExternalID[row.ExternalID]; ok {
+			existingCat.Name = name
+			existingCat.Kind = row.Kind
+			existingCat.Description = description
+			if err := tx.Model(&models.Category{}).Where("id = ?", existingCat.ID).
+				Updates(map[string]interface{}{"name": name, "kind": row.Kind, "description": description}).Error; err != nil {
+				results = append(results, categoryImportRowResult{Path: row.Path, Status: CategoryImportRowError, Error: err.Error()})
+				continue
+			}
+			byExternalID[row.ExternalID] = existingCat
+			results = append(results, categoryImportRowResult{Path: row.Path, Status: CategoryImportRowUpdated})
+			continue
+		}
+
+		category := models.Category{UserID: userID, Name: name, Kind: row.Kind, Description: description, ExternalID: row.ExternalID}
+		if err := tx.Create(&category).Error; err != nil {
+			results = append(results, categoryImportRowResult{Path: row.Path, Status: CategoryImportRowError, Error: err.Error()})
+			continue
+		}
+		byExternalID[row.ExternalID] = category
+		created++
+		results = append(results, categoryImportRowResult{Path: row.Path, Status: CategoryImportRowCreated})
+	}
+
+	// Pass two: resolve ParentExternalID now that every row in this batch
+	// has a row in byExternalID, whatever order they appeared in.
+	for i, row := range rows {
+		if results[i].Status == CategoryImportRowError {
+			continue
+		}
+		category, ok := byExternalID[row.ExternalID]
+		if !ok {
+			continue
+		}
+
+		var newParent *models.Category
+		if row.ParentExternalID != "" {
+			parent, ok := byExternalID[row.ParentExternalID]
+			if !ok {
+				results[i] = categoryImportRowResult{Path: row.Path, Status: CategoryImportRowError, Error: "parent external_id not found: " + row.ParentExternalID}
+				continue
+			}
+			if parent.Kind != category.Kind {
+				results[i] = categoryImportRowResult{Path: row.Path, Status: CategoryImportRowError, Error: "parent category must have the same kind (income/expense)"}
+				continue
+			}
+			if parent.Depth >= 2 {
+				results[i] = categoryImportRowResult{Path: row.Path, Status: CategoryImportRowError, Error: "category nesting too deep (max 3 levels)"}
+				continue
+			}
+			newParent = &parent
+		}
+
+		if (newParent == nil) == (category.ParentID == nil) && (newParent == nil || category.ParentID == nil || *category.ParentID == newParent.ID) {
+			continue
+		}
+		if err := db.ReparentCategory(tx, &category, newParent); err != nil {
+			results[i] = categoryImportRowResult{Path: row.Path, Status: CategoryImportRowError, Error: err.Error()}
+			continue
+		}
+		byExternalID[row.ExternalID] = category
+	}
+
+	return results, created
+}
+
+// parseCategoryImport decodes CSV (columns: path, kind, description, and
+// the optional external_id/parent_external_id) or JSON (an array of
+// categoryImportRow) into import rows.
+func parseCategoryImport(format string, data []byte) ([]categoryImportRow, error) {
+	switch format {
+	case "json":
+		var rows []categoryImportRow
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return rows, nil
+	case "csv":
+		reader := csv.NewReader(bytes.NewReader(data))
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("empty CSV file")
+		}
+
+		colIndex := make(map[string]int, len(records[0]))
+		for i, col := range records[0] {
+			colIndex[strings.ToLower(strings.TrimSpace(col))] = i
+		}
+		pathCol, ok := colIndex["path"]
+		if !ok {
+			return nil, fmt.Errorf("CSV must have a 'path' column")
+		}
+		kindCol, ok := colIndex["kind"]
+		if !ok {
+			return nil, fmt.Errorf("CSV must have a 'kind' column")
+		}
+		descCol, hasDesc := colIndex["description"]
+		extIDCol, hasExtID := colIndex["external_id"]
+		parentExtIDCol, hasParentExtID := colIndex["parent_external_id"]
+
+		rows := make([]categoryImportRow, 0, len(records)-1)
+		for _, record := range records[1:] {
+			row := categoryImportRow{
+				Path: strings.TrimSpace(record[pathCol]),
+				Kind: models.CategoryKind(strings.TrimSpace(record[kindCol])),
+			}
+			if hasDesc && descCol < len(record) {
+				row.Description = strings.TrimSpace(record[descCol])
+			}
+			if hasExtID && extIDCol < len(record) {
+				row.ExternalID = strings.TrimSpace(record[extIDCol])
+			}
+			if hasParentExtID && parentExtIDCol < len(record) {
+				row.ParentExternalID = strings.TrimSpace(record[parentExtIDCol])
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("unsupported import format %q, expected csv or json", format)
+	}
+}
+
+// categoryPath builds a category's full "Parent/Child" path by walking
+// its ancestor chain through byID.
+func categoryPath(cat models.Category, byID map[uint]models.Category) string {
+	if cat.ParentID == nil {
+		return cat.Name
+	}
+	parent, ok := byID[*cat.ParentID]
+	if !ok {
+		return cat.Name
+	}
+	return categoryPath(parent, byID) + "/" + cat.Name
+}
+
+// ExportCategories returns the authenticated user's chart of accounts as
+// a downloadable file in the same shape ImportCategories accepts
+// (?format=csv or json, default csv).
+func ExportCategories(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var categories []models.Category
+	if err := db.DB.Where("user_id = ?", userID).Order("kind, name").Find(&categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch categories"})
+		return
+	}
+
+	byID := make(map[uint]models.Category, len(categories))
+	for _, cat := range categories {
+		byID[cat.ID] = cat
+	}
+
+	rows := make([]categoryImportRow, 0, len(categories))
+	for _, cat := range categories {
+		description := ""
+		if cat.Description != nil {
+			description = *cat.Description
+		}
+		parentExternalID := ""
+		if cat.ParentID != nil {
+			parentExternalID = byID[*cat.ParentID].ExternalID
+		}
+		rows = append(rows, categoryImportRow{
+			ExternalID:       cat.ExternalID,
+			ParentExternalID: parentExternalID,
+			Path:             categoryPath(cat, byID),
+			Kind:             cat.Kind,
+			Description:      description,
+		})
+	}
+
+	switch c.DefaultQuery("format", "csv") {
+	case "json":
+		c.Header("Content-Disposition", `attachment; filename="categories.json"`)
+		c.JSON(http.StatusOK, rows)
+	case "csv":
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		writer.Write([]string{"path", "kind", "description", "external_id", "parent_external_id"})
+		for _, row := range rows {
+			writer.Write([]string{row.Path, string(row.Kind), row.Description, row.ExternalID, row.ParentExternalID})
+		}
+		writer.Flush()
+
+		c.Header("Content-Disposition", `attachment; filename="categories.csv"`)
+		c.Data(http.StatusOK, "text/csv", buf.Bytes())
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported export format, expected csv or json"})
+	}
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// HideSystemCategory hides a shared system category from the
+// authenticated user's own GetCategories results via CategoryOverride.
+// It never touches the underlying system category, so it has no effect
+// on any other user.
+func HideSystemCategory(c *gin.Context) {
+	setSystemCategoryHidden(c, true)
+}
+
+// UnhideSystemCategory reverses HideSystemCategory.
+func UnhideSystemCategory(c *gin.Context) {
+	setSystemCategoryHidden(c, false)
+}
+
+func setSystemCategoryHidden(c *gin.Context, hidden bool) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+
+	if err := upsertCategoryOverride(userID, uint(categoryID), func(override *models.CategoryOverride) {
+		override.Hidden = hidden
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "system category not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update category override"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category override updated"})
+}
+
+// UpdateCategoryOverride sets the authenticated user's custom display
+// name and/or description for a shared system category, without
+// affecting the hidden flag (see HideSystemCategory/UnhideSystemCategory
+// for that). Passing a field as null clears that override back to the
+// system category's own value.
+func UpdateCategoryOverride(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+
+	var input struct {
+		DisplayName *string `json:"display_name"`
+		Description *string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var override models.CategoryOverride
+	upsertErr := upsertCategoryOverrideInto(userID, uint(categoryID), &override, func(o *models.CategoryOverride) {
+		o.DisplayName = input.DisplayName
+		o.Description = input.Description
+	})
+	if upsertErr != nil {
+		if upsertErr == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "system category not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update category override"})
+		return
+	}
+
+	c.JSON(http.StatusOK, override)
+}
+
+// upsertCategoryOverride loads or creates the (userID, categoryID)
+// CategoryOverride, applies mutate, and saves it. categoryID must name an
+// existing system category (Category.IsSystem true) or this returns
+// gorm.ErrRecordNotFound.
+func upsertCategoryOverride(userID, categoryID uint, mutate func(*models.CategoryOverride)) error {
+	var override models.CategoryOverride
+	return upsertCategoryOverrideInto(userID, categoryID, &override, mutate)
+}
+
+func upsertCategoryOverrideInto(userID, categoryID uint, override *models.CategoryOverride, mutate func(*models.CategoryOverride)) error {
+	var systemCategory models.Category
+	if err := db.DB.Where("id = ? AND is_system = ?", categoryID, true).First(&systemCategory).Error; err != nil {
+		return err
+	}
+
+	if err := db.DB.Where(models.CategoryOverride{UserID: userID, SystemCategoryID: categoryID}).
+		FirstOrCreate(override).Error; err != nil {
+		return err
+	}
+
+	mutate(override)
+	return db.DB.Save(override).Error
+}
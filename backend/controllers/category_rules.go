@@ -0,0 +1,429 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// GetCategoryRules lists the authenticated user's auto-categorization
+// rules, highest Priority first.
+func GetCategoryRules(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var rules []models.CategoryRule
+	if err := db.DB.Where("user_id = ?", userID).Order("priority DESC, id ASC").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch category rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// categoryRuleInput is the CreateCategoryRule/UpdateCategoryRule request
+// body.
+type categoryRuleInput struct {
+	Pattern        string                        `json:"pattern"`
+	IsRegex        bool                          `json:"is_regex"`
+	MatchField     models.CategoryRuleMatchField `json:"match_field"`
+	CategoryID     uint                          `json:"category_id"`
+	Priority       int                           `json:"priority"`
+	Enabled        *bool                         `json:"enabled"`
+	AmountMinCents *int64                        `json:"amount_min_cents"`
+	AmountMaxCents *int64                        `json:"amount_max_cents"`
+	MatchAccountID *uint                         `json:"match_account_id"`
+}
+
+// validateCategoryRuleInput checks input's match_field and its kind-specific
+// fields, and that CategoryID names a category belonging to userID.
+func validateCategoryRuleInput(userID uint, input categoryRuleInput) error {
+	switch input.MatchField {
+	case models.CategoryRuleMatchDescription, models.CategoryRuleMatchMerchant, models.CategoryRuleMatchMemo, models.CategoryRuleMatchPlaidCategory:
+		if strings.TrimSpace(input.Pattern) == "" {
+			return fmt.Errorf("pattern is required")
+		}
+		if input.IsRegex {
+			if _, err := regexp.Compile(input.Pattern); err != nil {
+				return fmt.Errorf("invalid regex pattern: %w", err)
+			}
+		}
+	case models.CategoryRuleMatchAmountRange:
+		if input.AmountMinCents == nil && input.AmountMaxCents == nil {
+			return fmt.Errorf("amount_min_cents and/or amount_max_cents is required")
+		}
+	case models.CategoryRuleMatchAccountID:
+		if input.MatchAccountID == nil {
+			return fmt.Errorf("match_account_id is required")
+		}
+		var account models.Account
+		if err := db.DB.Where("id = ? AND user_id = ?", *input.MatchAccountID, userID).First(&account).Error; err != nil {
+			return fmt.Errorf("match_account_id not found or does not belong to user")
+		}
+	default:
+		return fmt.Errorf("match_field must be one of description, merchant, memo, plaid_category, amount_range, account_id")
+	}
+
+	var category models.Category
+	if err := db.DB.Where("id = ? AND user_id = ?", input.CategoryID, userID).First(&category).Error; err != nil {
+		return fmt.Errorf("category not found or does not belong to user")
+	}
+	return nil
+}
+
+// CreateCategoryRule adds a new auto-categorization rule for the
+// authenticated user.
+func CreateCategoryRule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input categoryRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validateCategoryRuleInput(userID, input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	enabled := true
+	if input.Enabled != nil {
+		enabled = *input.Enabled
+	}
+
+	rule := models.CategoryRule{
+		UserID:         userID,
+		Pattern:        input.Pattern,
+		IsRegex:        input.IsRegex,
+		MatchField:     input.MatchField,
+		CategoryID:     input.CategoryID,
+		Priority:       input.Priority,
+		Enabled:        enabled,
+		AmountMinCents: input.AmountMinCents,
+		AmountMaxCents: input.AmountMaxCents,
+		MatchAccountID: input.MatchAccountID,
+	}
+	if err := db.DB.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create category rule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateCategoryRule replaces an existing rule's fields.
+func UpdateCategoryRule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	ruleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category rule ID"})
+		return
+	}
+
+	var rule models.CategoryRule
+	if err := db.DB.Where("id = ? AND user_id = ?", ruleID, userID).First(&rule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category rule not found"})
+		return
+	}
+
+	var input categoryRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validateCategoryRuleInput(userID, input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule.Pattern = input.Pattern
+	rule.IsRegex = input.IsRegex
+	rule.MatchField = input.MatchField
+	rule.CategoryID = input.CategoryID
+	rule.Priority = input.Priority
+	rule.AmountMinCents = input.AmountMinCents
+	rule.AmountMaxCents = input.AmountMaxCents
+	rule.MatchAccountID = input.MatchAccountID
+	if input.Enabled != nil {
+		rule.Enabled = *input.Enabled
+	}
+
+	if err := db.DB.Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update category rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteCategoryRule removes a rule.
+func DeleteCategoryRule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	ruleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category rule ID"})
+		return
+	}
+
+	result := db.DB.Where("id = ? AND user_id = ?", ruleID, userID).Delete(&models.CategoryRule{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete category rule"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category rule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category rule deleted successfully"})
+}
+
+// CategoryRuleMatchContext is everything a CategoryRule might need to test
+// against, gathered either from an already-persisted Transaction (see
+// categoryRuleContextFromTransaction) or, during Plaid ingestion, from a
+// not-yet-created transaction plus the raw Plaid category array Plaid
+// doesn't let us persist on Transaction itself (see
+// plaid_api.go's applyPlaidTransactionAdd).
+type CategoryRuleMatchContext struct {
+	Description     string
+	Memo            string
+	AccountID       uint
+	AmountCents     int64
+	PlaidCategories []string
+}
+
+func categoryRuleContextFromTransaction(txn models.Transaction) CategoryRuleMatchContext {
+	return CategoryRuleMatchContext{
+		Description: txn.Description,
+		Memo:        txn.Notes,
+		AccountID:   txn.AccountID,
+		AmountCents: centsOf(txn.Amount),
+	}
+}
+
+// ruleMatches reports whether rule matches ctx: a case-insensitive
+// substring (or, if IsRegex, a regular expression) match of Pattern against
+// the field MatchField selects, for the text-based match kinds; a bounds
+// check against AmountCents for CategoryRuleMatchAmountRange; or an exact
+// AccountID match for CategoryRuleMatchAccountID. An invalid regex never
+// matches rather than erroring, since CreateCategoryRule/UpdateCategoryRule
+// already reject those at write time.
+func ruleMatches(rule models.CategoryRule, ctx CategoryRuleMatchContext) bool {
+	switch rule.MatchField {
+	case models.CategoryRuleMatchAmountRange:
+		if rule.AmountMinCents != nil && ctx.AmountCents < *rule.AmountMinCents {
+			return false
+		}
+		if rule.AmountMaxCents != nil && ctx.AmountCents > *rule.AmountMaxCents {
+			return false
+		}
+		return true
+	case models.CategoryRuleMatchAccountID:
+		return rule.MatchAccountID != nil && *rule.MatchAccountID == ctx.AccountID
+	case models.CategoryRuleMatchPlaidCategory:
+		for _, plaidCategory := range ctx.PlaidCategories {
+			if patternMatches(rule, plaidCategory) {
+				return true
+			}
+		}
+		return false
+	case models.CategoryRuleMatchMemo:
+		return patternMatches(rule, ctx.Memo)
+	default: // CategoryRuleMatchDescription, CategoryRuleMatchMerchant
+		return patternMatches(rule, ctx.Description)
+	}
+}
+
+// patternMatches is the text-matching primitive ruleMatches' text-based
+// cases share.
+func patternMatches(rule models.CategoryRule, text string) bool {
+	if rule.IsRegex {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(text)
+	}
+	return strings.Contains(strings.ToLower(text), strings.ToLower(rule.Pattern))
+}
+
+// MatchCategoryRuleContext returns the CategoryID of userID's
+// highest-priority enabled rule that matches ctx, or nil if none do.
+func MatchCategoryRuleContext(userID uint, ctx CategoryRuleMatchContext) *uint {
+	var rules []models.CategoryRule
+	db.DB.Where("user_id = ? AND enabled = ?", userID, true).Order("priority DESC, id ASC").Find(&rules)
+
+	for _, rule := range rules {
+		if ruleMatches(rule, ctx) {
+			categoryID := rule.CategoryID
+			return &categoryID
+		}
+	}
+	return nil
+}
+
+// MatchCategoryRule returns the CategoryID of the highest-priority enabled
+// rule that matches an already-persisted (or about-to-be) txn, or nil if
+// none do. Plaid sync instead calls MatchCategoryRuleContext directly, so
+// a CategoryRuleMatchPlaidCategory rule can see the raw Plaid category
+// array.
+func MatchCategoryRule(userID uint, txn models.Transaction) *uint {
+	return MatchCategoryRuleContext(userID, categoryRuleContextFromTransaction(txn))
+}
+
+// ApplyCategoryRuleToTransaction sets and persists txn.CategoryID from the
+// first matching rule when txn is currently uncategorized, or always when
+// overwrite is true. It reports whether a rule matched. Called from
+// CreateTransaction/UpdateTransaction so newly-saved transactions get
+// auto-categorized, and from ApplyCategoryRulesBackfill to sweep existing
+// ones.
+func ApplyCategoryRuleToTransaction(userID uint, txn *models.Transaction, overwrite bool) bool {
+	if txn.CategoryID != nil && !overwrite {
+		return false
+	}
+
+	categoryID := MatchCategoryRule(userID, *txn)
+	if categoryID == nil {
+		return false
+	}
+
+	txn.CategoryID = categoryID
+	db.DB.Model(&models.Transaction{}).Where("id = ? AND user_id = ?", txn.ID, userID).Update("category_id", *categoryID)
+	return true
+}
+
+// ApplyCategoryRulesBackfill re-evaluates every rule against the
+// authenticated user's transactions in [from, to] (both YYYY-MM-DD,
+// defaulting to an open range), categorizing the uncategorized ones - or
+// every one of them, when ?overwrite=true.
+func ApplyCategoryRulesBackfill(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	overwrite := c.Query("overwrite") == "true"
+
+	query := db.DB.Where("user_id = ?", userID)
+	if from := c.Query("from"); from != "" {
+		fromDate, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date format, use YYYY-MM-DD"})
+			return
+		}
+		query = query.Where("txn_date >= ?", fromDate)
+	}
+	if to := c.Query("to"); to != "" {
+		toDate, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date format, use YYYY-MM-DD"})
+			return
+		}
+		query = query.Where("txn_date <= ?", toDate)
+	}
+	if !overwrite {
+		query = query.Where("category_id IS NULL")
+	}
+
+	var transactions []models.Transaction
+	if err := query.Find(&transactions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch transactions"})
+		return
+	}
+
+	categorized := 0
+	for i := range transactions {
+		if ApplyCategoryRuleToTransaction(userID, &transactions[i], overwrite) {
+			categorized++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scanned": len(transactions), "categorized": categorized})
+}
+
+// TestCategoryRule returns the first ?limit= (default 20) of the
+// authenticated user's transactions that rule :id would match, without
+// categorizing any of them - for tuning a rule's pattern before it runs
+// for real via ApplyCategoryRulesBackfill.
+func TestCategoryRule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	ruleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category rule ID"})
+		return
+	}
+
+	var rule models.CategoryRule
+	if err := db.DB.Where("id = ? AND user_id = ?", ruleID, userID).First(&rule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category rule not found"})
+		return
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	var candidates []models.Transaction
+	if err := db.DB.Where("user_id = ?", userID).Order("txn_date DESC").Find(&candidates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch transactions"})
+		return
+	}
+
+	matched := make([]models.Transaction, 0, limit)
+	for _, txn := range candidates {
+		if len(matched) >= limit {
+			break
+		}
+		if ruleMatches(rule, categoryRuleContextFromTransaction(txn)) {
+			matched = append(matched, txn)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rule_id": rule.ID, "matched_count": len(matched), "transactions": matched})
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"net/http"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/reports"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// reportsService runs Lua report scripts (see services/reports), shared
+// the same way aiService is in ai_controller.go.
+var reportsService = reports.NewService()
+
+// GetReports lists the authenticated user's saved reports (both
+// Definition- and LuaSource-backed).
+func GetReports(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var savedReports []models.Report
+	if err := db.DB.Where("user_id = ?", userID).Order("updated_at DESC").Find(&savedReports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, savedReports)
+}
+
+// UpdateReport overwrites one of the authenticated user's saved reports'
+// name and definition/lua_source, same mutual-exclusivity rule as
+// CreateReport.
+func UpdateReport(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var report models.Report
+	if err := db.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&report).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+		return
+	}
+
+	var input struct {
+		Name       string                   `json:"name" binding:"required"`
+		Definition *models.ReportDefinition `json:"definition"`
+		LuaSource  *string                  `json:"lua_source"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if (input.Definition == nil) == (input.LuaSource == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of definition or lua_source is required"})
+		return
+	}
+
+	report.Name = input.Name
+	report.LuaSource = input.LuaSource
+	if input.Definition != nil {
+		report.Definition = *input.Definition
+	} else {
+		report.Definition = models.ReportDefinition{}
+	}
+
+	if err := db.DB.Save(&report).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// DeleteReport deletes one of the authenticated user's saved reports.
+func DeleteReport(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var report models.Report
+	if err := db.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&report).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+		return
+	}
+
+	if err := db.DB.Delete(&report).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "report deleted successfully"})
+}
+
+// RunCustomReport executes a user-authored Lua script (see
+// services/reports.Service) and returns the Tabulation it produced.
+// Accepts either report_id (a previously saved LuaSource report) or an
+// inline lua_source, so the frontend's script editor can preview a draft
+// before saving it.
+func RunCustomReport(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input struct {
+		ReportID  *uint  `json:"report_id"`
+		LuaSource string `json:"lua_source"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	luaSource := input.LuaSource
+	if input.ReportID != nil {
+		var report models.Report
+		if err := db.DB.Where("id = ? AND user_id = ?", *input.ReportID, userID).First(&report).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+			return
+		}
+		if report.LuaSource == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "this report has no lua_source; run it via GET /reports/:id/run instead"})
+			return
+		}
+		luaSource = *report.LuaSource
+	}
+
+	if luaSource == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "report_id or lua_source is required"})
+		return
+	}
+
+	tab, err := reportsService.Run(c.Request.Context(), userID, luaSource)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tab)
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+)
+
+// StartDBPoolMetricsScheduler launches a background goroutine that
+// periodically republishes the primary database connection pool's
+// sql.DB.Stats() (open_connections, in_use, idle, wait_count,
+// wait_duration) as Prometheus gauges, so pool exhaustion shows up in
+// Grafana before it surfaces as request latency.
+func StartDBPoolMetricsScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			recordDBPoolMetrics()
+		}
+	}()
+}
+
+func recordDBPoolMetrics() {
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return
+	}
+	middleware.RecordDBPoolStats(sqlDB.Stats())
+}
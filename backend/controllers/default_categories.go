@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+//go:embed default_categories/*.json
+var defaultCategoryTaxonomies embed.FS
+
+// defaultCategoryRow is one entry of a locale's curated starter
+// taxonomy, addressed the same way categoryImportRow and
+// systemCategoryRow are: a "/"-separated path whose last segment is the
+// category's own name and whose earlier segments must appear earlier in
+// the same locale file.
+type defaultCategoryRow struct {
+	Path string              `json:"path"`
+	Kind models.CategoryKind `json:"kind"`
+}
+
+// loadDefaultCategoryTaxonomy reads locale's curated tree from the
+// embedded default_categories/<locale>.json, falling back to "en" if
+// that locale hasn't been translated yet.
+func loadDefaultCategoryTaxonomy(locale string) ([]defaultCategoryRow, error) {
+	if locale == "" {
+		locale = "en"
+	}
+
+	data, err := defaultCategoryTaxonomies.ReadFile("default_categories/" + locale + ".json")
+	if err != nil {
+		data, err = defaultCategoryTaxonomies.ReadFile("default_categories/en.json")
+		if err != nil {
+			return nil, fmt.Errorf("load default category taxonomy: %w", err)
+		}
+	}
+
+	var rows []defaultCategoryRow
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("parse default category taxonomy: %w", err)
+	}
+	return rows, nil
+}
+
+// SeedDefaultCategories installs the curated starter taxonomy for locale
+// (falling back to "en") into userID's chart of accounts, normally
+// called once from Register right after the account is created. It's
+// idempotent: a row whose name/kind/parent already exists for the user
+// is left alone rather than duplicated, so calling it again - e.g. via
+// POST /api/categories/seed on an existing account - is a no-op for
+// anything already seeded. Validation mirrors CreateCategory's
+// kind/depth/duplicate-name rules, though the curated tree never nests
+// deep enough to hit the 3-level cap.
+func SeedDefaultCategories(userID uint, locale string) (int, error) {
+	rows, err := loadDefaultCategoryTaxonomy(locale)
+	if err != nil {
+		return 0, err
+	}
+
+	byPath := make(map[string]uint, len(rows))
+	created := 0
+
+	for _, row := range rows {
+		segments := strings.Split(row.Path, "/")
+		name := segments[len(segments)-1]
+
+		var parentID *uint
+		if len(segments) > 1 {
+			parentPath := strings.Join(segments[:len(segments)-1], "/")
+			id, ok := byPath[parentPath]
+			if !ok {
+				return created, fmt.Errorf("default category parent path not found: %s", row.Path)
+			}
+			parentID = &id
+		}
+
+		query := db.DB.Where("user_id = ? AND name = ? AND kind = ?", userID, name, row.Kind)
+		if parentID != nil {
+			query = query.Where("parent_id = ?", *parentID)
+		} else {
+			query = query.Where("parent_id IS NULL")
+		}
+
+		var existingCategory models.Category
+		switch err := query.First(&existingCategory).Error; {
+		case err == nil:
+			byPath[row.Path] = existingCategory.ID
+			continue
+		case err != gorm.ErrRecordNotFound:
+			return created, err
+		}
+
+		category := models.Category{UserID: userID, Name: name, Kind: row.Kind, ParentID: parentID}
+		if err := db.DB.Create(&category).Error; err != nil {
+			return created, fmt.Errorf("seed default category %s: %w", row.Path, err)
+		}
+
+		byPath[row.Path] = category.ID
+		created++
+	}
+
+	return created, nil
+}
+
+// SeedCategories is the POST /api/categories/seed endpoint: it lets an
+// existing account install the curated starter taxonomy (see
+// SeedDefaultCategories) after the fact, e.g. if they deleted everything
+// and want it back, or registered before this endpoint existed.
+// ?locale= selects the taxonomy, defaulting to "en".
+func SeedCategories(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	created, err := SeedDefaultCategories(userID, c.Query("locale"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to seed default categories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"created": created})
+}
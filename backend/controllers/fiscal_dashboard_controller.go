@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+)
+
+// categoryTaxFigure is one category's gross amount within the dashboard's
+// period, plus the VATRate/IRPFRate its TaxRule (if any) applies.
+type categoryTaxFigure struct {
+	CategoryID   uint                `json:"category_id"`
+	CategoryName string              `json:"category_name"`
+	Kind         models.CategoryKind `json:"kind"`
+	GrossCents   int64               `json:"gross_cents"`
+	VATRate      float64             `json:"vat_rate"`
+	IRPFRate     float64             `json:"irpf_rate"`
+}
+
+// GetFiscalDashboard handles GET /analytics/dashboard?period=: a
+// period-preset (see resolvePeriodPreset) view of gross income, deductible
+// expenses, and estimated VAT/income-tax owed, derived from each
+// category's TaxRule.
+//
+// VAT owed is estimated as output VAT on income categories minus input VAT
+// on expense categories (the standard "VAT collected minus VAT paid"
+// liability); income tax owed is estimated by applying each category's
+// IRPFRate to its own net contribution (positive for income, negative for
+// deductible expenses) rather than a single flat rate on the total, so a
+// category taxed differently from the rest pulls the estimate in
+// proportion to its own share. Both are approximations a bookkeeper should
+// verify, not a filed return.
+func GetFiscalDashboard(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	preset := c.DefaultQuery("period", "month")
+	fromDate, toDate, err := resolvePeriodPreset(preset, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	figures := computeCategoryTaxFigures(userID, fromDate, toDate)
+
+	var grossIncomeCents, deductibleExpenseCents, vatOwedCents, incomeTaxOwedCents int64
+	for _, f := range figures {
+		switch f.Kind {
+		case models.CategoryIncome:
+			grossIncomeCents += f.GrossCents
+			vatOwedCents += int64(float64(f.GrossCents) * f.VATRate)
+			incomeTaxOwedCents += int64(float64(f.GrossCents) * f.IRPFRate)
+		default: // expense
+			deductibleExpenseCents += f.GrossCents
+			vatOwedCents -= int64(float64(f.GrossCents) * f.VATRate)
+			incomeTaxOwedCents -= int64(float64(f.GrossCents) * f.IRPFRate)
+		}
+	}
+
+	netIncomeCents := grossIncomeCents - deductibleExpenseCents - vatOwedCents - incomeTaxOwedCents
+
+	c.JSON(http.StatusOK, gin.H{
+		"period": gin.H{
+			"preset": preset,
+			"from":   fromDate.Format("2006-01-02"),
+			"to":     toDate.Format("2006-01-02"),
+		},
+		"gross_income_cents":       grossIncomeCents,
+		"deductible_expense_cents": deductibleExpenseCents,
+		"estimated_vat_owed_cents": vatOwedCents,
+		"estimated_tax_owed_cents": incomeTaxOwedCents,
+		"net_income_cents":         netIncomeCents,
+		"categories":               figures,
+	})
+}
+
+// computeCategoryTaxFigures sums each of userID's categories' activity
+// (transactions plus transaction_splits, matching computeSpendSummary's own
+// two-query approach) within [from, to], joined against that category's
+// TaxRule rates when one exists.
+func computeCategoryTaxFigures(userID uint, fromDate, toDate time.Time) []categoryTaxFigure {
+	type categoryTotal struct {
+		CategoryID   uint
+		CategoryName string
+		Kind         models.CategoryKind
+		Gross        decimal.Decimal
+	}
+
+	var totals []categoryTotal
+	db.DB.Table("transactions").
+		Select("categories.id as category_id, categories.name as category_name, categories.kind as kind, SUM(ABS(transactions.amount)) as gross").
+		Joins("JOIN categories ON categories.id = transactions.category_id").
+		Where("transactions.user_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ?", userID, fromDate, toDate).
+		Group("categories.id, categories.name, categories.kind").
+		Scan(&totals)
+
+	var splitTotals []categoryTotal
+	db.DB.Table("transaction_splits").
+		Select("categories.id as category_id, categories.name as category_name, categories.kind as kind, SUM(ABS(transaction_splits.amount)) as gross").
+		Joins("JOIN categories ON categories.id = transaction_splits.category_id").
+		Joins("JOIN transactions ON transactions.id = transaction_splits.parent_txn_id").
+		Where("transactions.user_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ?", userID, fromDate, toDate).
+		Group("categories.id, categories.name, categories.kind").
+		Scan(&splitTotals)
+
+	totalsByCategory := make(map[uint]*categoryTotal)
+	for i := range totals {
+		totalsByCategory[totals[i].CategoryID] = &totals[i]
+	}
+	for _, split := range splitTotals {
+		if existing, ok := totalsByCategory[split.CategoryID]; ok {
+			existing.Gross = existing.Gross.Add(split.Gross)
+		} else {
+			totals = append(totals, split)
+			totalsByCategory[split.CategoryID] = &split
+		}
+	}
+
+	var taxRules []models.TaxRule
+	db.DB.Where("user_id = ?", userID).Find(&taxRules)
+	ratesByCategory := make(map[uint]models.TaxRule, len(taxRules))
+	for _, rule := range taxRules {
+		ratesByCategory[rule.CategoryID] = rule
+	}
+
+	figures := make([]categoryTaxFigure, 0, len(totals))
+	for _, t := range totals {
+		rule := ratesByCategory[t.CategoryID]
+		figures = append(figures, categoryTaxFigure{
+			CategoryID:   t.CategoryID,
+			CategoryName: t.CategoryName,
+			Kind:         t.Kind,
+			GrossCents:   centsOf(t.Gross),
+			VATRate:      rule.VATRate,
+			IRPFRate:     rule.IRPFRate,
+		})
+	}
+
+	return figures
+}
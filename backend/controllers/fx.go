@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+)
+
+// findSecurity resolves one of userID's own Securities by symbol
+// (case-sensitive, matching how Account/Budget.Currency are already
+// stored uppercase). Used to resolve a ?report_currency=USD query param.
+func findSecurity(userID uint, symbol string) (*models.ReportSecurity, error) {
+	var security models.ReportSecurity
+	if err := db.DB.Where("user_id = ? AND symbol = ?", userID, symbol).First(&security).Error; err != nil {
+		return nil, err
+	}
+	return &security, nil
+}
+
+// latestRate looks up the most recent Price converting 1 unit of fromID
+// into toID on or before asOf, falling back to the inverse pair if only
+// that direction has ever been recorded. ok is false if neither direction
+// has a Price on or before asOf.
+func latestRate(fromID, toID uint, asOf time.Time) (rate decimal.Decimal, ok bool) {
+	if fromID == toID {
+		return decimal.NewFromInt(1), true
+	}
+
+	var price models.Price
+	if err := db.DB.Where("security_id = ? AND currency_id = ? AND date <= ?", fromID, toID, asOf).
+		Order("date DESC").First(&price).Error; err == nil {
+		return price.Value, true
+	}
+
+	var inverse models.Price
+	if err := db.DB.Where("security_id = ? AND currency_id = ? AND date <= ?", toID, fromID, asOf).
+		Order("date DESC").First(&inverse).Error; err == nil && !inverse.Value.IsZero() {
+		return decimal.NewFromInt(1).Div(inverse.Value), true
+	}
+
+	return decimal.Decimal{}, false
+}
+
+// convertAmount converts amount, denominated in securityID, into
+// reportCurrency using the latest Price on or before asOf. reportCurrency
+// nil means the caller didn't ask for conversion at all; securityID nil
+// means the row predates Account/Transaction.SecurityID - in both cases
+// amount is returned unconverted, same as before report_currency existed.
+// A securityID with no rate on record also falls back to amount
+// unconverted, so one missing Price doesn't fail an entire report.
+func convertAmount(amount decimal.Decimal, securityID *uint, asOf time.Time, reportCurrency *models.ReportSecurity) decimal.Decimal {
+	if reportCurrency == nil || securityID == nil {
+		return amount
+	}
+	rate, ok := latestRate(*securityID, reportCurrency.ID, asOf)
+	if !ok {
+		return amount
+	}
+	return amount.Mul(rate)
+}
+
+// periodKeyFor computes the same period bucket computeCashflow's SQL-side
+// dateFormat would, in Go - needed for the report_currency path, which
+// folds converted amounts per-transaction instead of letting SQL GROUP BY
+// do it. "week" matches the SQL paths' Monday-anchored week.
+func periodKeyFor(t time.Time, groupBy string) string {
+	switch groupBy {
+	case "day":
+		return t.Format("2006-01-02")
+	case "week":
+		offset := (int(t.Weekday()) + 6) % 7
+		return t.AddDate(0, 0, -offset).Format("2006-01-02")
+	case "year":
+		return t.Format("2006")
+	default:
+		return t.Format("2006-01")
+	}
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/services/fx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFxRates returns the day-by-day base->quote rate for every date between
+// from and to (inclusive), fetching and caching any missing days from the
+// configured fx.Provider.
+func GetFxRates(c *gin.Context) {
+	base := c.Query("base")
+	quote := c.Query("quote")
+	if base == "" || quote == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "base and quote are required"})
+		return
+	}
+
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+	if fromStr == "" || toStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required, use YYYY-MM-DD"})
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date, use YYYY-MM-DD"})
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date, use YYYY-MM-DD"})
+		return
+	}
+
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be on or after from"})
+		return
+	}
+
+	type RateOnDate struct {
+		Date string  `json:"date"`
+		Rate float64 `json:"rate"`
+	}
+
+	var rates []RateOnDate
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		rate, err := fx.RateOn(base, quote, d)
+		if err != nil {
+			continue
+		}
+		rates = append(rates, RateOnDate{Date: d.Format("2006-01-02"), Rate: rate})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"base":  base,
+		"quote": quote,
+		"rates": rates,
+	})
+}
+
+// RefreshFxRates forces an immediate fx.RefreshNow() rather than waiting
+// for the background refresher's next tick - useful right after adding a
+// new account/budget currency that the existing cache has no rate for yet.
+func RefreshFxRates(c *gin.Context) {
+	results := fx.RefreshNow()
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DBChecker pings the primary database connection. It is critical: if the
+// database is unreachable the app cannot serve requests.
+type DBChecker struct {
+	DB *gorm.DB
+}
+
+func (c *DBChecker) Name() string           { return "database" }
+func (c *DBChecker) Timeout() time.Duration { return 2 * time.Second }
+func (c *DBChecker) Critical() bool         { return true }
+
+func (c *DBChecker) Check(ctx context.Context) error {
+	if c.DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	sqlDB, err := c.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// PingFunc adapts a simple ping function into a HealthChecker, for
+// dependencies (cache, SMTP, background worker) whose health is just "can we
+// reach it".
+type PingFunc struct {
+	name     string
+	timeout  time.Duration
+	critical bool
+	ping     func(ctx context.Context) error
+}
+
+// NewPingChecker builds a HealthChecker from a name, timeout, criticality,
+// and a ping function.
+func NewPingChecker(name string, timeout time.Duration, critical bool, ping func(ctx context.Context) error) *PingFunc {
+	return &PingFunc{name: name, timeout: timeout, critical: critical, ping: ping}
+}
+
+func (p *PingFunc) Name() string           { return p.name }
+func (p *PingFunc) Timeout() time.Duration { return p.timeout }
+func (p *PingFunc) Critical() bool         { return p.critical }
+func (p *PingFunc) Check(ctx context.Context) error {
+	return p.ping(ctx)
+}
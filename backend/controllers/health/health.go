@@ -0,0 +1,182 @@
+// Package health implements a pluggable health-check registry used by the
+// /health/detailed and /health/ready controller endpoints. Dependencies
+// (database, cache, OAuth providers, SMTP, background workers, ...) register
+// themselves as HealthCheckers instead of being hard-coded into the
+// controller, and results are cached briefly so load-balancer polling does
+// not hammer downstreams.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HealthChecker is implemented by anything the app depends on that should be
+// reported in /health/detailed.
+type HealthChecker interface {
+	// Name is the key the check is reported under in HealthResponse.Services.
+	Name() string
+	// Timeout bounds how long Check is allowed to run before it is treated
+	// as a failure.
+	Timeout() time.Duration
+	// Critical marks whether a failure of this check should fail readiness.
+	// Non-critical failures only downgrade status to "degraded".
+	Critical() bool
+	// Check performs the actual dependency probe.
+	Check(ctx context.Context) error
+}
+
+// CheckResult is the outcome of running a single HealthChecker, cached for
+// TTL and surfaced in the detailed health response.
+type CheckResult struct {
+	Name        string        `json:"name"`
+	Status      string        `json:"status"` // "healthy", "degraded", "unhealthy"
+	Critical    bool          `json:"critical"`
+	Latency     time.Duration `json:"latency_ms"`
+	LastSuccess *time.Time    `json:"last_success,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	CheckedAt   time.Time     `json:"checked_at"`
+}
+
+// Registry runs registered HealthCheckers concurrently and caches the
+// aggregated result for TTL, so bursts of readiness/liveness polling from a
+// load balancer don't repeatedly hit the same downstreams.
+type Registry struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	checkers []HealthChecker
+
+	cacheMu     sync.Mutex
+	cachedAt    time.Time
+	cached      map[string]CheckResult
+	lastSuccess map[string]time.Time
+}
+
+// NewRegistry creates a Registry whose cached results are reused for ttl.
+// A ttl of zero disables caching (every call re-runs all checks).
+func NewRegistry(ttl time.Duration) *Registry {
+	return &Registry{
+		ttl:         ttl,
+		lastSuccess: make(map[string]time.Time),
+	}
+}
+
+// Register adds a HealthChecker to the registry. Not safe to call
+// concurrently with CheckAll.
+func (r *Registry) Register(checker HealthChecker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers = append(r.checkers, checker)
+}
+
+// CheckAll runs every registered checker concurrently (each bounded by its
+// own Timeout) and returns the per-service results, reusing the cached set
+// if it is still within ttl.
+func (r *Registry) CheckAll(ctx context.Context) map[string]CheckResult {
+	r.cacheMu.Lock()
+	if r.ttl > 0 && r.cached != nil && time.Since(r.cachedAt) < r.ttl {
+		cached := r.cached
+		r.cacheMu.Unlock()
+		return cached
+	}
+	r.cacheMu.Unlock()
+
+	r.mu.Lock()
+	checkers := make([]HealthChecker, len(r.checkers))
+	copy(checkers, r.checkers)
+	r.mu.Unlock()
+
+	results := make(map[string]CheckResult, len(checkers))
+	var resultsMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, checker := range checkers {
+		checker := checker
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := r.runOne(ctx, checker)
+			resultsMu.Lock()
+			results[checker.Name()] = result
+			resultsMu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	r.cacheMu.Lock()
+	r.cached = results
+	r.cachedAt = time.Now()
+	r.cacheMu.Unlock()
+
+	return results
+}
+
+func (r *Registry) runOne(ctx context.Context, checker HealthChecker) CheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, checker.Timeout())
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(checkCtx)
+	latency := time.Since(start)
+
+	result := CheckResult{
+		Name:      checker.Name(),
+		Critical:  checker.Critical(),
+		Latency:   latency,
+		CheckedAt: start,
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+		if checker.Critical() {
+			result.Status = "unhealthy"
+		} else {
+			result.Status = "degraded"
+		}
+		if last, ok := r.lastSuccess[checker.Name()]; ok {
+			t := last
+			result.LastSuccess = &t
+		}
+		return result
+	}
+
+	result.Status = "healthy"
+	now := start
+	r.lastSuccessSet(checker.Name(), now)
+	result.LastSuccess = &now
+	return result
+}
+
+func (r *Registry) lastSuccessSet(name string, t time.Time) {
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	r.lastSuccess[name] = t
+}
+
+// Overall reduces a set of CheckResults to a single aggregate status:
+// "unhealthy" if any critical check failed, "degraded" if any non-critical
+// check failed, "healthy" otherwise.
+func Overall(results map[string]CheckResult) string {
+	status := "healthy"
+	for _, result := range results {
+		switch result.Status {
+		case "unhealthy":
+			return "unhealthy"
+		case "degraded":
+			status = "degraded"
+		}
+	}
+	return status
+}
+
+// AnyCriticalFailed reports whether any critical checker is unhealthy.
+func AnyCriticalFailed(results map[string]CheckResult) bool {
+	for _, result := range results {
+		if result.Critical && result.Status == "unhealthy" {
+			return true
+		}
+	}
+	return false
+}
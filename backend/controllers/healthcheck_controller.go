@@ -1,100 +1,120 @@
 package controllers
 
 import (
-    "net/http"
-    "time"
+	"net/http"
+	"sync"
+	"time"
 
-    "Personal-Finance-Tracker-backend/db"
-    "github.com/gin-gonic/gin"
-    "go.uber.org/zap"
+	"Personal-Finance-Tracker-backend/controllers/health"
+	"Personal-Finance-Tracker-backend/db"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-    Status    string            `json:"status"`
-    Timestamp string            `json:"timestamp"`
-    Services  map[string]string `json:"services"`
-    Version   string            `json:"version"`
+	Status    string                        `json:"status"`
+	Timestamp string                        `json:"timestamp"`
+	Services  map[string]health.CheckResult `json:"services"`
+	Version   string                        `json:"version"`
+}
+
+// defaultHealthTTL is how long DetailedHealthCheck/ReadinessCheck reuse a
+// cached set of check results, so a load balancer polling every second or
+// two doesn't hammer the database/cache/SMTP on every request.
+const defaultHealthTTL = 5 * time.Second
+
+var (
+	registryOnce sync.Once
+	registry     *health.Registry
+)
+
+// Registry returns the process-wide health check registry, registering the
+// built-in checkers (currently just the database) on first use.
+func Registry() *health.Registry {
+	registryOnce.Do(func() {
+		registry = health.NewRegistry(defaultHealthTTL)
+		registry.Register(&health.DBChecker{DB: db.DB})
+	})
+	return registry
+}
+
+// RegisterHealthChecker adds an additional dependency (cache, OAuth
+// provider, SMTP, background worker, ...) to the process-wide registry.
+// Call during application startup, before traffic is served.
+func RegisterHealthChecker(checker health.HealthChecker) {
+	Registry().Register(checker)
 }
 
 // HealthCheck provides a simple health check endpoint
 func HealthCheck(c *gin.Context) {
-    c.JSON(http.StatusOK, gin.H{
-        "status":    "ok",
-        "timestamp": time.Now().Format(time.RFC3339),
-    })
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "ok",
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
 }
 
-// DetailedHealthCheck provides detailed health information
+// DetailedHealthCheck runs every registered HealthChecker concurrently
+// (each bounded by its own timeout), reusing cached results within
+// defaultHealthTTL, and returns per-service status, latency, and the last
+// successful check time.
 func DetailedHealthCheck(c *gin.Context, logger *zap.Logger) {
-    response := HealthResponse{
-        Status:    "healthy",
-        Timestamp: time.Now().Format(time.RFC3339),
-        Services:  make(map[string]string),
-        Version:   "1.0.0",
-    }
-
-    // Check database connection
-    sqlDB, err := db.DB.DB()
-    if err != nil {
-        logger.Error("Database connection error",
-            zap.Error(err),
-        )
-        response.Status = "unhealthy"
-        response.Services["database"] = "down"
-    } else {
-        // Ping database
-        err = sqlDB.Ping()
-        if err != nil {
-            logger.Error("Database ping failed",
-                zap.Error(err),
-            )
-            response.Status = "degraded"
-            response.Services["database"] = "unreachable"
-        } else {
-            response.Services["database"] = "healthy"
-            
-            // Get database stats
-            stats := sqlDB.Stats()
-            logger.Info("Database stats",
-                zap.Int("open_connections", stats.OpenConnections),
-                zap.Int("in_use", stats.InUse),
-                zap.Int("idle", stats.Idle),
-            )
-        }
-    }
-
-    // Determine HTTP status based on health
-    statusCode := http.StatusOK
-    if response.Status == "unhealthy" {
-        statusCode = http.StatusServiceUnavailable
-    } else if response.Status == "degraded" {
-        statusCode = http.StatusOK // Still return 200 for degraded
-    }
-
-    c.JSON(statusCode, response)
+	results := Registry().CheckAll(c.Request.Context())
+
+	response := HealthResponse{
+		Status:    health.Overall(results),
+		Timestamp: time.Now().Format(time.RFC3339),
+		Services:  results,
+		Version:   "1.0.0",
+	}
+
+	for name, result := range results {
+		if result.Status != "healthy" {
+			logger.Warn("health check degraded",
+				zap.String("service", name),
+				zap.String("status", result.Status),
+				zap.String("error", result.Error),
+			)
+		}
+	}
+
+	statusCode := http.StatusOK
+	if response.Status == "unhealthy" {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	c.JSON(statusCode, response)
 }
 
-// ReadinessCheck checks if the application is ready to serve traffic
+// ReadinessCheck checks if the application is ready to serve traffic. Only
+// a failing Critical check fails readiness (503); non-critical failures are
+// reported but still return 200 so the pod isn't pulled from rotation over
+// e.g. a degraded SMTP provider.
 func ReadinessCheck(c *gin.Context) {
-    // Check if database is accessible
-    sqlDB, err := db.DB.DB()
-    if err != nil || sqlDB.Ping() != nil {
-        c.JSON(http.StatusServiceUnavailable, gin.H{
-            "status": "not_ready",
-            "reason": "database_unavailable",
-        })
-        return
-    }
-
-    c.JSON(http.StatusOK, gin.H{
-        "status": "ready",
-    })
+	results := Registry().CheckAll(c.Request.Context())
+
+	if health.AnyCriticalFailed(results) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":   "not_ready",
+			"reason":   "critical_dependency_unavailable",
+			"services": results,
+		})
+		return
+	}
+
+	status := "ready"
+	if health.Overall(results) == "degraded" {
+		status = "degraded"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+	})
 }
 
 // LivenessCheck checks if the application is alive
 func LivenessCheck(c *gin.Context) {
-    c.JSON(http.StatusOK, gin.H{
-        "status": "alive",
-    })
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, gin.H{
+		"status": "alive",
+	})
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/importers"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/store"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+)
+
+// ImportRowStatus is the per-row outcome returned by ImportTransactions.
+type ImportRowStatus string
+
+const (
+	ImportRowImported  ImportRowStatus = "imported"
+	ImportRowDuplicate ImportRowStatus = "duplicate"
+	ImportRowError     ImportRowStatus = "error"
+)
+
+type importRowResult struct {
+	Status      ImportRowStatus `json:"status"`
+	TxnDate     string          `json:"txn_date"`
+	AmountCents int64           `json:"amount_cents"`
+	Payee       string          `json:"payee"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// ImportTransactions parses an uploaded OFX/QIF/CSV statement into
+// Transaction rows for the given account. Duplicate rows (by content hash)
+// are skipped. Pass ?dry_run=true to preview the parse without writing
+// anything.
+func ImportTransactions(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	accountID := c.Param("id")
+
+	var account models.Account
+	if err := db.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing upload file"})
+		return
+	}
+
+	format := importers.Format(c.Query("format"))
+	if format == "" {
+		detected, err := importers.DetectFormat(fileHeader.Filename)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unable to determine import format, pass ?format=ofx|qif|csv"})
+			return
+		}
+		format = detected
+	}
+
+	parser, err := importers.ForFormat(format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to open upload"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read upload"})
+		return
+	}
+
+	parsed, err := parser.Parse(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	results := make([]importRowResult, 0, len(parsed))
+
+	if dryRun {
+		for _, row := range parsed {
+			status := ImportRowImported
+			hash := importers.ContentHash(account.ID, row)
+			var count int64
+			db.DB.Model(&models.Transaction{}).Where("import_hash = ?", hash).Count(&count)
+			if count > 0 {
+				status = ImportRowDuplicate
+			}
+			results = append(results, importRowResult{
+				Status:      status,
+				TxnDate:     row.TxnDate.Format("2006-01-02"),
+				AmountCents: row.AmountCents,
+				Payee:       row.Payee,
+			})
+		}
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "results": results})
+		return
+	}
+
+	tx := db.DB.Begin()
+	imported := 0
+
+	for _, row := range parsed {
+		hash := importers.ContentHash(account.ID, row)
+
+		var count int64
+		tx.Model(&models.Transaction{}).Where("import_hash = ?", hash).Count(&count)
+		if count > 0 {
+			results = append(results, importRowResult{
+				Status:      ImportRowDuplicate,
+				TxnDate:     row.TxnDate.Format("2006-01-02"),
+				AmountCents: row.AmountCents,
+				Payee:       row.Payee,
+			})
+			continue
+		}
+
+		hashCopy := hash
+		transaction := models.Transaction{
+			UserID:      userID,
+			AccountID:   account.ID,
+			Amount:      decimal.NewFromInt(row.AmountCents).Div(decimal.NewFromInt(100)),
+			Description: row.Payee,
+			TxnDate:     row.TxnDate,
+			ImportHash:  &hashCopy,
+		}
+
+		if err := tx.Create(&transaction).Error; err != nil {
+			results = append(results, importRowResult{
+				Status:      ImportRowError,
+				TxnDate:     row.TxnDate.Format("2006-01-02"),
+				AmountCents: row.AmountCents,
+				Payee:       row.Payee,
+				Error:       err.Error(),
+			})
+			continue
+		}
+
+		imported++
+		results = append(results, importRowResult{
+			Status:      ImportRowImported,
+			TxnDate:     row.TxnDate.Format("2006-01-02"),
+			AmountCents: row.AmountCents,
+			Payee:       row.Payee,
+		})
+	}
+
+	if imported > 0 {
+		if err := store.RecalculateBalance(tx, account.ID); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update account balance"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit import"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"dry_run": false, "imported": imported, "results": results})
+}
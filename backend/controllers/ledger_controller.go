@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// ledgerBalance is one account's reconstructed balance for the trial-balance
+// report: the sum of its LedgerEntry rows, not Account.CurrentBalance.
+// Since only CreateTransfer and plaid_api.go's collapsePlaidTransfers post
+// LedgerEntry rows today, this only reflects transfer movements - ordinary
+// category transactions don't have a ledger leg in this schema, so a
+// trial balance isn't yet a full account balance.
+type ledgerBalance struct {
+	AccountID    uint   `json:"account_id"`
+	AccountName  string `json:"account_name"`
+	BalanceCents int64  `json:"balance_cents"`
+}
+
+// GetTrialBalance reconstructs each of the authenticated user's accounts'
+// ledger-backed balance by summing LedgerEntry rows, and reports the sum of
+// all balances (which must always be zero for a balanced double-entry
+// ledger).
+func GetTrialBalance(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var accounts []models.Account
+	if err := db.DB.Where("user_id = ?", userID).Find(&accounts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch accounts"})
+		return
+	}
+
+	balances := make([]ledgerBalance, 0, len(accounts))
+	var total int64
+	for _, account := range accounts {
+		var sum int64
+		db.DB.Model(&models.LedgerEntry{}).
+			Where("account_id = ?", account.ID).
+			Select("COALESCE(SUM(amount_cents), 0)").
+			Scan(&sum)
+
+		balances = append(balances, ledgerBalance{
+			AccountID:    account.ID,
+			AccountName:  account.Name,
+			BalanceCents: sum,
+		})
+		total += sum
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"balances":    balances,
+		"total_cents": total,
+	})
+}
+
+// GetAccountLedgerBalance reconstructs a single account's ledger-backed
+// balance the same way GetTrialBalance does, for callers that only need one
+// account without pulling the whole trial balance.
+func GetAccountLedgerBalance(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	accountID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account ID"})
+		return
+	}
+
+	var account models.Account
+	if err := db.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	var sum int64
+	db.DB.Model(&models.LedgerEntry{}).
+		Where("account_id = ?", account.ID).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Scan(&sum)
+
+	c.JSON(http.StatusOK, ledgerBalance{
+		AccountID:    account.ID,
+		AccountName:  account.Name,
+		BalanceCents: sum,
+	})
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/redis"
+)
+
+// loginFailureThreshold is how many failed /auth/login attempts for one
+// username inside loginFailureWindow trigger a lockout.
+const loginFailureThreshold = 10
+
+// loginFailureWindow is how long a run of failures is allowed to
+// accumulate before the counter resets on its own.
+const loginFailureWindow = 15 * time.Minute
+
+// loginLockoutBase/loginLockoutMax bound the exponential backoff applied on
+// repeat lockouts: 1st lockout is loginLockoutBase, doubling each time the
+// account gets locked out again, capped at loginLockoutMax.
+const (
+	loginLockoutBase = 1 * time.Minute
+	loginLockoutMax  = 1 * time.Hour
+)
+
+// loginLockoutRecord tracks one username's failed-login history.
+type loginLockoutRecord struct {
+	FailCount   int       `json:"fail_count"`
+	WindowStart time.Time `json:"window_start"`
+	LockCount   int       `json:"lock_count"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// loginLockoutStore is the pluggable backend login-lockout state is kept
+// in, mirroring sessionStore/bucketStore: in-memory by default,
+// Redis-backed when configured so a lockout is shared across replicas.
+type loginLockoutStore interface {
+	get(username string) (loginLockoutRecord, bool)
+	put(username string, record loginLockoutRecord)
+	clear(username string)
+}
+
+type memoryLoginLockoutStore struct {
+	mu      sync.Mutex
+	records map[string]loginLockoutRecord
+}
+
+func newMemoryLoginLockoutStore() *memoryLoginLockoutStore {
+	return &memoryLoginLockoutStore{records: make(map[string]loginLockoutRecord)}
+}
+
+func (s *memoryLoginLockoutStore) get(username string) (loginLockoutRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[username]
+	return record, ok
+}
+
+func (s *memoryLoginLockoutStore) put(username string, record loginLockoutRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[username] = record
+}
+
+func (s *memoryLoginLockoutStore) clear(username string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, username)
+}
+
+var defaultLoginLockoutStore = newMemoryLoginLockoutStore()
+
+// redisLoginLockoutStore keeps one JSON blob per username under
+// login_lockout:<username>, refreshed with a TTL generous enough to cover
+// both the failure-accumulation window and the longest possible lockout.
+type redisLoginLockoutStore struct{}
+
+func (redisLoginLockoutStore) get(username string) (loginLockoutRecord, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	data, err := redis.RDB.Get(ctx, "login_lockout:"+username).Bytes()
+	if err != nil {
+		return loginLockoutRecord{}, false
+	}
+	var record loginLockoutRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return loginLockoutRecord{}, false
+	}
+	return record, true
+}
+
+func (redisLoginLockoutStore) put(username string, record loginLockoutRecord) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	redis.RDB.Set(ctx, "login_lockout:"+username, data, loginFailureWindow+loginLockoutMax)
+}
+
+func (redisLoginLockoutStore) clear(username string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	redis.RDB.Del(ctx, "login_lockout:"+username)
+}
+
+func activeLoginLockoutStore() loginLockoutStore {
+	if redis.RDB != nil {
+		return redisLoginLockoutStore{}
+	}
+	return defaultLoginLockoutStore
+}
+
+// checkLoginLockout reports whether username is currently locked out, and
+// if so, when it next unlocks.
+func checkLoginLockout(username string) (lockedUntil time.Time, locked bool) {
+	record, ok := activeLoginLockoutStore().get(username)
+	if !ok || !time.Now().Before(record.LockedUntil) {
+		return time.Time{}, false
+	}
+	return record.LockedUntil, true
+}
+
+// recordLoginFailure records one failed /auth/login attempt for username.
+// Once loginFailureThreshold failures land inside loginFailureWindow, the
+// account is locked out; each subsequent lockout doubles the previous
+// lockout's duration (capped at loginLockoutMax) so repeat offenders are
+// throttled harder than a one-off burst of typos.
+func recordLoginFailure(username string) (lockedUntil time.Time, justLocked bool) {
+	store := activeLoginLockoutStore()
+	record, ok := store.get(username)
+	now := time.Now()
+	if !ok || now.Sub(record.WindowStart) > loginFailureWindow {
+		record = loginLockoutRecord{WindowStart: now, LockCount: record.LockCount}
+	}
+	record.FailCount++
+
+	if record.FailCount < loginFailureThreshold {
+		store.put(username, record)
+		return time.Time{}, false
+	}
+
+	record.LockCount++
+	duration := loginLockoutBase
+	for i := 1; i < record.LockCount && duration < loginLockoutMax; i++ {
+		duration *= 2
+	}
+	if duration > loginLockoutMax {
+		duration = loginLockoutMax
+	}
+	record.LockedUntil = now.Add(duration)
+	record.FailCount = 0
+	record.WindowStart = now
+	store.put(username, record)
+	return record.LockedUntil, true
+}
+
+// clearLoginLockout removes username's lockout state entirely - called on a
+// successful login, and by the admin POST /admin/users/:id/unlock endpoint.
+func clearLoginLockout(username string) {
+	activeLoginLockoutStore().clear(username)
+}
@@ -0,0 +1,478 @@
+package controllers
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// merchantNoisePrefixes are leading tokens card networks and POS terminals
+// prepend to the actual payee name, stripped before pattern matching so
+// "POS SQ *AMAZON.COM" and "SQ *AMAZON.COM" normalize the same way.
+var merchantNoisePrefixes = []string{"POS ", "SQ *", "TST* ", "PAYPAL *", "DEBIT CARD PURCHASE "}
+
+// merchantTrailingIDPattern strips a trailing transaction id / reference
+// number a processor tacks onto the payee name (e.g. "AMZN Mktp US*A1B2C3"
+// -> "AMZN Mktp US", "SHELL OIL 12345678901" -> "SHELL OIL").
+var merchantTrailingIDPattern = regexp.MustCompile(`(?:\*[A-Z0-9]{4,}|\s+#?\d{4,})$`)
+
+// merchantCityStatePattern strips a trailing "CITY ST" suffix many card
+// statements append (e.g. "STARBUCKS #123 SEATTLE WA" -> "STARBUCKS #123").
+var merchantCityStatePattern = regexp.MustCompile(`(?i)\s+[A-Za-z.' ]+\s[A-Z]{2}$`)
+
+// NormalizeDescription strips the noise patterns above so differently
+// formatted statement lines for the same payee ("AMZN Mktp US*A1B2C3",
+// "POS AMZN Mktp US*D4E5F6") collapse to the same comparison string before
+// MatchMerchant runs its patterns against it.
+func NormalizeDescription(description string) string {
+	normalized := strings.TrimSpace(description)
+
+	upper := strings.ToUpper(normalized)
+	for _, prefix := range merchantNoisePrefixes {
+		if strings.HasPrefix(upper, strings.ToUpper(prefix)) {
+			normalized = normalized[len(prefix):]
+			upper = strings.ToUpper(normalized)
+		}
+	}
+
+	normalized = merchantTrailingIDPattern.ReplaceAllString(normalized, "")
+	normalized = merchantCityStatePattern.ReplaceAllString(normalized, "")
+
+	return strings.TrimSpace(normalized)
+}
+
+// merchantPatternMatches is Merchant's own version of patternMatches
+// (case-insensitive substring, or regex when IsRegex), matched against the
+// noise-stripped description rather than the raw one.
+func merchantPatternMatches(merchant models.Merchant, normalized string) bool {
+	for _, pattern := range merchant.Patterns {
+		if merchant.IsRegex {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				continue
+			}
+			if re.MatchString(normalized) {
+				return true
+			}
+			continue
+		}
+		if strings.Contains(strings.ToLower(normalized), strings.ToLower(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// merchantFuzzyThreshold is the minimum trigramSimilarity a normalized
+// description must have with an existing merchant's CanonicalName to
+// auto-cluster when no Pattern matched - the same role a Postgres `similarity()
+// > threshold` pg_trgm query would play, reimplemented in Go so a SQLite
+// install (no pg_trgm extension available) gets equivalent clustering.
+const merchantFuzzyThreshold = 0.35
+
+// trigrams returns s's set of 3-character substrings, padded with leading/
+// trailing spaces the way pg_trgm pads its own trigrams so a short word
+// still produces at least one.
+func trigrams(s string) map[string]struct{} {
+	padded := "  " + strings.ToLower(s) + " "
+	set := make(map[string]struct{})
+	for i := 0; i+3 <= len(padded); i++ {
+		set[padded[i:i+3]] = struct{}{}
+	}
+	return set
+}
+
+// trigramSimilarity is the Dice coefficient of a and b's trigram sets, in
+// [0, 1] - the same scoring pg_trgm's similarity() uses.
+func trigramSimilarity(a, b string) float64 {
+	setA, setB := trigrams(a), trigrams(b)
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range setA {
+		if _, ok := setB[t]; ok {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(setA)+len(setB))
+}
+
+// fuzzyMatchMerchant is MatchMerchant's fallback once no candidate's
+// Patterns matched: it scores normalized against every candidate's
+// CanonicalName and returns whichever clears merchantFuzzyThreshold by the
+// widest margin, or nil if none do. This is what lets "STARBUCKS STORE
+// 09281" cluster under a "Starbucks" merchant that was only ever taught the
+// pattern "SQ *STARBUCKS".
+func fuzzyMatchMerchant(candidates []models.Merchant, normalized string) *uint {
+	var best *models.Merchant
+	bestScore := merchantFuzzyThreshold
+	for i := range candidates {
+		if score := trigramSimilarity(normalized, candidates[i].CanonicalName); score > bestScore {
+			bestScore = score
+			best = &candidates[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	id := best.ID
+	return &id
+}
+
+// MatchMerchant returns the ID of the first of userID's (or the seeded
+// system set's) merchants whose Patterns match description's noise-stripped
+// form, or - failing that - the closest one by fuzzyMatchMerchant. Returns
+// nil if neither finds a candidate. User-defined merchants are checked
+// before system ones, so a user can override a seeded pattern with a more
+// specific one of their own.
+func MatchMerchant(userID uint, description string) *uint {
+	normalized := NormalizeDescription(description)
+
+	var merchants []models.Merchant
+	db.DB.Where("user_id = ? OR is_system = ?", userID, true).
+		Order("is_system ASC, id ASC").
+		Find(&merchants)
+
+	for _, merchant := range merchants {
+		if merchantPatternMatches(merchant, normalized) {
+			id := merchant.ID
+			return &id
+		}
+	}
+
+	return fuzzyMatchMerchant(merchants, normalized)
+}
+
+// ApplyMerchantToTransaction sets and persists txn.MerchantID from
+// MatchMerchant, and - when txn is still uncategorized - suggests that
+// merchant's DefaultCategoryID the same way ApplyCategoryRuleToTransaction
+// suggests a CategoryRule's category. Called from CreateTransaction after
+// category-rule matching, so a CategoryRule the user already set up always
+// wins over a merchant's default.
+func ApplyMerchantToTransaction(userID uint, txn *models.Transaction) bool {
+	merchantID := MatchMerchant(userID, txn.Description)
+	if merchantID == nil {
+		return false
+	}
+
+	txn.MerchantID = merchantID
+	updates := map[string]interface{}{"merchant_id": *merchantID}
+
+	if txn.CategoryID == nil {
+		var merchant models.Merchant
+		if db.DB.First(&merchant, *merchantID).Error == nil && merchant.DefaultCategoryID != nil {
+			txn.CategoryID = merchant.DefaultCategoryID
+			updates["category_id"] = *merchant.DefaultCategoryID
+		}
+	}
+
+	db.DB.Model(&models.Transaction{}).Where("id = ? AND user_id = ?", txn.ID, userID).Updates(updates)
+	return true
+}
+
+// GetMerchants lists the authenticated user's own merchants plus the seeded
+// system set.
+func GetMerchants(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var merchants []models.Merchant
+	if err := db.DB.Where("user_id = ? OR is_system = ?", userID, true).Order("canonical_name ASC").Find(&merchants).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch merchants"})
+		return
+	}
+
+	c.JSON(http.StatusOK, merchants)
+}
+
+// merchantInput is the CreateMerchant/UpdateMerchant request body.
+type merchantInput struct {
+	CanonicalName     string   `json:"canonical_name" binding:"required"`
+	Patterns          []string `json:"patterns" binding:"required,min=1"`
+	IsRegex           bool     `json:"is_regex"`
+	DefaultCategoryID *uint    `json:"default_category_id"`
+}
+
+// CreateMerchant defines a new canonical merchant for the authenticated
+// user.
+func CreateMerchant(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input merchantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.IsRegex {
+		for _, pattern := range input.Patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid regex pattern: " + err.Error()})
+				return
+			}
+		}
+	}
+
+	if input.DefaultCategoryID != nil {
+		var category models.Category
+		if err := db.DB.Where("id = ? AND user_id = ?", *input.DefaultCategoryID, userID).First(&category).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "default_category_id not found or does not belong to user"})
+			return
+		}
+	}
+
+	merchant := models.Merchant{
+		UserID:            userID,
+		CanonicalName:     input.CanonicalName,
+		Patterns:          models.MerchantPatterns(input.Patterns),
+		IsRegex:           input.IsRegex,
+		DefaultCategoryID: input.DefaultCategoryID,
+	}
+
+	if err := db.DB.Create(&merchant).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create merchant"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, merchant)
+}
+
+// UpdateMerchant updates one of the authenticated user's own merchants
+// (the seeded system set isn't user-editable).
+func UpdateMerchant(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	merchantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid merchant ID"})
+		return
+	}
+
+	var merchant models.Merchant
+	if err := db.DB.Where("id = ? AND user_id = ?", merchantID, userID).First(&merchant).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "merchant not found"})
+		return
+	}
+
+	var input merchantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.IsRegex {
+		for _, pattern := range input.Patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid regex pattern: " + err.Error()})
+				return
+			}
+		}
+	}
+
+	if input.DefaultCategoryID != nil {
+		var category models.Category
+		if err := db.DB.Where("id = ? AND user_id = ?", *input.DefaultCategoryID, userID).First(&category).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "default_category_id not found or does not belong to user"})
+			return
+		}
+	}
+
+	merchant.CanonicalName = input.CanonicalName
+	merchant.Patterns = models.MerchantPatterns(input.Patterns)
+	merchant.IsRegex = input.IsRegex
+	merchant.DefaultCategoryID = input.DefaultCategoryID
+
+	if err := db.DB.Save(&merchant).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update merchant"})
+		return
+	}
+
+	c.JSON(http.StatusOK, merchant)
+}
+
+// DeleteMerchant deletes one of the authenticated user's own merchants.
+func DeleteMerchant(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	merchantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid merchant ID"})
+		return
+	}
+
+	var merchant models.Merchant
+	if err := db.DB.Where("id = ? AND user_id = ?", merchantID, userID).First(&merchant).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "merchant not found"})
+		return
+	}
+
+	if err := db.DB.Delete(&merchant).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete merchant"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "merchant deleted successfully"})
+}
+
+// backfillMerchantMatches re-checks every one of userID's transactions that
+// has no MerchantID yet against merchant's Patterns, attaching merchant.ID
+// to whichever ones now match. AddMerchantAlias and MergeMerchants both need
+// this after changing what a merchant matches, so a correction doesn't only
+// apply going forward.
+func backfillMerchantMatches(userID uint, merchant models.Merchant) int {
+	var transactions []models.Transaction
+	db.DB.Where("user_id = ? AND merchant_id IS NULL", userID).Find(&transactions)
+
+	backfilled := 0
+	for _, txn := range transactions {
+		if !merchantPatternMatches(merchant, NormalizeDescription(txn.Description)) {
+			continue
+		}
+		db.DB.Model(&models.Transaction{}).Where("id = ?", txn.ID).Update("merchant_id", merchant.ID)
+		backfilled++
+	}
+	return backfilled
+}
+
+// AddMerchantAlias appends a new match pattern to one of the authenticated
+// user's own merchants, then calls backfillMerchantMatches so the
+// correction also applies to past transactions that a clustering mistake
+// (or missing pattern) left unmatched.
+func AddMerchantAlias(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	merchantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid merchant ID"})
+		return
+	}
+
+	var merchant models.Merchant
+	if err := db.DB.Where("id = ? AND user_id = ?", merchantID, userID).First(&merchant).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "merchant not found"})
+		return
+	}
+
+	var input struct {
+		Pattern string `json:"pattern" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if merchant.IsRegex {
+		if _, err := regexp.Compile(input.Pattern); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid regex pattern: " + err.Error()})
+			return
+		}
+	}
+
+	merchant.Patterns = append(merchant.Patterns, input.Pattern)
+	if err := db.DB.Save(&merchant).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add alias"})
+		return
+	}
+
+	backfilled := backfillMerchantMatches(userID, merchant)
+
+	c.JSON(http.StatusOK, gin.H{"merchant": merchant, "backfilled": backfilled})
+}
+
+// MergeMerchants folds source into target: target gains source's Patterns
+// (deduplicated), every transaction pointed at source is repointed to
+// target, and source is deleted. Lets a user correct two canonical
+// merchants existing for what's really one payee.
+func MergeMerchants(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input struct {
+		SourceID uint `json:"source_id" binding:"required"`
+		TargetID uint `json:"target_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.SourceID == input.TargetID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source_id and target_id must differ"})
+		return
+	}
+
+	var source, target models.Merchant
+	if err := db.DB.Where("id = ? AND user_id = ?", input.SourceID, userID).First(&source).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "source merchant not found"})
+		return
+	}
+	if err := db.DB.Where("id = ? AND user_id = ?", input.TargetID, userID).First(&target).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "target merchant not found"})
+		return
+	}
+
+	merged := append([]string{}, []string(target.Patterns)...)
+	for _, pattern := range source.Patterns {
+		alreadyPresent := false
+		for _, existing := range merged {
+			if existing == pattern {
+				alreadyPresent = true
+				break
+			}
+		}
+		if !alreadyPresent {
+			merged = append(merged, pattern)
+		}
+	}
+	target.Patterns = merged
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&target).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Transaction{}).Where("user_id = ? AND merchant_id = ?", userID, source.ID).
+			Update("merchant_id", target.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&source).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to merge merchants"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"merchant": target})
+}
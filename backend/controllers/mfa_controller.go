@@ -0,0 +1,459 @@
+package controllers
+
+import (
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha1"
+    "encoding/base32"
+    "encoding/binary"
+    "fmt"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+
+    "Personal-Finance-Tracker-backend/db"
+    "Personal-Finance-Tracker-backend/middleware"
+    "Personal-Finance-Tracker-backend/models"
+    "Personal-Finance-Tracker-backend/utils"
+
+    "github.com/gin-gonic/gin"
+    jwt "github.com/golang-jwt/jwt/v5"
+    "go.uber.org/zap"
+)
+
+const (
+    totpStep        = 30 * time.Second
+    totpWindowSteps = 1
+    backupCodeCount = 10
+)
+
+// totpAt computes the RFC 6238 TOTP code for secret (base32) at time t:
+// HMAC-SHA1 over the big-endian 8-byte step counter, dynamic-truncated per
+// RFC 4226 (last nibble of the digest picks a 4-byte offset, top bit
+// masked), mod 10^6, zero-padded to 6 digits.
+func totpAt(secret string, t time.Time) (string, error) {
+    key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+    if err != nil {
+        return "", err
+    }
+
+    counter := uint64(t.Unix() / int64(totpStep.Seconds()))
+    counterBytes := make([]byte, 8)
+    binary.BigEndian.PutUint64(counterBytes, counter)
+
+    mac := hmac.New(sha1.New, key)
+    mac.Write(counterBytes)
+    sum := mac.Sum(nil)
+
+    offset := sum[len(sum)-1] & 0x0f
+    code := (uint32(sum[offset]&0x7f) << 24) |
+        (uint32(sum[offset+1]) << 16) |
+        (uint32(sum[offset+2]) << 8) |
+        uint32(sum[offset+3])
+    code %= 1000000
+
+    return fmt.Sprintf("%06d", code), nil
+}
+
+// verifyTOTP accepts the code if it matches any step within
+// +/- totpWindowSteps of now, to tolerate clock drift between server and
+// authenticator app.
+func verifyTOTP(secret, code string) bool {
+    if code == "" {
+        return false
+    }
+    now := time.Now()
+    for skew := -totpWindowSteps; skew <= totpWindowSteps; skew++ {
+        expected, err := totpAt(secret, now.Add(time.Duration(skew)*totpStep))
+        if err != nil {
+            return false
+        }
+        if subtleConstantTimeCompare([]byte(expected), []byte(code)) {
+            return true
+        }
+    }
+    return false
+}
+
+// generateTOTPSecret returns a 20-byte secret, base32-encoded without
+// padding (the form authenticator apps expect in an otpauth:// URI).
+func generateTOTPSecret() (string, error) {
+    raw := make([]byte, 20)
+    if _, err := rand.Read(raw); err != nil {
+        return "", err
+    }
+    return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw), nil
+}
+
+// generateBackupCodes returns n random human-typeable recovery codes.
+func generateBackupCodes(n int) ([]string, error) {
+    codes := make([]string, n)
+    for i := range codes {
+        raw := make([]byte, 5)
+        if _, err := rand.Read(raw); err != nil {
+            return nil, err
+        }
+        codes[i] = fmt.Sprintf("%x", raw)
+    }
+    return codes, nil
+}
+
+// requireFreshTOTP gates a sensitive account action (password change,
+// account deletion) behind a current TOTP code when the caller has MFA
+// enabled. Accounts without MFA enrolled pass through untouched. On
+// failure it writes the response itself and returns false, the same
+// contract as requireConfirmToken in admin_usage.go.
+func requireFreshTOTP(c *gin.Context, user models.User, code string) bool {
+    if user.OTP == nil || user.OTP.ConfirmedAt == nil {
+        return true
+    }
+    secret, err := decryptTOTPSecret(user.OTP.Secret)
+    if err != nil || !verifyTOTP(secret, code) {
+        if !checkMFAFailureLimit(c, user.ID) {
+            return false
+        }
+        utils.Logger.Warn("Sensitive account action rejected - missing or invalid TOTP code",
+            zap.Uint("user_id", user.ID),
+            zap.String("ip", c.ClientIP()),
+        )
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "a valid TOTP code is required for this action"})
+        return false
+    }
+    return true
+}
+
+// checkMFAFailureLimit consumes one token from the per-user MFA-failure
+// bucket (see middleware.MFAFailureRate), writing the 429 response itself
+// and returning false if the caller has already guessed too many wrong
+// codes in the last minute.
+func checkMFAFailureLimit(c *gin.Context, userID uint) bool {
+    return middleware.CheckKeyLimit(c, "mfa_fail:"+strconv.FormatUint(uint64(userID), 10), middleware.MFAFailureRate, "too many failed MFA attempts, try again later")
+}
+
+// EnrollMFA generates a new TOTP secret for the current user and returns
+// it along with an otpauth:// URI for an authenticator app (or a
+// frontend-rendered QR code) to consume. The secret is not confirmed -
+// and MFA is not yet required at login - until /mfa/verify succeeds.
+func EnrollMFA(c *gin.Context) {
+    claims, exists := c.Get("user")
+    if !exists {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+        return
+    }
+    userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+    var user models.User
+    if err := db.DB.Preload("OTP").First(&user, userID).Error; err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+        return
+    }
+
+    if user.OTP != nil && user.OTP.ConfirmedAt != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "MFA is already enabled, disable it before re-enrolling"})
+        return
+    }
+
+    secret, err := generateTOTPSecret()
+    if err != nil {
+        utils.Logger.Error("Failed to generate TOTP secret", zap.Error(err), zap.Uint("user_id", userID))
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate MFA secret"})
+        return
+    }
+
+    encryptedSecret, err := encryptTOTPSecret(secret)
+    if err != nil {
+        utils.Logger.Error("Failed to encrypt TOTP secret", zap.Error(err), zap.Uint("user_id", userID))
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate MFA secret"})
+        return
+    }
+
+    otp := models.UserOTP{UserID: userID, Secret: encryptedSecret}
+    if user.OTP != nil {
+        otp.ID = user.OTP.ID
+    }
+    if err := db.DB.Save(&otp).Error; err != nil {
+        utils.Logger.Error("Failed to save TOTP enrollment", zap.Error(err), zap.Uint("user_id", userID))
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start MFA enrollment"})
+        return
+    }
+
+    otpauthURL := fmt.Sprintf(
+        "otpauth://totp/PersonalFinanceTracker:%s?secret=%s&issuer=PersonalFinanceTracker&algorithm=SHA1&digits=6&period=30",
+        url.QueryEscape(user.Username),
+        secret,
+    )
+
+    utils.Logger.Info("MFA enrollment started", zap.Uint("user_id", userID))
+
+    c.JSON(http.StatusOK, gin.H{
+        "secret":      secret,
+        "otpauth_url": otpauthURL,
+    })
+}
+
+// VerifyMFA confirms a pending enrollment with a valid TOTP code, issuing
+// a set of one-time backup codes (returned once, in plaintext) and
+// marking the enrollment confirmed so Login starts requiring step-up.
+func VerifyMFA(c *gin.Context) {
+    claims, exists := c.Get("user")
+    if !exists {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+        return
+    }
+    userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+    var input struct {
+        Code string `json:"code" binding:"required"`
+    }
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    var otp models.UserOTP
+    if err := db.DB.Where("user_id = ?", userID).First(&otp).Error; err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "no pending MFA enrollment"})
+        return
+    }
+
+    secret, err := decryptTOTPSecret(otp.Secret)
+    if err != nil || !verifyTOTP(secret, input.Code) {
+        if !checkMFAFailureLimit(c, userID) {
+            return
+        }
+        utils.Logger.Warn("MFA verification failed - invalid code", zap.Uint("user_id", userID))
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid code"})
+        return
+    }
+
+    codes, err := generateBackupCodes(backupCodeCount)
+    if err != nil {
+        utils.Logger.Error("Failed to generate backup codes", zap.Error(err), zap.Uint("user_id", userID))
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate backup codes"})
+        return
+    }
+
+    hashedCodes := make(models.StringList, len(codes))
+    for i, code := range codes {
+        hash, err := HashPassword(code)
+        if err != nil {
+            utils.Logger.Error("Failed to hash backup code", zap.Error(err), zap.Uint("user_id", userID))
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate backup codes"})
+            return
+        }
+        hashedCodes[i] = hash
+    }
+
+    now := time.Now()
+    otp.ConfirmedAt = &now
+    otp.BackupCodes = hashedCodes
+    if err := db.DB.Save(&otp).Error; err != nil {
+        utils.Logger.Error("Failed to confirm MFA enrollment", zap.Error(err), zap.Uint("user_id", userID))
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to confirm MFA enrollment"})
+        return
+    }
+
+    utils.Logger.Info("MFA enrollment confirmed", zap.Uint("user_id", userID))
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":      "MFA enabled",
+        "backup_codes": codes,
+    })
+}
+
+// DisableMFA turns off MFA for the current user, given a valid current
+// TOTP code.
+func DisableMFA(c *gin.Context) {
+    claims, exists := c.Get("user")
+    if !exists {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+        return
+    }
+    userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+    var input struct {
+        Code string `json:"code" binding:"required"`
+    }
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    var otp models.UserOTP
+    if err := db.DB.Where("user_id = ?", userID).First(&otp).Error; err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "MFA is not enabled"})
+        return
+    }
+
+    secret, err := decryptTOTPSecret(otp.Secret)
+    if err != nil || !verifyTOTP(secret, input.Code) {
+        if !checkMFAFailureLimit(c, userID) {
+            return
+        }
+        utils.Logger.Warn("MFA disable rejected - invalid code", zap.Uint("user_id", userID))
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid code"})
+        return
+    }
+
+    if err := db.DB.Delete(&otp).Error; err != nil {
+        utils.Logger.Error("Failed to disable MFA", zap.Error(err), zap.Uint("user_id", userID))
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to disable MFA"})
+        return
+    }
+
+    utils.Logger.Info("MFA disabled", zap.Uint("user_id", userID))
+
+    c.JSON(http.StatusOK, gin.H{"message": "MFA disabled"})
+}
+
+// RecoverMFA disables MFA using a one-time backup code instead of a TOTP
+// code, for when the authenticator device itself is lost. The consumed
+// code is removed from the stored set whether or not this call succeeds
+// in disabling MFA, so a leaked/guessed code can't be retried.
+func RecoverMFA(c *gin.Context) {
+    claims, exists := c.Get("user")
+    if !exists {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+        return
+    }
+    userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+    var input struct {
+        BackupCode string `json:"backup_code" binding:"required"`
+    }
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    var otp models.UserOTP
+    if err := db.DB.Where("user_id = ?", userID).First(&otp).Error; err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "MFA is not enabled"})
+        return
+    }
+
+    matchIdx := -1
+    for i, hash := range otp.BackupCodes {
+        if ok, _, _ := VerifyPassword(input.BackupCode, hash); ok {
+            matchIdx = i
+            break
+        }
+    }
+    if matchIdx == -1 {
+        if !checkMFAFailureLimit(c, userID) {
+            return
+        }
+        utils.Logger.Warn("MFA recovery rejected - invalid backup code", zap.Uint("user_id", userID))
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid backup code"})
+        return
+    }
+
+    if err := db.DB.Delete(&otp).Error; err != nil {
+        utils.Logger.Error("Failed to disable MFA via recovery", zap.Error(err), zap.Uint("user_id", userID))
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to disable MFA"})
+        return
+    }
+
+    utils.Logger.Info("MFA disabled via backup code recovery", zap.Uint("user_id", userID))
+
+    c.JSON(http.StatusOK, gin.H{"message": "MFA disabled using backup code, please re-enroll when ready"})
+}
+
+// LoginMFA completes a login that Login paused for MFA step-up: it
+// exchanges the short-lived mfa_token plus a current TOTP code for the
+// real session token.
+func LoginMFA(c *gin.Context) {
+    var input struct {
+        MFAToken string `json:"mfa_token" binding:"required"`
+        Code     string `json:"code" binding:"required"`
+    }
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    token, err := ParseToken(input.MFAToken)
+    if err != nil || !token.Valid {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa token"})
+        return
+    }
+    claims, ok := token.Claims.(jwt.MapClaims)
+    if !ok || claims["purpose"] != "mfa" {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa token"})
+        return
+    }
+    userID := uint(claims["sub"].(float64))
+
+    var user models.User
+    // Unscoped so the MFA step-up still completes for an account pending
+    // deletion (see DeleteUserAccount/UndeleteUserAccount); AuthMiddleware
+    // restricts the resulting token to POST /user/account/undelete.
+    if err := db.DB.Unscoped().Preload("OTP").First(&user, userID).Error; err != nil {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa token"})
+        return
+    }
+
+    if user.OTP == nil || user.OTP.ConfirmedAt == nil {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa token"})
+        return
+    }
+
+    secret, err := decryptTOTPSecret(user.OTP.Secret)
+    if err != nil || !verifyTOTP(secret, input.Code) {
+        if !checkMFAFailureLimit(c, userID) {
+            return
+        }
+        utils.Logger.Warn("MFA step-up failed - invalid code",
+            zap.Uint("user_id", userID),
+            zap.String("ip", c.ClientIP()),
+        )
+        middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+            UserID:    userID,
+            EventType: "2fa_verify",
+            Outcome:   "failure",
+            Details:   map[string]interface{}{"reason": "invalid code"},
+            IP:        c.ClientIP(),
+            UserAgent: c.Request.UserAgent(),
+        })
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid code"})
+        return
+    }
+
+    sessionToken, err := GenerateToken(user.ID, user.Username, string(user.Role))
+    if err != nil {
+        utils.Logger.Error("Failed to generate JWT token after MFA step-up", zap.Error(err), zap.Uint("user_id", userID))
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
+        return
+    }
+
+    refreshToken := issueSession(user.ID, c.ClientIP(), c.Request.UserAgent())
+
+    utils.Logger.Info("User completed MFA step-up and logged in",
+        zap.Uint("user_id", user.ID),
+        zap.String("username", user.Username),
+        zap.String("ip", c.ClientIP()),
+    )
+
+    middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+        UserID:    user.ID,
+        EventType: "2fa_verify",
+        Outcome:   "success",
+        IP:        c.ClientIP(),
+        UserAgent: c.Request.UserAgent(),
+    })
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":       "Login successful",
+        "token":         sessionToken,
+        "refresh_token": refreshToken,
+        "user": gin.H{
+            "id":       user.ID,
+            "username": user.Username,
+            "email":    user.Email,
+            "name":     user.Name,
+            "role":     user.Role,
+        },
+    })
+}
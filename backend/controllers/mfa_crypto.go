@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// mfaEncryptionKeyEnv names the env var holding the 32-byte, base64-encoded
+// AES-256 key used to encrypt UserOTP.Secret at rest, the same
+// nonce||ciphertext-blob approach services/psd2/crypto.go uses for bank
+// connection secrets.
+const mfaEncryptionKeyEnv = "MFA_ENCRYPTION_KEY"
+
+// encryptTOTPSecret AES-GCM encrypts a TOTP secret with the key configured
+// via MFA_ENCRYPTION_KEY and returns a base64-encoded nonce||ciphertext blob
+// safe to store in UserOTP.Secret.
+func encryptTOTPSecret(plaintext string) (string, error) {
+	gcm, err := mfaCipherFromEnv()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("mfa: generate nonce: %w", err)
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// decryptTOTPSecret reverses encryptTOTPSecret.
+func decryptTOTPSecret(encoded string) (string, error) {
+	gcm, err := mfaCipherFromEnv()
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.New("mfa: stored secret is not valid base64")
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(raw) < nonceSize {
+		return "", errors.New("mfa: stored secret is shorter than a nonce")
+	}
+
+	nonce, ciphertext := raw[:nonceSize], raw[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("mfa: decrypt secret: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+func mfaCipherFromEnv() (cipher.AEAD, error) {
+	encoded := os.Getenv(mfaEncryptionKeyEnv)
+	if encoded == "" {
+		return nil, errors.New("mfa: " + mfaEncryptionKeyEnv + " is not set")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("mfa: " + mfaEncryptionKeyEnv + " must be base64-encoded")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("mfa: build AES cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
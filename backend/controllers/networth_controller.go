@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+)
+
+// currentNetWorth sums the authenticated user's cash (Account.
+// CurrentBalance) and investments (Holding.InstitutionValueCents) into
+// a single snapshot, the same split NetWorthSnapshot persists daily.
+func currentNetWorth(userID uint) (cashCents, investmentsCents int64) {
+	var cash decimal.NullDecimal
+	db.DB.Model(&models.Account{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(SUM(current_balance), 0)").
+		Scan(&cash)
+
+	db.DB.Model(&models.Holding{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(SUM(institution_value_cents), 0)").
+		Scan(&investmentsCents)
+
+	return centsOf(cash.Decimal), investmentsCents
+}
+
+// GetNetWorth returns the authenticated user's persisted daily
+// NetWorthSnapshot series (see controllers.StartNetWorthSnapshotScheduler)
+// plus a live "today" point computed the same way, since today's snapshot
+// may not have run yet.
+func GetNetWorth(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var snapshots []models.NetWorthSnapshot
+	if err := db.DB.Where("user_id = ?", userID).Order("snapshot_date ASC").Find(&snapshots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch net worth history"})
+		return
+	}
+
+	cashCents, investmentsCents := currentNetWorth(userID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"history": snapshots,
+		"today": gin.H{
+			"snapshot_date":     time.Now().Format("2006-01-02"),
+			"cash_cents":        cashCents,
+			"investments_cents": investmentsCents,
+			"total_cents":       cashCents + investmentsCents,
+		},
+	})
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+)
+
+// StartNetWorthSnapshotScheduler launches a background goroutine that
+// periodically persists a NetWorthSnapshot for every user who doesn't
+// already have one for today, so GET /api/networth has a real historized
+// series instead of only ever reporting "today".
+func StartNetWorthSnapshotScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runNetWorthSnapshots()
+		}
+	}()
+}
+
+func runNetWorthSnapshots() {
+	var userIDs []uint
+	if err := db.DB.Model(&models.Account{}).Distinct("user_id").Pluck("user_id", &userIDs).Error; err != nil {
+		utils.Logger.Warn("net worth scheduler: failed to load users")
+		return
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	for _, userID := range userIDs {
+		var existing models.NetWorthSnapshot
+		err := db.DB.Where("user_id = ? AND snapshot_date = ?", userID, today).First(&existing).Error
+		if err == nil {
+			continue
+		}
+
+		cashCents, investmentsCents := currentNetWorth(userID)
+		snapshot := models.NetWorthSnapshot{
+			UserID:           userID,
+			SnapshotDate:     today,
+			CashCents:        cashCents,
+			InvestmentsCents: investmentsCents,
+			TotalCents:       cashCents + investmentsCents,
+		}
+		if err := db.DB.Create(&snapshot).Error; err != nil {
+			utils.Logger.Warn("net worth scheduler: failed to create snapshot")
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// GetNotificationPreferences handles GET /notification-preferences,
+// returning the authenticated user's digest/alert settings, or the
+// defaults a NotificationPreference row would get if one doesn't exist yet.
+func GetNotificationPreferences(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var pref models.NotificationPreference
+	if err := db.DB.Where("user_id = ?", userID).First(&pref).Error; err != nil {
+		pref = models.NotificationPreference{
+			UserID:                  userID,
+			Cadence:                 models.NotificationCadenceWeekly,
+			DayOfWeek:               1,
+			OverBudgetAlertsEnabled: true,
+		}
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
+
+// UpdateNotificationPreferences handles PUT /notification-preferences:
+// upserts the authenticated user's cadence, day-of-week, and over-budget
+// alert toggle.
+func UpdateNotificationPreferences(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input struct {
+		Cadence                 models.NotificationCadence `json:"cadence"`
+		DayOfWeek               *int                       `json:"day_of_week"`
+		OverBudgetAlertsEnabled *bool                      `json:"over_budget_alerts_enabled"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Cadence != "" && input.Cadence != models.NotificationCadenceWeekly && input.Cadence != models.NotificationCadenceMonthly {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cadence must be 'weekly' or 'monthly'"})
+		return
+	}
+	if input.DayOfWeek != nil && (*input.DayOfWeek < 0 || *input.DayOfWeek > 6) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "day_of_week must be between 0 and 6"})
+		return
+	}
+
+	var pref models.NotificationPreference
+	if err := db.DB.Where(models.NotificationPreference{UserID: userID}).
+		Attrs(models.NotificationPreference{
+			Cadence:                 models.NotificationCadenceWeekly,
+			DayOfWeek:               1,
+			OverBudgetAlertsEnabled: true,
+		}).
+		FirstOrCreate(&pref).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load notification preferences"})
+		return
+	}
+
+	if input.Cadence != "" {
+		pref.Cadence = input.Cadence
+	}
+	if input.DayOfWeek != nil {
+		pref.DayOfWeek = *input.DayOfWeek
+	}
+	if input.OverBudgetAlertsEnabled != nil {
+		pref.OverBudgetAlertsEnabled = *input.OverBudgetAlertsEnabled
+	}
+
+	if err := db.DB.Save(&pref).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
@@ -0,0 +1,759 @@
+package controllers
+
+import (
+    "context"
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "io"
+    "math/big"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "Personal-Finance-Tracker-backend/db"
+    "Personal-Finance-Tracker-backend/models"
+    "Personal-Finance-Tracker-backend/redis"
+    "Personal-Finance-Tracker-backend/utils"
+
+    "github.com/gin-gonic/gin"
+    jwt "github.com/golang-jwt/jwt/v5"
+    "go.uber.org/zap"
+)
+
+// oauthProviderConfig holds everything needed to drive one provider's
+// authorization-code flow. AuthURL/TokenURL/UserInfoURL (and, for OIDC
+// providers, Issuer/JWKSURL) are fixed per provider; ClientID/ClientSecret/
+// RedirectURL come from env vars.
+type oauthProviderConfig struct {
+    ClientID     string
+    ClientSecret string
+    RedirectURL  string
+    AuthURL      string
+    TokenURL     string
+    UserInfoURL  string
+    Scope        string
+
+    // Issuer and JWKSURL are set for providers that hand back a verifiable
+    // ID token (Google and any generic OIDC provider). When Issuer is set,
+    // OAuthCallback trusts the ID token's claims instead of making a
+    // separate userinfo request.
+    Issuer  string
+    JWKSURL string
+}
+
+// oauthProviders holds the providers configured via env vars, keyed by the
+// :provider route param ("google", "github", "oidc"). A provider is absent
+// from the map if its client ID/secret were not set.
+var oauthProviders = map[string]oauthProviderConfig{}
+
+// InitGoogleOAuth registers the Google provider if both a client ID and
+// secret are supplied (mirrors InitPlaidClient's configured-or-disabled
+// pattern in plaid_api.go).
+func InitGoogleOAuth(clientID, clientSecret, redirectURL string) {
+    if clientID == "" || clientSecret == "" {
+        return
+    }
+    oauthProviders["google"] = oauthProviderConfig{
+        ClientID:     clientID,
+        ClientSecret: clientSecret,
+        RedirectURL:  redirectURL,
+        AuthURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+        TokenURL:     "https://oauth2.googleapis.com/token",
+        UserInfoURL:  "https://www.googleapis.com/oauth2/v3/userinfo",
+        Scope:        "openid email profile",
+        Issuer:       "https://accounts.google.com",
+        JWKSURL:      "https://www.googleapis.com/oauth2/v3/certs",
+    }
+}
+
+// InitGitHubOAuth registers the GitHub provider if both a client ID and
+// secret are supplied. GitHub's OAuth app flow has no ID token, so this
+// provider is never driven by the Issuer/JWKSURL path.
+func InitGitHubOAuth(clientID, clientSecret, redirectURL string) {
+    if clientID == "" || clientSecret == "" {
+        return
+    }
+    oauthProviders["github"] = oauthProviderConfig{
+        ClientID:     clientID,
+        ClientSecret: clientSecret,
+        RedirectURL:  redirectURL,
+        AuthURL:      "https://github.com/login/oauth/authorize",
+        TokenURL:     "https://github.com/login/oauth/access_token",
+        UserInfoURL:  "https://api.github.com/user",
+        Scope:        "read:user user:email",
+    }
+}
+
+// InitOIDCProvider registers a generic OIDC provider under name (e.g.
+// "oidc", or a company's own identity provider) by discovering its
+// authorization/token/jwks endpoints from issuer's well-known document. It
+// no-ops if issuer/clientID/clientSecret aren't all supplied, and returns
+// an error if discovery fails so the caller can log and continue without
+// this provider rather than failing startup.
+func InitOIDCProvider(name, issuer, clientID, clientSecret, redirectURL string) error {
+    if name == "" || issuer == "" || clientID == "" || clientSecret == "" {
+        return nil
+    }
+
+    req, err := http.NewRequest(http.MethodGet, strings.TrimRight(issuer, "/")+"/.well-known/openid-configuration", nil)
+    if err != nil {
+        return err
+    }
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        return err
+    }
+
+    var discovery struct {
+        Issuer                string `json:"issuer"`
+        AuthorizationEndpoint string `json:"authorization_endpoint"`
+        TokenEndpoint         string `json:"token_endpoint"`
+        JWKSURI               string `json:"jwks_uri"`
+        UserinfoEndpoint      string `json:"userinfo_endpoint"`
+    }
+    if err := json.Unmarshal(body, &discovery); err != nil {
+        return err
+    }
+    if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+        return fmt.Errorf("oidc discovery document for %q is missing required endpoints", issuer)
+    }
+
+    oauthProviders[name] = oauthProviderConfig{
+        ClientID:     clientID,
+        ClientSecret: clientSecret,
+        RedirectURL:  redirectURL,
+        AuthURL:      discovery.AuthorizationEndpoint,
+        TokenURL:     discovery.TokenEndpoint,
+        UserInfoURL:  discovery.UserinfoEndpoint,
+        Scope:        "openid email profile",
+        Issuer:       discovery.Issuer,
+        JWKSURL:      discovery.JWKSURI,
+    }
+    return nil
+}
+
+// oauthFlowRecord is what a login/link attempt's state resolves to: the
+// provider it was started for, the PKCE verifier to present at the token
+// endpoint, and, for an account-linking attempt, the user it should be
+// attached to instead of minting a fresh session.
+type oauthFlowRecord struct {
+    Provider     string `json:"provider"`
+    CodeVerifier string `json:"code_verifier"`
+    LinkUserID   uint   `json:"link_user_id,omitempty"`
+}
+
+const oauthFlowTTL = 10 * time.Minute
+
+// oauthFlowStore is the pluggable backend an in-flight login/link attempt's
+// state and PKCE verifier are kept in, mirroring sessionStore/
+// tokenBlacklistStore: in-memory by default, Redis-backed when configured
+// so a flow started on one replica can be completed on another. A state is
+// single-use - take() both looks it up and invalidates it.
+type oauthFlowStore interface {
+    put(state string, record oauthFlowRecord)
+    take(state string) (oauthFlowRecord, bool)
+}
+
+type memoryOAuthFlowStore struct {
+    mu      sync.Mutex
+    records map[string]struct {
+        record oauthFlowRecord
+        expiry time.Time
+    }
+}
+
+func (s *memoryOAuthFlowStore) put(state string, record oauthFlowRecord) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.records == nil {
+        s.records = make(map[string]struct {
+            record oauthFlowRecord
+            expiry time.Time
+        })
+    }
+    s.records[state] = struct {
+        record oauthFlowRecord
+        expiry time.Time
+    }{record: record, expiry: time.Now().Add(oauthFlowTTL)}
+}
+
+func (s *memoryOAuthFlowStore) take(state string) (oauthFlowRecord, bool) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    entry, ok := s.records[state]
+    delete(s.records, state)
+    if !ok || time.Now().After(entry.expiry) {
+        return oauthFlowRecord{}, false
+    }
+    return entry.record, true
+}
+
+var defaultOAuthFlowStore = &memoryOAuthFlowStore{}
+
+type redisOAuthFlowStore struct{}
+
+func (redisOAuthFlowStore) put(state string, record oauthFlowRecord) {
+    payload, err := json.Marshal(record)
+    if err != nil {
+        return
+    }
+    ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+    defer cancel()
+    redis.RDB.Set(ctx, "oauth:state:"+state, payload, oauthFlowTTL)
+}
+
+func (redisOAuthFlowStore) take(state string) (oauthFlowRecord, bool) {
+    ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+    defer cancel()
+    payload, err := redis.RDB.GetDel(ctx, "oauth:state:"+state).Result()
+    if err != nil {
+        return oauthFlowRecord{}, false
+    }
+    var record oauthFlowRecord
+    if err := json.Unmarshal([]byte(payload), &record); err != nil {
+        return oauthFlowRecord{}, false
+    }
+    return record, true
+}
+
+func activeOAuthFlowStore() oauthFlowStore {
+    if redis.RDB != nil {
+        return redisOAuthFlowStore{}
+    }
+    return defaultOAuthFlowStore
+}
+
+// OAuthLogin starts the authorization-code flow for :provider: it generates
+// a CSRF state and PKCE code verifier, persists them in oauthFlowStore, and
+// redirects the browser to the provider's consent screen with the matching
+// code_challenge.
+func OAuthLogin(c *gin.Context) {
+    startOAuthFlow(c, 0)
+}
+
+// OAuthLinkStart begins the same flow as OAuthLogin but records the
+// currently authenticated user as the link target, so OAuthCallback
+// attaches the resulting identity to that user instead of logging in as
+// whoever it belongs to.
+func OAuthLinkStart(c *gin.Context) {
+    claims, exists := c.Get("user")
+    if !exists {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+        return
+    }
+    userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+    startOAuthFlow(c, userID)
+}
+
+func startOAuthFlow(c *gin.Context, linkUserID uint) {
+    provider := c.Param("provider")
+    cfg, ok := oauthProviders[provider]
+    if !ok {
+        utils.Logger.Warn("OAuth flow requested for unconfigured provider",
+            zap.String("provider", provider),
+        )
+        c.JSON(http.StatusNotFound, gin.H{"error": "unknown or unconfigured oauth provider"})
+        return
+    }
+
+    state, err := generateOAuthState()
+    if err != nil {
+        utils.Logger.Error("Failed to generate oauth state", zap.Error(err))
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start oauth flow"})
+        return
+    }
+    codeVerifier, err := generateOAuthState()
+    if err != nil {
+        utils.Logger.Error("Failed to generate oauth pkce verifier", zap.Error(err))
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start oauth flow"})
+        return
+    }
+
+    activeOAuthFlowStore().put(state, oauthFlowRecord{
+        Provider:     provider,
+        CodeVerifier: codeVerifier,
+        LinkUserID:   linkUserID,
+    })
+
+    params := url.Values{}
+    params.Set("client_id", cfg.ClientID)
+    params.Set("redirect_uri", cfg.RedirectURL)
+    params.Set("response_type", "code")
+    params.Set("scope", cfg.Scope)
+    params.Set("state", state)
+    params.Set("code_challenge", pkceCodeChallenge(codeVerifier))
+    params.Set("code_challenge_method", "S256")
+
+    c.Redirect(http.StatusFound, cfg.AuthURL+"?"+params.Encode())
+}
+
+// OAuthCallback completes the authorization-code flow: it resolves the
+// state to its oauthFlowRecord, exchanges the code (with its PKCE verifier)
+// for tokens, and either verifies and reads the ID token or falls back to
+// the provider's userinfo endpoint. A flow started by OAuthLinkStart
+// attaches the resulting identity to that flow's user; otherwise it
+// upserts a local User (PasswordHash left empty for OAuth-only accounts)
+// plus the linking OAuthIdentity row, and returns the same
+// {message, token, user} shape Login does.
+func OAuthCallback(c *gin.Context) {
+    provider := c.Param("provider")
+    cfg, ok := oauthProviders[provider]
+    if !ok {
+        c.JSON(http.StatusNotFound, gin.H{"error": "unknown or unconfigured oauth provider"})
+        return
+    }
+
+    state := c.Query("state")
+    flow, ok := activeOAuthFlowStore().take(state)
+    if state == "" || !ok || flow.Provider != provider {
+        utils.Logger.Warn("OAuth callback state mismatch",
+            zap.String("provider", provider),
+            zap.String("ip", c.ClientIP()),
+        )
+        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired oauth state"})
+        return
+    }
+
+    code := c.Query("code")
+    if code == "" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "missing authorization code"})
+        return
+    }
+
+    tokens, err := exchangeOAuthCode(cfg, code, flow.CodeVerifier)
+    if err != nil {
+        utils.Logger.Error("OAuth code exchange failed",
+            zap.Error(err),
+            zap.String("provider", provider),
+        )
+        c.JSON(http.StatusBadGateway, gin.H{"error": "failed to exchange oauth code"})
+        return
+    }
+
+    var subject, email, name string
+    if cfg.Issuer != "" {
+        subject, email, name, err = verifyOAuthIDToken(cfg, tokens.IDToken)
+    } else {
+        subject, email, name, err = fetchOAuthUserInfo(provider, cfg, tokens.AccessToken)
+    }
+    if err != nil {
+        utils.Logger.Error("OAuth identity verification failed",
+            zap.Error(err),
+            zap.String("provider", provider),
+        )
+        c.JSON(http.StatusBadGateway, gin.H{"error": "failed to verify oauth identity"})
+        return
+    }
+
+    if flow.LinkUserID != 0 {
+        if err := linkOAuthIdentity(flow.LinkUserID, provider, subject, email); err != nil {
+            utils.Logger.Error("Failed to link oauth identity",
+                zap.Error(err),
+                zap.String("provider", provider),
+                zap.Uint("user_id", flow.LinkUserID),
+            )
+            c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+            return
+        }
+        c.JSON(http.StatusOK, gin.H{
+            "message":  "Account linked successfully",
+            "provider": provider,
+        })
+        return
+    }
+
+    user, err := upsertOAuthUser(provider, subject, email, name)
+    if err != nil {
+        utils.Logger.Error("Failed to upsert oauth user",
+            zap.Error(err),
+            zap.String("provider", provider),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to complete oauth login"})
+        return
+    }
+
+    token, err := GenerateToken(user.ID, user.Username, string(user.Role))
+    if err != nil {
+        utils.Logger.Error("Failed to generate JWT token for oauth login",
+            zap.Error(err),
+            zap.Uint("user_id", user.ID),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
+        return
+    }
+
+    refreshToken := issueSession(user.ID, c.ClientIP(), c.Request.UserAgent())
+
+    utils.Logger.Info("User logged in via oauth",
+        zap.Uint("user_id", user.ID),
+        zap.String("username", user.Username),
+        zap.String("provider", provider),
+        zap.String("ip", c.ClientIP()),
+    )
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":       "Login successful",
+        "token":         token,
+        "refresh_token": refreshToken,
+        "user": gin.H{
+            "id":       user.ID,
+            "username": user.Username,
+            "email":    user.Email,
+            "name":     user.Name,
+            "role":     user.Role,
+        },
+    })
+}
+
+func generateOAuthState() (string, error) {
+    raw := make([]byte, 32)
+    if _, err := rand.Read(raw); err != nil {
+        return "", err
+    }
+    return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// pkceCodeChallenge derives the S256 code_challenge sent at the authorize
+// endpoint from a code_verifier, per RFC 7636.
+func pkceCodeChallenge(codeVerifier string) string {
+    sum := sha256.Sum256([]byte(codeVerifier))
+    return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// oauthTokenResponse is the token endpoint's response, normalized across
+// providers. IDToken is only populated by OIDC-compliant providers
+// (Google, and any provider registered via InitOIDCProvider).
+type oauthTokenResponse struct {
+    AccessToken string
+    IDToken     string
+}
+
+// exchangeOAuthCode trades an authorization code (plus its PKCE verifier)
+// for tokens via the provider's token endpoint, hand-rolled with net/http
+// the same way ai_controller.go talks to the AI service rather than
+// pulling in an oauth2 client library.
+func exchangeOAuthCode(cfg oauthProviderConfig, code, codeVerifier string) (oauthTokenResponse, error) {
+    form := url.Values{}
+    form.Set("client_id", cfg.ClientID)
+    form.Set("client_secret", cfg.ClientSecret)
+    form.Set("code", code)
+    form.Set("redirect_uri", cfg.RedirectURL)
+    form.Set("grant_type", "authorization_code")
+    form.Set("code_verifier", codeVerifier)
+
+    req, err := http.NewRequest(http.MethodPost, cfg.TokenURL, strings.NewReader(form.Encode()))
+    if err != nil {
+        return oauthTokenResponse{}, err
+    }
+    req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    req.Header.Set("Accept", "application/json")
+
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil {
+        return oauthTokenResponse{}, err
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        return oauthTokenResponse{}, err
+    }
+
+    var tokenResp struct {
+        AccessToken string `json:"access_token"`
+        IDToken     string `json:"id_token"`
+        Error       string `json:"error"`
+    }
+    if err := json.Unmarshal(body, &tokenResp); err != nil {
+        return oauthTokenResponse{}, err
+    }
+    if tokenResp.AccessToken == "" {
+        return oauthTokenResponse{}, &oauthError{provider: "token exchange", reason: tokenResp.Error}
+    }
+    return oauthTokenResponse{AccessToken: tokenResp.AccessToken, IDToken: tokenResp.IDToken}, nil
+}
+
+// fetchOAuthUserInfo calls the provider's userinfo endpoint and normalizes
+// the response into (subject, email, name). Subject is the provider's
+// stable per-user ID, used as the OAuthIdentity lookup key since email can
+// change or be unset. Used for providers with no verifiable ID token
+// (GitHub); OIDC-compliant providers go through verifyOAuthIDToken instead.
+func fetchOAuthUserInfo(provider string, cfg oauthProviderConfig, accessToken string) (subject, email, name string, err error) {
+    req, err := http.NewRequest(http.MethodGet, cfg.UserInfoURL, nil)
+    if err != nil {
+        return "", "", "", err
+    }
+    req.Header.Set("Authorization", "Bearer "+accessToken)
+    req.Header.Set("Accept", "application/json")
+
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil {
+        return "", "", "", err
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        return "", "", "", err
+    }
+
+    switch provider {
+    case "github":
+        var info struct {
+            ID    int64  `json:"id"`
+            Email string `json:"email"`
+            Name  string `json:"name"`
+            Login string `json:"login"`
+        }
+        if err := json.Unmarshal(body, &info); err != nil {
+            return "", "", "", err
+        }
+        name = info.Name
+        if name == "" {
+            name = info.Login
+        }
+        return strconv.FormatInt(info.ID, 10), info.Email, name, nil
+    default:
+        return "", "", "", &oauthError{provider: provider, reason: "unsupported provider"}
+    }
+}
+
+// oidcIDTokenClaims is the set of ID token claims OAuthCallback trusts.
+type oidcIDTokenClaims struct {
+    jwt.RegisteredClaims
+    Email string `json:"email"`
+    Name  string `json:"name"`
+}
+
+// verifyOAuthIDToken verifies idToken's signature against cfg's JWKS, and
+// that its issuer/audience/expiry match cfg, returning (subject, email,
+// name) from its claims. This is what lets OAuthCallback trust a provider's
+// identity claims without an extra userinfo round trip.
+func verifyOAuthIDToken(cfg oauthProviderConfig, idToken string) (subject, email, name string, err error) {
+    if idToken == "" {
+        return "", "", "", fmt.Errorf("provider did not return an id_token")
+    }
+
+    var claims oidcIDTokenClaims
+    _, err = jwt.ParseWithClaims(idToken, &claims, func(token *jwt.Token) (interface{}, error) {
+        if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+            return nil, fmt.Errorf("unexpected id_token signing method: %v", token.Header["alg"])
+        }
+        kid, _ := token.Header["kid"].(string)
+        return fetchJWKSPublicKey(cfg.JWKSURL, kid)
+    })
+    if err != nil {
+        return "", "", "", err
+    }
+
+    if cfg.Issuer != "" && claims.Issuer != cfg.Issuer {
+        return "", "", "", fmt.Errorf("id_token issuer %q does not match expected %q", claims.Issuer, cfg.Issuer)
+    }
+    audienceOK := false
+    for _, aud := range claims.Audience {
+        if aud == cfg.ClientID {
+            audienceOK = true
+            break
+        }
+    }
+    if !audienceOK {
+        return "", "", "", fmt.Errorf("id_token audience does not include this client")
+    }
+
+    return claims.Subject, claims.Email, claims.Name, nil
+}
+
+// jwksCacheTTL bounds how long a fetched JWKS document is reused before
+// being re-fetched, so a provider's key rotation is picked up without
+// hitting its JWKS endpoint on every single login.
+const jwksCacheTTL = 1 * time.Hour
+
+var (
+    jwksCacheMu sync.Mutex
+    jwksCache   = map[string]struct {
+        keys    map[string]*rsa.PublicKey
+        expiry  time.Time
+    }{}
+)
+
+// fetchJWKSPublicKey returns the RSA public key for kid from jwksURL,
+// fetching and caching the whole JWKS document for jwksCacheTTL.
+func fetchJWKSPublicKey(jwksURL, kid string) (*rsa.PublicKey, error) {
+    jwksCacheMu.Lock()
+    cached, ok := jwksCache[jwksURL]
+    jwksCacheMu.Unlock()
+
+    if !ok || time.Now().After(cached.expiry) {
+        keys, err := fetchJWKS(jwksURL)
+        if err != nil {
+            return nil, err
+        }
+        cached = struct {
+            keys    map[string]*rsa.PublicKey
+            expiry  time.Time
+        }{keys: keys, expiry: time.Now().Add(jwksCacheTTL)}
+        jwksCacheMu.Lock()
+        jwksCache[jwksURL] = cached
+        jwksCacheMu.Unlock()
+    }
+
+    key, ok := cached.keys[kid]
+    if !ok {
+        return nil, fmt.Errorf("no jwks key found for kid %q", kid)
+    }
+    return key, nil
+}
+
+// fetchJWKS downloads jwksURL and parses its RSA keys into a kid -> public
+// key map.
+func fetchJWKS(jwksURL string) (map[string]*rsa.PublicKey, error) {
+    resp, err := http.Get(jwksURL)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        return nil, err
+    }
+
+    var doc struct {
+        Keys []struct {
+            Kty string `json:"kty"`
+            Kid string `json:"kid"`
+            N   string `json:"n"`
+            E   string `json:"e"`
+        } `json:"keys"`
+    }
+    if err := json.Unmarshal(body, &doc); err != nil {
+        return nil, err
+    }
+
+    keys := make(map[string]*rsa.PublicKey, len(doc.Keys))
+    for _, k := range doc.Keys {
+        if k.Kty != "RSA" {
+            continue
+        }
+        pub, err := rsaPublicKeyFromJWK(k.N, k.E)
+        if err != nil {
+            continue
+        }
+        keys[k.Kid] = pub
+    }
+    return keys, nil
+}
+
+// rsaPublicKeyFromJWK builds an *rsa.PublicKey from a JWK's base64url-
+// encoded modulus (n) and exponent (e).
+func rsaPublicKeyFromJWK(n, e string) (*rsa.PublicKey, error) {
+    nBytes, err := base64.RawURLEncoding.DecodeString(n)
+    if err != nil {
+        return nil, err
+    }
+    eBytes, err := base64.RawURLEncoding.DecodeString(e)
+    if err != nil {
+        return nil, err
+    }
+
+    return &rsa.PublicKey{
+        N: new(big.Int).SetBytes(nBytes),
+        E: int(new(big.Int).SetBytes(eBytes).Int64()),
+    }, nil
+}
+
+// upsertOAuthUser finds the User already linked to (provider, subject),
+// links an existing User matched by email, or creates a new User with an
+// empty PasswordHash (an OAuth-only account has no password to verify).
+func upsertOAuthUser(provider, subject, email, name string) (models.User, error) {
+    var identity models.OAuthIdentity
+    err := db.DB.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+    if err == nil {
+        var user models.User
+        if err := db.DB.First(&user, identity.UserID).Error; err != nil {
+            return models.User{}, err
+        }
+        return user, nil
+    }
+
+    var user models.User
+    linkingExistingUser := false
+    if email != "" {
+        if err := db.DB.Where("email = ?", email).First(&user).Error; err == nil {
+            linkingExistingUser = true
+        }
+    }
+
+    if !linkingExistingUser {
+        username := provider + "_" + subject
+        user = models.User{
+            Username:     username,
+            Email:        email,
+            PasswordHash: "",
+            Name:         name,
+            CreatedAt:    time.Now(),
+        }
+        if err := db.DB.Create(&user).Error; err != nil {
+            return models.User{}, err
+        }
+    }
+
+    identity = models.OAuthIdentity{
+        UserID:   user.ID,
+        Provider: provider,
+        Subject:  subject,
+        Email:    email,
+    }
+    if err := db.DB.Create(&identity).Error; err != nil {
+        return models.User{}, err
+    }
+
+    return user, nil
+}
+
+// linkOAuthIdentity attaches (provider, subject) to userID, for
+// OAuthLinkStart/OAuthCallback's account-linking flow. It rejects linking
+// an identity that's already attached to a different user.
+func linkOAuthIdentity(userID uint, provider, subject, email string) error {
+    var existing models.OAuthIdentity
+    err := db.DB.Where("provider = ? AND subject = ?", provider, subject).First(&existing).Error
+    if err == nil {
+        if existing.UserID != userID {
+            return fmt.Errorf("this %s account is already linked to another user", provider)
+        }
+        return nil
+    }
+
+    identity := models.OAuthIdentity{
+        UserID:   userID,
+        Provider: provider,
+        Subject:  subject,
+        Email:    email,
+    }
+    return db.DB.Create(&identity).Error
+}
+
+// oauthError is a small local error type so token-exchange/userinfo
+// failures carry enough context to log without leaking provider response
+// bodies back to the client.
+type oauthError struct {
+    provider string
+    reason   string
+}
+
+func (e *oauthError) Error() string {
+    return e.provider + ": " + e.reason
+}
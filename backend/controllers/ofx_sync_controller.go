@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/importers"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/psd2"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+)
+
+// ofxSyncWindow is how far back SyncAccountOFX asks the bank's OFX server
+// to report transactions for, mirroring investmentsSyncWindow's role for
+// Plaid investments.
+const ofxSyncWindow = 90 * 24 * time.Hour
+
+// buildOFXStatementRequest assembles an OFX 2.x SGML signon + statement
+// request for account, using CCSTMTRQ for a credit account (no BANKID,
+// ACCTID only) and STMTRQ for everything else.
+func buildOFXStatementRequest(account models.Account, password string) string {
+	now := time.Now().UTC().Format("20060102150405")
+	start := time.Now().Add(-ofxSyncWindow).UTC().Format("20060102")
+
+	signon := fmt.Sprintf(`<SIGNONMSGSRQV1>
+<SONRQ>
+<DTCLIENT>%s
+<USERID>%s
+<USERPASS>%s
+<LANGUAGE>ENG
+<FI>
+<ORG>%s
+<FID>%s
+</FI>
+<APPID>QWIN
+<APPVER>2700
+</SONRQ>
+</SIGNONMSGSRQV1>`, now, account.OFXUser, password, account.OFXOrg, account.OFXFID)
+
+	var body string
+	if account.Type == models.AccountCredit {
+		body = fmt.Sprintf(`<CREDITCARDMSGSRQV1>
+<CCSTMTTRNRQ>
+<TRNUID>%s
+<CLTCOOKIE>1
+<CCSTMTRQ>
+<CCACCTFROM>
+<ACCTID>%s
+</CCACCTFROM>
+<INCTRAN>
+<DTSTART>%s
+<INCLUDE>Y
+</INCTRAN>
+</CCSTMTRQ>
+</CCSTMTTRNRQ>
+</CREDITCARDMSGSRQV1>`, now, account.OFXBankID, start)
+	} else {
+		body = fmt.Sprintf(`<BANKMSGSRQV1>
+<STMTTRNRQ>
+<TRNUID>%s
+<CLTCOOKIE>1
+<STMTRQ>
+<BANKACCTFROM>
+<BANKID>%s
+<ACCTID>%s
+<ACCTTYPE>%s
+</BANKACCTFROM>
+<INCTRAN>
+<DTSTART>%s
+<INCLUDE>Y
+</INCTRAN>
+</STMTRQ>
+</STMTTRNRQ>
+</BANKMSGSRQV1>`, now, account.OFXBankID, account.OFXBankID, account.OFXAcctType, start)
+	}
+
+	return fmt.Sprintf(`OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+CHARSET:1252
+COMPRESSION:NONE
+OLDFILEUID:NONE
+NEWFILEUID:NONE
+
+<OFX>
+%s
+%s
+</OFX>`, signon, body)
+}
+
+// SyncAccountOFX pulls new transactions from account's OFX Direct Connect
+// server (see Account.OFXURL and friends) and imports them through the same
+// importers.OFXParser/ContentHash dedup path ImportTransactions uses for an
+// uploaded file.
+func SyncAccountOFX(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	accountID := c.Param("id")
+
+	var account models.Account
+	if err := db.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	if account.OFXURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account has no OFX Direct Connect connection configured"})
+		return
+	}
+
+	password, err := psd2.DecryptSecret(account.OFXPassword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decrypt OFX password"})
+		return
+	}
+
+	request := buildOFXStatementRequest(account, password)
+
+	resp, err := http.Post(account.OFXURL, "application/x-ofx", bytes.NewBufferString(request))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to reach OFX server"})
+		return
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to read OFX response"})
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "OFX server returned an error", "status": resp.StatusCode})
+		return
+	}
+
+	parsed, err := importers.OFXParser{}.Parse(data)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to parse OFX response"})
+		return
+	}
+
+	tx := db.DB.Begin()
+	imported := 0
+	results := make([]importRowResult, 0, len(parsed))
+
+	for _, row := range parsed {
+		hash := importers.ContentHash(account.ID, row)
+
+		var count int64
+		tx.Model(&models.Transaction{}).Where("import_hash = ?", hash).Count(&count)
+		if count > 0 {
+			results = append(results, importRowResult{
+				Status:      ImportRowDuplicate,
+				TxnDate:     row.TxnDate.Format("2006-01-02"),
+				AmountCents: row.AmountCents,
+				Payee:       row.Payee,
+			})
+			continue
+		}
+
+		hashCopy := hash
+		transaction := models.Transaction{
+			UserID:      userID,
+			AccountID:   account.ID,
+			Amount:      decimal.NewFromInt(row.AmountCents).Div(decimal.NewFromInt(100)),
+			Description: row.Payee,
+			TxnDate:     row.TxnDate,
+			ImportHash:  &hashCopy,
+		}
+
+		if err := tx.Create(&transaction).Error; err != nil {
+			results = append(results, importRowResult{
+				Status:      ImportRowError,
+				TxnDate:     row.TxnDate.Format("2006-01-02"),
+				AmountCents: row.AmountCents,
+				Payee:       row.Payee,
+				Error:       err.Error(),
+			})
+			continue
+		}
+
+		imported++
+		results = append(results, importRowResult{
+			Status:      ImportRowImported,
+			TxnDate:     row.TxnDate.Format("2006-01-02"),
+			AmountCents: row.AmountCents,
+			Payee:       row.Payee,
+		})
+	}
+
+	if imported > 0 {
+		var total decimal.NullDecimal
+		tx.Model(&models.Transaction{}).
+			Where("account_id = ?", account.ID).
+			Select("COALESCE(SUM(amount), 0)").
+			Scan(&total)
+
+		newBalance := decimal.NewFromInt(account.InitialBalanceCents).Div(decimal.NewFromInt(100)).Add(total.Decimal)
+		if err := tx.Model(&account).Update("current_balance", newBalance).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update account balance"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit OFX sync"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"imported": imported, "results": results})
+}
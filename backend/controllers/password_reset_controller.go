@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+	"Personal-Finance-Tracker-backend/utils/mailer"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// passwordResetTTL is how long a POST /password/forgot link stays valid.
+const passwordResetTTL = 1 * time.Hour
+
+// ForgotPassword always responds 200 - whether or not email matches an
+// account - so the endpoint can't be used to enumerate registered emails.
+// On a match it emails a one-time reset link; callers never see the
+// distinction, but it's still rate-limited per submitted email address (in
+// addition to the per-IP limit applied at the route) so it can't be used to
+// spam a mailbox either.
+func ForgotPassword(c *gin.Context) {
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.CheckKeyLimit(c, "password_reset_email:"+input.Email, middleware.PasswordResetRequestRate, "too many password reset requests, try again later") {
+		middleware.IncrementPasswordResetRequest("rate_limited")
+		return
+	}
+
+	var user models.User
+	if err := db.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
+		middleware.IncrementPasswordResetRequest("unknown_email")
+		c.JSON(http.StatusOK, gin.H{"message": "if that email is registered, a reset link has been sent"})
+		return
+	}
+
+	token, err := issuePasswordResetToken(user.ID)
+	if err != nil {
+		utils.Logger.Error("Failed to create password reset token", zap.Error(err), zap.Uint("user_id", user.ID))
+		middleware.IncrementPasswordResetRequest("error")
+		c.JSON(http.StatusOK, gin.H{"message": "if that email is registered, a reset link has been sent"})
+		return
+	}
+
+	resetLink := os.Getenv("FRONTEND_URL") + "/reset?token=" + token
+	if err := mailer.Send(user.Email, "Reset your password", "Reset your password using this link: "+resetLink); err != nil {
+		utils.Logger.Error("Failed to send password reset email", zap.Error(err), zap.Uint("user_id", user.ID))
+		middleware.IncrementPasswordResetRequest("mailer_error")
+		c.JSON(http.StatusOK, gin.H{"message": "if that email is registered, a reset link has been sent"})
+		return
+	}
+
+	utils.Logger.Info("Password reset requested", zap.Uint("user_id", user.ID), zap.String("ip", c.ClientIP()))
+	middleware.IncrementPasswordResetRequest("sent")
+	c.JSON(http.StatusOK, gin.H{"message": "if that email is registered, a reset link has been sent"})
+}
+
+// issuePasswordResetToken generates a random token, persists its hash as a
+// pending models.PasswordReset for userID, and returns the raw token to
+// email to the user - the raw value is never stored.
+func issuePasswordResetToken(userID uint) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	sum := sha256.Sum256([]byte(token))
+
+	reset := models.PasswordReset{
+		UserID:    userID,
+		TokenHash: hex.EncodeToString(sum[:]),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+	if err := db.DB.Create(&reset).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ResetPassword redeems a token issued by ForgotPassword, setting a new
+// password and logging every session for the account out - same
+// logout-all-on-credential-change rule ChangePassword follows.
+func ResetPassword(c *gin.Context) {
+	var input struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required,min=6"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(input.Token))
+	tokenHash := hex.EncodeToString(sum[:])
+
+	var reset models.PasswordReset
+	if err := db.DB.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, time.Now()).First(&reset).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired reset token"})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, reset.UserID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired reset token"})
+		return
+	}
+
+	newHash, err := HashPassword(input.NewPassword)
+	if err != nil {
+		utils.Logger.Error("Failed to hash new password during reset", zap.Error(err), zap.Uint("user_id", user.ID))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset password"})
+		return
+	}
+	user.PasswordHash = newHash
+	user.UpdatedAt = time.Now()
+	if err := db.DB.Save(&user).Error; err != nil {
+		utils.Logger.Error("Failed to save new password during reset", zap.Error(err), zap.Uint("user_id", user.ID))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset password"})
+		return
+	}
+
+	now := time.Now()
+	reset.UsedAt = &now
+	if err := db.DB.Save(&reset).Error; err != nil {
+		utils.Logger.Error("Failed to mark password reset token used", zap.Error(err), zap.Uint("user_id", user.ID))
+	}
+
+	endAllSessions(user.ID)
+
+	utils.Logger.Info("Password reset via token", zap.Uint("user_id", user.ID), zap.String("ip", c.ClientIP()))
+	c.JSON(http.StatusOK, gin.H{"message": "password has been reset"})
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+)
+
+// resolvePeriodPreset turns a `period=` query value into a concrete [from,
+// to] range anchored on now, so GetSpendSummary/GetCashflow/
+// GetMonthlyTrends/GetFiscalDashboard don't each have to compute "this
+// quarter" or "last month" from raw from/to query params. Recognized
+// presets: month, yestermonth, quarter, yesterquarter, year, yesteryear,
+// ytd.
+func resolvePeriodPreset(preset string, now time.Time) (from, to time.Time, err error) {
+	loc := now.Location()
+
+	startOfMonth := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	}
+	startOfQuarter := func(t time.Time) time.Time {
+		quarterMonth := time.Month(((int(t.Month())-1)/3)*3 + 1)
+		return time.Date(t.Year(), quarterMonth, 1, 0, 0, 0, 0, loc)
+	}
+	endOfDay := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, loc)
+	}
+
+	switch preset {
+	case "month":
+		from = startOfMonth(now)
+		to = endOfDay(from.AddDate(0, 1, -1))
+	case "yestermonth":
+		from = startOfMonth(now).AddDate(0, -1, 0)
+		to = endOfDay(startOfMonth(now).AddDate(0, 0, -1))
+	case "quarter":
+		from = startOfQuarter(now)
+		to = endOfDay(from.AddDate(0, 3, -1))
+	case "yesterquarter":
+		thisQuarterStart := startOfQuarter(now)
+		from = thisQuarterStart.AddDate(0, -3, 0)
+		to = endOfDay(thisQuarterStart.AddDate(0, 0, -1))
+	case "year":
+		from = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, loc)
+		to = endOfDay(time.Date(now.Year(), 12, 31, 0, 0, 0, 0, loc))
+	case "yesteryear":
+		from = time.Date(now.Year()-1, 1, 1, 0, 0, 0, 0, loc)
+		to = endOfDay(time.Date(now.Year()-1, 12, 31, 0, 0, 0, 0, loc))
+	case "ytd":
+		from = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, loc)
+		to = endOfDay(now)
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("unknown period preset %q", preset)
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// plaidErrorBody duck-types the Body() []byte method every
+// openapi-generator-produced error type in plaid-go implements, so
+// plaidErrorCode can read the raw JSON body Plaid sent back without
+// depending on the SDK's exact generated error type name.
+type plaidErrorBody interface {
+	Body() []byte
+}
+
+// plaidAPIError is the subset of Plaid's error response schema
+// (https://plaid.com/docs/errors/) this package acts on.
+type plaidAPIError struct {
+	ErrorType    string `json:"error_type"`
+	ErrorCode    string `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// plaidErrorCode extracts the machine-readable error_code Plaid put in an
+// API error's JSON body (e.g. "ITEM_LOGIN_REQUIRED", "RATE_LIMIT_EXCEEDED",
+// "INVALID_ACCESS_TOKEN") so handlers can act on it instead of pattern
+// matching err.Error() strings. Returns "" if err isn't a Plaid API error,
+// or doesn't carry a body plaidErrorCode can parse.
+func plaidErrorCode(err error) string {
+	var withBody plaidErrorBody
+	if !errors.As(err, &withBody) {
+		return ""
+	}
+	var parsed plaidAPIError
+	if jsonErr := json.Unmarshal(withBody.Body(), &parsed); jsonErr != nil {
+		return ""
+	}
+	return parsed.ErrorCode
+}
+
+// plaidReauthErrorCode reports whether code means the item itself needs
+// the user to reconnect - the same condition handlePlaidItemWebhook's
+// ERROR/PENDING_EXPIRATION cases and runPlaidConsentExpiryScan already flag
+// NeedsReauth for, just discovered from an API response instead of a
+// webhook or a consent-expiry scan.
+func plaidReauthErrorCode(code string) bool {
+	switch code {
+	case "ITEM_LOGIN_REQUIRED", "INVALID_ACCESS_TOKEN", "ITEM_NOT_FOUND":
+		return true
+	default:
+		return false
+	}
+}
+
+// plaidRetryMinBackoff/plaidRetryMaxBackoff bound withPlaidRateLimitRetry's
+// exponential backoff, the same doubling shape aidriver.retryBackoff uses
+// for AI backend retries.
+const (
+	plaidRetryMinBackoff     = 250 * time.Millisecond
+	plaidRetryMaxBackoff     = 2 * time.Second
+	plaidMaxRateLimitRetries = 3
+)
+
+// plaidRetryBackoff doubles from plaidRetryMinBackoff on each successive
+// attempt, capped at plaidRetryMaxBackoff.
+func plaidRetryBackoff(attempt int) time.Duration {
+	delay := plaidRetryMinBackoff
+	for i := 1; i < attempt && delay < plaidRetryMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > plaidRetryMaxBackoff {
+		delay = plaidRetryMaxBackoff
+	}
+	return delay
+}
+
+// withPlaidRateLimitRetry calls fn, retrying up to plaidMaxRateLimitRetries
+// additional times - with exponential backoff - only when fn's error is
+// Plaid's own RATE_LIMIT_EXCEEDED; any other error (or success) returns
+// immediately. RATE_LIMIT_EXCEEDED is the one Plaid error code worth
+// retrying automatically: everything else (ITEM_LOGIN_REQUIRED,
+// INVALID_ACCESS_TOKEN, ...) means the item itself needs attention, and
+// retrying wouldn't change that.
+// plaidErrorJSON writes status with message, plus plaid_error_code when err
+// carries one - the user-facing counterpart to plaidErrorCode, so a
+// frontend can distinguish "reconnect this bank" (ITEM_LOGIN_REQUIRED,
+// INVALID_ACCESS_TOKEN) from "try again shortly" (RATE_LIMIT_EXCEEDED)
+// instead of pattern-matching the error string.
+func plaidErrorJSON(c *gin.Context, status int, message string, err error) {
+	body := gin.H{"error": message}
+	if code := plaidErrorCode(err); code != "" {
+		body["plaid_error_code"] = code
+	}
+	c.JSON(status, body)
+}
+
+func withPlaidRateLimitRetry(fn func() error) error {
+	var lastErr error
+	for attempt := 0; attempt <= plaidMaxRateLimitRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(plaidRetryBackoff(attempt))
+		}
+		lastErr = fn()
+		if lastErr == nil || plaidErrorCode(lastErr) != "RATE_LIMIT_EXCEEDED" {
+			return lastErr
+		}
+	}
+	return lastErr
+}
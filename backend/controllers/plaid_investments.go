@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/plaid/plaid-go/v29/plaid"
+	"github.com/shopspring/decimal"
+)
+
+// investmentsSyncWindow is how far back SyncPlaidInvestments asks
+// /investments/transactions/get to look. Unlike /transactions/sync, Plaid's
+// investments endpoints aren't cursor-based, so each call re-fetches the
+// full holdings snapshot and this trailing window of activity rather than
+// resuming from a stored cursor.
+const investmentsSyncWindow = 90 * 24 * time.Hour
+
+// SyncPlaidInvestments pulls this connection's /investments/holdings/get
+// snapshot and /investments/transactions/get activity: it upserts Holding/
+// Security rows for the snapshot, and imports buy/sell/dividend activity
+// into the Transaction table (Kind investment_buy/investment_sell/
+// investment_dividend) the same way plaidSyncConnection imports everyday
+// transactions.
+func SyncPlaidInvestments(c *gin.Context) {
+	if activePlaidHandler == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Plaid client not initialized"})
+		return
+	}
+	activePlaidHandler.SyncPlaidInvestments(c)
+}
+
+// SyncPlaidInvestments is the PlaidHandler method the package-level
+// SyncPlaidInvestments wrapper calls into.
+func (h *PlaidHandler) SyncPlaidInvestments(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	connectionID := c.Param("id")
+	var connection models.BankConnection
+	if err := h.db.Where("id = ? AND user_id = ?", connectionID, userID).First(&connection).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "connection not found"})
+		return
+	}
+
+	accessToken, ok := connection.Metadata["access_token"].(string)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "access token not found"})
+		return
+	}
+
+	var bankAccounts []models.BankAccount
+	h.db.Where("bank_connection_id = ?", connection.ID).Find(&bankAccounts)
+	accountMap := make(map[string]uint)
+	for _, ba := range bankAccounts {
+		if ba.InternalAccountID != nil {
+			accountMap[ba.AccountID] = *ba.InternalAccountID
+		}
+	}
+
+	holdingsRequest := plaid.NewInvestmentsHoldingsGetRequest(accessToken)
+	holdingsResp, err := h.client.InvestmentsHoldingsGet(*holdingsRequest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch holdings: " + err.Error()})
+		return
+	}
+
+	securityMap := make(map[string]uint) // Plaid security_id -> models.Security.ID
+	for _, sec := range holdingsResp.GetSecurities() {
+		securityMap[sec.GetSecurityId()] = upsertSecurity(sec).ID
+	}
+
+	holdingsUpserted := 0
+	for _, holding := range holdingsResp.GetHoldings() {
+		accountID, ok := accountMap[holding.GetAccountId()]
+		if !ok {
+			continue
+		}
+		securityID, ok := securityMap[holding.GetSecurityId()]
+		if !ok {
+			continue
+		}
+		upsertHolding(userID, accountID, securityID, holding)
+		holdingsUpserted++
+	}
+
+	end := time.Now()
+	start := end.Add(-investmentsSyncWindow)
+	txRequest := plaid.NewInvestmentsTransactionsGetRequest(accessToken, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	txResp, err := h.client.InvestmentsTransactionsGet(*txRequest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch investment transactions: " + err.Error()})
+		return
+	}
+
+	imported := 0
+	for _, txn := range txResp.GetInvestmentTransactions() {
+		accountID, ok := accountMap[txn.GetAccountId()]
+		if !ok {
+			continue
+		}
+		if applyPlaidInvestmentTransaction(userID, accountID, txn) {
+			imported++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":            true,
+		"holdings_upserted":  holdingsUpserted,
+		"transactions_added": imported,
+	})
+}
+
+// upsertSecurity finds or creates the models.Security matching sec's
+// ticker, the catalog key Holding.SecurityID references.
+func upsertSecurity(sec plaid.Security) models.Security {
+	var security models.Security
+	ticker := sec.GetTickerSymbol()
+	db.DB.Where("ticker = ?", ticker).FirstOrCreate(&security, models.Security{
+		Ticker:      ticker,
+		Name:        sec.GetName(),
+		Type:        string(sec.GetType()),
+		ISOCurrency: sec.GetIsoCurrencyCode(),
+	})
+	return security
+}
+
+// upsertHolding matches an existing Holding by (userID, accountID,
+// securityID) and overwrites its quantity/value, or creates one if this is
+// the first sync to see that position.
+func upsertHolding(userID, accountID, securityID uint, h plaid.Holding) {
+	var holding models.Holding
+	found := db.DB.Where("user_id = ? AND account_id = ? AND security_id = ?", userID, accountID, securityID).First(&holding).Error == nil
+
+	holding.UserID = userID
+	holding.AccountID = accountID
+	holding.SecurityID = securityID
+	holding.Quantity = h.GetQuantity()
+	holding.CostBasisCents = int64(h.GetCostBasis() * 100)
+	holding.InstitutionValueCents = int64(h.GetInstitutionValue() * 100)
+	holding.Currency = h.GetIsoCurrencyCode()
+	holding.PlaidAccountID = h.GetAccountId()
+	holding.PlaidSecurityID = h.GetSecurityId()
+
+	if found {
+		db.DB.Save(&holding)
+	} else {
+		db.DB.Create(&holding)
+	}
+}
+
+// applyPlaidInvestmentTransaction creates the internal Transaction for one
+// of /investments/transactions/get's entries, the investment-side
+// counterpart of applyPlaidTransactionAdd. Returns false (and creates
+// nothing) if it was already imported by an earlier sync.
+func applyPlaidInvestmentTransaction(userID, accountID uint, txn plaid.InvestmentTransaction) bool {
+	txnID := txn.GetInvestmentTransactionId()
+	var existing models.Transaction
+	if err := db.DB.Where("bank_transaction_id = ?", txnID).First(&existing).Error; err == nil {
+		return false
+	}
+
+	kind := models.TransactionKindInvestmentBuy
+	switch txn.GetType() {
+	case "sell":
+		kind = models.TransactionKindInvestmentSell
+	case "cash":
+		if txn.GetSubtype() == "dividend" {
+			kind = models.TransactionKindInvestmentDividend
+		}
+	}
+
+	amountCents := int64(-txn.GetAmount() * 100) // Plaid: positive removes cash (buy), negative adds cash (sell/dividend)
+	txnDate, _ := time.Parse("2006-01-02", txn.GetDate())
+
+	transaction := models.Transaction{
+		UserID:            userID,
+		AccountID:         accountID,
+		Amount:            decimal.NewFromInt(amountCents).Div(decimal.NewFromInt(100)),
+		Description:       txn.GetName(),
+		TxnDate:           txnDate,
+		Kind:              kind,
+		BankTransactionID: &txnID,
+	}
+	return db.DB.Create(&transaction).Error == nil
+}
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+
+	"go.uber.org/zap"
+)
+
+// plaidReauthWindow is how far ahead of ConsentValidUntil
+// runPlaidConsentExpiryScan starts flagging a connection NeedsReauth, so the
+// UI has a week's notice before a sync actually starts failing.
+const plaidReauthWindow = 7 * 24 * time.Hour
+
+// PlaidSyncInterval is how often StartPlaidSyncScheduler re-syncs a Plaid
+// connection, configurable via PLAID_SYNC_INTERVAL_MINUTES (default 15).
+func PlaidSyncInterval() time.Duration {
+	return plaidEnvMinutes("PLAID_SYNC_INTERVAL_MINUTES", 15)
+}
+
+// PlaidConsentScanInterval is how often StartPlaidSyncScheduler scans for
+// about-to-lapse Plaid consents, configurable via
+// PLAID_CONSENT_SCAN_INTERVAL_MINUTES (default 60).
+func PlaidConsentScanInterval() time.Duration {
+	return plaidEnvMinutes("PLAID_CONSENT_SCAN_INTERVAL_MINUTES", 60)
+}
+
+func plaidEnvMinutes(envVar string, fallback int) time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv(envVar))
+	if err != nil || minutes <= 0 {
+		minutes = fallback
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// StartPlaidSyncScheduler launches two background goroutines: one that
+// re-syncs Plaid connections whose last sync is older than syncInterval
+// through the same plaidSyncConnection path SyncPlaidTransactions and
+// PlaidWebhook use, and one that scans for connections whose consent is
+// about to lapse and flags them NeedsReauth - the poll-based backstop for
+// banks that don't send a PENDING_EXPIRATION webhook. Both passes no-op
+// while activePlaidHandler is nil (Plaid isn't configured).
+func StartPlaidSyncScheduler(syncInterval, consentScanInterval time.Duration) {
+	go runPlaidSyncLoop(syncInterval)
+	go runPlaidConsentScanLoop(consentScanInterval)
+}
+
+func runPlaidSyncLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		time.Sleep(plaidSyncJitter())
+		syncDuePlaidConnections(interval)
+	}
+}
+
+func runPlaidConsentScanLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		runPlaidConsentExpiryScan()
+	}
+}
+
+// plaidSyncJitter spreads a sync pass out over PLAID_SYNC_JITTER_SECONDS (0
+// by default, i.e. no jitter), so several instances of this server don't
+// all hit Plaid in the same instant on a shared tick boundary.
+func plaidSyncJitter() time.Duration {
+	seconds, _ := strconv.Atoi(os.Getenv("PLAID_SYNC_JITTER_SECONDS"))
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Intn(seconds)) * time.Second
+}
+
+// plaidSyncConcurrency bounds how many connections syncDuePlaidConnections
+// syncs at once, configurable via PLAID_SYNC_CONCURRENCY (default 4).
+func plaidSyncConcurrency() int {
+	n, err := strconv.Atoi(os.Getenv("PLAID_SYNC_CONCURRENCY"))
+	if err != nil || n <= 0 {
+		return 4
+	}
+	return n
+}
+
+// syncDuePlaidConnections re-syncs every connected Plaid BankConnection
+// whose last sync is older than interval (or has never synced), up to
+// plaidSyncConcurrency at a time.
+func syncDuePlaidConnections(interval time.Duration) {
+	if activePlaidHandler == nil {
+		return
+	}
+
+	var due []models.BankConnection
+	cutoff := time.Now().Add(-interval)
+	err := db.DB.Where("provider = ? AND status = ? AND (last_sync_at IS NULL OR last_sync_at <= ?)", "plaid", "connected", cutoff).
+		Find(&due).Error
+	if err != nil {
+		utils.Logger.Warn("plaid sync scheduler: failed to load due connections", zap.Error(err))
+		return
+	}
+
+	sem := make(chan struct{}, plaidSyncConcurrency())
+	var wg sync.WaitGroup
+	for i := range due {
+		connection := due[i]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if _, _, _, _, err := activePlaidHandler.plaidSyncConnection(&connection); err != nil {
+				utils.Logger.Warn("plaid sync scheduler: sync failed", zap.Uint("connection_id", connection.ID), zap.Error(err))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// runPlaidConsentExpiryScan flags every connected Plaid BankConnection
+// whose ConsentValidUntil falls within plaidReauthWindow as NeedsReauth, and
+// records a PlaidReauthAlert the first time it does so for a given
+// connection - repeated scans before the user reconnects don't pile up
+// duplicate alerts, since the query only matches rows still NeedsReauth =
+// false.
+func runPlaidConsentExpiryScan() {
+	var expiring []models.BankConnection
+	err := db.DB.Where("provider = ? AND status = ? AND needs_reauth = ? AND consent_valid_until <= ?",
+		"plaid", "connected", false, time.Now().Add(plaidReauthWindow)).Find(&expiring).Error
+	if err != nil {
+		utils.Logger.Warn("plaid sync scheduler: failed to scan for expiring consents", zap.Error(err))
+		return
+	}
+
+	for _, connection := range expiring {
+		if err := db.DB.Model(&connection).Update("needs_reauth", true).Error; err != nil {
+			utils.Logger.Warn("plaid sync scheduler: failed to flag connection needs_reauth", zap.Uint("connection_id", connection.ID), zap.Error(err))
+			continue
+		}
+
+		alert := models.PlaidReauthAlert{
+			UserID:           connection.UserID,
+			BankConnectionID: connection.ID,
+			Reason:           "consent_expiring",
+		}
+		if err := db.DB.Create(&alert).Error; err != nil {
+			utils.Logger.Warn("plaid sync scheduler: failed to record reauth alert", zap.Uint("connection_id", connection.ID), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/plaid/plaid-go/v29/plaid"
+	"go.uber.org/zap"
+)
+
+// plaidWebhookMaxAge rejects a verification JWT whose iat is older than
+// this, the same 5-minute replay window Plaid's own docs recommend.
+const plaidWebhookMaxAge = 5 * time.Minute
+
+// plaidWebhookClaims is the Plaid-Verification header's JWT payload: a
+// hash of the raw request body, checked against the body PlaidWebhook
+// actually received, plus the standard iat Plaid signs every webhook with.
+type plaidWebhookClaims struct {
+	jwt.RegisteredClaims
+	RequestBodySHA256 string `json:"request_body_sha256"`
+}
+
+// plaidWebhookPayload is the JSON body of every Plaid webhook - only the
+// fields PlaidWebhook dispatches on or needs for the ITEM:ERROR case.
+type plaidWebhookPayload struct {
+	WebhookType string `json:"webhook_type"`
+	WebhookCode string `json:"webhook_code"`
+	ItemID      string `json:"item_id"`
+	Error       *struct {
+		ErrorCode    string `json:"error_code"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"error,omitempty"`
+}
+
+// PlaidWebhook receives every webhook Plaid sends for items this server
+// created (see CreateLinkToken's SetWebhook call), verifies the
+// Plaid-Verification JWT against Plaid's own /webhook_verification_key/get
+// endpoint, and dispatches TRANSACTIONS/ITEM webhook codes. It always
+// returns 200 once the payload has been read and matched to a connection -
+// Plaid retries on non-2xx, and an item we don't recognize or a code we
+// don't act on isn't something a retry would fix.
+func PlaidWebhook(c *gin.Context) {
+	if activePlaidHandler == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Plaid client not initialized"})
+		return
+	}
+	activePlaidHandler.PlaidWebhook(c)
+}
+
+// PlaidWebhook is the PlaidHandler method the package-level PlaidWebhook
+// wrapper calls into.
+func (h *PlaidHandler) PlaidWebhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+		return
+	}
+
+	verification := c.GetHeader("Plaid-Verification")
+	if verification == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing Plaid-Verification header"})
+		return
+	}
+
+	var claims plaidWebhookClaims
+	if _, err := jwt.ParseWithClaims(verification, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
+			return nil, fmt.Errorf("unexpected plaid webhook signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return h.plaidWebhookVerificationKey(kid)
+	}); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid webhook signature"})
+		return
+	}
+
+	if claims.IssuedAt == nil || time.Since(claims.IssuedAt.Time) > plaidWebhookMaxAge {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "webhook verification token expired"})
+		return
+	}
+
+	bodyHash := sha256.Sum256(body)
+	if hex.EncodeToString(bodyHash[:]) != claims.RequestBodySHA256 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "webhook body hash mismatch"})
+		return
+	}
+
+	var payload plaidWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook payload"})
+		return
+	}
+
+	var connection models.BankConnection
+	if err := h.db.Where("consent_id = ? AND bank_endpoint = ?", payload.ItemID, plaidBankEndpoint).First(&connection).Error; err != nil {
+		c.JSON(http.StatusOK, gin.H{"acknowledged": true})
+		return
+	}
+
+	switch payload.WebhookType {
+	case "TRANSACTIONS":
+		h.handlePlaidTransactionsWebhook(payload, &connection)
+	case "ITEM":
+		h.handlePlaidItemWebhook(payload, &connection)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"acknowledged": true})
+}
+
+// handlePlaidTransactionsWebhook re-runs plaidSyncConnection for any
+// TRANSACTIONS code that means "new data is available to pull" -
+// SYNC_UPDATES_AVAILABLE is what /transactions/sync items send;
+// DEFAULT_UPDATE/INITIAL_UPDATE/HISTORICAL_UPDATE are the equivalent codes
+// for items still on the older /transactions/get flow.
+func (h *PlaidHandler) handlePlaidTransactionsWebhook(payload plaidWebhookPayload, connection *models.BankConnection) {
+	switch payload.WebhookCode {
+	case "SYNC_UPDATES_AVAILABLE", "DEFAULT_UPDATE", "INITIAL_UPDATE", "HISTORICAL_UPDATE":
+		if _, _, _, _, err := h.plaidSyncConnection(connection); err != nil {
+			utils.Logger.Warn("plaid webhook: transactions sync failed", zap.Uint("connection_id", connection.ID), zap.Error(err))
+		}
+	}
+}
+
+// handlePlaidItemWebhook records the item-level problems Plaid reports so
+// the next sync attempt (or the UI) can see why a connection stopped
+// working, instead of silently failing the next scheduled sync. ERROR also
+// persists the Plaid error code/message into Metadata["last_error"], and
+// PENDING_EXPIRATION sets NeedsReauth so the UI can prompt the user to
+// re-link before the item actually fails.
+func (h *PlaidHandler) handlePlaidItemWebhook(payload plaidWebhookPayload, connection *models.BankConnection) {
+	updates := map[string]interface{}{}
+	switch payload.WebhookCode {
+	case "ERROR":
+		updates["status"] = "error"
+		if payload.Error != nil {
+			utils.Logger.Warn("plaid webhook: item error", zap.Uint("connection_id", connection.ID), zap.String("error_code", payload.Error.ErrorCode))
+			if connection.Metadata == nil {
+				connection.Metadata = models.JSONB{}
+			}
+			connection.Metadata["last_error"] = map[string]string{
+				"error_code":    payload.Error.ErrorCode,
+				"error_message": payload.Error.ErrorMessage,
+			}
+			updates["metadata"] = connection.Metadata
+		}
+	case "PENDING_EXPIRATION":
+		updates["status"] = "expiring"
+		updates["needs_reauth"] = true
+	case "USER_PERMISSION_REVOKED":
+		updates["status"] = "revoked"
+	default:
+		return
+	}
+
+	alreadyFlagged := connection.NeedsReauth
+	if err := h.db.Model(connection).Updates(updates).Error; err != nil {
+		utils.Logger.Warn("plaid webhook: failed to update connection status", zap.Uint("connection_id", connection.ID), zap.Error(err))
+		return
+	}
+
+	// Record a PlaidReauthAlert the first time this connection needs
+	// reauthorization, the same row runPlaidConsentExpiryScan creates when
+	// it catches an about-to-lapse consent no webhook announced.
+	if payload.WebhookCode == "PENDING_EXPIRATION" && !alreadyFlagged {
+		alert := models.PlaidReauthAlert{
+			UserID:           connection.UserID,
+			BankConnectionID: connection.ID,
+			Reason:           "pending_expiration",
+		}
+		if err := h.db.Create(&alert).Error; err != nil {
+			utils.Logger.Warn("plaid webhook: failed to record reauth alert", zap.Uint("connection_id", connection.ID), zap.Error(err))
+		}
+	}
+}
+
+// plaidWebhookKeyCacheTTL mirrors fetchJWKSPublicKey's OAuth ID token cache:
+// Plaid's signing keys rotate infrequently, so a fetched key is reused for a
+// while instead of hitting /webhook_verification_key/get on every webhook.
+const plaidWebhookKeyCacheTTL = 1 * time.Hour
+
+var (
+	plaidWebhookKeyMu    sync.Mutex
+	plaidWebhookKeyCache = map[string]struct {
+		key    *ecdsa.PublicKey
+		expiry time.Time
+	}{}
+)
+
+// plaidWebhookVerificationKey returns the EC public key for kid, fetching
+// and caching it from Plaid's /webhook_verification_key/get for
+// plaidWebhookKeyCacheTTL.
+func (h *PlaidHandler) plaidWebhookVerificationKey(kid string) (*ecdsa.PublicKey, error) {
+	plaidWebhookKeyMu.Lock()
+	cached, ok := plaidWebhookKeyCache[kid]
+	plaidWebhookKeyMu.Unlock()
+	if ok && time.Now().Before(cached.expiry) {
+		return cached.key, nil
+	}
+
+	request := plaid.NewWebhookVerificationKeyGetRequest(kid)
+	resp, err := h.client.WebhookVerificationKeyGet(*request)
+	if err != nil {
+		return nil, fmt.Errorf("plaid: fetch webhook verification key %q: %w", kid, err)
+	}
+
+	jwk := resp.GetKey()
+	if expiredAt, ok := jwk.GetExpiredAtOk(); ok && expiredAt != nil {
+		return nil, fmt.Errorf("plaid: webhook verification key %q has expired", kid)
+	}
+
+	key, err := ecdsaPublicKeyFromJWK(jwk.X, jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	plaidWebhookKeyMu.Lock()
+	plaidWebhookKeyCache[kid] = struct {
+		key    *ecdsa.PublicKey
+		expiry time.Time
+	}{key: key, expiry: time.Now().Add(plaidWebhookKeyCacheTTL)}
+	plaidWebhookKeyMu.Unlock()
+
+	return key, nil
+}
+
+// ecdsaPublicKeyFromJWK builds a P-256 *ecdsa.PublicKey from a JWK's
+// base64url-encoded x/y coordinates, the EC equivalent of
+// rsaPublicKeyFromJWK in oauth_controller.go.
+func ecdsaPublicKeyFromJWK(xB64, yB64 string) (*ecdsa.PublicKey, error) {
+	xBytes, err := base64.RawURLEncoding.DecodeString(xB64)
+	if err != nil {
+		return nil, fmt.Errorf("plaid: invalid jwk x coordinate: %w", err)
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(yB64)
+	if err != nil {
+		return nil, fmt.Errorf("plaid: invalid jwk y coordinate: %w", err)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(xBytes),
+		Y:     new(big.Int).SetBytes(yBytes),
+	}, nil
+}
+
+// plaidWebhookBaseURL returns the externally-reachable base URL Plaid calls
+// webhooks back to, the same way callbackBaseURL does for the PSD2 SCA
+// redirect in bank_sync.go.
+func plaidWebhookBaseURL() string {
+	if base := os.Getenv("PLAID_WEBHOOK_BASE_URL"); base != "" {
+		return base
+	}
+	return "http://localhost:8080"
+}
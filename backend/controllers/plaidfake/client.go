@@ -0,0 +1,123 @@
+// Package plaidfake provides a controllers.PlaidClient implementation that
+// never makes a network call, so tests can script Plaid responses (multi-page
+// /transactions/sync included) instead of hitting the real API or leaving the
+// package-level client nil.
+package plaidfake
+
+import (
+	"fmt"
+
+	"github.com/plaid/plaid-go/v29/plaid"
+)
+
+// apiError is a *Func field's stand-in for the error plaid-go's real SDK
+// client returns on a non-2xx response: controllers.plaidErrorCode reads it
+// through the Body() []byte method every openapi-generator error type in
+// the real SDK implements, so tests can script ITEM_LOGIN_REQUIRED/
+// RATE_LIMIT_EXCEEDED/INVALID_ACCESS_TOKEN the same way production code
+// will see them, without a real HTTP round trip.
+type apiError struct {
+	code string
+	body []byte
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("plaid: %s", e.code)
+}
+
+func (e *apiError) Body() []byte {
+	return e.body
+}
+
+// APIError builds the error a *Func field should return to simulate Plaid
+// responding with error_code (e.g. "ITEM_LOGIN_REQUIRED",
+// "RATE_LIMIT_EXCEEDED", "INVALID_ACCESS_TOKEN") and errorMessage.
+func APIError(code, errorMessage string) error {
+	body := fmt.Sprintf(`{"error_type":"%s","error_code":"%s","error_message":"%s"}`, errorTypeFor(code), code, errorMessage)
+	return &apiError{code: code, body: []byte(body)}
+}
+
+// errorTypeFor fills in the error_type Plaid's real error payloads always
+// carry alongside error_code, for the handful of codes this repo's tests
+// script - good enough for plaidErrorCode, which only reads error_code.
+func errorTypeFor(code string) string {
+	switch code {
+	case "RATE_LIMIT_EXCEEDED":
+		return "RATE_LIMIT_EXCEEDED"
+	case "ITEM_LOGIN_REQUIRED", "INVALID_ACCESS_TOKEN", "ITEM_NOT_FOUND":
+		return "ITEM_ERROR"
+	default:
+		return "API_ERROR"
+	}
+}
+
+// Client implements controllers.PlaidClient. Each method delegates to the
+// matching *Func field if set, or otherwise returns a zero-value response and
+// a nil error - enough for tests that only care about one or two of the
+// interface's methods to leave the rest unset.
+type Client struct {
+	LinkTokenCreateFunc            func(plaid.LinkTokenCreateRequest) (plaid.LinkTokenCreateResponse, error)
+	ItemPublicTokenExchangeFunc    func(plaid.ItemPublicTokenExchangeRequest) (plaid.ItemPublicTokenExchangeResponse, error)
+	AccountsGetFunc                func(plaid.AccountsGetRequest) (plaid.AccountsGetResponse, error)
+	TransactionsSyncFunc           func(plaid.TransactionsSyncRequest) (plaid.TransactionsSyncResponse, error)
+	ItemGetFunc                    func(plaid.ItemGetRequest) (plaid.ItemGetResponse, error)
+	WebhookVerificationKeyGetFunc  func(plaid.WebhookVerificationKeyGetRequest) (plaid.WebhookVerificationKeyGetResponse, error)
+	InvestmentsHoldingsGetFunc     func(plaid.InvestmentsHoldingsGetRequest) (plaid.InvestmentsHoldingsGetResponse, error)
+	InvestmentsTransactionsGetFunc func(plaid.InvestmentsTransactionsGetRequest) (plaid.InvestmentsTransactionsGetResponse, error)
+}
+
+func (c *Client) LinkTokenCreate(req plaid.LinkTokenCreateRequest) (plaid.LinkTokenCreateResponse, error) {
+	if c.LinkTokenCreateFunc != nil {
+		return c.LinkTokenCreateFunc(req)
+	}
+	return plaid.LinkTokenCreateResponse{}, nil
+}
+
+func (c *Client) ItemPublicTokenExchange(req plaid.ItemPublicTokenExchangeRequest) (plaid.ItemPublicTokenExchangeResponse, error) {
+	if c.ItemPublicTokenExchangeFunc != nil {
+		return c.ItemPublicTokenExchangeFunc(req)
+	}
+	return plaid.ItemPublicTokenExchangeResponse{}, nil
+}
+
+func (c *Client) AccountsGet(req plaid.AccountsGetRequest) (plaid.AccountsGetResponse, error) {
+	if c.AccountsGetFunc != nil {
+		return c.AccountsGetFunc(req)
+	}
+	return plaid.AccountsGetResponse{}, nil
+}
+
+func (c *Client) TransactionsSync(req plaid.TransactionsSyncRequest) (plaid.TransactionsSyncResponse, error) {
+	if c.TransactionsSyncFunc != nil {
+		return c.TransactionsSyncFunc(req)
+	}
+	return plaid.TransactionsSyncResponse{}, nil
+}
+
+func (c *Client) ItemGet(req plaid.ItemGetRequest) (plaid.ItemGetResponse, error) {
+	if c.ItemGetFunc != nil {
+		return c.ItemGetFunc(req)
+	}
+	return plaid.ItemGetResponse{}, nil
+}
+
+func (c *Client) WebhookVerificationKeyGet(req plaid.WebhookVerificationKeyGetRequest) (plaid.WebhookVerificationKeyGetResponse, error) {
+	if c.WebhookVerificationKeyGetFunc != nil {
+		return c.WebhookVerificationKeyGetFunc(req)
+	}
+	return plaid.WebhookVerificationKeyGetResponse{}, nil
+}
+
+func (c *Client) InvestmentsHoldingsGet(req plaid.InvestmentsHoldingsGetRequest) (plaid.InvestmentsHoldingsGetResponse, error) {
+	if c.InvestmentsHoldingsGetFunc != nil {
+		return c.InvestmentsHoldingsGetFunc(req)
+	}
+	return plaid.InvestmentsHoldingsGetResponse{}, nil
+}
+
+func (c *Client) InvestmentsTransactionsGet(req plaid.InvestmentsTransactionsGetRequest) (plaid.InvestmentsTransactionsGetResponse, error) {
+	if c.InvestmentsTransactionsGetFunc != nil {
+		return c.InvestmentsTransactionsGetFunc(req)
+	}
+	return plaid.InvestmentsTransactionsGetResponse{}, nil
+}
@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/psd2"
+	"Personal-Finance-Tracker-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// requestAuditHeaders builds a psd2.AuditHeaders from c: the caller's own
+// X-Request-ID if it sent one (so a retried request correlates with the
+// first attempt), otherwise a freshly generated one, plus the caller's IP
+// as PSU-IP-Address.
+func requestAuditHeaders(c *gin.Context) psd2.AuditHeaders {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = newPSD2RequestID()
+	}
+	return psd2.AuditHeaders{RequestID: requestID, PSUIPAddress: c.ClientIP()}
+}
+
+// newPSD2RequestID returns a random X-Request-ID, the same way
+// controllers/auth_helpers.go's newJTI mints a random session token ID.
+func newPSD2RequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// logPSD2Audit writes a BankSyncLog row recording one PSD2 call's outcome
+// and its audit headers, the way syncConnection already does for a
+// transactions sync - just with syncType/status supplied by the caller
+// instead of always being "transactions".
+func logPSD2Audit(connectionID uint, syncType string, audit psd2.AuditHeaders, redirectURI string, err error) {
+	status := "success"
+	errMessage := ""
+	if err != nil {
+		status = "failed"
+		errMessage = err.Error()
+	}
+	entry := models.BankSyncLog{
+		BankConnectionID: connectionID,
+		SyncType:         syncType,
+		Status:           status,
+		ErrorMessage:     errMessage,
+		APICallsUsed:     1,
+		RequestID:        audit.RequestID,
+		PSUIPAddress:     audit.PSUIPAddress,
+		TPPRedirectURI:   redirectURI,
+	}
+	if createErr := db.DB.Create(&entry).Error; createErr != nil {
+		utils.Logger.Warn("psd2: failed to write audit log")
+	}
+}
+
+type createPSD2ConsentRequest struct {
+	BankName                 string `json:"bank_name" binding:"required"`
+	FrequencyPerDay          int    `json:"frequency_per_day"`
+	CombinedServiceIndicator bool   `json:"combined_service_indicator"`
+	RecurringIndicator       bool   `json:"recurring_indicator"`
+}
+
+// CreatePSD2Consent initiates a Berlin Group consent for one of
+// services/psd2.Providers, with every consent-request field the spec
+// defines exposed on the request body - unlike CreateBankConnection, which
+// hardcodes FrequencyPerDay=4 and the recurring/combined-service flags.
+// Mirrors CreateBankConnection's pending-until-callback lifecycle.
+func CreatePSD2Consent(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var req createPSD2ConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	provider, ok := psd2.Providers[req.BankName]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported bank_name"})
+		return
+	}
+
+	frequencyPerDay := req.FrequencyPerDay
+	if frequencyPerDay <= 0 {
+		frequencyPerDay = 4
+	}
+
+	connection := models.BankConnection{
+		UserID:          userID,
+		BankName:        provider.BankName,
+		BankEndpoint:    provider.Endpoint,
+		ConsentStatus:   "initiating",
+		FrequencyPerDay: frequencyPerDay,
+		Status:          "pending",
+	}
+	if err := db.DB.Create(&connection).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create bank connection"})
+		return
+	}
+
+	validUntil := time.Now().AddDate(0, 0, consentValidityDays)
+	redirectURI := fmt.Sprintf("%s/api/banks/connections/%d/callback", callbackBaseURL(), connection.ID)
+	audit := requestAuditHeaders(c)
+
+	consent, err := psd2.NewClient(provider.Endpoint).InitiateConsentWithOptions(redirectURI, validUntil, psd2.ConsentOptions{
+		FrequencyPerDay:          frequencyPerDay,
+		CombinedServiceIndicator: req.CombinedServiceIndicator,
+		RecurringIndicator:       req.RecurringIndicator,
+	}, audit)
+	logPSD2Audit(connection.ID, "consent", audit, redirectURI, err)
+	if err != nil {
+		utils.Logger.Warn("psd2: failed to initiate consent")
+		db.DB.Model(&connection).Updates(map[string]interface{}{"status": "failed", "consent_status": "failed"})
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to initiate consent with bank"})
+		return
+	}
+
+	connection.ConsentID = consent.ConsentID
+	connection.ConsentStatus = consent.Status
+	connection.ConsentValidUntil = validUntil
+	if metadata, ok := encryptedBankSecrets(provider.BankName); ok {
+		connection.Metadata = metadata
+	}
+	if err := db.DB.Save(&connection).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store consent"})
+		return
+	}
+
+	recordBankAuditEvent(c, userID, &connection.ID, models.BankAuditActionConnected, nil,
+		models.JSONB{"bank_name": connection.BankName, "consent_status": connection.ConsentStatus})
+
+	c.JSON(http.StatusOK, gin.H{
+		"connection_id": connection.ID,
+		"redirect_url":  consent.RedirectURL,
+		"consent_id":    consent.ConsentID,
+	})
+}
+
+// GetPSD2ConsentStatus polls the live consent status for a connection owned
+// by the caller and persists it, so a frontend can show "still pending SCA"
+// vs. "valid" without waiting for the next BankConnectionCallback.
+func GetPSD2ConsentStatus(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	connectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid connection id"})
+		return
+	}
+
+	var connection models.BankConnection
+	if err := db.DB.Where("id = ? AND user_id = ?", connectionID, userID).First(&connection).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bank connection not found"})
+		return
+	}
+
+	audit := requestAuditHeaders(c)
+	status, err := psd2.NewClient(connection.BankEndpoint).ConsentStatusWithAudit(connection.ConsentID, audit)
+	logPSD2Audit(connection.ID, "consent_status", audit, "", err)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to check consent status"})
+		return
+	}
+
+	db.DB.Model(&connection).Update("consent_status", status)
+	c.JSON(http.StatusOK, gin.H{"consent_id": connection.ConsentID, "consent_status": status})
+}
+
+// SyncPSD2Account pulls transactions for one BankAccount - narrower than
+// SyncBankConnection, which syncs every account under a connection at once
+// - calling GET /v1/accounts/{account-id}/transactions directly so a
+// frontend can refresh a single account without waiting on the others.
+func SyncPSD2Account(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	accountID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	var bankAccount models.BankAccount
+	if err := db.DB.Joins("JOIN bank_connections ON bank_connections.id = bank_accounts.bank_connection_id").
+		Where("bank_accounts.id = ? AND bank_connections.user_id = ?", accountID, userID).
+		First(&bankAccount).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bank account not found"})
+		return
+	}
+
+	var connection models.BankConnection
+	if err := db.DB.First(&connection, bankAccount.BankConnectionID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bank connection not found"})
+		return
+	}
+	if connection.ConsentStatus != "valid" {
+		c.JSON(http.StatusConflict, gin.H{"error": "connection does not have a valid consent"})
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -30)
+	if bankAccount.LastTransactionSync != nil {
+		since = *bankAccount.LastTransactionSync
+	}
+
+	audit := requestAuditHeaders(c)
+	transactions, err := psd2.NewClient(connection.BankEndpoint).TransactionsWithAudit(connection.ConsentID, bankAccount.AccountID, since, audit)
+	logPSD2Audit(connection.ID, "account_transactions", audit, "", err)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "sync failed: " + err.Error()})
+		return
+	}
+
+	added, updated := ingestBankTransactions(userID, bankAccount, transactions)
+
+	now := time.Now()
+	accountUpdates := map[string]interface{}{"last_transaction_sync": &now}
+	if len(transactions) > 0 {
+		accountUpdates["last_sync_cursor"] = transactions[len(transactions)-1].TransactionID
+	}
+	db.DB.Model(&bankAccount).Updates(accountUpdates)
+
+	recordBankAuditEvent(c, userID, &connection.ID, models.BankAuditActionSynced, nil,
+		models.JSONB{"transactions_found": len(transactions), "transactions_added": added})
+
+	c.JSON(http.StatusOK, gin.H{
+		"transactions_found":   len(transactions),
+		"transactions_added":   added,
+		"transactions_updated": updated,
+	})
+}
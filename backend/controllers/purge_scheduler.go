@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+)
+
+// StartPurgeScheduler launches a background goroutine that periodically
+// hard-deletes soft-deleted admin resources (users, accounts, categories,
+// budgets, transactions) whose grace period (PurgeAfter) has elapsed.
+func StartPurgeScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			purgeExpiredSoftDeletes()
+		}
+	}()
+}
+
+// purgeExpiredSoftDeletes removes rows past their grace period, children
+// first so nothing is left dangling if the process restarts mid-purge.
+func purgeExpiredSoftDeletes() {
+	now := time.Now()
+	due := "deleted_at IS NOT NULL AND purge_after IS NOT NULL AND purge_after <= ?"
+
+	if err := db.DB.Unscoped().Where(due, now).Delete(&models.Transaction{}).Error; err != nil {
+		utils.Logger.Warn("purge scheduler: failed to purge transactions")
+	}
+	if err := db.DB.Unscoped().Where(due, now).Delete(&models.Account{}).Error; err != nil {
+		utils.Logger.Warn("purge scheduler: failed to purge accounts")
+	}
+	if err := db.DB.Unscoped().Where(due, now).Delete(&models.Category{}).Error; err != nil {
+		utils.Logger.Warn("purge scheduler: failed to purge categories")
+	}
+	if err := db.DB.Unscoped().Where(due, now).Delete(&models.Budget{}).Error; err != nil {
+		utils.Logger.Warn("purge scheduler: failed to purge budgets")
+	}
+	if err := db.DB.Unscoped().Where(due, now).Delete(&models.User{}).Error; err != nil {
+		utils.Logger.Warn("purge scheduler: failed to purge users")
+	}
+}
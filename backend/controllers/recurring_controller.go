@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/recurring"
+	"Personal-Finance-Tracker-backend/store"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetRecurringRules lists the authenticated user's recurring rules
+func GetRecurringRules(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var rules []models.RecurringRule
+	if err := db.DB.Where("user_id = ?", userID).Order("next_run ASC").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch recurring rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// CreateRecurringRule creates a new recurring rule for the authenticated user
+func CreateRecurringRule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input struct {
+		AccountID   uint   `json:"account_id" binding:"required"`
+		CategoryID  *uint  `json:"category_id"`
+		AmountCents int64  `json:"amount_cents" binding:"required"`
+		Description string `json:"description"`
+		RRule       string `json:"rrule" binding:"required"`
+		NextRun     string `json:"next_run" binding:"required"`
+		EndDate     string `json:"end_date"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := recurring.Parse(input.RRule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rrule: " + err.Error()})
+		return
+	}
+
+	var account models.Account
+	if err := db.DB.Where("id = ? AND user_id = ?", input.AccountID, userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account not found or does not belong to user"})
+		return
+	}
+
+	nextRun, err := time.Parse("2006-01-02", input.NextRun)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid next_run format, use YYYY-MM-DD"})
+		return
+	}
+
+	rule := models.RecurringRule{
+		UserID:      userID,
+		AccountID:   input.AccountID,
+		CategoryID:  input.CategoryID,
+		AmountCents: input.AmountCents,
+		Description: input.Description,
+		RRule:       input.RRule,
+		NextRun:     nextRun,
+	}
+
+	if input.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", input.EndDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format, use YYYY-MM-DD"})
+			return
+		}
+		rule.EndDate = &endDate
+	}
+
+	if err := db.DB.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create recurring rule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRecurringRule updates an existing recurring rule
+func UpdateRecurringRule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	ruleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recurring rule ID"})
+		return
+	}
+
+	var rule models.RecurringRule
+	if err := db.DB.Where("id = ? AND user_id = ?", ruleID, userID).First(&rule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "recurring rule not found"})
+		return
+	}
+
+	var input struct {
+		AmountCents int64  `json:"amount_cents"`
+		Description string `json:"description"`
+		RRule       string `json:"rrule"`
+		EndDate     string `json:"end_date"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.RRule != "" {
+		if _, err := recurring.Parse(input.RRule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rrule: " + err.Error()})
+			return
+		}
+		rule.RRule = input.RRule
+	}
+	if input.AmountCents != 0 {
+		rule.AmountCents = input.AmountCents
+	}
+	if input.Description != "" {
+		rule.Description = input.Description
+	}
+	if input.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", input.EndDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format, use YYYY-MM-DD"})
+			return
+		}
+		rule.EndDate = &endDate
+	}
+
+	if err := db.DB.Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update recurring rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRecurringRule deletes a recurring rule
+func DeleteRecurringRule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	ruleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recurring rule ID"})
+		return
+	}
+
+	var rule models.RecurringRule
+	if err := db.DB.Where("id = ? AND user_id = ?", ruleID, userID).First(&rule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "recurring rule not found"})
+		return
+	}
+
+	if err := db.DB.Delete(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete recurring rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "recurring rule deleted successfully"})
+}
+
+// RunRecurringRuleNow materializes a single rule immediately, regardless of
+// its NextRun, honoring the same at-most-once guard the scheduler uses.
+func RunRecurringRuleNow(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	ruleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recurring rule ID"})
+		return
+	}
+
+	var rule models.RecurringRule
+	if err := db.DB.Where("id = ? AND user_id = ?", ruleID, userID).First(&rule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "recurring rule not found"})
+		return
+	}
+
+	transaction, err := MaterializeRecurringRule(rule.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if transaction == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "occurrence already materialized, nothing to do"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// MaterializeRecurringRule posts the rule's due occurrence as a real
+// Transaction, updates the account balance, and advances NextRun/
+// LastMaterializedAt, all inside a single DB transaction guarded by
+// SELECT ... FOR UPDATE so concurrent/duplicate runs across restarts cannot
+// double-post the same occurrence. Returns a nil Transaction and a nil error
+// when the occurrence was already materialized - there is nothing to report
+// back, and it must not be confused with an actual zero-value Transaction.
+func MaterializeRecurringRule(ruleID uint) (*models.Transaction, error) {
+	var transaction *models.Transaction
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var rule models.RecurringRule
+		query := tx
+		if tx.Dialector.Name() == "postgres" {
+			// SQLite (used in tests) has no SELECT ... FOR UPDATE syntax; its
+			// own transaction locking already makes this step atomic.
+			query = tx.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.First(&rule, ruleID).Error; err != nil {
+			return err
+		}
+
+		occurrence := rule.NextRun
+		if rule.LastMaterializedAt != nil && !rule.LastMaterializedAt.Before(occurrence) {
+			// Already materialized this occurrence; nothing to do.
+			return nil
+		}
+
+		posted := models.Transaction{
+			UserID:      rule.UserID,
+			AccountID:   rule.AccountID,
+			CategoryID:  rule.CategoryID,
+			Amount:      decimal.NewFromInt(rule.AmountCents).Div(decimal.NewFromInt(100)),
+			Description: rule.Description,
+			TxnDate:     occurrence,
+		}
+		if err := tx.Create(&posted).Error; err != nil {
+			return err
+		}
+		transaction = &posted
+
+		if err := store.RecalculateBalance(tx, rule.AccountID); err != nil {
+			return err
+		}
+
+		rule.NextRun = nextRuleRun(rule, occurrence)
+		now := occurrence
+		rule.LastMaterializedAt = &now
+
+		return tx.Save(&rule).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func nextRuleRun(rule models.RecurringRule, occurrence time.Time) time.Time {
+	parsed, err := recurring.Parse(rule.RRule)
+	if err != nil {
+		return occurrence
+	}
+	return parsed.Next(occurrence)
+}
@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+)
+
+// recurringIntervalCVThreshold/recurringAmountTolerance are
+// detectRecurringPattern's clustering cutoffs: inter-arrival times must
+// vary by less than 25% (coefficient of variation) around their mean, and
+// every occurrence's amount must be within 10% of the cluster's mean
+// amount, before a group of transactions is suggested as recurring.
+const (
+	recurringIntervalCVThreshold = 0.25
+	recurringAmountTolerance     = 0.10
+)
+
+// RecurringSuggestion is one statistically-detected recurring pattern
+// DetectRecurringTransactions/GetCashflowForecast found in a user's
+// transaction history, not yet confirmed into a models.RecurringRule.
+type RecurringSuggestion struct {
+	MerchantID     *uint     `json:"merchant_id,omitempty"`
+	Description    string    `json:"description"`
+	AccountID      uint      `json:"account_id"`
+	CategoryID     *uint     `json:"category_id,omitempty"`
+	AmountCents    int64     `json:"amount_cents"`
+	IntervalDays   float64   `json:"interval_days"`
+	SuggestedRRule string    `json:"suggested_rrule"`
+	NextDueAt      time.Time `json:"next_due_at"`
+	Confidence     float64   `json:"confidence"`
+	Occurrences    int       `json:"occurrences"`
+}
+
+// recurringCandidateRow is one transaction being considered for clustering.
+type recurringCandidateRow struct {
+	MerchantID  *uint
+	Description string
+	AccountID   uint
+	CategoryID  *uint
+	Amount      decimal.Decimal
+	TxnDate     time.Time
+}
+
+// meanAndCV returns values' mean and coefficient of variation (stddev /
+// mean). An empty or zero-mean input returns cv=1, the highest value
+// detectRecurringPattern's threshold can reject.
+func meanAndCV(values []float64) (mean, cv float64) {
+	if len(values) == 0 {
+		return 0, 1
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean = sum / float64(len(values))
+	if mean == 0 {
+		return 0, 1
+	}
+
+	var variance float64
+	for _, v := range values {
+		d := v - mean
+		variance += d * d
+	}
+	variance /= float64(len(values))
+
+	return mean, math.Sqrt(variance) / mean
+}
+
+// inferRRule maps an observed mean inter-arrival interval to the closest
+// standard RRULE, falling back to an explicit DAILY;INTERVAL=N for an
+// interval that doesn't land near a week/month/year boundary.
+func inferRRule(avgIntervalDays float64) string {
+	switch {
+	case avgIntervalDays >= 350 && avgIntervalDays <= 380:
+		return "FREQ=YEARLY"
+	case avgIntervalDays >= 27 && avgIntervalDays <= 32:
+		return "FREQ=MONTHLY"
+	case avgIntervalDays >= 12 && avgIntervalDays <= 16:
+		return "FREQ=WEEKLY;INTERVAL=2"
+	case avgIntervalDays >= 6 && avgIntervalDays <= 8:
+		return "FREQ=WEEKLY"
+	default:
+		days := int(math.Round(avgIntervalDays))
+		if days <= 0 {
+			days = 1
+		}
+		return "FREQ=DAILY;INTERVAL=" + strconv.Itoa(days)
+	}
+}
+
+// detectRecurringPattern decides whether rows (all sharing one merchant/
+// description+account grouping) look recurring: 3+ occurrences, low
+// inter-arrival variance, and amounts clustered within
+// recurringAmountTolerance of their mean.
+func detectRecurringPattern(rows []recurringCandidateRow) (RecurringSuggestion, bool) {
+	if len(rows) < 3 {
+		return RecurringSuggestion{}, false
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].TxnDate.Before(rows[j].TxnDate) })
+
+	intervals := make([]float64, 0, len(rows)-1)
+	for i := 1; i < len(rows); i++ {
+		intervals = append(intervals, rows[i].TxnDate.Sub(rows[i-1].TxnDate).Hours()/24)
+	}
+	meanInterval, intervalCV := meanAndCV(intervals)
+	if meanInterval <= 0 || intervalCV >= recurringIntervalCVThreshold {
+		return RecurringSuggestion{}, false
+	}
+
+	amounts := make([]float64, 0, len(rows))
+	for _, row := range rows {
+		f, _ := row.Amount.Abs().Float64()
+		amounts = append(amounts, f)
+	}
+	meanAmount, amountCV := meanAndCV(amounts)
+
+	var maxDeviation float64
+	for _, a := range amounts {
+		if dev := math.Abs(a-meanAmount) / meanAmount; dev > maxDeviation {
+			maxDeviation = dev
+		}
+	}
+	if maxDeviation > recurringAmountTolerance {
+		return RecurringSuggestion{}, false
+	}
+
+	last := rows[len(rows)-1]
+	signedAmount := meanAmount
+	if last.Amount.IsNegative() {
+		signedAmount = -meanAmount
+	}
+
+	confidence := (1 - intervalCV) * (1 - amountCV)
+	if confidence < 0 {
+		confidence = 0
+	}
+
+	return RecurringSuggestion{
+		MerchantID:     last.MerchantID,
+		Description:    last.Description,
+		AccountID:      last.AccountID,
+		CategoryID:     last.CategoryID,
+		AmountCents:    centsOf(decimal.NewFromFloat(signedAmount)),
+		IntervalDays:   meanInterval,
+		SuggestedRRule: inferRRule(meanInterval),
+		NextDueAt:      last.TxnDate.AddDate(0, 0, int(math.Round(meanInterval))),
+		Confidence:     confidence,
+		Occurrences:    len(rows),
+	}, true
+}
+
+// recurringGroupKey groups transactions the way detectRecurringPattern
+// expects to see them: by merchant when one matched, falling back to the
+// normalized description when it didn't - both scoped to one account,
+// since the same payee charged to two different accounts isn't one
+// recurring schedule.
+type recurringGroupKey struct {
+	MerchantID  uint
+	Description string
+	AccountID   uint
+}
+
+// detectRecurringSuggestions groups userID's transactions since `since` by
+// recurringGroupKey and runs detectRecurringPattern over each group with
+// 3+ occurrences, returning every cluster found, highest confidence first.
+func detectRecurringSuggestions(userID uint, since time.Time) []RecurringSuggestion {
+	var rows []recurringCandidateRow
+	db.DB.Model(&models.Transaction{}).
+		Select("merchant_id, description, account_id, category_id, amount, txn_date").
+		Where("user_id = ? AND txn_date >= ?", userID, since).
+		Order("txn_date ASC").
+		Scan(&rows)
+
+	groups := make(map[recurringGroupKey][]recurringCandidateRow)
+	for _, row := range rows {
+		key := recurringGroupKey{AccountID: row.AccountID}
+		if row.MerchantID != nil {
+			key.MerchantID = *row.MerchantID
+		} else {
+			key.Description = NormalizeDescription(row.Description)
+		}
+		groups[key] = append(groups[key], row)
+	}
+
+	var suggestions []RecurringSuggestion
+	for _, group := range groups {
+		if suggestion, ok := detectRecurringPattern(group); ok {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool { return suggestions[i].Confidence > suggestions[j].Confidence })
+	return suggestions
+}
+
+// DetectRecurringTransactions scans the authenticated user's last 12
+// months of transactions for statistically recurring patterns (see
+// detectRecurringSuggestions) and returns them for the user to confirm
+// into a models.RecurringRule via CreateRecurringRule - this endpoint only
+// suggests, it never writes a RecurringRule itself.
+func DetectRecurringTransactions(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	suggestions := detectRecurringSuggestions(userID, time.Now().AddDate(0, -12, 0))
+
+	c.JSON(http.StatusOK, gin.H{"suggestions": suggestions})
+}
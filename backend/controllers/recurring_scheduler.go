@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+)
+
+// StartRecurringScheduler launches a background goroutine that periodically
+// materializes any RecurringRule whose NextRun has passed. It is safe to
+// restart the process at any time: MaterializeRecurringRule's
+// LastMaterializedAt guard prevents double-posting an occurrence that a
+// previous run already materialized.
+func StartRecurringScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runDueRecurringRules()
+		}
+	}()
+}
+
+func runDueRecurringRules() {
+	var due []models.RecurringRule
+	now := time.Now()
+
+	if err := db.DB.Where("next_run <= ? AND (end_date IS NULL OR end_date >= ?)", now, now).Find(&due).Error; err != nil {
+		utils.Logger.Warn("recurring scheduler: failed to load due rules")
+		return
+	}
+
+	for _, rule := range due {
+		if _, err := MaterializeRecurringRule(rule.ID); err != nil {
+			utils.Logger.Warn("recurring scheduler: failed to materialize rule")
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package controllers
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "time"
+
+    "Personal-Finance-Tracker-backend/db"
+    "Personal-Finance-Tracker-backend/models"
+)
+
+// ScheduledReportRender is a report flattened into a title/subtitle plus a
+// plain table, so services/reports/chore can hand it to a CSV or PDF
+// writer without knowing which report type produced it.
+type ScheduledReportRender struct {
+    Title    string
+    Subtitle string
+    Headers  []string
+    Rows     [][]string
+}
+
+// RenderScheduledReport builds schedule's report by calling the exact same
+// compute functions GetSpendSummary/GetCashflow/GetBudgetProgress/
+// RunCustomReport serve over HTTP (the same reuse digest_scheduler.go
+// already relies on), then flattens the result into rows.
+func RenderScheduledReport(schedule models.ReportSchedule) (ScheduledReportRender, error) {
+    switch schedule.ReportType {
+    case models.ReportScheduleSpendSummary:
+        return renderSpendSummarySchedule(schedule)
+    case models.ReportScheduleCashflow:
+        return renderCashflowSchedule(schedule)
+    case models.ReportScheduleBudgetProgress:
+        return renderBudgetProgressSchedule(schedule)
+    case models.ReportScheduleCustom:
+        return renderCustomReportSchedule(schedule)
+    default:
+        return ScheduledReportRender{}, fmt.Errorf("unsupported report_type: %q", schedule.ReportType)
+    }
+}
+
+// scheduleDateRange resolves a ReportSchedule's from/to window: an
+// explicit Period preset takes priority, then explicit FromDate/ToDate,
+// falling back to the trailing 30 days - the same default GetSpendSummary
+// applies when no from/to/period query param is given.
+func scheduleDateRange(params models.ReportScheduleParams, now time.Time) (from, to time.Time, err error) {
+    if params.Period != "" {
+        return resolvePeriodPreset(params.Period, now)
+    }
+    if params.FromDate != nil {
+        from = *params.FromDate
+    } else {
+        from = now.AddDate(0, 0, -30)
+    }
+    if params.ToDate != nil {
+        to = *params.ToDate
+    } else {
+        to = now
+    }
+    return from, to, nil
+}
+
+func scheduleReportCurrency(userID uint, params models.ReportScheduleParams) (*models.ReportSecurity, error) {
+    if params.ReportCurrency == "" {
+        return nil, nil
+    }
+    return findSecurity(userID, params.ReportCurrency)
+}
+
+func renderSpendSummarySchedule(schedule models.ReportSchedule) (ScheduledReportRender, error) {
+    now := time.Now()
+    from, to, err := scheduleDateRange(schedule.Params, now)
+    if err != nil {
+        return ScheduledReportRender{}, err
+    }
+    reportCurrency, err := scheduleReportCurrency(schedule.UserID, schedule.Params)
+    if err != nil {
+        return ScheduledReportRender{}, err
+    }
+
+    summary := computeSpendSummary(schedule.UserID, from, to, reportCurrency)
+
+    rows := make([][]string, 0, len(summary.Categories))
+    for _, cat := range summary.Categories {
+        rows = append(rows, []string{
+            cat.CategoryName,
+            cat.CategoryKind,
+            formatCents(cat.TotalCents),
+            fmt.Sprintf("%.1f%%", cat.Percentage),
+        })
+    }
+
+    return ScheduledReportRender{
+        Title:    "Spend Summary",
+        Subtitle: fmt.Sprintf("%s to %s - total %s", from.Format("2006-01-02"), to.Format("2006-01-02"), formatCents(summary.TotalCents)),
+        Headers:  []string{"Category", "Kind", "Total", "Percentage"},
+        Rows:     rows,
+    }, nil
+}
+
+func renderCashflowSchedule(schedule models.ReportSchedule) (ScheduledReportRender, error) {
+    now := time.Now()
+    from, to, err := scheduleDateRange(schedule.Params, now)
+    if err != nil {
+        return ScheduledReportRender{}, err
+    }
+    reportCurrency, err := scheduleReportCurrency(schedule.UserID, schedule.Params)
+    if err != nil {
+        return ScheduledReportRender{}, err
+    }
+
+    groupBy := schedule.Params.GroupBy
+    if groupBy == "" {
+        groupBy = "month"
+    }
+
+    summary := computeCashflow(schedule.UserID, from, to, groupBy, reportCurrency)
+
+    rows := make([][]string, 0, len(summary.Periods))
+    for _, period := range summary.Periods {
+        rows = append(rows, []string{
+            period.Period,
+            formatCents(period.IncomeCents),
+            formatCents(period.ExpenseCents),
+            formatCents(period.NetCents),
+            formatCents(period.RunningBalanceCents),
+        })
+    }
+
+    return ScheduledReportRender{
+        Title:    "Cashflow",
+        Subtitle: fmt.Sprintf("%s to %s, grouped by %s", from.Format("2006-01-02"), to.Format("2006-01-02"), groupBy),
+        Headers:  []string{"Period", "Income", "Expense", "Net", "Running Balance"},
+        Rows:     rows,
+    }, nil
+}
+
+func renderBudgetProgressSchedule(schedule models.ReportSchedule) (ScheduledReportRender, error) {
+    var budget models.Budget
+    now := time.Now()
+
+    if schedule.Params.BudgetID != nil {
+        if err := db.DB.Preload("Items.Category").
+            Where("id = ? AND user_id = ?", *schedule.Params.BudgetID, schedule.UserID).
+            First(&budget).Error; err != nil {
+            return ScheduledReportRender{}, fmt.Errorf("budget not found: %w", err)
+        }
+    } else {
+        if err := db.DB.Preload("Items.Category").
+            Where("user_id = ? AND period_start <= ? AND period_end >= ?", schedule.UserID, now, now).
+            First(&budget).Error; err != nil {
+            return ScheduledReportRender{}, fmt.Errorf("no active budget found: %w", err)
+        }
+    }
+
+    summary := computeBudgetProgress(schedule.UserID, budget)
+
+    rows := make([][]string, 0, len(summary.Categories))
+    for _, cat := range summary.Categories {
+        rows = append(rows, []string{
+            cat.CategoryName,
+            formatCents(cat.PlannedCents),
+            formatCents(cat.SpentCents),
+            formatCents(cat.RemainingCents),
+            cat.Status,
+        })
+    }
+
+    return ScheduledReportRender{
+        Title: "Budget Progress",
+        Subtitle: fmt.Sprintf("%s to %s - planned %s, spent %s",
+            budget.PeriodStart.Format("2006-01-02"), budget.PeriodEnd.Format("2006-01-02"),
+            formatCents(summary.TotalPlannedCents), formatCents(summary.TotalSpentCents)),
+        Headers: []string{"Category", "Planned", "Spent", "Remaining", "Status"},
+        Rows:    rows,
+    }, nil
+}
+
+func renderCustomReportSchedule(schedule models.ReportSchedule) (ScheduledReportRender, error) {
+    if schedule.Params.ReportID == nil {
+        return ScheduledReportRender{}, fmt.Errorf("custom report schedule is missing params.report_id")
+    }
+
+    var report models.Report
+    if err := db.DB.Where("id = ? AND user_id = ?", *schedule.Params.ReportID, schedule.UserID).First(&report).Error; err != nil {
+        return ScheduledReportRender{}, fmt.Errorf("report not found: %w", err)
+    }
+    if report.LuaSource == nil {
+        return ScheduledReportRender{}, fmt.Errorf("report %d has no lua_source", report.ID)
+    }
+
+    tab, err := reportsService.Run(context.Background(), schedule.UserID, *report.LuaSource)
+    if err != nil {
+        return ScheduledReportRender{}, err
+    }
+
+    seriesKeys := make([]string, 0, len(tab.Series))
+    for key := range tab.Series {
+        seriesKeys = append(seriesKeys, key)
+    }
+    sort.Strings(seriesKeys)
+
+    rows := make([][]string, 0, len(tab.Labels))
+    for i, label := range tab.Labels {
+        row := []string{label}
+        for _, key := range seriesKeys {
+            values := tab.Series[key]
+            if i < len(values) {
+                row = append(row, fmt.Sprintf("%d", values[i]))
+            } else {
+                row = append(row, "")
+            }
+        }
+        rows = append(rows, row)
+    }
+
+    headers := append([]string{""}, seriesKeys...)
+
+    return ScheduledReportRender{
+        Title:    tab.Title,
+        Subtitle: tab.Subtitle,
+        Headers:  headers,
+        Rows:     rows,
+    }, nil
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/cronexpr"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// GetReportSchedules lists the authenticated user's report schedules.
+func GetReportSchedules(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var schedules []models.ReportSchedule
+	if err := db.DB.Where("user_id = ?", userID).Order("next_run_at ASC").Find(&schedules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch report schedules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// reportScheduleInput is CreateReportSchedule/UpdateReportSchedule's shared
+// request body.
+type reportScheduleInput struct {
+	ReportType     models.ReportScheduleType   `json:"report_type" binding:"required"`
+	Params         models.ReportScheduleParams `json:"params"`
+	Cron           string                      `json:"cron" binding:"required"`
+	Timezone       string                      `json:"timezone"`
+	DeliveryMethod models.ReportDeliveryMethod `json:"delivery_method" binding:"required"`
+	Target         string                      `json:"target" binding:"required"`
+}
+
+// CreateReportSchedule creates a new report schedule for the authenticated
+// user, computing its first NextRunAt from Cron/Timezone.
+func CreateReportSchedule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input reportScheduleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Timezone == "" {
+		input.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(input.Timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid timezone"})
+		return
+	}
+	if _, err := cronexpr.Parse(input.Cron); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cron: " + err.Error()})
+		return
+	}
+
+	now := time.Now()
+	nextRun, err := cronexpr.Next(input.Cron, now, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cron: " + err.Error()})
+		return
+	}
+
+	schedule := models.ReportSchedule{
+		UserID:         userID,
+		ReportType:     input.ReportType,
+		Params:         input.Params,
+		Cron:           input.Cron,
+		Timezone:       input.Timezone,
+		DeliveryMethod: input.DeliveryMethod,
+		Target:         input.Target,
+		NextRunAt:      nextRun,
+	}
+
+	if err := db.DB.Create(&schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create report schedule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateReportSchedule updates an existing report schedule, recomputing
+// NextRunAt if Cron or Timezone changed.
+func UpdateReportSchedule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var schedule models.ReportSchedule
+	if err := db.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&schedule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "report schedule not found"})
+		return
+	}
+
+	var input reportScheduleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Timezone == "" {
+		input.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(input.Timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid timezone"})
+		return
+	}
+	if _, err := cronexpr.Parse(input.Cron); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cron: " + err.Error()})
+		return
+	}
+
+	rescheduled := input.Cron != schedule.Cron || input.Timezone != schedule.Timezone
+
+	schedule.ReportType = input.ReportType
+	schedule.Params = input.Params
+	schedule.Cron = input.Cron
+	schedule.Timezone = input.Timezone
+	schedule.DeliveryMethod = input.DeliveryMethod
+	schedule.Target = input.Target
+
+	if rescheduled {
+		nextRun, err := cronexpr.Next(input.Cron, time.Now(), loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cron: " + err.Error()})
+			return
+		}
+		schedule.NextRunAt = nextRun
+	}
+
+	if err := db.DB.Save(&schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update report schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteReportSchedule deletes a report schedule.
+func DeleteReportSchedule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var schedule models.ReportSchedule
+	if err := db.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&schedule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "report schedule not found"})
+		return
+	}
+
+	if err := db.DB.Delete(&schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete report schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "report schedule deleted successfully"})
+}
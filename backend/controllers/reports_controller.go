@@ -1,107 +1,171 @@
 package controllers
 
 import (
+    "fmt"
+    "math"
     "net/http"
+    "sort"
     "strconv"
     "time"
 
     "Personal-Finance-Tracker-backend/db"
     "Personal-Finance-Tracker-backend/models"
+    "Personal-Finance-Tracker-backend/recurring"
     "github.com/gin-gonic/gin"
     jwt "github.com/golang-jwt/jwt/v5"
+    "github.com/shopspring/decimal"
 )
 
-// GetSpendSummary provides spending breakdown by category
-func GetSpendSummary(c *gin.Context) {
-    claims, exists := c.Get("user")
-    if !exists {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-        return
-    }
+// CategorySpend is one category's total spend within a SpendSummary.
+type CategorySpend struct {
+    CategoryID   uint   `json:"category_id"`
+    CategoryName string `json:"category_name"`
+    CategoryKind string `json:"category_kind"`
+    TotalCents   int64  `json:"total_cents"`
+    Count        int64  `json:"transaction_count"`
+}
 
-    userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+// CategorySpendWithPercent is a CategorySpend plus its share of the
+// period's total spending.
+type CategorySpendWithPercent struct {
+    CategorySpend
+    Percentage float64 `json:"percentage"`
+}
 
-    from := c.Query("from")
-    to := c.Query("to")
-    
-    var fromDate, toDate time.Time
-    var err error
+// SpendSummary is computeSpendSummary's result: every category a user spent
+// against in [from, to], its total, and its percentage of the period's
+// overall spend.
+type SpendSummary struct {
+    FromDate   time.Time
+    ToDate     time.Time
+    TotalCents int64
+    Categories []CategorySpendWithPercent
+}
 
-    if from == "" {
-        now := time.Now()
-        fromDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-    } else {
-        fromDate, err = time.Parse("2006-01-02", from)
-        if err != nil {
-            c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date format, use YYYY-MM-DD"})
-            return
-        }
-    }
+// categorySpendRow is one category's running total/count, whether it came
+// from the fast SQL-side SUM path or the per-row report_currency path.
+type categorySpendRow struct {
+    CategoryID   uint
+    CategoryName string
+    CategoryKind string
+    Total        decimal.Decimal
+    Count        int64
+}
 
-    if to == "" {
-        now := time.Now()
-        toDate = time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
-    } else {
-        toDate, err = time.Parse("2006-01-02", to)
-        if err != nil {
-            c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date format, use YYYY-MM-DD"})
-            return
-        }
-    }
+// categorySpendTxnRow is one transaction or split, pre-aggregation, used
+// only by computeSpendSummary's report_currency path.
+type categorySpendTxnRow struct {
+    CategoryID   uint
+    CategoryName string
+    CategoryKind string
+    Amount       decimal.Decimal
+    SecurityID   *uint
+    TxnDate      time.Time
+}
 
-    type CategorySpend struct {
-        CategoryID   uint   `json:"category_id"`
-        CategoryName string `json:"category_name"`
-        CategoryKind string `json:"category_kind"`
-        TotalCents   int64  `json:"total_cents"`
-        Count        int64  `json:"transaction_count"`
+// foldCategorySpendRows converts each row through convertAmount, using its
+// own TxnDate, and folds it into a categorySpendRow per category.
+func foldCategorySpendRows(rows []categorySpendTxnRow, reportCurrency *models.ReportSecurity) []categorySpendRow {
+    rowMap := make(map[uint]*categorySpendRow)
+    var result []categorySpendRow
+    for _, row := range rows {
+        converted := convertAmount(row.Amount, row.SecurityID, row.TxnDate, reportCurrency)
+        if existing, ok := rowMap[row.CategoryID]; ok {
+            existing.Total = existing.Total.Add(converted)
+            existing.Count++
+            continue
+        }
+        result = append(result, categorySpendRow{
+            CategoryID:   row.CategoryID,
+            CategoryName: row.CategoryName,
+            CategoryKind: row.CategoryKind,
+            Total:        converted,
+            Count:        1,
+        })
+        rowMap[row.CategoryID] = &result[len(result)-1]
     }
+    return result
+}
 
-    var categorySpends []CategorySpend
-
-    db.DB.Table("transactions").
-        Select("categories.id as category_id, categories.name as category_name, categories.kind as category_kind, SUM(ABS(transactions.amount_cents)) as total_cents, COUNT(*) as count").
-        Joins("JOIN categories ON categories.id = transactions.category_id").
-        Where("transactions.user_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ? AND transactions.amount_cents < 0", userID, fromDate, toDate).
-        Group("categories.id, categories.name, categories.kind").
-        Order("total_cents DESC").
-        Scan(&categorySpends)
-
-    var splitSpends []CategorySpend
+// computeSpendSummary builds the spending-by-category breakdown GetSpendSummary
+// serves and controllers.StartDigestScheduler's weekly/monthly digest reuses,
+// so both read the exact same GORM queries instead of drifting apart.
+// reportCurrency nil keeps the fast SQL-side SUM path (every amount assumed
+// to already be comparable); non-nil switches to a per-row path that
+// converts each transaction/split through convertAmount, using its own
+// TxnDate, before folding it into the category total.
+func computeSpendSummary(userID uint, fromDate, toDate time.Time, reportCurrency *models.ReportSecurity) SpendSummary {
+    var categoryRows []categorySpendRow
+    var splitRows []categorySpendRow
+
+    if reportCurrency == nil {
+        db.DB.Table("transactions").
+            Select("categories.id as category_id, categories.name as category_name, categories.kind as category_kind, SUM(ABS(transactions.amount)) as total, COUNT(*) as count").
+            Joins("JOIN categories ON categories.id = transactions.category_id").
+            Where("transactions.user_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ? AND transactions.amount < 0", userID, fromDate, toDate).
+            Group("categories.id, categories.name, categories.kind").
+            Order("total DESC").
+            Scan(&categoryRows)
 
-    db.DB.Table("transaction_splits").
-        Select("categories.id as category_id, categories.name as category_name, categories.kind as category_kind, SUM(ABS(transaction_splits.amount_cents)) as total_cents, COUNT(*) as count").
-        Joins("JOIN categories ON categories.id = transaction_splits.category_id").
-        Joins("JOIN transactions ON transactions.id = transaction_splits.parent_txn_id").
-        Where("transactions.user_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ?", userID, fromDate, toDate).
-        Group("categories.id, categories.name, categories.kind").
-        Scan(&splitSpends)
+        db.DB.Table("transaction_splits").
+            Select("categories.id as category_id, categories.name as category_name, categories.kind as category_kind, SUM(ABS(transaction_splits.amount)) as total, COUNT(*) as count").
+            Joins("JOIN categories ON categories.id = transaction_splits.category_id").
+            Joins("JOIN transactions ON transactions.id = transaction_splits.parent_txn_id").
+            Where("transactions.user_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ?", userID, fromDate, toDate).
+            Group("categories.id, categories.name, categories.kind").
+            Scan(&splitRows)
+    } else {
+        var txnRows []categorySpendTxnRow
+        db.DB.Table("transactions").
+            Select("categories.id as category_id, categories.name as category_name, categories.kind as category_kind, ABS(transactions.amount) as amount, accounts.security_id as security_id, transactions.txn_date as txn_date").
+            Joins("JOIN categories ON categories.id = transactions.category_id").
+            Joins("JOIN accounts ON accounts.id = transactions.account_id").
+            Where("transactions.user_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ? AND transactions.amount < 0", userID, fromDate, toDate).
+            Scan(&txnRows)
+        categoryRows = foldCategorySpendRows(txnRows, reportCurrency)
+
+        var splitTxnRows []categorySpendTxnRow
+        db.DB.Table("transaction_splits").
+            Select("categories.id as category_id, categories.name as category_name, categories.kind as category_kind, ABS(transaction_splits.amount) as amount, accounts.security_id as security_id, transactions.txn_date as txn_date").
+            Joins("JOIN categories ON categories.id = transaction_splits.category_id").
+            Joins("JOIN transactions ON transactions.id = transaction_splits.parent_txn_id").
+            Joins("JOIN accounts ON accounts.id = transactions.account_id").
+            Where("transactions.user_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ?", userID, fromDate, toDate).
+            Scan(&splitTxnRows)
+        splitRows = foldCategorySpendRows(splitTxnRows, reportCurrency)
+    }
 
-    categoryMap := make(map[uint]*CategorySpend)
-    for i := range categorySpends {
-        categoryMap[categorySpends[i].CategoryID] = &categorySpends[i]
+    rowMap := make(map[uint]*categorySpendRow)
+    for i := range categoryRows {
+        rowMap[categoryRows[i].CategoryID] = &categoryRows[i]
     }
 
-    for _, split := range splitSpends {
-        if existing, exists := categoryMap[split.CategoryID]; exists {
-            existing.TotalCents += split.TotalCents
+    for _, split := range splitRows {
+        if existing, exists := rowMap[split.CategoryID]; exists {
+            existing.Total = existing.Total.Add(split.Total)
             existing.Count += split.Count
         } else {
-            categorySpends = append(categorySpends, split)
-            categoryMap[split.CategoryID] = &split
+            categoryRows = append(categoryRows, split)
+            rowMap[split.CategoryID] = &split
         }
     }
 
+    categorySpends := make([]CategorySpend, 0, len(categoryRows))
+    for _, row := range categoryRows {
+        categorySpends = append(categorySpends, CategorySpend{
+            CategoryID:   row.CategoryID,
+            CategoryName: row.CategoryName,
+            CategoryKind: row.CategoryKind,
+            TotalCents:   centsOf(row.Total),
+            Count:        row.Count,
+        })
+    }
+
     var totalSpending int64
     for _, spend := range categorySpends {
         totalSpending += spend.TotalCents
     }
 
-    type CategorySpendWithPercent struct {
-        CategorySpend
-        Percentage float64 `json:"percentage"`
-    }
-
     var result []CategorySpendWithPercent
     for _, spend := range categorySpends {
         percentage := 0.0
@@ -114,18 +178,16 @@ func GetSpendSummary(c *gin.Context) {
         })
     }
 
-    c.JSON(http.StatusOK, gin.H{
-        "period": gin.H{
-            "from": fromDate.Format("2006-01-02"),
-            "to":   toDate.Format("2006-01-02"),
-        },
-        "total_spent_cents": totalSpending,
-        "categories":        result,
-    })
+    return SpendSummary{
+        FromDate:   fromDate,
+        ToDate:     toDate,
+        TotalCents: totalSpending,
+        Categories: result,
+    }
 }
 
-// GetCashflow provides income vs expenses over time
-func GetCashflow(c *gin.Context) {
+// GetSpendSummary provides spending breakdown by category
+func GetSpendSummary(c *gin.Context) {
     claims, exists := c.Get("user")
     if !exists {
         c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
@@ -133,112 +195,270 @@ func GetCashflow(c *gin.Context) {
     }
 
     userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
     from := c.Query("from")
     to := c.Query("to")
-    groupBy := c.DefaultQuery("group_by", "month")
 
     var fromDate, toDate time.Time
     var err error
 
-    if from == "" {
-        fromDate = time.Now().AddDate(0, -12, 0)
-    } else {
-        fromDate, err = time.Parse("2006-01-02", from)
+    if period := c.Query("period"); period != "" {
+        fromDate, toDate, err = resolvePeriodPreset(period, time.Now())
         if err != nil {
-            c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date format, use YYYY-MM-DD"})
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
             return
         }
+    } else {
+        if from == "" {
+            now := time.Now()
+            fromDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+        } else {
+            fromDate, err = time.Parse("2006-01-02", from)
+            if err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date format, use YYYY-MM-DD"})
+                return
+            }
+        }
+
+        if to == "" {
+            now := time.Now()
+            toDate = time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
+        } else {
+            toDate, err = time.Parse("2006-01-02", to)
+            if err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date format, use YYYY-MM-DD"})
+                return
+            }
+        }
     }
 
-    if to == "" {
-        toDate = time.Now()
-    } else {
-        toDate, err = time.Parse("2006-01-02", to)
+    var reportCurrency *models.ReportSecurity
+    if symbol := c.Query("report_currency"); symbol != "" {
+        reportCurrency, err = findSecurity(userID, symbol)
         if err != nil {
-            c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date format, use YYYY-MM-DD"})
+            c.JSON(http.StatusBadRequest, gin.H{"error": "unknown report_currency security"})
             return
         }
     }
 
-    // ✅ Detect database driver
-    dbDriver := db.DB.Dialector.Name()
-    var dateFormat string
+    summary := computeSpendSummary(userID, fromDate, toDate, reportCurrency)
 
-    if dbDriver == "sqlite" {
-        // SQLite syntax
-        switch groupBy {
-        case "day":
-            dateFormat = "DATE(txn_date)"
-        case "week":
-            dateFormat = "DATE(txn_date, 'weekday 0', '-6 days')"
-        case "year":
-            dateFormat = "STRFTIME('%Y', txn_date)"
-        default: // month
-            dateFormat = "STRFTIME('%Y-%m', txn_date)"
-        }
-    } else {
-        // PostgreSQL syntax
-        switch groupBy {
-        case "day":
-            dateFormat = "DATE(txn_date)"
-        case "week":
-            dateFormat = "TO_CHAR(DATE_TRUNC('week', txn_date), 'YYYY-MM-DD')"
-        case "year":
-            dateFormat = "TO_CHAR(DATE_TRUNC('year', txn_date), 'YYYY')"
-        default: // month
-            dateFormat = "TO_CHAR(DATE_TRUNC('month', txn_date), 'YYYY-MM')"
-        }
-    }
+    c.JSON(http.StatusOK, gin.H{
+        "period": gin.H{
+            "from": fromDate.Format("2006-01-02"),
+            "to":   toDate.Format("2006-01-02"),
+        },
+        "total_spent_cents": summary.TotalCents,
+        "categories":        summary.Categories,
+    })
+}
 
-    type CashflowPeriod struct {
-        Period       string `json:"period"`
-        IncomeCents  int64  `json:"income_cents"`
-        ExpenseCents int64  `json:"expense_cents"`
-        NetCents     int64  `json:"net_cents"`
-    }
+// CashflowWithBalance is one grouped period's income/expense totals plus the
+// running balance through the end of that period.
+type CashflowWithBalance struct {
+    Period              string `json:"period"`
+    IncomeCents         int64  `json:"income_cents"`
+    ExpenseCents        int64  `json:"expense_cents"`
+    NetCents            int64  `json:"net_cents"`
+    RunningBalanceCents int64  `json:"running_balance_cents"`
+}
 
-    var periods []CashflowPeriod
+// CashflowSummary is computeCashflow's result.
+type CashflowSummary struct {
+    FromDate          time.Time
+    ToDate            time.Time
+    GroupBy           string
+    TotalIncomeCents  int64
+    TotalExpenseCents int64
+    Periods           []CashflowWithBalance
+}
 
-    db.DB.Raw(`
-        SELECT 
+// cashflowPeriod is one grouped period's raw income/expense/net totals,
+// before they're rounded to cents and given a running balance.
+type cashflowPeriod struct {
+    Period  string          `json:"period"`
+    Income  decimal.Decimal `json:"-"`
+    Expense decimal.Decimal `json:"-"`
+    Net     decimal.Decimal `json:"-"`
+}
+
+// computeCashflow builds the grouped income/expense/running-balance series
+// GetCashflow serves and controllers.StartDigestScheduler's week-over-week
+// delta reuses. reportCurrency nil keeps the fast SQL-side SUM/GROUP BY
+// path; non-nil switches to a per-transaction path that converts each
+// amount through convertAmount, using its own TxnDate, before bucketing it
+// with periodKeyFor - SQL can't GROUP BY a per-row FX rate the way it can
+// a plain date truncation.
+func computeCashflow(userID uint, fromDate, toDate time.Time, groupBy string, reportCurrency *models.ReportSecurity) CashflowSummary {
+    var periods []cashflowPeriod
+
+    if reportCurrency == nil {
+        // ✅ Detect database driver
+        dbDriver := db.DB.Dialector.Name()
+        var dateFormat string
+
+        if dbDriver == "sqlite" {
+            // SQLite syntax
+            switch groupBy {
+            case "day":
+                dateFormat = "DATE(txn_date)"
+            case "week":
+                dateFormat = "DATE(txn_date, 'weekday 0', '-6 days')"
+            case "year":
+                dateFormat = "STRFTIME('%Y', txn_date)"
+            default: // month
+                dateFormat = "STRFTIME('%Y-%m', txn_date)"
+            }
+        } else {
+            // PostgreSQL syntax
+            switch groupBy {
+            case "day":
+                dateFormat = "DATE(txn_date)"
+            case "week":
+                dateFormat = "TO_CHAR(DATE_TRUNC('week', txn_date), 'YYYY-MM-DD')"
+            case "year":
+                dateFormat = "TO_CHAR(DATE_TRUNC('year', txn_date), 'YYYY')"
+            default: // month
+                dateFormat = "TO_CHAR(DATE_TRUNC('month', txn_date), 'YYYY-MM')"
+            }
+        }
+
+        db.DB.Raw(`
+        SELECT
             `+dateFormat+` as period,
-            COALESCE(SUM(CASE WHEN amount_cents > 0 THEN amount_cents ELSE 0 END), 0) as income_cents,
-            COALESCE(SUM(CASE WHEN amount_cents < 0 THEN ABS(amount_cents) ELSE 0 END), 0) as expense_cents,
-            COALESCE(SUM(amount_cents), 0) as net_cents
+            COALESCE(SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END), 0) as income,
+            COALESCE(SUM(CASE WHEN amount < 0 THEN ABS(amount) ELSE 0 END), 0) as expense,
+            COALESCE(SUM(amount), 0) as net
         FROM transactions
         WHERE user_id = ? AND txn_date >= ? AND txn_date <= ?
         GROUP BY period
         ORDER BY period ASC
     `, userID, fromDate, toDate).Scan(&periods)
+    } else {
+        type cashflowTxnRow struct {
+            Amount     decimal.Decimal
+            SecurityID *uint
+            TxnDate    time.Time
+        }
+
+        var rows []cashflowTxnRow
+        db.DB.Table("transactions").
+            Select("transactions.amount as amount, accounts.security_id as security_id, transactions.txn_date as txn_date").
+            Joins("JOIN accounts ON accounts.id = transactions.account_id").
+            Where("transactions.user_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ?", userID, fromDate, toDate).
+            Scan(&rows)
+
+        byPeriod := make(map[string]*cashflowPeriod)
+        for _, row := range rows {
+            converted := convertAmount(row.Amount, row.SecurityID, row.TxnDate, reportCurrency)
+            key := periodKeyFor(row.TxnDate, groupBy)
+            period, ok := byPeriod[key]
+            if !ok {
+                period = &cashflowPeriod{Period: key}
+                byPeriod[key] = period
+                periods = append(periods, *period)
+            }
+            if converted.IsPositive() {
+                period.Income = period.Income.Add(converted)
+            } else if converted.IsNegative() {
+                period.Expense = period.Expense.Add(converted.Abs())
+            }
+            period.Net = period.Net.Add(converted)
+        }
 
-    type CashflowWithBalance struct {
-        Period              string `json:"period"`
-        IncomeCents         int64  `json:"income_cents"`
-        ExpenseCents        int64  `json:"expense_cents"`
-        NetCents            int64  `json:"net_cents"`
-        RunningBalanceCents int64  `json:"running_balance_cents"`
+        // Rebuild periods from byPeriod now that every row has been folded
+        // in (the append above only reserved each period's slot/order).
+        for i := range periods {
+            periods[i] = *byPeriod[periods[i].Period]
+        }
+        sort.Slice(periods, func(i, j int) bool { return periods[i].Period < periods[j].Period })
     }
 
     var result []CashflowWithBalance
     var runningBalance int64
 
     for _, period := range periods {
-        runningBalance += period.NetCents
+        runningBalance += centsOf(period.Net)
         result = append(result, CashflowWithBalance{
             Period:              period.Period,
-            IncomeCents:         period.IncomeCents,
-            ExpenseCents:        period.ExpenseCents,
-            NetCents:            period.NetCents,
+            IncomeCents:         centsOf(period.Income),
+            ExpenseCents:        centsOf(period.Expense),
+            NetCents:            centsOf(period.Net),
             RunningBalanceCents: runningBalance,
         })
     }
 
     var totalIncome, totalExpenses int64
     for _, period := range periods {
-        totalIncome += period.IncomeCents
-        totalExpenses += period.ExpenseCents
+        totalIncome += centsOf(period.Income)
+        totalExpenses += centsOf(period.Expense)
+    }
+
+    return CashflowSummary{
+        FromDate:          fromDate,
+        ToDate:            toDate,
+        GroupBy:           groupBy,
+        TotalIncomeCents:  totalIncome,
+        TotalExpenseCents: totalExpenses,
+        Periods:           result,
+    }
+}
+
+// GetCashflow provides income vs expenses over time
+func GetCashflow(c *gin.Context) {
+    claims, exists := c.Get("user")
+    if !exists {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+        return
+    }
+
+    userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+    from := c.Query("from")
+    to := c.Query("to")
+    groupBy := c.DefaultQuery("group_by", "month")
+
+    var fromDate, toDate time.Time
+    var err error
+
+    if period := c.Query("period"); period != "" {
+        fromDate, toDate, err = resolvePeriodPreset(period, time.Now())
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+    } else {
+        if from == "" {
+            fromDate = time.Now().AddDate(0, -12, 0)
+        } else {
+            fromDate, err = time.Parse("2006-01-02", from)
+            if err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date format, use YYYY-MM-DD"})
+                return
+            }
+        }
+
+        if to == "" {
+            toDate = time.Now()
+        } else {
+            toDate, err = time.Parse("2006-01-02", to)
+            if err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date format, use YYYY-MM-DD"})
+                return
+            }
+        }
+    }
+
+    var reportCurrency *models.ReportSecurity
+    if symbol := c.Query("report_currency"); symbol != "" {
+        reportCurrency, err = findSecurity(userID, symbol)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "unknown report_currency security"})
+            return
+        }
     }
 
+    summary := computeCashflow(userID, fromDate, toDate, groupBy, reportCurrency)
+
     c.JSON(http.StatusOK, gin.H{
         "period": gin.H{
             "from":     fromDate.Format("2006-01-02"),
@@ -246,11 +466,11 @@ func GetCashflow(c *gin.Context) {
             "group_by": groupBy,
         },
         "summary": gin.H{
-            "total_income_cents":  totalIncome,
-            "total_expense_cents": totalExpenses,
-            "net_cents":           totalIncome - totalExpenses,
+            "total_income_cents":  summary.TotalIncomeCents,
+            "total_expense_cents": summary.TotalExpenseCents,
+            "net_cents":           summary.TotalIncomeCents - summary.TotalExpenseCents,
         },
-        "periods": result,
+        "periods": summary.Periods,
     })
 }
 
@@ -264,33 +484,64 @@ func GetAccountBalances(c *gin.Context) {
 
     userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
 
-    type AccountBalance struct {
-        AccountID        uint   `json:"account_id"`
-        AccountName      string `json:"account_name"`
-        AccountType      string `json:"account_type"`
-        BalanceCents     int64  `json:"balance_cents"`
-        TransactionCount int64  `json:"transaction_count"`
+    var reportCurrency *models.ReportSecurity
+    if symbol := c.Query("report_currency"); symbol != "" {
+        security, err := findSecurity(userID, symbol)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "unknown report_currency security"})
+            return
+        }
+        reportCurrency = security
     }
 
-    var balances []AccountBalance
+    type accountBalanceRow struct {
+        AccountID        uint
+        AccountName      string
+        AccountType      string
+        SecurityID       *uint
+        Balance          decimal.Decimal
+        TransactionCount int64
+    }
+
+    var balanceRows []accountBalanceRow
 
     db.DB.Table("accounts").
         Select(`
-            accounts.id as account_id, 
-            accounts.name as account_name, 
-            accounts.type as account_type, 
-            accounts.current_balance_cents as balance_cents,
+            accounts.id as account_id,
+            accounts.name as account_name,
+            accounts.type as account_type,
+            accounts.security_id as security_id,
+            accounts.current_balance as balance,
             COUNT(transactions.id) as transaction_count
         `).
         Joins("LEFT JOIN transactions ON transactions.account_id = accounts.id").
         Where("accounts.user_id = ?", userID).
-        Group("accounts.id, accounts.name, accounts.type, accounts.current_balance_cents").
+        Group("accounts.id, accounts.name, accounts.type, accounts.security_id, accounts.current_balance").
         Order("account_type, account_name").
-        Scan(&balances)
+        Scan(&balanceRows)
+
+    type AccountBalance struct {
+        AccountID        uint   `json:"account_id"`
+        AccountName      string `json:"account_name"`
+        AccountType      string `json:"account_type"`
+        BalanceCents     int64  `json:"balance_cents"`
+        TransactionCount int64  `json:"transaction_count"`
+    }
 
+    balances := make([]AccountBalance, 0, len(balanceRows))
     var totalBalance int64
-    for _, balance := range balances {
-        totalBalance += balance.BalanceCents
+    now := time.Now()
+    for _, row := range balanceRows {
+        balance := convertAmount(row.Balance, row.SecurityID, now, reportCurrency)
+        balanceCents := centsOf(balance)
+        balances = append(balances, AccountBalance{
+            AccountID:        row.AccountID,
+            AccountName:      row.AccountName,
+            AccountType:      row.AccountType,
+            BalanceCents:     balanceCents,
+            TransactionCount: row.TransactionCount,
+        })
+        totalBalance += balanceCents
     }
 
     c.JSON(http.StatusOK, gin.H{
@@ -299,73 +550,243 @@ func GetAccountBalances(c *gin.Context) {
     })
 }
 
-// GetBudgetProgress shows budget vs actual spending
-func GetBudgetProgress(c *gin.Context) {
+// CashflowForecastPeriod is one future calendar month's projected
+// cashflow, split into what's already posted this month (Actual), what
+// GetRecurringRules' confirmed schedules account for (Scheduled), and
+// what detectRecurringSuggestions' statistical clustering expects
+// (Predicted) - plus a Low/High band sized to the clustering's own amount
+// tolerance, since a predicted series is never as certain as a confirmed
+// recurring rule.
+type CashflowForecastPeriod struct {
+    Period                string `json:"period"`
+    ActualIncomeCents     int64  `json:"actual_income_cents"`
+    ActualExpenseCents    int64  `json:"actual_expense_cents"`
+    ScheduledIncomeCents  int64  `json:"scheduled_income_cents"`
+    ScheduledExpenseCents int64  `json:"scheduled_expense_cents"`
+    PredictedIncomeCents  int64  `json:"predicted_income_cents"`
+    PredictedExpenseCents int64  `json:"predicted_expense_cents"`
+    IncomeCents           int64  `json:"income_cents"`
+    ExpenseCents          int64  `json:"expense_cents"`
+    NetCents              int64  `json:"net_cents"`
+    LowCents              int64  `json:"low_cents"`
+    HighCents             int64  `json:"high_cents"`
+    RunningBalanceCents   int64  `json:"running_balance_cents"`
+}
+
+// GetCashflowForecast projects income/expense/net/running-balance for the
+// `months` calendar months starting with the current one, by combining
+// three sources per period: transactions already posted this month,
+// every confirmed models.RecurringRule evaluated forward through
+// recurring.Rule.Next, and detectRecurringSuggestions' statistically
+// detected patterns projected forward at their own mean interval. It
+// never writes anything - confirming a predicted pattern into a real
+// RecurringRule is DetectRecurringTransactions/CreateRecurringRule's job.
+func GetCashflowForecast(c *gin.Context) {
     claims, exists := c.Get("user")
     if !exists {
         c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
         return
     }
-
     userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
-    budgetIDStr := c.Query("budget_id")
-    
-    var budget models.Budget
 
-    if budgetIDStr != "" {
-        if err := db.DB.
-            Preload("Items.Category").
-            Where("id = ? AND user_id = ?", budgetIDStr, userID).
-            First(&budget).Error; err != nil {
-            c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
-            return
+    months, err := strconv.Atoi(c.DefaultQuery("months", "6"))
+    if err != nil || months <= 0 {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "months must be a positive integer"})
+        return
+    }
+
+    now := time.Now()
+    periodStart := make([]time.Time, months)
+    for i := 0; i < months; i++ {
+        periodStart[i] = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, i, 0)
+    }
+    horizonEnd := periodStart[0].AddDate(0, months, 0)
+
+    periodIndex := func(t time.Time) int {
+        if t.Before(periodStart[0]) || !t.Before(horizonEnd) {
+            return -1
         }
-    } else {
-        now := time.Now()
-        if err := db.DB.
-            Preload("Items.Category").
-            Where("user_id = ? AND period_start <= ? AND period_end >= ?", userID, now, now).
-            First(&budget).Error; err != nil {
-            c.JSON(http.StatusNotFound, gin.H{"error": "no active budget found"})
-            return
+        for i := months - 1; i >= 0; i-- {
+            if !t.Before(periodStart[i]) {
+                return i
+            }
+        }
+        return -1
+    }
+
+    periods := make([]CashflowForecastPeriod, months)
+    for i, start := range periodStart {
+        periods[i].Period = start.Format("2006-01")
+    }
+
+    // Actual: whatever has already posted in the current (first) period.
+    var actualRows []struct {
+        Amount  decimal.Decimal
+        TxnDate time.Time
+    }
+    db.DB.Model(&models.Transaction{}).
+        Select("amount, txn_date").
+        Where("user_id = ? AND txn_date >= ? AND txn_date <= ?", userID, periodStart[0], now).
+        Scan(&actualRows)
+    for _, row := range actualRows {
+        if row.Amount.IsPositive() {
+            periods[0].ActualIncomeCents += centsOf(row.Amount)
+        } else {
+            periods[0].ActualExpenseCents += centsOf(row.Amount.Abs())
+        }
+    }
+
+    // Scheduled: every confirmed RecurringRule, walked forward from its
+    // own NextRun through the forecast horizon.
+    var rules []models.RecurringRule
+    db.DB.Where("user_id = ?", userID).Find(&rules)
+    for _, rule := range rules {
+        parsed, err := recurring.Parse(rule.RRule)
+        if err != nil {
+            continue
+        }
+        occurrence := rule.NextRun
+        count := 0
+        for occurrence.Before(horizonEnd) {
+            if rule.EndDate != nil && occurrence.After(*rule.EndDate) {
+                break
+            }
+            if parsed.Count != 0 && count >= parsed.Count {
+                break
+            }
+            if idx := periodIndex(occurrence); idx >= 0 {
+                if rule.AmountCents >= 0 {
+                    periods[idx].ScheduledIncomeCents += rule.AmountCents
+                } else {
+                    periods[idx].ScheduledExpenseCents += -rule.AmountCents
+                }
+            }
+            count++
+            occurrence = parsed.Next(occurrence)
+        }
+    }
+
+    // Predicted: statistically detected patterns, projected forward from
+    // their last observed occurrence at their own mean interval.
+    for _, suggestion := range detectRecurringSuggestions(userID, now.AddDate(0, -12, 0)) {
+        step := int(math.Round(suggestion.IntervalDays))
+        if step <= 0 {
+            continue
+        }
+        for occurrence := suggestion.NextDueAt; occurrence.Before(horizonEnd); occurrence = occurrence.AddDate(0, 0, step) {
+            idx := periodIndex(occurrence)
+            if idx < 0 {
+                continue
+            }
+            if suggestion.AmountCents >= 0 {
+                periods[idx].PredictedIncomeCents += suggestion.AmountCents
+            } else {
+                periods[idx].PredictedExpenseCents += -suggestion.AmountCents
+            }
+        }
+    }
+
+    startingBalance, err := resolveForecastStartingBalance(c, userID)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    runningBalance := startingBalance
+    for i := range periods {
+        income := periods[i].ActualIncomeCents + periods[i].ScheduledIncomeCents + periods[i].PredictedIncomeCents
+        expense := periods[i].ActualExpenseCents + periods[i].ScheduledExpenseCents + periods[i].PredictedExpenseCents
+        net := income - expense
+        band := int64(float64(periods[i].PredictedIncomeCents+periods[i].PredictedExpenseCents) * recurringAmountTolerance)
+
+        periods[i].IncomeCents = income
+        periods[i].ExpenseCents = expense
+        periods[i].NetCents = net
+        periods[i].LowCents = net - band
+        periods[i].HighCents = net + band
+
+        runningBalance += net
+        periods[i].RunningBalanceCents = runningBalance
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "starting_balance_cents": startingBalance,
+        "periods":                periods,
+    })
+}
+
+// resolveForecastStartingBalance honours an explicit
+// ?starting_balance_cents= override, falling back to the user's current
+// total balance across all accounts (the same figure GetAccountBalances
+// reports).
+func resolveForecastStartingBalance(c *gin.Context, userID uint) (int64, error) {
+    if raw := c.Query("starting_balance_cents"); raw != "" {
+        cents, err := strconv.ParseInt(raw, 10, 64)
+        if err != nil {
+            return 0, fmt.Errorf("invalid starting_balance_cents")
         }
+        return cents, nil
     }
 
-    type CategoryProgress struct {
-        CategoryID     uint    `json:"category_id"`
-        CategoryName   string  `json:"category_name"`
-        PlannedCents   int64   `json:"planned_cents"`
-        SpentCents     int64   `json:"spent_cents"`
-        RemainingCents int64   `json:"remaining_cents"`
-        Progress       float64 `json:"progress_percent"`
-        Status         string  `json:"status"`
+    var total decimal.NullDecimal
+    db.DB.Model(&models.Account{}).Where("user_id = ?", userID).Select("COALESCE(SUM(current_balance), 0)").Scan(&total)
+    if !total.Valid {
+        return 0, nil
     }
+    return centsOf(total.Decimal), nil
+}
 
+// CategoryProgress is one budget item's planned-vs-actual spend.
+type CategoryProgress struct {
+    CategoryID     uint    `json:"category_id"`
+    CategoryName   string  `json:"category_name"`
+    PlannedCents   int64   `json:"planned_cents"`
+    SpentCents     int64   `json:"spent_cents"`
+    RemainingCents int64   `json:"remaining_cents"`
+    Progress       float64 `json:"progress_percent"`
+    Status         string  `json:"status"`
+}
+
+// BudgetProgressSummary is computeBudgetProgress's result.
+type BudgetProgressSummary struct {
+    Budget            models.Budget
+    DaysRemaining     int
+    TotalPlannedCents int64
+    TotalSpentCents   int64
+    Categories        []CategoryProgress
+}
+
+// computeBudgetProgress builds the planned-vs-actual breakdown
+// GetBudgetProgress serves and controllers.StartDigestScheduler's
+// over-budget alerts/exhaustion projection reuse, for an already-loaded
+// budget (with its Items.Category preloaded).
+func computeBudgetProgress(userID uint, budget models.Budget) BudgetProgressSummary {
     var categoryProgress []CategoryProgress
     var totalPlanned, totalSpent int64
 
     for _, item := range budget.Items {
-        var spentCents int64
+        var spent decimal.NullDecimal
         db.DB.Model(&models.Transaction{}).
-            Where("user_id = ? AND category_id = ? AND txn_date >= ? AND txn_date <= ? AND amount_cents < 0",
+            Where("user_id = ? AND category_id = ? AND txn_date >= ? AND txn_date <= ? AND amount < 0",
                 userID, item.CategoryID, budget.PeriodStart, budget.PeriodEnd).
-            Select("COALESCE(SUM(ABS(amount_cents)), 0)").
-            Scan(&spentCents)
+            Select("COALESCE(SUM(ABS(amount)), 0)").
+            Scan(&spent)
 
-        var splitSpent int64
+        var splitSpent decimal.NullDecimal
         db.DB.Table("transaction_splits").
             Joins("JOIN transactions ON transactions.id = transaction_splits.parent_txn_id").
             Where("transactions.user_id = ? AND transaction_splits.category_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ?",
                 userID, item.CategoryID, budget.PeriodStart, budget.PeriodEnd).
-            Select("COALESCE(SUM(ABS(transaction_splits.amount_cents)), 0)").
+            Select("COALESCE(SUM(ABS(transaction_splits.amount)), 0)").
             Scan(&splitSpent)
 
-        spentCents += splitSpent
+        spentCents := centsOf(spent.Decimal) + centsOf(splitSpent.Decimal)
+        plannedCents := centsOf(item.PlannedAmount)
 
-        remaining := item.PlannedCents - spentCents
+        remaining := plannedCents - spentCents
         progress := 0.0
-        if item.PlannedCents > 0 {
-            progress = (float64(spentCents) / float64(item.PlannedCents)) * 100
+        if plannedCents > 0 {
+            progress = (float64(spentCents) / float64(plannedCents)) * 100
         }
 
         status := "under_budget"
@@ -378,14 +799,14 @@ func GetBudgetProgress(c *gin.Context) {
         categoryProgress = append(categoryProgress, CategoryProgress{
             CategoryID:     item.CategoryID,
             CategoryName:   item.Category.Name,
-            PlannedCents:   item.PlannedCents,
+            PlannedCents:   plannedCents,
             SpentCents:     spentCents,
             RemainingCents: remaining,
             Progress:       progress,
             Status:         status,
         })
 
-        totalPlanned += item.PlannedCents
+        totalPlanned += plannedCents
         totalSpent += spentCents
     }
 
@@ -395,20 +816,63 @@ func GetBudgetProgress(c *gin.Context) {
         daysRemaining = 0
     }
 
+    return BudgetProgressSummary{
+        Budget:            budget,
+        DaysRemaining:     daysRemaining,
+        TotalPlannedCents: totalPlanned,
+        TotalSpentCents:   totalSpent,
+        Categories:        categoryProgress,
+    }
+}
+
+// GetBudgetProgress shows budget vs actual spending
+func GetBudgetProgress(c *gin.Context) {
+    claims, exists := c.Get("user")
+    if !exists {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+        return
+    }
+
+    userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+    budgetIDStr := c.Query("budget_id")
+
+    var budget models.Budget
+
+    if budgetIDStr != "" {
+        if err := db.DB.
+            Preload("Items.Category").
+            Where("id = ? AND user_id = ?", budgetIDStr, userID).
+            First(&budget).Error; err != nil {
+            c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+            return
+        }
+    } else {
+        now := time.Now()
+        if err := db.DB.
+            Preload("Items.Category").
+            Where("user_id = ? AND period_start <= ? AND period_end >= ?", userID, now, now).
+            First(&budget).Error; err != nil {
+            c.JSON(http.StatusNotFound, gin.H{"error": "no active budget found"})
+            return
+        }
+    }
+
+    summary := computeBudgetProgress(userID, budget)
+
     c.JSON(http.StatusOK, gin.H{
         "budget": gin.H{
-            "id":             budget.ID,
-            "period_start":   budget.PeriodStart.Format("2006-01-02"),
-            "period_end":     budget.PeriodEnd.Format("2006-01-02"),
-            "days_remaining": daysRemaining,
+            "id":             summary.Budget.ID,
+            "period_start":   summary.Budget.PeriodStart.Format("2006-01-02"),
+            "period_end":     summary.Budget.PeriodEnd.Format("2006-01-02"),
+            "days_remaining": summary.DaysRemaining,
         },
         "summary": gin.H{
-            "total_planned_cents":   totalPlanned,
-            "total_spent_cents":     totalSpent,
-            "total_remaining_cents": totalPlanned - totalSpent,
-            "overall_progress":      (float64(totalSpent) / float64(totalPlanned)) * 100,
+            "total_planned_cents":   summary.TotalPlannedCents,
+            "total_spent_cents":     summary.TotalSpentCents,
+            "total_remaining_cents": summary.TotalPlannedCents - summary.TotalSpentCents,
+            "overall_progress":      (float64(summary.TotalSpentCents) / float64(summary.TotalPlannedCents)) * 100,
         },
-        "categories": categoryProgress,
+        "categories": summary.Categories,
     })
 }
 
@@ -427,50 +891,83 @@ func GetMonthlyTrends(c *gin.Context) {
         months = m
     }
 
-    type MonthlyData struct {
-        Month        string  `json:"month"`
-        IncomeCents  int64   `json:"income_cents"`
-        ExpenseCents int64   `json:"expense_cents"`
-        NetCents     int64   `json:"net_cents"`
-        SavingsRate  float64 `json:"savings_rate_percent"`
+    // A `period=` preset (see resolvePeriodPreset) bounds the trend to that
+    // exact range instead of the rolling "last N months" window `months`
+    // gives, so the front-end can ask for "this quarter"'s trend without
+    // computing dates itself.
+    var toDate *time.Time
+    cutoffDate := time.Now().AddDate(0, -months, 0)
+    if period := c.Query("period"); period != "" {
+        presetFrom, presetTo, err := resolvePeriodPreset(period, time.Now())
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+        cutoffDate = presetFrom
+        toDate = &presetTo
+    }
+
+    type monthlyRow struct {
+        Month   string
+        Income  decimal.Decimal
+        Expense decimal.Decimal
+        Net     decimal.Decimal
     }
 
-    var trends []MonthlyData
+    var rows []monthlyRow
 
     // ✅ Detect database driver
     dbDriver := db.DB.Dialector.Name()
-    cutoffDate := time.Now().AddDate(0, -months, 0)
+
+    query := db.DB.Table("transactions").Where("user_id = ? AND txn_date >= ?", userID, cutoffDate)
+    if toDate != nil {
+        query = query.Where("txn_date <= ?", *toDate)
+    }
 
     if dbDriver == "sqlite" {
-        db.DB.Raw(`
-            SELECT 
+        query.
+            Select(`
                 STRFTIME('%Y-%m', txn_date) as month,
-                COALESCE(SUM(CASE WHEN amount_cents > 0 THEN amount_cents ELSE 0 END), 0) as income_cents,
-                COALESCE(SUM(CASE WHEN amount_cents < 0 THEN ABS(amount_cents) ELSE 0 END), 0) as expense_cents,
-                COALESCE(SUM(amount_cents), 0) as net_cents
-            FROM transactions
-            WHERE user_id = ? AND txn_date >= ?
-            GROUP BY STRFTIME('%Y-%m', txn_date)
-            ORDER BY month ASC
-        `, userID, cutoffDate.Format("2006-01-02")).Scan(&trends)
+                COALESCE(SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END), 0) as income,
+                COALESCE(SUM(CASE WHEN amount < 0 THEN ABS(amount) ELSE 0 END), 0) as expense,
+                COALESCE(SUM(amount), 0) as net
+            `).
+            Group("STRFTIME('%Y-%m', txn_date)").
+            Order("month ASC").
+            Scan(&rows)
     } else {
-        db.DB.Raw(`
-            SELECT 
+        query.
+            Select(`
                 TO_CHAR(DATE_TRUNC('month', txn_date), 'YYYY-MM') as month,
-                COALESCE(SUM(CASE WHEN amount_cents > 0 THEN amount_cents ELSE 0 END), 0) as income_cents,
-                COALESCE(SUM(CASE WHEN amount_cents < 0 THEN ABS(amount_cents) ELSE 0 END), 0) as expense_cents,
-                COALESCE(SUM(amount_cents), 0) as net_cents
-            FROM transactions
-            WHERE user_id = ? AND txn_date >= ?
-            GROUP BY DATE_TRUNC('month', txn_date)
-            ORDER BY month ASC
-        `, userID, cutoffDate).Scan(&trends)
-    }
-
-    for i := range trends {
-        if trends[i].IncomeCents > 0 {
-            trends[i].SavingsRate = (float64(trends[i].NetCents) / float64(trends[i].IncomeCents)) * 100
+                COALESCE(SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END), 0) as income,
+                COALESCE(SUM(CASE WHEN amount < 0 THEN ABS(amount) ELSE 0 END), 0) as expense,
+                COALESCE(SUM(amount), 0) as net
+            `).
+            Group("DATE_TRUNC('month', txn_date)").
+            Order("month ASC").
+            Scan(&rows)
+    }
+
+    type MonthlyData struct {
+        Month        string  `json:"month"`
+        IncomeCents  int64   `json:"income_cents"`
+        ExpenseCents int64   `json:"expense_cents"`
+        NetCents     int64   `json:"net_cents"`
+        SavingsRate  float64 `json:"savings_rate_percent"`
+    }
+
+    trends := make([]MonthlyData, 0, len(rows))
+    for _, row := range rows {
+        data := MonthlyData{
+            Month:        row.Month,
+            IncomeCents:  centsOf(row.Income),
+            ExpenseCents: centsOf(row.Expense),
+            NetCents:     centsOf(row.Net),
+        }
+        if data.IncomeCents > 0 {
+            data.SavingsRate = (float64(data.NetCents) / float64(data.IncomeCents)) * 100
         }
+        trends = append(trends, data)
     }
 
     c.JSON(http.StatusOK, gin.H{
@@ -479,7 +976,12 @@ func GetMonthlyTrends(c *gin.Context) {
     })
 }
 
-// GetTopMerchants shows most frequent transaction descriptions
+// GetTopMerchants shows the biggest spend by merchant: transactions with a
+// MerchantID group under that Merchant's CanonicalName instead of their own
+// raw, differently-formatted Description (see MatchMerchant), so "AMZN
+// Mktp US*A1B2C3" and "Amazon.com" on the same merchant report as one line.
+// Transactions no merchant pattern matched still group by their raw
+// description, same as before merchant normalization existed.
 func GetTopMerchants(c *gin.Context) {
     claims, exists := c.Get("user")
     if !exists {
@@ -489,28 +991,70 @@ func GetTopMerchants(c *gin.Context) {
 
     userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
     limit := c.DefaultQuery("limit", "10")
+    ungrouped := c.Query("ungrouped") == "true"
+
+    type merchantRow struct {
+        MerchantID  *uint
+        Description string
+        Total       decimal.Decimal
+        Count       int64
+        Avg         decimal.Decimal
+    }
+
+    var rows []merchantRow
+
+    if ungrouped {
+        // Raw mode: group by the literal Description, ignoring any merchant
+        // clustering - lets a caller see what MatchMerchant's normalization
+        // and fuzzy clustering collapsed together.
+        db.DB.Raw(`
+            SELECT
+                NULL as merchant_id,
+                t.description as description,
+                SUM(ABS(t.amount)) as total,
+                COUNT(*) as count,
+                AVG(ABS(t.amount)) as avg
+            FROM transactions t
+            WHERE t.user_id = ? AND t.amount < 0 AND t.description != ''
+            GROUP BY t.description
+            ORDER BY total DESC
+            LIMIT ?
+        `, userID, limit).Scan(&rows)
+    } else {
+        db.DB.Raw(`
+            SELECT
+                t.merchant_id as merchant_id,
+                COALESCE(m.canonical_name, t.description) as description,
+                SUM(ABS(t.amount)) as total,
+                COUNT(*) as count,
+                AVG(ABS(t.amount)) as avg
+            FROM transactions t
+            LEFT JOIN merchants m ON m.id = t.merchant_id
+            WHERE t.user_id = ? AND t.amount < 0 AND t.description != ''
+            GROUP BY t.merchant_id, COALESCE(m.canonical_name, t.description)
+            ORDER BY total DESC
+            LIMIT ?
+        `, userID, limit).Scan(&rows)
+    }
 
     type MerchantSpend struct {
+        MerchantID  *uint  `json:"merchant_id,omitempty"`
         Description string `json:"description"`
         TotalCents  int64  `json:"total_cents"`
         Count       int64  `json:"transaction_count"`
         AvgCents    int64  `json:"avg_cents"`
     }
 
-    var merchants []MerchantSpend
-
-    db.DB.Raw(`
-        SELECT 
-            description,
-            SUM(ABS(amount_cents)) as total_cents,
-            COUNT(*) as count,
-            AVG(ABS(amount_cents)) as avg_cents
-        FROM transactions
-        WHERE user_id = ? AND amount_cents < 0 AND description != ''
-        GROUP BY description
-        ORDER BY total_cents DESC
-        LIMIT ?
-    `, userID, limit).Scan(&merchants)
+    merchants := make([]MerchantSpend, 0, len(rows))
+    for _, row := range rows {
+        merchants = append(merchants, MerchantSpend{
+            MerchantID:  row.MerchantID,
+            Description: row.Description,
+            TotalCents:  centsOf(row.Total),
+            Count:       row.Count,
+            AvgCents:    centsOf(row.Avg),
+        })
+    }
 
     c.JSON(http.StatusOK, gin.H{
         "top_merchants": merchants,
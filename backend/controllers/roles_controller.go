@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"net/http"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRoles returns every assignable role together with its currently
+// granted permissions (requires perm.role.manage, granted to SuperAdmin by
+// default).
+func GetRoles(c *gin.Context) {
+	var grants []models.RolePermission
+	if err := db.DB.Find(&grants).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch role permissions"})
+		return
+	}
+
+	byRole := make(map[models.UserRole][]models.Permission)
+	for _, g := range grants {
+		byRole[g.Role] = append(byRole[g.Role], g.Permission)
+	}
+
+	roles := make([]gin.H, 0, len(models.AllRoles))
+	for _, role := range models.AllRoles {
+		roles = append(roles, gin.H{
+			"role":        role,
+			"permissions": byRole[role],
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+// UpdateRolePermissions replaces the full set of permissions granted to a
+// role. SuperAdmin's grants can't be edited here - it has every permission
+// by definition of being the top tier.
+func UpdateRolePermissions(c *gin.Context) {
+	role := models.UserRole(c.Param("name"))
+	if !isAssignableRole(role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role"})
+		return
+	}
+	if role == models.UserRoleSuperAdmin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SuperAdmin permissions cannot be edited"})
+		return
+	}
+
+	var input struct {
+		Permissions []models.Permission `json:"permissions" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	seen := make(map[models.Permission]bool, len(input.Permissions))
+	for _, perm := range input.Permissions {
+		if !isKnownPermission(perm) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid permission: " + string(perm)})
+			return
+		}
+		if seen[perm] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "duplicate permission: " + string(perm)})
+			return
+		}
+		seen[perm] = true
+	}
+
+	tx := db.DB.Begin()
+	if err := tx.Where("role = ?", role).Delete(&models.RolePermission{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update role permissions"})
+		return
+	}
+	for _, perm := range input.Permissions {
+		if err := tx.Create(&models.RolePermission{Role: role, Permission: perm}).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update role permissions"})
+			return
+		}
+	}
+	// Bump the role's version so every already-issued token's perm_ver
+	// claim, and middleware's in-process permission cache, are stale as of
+	// this change rather than silently serving the old grant set for up to
+	// a minute.
+	if err := models.BumpRoleVersion(tx, role); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update role permissions"})
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update role permissions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"role": role, "permissions": input.Permissions})
+}
+
+func isAssignableRole(role models.UserRole) bool {
+	for _, r := range models.AllRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func isKnownPermission(perm models.Permission) bool {
+	for _, p := range models.AllPermissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+)
+
+// GetSecurities lists the authenticated user's currencies and investment
+// holdings.
+func GetSecurities(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var securities []models.ReportSecurity
+	if err := db.DB.Where("user_id = ?", userID).Order("symbol").Find(&securities).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch securities"})
+		return
+	}
+
+	c.JSON(http.StatusOK, securities)
+}
+
+// CreateSecurity registers a new currency or investment holding the
+// authenticated user can then attach to an Account/Transaction and import
+// Price history for via ImportSecurityPrices.
+func CreateSecurity(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input struct {
+		Name      string              `json:"name" binding:"required"`
+		Symbol    string              `json:"symbol" binding:"required"`
+		Type      models.SecurityType `json:"type"`
+		Precision int                 `json:"precision"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Type == "" {
+		input.Type = models.SecurityCurrency
+	}
+	if input.Precision == 0 {
+		input.Precision = 2
+	}
+
+	security := models.ReportSecurity{
+		UserID:    userID,
+		Name:      input.Name,
+		Symbol:    input.Symbol,
+		Type:      input.Type,
+		Precision: input.Precision,
+	}
+	if err := db.DB.Create(&security).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create security, symbol may already exist"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, security)
+}
+
+// GetSecurityPrices lists one of the authenticated user's Securities'
+// price history, optionally bounded by ?from=&to= (YYYY-MM-DD).
+func GetSecurityPrices(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var security models.ReportSecurity
+	if err := db.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&security).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "security not found"})
+		return
+	}
+
+	query := db.DB.Where("security_id = ?", security.ID)
+	if from := c.Query("from"); from != "" {
+		query = query.Where("date >= ?", from)
+	}
+	if to := c.Query("to"); to != "" {
+		query = query.Where("date <= ?", to)
+	}
+
+	var prices []models.Price
+	if err := query.Order("date ASC").Find(&prices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch prices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
+
+// ImportSecurityPrices seeds one of the authenticated user's Securities'
+// historical FX/quote rates from an uploaded CSV with a "date,currency,
+// value" header - e.g. importing EUR's USD history to convert EUR
+// transactions into a USD report_currency. Rows with a currency symbol the
+// user hasn't created as a Security yet are reported back rather than
+// aborting the rest of the import.
+func ImportSecurityPrices(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var security models.ReportSecurity
+	if err := db.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&security).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "security not found"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing upload file"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to open upload"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read upload"})
+		return
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	rows, err := reader.ReadAll()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to parse CSV"})
+		return
+	}
+	if len(rows) > 0 {
+		rows = rows[1:] // header: date,currency,value
+	}
+
+	type importResult struct {
+		Imported int      `json:"imported"`
+		Errors   []string `json:"errors,omitempty"`
+	}
+	result := importResult{}
+
+	for i, row := range rows {
+		if len(row) < 3 {
+			result.Errors = append(result.Errors, "row "+strconv.Itoa(i+2)+": expected date,currency,value")
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", row[0])
+		if err != nil {
+			result.Errors = append(result.Errors, "row "+strconv.Itoa(i+2)+": invalid date "+row[0])
+			continue
+		}
+
+		currency, err := findSecurity(userID, row[1])
+		if err != nil {
+			result.Errors = append(result.Errors, "row "+strconv.Itoa(i+2)+": unknown currency security "+row[1])
+			continue
+		}
+
+		value, err := decimal.NewFromString(row[2])
+		if err != nil {
+			result.Errors = append(result.Errors, "row "+strconv.Itoa(i+2)+": invalid value "+row[2])
+			continue
+		}
+
+		price := models.Price{
+			SecurityID: security.ID,
+			CurrencyID: currency.ID,
+			Date:       date,
+			Value:      value,
+		}
+		if err := db.DB.Where("security_id = ? AND currency_id = ? AND date = ?", price.SecurityID, price.CurrencyID, price.Date).
+			Assign(price).FirstOrCreate(&price).Error; err != nil {
+			result.Errors = append(result.Errors, "row "+strconv.Itoa(i+2)+": "+err.Error())
+			continue
+		}
+		result.Imported++
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// RefreshSession exchanges a still-valid refresh token for a new
+// access/refresh pair, rotating the refresh token in the process. A token
+// that was already rotated away from being presented again is treated as
+// reuse (see session_store.go) and invalidates every session in its family.
+func RefreshSession(c *gin.Context) {
+	var input struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, newRefreshToken, err := rotateSession(input.RefreshToken, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		if err == ErrRefreshTokenReused {
+			utils.Logger.Warn("Refresh token reuse detected, session family revoked",
+				zap.String("ip", c.ClientIP()),
+			)
+			middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+				EventType: "refresh",
+				Outcome:   "failure",
+				Details:   map[string]interface{}{"reason": "refresh token reuse detected"},
+				IP:        c.ClientIP(),
+				UserAgent: c.Request.UserAgent(),
+			})
+			middleware.IncrementTokenOperation("refresh", "failure")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "session reuse detected, please log in again"})
+			return
+		}
+		middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+			EventType: "refresh",
+			Outcome:   "failure",
+			Details:   map[string]interface{}{"reason": "invalid or expired refresh token"},
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		middleware.IncrementTokenOperation("refresh", "failure")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired refresh token"})
+		return
+	}
+
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired refresh token"})
+		return
+	}
+
+	accessToken, err := GenerateToken(user.ID, user.Username, string(user.Role))
+	if err != nil {
+		utils.Logger.Error("Failed to generate JWT token during refresh", zap.Error(err), zap.Uint("user_id", user.ID))
+		middleware.IncrementTokenOperation("refresh", "failure")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
+		return
+	}
+
+	middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+		UserID:    user.ID,
+		EventType: "refresh",
+		Outcome:   "success",
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	middleware.IncrementTokenOperation("refresh", "success")
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":         accessToken,
+		"refresh_token": newRefreshToken,
+	})
+}
+
+// Logout deletes the presented refresh token's session, ending that one
+// device/session. The access token used to call it is left to expire on
+// its own - it's short-lived enough (see accessTokenTTL) not to matter.
+func Logout(c *gin.Context) {
+	var input struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, found := endSession(input.RefreshToken)
+	if found {
+		middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+			UserID:    userID,
+			EventType: "logout",
+			Outcome:   "success",
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
+// LogoutAll ends every session (every refresh-token family) belonging to
+// the caller, e.g. "log out everywhere".
+func LogoutAll(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	endAllSessions(userID)
+	middleware.RecordAuthAuditEvent(middleware.AuthAuditEventInput{
+		UserID:    userID,
+		EventType: "logout",
+		Outcome:   "success",
+		Details:   map[string]interface{}{"scope": "all_sessions"},
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "logged out of all sessions"})
+}
+
+// sessionView is one row of GetSessions' response.
+type sessionView struct {
+	IssuedAt   time.Time `json:"issued_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+}
+
+// GetSessions lists the caller's currently active sessions (one per
+// refresh-token family) with the ip/user-agent they were last used from.
+func GetSessions(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	records := listSessions(userID)
+	sessions := make([]sessionView, 0, len(records))
+	for _, r := range records {
+		sessions = append(sessions, sessionView{
+			IssuedAt:   r.IssuedAt,
+			LastUsedAt: r.LastUsedAt,
+			IP:         r.IP,
+			UserAgent:  r.UA,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
@@ -0,0 +1,387 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/redis"
+)
+
+// refreshTokenTTL bounds how long a refresh token (and the session it
+// represents) can sit unused before it's no longer accepted by /auth/refresh.
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+// ErrInvalidRefreshToken is returned by rotateSession for an unknown,
+// expired, or already-logged-out refresh token.
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// ErrRefreshTokenReused is returned by rotateSession when a refresh token
+// that was already rotated away from is presented again - RFC 6819 replay
+// detection. The token's whole family has already been invalidated by the
+// time this is returned, so the caller only needs to report the error.
+var ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+
+// sessionRecord is what a single refresh token resolves to. It's kept even
+// after the token is rotated away from (Used becomes true) so a replayed
+// token can be recognized as reuse rather than simply "not found".
+type sessionRecord struct {
+	UserID     uint      `json:"user_id"`
+	FamilyID   string    `json:"family_id"`
+	IssuedAt   time.Time `json:"issued_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	IP         string    `json:"ip"`
+	UA         string    `json:"ua"`
+	Used       bool      `json:"used"`
+}
+
+// sessionStore is the pluggable backend refresh-token sessions are kept in,
+// mirroring bucketStore/tokenBlacklistStore: in-memory by default,
+// Redis-backed when configured so sessions survive a restart and are shared
+// across replicas.
+type sessionStore interface {
+	// put stores record under tokenHash, valid for refreshTokenTTL, and
+	// marks it the active token for its family.
+	put(tokenHash string, record sessionRecord)
+	// get returns the record stored under tokenHash, if any.
+	get(tokenHash string) (sessionRecord, bool)
+	// markUsed flips a record's Used flag without changing its TTL, so a
+	// later replay of the same token is still recognized (not just expired).
+	markUsed(tokenHash string)
+	// activeHash returns the tokenHash currently considered live for
+	// familyID, i.e. the one /auth/refresh or GET /auth/sessions should
+	// trust; stale/rotated-out hashes never match.
+	activeHash(familyID string) (string, bool)
+	// familiesForUser lists every family_id a user has an active or
+	// recently-active session under.
+	familiesForUser(userID uint) []string
+	// addFamily records familyID under userID's family set.
+	addFamily(userID uint, familyID string)
+	// dropFamily removes familyID (and its active-hash pointer) entirely -
+	// used by logout and by reuse-detection.
+	dropFamily(userID uint, familyID string)
+}
+
+type memorySessionEntry struct {
+	record sessionRecord
+	expiry time.Time
+}
+
+type memorySessionStore struct {
+	mu            sync.Mutex
+	records       map[string]memorySessionEntry // tokenHash -> entry
+	activeByFam   map[string]string             // familyID -> active tokenHash
+	familyExpiry  map[string]time.Time          // familyID -> expiry
+	familiesByUID map[uint]map[string]bool      // userID -> set of familyID
+}
+
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{
+		records:       make(map[string]memorySessionEntry),
+		activeByFam:   make(map[string]string),
+		familyExpiry:  make(map[string]time.Time),
+		familiesByUID: make(map[uint]map[string]bool),
+	}
+}
+
+func (s *memorySessionStore) put(tokenHash string, record sessionRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiry := time.Now().Add(refreshTokenTTL)
+	s.records[tokenHash] = memorySessionEntry{record: record, expiry: expiry}
+	s.activeByFam[record.FamilyID] = tokenHash
+	s.familyExpiry[record.FamilyID] = expiry
+}
+
+func (s *memorySessionStore) get(tokenHash string) (sessionRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.records[tokenHash]
+	if !ok {
+		return sessionRecord{}, false
+	}
+	if time.Now().After(entry.expiry) {
+		delete(s.records, tokenHash)
+		return sessionRecord{}, false
+	}
+	return entry.record, true
+}
+
+func (s *memorySessionStore) markUsed(tokenHash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.records[tokenHash]
+	if !ok {
+		return
+	}
+	entry.record.Used = true
+	s.records[tokenHash] = entry
+}
+
+func (s *memorySessionStore) activeHash(familyID string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiry, ok := s.familyExpiry[familyID]
+	if !ok || time.Now().After(expiry) {
+		delete(s.activeByFam, familyID)
+		delete(s.familyExpiry, familyID)
+		return "", false
+	}
+	hash, ok := s.activeByFam[familyID]
+	return hash, ok
+}
+
+func (s *memorySessionStore) familiesForUser(userID uint) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fams := make([]string, 0, len(s.familiesByUID[userID]))
+	for fam := range s.familiesByUID[userID] {
+		fams = append(fams, fam)
+	}
+	return fams
+}
+
+func (s *memorySessionStore) addFamily(userID uint, familyID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.familiesByUID[userID] == nil {
+		s.familiesByUID[userID] = make(map[string]bool)
+	}
+	s.familiesByUID[userID][familyID] = true
+}
+
+func (s *memorySessionStore) dropFamily(userID uint, familyID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.activeByFam, familyID)
+	delete(s.familyExpiry, familyID)
+	delete(s.familiesByUID[userID], familyID)
+}
+
+var defaultSessionStore = newMemorySessionStore()
+
+// redisSessionStore keeps the same three key families the doc comment on
+// sessionStore describes: refresh:<hash> for the per-token record,
+// family_active:<family_id> for the currently-valid token of a family, and
+// user_families:<user_id> for the set of families a user can list/revoke.
+type redisSessionStore struct{}
+
+func (redisSessionStore) put(tokenHash string, record sessionRecord) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	redis.RDB.Set(ctx, "refresh:"+tokenHash, data, refreshTokenTTL)
+	redis.RDB.Set(ctx, "family_active:"+record.FamilyID, tokenHash, refreshTokenTTL)
+}
+
+func (redisSessionStore) get(tokenHash string) (sessionRecord, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	data, err := redis.RDB.Get(ctx, "refresh:"+tokenHash).Bytes()
+	if err != nil {
+		return sessionRecord{}, false
+	}
+	var record sessionRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return sessionRecord{}, false
+	}
+	return record, true
+}
+
+func (redisSessionStore) markUsed(tokenHash string) {
+	record, ok := redisSessionStore{}.get(tokenHash)
+	if !ok {
+		return
+	}
+	record.Used = true
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	ttl := redis.RDB.TTL(ctx, "refresh:"+tokenHash).Val()
+	if ttl <= 0 {
+		ttl = refreshTokenTTL
+	}
+	redis.RDB.Set(ctx, "refresh:"+tokenHash, data, ttl)
+}
+
+func (redisSessionStore) activeHash(familyID string) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	hash, err := redis.RDB.Get(ctx, "family_active:"+familyID).Result()
+	if err != nil {
+		return "", false
+	}
+	return hash, true
+}
+
+func (redisSessionStore) familiesForUser(userID uint) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	fams, err := redis.RDB.SMembers(ctx, "user_families:"+strconv.FormatUint(uint64(userID), 10)).Result()
+	if err != nil {
+		return nil
+	}
+	return fams
+}
+
+func (redisSessionStore) addFamily(userID uint, familyID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	key := "user_families:" + strconv.FormatUint(uint64(userID), 10)
+	redis.RDB.SAdd(ctx, key, familyID)
+	redis.RDB.Expire(ctx, key, refreshTokenTTL)
+}
+
+func (redisSessionStore) dropFamily(userID uint, familyID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	redis.RDB.Del(ctx, "family_active:"+familyID)
+	redis.RDB.SRem(ctx, "user_families:"+strconv.FormatUint(uint64(userID), 10), familyID)
+}
+
+func activeSessionStore() sessionStore {
+	if redis.RDB != nil {
+		return redisSessionStore{}
+	}
+	return defaultSessionStore
+}
+
+// newOpaqueToken returns a URL-safe random refresh token: 32 random bytes,
+// base64-encoded. It carries no claims - it's just a lookup key into
+// sessionStore, so stealing the DB doesn't also require breaking a JWT.
+func newOpaqueToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// hashRefreshToken is the key a refresh token is stored/looked-up under, so
+// the raw token itself is never persisted.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newFamilyID identifies one refresh-token lineage (one login session,
+// renewed by rotation) independent of the individual tokens issued within
+// it - reuse detection and logout-all operate on families.
+func newFamilyID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// issueSession starts a brand new refresh-token family for userID (e.g. on
+// login) and returns the opaque refresh token to hand to the client.
+func issueSession(userID uint, ip, ua string) string {
+	token := newOpaqueToken()
+	familyID := newFamilyID()
+	now := time.Now()
+	store := activeSessionStore()
+	store.put(hashRefreshToken(token), sessionRecord{
+		UserID:     userID,
+		FamilyID:   familyID,
+		IssuedAt:   now,
+		LastUsedAt: now,
+		IP:         ip,
+		UA:         ua,
+	})
+	store.addFamily(userID, familyID)
+	return token
+}
+
+// rotateSession redeems a presented refresh token for a new one in the same
+// family. It returns the new token, or an error if the token is unknown, or
+// ErrRefreshTokenReused if the token had already been rotated away from
+// (RFC 6819 reuse detection) - in which case the entire family has already
+// been invalidated by the time this returns.
+func rotateSession(presented string, ip, ua string) (uint, string, error) {
+	store := activeSessionStore()
+	hash := hashRefreshToken(presented)
+	record, ok := store.get(hash)
+	if !ok {
+		return 0, "", ErrInvalidRefreshToken
+	}
+
+	active, _ := store.activeHash(record.FamilyID)
+	if active != hash {
+		// Either a rotated-out token being replayed, or a family that's
+		// already been logged out. Only the former is an attack, but both
+		// are handled the same way: the family is dead either way, so
+		// invalidating it again is a safe no-op for the logout case.
+		store.dropFamily(record.UserID, record.FamilyID)
+		if record.Used {
+			return 0, "", ErrRefreshTokenReused
+		}
+		return 0, "", ErrInvalidRefreshToken
+	}
+
+	store.markUsed(hash)
+
+	newToken := newOpaqueToken()
+	store.put(hashRefreshToken(newToken), sessionRecord{
+		UserID:     record.UserID,
+		FamilyID:   record.FamilyID,
+		IssuedAt:   record.IssuedAt,
+		LastUsedAt: time.Now(),
+		IP:         ip,
+		UA:         ua,
+	})
+	store.addFamily(record.UserID, record.FamilyID)
+	return record.UserID, newToken, nil
+}
+
+// endSession logs out the single session presented belongs to, by killing
+// its whole family (refresh tokens are one-per-family at a time, so this is
+// equivalent to "forget this device"). Returns the session's owner and
+// whether a session was actually found, so the caller can audit the logout.
+func endSession(presented string) (userID uint, found bool) {
+	store := activeSessionStore()
+	record, ok := store.get(hashRefreshToken(presented))
+	if !ok {
+		return 0, false
+	}
+	store.dropFamily(record.UserID, record.FamilyID)
+	return record.UserID, true
+}
+
+// endAllSessions logs every session of userID out, e.g. after a password
+// change or account deletion so no previously-issued refresh token survives it.
+func endAllSessions(userID uint) {
+	store := activeSessionStore()
+	for _, familyID := range store.familiesForUser(userID) {
+		store.dropFamily(userID, familyID)
+	}
+}
+
+// listSessions returns the currently-active session of every family userID
+// has, for GET /auth/sessions.
+func listSessions(userID uint) []sessionRecord {
+	store := activeSessionStore()
+	var sessions []sessionRecord
+	for _, familyID := range store.familiesForUser(userID) {
+		hash, ok := store.activeHash(familyID)
+		if !ok {
+			continue
+		}
+		record, ok := store.get(hash)
+		if !ok {
+			continue
+		}
+		sessions = append(sessions, record)
+	}
+	return sessions
+}
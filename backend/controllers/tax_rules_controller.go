@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"net/http"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// GetTaxRules lists the authenticated user's category VAT/IRPF rates.
+func GetTaxRules(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var rules []models.TaxRule
+	if err := db.DB.Where("user_id = ?", userID).Preload("Category").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch tax rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// taxRuleInput is the CreateTaxRule/UpdateTaxRule request body.
+type taxRuleInput struct {
+	CategoryID uint    `json:"category_id" binding:"required"`
+	VATRate    float64 `json:"vat_rate"`
+	IRPFRate   float64 `json:"irpf_rate"`
+}
+
+// UpsertTaxRule handles POST /tax-rules: creates or replaces the
+// authenticated user's VAT/IRPF rates for one category, so setting a rate
+// twice updates it rather than erroring on the unique (user_id,
+// category_id) index.
+func UpsertTaxRule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input taxRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var category models.Category
+	if err := db.DB.Where("id = ? AND user_id = ?", input.CategoryID, userID).First(&category).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "category not found or does not belong to user"})
+		return
+	}
+
+	var rule models.TaxRule
+	if err := db.DB.Where(models.TaxRule{UserID: userID, CategoryID: input.CategoryID}).
+		FirstOrCreate(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save tax rule"})
+		return
+	}
+
+	rule.VATRate = input.VATRate
+	rule.IRPFRate = input.IRPFRate
+	if err := db.DB.Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save tax rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteTaxRule handles DELETE /tax-rules/:id.
+func DeleteTaxRule(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	ruleID := c.Param("id")
+
+	result := db.DB.Where("id = ? AND user_id = ?", ruleID, userID).Delete(&models.TaxRule{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete tax rule"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tax rule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "tax rule deleted"})
+}
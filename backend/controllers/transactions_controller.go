@@ -1,19 +1,40 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"Personal-Finance-Tracker-backend/db"
 	"Personal-Finance-Tracker-backend/middleware"
 	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/store"
 
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
-// GetTransactions retrieves all transactions for the authenticated user
+// TransactionStore is the store.Store implementation controllers use to
+// persist transactions. It is a package variable (mirroring db.DB) rather
+// than constructor-injected state, so tests can swap in mocks.NewMockStore
+// without restructuring the router wiring. It is re-wrapped around db.DB on
+// every call rather than cached, since tests reassign db.DB per test.
+var TransactionStore func() store.Store = func() store.Store {
+	return store.NewGormStore(db.DB)
+}
+
+// GetTransactions retrieves a keyset-paginated page of the authenticated
+// user's transactions, newest (txn_date DESC, id DESC) first. Pass the
+// previous response's next_cursor back as ?cursor= to fetch the next page;
+// an empty next_cursor means there is no more data. ?fields= trims the
+// response: pass a comma-separated list of extra fields to include (today
+// just "notes" and "splits", the two this skips by default) to get the old
+// always-everything shape back for a single page.
 func GetTransactions(c *gin.Context) {
 	// Step 1: Authenticate
 	claims, exists := c.Get("user")
@@ -24,65 +45,78 @@ func GetTransactions(c *gin.Context) {
 
 	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
 
-	var transactions []models.Transaction
-
-	// Step 2: Always filter by user_id (SECURITY CRITICAL!)
-	query := db.DB.Where("user_id = ?", userID)
-
-	// Step 3: Optional filters
-	if accountID := c.Query("account_id"); accountID != "" {
-		query = query.Where("account_id = ?", accountID)
-	}
+	// Step 2: Build filters (account/category scoping to the user happens in
+	// the store via userID)
+	var filters store.TransactionFilters
+	filters.AccountID = c.Query("account_id")
+	filters.CategoryID = c.Query("category_id")
 
-	if categoryID := c.Query("category_id"); categoryID != "" {
-		query = query.Where("category_id = ?", categoryID)
-	}
-
-	// Date range filters
 	if from := c.Query("from"); from != "" {
-		fromDate, err := time.Parse("2006-01-02", from)
-		if err == nil {
-			query = query.Where("txn_date >= ?", fromDate)
+		if fromDate, err := time.Parse("2006-01-02", from); err == nil {
+			filters.From = &fromDate
 		}
 	}
-
 	if to := c.Query("to"); to != "" {
-		toDate, err := time.Parse("2006-01-02", to)
-		if err == nil {
-			query = query.Where("txn_date <= ?", toDate)
+		if toDate, err := time.Parse("2006-01-02", to); err == nil {
+			filters.To = &toDate
 		}
 	}
-
-	// Amount range filters
 	if minAmount := c.Query("min_amount"); minAmount != "" {
-		if min, err := strconv.ParseInt(minAmount, 10, 64); err == nil {
-			query = query.Where("amount_cents >= ?", min)
+		if min, err := decimal.NewFromString(minAmount); err == nil {
+			filters.MinAmount = &min
 		}
 	}
-
 	if maxAmount := c.Query("max_amount"); maxAmount != "" {
-		if max, err := strconv.ParseInt(maxAmount, 10, 64); err == nil {
-			query = query.Where("amount_cents <= ?", max)
+		if max, err := decimal.NewFromString(maxAmount); err == nil {
+			filters.MaxAmount = &max
+		}
+	}
+	filters.Search = c.Query("search")
+	filters.Status = c.Query("status")
+
+	if fields := c.Query("fields"); fields != "" {
+		for _, f := range strings.Split(fields, ",") {
+			switch strings.TrimSpace(f) {
+			case "notes":
+				filters.IncludeNotes = true
+			case "splits":
+				filters.IncludeSplits = true
+			}
 		}
+	} else {
+		filters.IncludeNotes = true
+		filters.IncludeSplits = true
 	}
 
-	// Search in description/notes
-	if search := c.Query("search"); search != "" {
-		query = query.Where("description ILIKE ? OR notes ILIKE ?", "%"+search+"%", "%"+search+"%")
+	cursorToken, limit, err := ParseCursor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if cursorToken != "" {
+		pc, _ := decodeCursor(cursorToken) // already validated by ParseCursor
+		cursorTxnDate := time.Unix(pc.TS, 0).UTC()
+		filters.CursorTxnDate = &cursorTxnDate
+		filters.CursorID = &pc.ID
 	}
+	// Ask for one extra row so we can tell whether a next page exists
+	// without a separate COUNT query.
+	filters.Limit = limit + 1
 
-	// Step 4: Execute query with preloads
-	if err := query.
-		Preload("Account").
-		Preload("Category").
-		Preload("Splits.Category").
-		Order("txn_date DESC, created_at DESC").
-		Find(&transactions).Error; err != nil {
+	transactions, err := TransactionStore().GetTransactions(c.Request.Context(), userID, filters)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch transactions"})
 		return
 	}
 
-	c.JSON(http.StatusOK, transactions)
+	var nextCursor string
+	if len(transactions) > limit {
+		last := transactions[limit-1]
+		nextCursor = encodeCursor(last.ID, last.TxnDate)
+		transactions = transactions[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": transactions, "next_cursor": nextCursor})
 }
 
 // GetTransaction retrieves a single transaction by ID
@@ -100,13 +134,8 @@ func GetTransaction(c *gin.Context) {
 		return
 	}
 
-	var transaction models.Transaction
-	if err := db.DB.
-		Preload("Account").
-		Preload("Category").
-		Preload("Splits.Category").
-		Where("id = ? AND user_id = ?", transactionID, userID).
-		First(&transaction).Error; err != nil {
+	transaction, err := TransactionStore().GetTransaction(c.Request.Context(), userID, uint(transactionID))
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
 		return
 	}
@@ -114,6 +143,41 @@ func GetTransaction(c *gin.Context) {
 	c.JSON(http.StatusOK, transaction)
 }
 
+// resolveTransactionAccountAndCategory validates that accountID and (if
+// categoryID is non-nil) categoryID belong to userID, and returns the
+// amount with its sign corrected to match the category's Kind (expenses
+// negative, income positive) - the same adjustment CreateTransaction has
+// always made. BulkCreateTransactions reuses this so a bulk-imported row is
+// validated identically to one entered by hand.
+func resolveTransactionAccountAndCategory(tx *gorm.DB, userID, accountID uint, categoryID *uint, amount decimal.Decimal) (models.Account, *models.Category, decimal.Decimal, error) {
+	var account models.Account
+	if err := tx.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+		return models.Account{}, nil, decimal.Zero, errors.New("account not found or does not belong to user")
+	}
+
+	var category *models.Category
+	if categoryID != nil {
+		cat := models.Category{}
+		if err := tx.Where("id = ? AND user_id = ?", *categoryID, userID).First(&cat).Error; err != nil {
+			return models.Account{}, nil, decimal.Zero, errors.New("category not found or does not belong to user")
+		}
+		category = &cat
+	}
+
+	finalAmount := amount
+	if category != nil && category.Kind == "expense" {
+		if finalAmount.IsPositive() {
+			finalAmount = finalAmount.Neg()
+		}
+	} else if category != nil && category.Kind == "income" {
+		if finalAmount.IsNegative() {
+			finalAmount = finalAmount.Neg()
+		}
+	}
+
+	return account, category, finalAmount, nil
+}
+
 // CreateTransaction creates a new transaction
 func CreateTransaction(c *gin.Context) {
 	claims, exists := c.Get("user")
@@ -126,16 +190,27 @@ func CreateTransaction(c *gin.Context) {
 
 	// Define input structure
 	var input struct {
-		AccountID   uint   `json:"account_id" binding:"required"`
-		CategoryID  *uint  `json:"category_id"`
-		AmountCents int64  `json:"amount_cents" binding:"required"`
-		Description string `json:"description"`
-		TxnDate     string `json:"txn_date" binding:"required"` // YYYY-MM-DD format
-		Notes       string `json:"notes"`
+		AccountID   uint            `json:"account_id" binding:"required"`
+		CategoryID  *uint           `json:"category_id"`
+		Amount      decimal.Decimal `json:"amount" binding:"required"`
+		Description string          `json:"description"`
+		TxnDate     string          `json:"txn_date" binding:"required"` // YYYY-MM-DD format
+		Notes       string          `json:"notes"`
 		Splits      []struct {
-			CategoryID  uint  `json:"category_id" binding:"required"`
-			AmountCents int64 `json:"amount_cents" binding:"required"`
+			CategoryID uint            `json:"category_id" binding:"required"`
+			Amount     decimal.Decimal `json:"amount" binding:"required"`
+			// AccountID marks this split as a ledger leg on a different
+			// account than AccountID above (e.g. the cash side of an ATM
+			// withdrawal), rather than just a category breakdown of the
+			// primary amount. Omit it for ordinary categorization splits.
+			AccountID *uint `json:"account_id"`
 		} `json:"splits"`
+		// BudgetGroupID/SplitKind mark this transaction as shared within a
+		// BudgetGroup (see controllers.GetGroupBalances) instead of an
+		// ordinary individual expense. BudgetGroupID is required when
+		// SplitKind is "shared", and the caller must belong to that group.
+		BudgetGroupID *uint                       `json:"budget_group_id"`
+		SplitKind     models.TransactionSplitKind `json:"split_kind"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -144,11 +219,27 @@ func CreateTransaction(c *gin.Context) {
 	}
 
 	// Validate amount (non-zero)
-	if input.AmountCents == 0 {
+	if input.Amount.IsZero() {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "amount cannot be zero"})
 		return
 	}
 
+	if input.SplitKind == "" {
+		input.SplitKind = models.TransactionSplitIndividual
+	}
+	if input.SplitKind == models.TransactionSplitShared {
+		if input.BudgetGroupID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "budget_group_id is required for a shared transaction"})
+			return
+		}
+		if !isBudgetGroupMember(*input.BudgetGroupID, userID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not a member of this budget group"})
+			return
+		}
+	} else {
+		input.BudgetGroupID = nil
+	}
+
 	// Parse date
 	txnDate, err := time.Parse("2006-01-02", input.TxnDate)
 	if err != nil {
@@ -156,43 +247,26 @@ func CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	// Verify account belongs to user
-	var account models.Account
-	if err := db.DB.Where("id = ? AND user_id = ?", input.AccountID, userID).First(&account).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "account not found or does not belong to user"})
+	account, _, finalAmount, err := resolveTransactionAccountAndCategory(db.DB, userID, input.AccountID, input.CategoryID, input.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Verify category belongs to user (if provided) and get category info
-	var category *models.Category
-	if input.CategoryID != nil {
-		cat := models.Category{}
-		if err := db.DB.Where("id = ? AND user_id = ?", *input.CategoryID, userID).First(&cat).Error; err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "category not found or does not belong to user"})
-			return
-		}
-		category = &cat
-	}
-
-	// Adjust amount sign based on category type
-	// Positive = income, Negative = expense
-	finalAmount := input.AmountCents
-	if category != nil && category.Kind == "expense" {
-		// Make sure expenses are negative
-		if finalAmount > 0 {
-			finalAmount = -finalAmount
-		}
-	} else if category != nil && category.Kind == "income" {
-		// Make sure income is positive
-		if finalAmount < 0 {
-			finalAmount = -finalAmount
-		}
-	}
-	// If no category, keep the amount as provided by user
-
-	// Validate splits if provided
+	// Validate splits if provided. A split either recategorizes a slice of
+	// the primary AccountID/Amount (no AccountID, or the same one) or
+	// names a different account entirely, becoming a second ledger leg for
+	// a transfer or multi-currency transaction (e.g. an ATM withdrawal's
+	// cash side). Recategorizing splits must still add up to the
+	// transaction amount; cross-account splits are checked instead via a
+	// per-currency imbalance map that every touched currency must net to
+	// zero, mirroring how double-entry ledgers (e.g. moneygo) validate
+	// multi-account transactions.
 	if len(input.Splits) > 0 {
-		var splitTotal int64
+		imbalance := map[string]decimal.Decimal{account.Currency: finalAmount}
+		touchedAccounts := map[uint]bool{input.AccountID: true}
+		categorySplitTotal := decimal.Zero
+
 		for _, split := range input.Splits {
 			// Verify each split category
 			var category models.Category
@@ -200,70 +274,81 @@ func CreateTransaction(c *gin.Context) {
 				c.JSON(http.StatusBadRequest, gin.H{"error": "split category not found or does not belong to user"})
 				return
 			}
-			splitTotal += split.AmountCents
+
+			if split.AccountID != nil && *split.AccountID != input.AccountID {
+				var splitAccount models.Account
+				if err := db.DB.Where("id = ? AND user_id = ?", *split.AccountID, userID).First(&splitAccount).Error; err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "split account not found or does not belong to user"})
+					return
+				}
+				imbalance[splitAccount.Currency] = imbalance[splitAccount.Currency].Add(split.Amount)
+				touchedAccounts[*split.AccountID] = true
+			} else {
+				categorySplitTotal = categorySplitTotal.Add(split.Amount)
+			}
 		}
 
-		// Splits must equal transaction amount (with same sign)
-		if splitTotal != input.AmountCents {
+		// Category-only splits (no distinct account) must still equal the
+		// transaction amount (with same sign).
+		if !categorySplitTotal.IsZero() && !categorySplitTotal.Equal(finalAmount) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "split amounts must equal transaction amount"})
 			return
 		}
 
+		// Once more than one account is involved, every currency touched
+		// must balance to zero across all legs.
+		if len(touchedAccounts) > 1 {
+			for currency, sum := range imbalance {
+				if !sum.IsZero() {
+					c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("transaction is not balanced for %s", currency)})
+					return
+				}
+			}
+		}
+
 		// If splits exist, category_id should be null
 		input.CategoryID = nil
 	}
 
-	// Create transaction
-	transaction := models.Transaction{
+	var splits []models.TransactionSplit
+	for _, split := range input.Splits {
+		splits = append(splits, models.TransactionSplit{
+			CategoryID: split.CategoryID,
+			Amount:     split.Amount,
+			AccountID:  split.AccountID,
+		})
+	}
+
+	transaction, err := TransactionStore().CreateTransactionTx(c.Request.Context(), store.CreateTransactionArg{
 		UserID:      userID,
 		AccountID:   input.AccountID,
 		CategoryID:  input.CategoryID,
-		AmountCents: finalAmount,
+		Amount:      finalAmount,
 		Description: input.Description,
 		TxnDate:     txnDate,
 		Notes:       input.Notes,
-	} // Start database transaction for atomicity
-	tx := db.DB.Begin()
+		Splits:      splits,
 
-	if err := tx.Create(&transaction).Error; err != nil {
-		tx.Rollback()
+		BudgetGroupID: input.BudgetGroupID,
+		SplitKind:     input.SplitKind,
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create transaction"})
 		return
 	}
 
-	// Track metrics
-	middleware.IncrementTransactionsCreated()
-
-	// Create splits if provided
-	if len(input.Splits) > 0 {
-		for _, split := range input.Splits {
-			transactionSplit := models.TransactionSplit{
-				ParentTxnID: transaction.ID,
-				CategoryID:  split.CategoryID,
-				AmountCents: split.AmountCents,
-			}
-			if err := tx.Create(&transactionSplit).Error; err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create transaction splits"})
-				return
-			}
-		}
-	}
-
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to commit transaction"})
-		return
+	// Uncategorized transactions get a chance to match an auto-categorization rule.
+	if input.CategoryID == nil {
+		ApplyCategoryRuleToTransaction(userID, &transaction, false)
 	}
+	// Attach a canonical merchant, and suggest its default category if no
+	// CategoryRule already claimed this transaction.
+	ApplyMerchantToTransaction(userID, &transaction)
 
-	// Update account balance
-	if err := UpdateAccountBalance(input.AccountID); err != nil {
-		// Log error but don't fail the request since transaction was created
-		// In production, you might want to use a job queue for this
-	}
+	checkTransactionForAnomalies(c.Request.Context(), userID, transaction.ID)
 
-	// Reload with relationships
-	db.DB.Preload("Account").Preload("Category").Preload("Splits.Category").First(&transaction, transaction.ID)
+	// Track metrics
+	middleware.IncrementTransactionsCreated()
 
 	c.JSON(http.StatusCreated, transaction)
 }
@@ -283,19 +368,24 @@ func UpdateTransaction(c *gin.Context) {
 		return
 	}
 
-	var transaction models.Transaction
-	if err := db.DB.Where("id = ? AND user_id = ?", transactionID, userID).First(&transaction).Error; err != nil {
+	existing, err := TransactionStore().GetTransaction(c.Request.Context(), userID, uint(transactionID))
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
 		return
 	}
 
+	if existing.Status == models.TransactionReconciled && c.Query("force") != "true" {
+		c.JSON(http.StatusConflict, gin.H{"error": "transaction is reconciled, pass ?force=true to override"})
+		return
+	}
+
 	var input struct {
-		AccountID   uint   `json:"account_id"`
-		CategoryID  *uint  `json:"category_id"`
-		AmountCents int64  `json:"amount_cents"`
-		Description string `json:"description"`
-		TxnDate     string `json:"txn_date"`
-		Notes       string `json:"notes"`
+		AccountID   uint            `json:"account_id"`
+		CategoryID  *uint           `json:"category_id"`
+		Amount      decimal.Decimal `json:"amount"`
+		Description string          `json:"description"`
+		TxnDate     string          `json:"txn_date"`
+		Notes       string          `json:"notes"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -310,10 +400,10 @@ func UpdateTransaction(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "account not found or does not belong to user"})
 			return
 		}
-		transaction.AccountID = input.AccountID
 	}
 
 	// Verify category if provided and get category info
+	categoryID := existing.CategoryID
 	var category *models.Category
 	if input.CategoryID != nil {
 		cat := models.Category{}
@@ -321,57 +411,56 @@ func UpdateTransaction(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "category not found or does not belong to user"})
 			return
 		}
-		transaction.CategoryID = input.CategoryID
+		categoryID = input.CategoryID
 		category = &cat
 	}
 
-	if input.AmountCents != 0 {
+	finalAmount := input.Amount
+	if !finalAmount.IsZero() {
 		// Adjust amount sign based on category type if category is being set
-		finalAmount := input.AmountCents
 		if category != nil && category.Kind == "expense" {
 			// Make sure expenses are negative
-			if finalAmount > 0 {
-				finalAmount = -finalAmount
+			if finalAmount.IsPositive() {
+				finalAmount = finalAmount.Neg()
 			}
 		} else if category != nil && category.Kind == "income" {
 			// Make sure income is positive
-			if finalAmount < 0 {
-				finalAmount = -finalAmount
+			if finalAmount.IsNegative() {
+				finalAmount = finalAmount.Neg()
 			}
 		}
-		transaction.AmountCents = finalAmount
-	}
-
-	if input.Description != "" {
-		transaction.Description = input.Description
 	}
 
+	var txnDatePtr *time.Time
 	if input.TxnDate != "" {
 		txnDate, err := time.Parse("2006-01-02", input.TxnDate)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
 			return
 		}
-		transaction.TxnDate = txnDate
+		txnDatePtr = &txnDate
 	}
 
-	if input.Notes != "" {
-		transaction.Notes = input.Notes
-	}
-
-	if err := db.DB.Save(&transaction).Error; err != nil {
+	transaction, err := TransactionStore().UpdateTransactionTx(c.Request.Context(), store.UpdateTransactionArg{
+		ID:          uint(transactionID),
+		UserID:      userID,
+		AccountID:   input.AccountID,
+		CategoryID:  categoryID,
+		Amount:      finalAmount,
+		Description: input.Description,
+		TxnDate:     txnDatePtr,
+		Notes:       input.Notes,
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update transaction"})
 		return
 	}
 
-	// Update account balance
-	if err := UpdateAccountBalance(transaction.AccountID); err != nil {
-		// Log error but don't fail the request since transaction was updated
+	// Still-uncategorized transactions get a chance to match an auto-categorization rule.
+	if categoryID == nil {
+		ApplyCategoryRuleToTransaction(userID, &transaction, false)
 	}
 
-	// Reload with relationships
-	db.DB.Preload("Account").Preload("Category").Preload("Splits.Category").First(&transaction, transaction.ID)
-
 	c.JSON(http.StatusOK, transaction)
 }
 
@@ -390,28 +479,84 @@ func DeleteTransaction(c *gin.Context) {
 		return
 	}
 
-	var transaction models.Transaction
-	if err := db.DB.Where("id = ? AND user_id = ?", transactionID, userID).First(&transaction).Error; err != nil {
+	existing, err := TransactionStore().GetTransaction(c.Request.Context(), userID, uint(transactionID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+		return
+	}
+
+	if existing.Status == models.TransactionReconciled && c.Query("force") != "true" {
+		c.JSON(http.StatusConflict, gin.H{"error": "transaction is reconciled, pass ?force=true to override"})
+		return
+	}
+
+	if err := TransactionStore().DeleteTransactionTx(c.Request.Context(), userID, uint(transactionID)); err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
 		return
 	}
 
-	// Store account ID before deletion for balance update
-	accountID := transaction.AccountID
+	c.JSON(http.StatusOK, gin.H{"message": "transaction deleted successfully"})
+}
+
+// UpdateTransactionStatus moves a transaction through the reconciliation
+// workflow (Imported -> Entered -> Cleared -> Reconciled -> Voided). Flipping
+// a transaction to/from Reconciled this way bypasses the statement-balance
+// check that ReconcileAccount performs; use that endpoint for the normal
+// bank-reconciliation flow and reserve this one for manual corrections.
+func UpdateTransactionStatus(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	transactionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction ID"})
+		return
+	}
+
+	var input struct {
+		Status models.TransactionStatus `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	validStatuses := map[models.TransactionStatus]bool{
+		models.TransactionImported:   true,
+		models.TransactionEntered:    true,
+		models.TransactionCleared:    true,
+		models.TransactionReconciled: true,
+		models.TransactionVoided:     true,
+	}
+	if !validStatuses[input.Status] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
 
-	// Delete splits first (if any)
-	db.DB.Where("parent_txn_id = ?", transactionID).Delete(&models.TransactionSplit{})
+	transaction, err := TransactionStore().GetTransaction(c.Request.Context(), userID, uint(transactionID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+		return
+	}
 
-	// Delete transaction
-	if err := db.DB.Delete(&transaction).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete transaction"})
+	if transaction.Status == models.TransactionReconciled && c.Query("force") != "true" {
+		c.JSON(http.StatusConflict, gin.H{"error": "transaction is reconciled, pass ?force=true to override"})
 		return
 	}
 
-	// Update account balance
-	if err := UpdateAccountBalance(accountID); err != nil {
-		// Log error but don't fail the request since transaction was deleted
+	updated, err := TransactionStore().UpdateTransactionStatusTx(c.Request.Context(), store.UpdateTransactionStatusArg{
+		ID:     uint(transactionID),
+		UserID: userID,
+		Status: input.Status,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update transaction status"})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "transaction deleted successfully"})
+	c.JSON(http.StatusOK, updated)
 }
@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/store"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// CreateTransfer atomically moves money between two of the authenticated
+// user's accounts by posting a balanced pair of LedgerEntry rows (debit the
+// source, credit the destination) inside a single GORM transaction.
+//
+// Replaying the same transfer_id returns the original transfer instead of
+// creating a second one, so retries after a network blip cannot double-post.
+func CreateTransfer(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input struct {
+		FromAccountID uint     `json:"from_account_id" binding:"required"`
+		ToAccountID   uint     `json:"to_account_id" binding:"required"`
+		AmountCents   int64    `json:"amount_cents" binding:"required"`
+		ToAmountCents *int64   `json:"to_amount_cents"`
+		FXRate        *float64 `json:"fx_rate"`
+		Description   string   `json:"description"`
+		TransferID    string   `json:"transfer_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.AmountCents <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount must be positive"})
+		return
+	}
+
+	if input.FromAccountID == input.ToAccountID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to accounts must differ"})
+		return
+	}
+
+	// Idempotency: a replayed transfer_id returns the original transfer.
+	var existing models.Transfer
+	if err := db.DB.Where("transfer_id = ? AND user_id = ?", input.TransferID, userID).First(&existing).Error; err == nil {
+		c.JSON(http.StatusOK, existing)
+		return
+	}
+
+	// First() filters out soft-deleted accounts by default (Account.DeletedAt
+	// is a gorm.DeletedAt), so a transfer into/out of a deleted account
+	// already fails here with "not found" rather than needing a separate check.
+	var fromAccount, toAccount models.Account
+	if err := db.DB.Where("id = ? AND user_id = ?", input.FromAccountID, userID).First(&fromAccount).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source account not found or does not belong to user"})
+		return
+	}
+	if err := db.DB.Where("id = ? AND user_id = ?", input.ToAccountID, userID).First(&toAccount).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "destination account not found or does not belong to user"})
+		return
+	}
+
+	// Cross-currency transfers need to know the destination-side amount:
+	// either given directly, or derived from an FX rate applied to the
+	// source amount.
+	creditAmountCents := input.AmountCents
+	if fromAccount.Currency != toAccount.Currency {
+		switch {
+		case input.ToAmountCents != nil:
+			creditAmountCents = *input.ToAmountCents
+		case input.FXRate != nil:
+			creditAmountCents = int64(float64(input.AmountCents)**input.FXRate + 0.5)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to_amount_cents or fx_rate is required when accounts use different currencies"})
+			return
+		}
+	}
+
+	transfer := models.Transfer{
+		UserID:        userID,
+		FromAccountID: input.FromAccountID,
+		ToAccountID:   input.ToAccountID,
+		AmountCents:   input.AmountCents,
+		Description:   input.Description,
+		TransferID:    input.TransferID,
+	}
+	if fromAccount.Currency != toAccount.Currency {
+		transfer.ToAmountCents = &creditAmountCents
+		rate := float64(creditAmountCents) / float64(input.AmountCents)
+		transfer.FXRate = &rate
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		debit := models.LedgerEntry{
+			AccountID:   input.FromAccountID,
+			AmountCents: -input.AmountCents,
+			Direction:   models.LedgerDebit,
+		}
+		if err := tx.Create(&debit).Error; err != nil {
+			return err
+		}
+
+		credit := models.LedgerEntry{
+			AccountID:   input.ToAccountID,
+			AmountCents: creditAmountCents,
+			Direction:   models.LedgerCredit,
+		}
+		if err := tx.Create(&credit).Error; err != nil {
+			return err
+		}
+
+		transfer.DebitEntryID = debit.ID
+		transfer.CreditEntryID = credit.ID
+		if err := tx.Create(&transfer).Error; err != nil {
+			return err
+		}
+
+		if err := store.RecalculateBalance(tx, input.FromAccountID); err != nil {
+			return err
+		}
+		if err := store.RecalculateBalance(tx, input.ToAccountID); err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		// A unique-constraint violation on transfer_id means we lost a race
+		// against a concurrent identical retry; surface the existing row.
+		var raced models.Transfer
+		if db.DB.Where("transfer_id = ? AND user_id = ?", input.TransferID, userID).First(&raced).Error == nil {
+			c.JSON(http.StatusOK, raced)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create transfer"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+// GetTransfer fetches a transfer belonging to the authenticated user by its
+// group id (Transfer.ID), along with its two linked LedgerEntry legs.
+func GetTransfer(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var transfer models.Transfer
+	if err := db.DB.Where("id = ? AND user_id = ?", c.Param("group_id"), userID).First(&transfer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transfer not found"})
+		return
+	}
+
+	var debit, credit models.LedgerEntry
+	db.DB.First(&debit, transfer.DebitEntryID)
+	db.DB.First(&credit, transfer.CreditEntryID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"transfer":   transfer,
+		"debit_leg":  debit,
+		"credit_leg": credit,
+	})
+}
+
+// DeleteTransfer reverses a transfer by posting an equal-and-opposite
+// LedgerEntry pair and undoing both accounts' balance updates, atomically,
+// rather than deleting the original legs - the reversal stays in the
+// ledger as its own audit trail. A transfer can only be reversed once.
+func DeleteTransfer(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var transfer models.Transfer
+	if err := db.DB.Where("id = ? AND user_id = ?", c.Param("group_id"), userID).First(&transfer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transfer not found"})
+		return
+	}
+
+	if transfer.ReversedAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "transfer has already been reversed"})
+		return
+	}
+
+	var fromAccount, toAccount models.Account
+	if err := db.DB.Where("id = ? AND user_id = ?", transfer.FromAccountID, userID).First(&fromAccount).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source account not found or does not belong to user"})
+		return
+	}
+	if err := db.DB.Where("id = ? AND user_id = ?", transfer.ToAccountID, userID).First(&toAccount).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "destination account not found or does not belong to user"})
+		return
+	}
+
+	creditAmountCents := transfer.AmountCents
+	if transfer.ToAmountCents != nil {
+		creditAmountCents = *transfer.ToAmountCents
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		reverseDebit := models.LedgerEntry{
+			AccountID:   transfer.ToAccountID,
+			AmountCents: -creditAmountCents,
+			Direction:   models.LedgerDebit,
+		}
+		if err := tx.Create(&reverseDebit).Error; err != nil {
+			return err
+		}
+
+		reverseCredit := models.LedgerEntry{
+			AccountID:   transfer.FromAccountID,
+			AmountCents: transfer.AmountCents,
+			Direction:   models.LedgerCredit,
+		}
+		if err := tx.Create(&reverseCredit).Error; err != nil {
+			return err
+		}
+
+		if err := store.RecalculateBalance(tx, transfer.ToAccountID); err != nil {
+			return err
+		}
+		if err := store.RecalculateBalance(tx, transfer.FromAccountID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		return tx.Model(&transfer).Update("reversed_at", &now).Error
+	})
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reverse transfer"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
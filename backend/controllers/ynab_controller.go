@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/integrations/ynab"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// ConnectYnab stores the authenticated user's YNAB personal access token,
+// encrypted at rest with ynab.EncryptToken, and which of that token's
+// budgets to sync from. Replaying this endpoint (e.g. to rotate a token or
+// switch budgets) overwrites the existing connection rather than creating a
+// second one - a user has at most one.
+func ConnectYnab(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var input struct {
+		PersonalAccessToken string                      `json:"personal_access_token" binding:"required"`
+		BudgetID            string                      `json:"budget_id"`
+		ConflictStrategy    models.YnabConflictStrategy `json:"conflict_strategy"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	budgetID := input.BudgetID
+	if budgetID == "" {
+		budgetID = "last-used"
+	}
+
+	conflictStrategy := models.YnabConflictServerWins
+	if input.ConflictStrategy != "" {
+		switch input.ConflictStrategy {
+		case models.YnabConflictServerWins, models.YnabConflictLocalWins, models.YnabConflictManualReview:
+			conflictStrategy = input.ConflictStrategy
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "conflict_strategy must be one of server_wins, local_wins, manual_review"})
+			return
+		}
+	}
+
+	encryptedToken, err := ynab.EncryptToken(input.PersonalAccessToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encrypt access token: " + err.Error()})
+		return
+	}
+
+	var conn models.YnabConnection
+	found := db.DB.Where("user_id = ?", userID).First(&conn).Error == nil
+
+	conn.UserID = userID
+	conn.EncryptedToken = encryptedToken
+	conn.BudgetID = budgetID
+	conn.ConflictStrategy = conflictStrategy
+	conn.Status = "connected"
+
+	if found {
+		err = db.DB.Save(&conn).Error
+	} else {
+		err = db.DB.Create(&conn).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save YNAB connection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":                conn.ID,
+		"budget_id":         conn.BudgetID,
+		"status":            conn.Status,
+		"conflict_strategy": conn.ConflictStrategy,
+	})
+}
+
+// SyncYnab pulls the authenticated user's connected YNAB budget and merges
+// it into their accounts/categories/budgets/transactions (see ynab.Sync).
+func SyncYnab(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var conn models.YnabConnection
+	if err := db.DB.Where("user_id = ?", userID).First(&conn).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no YNAB connection for this user"})
+		return
+	}
+
+	result, err := ynab.Sync(userID, conn)
+	if err != nil {
+		db.DB.Model(&conn).Update("status", "failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"accounts_upserted":     result.AccountsUpserted,
+		"categories_upserted":   result.CategoriesUpserted,
+		"budget_items_upserted": result.BudgetItemsUpserted,
+		"transactions_applied":  result.TransactionsApplied,
+		"conflicts_raised":      result.ConflictsRaised,
+	})
+}
+
+// GetYnabConflicts lists the authenticated user's unresolved YnabConflict
+// rows - transactions a manual_review ConflictStrategy declined to
+// overwrite during sync because they'd been edited locally since.
+func GetYnabConflicts(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+	var conn models.YnabConnection
+	if err := db.DB.Where("user_id = ?", userID).First(&conn).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no YNAB connection for this user"})
+		return
+	}
+
+	var conflicts []models.YnabConflict
+	if err := db.DB.
+		Where("ynab_connection_id = ? AND resolved = ?", conn.ID, false).
+		Order("created_at DESC").
+		Find(&conflicts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch YNAB conflicts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, conflicts)
+}
+
+// ResolveYnabConflict applies one side of a YnabConflict to its Transaction
+// and marks the conflict resolved: "remote" overwrites the local row with
+// the Remote* fields Sync captured, "local" just marks it resolved and
+// leaves the Transaction untouched.
+func ResolveYnabConflict(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	conflictID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conflict ID"})
+		return
+	}
+
+	var input struct {
+		Resolution string `json:"resolution" binding:"required"` // "remote" or "local"
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Resolution != "remote" && input.Resolution != "local" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resolution must be one of remote, local"})
+		return
+	}
+
+	var conn models.YnabConnection
+	if err := db.DB.Where("user_id = ?", userID).First(&conn).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no YNAB connection for this user"})
+		return
+	}
+
+	var conflict models.YnabConflict
+	if err := db.DB.Where("id = ? AND ynab_connection_id = ?", conflictID, conn.ID).First(&conflict).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "YNAB conflict not found"})
+		return
+	}
+
+	if input.Resolution == "remote" {
+		var transaction models.Transaction
+		if err := db.DB.Where("id = ? AND user_id = ?", conflict.TransactionID, userID).First(&transaction).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+			return
+		}
+
+		transaction.Description = conflict.RemoteDescription
+		transaction.Amount = conflict.RemoteAmount
+		transaction.CategoryID = conflict.RemoteCategoryID
+		transaction.TxnDate = conflict.RemoteTxnDate
+		if err := db.DB.Save(&transaction).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to apply remote transaction"})
+			return
+		}
+	}
+
+	if err := db.DB.Model(&conflict).Update("resolved", true).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve YNAB conflict"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "conflict resolved"})
+}
@@ -0,0 +1,103 @@
+// Package cronexpr parses and evaluates a standard 5-field cron expression
+// ("minute hour day-of-month month day-of-week"). It's a deliberately
+// minimal, hand-rolled subset - just "*" and comma-separated integer lists,
+// no step ranges or named months/weekdays - the same way backend/recurring
+// hand-rolls just enough of RFC 5545 for recurring transactions instead of
+// pulling in a full RRULE library. There's no go.mod here to add
+// robfig/cron as a dependency to either (see
+// controllers/digest_scheduler.go's doc comment on the same constraint).
+package cronexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed 5-field cron expression.
+type Schedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek fieldMatcher
+}
+
+// fieldMatcher is one cron field: nil means "*" (matches anything).
+type fieldMatcher map[int]struct{}
+
+func (m fieldMatcher) matches(v int) bool {
+	if m == nil {
+		return true
+	}
+	_, ok := m[v]
+	return ok
+}
+
+func parseField(field string) (fieldMatcher, error) {
+	if field == "*" {
+		return nil, nil
+	}
+
+	matcher := make(fieldMatcher)
+	for _, part := range strings.Split(field, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron field %q: %w", field, err)
+		}
+		matcher[n] = struct{}{}
+	}
+	return matcher, nil
+}
+
+// Parse parses a standard 5-field "minute hour dom month dow" cron
+// expression.
+func Parse(expr string) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("cron expression must have 5 fields, got %d", len(fields))
+	}
+
+	var s Schedule
+	var err error
+	if s.minute, err = parseField(fields[0]); err != nil {
+		return Schedule{}, err
+	}
+	if s.hour, err = parseField(fields[1]); err != nil {
+		return Schedule{}, err
+	}
+	if s.dayOfMonth, err = parseField(fields[2]); err != nil {
+		return Schedule{}, err
+	}
+	if s.month, err = parseField(fields[3]); err != nil {
+		return Schedule{}, err
+	}
+	if s.dayOfWeek, err = parseField(fields[4]); err != nil {
+		return Schedule{}, err
+	}
+	return s, nil
+}
+
+// maxSearchMinutes bounds Next's minute-by-minute search so a malformed/
+// impossible expression (e.g. a day-of-month value that excludes every
+// month the month-field allows) fails fast instead of looping forever.
+const maxSearchMinutes = 4 * 366 * 24 * 60
+
+// Next returns the first minute-aligned instant, strictly after `after`
+// (interpreted in `loc`), that satisfies expr.
+func Next(expr string, after time.Time, loc *time.Location) (time.Time, error) {
+	schedule, err := Parse(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	candidate := after.In(loc).Truncate(time.Minute).Add(time.Minute)
+	for i := 0; i < maxSearchMinutes; i++ {
+		if schedule.minute.matches(candidate.Minute()) &&
+			schedule.hour.matches(candidate.Hour()) &&
+			schedule.dayOfMonth.matches(candidate.Day()) &&
+			schedule.month.matches(int(candidate.Month())) &&
+			schedule.dayOfWeek.matches(int(candidate.Weekday())) {
+			return candidate, nil
+		}
+		candidate = candidate.Add(time.Minute)
+	}
+	return time.Time{}, fmt.Errorf("no matching time found for cron expression %q within %d years", expr, maxSearchMinutes/(366*24*60))
+}
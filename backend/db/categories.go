@@ -0,0 +1,104 @@
+package db
+
+import (
+	"strconv"
+	"strings"
+
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// ReparentCategory moves category under newParent (nil for a root),
+// rewriting its own Path/Depth and every descendant's in one indexed LIKE
+// scan instead of walking the subtree in Go. category.Path/Depth must
+// already reflect its *current* (pre-move) position - its initial Path is
+// stamped by models.Category's AfterCreate hook, so this only needs to
+// handle moves after the fact.
+func ReparentCategory(tx *gorm.DB, category *models.Category, newParent *models.Category) error {
+	newParentPath, newDepth := "", 0
+	if newParent != nil {
+		newParentPath, newDepth = newParent.Path, newParent.Depth+1
+	}
+	newPath := newParentPath + strconv.FormatUint(uint64(category.ID), 10) + "/"
+	if newParentPath == "" {
+		newPath = "/" + strconv.FormatUint(uint64(category.ID), 10) + "/"
+	}
+	delta := newDepth - category.Depth
+
+	oldPath := category.Path
+	if err := tx.Exec(
+		"UPDATE categories SET path = REPLACE(path, ?, ?), depth = depth + ? WHERE path LIKE ?",
+		oldPath, newPath, delta, oldPath+"%",
+	).Error; err != nil {
+		return err
+	}
+
+	var newParentID *uint
+	if newParent != nil {
+		newParentID = &newParent.ID
+	}
+	if err := tx.Model(category).Update("parent_id", newParentID).Error; err != nil {
+		return err
+	}
+
+	category.Path = newPath
+	category.Depth = newDepth
+	category.ParentID = newParentID
+	return nil
+}
+
+// CategoryAncestors returns id's ancestor chain (parent, grandparent, ...),
+// nearest first, gated by userID so a lookup can never cross into another
+// user's tree. Reads straight off the Path column instead of walking
+// ParentID one query per level.
+func CategoryAncestors(userID, id uint) ([]models.Category, error) {
+	var self models.Category
+	if err := DB.Where("id = ? AND user_id = ?", id, userID).First(&self).Error; err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(strings.Trim(self.Path, "/"), "/")
+	if len(segments) <= 1 {
+		return nil, nil
+	}
+	ancestorIDs := segments[:len(segments)-1]
+
+	var ancestors []models.Category
+	if err := DB.Where("id IN ? AND user_id = ?", ancestorIDs, userID).Find(&ancestors).Error; err != nil {
+		return nil, err
+	}
+
+	// The path is furthest-ancestor-first (root ... parent); DB.Find
+	// doesn't preserve IN-list order, so re-sort to nearest-first to match
+	// the previous ParentID-walk implementation's contract.
+	byID := make(map[uint]models.Category, len(ancestors))
+	for _, a := range ancestors {
+		byID[a.ID] = a
+	}
+	ordered := make([]models.Category, 0, len(ancestorIDs))
+	for i := len(ancestorIDs) - 1; i >= 0; i-- {
+		parsed, err := strconv.ParseUint(ancestorIDs[i], 10, 32)
+		if err != nil {
+			continue
+		}
+		if a, ok := byID[uint(parsed)]; ok {
+			ordered = append(ordered, a)
+		}
+	}
+	return ordered, nil
+}
+
+// CategoryDescendants returns every category at any depth below id, gated
+// by userID, via a single indexed `path LIKE 'prefix%'` scan instead of a
+// level-by-level walk.
+func CategoryDescendants(userID, id uint) ([]models.Category, error) {
+	var self models.Category
+	if err := DB.Where("id = ? AND user_id = ?", id, userID).First(&self).Error; err != nil {
+		return nil, err
+	}
+
+	var descendants []models.Category
+	err := DB.Where("user_id = ? AND path LIKE ? AND id != ?", userID, self.Path+"%", id).Find(&descendants).Error
+	return descendants, err
+}
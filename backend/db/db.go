@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"Personal-Finance-Tracker-backend/migrations"
 	"Personal-Finance-Tracker-backend/models"
 
 	"gorm.io/driver/postgres"
@@ -44,23 +45,18 @@ func ConnectDatabase() {
 		if err == nil {
 			log.Println("✅ Connected to database!")
 
-			// 🧱 AutoMigrate all models
-			if err := DB.AutoMigrate(
-				&models.User{},
-				&models.Account{},
-				&models.Category{},
-				&models.Transaction{},
-				&models.TransactionSplit{},
-				&models.Budget{},
-				&models.BudgetItem{},
-				&models.BankConnection{},
-				&models.BankAccount{},
-				&models.BankSyncLog{},
-			); err != nil {
+			// 🧱 Apply every registered schema migration (see the
+			// migrations package) instead of one inline AutoMigrate call
+			if err := migrations.Migrate(DB); err != nil {
 				log.Fatalf("❌ Failed to migrate database: %v", err)
 			}
 
 			log.Println("✅ Database migration completed!")
+
+			if err := models.SeedDefaultRolePermissions(DB); err != nil {
+				log.Fatalf("❌ Failed to seed default role permissions: %v", err)
+			}
+
 			return
 		}
 
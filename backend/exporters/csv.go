@@ -0,0 +1,54 @@
+package exporters
+
+import (
+	"encoding/csv"
+	"strconv"
+)
+
+// csvWriter is a flat CSV export - account boundaries are just a column,
+// not a section break, so BeginAccount/EndAccount only track the current
+// account for WriteTransaction to stamp on each row.
+type csvWriter struct {
+	w           *csv.Writer
+	accountID   uint
+	accountName string
+}
+
+func newCSVWriter(w writerTarget) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (e *csvWriter) Open() error {
+	err := e.w.Write([]string{"account_id", "account_name", "txn_date", "amount_cents", "payee", "category", "fitid"})
+	e.w.Flush()
+	return err
+}
+
+func (e *csvWriter) BeginAccount(accountID uint, accountName string) error {
+	e.accountID = accountID
+	e.accountName = accountName
+	return nil
+}
+
+func (e *csvWriter) WriteTransaction(txn ExportTransaction) error {
+	err := e.w.Write([]string{
+		strconv.FormatUint(uint64(e.accountID), 10),
+		e.accountName,
+		txn.TxnDate.Format("2006-01-02"),
+		strconv.FormatInt(txn.AmountCents, 10),
+		txn.Payee,
+		txn.CategoryName,
+		txn.FITID,
+	})
+	e.w.Flush()
+	return err
+}
+
+func (e *csvWriter) EndAccount() error {
+	return nil
+}
+
+func (e *csvWriter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
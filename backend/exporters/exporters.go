@@ -0,0 +1,89 @@
+// Package exporters streams transactions out to the same interchange
+// formats backend/importers reads, grouped by account as each format
+// expects (an OFX <BANKTRANLIST>, a QIF "!Type:Bank" section). Writers take
+// rows one at a time so a caller can stream straight from a DB cursor
+// without buffering the whole export in memory.
+package exporters
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExportTransaction is one row handed to a Writer, independent of the
+// destination format.
+type ExportTransaction struct {
+	TxnDate      time.Time
+	AmountCents  int64
+	Payee        string
+	CategoryName string
+	FITID        string // stable external/bank ID, empty if the transaction has none
+}
+
+// Writer streams transactions for one account group at a time into a
+// specific interchange format. Callers must call Open once, BeginAccount/
+// WriteTransaction*/EndAccount once per account group (in account order),
+// and Close once, in that order.
+type Writer interface {
+	Open() error
+	BeginAccount(accountID uint, accountName string) error
+	WriteTransaction(txn ExportTransaction) error
+	EndAccount() error
+	Close() error
+}
+
+// Format identifies which Writer to use.
+type Format string
+
+const (
+	FormatCSV Format = "csv"
+	FormatQIF Format = "qif"
+	FormatOFX Format = "ofx"
+)
+
+// ContentType is the HTTP Content-Type for format's export body.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatQIF:
+		return "application/qif"
+	case FormatOFX:
+		return "application/x-ofx"
+	default:
+		return "text/csv"
+	}
+}
+
+// NewWriter returns the Writer registered for format, streaming into w.
+func NewWriter(format Format, w writerTarget) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatQIF:
+		return newQIFWriter(w), nil
+	case FormatOFX:
+		return newOFXWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// writerTarget is the minimal io.Writer surface Writers need; named so
+// NewWriter's signature doesn't force an "io" import on callers that only
+// pass gin.ResponseWriter.
+type writerTarget interface {
+	Write(p []byte) (n int, err error)
+}
+
+func ofxTxnType(amountCents int64) string {
+	if amountCents < 0 {
+		return "DEBIT"
+	}
+	return "CREDIT"
+}
+
+// qifSanitize strips newlines from free-text fields, since QIF's "^" record
+// terminator and line-oriented fields don't tolerate embedded newlines.
+func qifSanitize(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r", " "), "\n", " ")
+}
@@ -0,0 +1,58 @@
+package exporters
+
+import (
+	"fmt"
+	"html"
+	"time"
+)
+
+// ofxWriter emits OFX 2.x SGML (the dialect OFXParser's tolerant regex
+// parser and most banks/PFM tools read): one <STMTTRNRS>/<BANKTRANLIST>
+// per account, one <STMTTRN> per transaction.
+type ofxWriter struct {
+	w writerTarget
+}
+
+func newOFXWriter(w writerTarget) *ofxWriter {
+	return &ofxWriter{w: w}
+}
+
+func (e *ofxWriter) Open() error {
+	_, err := fmt.Fprint(e.w, ""+
+		"OFXHEADER:100\r\nDATA:OFXSGML\r\nVERSION:211\r\nSECURITY:NONE\r\nENCODING:USASCII\r\nCHARSET:1252\r\nCOMPRESSION:NONE\r\nOLDFILEUID:NONE\r\nNEWFILEUID:NONE\r\n\r\n"+
+		"<OFX>\n<SIGNONMSGSRSV1>\n<SONRS>\n<STATUS>\n<CODE>0\n<SEVERITY>INFO\n</STATUS>\n<DTSERVER>"+ofxNow()+"\n<LANGUAGE>ENG\n</SONRS>\n</SIGNONMSGSRSV1>\n"+
+		"<BANKMSGSRSV1>\n<STMTTRNRS>\n<TRNUID>1\n<STATUS>\n<CODE>0\n<SEVERITY>INFO\n</STATUS>\n")
+	return err
+}
+
+func (e *ofxWriter) BeginAccount(accountID uint, accountName string) error {
+	_, err := fmt.Fprintf(e.w, "<STMTRS>\n<CURDEF>USD\n<BANKACCTFROM>\n<ACCTID>%d\n<ACCTTYPE>CHECKING\n</BANKACCTFROM>\n<BANKTRANLIST>\n", accountID)
+	return err
+}
+
+func (e *ofxWriter) WriteTransaction(txn ExportTransaction) error {
+	_, err := fmt.Fprintf(e.w,
+		"<STMTTRN>\n<TRNTYPE>%s\n<DTPOSTED>%s\n<TRNAMT>%.2f\n<FITID>%s\n<NAME>%s\n<MEMO>%s\n</STMTTRN>\n",
+		ofxTxnType(txn.AmountCents),
+		txn.TxnDate.Format("20060102"),
+		float64(txn.AmountCents)/100.0,
+		html.EscapeString(txn.FITID),
+		html.EscapeString(txn.Payee),
+		html.EscapeString(txn.CategoryName),
+	)
+	return err
+}
+
+func (e *ofxWriter) EndAccount() error {
+	_, err := fmt.Fprint(e.w, "</BANKTRANLIST>\n</STMTRS>\n")
+	return err
+}
+
+func (e *ofxWriter) Close() error {
+	_, err := fmt.Fprint(e.w, "</STMTTRNRS>\n</BANKMSGSRSV1>\n</OFX>\n")
+	return err
+}
+
+func ofxNow() string {
+	return time.Now().UTC().Format("20060102150405")
+}
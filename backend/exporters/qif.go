@@ -0,0 +1,38 @@
+package exporters
+
+import "fmt"
+
+// qifWriter emits one "!Account"/"!Type:Bank" section per account, matching
+// the layout QIFParser (backend/importers/qif.go) reads back.
+type qifWriter struct {
+	w writerTarget
+}
+
+func newQIFWriter(w writerTarget) *qifWriter {
+	return &qifWriter{w: w}
+}
+
+func (e *qifWriter) Open() error {
+	return nil
+}
+
+func (e *qifWriter) BeginAccount(accountID uint, accountName string) error {
+	_, err := fmt.Fprintf(e.w, "!Account\nN%s\nTBank\n^\n!Type:Bank\n", qifSanitize(accountName))
+	return err
+}
+
+func (e *qifWriter) WriteTransaction(txn ExportTransaction) error {
+	amount := float64(txn.AmountCents) / 100.0
+	payee := qifSanitize(txn.Payee)
+	category := qifSanitize(txn.CategoryName)
+	_, err := fmt.Fprintf(e.w, "D%s\nT%.2f\nP%s\nL%s\n^\n", txn.TxnDate.Format("01/02/2006"), amount, payee, category)
+	return err
+}
+
+func (e *qifWriter) EndAccount() error {
+	return nil
+}
+
+func (e *qifWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,243 @@
+// Package forecast predicts a category's next-period spend from its
+// monthly history, in process - replacing the Python AI sidecar that used
+// to do this over HTTP. PredictCategory is the only entry point
+// controllers need.
+package forecast
+
+import "math"
+
+// SeasonLength is the number of months per season Holt-Winters assumes -
+// the calendar year, since spend in most categories (utilities, gifts,
+// travel) has yearly seasonality.
+const SeasonLength = 12
+
+// Smoothing constants for Holt-Winters' level, trend and seasonal
+// components. Fixed rather than fitted per-category: with the handful of
+// months most users have, optimizing all three per category would overfit
+// more often than it would help.
+const (
+	alpha = 0.3
+	beta  = 0.1
+	gamma = 0.3
+)
+
+// trendThreshold bounds how large a step-over-step change in level has to
+// be, as a fraction of the historical average, before TrendDirection
+// calls it "increasing"/"decreasing" instead of "stable" - small
+// month-to-month noise shouldn't flip the label.
+const trendThreshold = 0.01
+
+// MonthlyTotal is one month's observed spend for a category, oldest first.
+type MonthlyTotal struct {
+	Month       string // "2006-01"
+	AmountCents int64
+}
+
+// Prediction is PredictCategory's output.
+type Prediction struct {
+	PredictedAmountCents int64
+	ConfidenceScore      float64
+	TrendDirection       string // "increasing", "decreasing", or "stable"
+	HistoricalAvgCents   int64
+}
+
+// PredictCategory forecasts a category's spend `horizon` months past the
+// last month in history. With at least two full seasons (24 months) of
+// history it uses Holt-Winters triple exponential smoothing with additive
+// seasonality of period SeasonLength; with less than that it falls back
+// to a recency-weighted moving average, which doesn't need enough data to
+// estimate a seasonal cycle. Fewer than 3 months of history returns the
+// zero Prediction - too little signal to forecast anything from, which
+// callers treat the same as "no historical data".
+func PredictCategory(history []MonthlyTotal, horizon int) Prediction {
+	if len(history) < 3 {
+		return Prediction{}
+	}
+	if horizon < 1 {
+		horizon = 1
+	}
+
+	y := make([]float64, len(history))
+	for i, h := range history {
+		y[i] = float64(h.AmountCents)
+	}
+	historicalAvg := mean(y)
+
+	var forecastVal, trendAtEnd, rmse float64
+	if len(y) >= 2*SeasonLength {
+		var fitted []float64
+		forecastVal, trendAtEnd, fitted = holtWinters(y, horizon)
+		rmse = rootMeanSquaredError(y, fitted, SeasonLength)
+	} else {
+		var fitted []float64
+		forecastVal, fitted = weightedMovingAverage(y)
+		trendAtEnd = linearSlope(y)
+		rmse = rootMeanSquaredError(y, fitted, 1)
+	}
+	if forecastVal < 0 {
+		forecastVal = 0
+	}
+
+	meanAbs := mean(absAll(y))
+	confidence := 1 - math.Min(1, rmse/math.Max(1, meanAbs))
+	if confidence < 0 {
+		confidence = 0
+	}
+
+	direction := "stable"
+	if threshold := trendThreshold * math.Max(1, math.Abs(historicalAvg)); math.Abs(trendAtEnd) > threshold {
+		if trendAtEnd > 0 {
+			direction = "increasing"
+		} else {
+			direction = "decreasing"
+		}
+	}
+
+	return Prediction{
+		PredictedAmountCents: int64(math.Round(forecastVal)),
+		ConfidenceScore:      round2(confidence),
+		TrendDirection:       direction,
+		HistoricalAvgCents:   int64(math.Round(historicalAvg)),
+	}
+}
+
+// holtWinters runs the classic triple exponential smoothing recursion:
+//
+//	L_t = alpha*(y_t - S_{t-12}) + (1-alpha)*(L_{t-1} + T_{t-1})
+//	T_t = beta*(L_t - L_{t-1}) + (1-beta)*T_{t-1}
+//	S_t = gamma*(y_t - L_t) + (1-gamma)*S_{t-12}
+//
+// L_0 is seeded as the mean of the first season, T_0 as the average
+// per-step slope between the first two seasons' means, and each S_i in
+// the first season as y_i - L_0. fitted holds the one-step-ahead fit
+// L_{t-1} + T_{t-1} + S_{t-12} for t >= SeasonLength, used to score
+// in-sample error; indices before that are left zero since there's no
+// fit to compare against yet.
+func holtWinters(y []float64, horizon int) (forecast, trendAtEnd float64, fitted []float64) {
+	n := len(y)
+	p := SeasonLength
+
+	level := make([]float64, n)
+	trend := make([]float64, n)
+	season := make([]float64, n)
+	fitted = make([]float64, n)
+
+	l0 := mean(y[:p])
+	t0 := (mean(y[p:2*p]) - l0) / float64(p)
+
+	for i := 0; i < p; i++ {
+		season[i] = y[i] - l0
+	}
+	level[p-1] = l0
+	trend[p-1] = t0
+
+	for t := p; t < n; t++ {
+		prevLevel := level[t-1]
+		prevTrend := trend[t-1]
+		seasonalPast := season[t-p]
+
+		level[t] = alpha*(y[t]-seasonalPast) + (1-alpha)*(prevLevel+prevTrend)
+		trend[t] = beta*(level[t]-prevLevel) + (1-beta)*prevTrend
+		season[t] = gamma*(y[t]-level[t]) + (1-gamma)*seasonalPast
+
+		fitted[t] = prevLevel + prevTrend + seasonalPast
+	}
+
+	lastIdx := n - 1
+	seasonIdx := n - p + ((horizon - 1) % p)
+	forecast = level[lastIdx] + float64(horizon)*trend[lastIdx] + season[seasonIdx]
+	trendAtEnd = trend[lastIdx]
+	return forecast, trendAtEnd, fitted
+}
+
+// weightedMovingAverage forecasts the next value as a recency-weighted
+// average of the whole series (month i weighted i+1, so the most recent
+// month counts most), used when there isn't enough history to estimate a
+// seasonal cycle. fitted[i] is the same weighted average computed over
+// only y[:i], i.e. what this method would have forecast for month i
+// having seen everything before it; fitted[0] has no prior months to
+// average so it's left as y[0], a perfect (and excluded) fit.
+func weightedMovingAverage(y []float64) (forecast float64, fitted []float64) {
+	n := len(y)
+	fitted = make([]float64, n)
+	fitted[0] = y[0]
+
+	weightedAvg := func(upTo int) float64 {
+		var weightedSum, weightSum float64
+		for i := 0; i < upTo; i++ {
+			w := float64(i + 1)
+			weightedSum += w * y[i]
+			weightSum += w
+		}
+		return weightedSum / weightSum
+	}
+
+	for i := 1; i < n; i++ {
+		fitted[i] = weightedAvg(i)
+	}
+	forecast = weightedAvg(n)
+	return forecast, fitted
+}
+
+// linearSlope returns the least-squares slope of y against its index,
+// standing in for Holt-Winters' trend component when there isn't enough
+// history to run the full recursion.
+func linearSlope(y []float64) float64 {
+	n := len(y)
+	if n < 2 {
+		return 0
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for i, v := range y {
+		x := float64(i)
+		sumX += x
+		sumY += v
+		sumXY += x * v
+		sumXX += x * x
+	}
+	nf := float64(n)
+	denom := nf*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (nf*sumXY - sumX*sumY) / denom
+}
+
+// rootMeanSquaredError compares y against fitted from start onward,
+// skipping the leading indices a forecasting method has no fit for.
+func rootMeanSquaredError(y, fitted []float64, start int) float64 {
+	var sumSq float64
+	count := 0
+	for i := start; i < len(y); i++ {
+		diff := y[i] - fitted[i]
+		sumSq += diff * diff
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return math.Sqrt(sumSq / float64(count))
+}
+
+func mean(v []float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range v {
+		sum += x
+	}
+	return sum / float64(len(v))
+}
+
+func absAll(v []float64) []float64 {
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = math.Abs(x)
+	}
+	return out
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,62 @@
+package importers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVParser parses a simple "date,payee,amount" CSV export with an optional
+// header row. Amount is read in dollars and converted to cents.
+type CSVParser struct{}
+
+func (CSVParser) Parse(data []byte) ([]ParsedTransaction, error) {
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	var transactions []ParsedTransaction
+	for i, row := range rows {
+		if len(row) < 3 {
+			continue
+		}
+
+		// Skip a header row such as "date,payee,amount".
+		if i == 0 {
+			if _, err := time.Parse("2006-01-02", strings.TrimSpace(row[0])); err != nil {
+				continue
+			}
+		}
+
+		txnDate, err := time.Parse("2006-01-02", strings.TrimSpace(row[0]))
+		if err != nil {
+			continue
+		}
+
+		amount, err := strconv.ParseFloat(strings.TrimSpace(row[2]), 64)
+		if err != nil {
+			continue
+		}
+
+		transactions = append(transactions, ParsedTransaction{
+			TxnDate:     txnDate,
+			AmountCents: int64(amount*100 + sign(amount)*0.5),
+			Payee:       strings.TrimSpace(row[1]),
+		})
+	}
+
+	return transactions, nil
+}
+
+func sign(f float64) float64 {
+	if f < 0 {
+		return -1
+	}
+	return 1
+}
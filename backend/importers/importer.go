@@ -0,0 +1,78 @@
+// Package importers parses bank/statement export files into a common
+// ParsedTransaction shape that controllers can dedupe and persist.
+package importers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParsedTransaction is one row extracted from an imported file, independent
+// of the source format.
+type ParsedTransaction struct {
+	TxnDate     time.Time
+	AmountCents int64
+	Payee       string
+	FITID       string // bank-assigned transaction ID, when the format provides one
+}
+
+// Parser converts raw file bytes into ParsedTransactions. Implementations
+// should be tolerant of minor formatting quirks rather than failing outright,
+// since bank exports are rarely perfectly well-formed.
+type Parser interface {
+	// Parse returns the transactions found in data, in file order.
+	Parse(data []byte) ([]ParsedTransaction, error)
+}
+
+// Format identifies which Parser to use.
+type Format string
+
+const (
+	FormatOFX Format = "ofx"
+	FormatQIF Format = "qif"
+	FormatCSV Format = "csv"
+)
+
+// ForFormat returns the Parser registered for format, or an error if none is
+// registered. Additional formats (MT940, CAMT.053, ...) can be added by
+// registering a new Parser here without touching callers.
+func ForFormat(format Format) (Parser, error) {
+	switch format {
+	case FormatOFX:
+		return OFXParser{}, nil
+	case FormatQIF:
+		return QIFParser{}, nil
+	case FormatCSV:
+		return CSVParser{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+}
+
+// DetectFormat guesses the import format from a filename extension.
+func DetectFormat(filename string) (Format, error) {
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".ofx"):
+		return FormatOFX, nil
+	case strings.HasSuffix(lower, ".qif"):
+		return FormatQIF, nil
+	case strings.HasSuffix(lower, ".csv"):
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("cannot determine import format from filename %q", filename)
+	}
+}
+
+// ContentHash computes a stable dedup key for a transaction that is about to
+// be imported into accountID. It intentionally ignores row order and
+// whitespace so the same statement imported twice produces the same hashes.
+func ContentHash(accountID uint, txn ParsedTransaction) string {
+	payee := strings.ToLower(strings.TrimSpace(txn.Payee))
+	raw := fmt.Sprintf("%d|%s|%d|%s|%s", accountID, txn.TxnDate.Format("2006-01-02"), txn.AmountCents, payee, txn.FITID)
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
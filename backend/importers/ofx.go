@@ -0,0 +1,66 @@
+package importers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OFXParser parses both classic SGML OFX (unclosed tags, e.g. <DTPOSTED>20240102)
+// and OFX/2 XML. It extracts each <STMTTRN>...</STMTTRN> block and pulls out
+// DTPOSTED, TRNAMT, NAME/PAYEE, and FITID with a tolerant regex rather than a
+// full SGML/XML parser, since real-world OFX exports are frequently invalid
+// XML.
+type OFXParser struct{}
+
+var stmtTrnRe = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+
+func ofxField(block, tag string) string {
+	re := regexp.MustCompile(`(?is)<` + tag + `>\s*([^<\r\n]*)`)
+	m := re.FindStringSubmatch(block)
+	if m == nil {
+		return ""
+	}
+	return strings.TrimSpace(m[1])
+}
+
+func (OFXParser) Parse(data []byte) ([]ParsedTransaction, error) {
+	var transactions []ParsedTransaction
+
+	for _, block := range stmtTrnRe.FindAllStringSubmatch(string(data), -1) {
+		body := block[1]
+
+		dateStr := ofxField(body, "DTPOSTED")
+		if dateStr == "" {
+			continue
+		}
+		if len(dateStr) > 8 {
+			dateStr = dateStr[:8] // strip time/timezone suffix, e.g. 20240102120000[0:GMT]
+		}
+		txnDate, err := time.Parse("20060102", dateStr)
+		if err != nil {
+			continue
+		}
+
+		amountStr := ofxField(body, "TRNAMT")
+		amount, err := strconv.ParseFloat(amountStr, 64)
+		if err != nil {
+			continue
+		}
+
+		payee := ofxField(body, "NAME")
+		if payee == "" {
+			payee = ofxField(body, "PAYEE")
+		}
+
+		transactions = append(transactions, ParsedTransaction{
+			TxnDate:     txnDate,
+			AmountCents: int64(amount*100 + sign(amount)*0.5),
+			Payee:       payee,
+			FITID:       ofxField(body, "FITID"),
+		})
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,66 @@
+package importers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QIFParser parses Quicken Interchange Format transaction records. Each
+// record is terminated by a line containing only "^"; fields of interest are
+// D (date), T (amount), P (payee).
+type QIFParser struct{}
+
+func (QIFParser) Parse(data []byte) ([]ParsedTransaction, error) {
+	var transactions []ParsedTransaction
+	var current ParsedTransaction
+	haveDate, haveAmount := false, false
+
+	for _, rawLine := range strings.Split(string(data), "\n") {
+		line := strings.TrimRight(rawLine, "\r")
+		if line == "" {
+			continue
+		}
+
+		switch {
+		case line == "^":
+			if haveDate && haveAmount {
+				transactions = append(transactions, current)
+			}
+			current = ParsedTransaction{}
+			haveDate, haveAmount = false, false
+		case strings.HasPrefix(line, "D"):
+			if d, err := parseQIFDate(line[1:]); err == nil {
+				current.TxnDate = d
+				haveDate = true
+			}
+		case strings.HasPrefix(line, "T") || strings.HasPrefix(line, "U"):
+			amountStr := strings.ReplaceAll(line[1:], ",", "")
+			if amount, err := strconv.ParseFloat(amountStr, 64); err == nil {
+				current.AmountCents = int64(amount*100 + sign(amount)*0.5)
+				haveAmount = true
+			}
+		case strings.HasPrefix(line, "P"):
+			current.Payee = line[1:]
+		case strings.HasPrefix(line, "N"):
+			current.FITID = line[1:]
+		}
+	}
+
+	// A file without a trailing "^" still has one pending record.
+	if haveDate && haveAmount {
+		transactions = append(transactions, current)
+	}
+
+	return transactions, nil
+}
+
+func parseQIFDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"1/2/2006", "1/2/'06", "01/02/2006", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Parse("1/2/2006", s)
+}
@@ -0,0 +1,80 @@
+package ynab
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// EncryptionKeyEnv names the env var holding the 32-byte, base64-encoded
+// AES-256 key used to encrypt a user's YNAB personal access token before
+// it's written to YnabConnection.EncryptedToken. Mirrors
+// services/psd2.EncryptionKeyEnv and controllers' MFA_ENCRYPTION_KEY - each
+// integration that stores a third-party secret at rest keeps its own key.
+const EncryptionKeyEnv = "YNAB_ENCRYPTION_KEY"
+
+// EncryptToken AES-GCM encrypts a YNAB personal access token with the key
+// configured via YNAB_ENCRYPTION_KEY and returns a base64-encoded
+// nonce||ciphertext blob safe to store in YnabConnection.EncryptedToken.
+func EncryptToken(token string) (string, error) {
+	gcm, err := cipherFromEnv()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("ynab: generate nonce: %w", err)
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, []byte(token), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptToken reverses EncryptToken.
+func DecryptToken(encoded string) (string, error) {
+	gcm, err := cipherFromEnv()
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.New("ynab: stored token is not valid base64")
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(raw) < nonceSize {
+		return "", errors.New("ynab: stored token is shorter than a nonce")
+	}
+
+	nonce, ciphertext := raw[:nonceSize], raw[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("ynab: decrypt token: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+func cipherFromEnv() (cipher.AEAD, error) {
+	encoded := os.Getenv(EncryptionKeyEnv)
+	if encoded == "" {
+		return nil, errors.New("ynab: " + EncryptionKeyEnv + " is not set")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("ynab: " + EncryptionKeyEnv + " must be base64-encoded")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("ynab: build AES cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
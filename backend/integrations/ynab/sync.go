@@ -0,0 +1,326 @@
+package ynab
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+)
+
+// milliunitsToDecimal converts a YNAB amount (milliunits: 1/1000 of the
+// budget's currency major unit, e.g. -12340 is -12.34) into the major-unit
+// decimal.Decimal this module's own Transaction.Amount/Account.CurrentBalance
+// are stored in.
+func milliunitsToDecimal(milliunits int64) decimal.Decimal {
+	return decimal.NewFromInt(milliunits).Div(decimal.NewFromInt(1000))
+}
+
+// Result summarizes one Sync call for the connect/sync endpoint to report
+// back to the caller.
+type Result struct {
+	AccountsUpserted    int
+	CategoriesUpserted  int
+	BudgetItemsUpserted int
+	TransactionsApplied int
+	ConflictsRaised     int
+}
+
+// Sync pulls conn's budget from YNAB and merges it into userID's own data:
+// accounts and categories are matched (by YnabAccountID/ExternalID) or
+// created on first sight, the current month's per-category planned amounts
+// are upserted into a Budget/BudgetItem pair (matched by YnabBudgetID), and
+// transactions are fetched delta-only since the connection's stored
+// last_knowledge_of_server cursor and upserted by YnabTransactionID. Every
+// row this creates or updates is tagged Source="ynab" - see
+// models.Transaction.Source's doc comment for the default conflict
+// behavior; conn.ConflictStrategy can instead keep the local edit
+// (YnabConflictLocalWins) or leave it untouched and record a YnabConflict
+// (YnabConflictManualReview) when a row changed both places since its last
+// sync. GetSpendSummary/GetBudgetProgress keep working over the merged
+// dataset because they query Amount/CategoryID regardless of Source.
+func Sync(userID uint, conn models.YnabConnection) (Result, error) {
+	var result Result
+
+	token, err := DecryptToken(conn.EncryptedToken)
+	if err != nil {
+		return result, err
+	}
+	client := NewClient(token)
+
+	accountIDMap, err := syncAccounts(userID, conn.BudgetID, client, &result)
+	if err != nil {
+		return result, fmt.Errorf("ynab: sync accounts: %w", err)
+	}
+
+	categoryIDMap, err := syncCategories(userID, client, &result)
+	if err != nil {
+		return result, fmt.Errorf("ynab: sync categories: %w", err)
+	}
+
+	if err := syncBudgets(userID, conn, client, categoryIDMap, &result); err != nil {
+		return result, fmt.Errorf("ynab: sync budgets: %w", err)
+	}
+
+	if err := syncTransactions(userID, conn, client, accountIDMap, categoryIDMap, &result); err != nil {
+		return result, fmt.Errorf("ynab: sync transactions: %w", err)
+	}
+
+	now := time.Now()
+	db.DB.Model(&models.YnabConnection{}).Where("id = ?", conn.ID).Update("last_sync_at", &now)
+
+	return result, nil
+}
+
+// syncAccounts upserts a models.Account for every account YNAB reports in
+// budgetID, matched by YnabAccountID, and returns a YNAB account id ->
+// internal Account ID map for syncTransactions to resolve AccountID against.
+func syncAccounts(userID uint, budgetID string, client *Client, result *Result) (map[string]uint, error) {
+	remoteAccounts, err := client.Accounts(budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	idMap := make(map[string]uint, len(remoteAccounts))
+	for _, ra := range remoteAccounts {
+		ra := ra
+		var account models.Account
+		found := db.DB.Where("user_id = ? AND ynab_account_id = ?", userID, ra.ID).First(&account).Error == nil
+
+		account.UserID = userID
+		account.Name = ra.Name
+		account.Type = models.AccountChecking
+		account.CurrentBalance = milliunitsToDecimal(ra.Balance)
+		account.YnabAccountID = &ra.ID
+
+		if found {
+			if err := db.DB.Save(&account).Error; err != nil {
+				return nil, err
+			}
+		} else {
+			if err := db.DB.Create(&account).Error; err != nil {
+				return nil, err
+			}
+		}
+		idMap[ra.ID] = account.ID
+		result.AccountsUpserted++
+	}
+	return idMap, nil
+}
+
+// syncCategories upserts a models.Category for every category YNAB reports,
+// matched by ExternalID (the round-trip field Category already exposes for
+// exactly this purpose - see its own doc comment), and returns a YNAB
+// category id -> internal Category ID map.
+func syncCategories(userID uint, client *Client, result *Result) (map[string]uint, error) {
+	remoteCategories, err := client.Categories("last-used")
+	if err != nil {
+		return nil, err
+	}
+
+	idMap := make(map[string]uint, len(remoteCategories))
+	for _, rc := range remoteCategories {
+		if rc.Deleted || rc.Hidden {
+			continue
+		}
+		rc := rc
+
+		var category models.Category
+		found := db.DB.Where("user_id = ? AND external_id = ?", userID, rc.ID).First(&category).Error == nil
+
+		category.UserID = userID
+		category.Name = rc.Name
+		category.ExternalID = rc.ID
+		if !found {
+			category.Kind = models.CategoryExpense
+		}
+
+		if found {
+			if err := db.DB.Save(&category).Error; err != nil {
+				return nil, err
+			}
+		} else {
+			if err := db.DB.Create(&category).Error; err != nil {
+				return nil, err
+			}
+		}
+		idMap[rc.ID] = category.ID
+		result.CategoriesUpserted++
+	}
+	return idMap, nil
+}
+
+// syncBudgets upserts a models.Budget (matched by YnabBudgetID) covering the
+// current calendar month, with one BudgetItem per category YNAB reports a
+// positive budgeted amount for, matched by (BudgetID, CategoryID).
+func syncBudgets(userID uint, conn models.YnabConnection, client *Client, categoryIDMap map[string]uint, result *Result) error {
+	monthCategories, err := client.CurrentMonthCategories(conn.BudgetID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, -1)
+
+	var budget models.Budget
+	found := db.DB.Where("ynab_budget_id = ?", conn.BudgetID).First(&budget).Error == nil
+
+	budget.UserID = userID
+	budget.PeriodStart = periodStart
+	budget.PeriodEnd = periodEnd
+	budget.YnabBudgetID = &conn.BudgetID
+
+	if found {
+		if err := db.DB.Save(&budget).Error; err != nil {
+			return err
+		}
+	} else {
+		if err := db.DB.Create(&budget).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, mc := range monthCategories {
+		categoryID, ok := categoryIDMap[mc.CategoryID]
+		if !ok || mc.Budgeted <= 0 {
+			continue
+		}
+
+		var item models.BudgetItem
+		itemFound := db.DB.Where("budget_id = ? AND category_id = ?", budget.ID, categoryID).First(&item).Error == nil
+
+		item.BudgetID = budget.ID
+		item.CategoryID = categoryID
+		item.PlannedAmount = milliunitsToDecimal(mc.Budgeted)
+
+		if itemFound {
+			if err := db.DB.Save(&item).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := db.DB.Create(&item).Error; err != nil {
+				return err
+			}
+		}
+		result.BudgetItemsUpserted++
+	}
+
+	return nil
+}
+
+// transactionSyncHash covers every field a YNAB edit might change, so a
+// YnabConnection with a non-default ConflictStrategy can tell "this row
+// still matches what we last synced" apart from "this row was edited
+// in-app since".
+func transactionSyncHash(amount decimal.Decimal, description string, categoryID *uint, txnDate time.Time) string {
+	categoryKey := ""
+	if categoryID != nil {
+		categoryKey = fmt.Sprintf("%d", *categoryID)
+	}
+	sum := sha256.Sum256([]byte(amount.String() + "|" + description + "|" + categoryKey + "|" + txnDate.Format("2006-01-02")))
+	return hex.EncodeToString(sum[:])
+}
+
+// syncTransactions fetches every transaction YNAB has added or changed
+// since conn's stored cursor, upserts each as a Source="ynab" Transaction
+// keyed by YnabTransactionID, and advances the cursor to what YNAB returned
+// - so the next sync only asks for what's changed since this one. A row
+// that was edited locally since its last sync is resolved per
+// conn.ConflictStrategy instead of being overwritten unconditionally.
+func syncTransactions(userID uint, conn models.YnabConnection, client *Client, accountIDMap, categoryIDMap map[string]uint, result *Result) error {
+	var state models.YnabSyncState
+	db.DB.Where("ynab_connection_id = ?", conn.ID).FirstOrCreate(&state, models.YnabSyncState{YnabConnectionID: conn.ID})
+
+	delta, err := client.TransactionsSince(conn.BudgetID, state.LastKnowledgeOfServer)
+	if err != nil {
+		return err
+	}
+
+	for _, rt := range delta.Transactions {
+		if rt.Deleted {
+			db.DB.Where("ynab_transaction_id = ?", rt.ID).Delete(&models.Transaction{})
+			continue
+		}
+
+		accountID, ok := accountIDMap[rt.AccountID]
+		if !ok {
+			continue
+		}
+		txnID := rt.ID
+
+		var category *uint
+		if id, ok := categoryIDMap[rt.CategoryID]; ok {
+			category = &id
+		}
+
+		txnDate, err := time.Parse("2006-01-02", rt.Date)
+		if err != nil {
+			continue
+		}
+
+		description := rt.PayeeName
+		if rt.Memo != "" {
+			description = rt.Memo
+		}
+
+		remoteAmount := milliunitsToDecimal(rt.Amount)
+
+		var transaction models.Transaction
+		found := db.DB.Where("ynab_transaction_id = ?", txnID).First(&transaction).Error == nil
+
+		// A conflict only applies to a row Sync has already written at
+		// least once (YnabSyncHash set) and whose current values have since
+		// drifted from that snapshot - i.e. an in-app edit, not just this
+		// sync's incoming remote change.
+		conflicted := found && conn.ConflictStrategy != models.YnabConflictServerWins &&
+			transaction.YnabSyncHash != "" &&
+			transaction.YnabSyncHash != transactionSyncHash(transaction.Amount, transaction.Description, transaction.CategoryID, transaction.TxnDate)
+
+		if conflicted && conn.ConflictStrategy == models.YnabConflictManualReview {
+			conflict := models.YnabConflict{
+				YnabConnectionID:  conn.ID,
+				TransactionID:     transaction.ID,
+				RemoteDescription: description,
+				RemoteAmount:      remoteAmount,
+				RemoteCategoryID:  category,
+				RemoteTxnDate:     txnDate,
+			}
+			if err := db.DB.Create(&conflict).Error; err != nil {
+				return err
+			}
+			result.ConflictsRaised++
+			continue
+		}
+		if conflicted && conn.ConflictStrategy == models.YnabConflictLocalWins {
+			continue
+		}
+
+		transaction.UserID = userID
+		transaction.AccountID = accountID
+		transaction.CategoryID = category
+		transaction.Amount = remoteAmount
+		transaction.Description = description
+		transaction.TxnDate = txnDate
+		transaction.Source = "ynab"
+		transaction.YnabTransactionID = &txnID
+		transaction.YnabSyncHash = transactionSyncHash(remoteAmount, description, category, txnDate)
+
+		if found {
+			if err := db.DB.Save(&transaction).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := db.DB.Create(&transaction).Error; err != nil {
+				return err
+			}
+		}
+		result.TransactionsApplied++
+	}
+
+	return db.DB.Model(&models.YnabSyncState{}).Where("id = ?", state.ID).Update("last_knowledge_of_server", delta.ServerKnowledgeOfSet).Error
+}
@@ -5,14 +5,29 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"Personal-Finance-Tracker-backend/aggregators"
+	_ "Personal-Finance-Tracker-backend/aggregators/gocardless"
+	_ "Personal-Finance-Tracker-backend/aggregators/plaid"
+	_ "Personal-Finance-Tracker-backend/aggregators/saltedge"
+	_ "Personal-Finance-Tracker-backend/aggregators/truelayer"
+	"Personal-Finance-Tracker-backend/config"
 	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/controllers/aidriver"
 	"Personal-Finance-Tracker-backend/db"
 	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/migrations"
 	"Personal-Finance-Tracker-backend/redis"
 	"Personal-Finance-Tracker-backend/routes"
 	"Personal-Finance-Tracker-backend/seed"
+	"Personal-Finance-Tracker-backend/services/ai"
+	"Personal-Finance-Tracker-backend/services/anomaly"
+	"Personal-Finance-Tracker-backend/services/banksync"
+	"Personal-Finance-Tracker-backend/services/fx"
+	"Personal-Finance-Tracker-backend/services/reports/chore"
 	"Personal-Finance-Tracker-backend/utils"
 
 	"github.com/gin-contrib/cors"
@@ -22,6 +37,13 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if err := seed.RunCLI(os.Args[2:]); err != nil {
+			log.Fatalf("seed: %v", err)
+		}
+		return
+	}
+
 	// Initialize structured logger
 	if err := utils.InitLogger(); err != nil {
 		panic("Failed to initialize logger: " + err.Error())
@@ -31,6 +53,13 @@ func main() {
 	logger := utils.Logger
 	logger.Info("Starting Personal Finance Tracker API")
 
+	// Initialize the dynamic config provider (env/file/etcd, selected via
+	// CONFIG_BACKEND) that admin bootstrap and feature flags read from.
+	if err := config.Init(); err != nil {
+		logger.Warn("Config provider init failed, falling back to env vars", zap.Error(err))
+	}
+	controllers.LogActivePasswordPolicy()
+
 	// Initialize DB
 	db.ConnectDatabase()
 	if err := redis.InitRedis(); err != nil {
@@ -53,8 +82,170 @@ func main() {
 	} else {
 		logger.Info("Plaid credentials not configured, Plaid features disabled")
 	}
-	// Seed demo data
-	seed.SeedDemoData(db.DB)
+
+	// Activate the bank aggregators (see aggregators/ and its plaid/
+	// gocardless/truelayer/saltedge sub-packages) that back the
+	// provider-agnostic /api/banks/link-session and /api/banks/exchange-token
+	// routes. Plaid is activated with the same credentials as InitPlaidClient
+	// above; gocardless/truelayer/saltedge are stubs today but still Activate
+	// so BankConnection.Provider round-trips ahead of their real
+	// implementations landing.
+	if plaidClientID != "" && plaidSecret != "" {
+		if err := aggregators.Activate("plaid", aggregators.Config{ClientID: plaidClientID, Secret: plaidSecret, Environment: plaidEnv}); err != nil {
+			logger.Warn("Failed to activate plaid aggregator", zap.Error(err))
+		}
+	}
+	if err := aggregators.Activate("gocardless", aggregators.Config{
+		ClientID: os.Getenv("GOCARDLESS_SECRET_ID"),
+		Secret:   os.Getenv("GOCARDLESS_SECRET_KEY"),
+	}); err != nil {
+		logger.Warn("Failed to activate gocardless aggregator", zap.Error(err))
+	}
+	if err := aggregators.Activate("truelayer", aggregators.Config{
+		ClientID: os.Getenv("TRUELAYER_CLIENT_ID"),
+		Secret:   os.Getenv("TRUELAYER_CLIENT_SECRET"),
+	}); err != nil {
+		logger.Warn("Failed to activate truelayer aggregator", zap.Error(err))
+	}
+	if err := aggregators.Activate("saltedge", aggregators.Config{
+		ClientID: os.Getenv("SALTEDGE_APP_ID"),
+		Secret:   os.Getenv("SALTEDGE_SECRET"),
+	}); err != nil {
+		logger.Warn("Failed to activate saltedge aggregator", zap.Error(err))
+	}
+
+	// Initialize OAuth2/OIDC social login providers (Google/GitHub, plus a
+	// generic OIDC provider for e.g. a company identity provider), each
+	// only enabled if its client ID/secret are configured
+	controllers.InitGoogleOAuth(os.Getenv("GOOGLE_CLIENT_ID"), os.Getenv("GOOGLE_CLIENT_SECRET"), os.Getenv("GOOGLE_REDIRECT_URL"))
+	controllers.InitGitHubOAuth(os.Getenv("GITHUB_CLIENT_ID"), os.Getenv("GITHUB_CLIENT_SECRET"), os.Getenv("GITHUB_REDIRECT_URL"))
+	oidcProviderName := os.Getenv("OIDC_PROVIDER_NAME")
+	if oidcProviderName == "" {
+		oidcProviderName = "oidc"
+	}
+	if err := controllers.InitOIDCProvider(oidcProviderName, os.Getenv("OIDC_ISSUER_URL"), os.Getenv("OIDC_CLIENT_ID"), os.Getenv("OIDC_CLIENT_SECRET"), os.Getenv("OIDC_REDIRECT_URL")); err != nil {
+		logger.Warn("Failed to initialize generic OIDC provider", zap.Error(err))
+	}
+
+	// Bootstrap the default admin and seed demo data. Each is a named,
+	// versioned seeder recorded in seed_history, so a restart doesn't
+	// re-run work that already happened.
+	if _, err := migrations.RunSeeder(db.DB, seed.AdminSeeder{}, false); err != nil {
+		logger.Warn("Default admin seeding failed", zap.Error(err))
+	}
+	if _, err := migrations.RunSeeder(db.DB, seed.SystemCategorySeeder{}, false); err != nil {
+		logger.Warn("System category seeding failed", zap.Error(err))
+	}
+	if config.IsEnabled("seed.demo_enabled", true) {
+		demoSeeder := seed.DemoDataSeeder{Scenario: seed.BuiltinScenarios["presentation"]}
+		if _, err := migrations.RunSeeder(db.DB, demoSeeder, false); err != nil {
+			logger.Warn("Demo data seeding failed", zap.Error(err))
+		}
+	} else {
+		logger.Info("Demo data seeding disabled via seed.demo_enabled")
+	}
+
+	// Start background scheduler for recurring transactions
+	controllers.StartRecurringScheduler(1 * time.Hour)
+
+	// Start background worker that hard-deletes soft-deleted admin
+	// resources past their grace period
+	controllers.StartPurgeScheduler(1 * time.Hour)
+
+	// Recover each bank connection's circuit breaker state from its recent
+	// BankSyncLog rows before the scheduler's first tick, so a restart
+	// doesn't silently re-close a breaker that was open when we stopped.
+	if err := banksync.Recover(); err != nil {
+		logger.Warn("Failed to recover bank sync circuit breaker state", zap.Error(err))
+	}
+
+	// Start background worker that re-syncs due PSD2 bank connections and
+	// expires ones whose consent has lapsed
+	controllers.StartBankSyncScheduler(1 * time.Hour)
+
+	// Start background worker that emits reminder rows for due-but-unpaid
+	// bills (see controllers/bill_scheduler.go)
+	controllers.StartBillScheduler(1 * time.Hour)
+
+	// Start background worker that materializes each BudgetTemplate's next
+	// period once its last Budget's period has ended
+	controllers.StartBudgetRolloverScheduler(1 * time.Hour)
+
+	// Start background worker that persists today's NetWorthSnapshot for
+	// every user once a day (see controllers/networth_scheduler.go)
+	controllers.StartNetWorthSnapshotScheduler(24 * time.Hour)
+
+	// Start background worker that recomputes every account's
+	// current_balance_cents from its transactions, correcting any drift a
+	// write path introduced by forgetting to call UpdateAccountBalance (see
+	// controllers/balance_reconciliation_scheduler.go)
+	controllers.StartBalanceReconciler(6 * time.Hour)
+
+	// Start background worker that emails each opted-in user's
+	// weekly/monthly spend/cashflow/budget digest (see
+	// controllers/digest_scheduler.go and models.NotificationPreference)
+	controllers.StartDigestScheduler(1 * time.Hour)
+
+	// Start background workers that re-sync due Plaid connections and flag
+	// about-to-lapse consents NeedsReauth (see
+	// controllers/plaid_sync_scheduler.go); intervals configurable via
+	// PLAID_SYNC_INTERVAL_MINUTES/PLAID_CONSENT_SCAN_INTERVAL_MINUTES.
+	controllers.StartPlaidSyncScheduler(controllers.PlaidSyncInterval(), controllers.PlaidConsentScanInterval())
+
+	// Start the background worker that claims and delivers due
+	// ReportSchedule rows every minute (see services/reports/chore) -
+	// ticking per-minute rather than per-hour like the other schedulers
+	// above, since Cron expressions can legitimately fire that often.
+	chore.Start(1 * time.Minute)
+
+	// Start the background worker that republishes the DB connection
+	// pool's sql.DB.Stats() as Prometheus gauges (see
+	// controllers/db_pool_metrics_scheduler.go)
+	controllers.StartDBPoolMetricsScheduler(15 * time.Second)
+
+	// Start the background worker that appends auth audit events (see
+	// middleware/auth_audit.go) to the hash chain, so register/login/etc.
+	// don't block on the write. Drained on shutdown below.
+	middleware.StartAuthAuditWorker()
+	defer middleware.StopAuthAuditWorker()
+
+	// Start the background worker that keeps services/fx's rate cache warm
+	// for every currency pair in active use, so admin requests with
+	// ?display_currency= don't pay the provider's fetch latency inline.
+	fx.StartRateRefresher()
+	defer fx.StopRateRefresher()
+
+	// Start the background worker that delivers HMAC-signed
+	// services/anomaly webhook payloads, so a slow or unreachable endpoint
+	// can't block transaction ingestion. Drained on shutdown below.
+	anomaly.StartWebhookWorker()
+	defer anomaly.StopWebhookWorker()
+
+	// Start the background worker that applies bank-initiated webhook
+	// events (see controllers/bank_webhook.go) asynchronously, so
+	// ReceiveBankWebhook can answer well within the provider's retry
+	// window instead of blocking on a consent update or a targeted sync.
+	controllers.StartBankWebhookWorker()
+	defer controllers.StopBankWebhookWorker()
+
+	// Select the AI backend via AI_DRIVER ("local" (default) or "sidecar" -
+	// both run forecasting in-process, see aidriver.localDriver's doc
+	// comment - "openai", or "ollama") and route GetBudgetPrediction /
+	// GetSpendingPatterns through it. AI_DRIVER_API_KEY/AI_DRIVER_BASE_URL
+	// configure the openai/ollama drivers; per-user overrides live in
+	// models.UserAISetting and are resolved per-request by driverAdapter.
+	aiDriverName := config.GetOr("ai.driver", "local")
+	aiDriver, err := aidriver.New(aiDriverName, aidriver.Config{
+		APIKey:  config.GetOr("ai.driver_api_key", ""),
+		BaseURL: config.GetOr("ai.driver_base_url", ""),
+	})
+	if err != nil {
+		logger.Warn("AI driver init failed, falling back to local", zap.String("driver", aiDriverName), zap.Error(err))
+		aiDriver, _ = aidriver.New("local", aidriver.Config{})
+	}
+	adapter := controllers.NewDriverAdapter(aiDriver)
+	controllers.AIPredictor = func() ai.Predictor { return adapter }
+	controllers.AIPatternsAnalyzer = func() ai.PatternsAnalyzer { return adapter }
 
 	// Set Gin to release mode for production
 	gin.SetMode(gin.ReleaseMode)
@@ -64,7 +255,7 @@ func main() {
 
 	// Add custom middleware
 	r.Use(middleware.RecoveryMiddleware(logger))
-	r.Use(middleware.LoggingMiddleware(logger))
+	r.Use(middleware.RequestLoggerMiddleware(logger))
 	r.Use(middleware.MetricsMiddleware())
 
 	// CORS
@@ -83,7 +274,11 @@ func main() {
 	// Health check endpoints (no auth required)
 	r.GET("/health", controllers.HealthCheck)
 	r.GET("/health/detailed", func(c *gin.Context) {
-		controllers.DetailedHealthCheck(c, logger)
+		requestLogger := logger
+		if l, ok := c.Get("logger"); ok {
+			requestLogger = l.(*zap.Logger)
+		}
+		controllers.DetailedHealthCheck(c, requestLogger)
 	})
 	r.GET("/health/ready", controllers.ReadinessCheck)
 	r.GET("/health/live", controllers.LivenessCheck)
@@ -91,10 +286,55 @@ func main() {
 	// Auth endpoints (no auth required)
 	auth := r.Group("/auth")
 	{
-		auth.POST("/register", controllers.Register)
-		auth.POST("/login", controllers.Login)
+		auth.POST("/register", middleware.RateLimit(middleware.IPRouteKey, middleware.RegistrationRate), controllers.Register)
+		auth.POST("/login", middleware.RateLimit(middleware.IPRouteKey, middleware.LoginRate), controllers.Login)
+		auth.POST("/login/mfa", controllers.LoginMFA)
+		auth.POST("/refresh", controllers.RefreshSession)
+		auth.POST("/logout", controllers.Logout)
+
+		// These act on the caller's account, so they require the access
+		// token issued alongside the refresh token being presented.
+		auth.POST("/logout-all", controllers.AuthMiddleware(), controllers.LogoutAll)
+		auth.GET("/sessions", controllers.AuthMiddleware(), controllers.GetSessions)
+	}
+
+	// OAuth2 social login (Google/GitHub) - no auth required, these are the
+	// routes that establish it
+	oauthRoutes := r.Group("/oauth")
+	{
+		oauthRoutes.GET("/:provider/login", controllers.OAuthLogin)
+		oauthRoutes.GET("/:provider/callback", controllers.OAuthCallback)
 	}
 
+	// Self-service password reset (no auth required - the reset token itself
+	// is the credential). /forgot is additionally rate-limited per-IP here
+	// and per-email inside the handler.
+	password := r.Group("/password")
+	{
+		password.POST("/forgot", middleware.RateLimit(func(c *gin.Context) string {
+			return "password_forgot:" + c.ClientIP()
+		}, middleware.PasswordResetRequestRate), controllers.ForgotPassword)
+		password.POST("/reset", controllers.ResetPassword)
+	}
+
+	// Plaid webhooks (no auth - Plaid calls this unauthenticated and signs
+	// the payload instead via the Plaid-Verification header, see
+	// controllers.PlaidWebhook). Registered at the same path CreateLinkToken
+	// hands Plaid via plaidWebhookBaseURL, so it must stay outside the /api
+	// group's AuthMiddleware.
+	r.POST("/api/plaid/webhook", controllers.PlaidWebhook)
+
+	// Plaid's OAuth institution redirect lands here (no auth - the browser
+	// arrives with only the oauth_state_id query param, not a session token;
+	// see controllers.PlaidOAuthCallback).
+	r.GET("/api/plaid/oauth/callback", controllers.PlaidOAuthCallback)
+
+	// Bank-initiated push webhooks (no auth - each provider signs its own
+	// payload instead, see controllers.ReceiveBankWebhook). Keyed by
+	// :provider rather than one path per ASPSP so a new bank doesn't need a
+	// new route, just a BankConnection.Metadata["webhook_secret"].
+	r.POST("/api/banks/webhooks/:provider", controllers.ReceiveBankWebhook)
+
 	// API routes (protected)
 	api := r.Group("/api")
 	api.Use(controllers.AuthMiddleware())
@@ -161,6 +401,8 @@ func main() {
 			isAPIRoute = true
 		} else if len(path) >= 10 && path[:10] == "/redistest" {
 			isAPIRoute = true
+		} else if len(path) >= 6 && path[:6] == "/oauth" {
+			isAPIRoute = true
 		}
 
 		if isAPIRoute {
@@ -175,8 +417,25 @@ func main() {
 		c.File("./frontend/build/index.html")
 	})
 
-	logger.Info("Server starting on port 8080")
-	if err := r.Run(":8080"); err != nil {
-		logger.Fatal("Failed to start server", zap.Error(err))
+	srv := &http.Server{Addr: ":8080", Handler: r}
+
+	go func() {
+		logger.Info("Server starting on port 8080")
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("Failed to start server", zap.Error(err))
+		}
+	}()
+
+	// Wait for SIGINT/SIGTERM, then stop accepting new connections before
+	// the deferred StopAuthAuditWorker drains whatever's still queued.
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Server shutdown did not complete cleanly", zap.Error(err))
 	}
 }
@@ -10,10 +10,15 @@ import (
 	jwt "github.com/golang-jwt/jwt/v5"
 )
 
-// RequireAdmin middleware checks if the user has admin role
-func RequireAdmin() gin.HandlerFunc {
+// RequirePermission checks that the authenticated user's role has been
+// granted perm in role_permissions, loading the acting user into context as
+// "adminUser" for handlers that need it (e.g. to stop an admin deleting
+// themselves). Each admin route is wired to the one permission it needs, in
+// place of the old blanket RequireAdmin gate. The lookup is scoped (not
+// Unscoped), so an admin whose own account is pending deletion (see
+// controllers.DeleteUserAccount) is rejected here too.
+func RequirePermission(perm models.Permission) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
-		// First check if user is authenticated
 		claims, exists := c.Get("user")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
@@ -24,27 +29,50 @@ func RequireAdmin() gin.HandlerFunc {
 		jwtClaims := claims.(jwt.MapClaims)
 		userID := uint(jwtClaims["sub"].(float64))
 
-		// Check role from JWT token first (if available)
-		if role, ok := jwtClaims["role"]; ok {
-			if role.(string) == string(models.UserRoleAdmin) {
-				// Get user from database for context
-				var user models.User
-				if err := db.DB.Where("id = ?", userID).First(&user).Error; err != nil {
-					c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
-					c.Abort()
-					return
-				}
-				c.Set("adminUser", user)
-				c.Next()
-				return
-			} else {
-				c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
-				c.Abort()
-				return
-			}
+		var user models.User
+		if err := db.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+			c.Abort()
+			return
+		}
+
+		if !RoleHasPermission(user.Role, perm) {
+			RecordUnauthorizedAccess(c, userID, user.Username)
+			c.JSON(http.StatusForbidden, gin.H{"error": "missing required permission: " + string(perm)})
+			c.Abort()
+			return
+		}
+
+		c.Set("adminUser", user)
+		c.Next()
+	})
+}
+
+// RoleHasPermission reports whether role has been granted perm, from
+// rolePermissionSet's 60s cache rather than querying role_permissions on
+// every call.
+func RoleHasPermission(role models.UserRole, perm models.Permission) bool {
+	return rolePermissionSet(role)[perm]
+}
+
+// RequireScope checks that the authenticated user's role carries perm (see
+// RequirePermission) OR perm has been granted to them directly via
+// User.Scopes, loading the acting user into context as "adminUser" the
+// same way RequirePermission does. Use this instead of RequirePermission
+// for a route that an individual account should be able to reach without
+// being promoted to a whole new role.
+func RequireScope(perm models.Permission) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		claims, exists := c.Get("user")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
 		}
 
-		// Fallback: Get user from database to check role (for older tokens without role)
+		jwtClaims := claims.(jwt.MapClaims)
+		userID := uint(jwtClaims["sub"].(float64))
+
 		var user models.User
 		if err := db.DB.Where("id = ?", userID).First(&user).Error; err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
@@ -52,15 +80,25 @@ func RequireAdmin() gin.HandlerFunc {
 			return
 		}
 
-		// Check if user has admin role
-		if user.Role != models.UserRoleAdmin {
-			c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+		if !RoleHasPermission(user.Role, perm) && !userHasScope(user, perm) {
+			RecordUnauthorizedAccess(c, userID, user.Username)
+			c.JSON(http.StatusForbidden, gin.H{"error": "missing required scope: " + string(perm)})
 			c.Abort()
 			return
 		}
 
-		// Store user object in context for later use
 		c.Set("adminUser", user)
 		c.Next()
 	})
 }
+
+// userHasScope reports whether perm was granted to user directly via
+// User.Scopes, independent of their role.
+func userHasScope(user models.User, perm models.Permission) bool {
+	for _, scope := range user.Scopes {
+		if models.Permission(scope) == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuditLog records every request handled by the admin route group it is
+// attached to: actor, target resource, method, endpoint, request body (for
+// mutations), remote IP, user agent and timestamp. It must run after
+// AuthMiddleware/RequirePermission so the "user" claims are already in context.
+func AuditLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var bodyBytes []byte
+		if c.Request.Body != nil && requestHasBody(c.Request.Method) {
+			bodyBytes, _ = io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		}
+
+		c.Next()
+
+		claims, exists := c.Get("user")
+		if !exists {
+			return
+		}
+		jwtClaims := claims.(jwt.MapClaims)
+		userID := uint(jwtClaims["sub"].(float64))
+		username, _ := jwtClaims["name"].(string)
+
+		targetType, targetID := auditTarget(c)
+
+		recordAudit(auditRecordInput{
+			ActorUserID:   userID,
+			ActorUsername: username,
+			Action:        c.Request.Method + " " + c.FullPath(),
+			TargetType:    targetType,
+			TargetID:      targetID,
+			Method:        c.Request.Method,
+			Endpoint:      c.FullPath(),
+			RequestDiff:   string(bodyBytes),
+			RemoteIP:      c.ClientIP(),
+			UserAgent:     c.Request.UserAgent(),
+		})
+	}
+}
+
+// RecordUnauthorizedAccess emits an audit record for a rejected admin
+// authorization attempt, so security review can spot enumeration of the
+// admin API by non-admin or forged tokens.
+func RecordUnauthorizedAccess(c *gin.Context, userID uint, username string) {
+	targetType, targetID := auditTarget(c)
+	recordAudit(auditRecordInput{
+		ActorUserID:   userID,
+		ActorUsername: username,
+		Action:        c.Request.Method + " " + c.FullPath(),
+		TargetType:    targetType,
+		TargetID:      targetID,
+		Method:        c.Request.Method,
+		Endpoint:      c.FullPath(),
+		RemoteIP:      c.ClientIP(),
+		UserAgent:     c.Request.UserAgent(),
+		Reason:        "unauthorized_access",
+	})
+}
+
+func requestHasBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete
+}
+
+// auditTarget derives a coarse resource type and ID from the route being
+// handled, e.g. "/api/admin/users/:id" -> ("user", "42").
+func auditTarget(c *gin.Context) (targetType string, targetID string) {
+	path := c.FullPath()
+	switch {
+	case strings.Contains(path, "/admin/users"):
+		targetType = "user"
+	case strings.Contains(path, "/admin/transactions"):
+		targetType = "transaction"
+	case strings.Contains(path, "/admin/accounts"):
+		targetType = "account"
+	case strings.Contains(path, "/admin/categories"):
+		targetType = "category"
+	case strings.Contains(path, "/admin/budgets"):
+		targetType = "budget"
+	case strings.Contains(path, "/admin/audit-logs"):
+		targetType = "audit_log"
+	case strings.Contains(path, "/admin/dashboard-stats"):
+		targetType = "dashboard"
+	default:
+		targetType = "unknown"
+	}
+	targetID = c.Param("id")
+	return targetType, targetID
+}
+
+type auditRecordInput struct {
+	ActorUserID   uint
+	ActorUsername string
+	Action        string
+	TargetType    string
+	TargetID      string
+	Method        string
+	Endpoint      string
+	RequestDiff   string
+	RemoteIP      string
+	UserAgent     string
+	Reason        string
+}
+
+// recordAudit appends a new entry to the audit hash chain. The chain is keyed
+// off the single most recent row, so PrevHash always links to whatever the
+// last write was (across all actors), forming one authority-wide chain. The
+// read of that row, the hash, and the insert all happen inside one DB
+// transaction with the row locked (the same SELECT ... FOR UPDATE pattern
+// controllers.MaterializeRecurringRule uses for its own invariant), so two
+// concurrent admin requests can't both read the same prev row and fork the
+// chain.
+func recordAudit(in auditRecordInput) {
+	record := models.AuditLog{
+		ActorUserID:   in.ActorUserID,
+		ActorUsername: in.ActorUsername,
+		Action:        in.Action,
+		TargetType:    in.TargetType,
+		TargetID:      in.TargetID,
+		Method:        in.Method,
+		Endpoint:      in.Endpoint,
+		RequestDiff:   in.RequestDiff,
+		RemoteIP:      in.RemoteIP,
+		UserAgent:     in.UserAgent,
+		Reason:        in.Reason,
+		CreatedAt:     time.Now(),
+	}
+
+	db.DB.Transaction(func(tx *gorm.DB) error {
+		query := tx
+		if tx.Dialector.Name() == "postgres" {
+			// SQLite (used in tests) has no SELECT ... FOR UPDATE syntax; its
+			// own transaction locking already makes this step atomic.
+			query = tx.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		var prev models.AuditLog
+		if err := query.Order("id DESC").First(&prev).Error; err == nil {
+			record.PrevHash = prev.Hash
+		}
+		record.Hash = ComputeAuditHash(record.PrevHash, record)
+
+		return tx.Create(&record).Error
+	})
+}
+
+// ComputeAuditHash computes sha256(prevHash || canonical_json(record without
+// ID and Hash)). ID is left out because it is only assigned on insert, after
+// the hash is taken. It is exported so the audit-log verification endpoint can replay
+// the chain without duplicating the hashing rule.
+func ComputeAuditHash(prevHash string, record models.AuditLog) string {
+	record.ID = 0
+	record.Hash = ""
+	payload, _ := json.Marshal(record)
+	sum := sha256.Sum256(append([]byte(prevHash), payload...))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+
+	"go.uber.org/zap"
+)
+
+// authAuditBufferSize bounds how many pending events the worker will queue
+// before RecordAuthAuditEvent starts dropping them rather than blocking the
+// auth endpoint that called it.
+const authAuditBufferSize = 256
+
+// AuthAuditEventInput is one occurrence to append to the auth audit chain.
+type AuthAuditEventInput struct {
+	UserID    uint
+	EventType string // e.g. "register", "login", "logout", "refresh", "2fa_verify", "password_change", "role_change"
+	Outcome   string // "success" or "failure"
+	Details   map[string]interface{}
+	IP        string
+	UserAgent string
+}
+
+var (
+	authAuditCh     chan AuthAuditEventInput
+	authAuditWg     sync.WaitGroup
+	authAuditOnce   sync.Once
+	authAuditClosed bool
+	authAuditMu     sync.Mutex
+)
+
+// StartAuthAuditWorker starts the background goroutine that appends queued
+// auth audit events to the hash chain. It is a no-op if already started -
+// safe to call once from main at startup.
+func StartAuthAuditWorker() {
+	authAuditOnce.Do(func() {
+		authAuditCh = make(chan AuthAuditEventInput, authAuditBufferSize)
+		authAuditWg.Add(1)
+		go authAuditWorkerLoop()
+	})
+}
+
+// StopAuthAuditWorker closes the event channel and blocks until the worker
+// has drained every event still queued, so a shutdown doesn't lose the tail
+// of the audit trail. Call it once, after the HTTP server has stopped
+// accepting new requests.
+func StopAuthAuditWorker() {
+	authAuditMu.Lock()
+	if authAuditCh == nil || authAuditClosed {
+		authAuditMu.Unlock()
+		return
+	}
+	authAuditClosed = true
+	close(authAuditCh)
+	authAuditMu.Unlock()
+
+	authAuditWg.Wait()
+}
+
+func authAuditWorkerLoop() {
+	defer authAuditWg.Done()
+	for in := range authAuditCh {
+		writeAuthAuditEvent(in)
+	}
+}
+
+// RecordAuthAuditEvent enqueues in for the background worker to append to
+// the chain. It never blocks the caller: if the worker hasn't been started
+// (e.g. in tests) the event is written inline instead, and if the buffer is
+// full the event is dropped and logged so a slow DB can't back up auth
+// endpoints.
+func RecordAuthAuditEvent(in AuthAuditEventInput) {
+	authAuditMu.Lock()
+	ch := authAuditCh
+	closed := authAuditClosed
+	authAuditMu.Unlock()
+
+	if ch == nil || closed {
+		writeAuthAuditEvent(in)
+		return
+	}
+
+	select {
+	case ch <- in:
+	default:
+		utils.Logger.Warn("Auth audit buffer full, dropping event",
+			zap.String("event_type", in.EventType),
+			zap.Uint("user_id", in.UserID),
+		)
+	}
+}
+
+// writeAuthAuditEvent appends one record to the hash chain, keyed off the
+// single most recent row (across all users), mirroring recordAudit's
+// approach for the admin AuditLog chain.
+func writeAuthAuditEvent(in AuthAuditEventInput) {
+	details, err := json.Marshal(in.Details)
+	if err != nil {
+		details = []byte("{}")
+	}
+
+	record := models.AuthAuditEvent{
+		UserID:    in.UserID,
+		EventType: in.EventType,
+		Outcome:   in.Outcome,
+		Details:   string(details),
+		IP:        in.IP,
+		UserAgent: in.UserAgent,
+		Timestamp: time.Now(),
+	}
+
+	var prev models.AuthAuditEvent
+	if err := db.DB.Order("id DESC").First(&prev).Error; err == nil {
+		record.PrevHash = prev.Hash
+	}
+	record.Hash = ComputeAuthAuditHash(record.PrevHash, record)
+
+	if err := db.DB.Create(&record).Error; err != nil {
+		utils.Logger.Error("Failed to write auth audit event", zap.Error(err), zap.String("event_type", in.EventType))
+	}
+}
+
+// ComputeAuthAuditHash computes sha256(prevHash || canonical_json(event
+// without ID and Hash)). ID is left out because it is only assigned on
+// insert, after the hash is taken. Exported so GET /api/admin/audit/verify can replay the
+// chain without duplicating the hashing rule.
+func ComputeAuthAuditHash(prevHash string, event models.AuthAuditEvent) string {
+	event.ID = 0
+	event.Hash = ""
+	payload, _ := json.Marshal(event)
+	sum := sha256.Sum256(append([]byte(prevHash), payload...))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+const idempotencyKeyTTL = 24 * time.Hour
+
+// responseRecorder buffers the handler's response so it can be persisted
+// alongside the idempotency key after a successful write.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// IdempotencyMiddleware honors an Idempotency-Key header on mutating
+// requests. It claims the key by inserting a pending IdempotencyKey row
+// (unique on user_id+key) before the handler runs, so two concurrent
+// requests with the same key can't both execute it: the loser's insert hits
+// the unique index and is turned away before ever reaching c.Next(), rather
+// than both running the handler and only racing on the cache write
+// afterward. On replay with the same key and an identical request body, it
+// returns the cached response without re-executing the handler. On replay
+// with the same key but a different body, it returns 409 Conflict.
+func IdempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		claims, exists := c.Get("user")
+		if !exists {
+			c.Next()
+			return
+		}
+		userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+
+		bodyBytes, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+
+		hashBytes := sha256.Sum256(bodyBytes)
+		requestHash := hex.EncodeToString(hashBytes[:])
+
+		// Claim the key up front. ResponseStatus stays 0 until the handler
+		// finishes, so a row with ResponseStatus 0 that someone else reads
+		// means "still in flight", not "cached empty response".
+		claim := models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+			ExpiresAt:   time.Now().Add(idempotencyKeyTTL),
+		}
+		if err := db.DB.Create(&claim).Error; err != nil {
+			var existing models.IdempotencyKey
+			if db.DB.Where("user_id = ? AND key = ?", userID, key).First(&existing).Error != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to claim idempotency key"})
+				c.Abort()
+				return
+			}
+			if existing.RequestHash != requestHash {
+				c.JSON(http.StatusConflict, gin.H{"error": "idempotency key reused with a different request body"})
+				c.Abort()
+				return
+			}
+			if existing.ResponseStatus == 0 {
+				c.JSON(http.StatusConflict, gin.H{"error": "a request with this idempotency key is already in progress"})
+				c.Abort()
+				return
+			}
+			c.Data(existing.ResponseStatus, "application/json", []byte(existing.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = recorder
+
+		c.Next()
+
+		// Only cache successful writes; a failed request should be retryable
+		// with the same key, so release the claim instead of leaving it
+		// stuck at ResponseStatus 0 forever.
+		if recorder.status >= 200 && recorder.status < 300 {
+			db.DB.Model(&claim).Updates(map[string]interface{}{
+				"response_status": recorder.status,
+				"response_body":   recorder.body.String(),
+			})
+		} else {
+			db.DB.Delete(&claim)
+		}
+	}
+}
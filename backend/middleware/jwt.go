@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// jwtSecret is shared by every package that mints or verifies a session
+// token (controllers.GenerateToken, RequireAccessToken, ...), so it lives
+// here rather than in controllers - middleware has no dependency back on
+// controllers, but controllers already depends on middleware.
+var jwtSecret []byte
+
+// JWTSecret returns the HMAC key tokens are signed/verified with, reading
+// JWT_SECRET once and falling back to an ephemeral per-process key (not for
+// production) if it isn't set.
+func JWTSecret() []byte {
+	if jwtSecret != nil {
+		return jwtSecret
+	}
+	s := os.Getenv("JWT_SECRET")
+	if s == "" {
+		tmp := make([]byte, 32)
+		_, _ = rand.Read(tmp)
+		s = base64.RawStdEncoding.EncodeToString(tmp)
+	}
+	jwtSecret = []byte(s)
+	return jwtSecret
+}
+
+// ParseToken validates tokenStr's signature against JWTSecret and returns
+// the parsed token (check token.Valid and its claims before trusting it).
+func ParseToken(tokenStr string) (*jwt.Token, error) {
+	return jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return JWTSecret(), nil
+	})
+}
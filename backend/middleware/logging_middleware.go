@@ -1,72 +1,109 @@
 package middleware
 
 import (
-    "time"
+	"time"
 
-    "github.com/gin-gonic/gin"
-    "go.uber.org/zap"
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
-// LoggingMiddleware logs HTTP requests with structured logging
-func LoggingMiddleware(logger *zap.Logger) gin.HandlerFunc {
-    return func(c *gin.Context) {
-        start := time.Now()
-        path := c.Request.URL.Path
-        query := c.Request.URL.RawQuery
+// RequestIDHeader is the header a correlation ID is read from and echoed
+// back on, matching the X-Request-ID convention controllers/psd2.go already
+// uses for outbound bank calls.
+const RequestIDHeader = "X-Request-ID"
 
-        // Process request
-        c.Next()
+// RequestLoggerMiddleware assigns a per-request correlation ID - the
+// caller's own X-Request-ID if it sent one, otherwise a fresh UUIDv4 - and
+// stores both the ID ("request_id") and a child *zap.Logger carrying it as
+// a field ("logger") on the gin context, so handlers can log against
+// c.MustGet("logger") instead of the bare global one and have every line
+// they emit correlate with this request. It also emits one structured
+// summary line per request once the handler chain returns.
+func RequestLoggerMiddleware(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		c.Writer.Header().Set(RequestIDHeader, requestID)
+		c.Set("request_id", requestID)
+		c.Set("logger", logger.With(zap.String("request_id", requestID)))
 
-        // Log after processing
-        duration := time.Since(start)
-        
-        fields := []zap.Field{
-            zap.Int("status", c.Writer.Status()),
-            zap.String("method", c.Request.Method),
-            zap.String("path", path),
-            zap.String("query", query),
-            zap.String("ip", c.ClientIP()),
-            zap.Duration("duration", duration),
-            zap.String("user_agent", c.Request.UserAgent()),
-        }
+		start := time.Now()
+		c.Next()
+		duration := time.Since(start)
 
-        // Add user ID if authenticated
-        if userClaims, exists := c.Get("user"); exists {
-            fields = append(fields, zap.Any("user", userClaims))
-        }
+		requestLogger := c.MustGet("logger").(*zap.Logger)
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("latency", duration),
+			zap.Int("bytes", c.Writer.Size()),
+			zap.String("remote_ip", c.ClientIP()),
+		}
+		if userID, ok := userIDFromContext(c); ok {
+			fields = append(fields, zap.Uint("user_id", userID))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
 
-        // Log errors if any
-        if len(c.Errors) > 0 {
-            fields = append(fields, zap.String("errors", c.Errors.String()))
-        }
+		switch {
+		case c.Writer.Status() >= 500:
+			requestLogger.Error("request completed", fields...)
+		case c.Writer.Status() >= 400:
+			requestLogger.Warn("request completed", fields...)
+		default:
+			requestLogger.Info("request completed", fields...)
+		}
+	}
+}
 
-        // Choose log level based on status code
-        switch {
-        case c.Writer.Status() >= 500:
-            logger.Error("Server error", fields...)
-        case c.Writer.Status() >= 400:
-            logger.Warn("Client error", fields...)
-        default:
-            logger.Info("Request completed", fields...)
-        }
-    }
+// userIDFromContext extracts the sub claim AuthMiddleware sets as "user",
+// the same way RequirePermission reads the acting user's ID.
+func userIDFromContext(c *gin.Context) (uint, bool) {
+	claims, exists := c.Get("user")
+	if !exists {
+		return 0, false
+	}
+	jwtClaims, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, false
+	}
+	sub, ok := jwtClaims["sub"].(float64)
+	if !ok {
+		return 0, false
+	}
+	return uint(sub), true
 }
 
-// RecoveryMiddleware recovers from panics and logs them
+// RecoveryMiddleware recovers from panics, logs them against the request's
+// correlated logger when RequestLoggerMiddleware has set one, and returns
+// the request ID alongside the error so the caller can quote it when
+// reporting the failure.
 func RecoveryMiddleware(logger *zap.Logger) gin.HandlerFunc {
-    return func(c *gin.Context) {
-        defer func() {
-            if err := recover(); err != nil {
-                logger.Error("Panic recovered",
-                    zap.Any("error", err),
-                    zap.String("path", c.Request.URL.Path),
-                    zap.String("method", c.Request.Method),
-                )
-                c.AbortWithStatusJSON(500, gin.H{
-                    "error": "Internal server error",
-                })
-            }
-        }()
-        c.Next()
-    }
-}
\ No newline at end of file
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				requestLogger := logger
+				if l, ok := c.Get("logger"); ok {
+					requestLogger = l.(*zap.Logger)
+				}
+				requestLogger.Error("panic recovered",
+					zap.Any("error", err),
+					zap.String("path", c.Request.URL.Path),
+					zap.String("method", c.Request.Method),
+				)
+				requestID, _ := c.Get("request_id")
+				c.AbortWithStatusJSON(500, gin.H{
+					"error":      "Internal server error",
+					"request_id": requestID,
+				})
+			}
+		}()
+		c.Next()
+	}
+}
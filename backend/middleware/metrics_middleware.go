@@ -1,6 +1,7 @@
 package middleware
 
 import (
+    "database/sql"
     "strconv"
     "time"
 
@@ -92,6 +93,131 @@ var (
         },
         []string{"operation"},
     )
+
+    passwordResetRequestsTotal = promauto.NewCounterVec(
+        prometheus.CounterOpts{
+            Name: "password_reset_requests_total",
+            Help: "Total number of POST /password/forgot requests, by outcome",
+        },
+        []string{"outcome"},
+    )
+
+    // Balance reconciliation metrics (controllers.StartBalanceReconciler)
+    accountBalanceDriftCents = promauto.NewGauge(
+        prometheus.GaugeOpts{
+            Name: "account_balance_drift_cents",
+            Help: "Sum of |stored - computed| current_balance_cents across all accounts in the most recent reconciliation pass",
+        },
+    )
+
+    balanceReconciliationsTotal = promauto.NewCounterVec(
+        prometheus.CounterOpts{
+            Name: "balance_reconciliations_total",
+            Help: "Total number of account balance reconciliation passes, by outcome",
+        },
+        []string{"outcome"},
+    )
+
+    balanceReconciliationDuration = promauto.NewHistogram(
+        prometheus.HistogramOpts{
+            Name:    "balance_reconciliation_duration_seconds",
+            Help:    "Duration of a full account balance reconciliation pass",
+            Buckets: prometheus.DefBuckets,
+        },
+    )
+
+    // DB connection-pool gauges (controllers.StartDBPoolMetricsScheduler),
+    // sourced from sql.DB.Stats() on the primary connection.
+    dbPoolOpenConnections = promauto.NewGauge(
+        prometheus.GaugeOpts{
+            Name: "db_pool_open_connections",
+            Help: "Number of established connections to the database, both in use and idle",
+        },
+    )
+
+    dbPoolInUse = promauto.NewGauge(
+        prometheus.GaugeOpts{
+            Name: "db_pool_in_use",
+            Help: "Number of connections currently in use",
+        },
+    )
+
+    dbPoolIdle = promauto.NewGauge(
+        prometheus.GaugeOpts{
+            Name: "db_pool_idle",
+            Help: "Number of idle connections in the pool",
+        },
+    )
+
+    dbPoolWaitCount = promauto.NewGauge(
+        prometheus.GaugeOpts{
+            Name: "db_pool_wait_count_total",
+            Help: "Total number of connections waited for because the pool was at MaxOpenConns",
+        },
+    )
+
+    dbPoolWaitDurationSeconds = promauto.NewGauge(
+        prometheus.GaugeOpts{
+            Name: "db_pool_wait_duration_seconds_total",
+            Help: "Total time spent waiting for a connection because the pool was at MaxOpenConns",
+        },
+    )
+
+    // Auth outcome metrics (controllers.Login, session_controller.go)
+    loginAttemptsTotal = promauto.NewCounterVec(
+        prometheus.CounterOpts{
+            Name: "auth_login_attempts_total",
+            Help: "Total number of login attempts, by outcome",
+        },
+        []string{"outcome"},
+    )
+
+    tokenOperationsTotal = promauto.NewCounterVec(
+        prometheus.CounterOpts{
+            Name: "auth_token_operations_total",
+            Help: "Total number of access/refresh token lifecycle events, by operation and outcome",
+        },
+        []string{"operation", "outcome"},
+    )
+
+    // Business event metrics
+    budgetsBreachedTotal = promauto.NewCounter(
+        prometheus.CounterOpts{
+            Name: "budgets_breached_total",
+            Help: "Total number of budget alert threshold crossings that fired a notification",
+        },
+    )
+
+    // Plaid backpressure metrics (middleware.PlaidBackpressure)
+    plaidRequestsTotal = promauto.NewCounterVec(
+        prometheus.CounterOpts{
+            Name: "plaid_requests_total",
+            Help: "Total number of Plaid-backed requests admitted past the backpressure layer, by response status",
+        },
+        []string{"status"},
+    )
+
+    plaidInFlight = promauto.NewGauge(
+        prometheus.GaugeOpts{
+            Name: "plaid_inflight",
+            Help: "Number of Plaid-backed requests currently being served",
+        },
+    )
+
+    plaidRateLimitedTotal = promauto.NewCounter(
+        prometheus.CounterOpts{
+            Name: "plaid_rate_limited_total",
+            Help: "Total number of Plaid-backed requests rejected with 429 by the concurrency or rate limit",
+        },
+    )
+
+    plaidLatencySeconds = promauto.NewHistogram(
+        prometheus.HistogramOpts{
+            Name:    "plaid_latency_seconds",
+            Help:    "Duration of a Plaid-backed request, measured from the backpressure layer",
+            Buckets: prometheus.DefBuckets,
+        },
+    )
 )
 
 // MetricsMiddleware collects HTTP metrics
@@ -145,4 +271,73 @@ func SetActiveUsers(count float64) {
 func TrackDBQuery(operation string, duration time.Duration) {
     dbQueriesTotal.WithLabelValues(operation).Inc()
     dbQueryDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// IncrementPasswordResetRequest records one POST /password/forgot call by
+// its outcome (e.g. "sent", "unknown_email", "rate_limited", "mailer_error").
+func IncrementPasswordResetRequest(outcome string) {
+    passwordResetRequestsTotal.WithLabelValues(outcome).Inc()
+}
+
+// RecordBalanceReconciliation reports one reconciliation pass's total
+// absolute drift and duration, and increments its outcome counter
+// ("ok" or "error").
+func RecordBalanceReconciliation(outcome string, totalDriftCents int64, duration time.Duration) {
+    accountBalanceDriftCents.Set(float64(totalDriftCents))
+    balanceReconciliationsTotal.WithLabelValues(outcome).Inc()
+    balanceReconciliationDuration.Observe(duration.Seconds())
+}
+
+// IncrementPlaidRequests records one request admitted past
+// PlaidBackpressure, by its final HTTP status.
+func IncrementPlaidRequests(status string) {
+    plaidRequestsTotal.WithLabelValues(status).Inc()
+}
+
+// SetPlaidInFlight reports how many Plaid-backed requests
+// PlaidBackpressure is currently holding a worker-pool slot for.
+func SetPlaidInFlight(count float64) {
+    plaidInFlight.Set(count)
+}
+
+// IncrementPlaidRateLimited records one request PlaidBackpressure rejected
+// with 429, whether from the concurrency semaphore or the token bucket.
+func IncrementPlaidRateLimited() {
+    plaidRateLimitedTotal.Inc()
+}
+
+// ObservePlaidLatency records how long a Plaid-backed request took, from
+// when PlaidBackpressure admitted it to when its handler returned.
+func ObservePlaidLatency(seconds float64) {
+    plaidLatencySeconds.Observe(seconds)
+}
+
+// RecordDBPoolStats republishes sql.DB.Stats() as gauges, so Grafana can
+// alert on e.g. wait_count climbing instead of only seeing pool exhaustion
+// after it has already caused request latency.
+func RecordDBPoolStats(stats sql.DBStats) {
+    dbPoolOpenConnections.Set(float64(stats.OpenConnections))
+    dbPoolInUse.Set(float64(stats.InUse))
+    dbPoolIdle.Set(float64(stats.Idle))
+    dbPoolWaitCount.Set(float64(stats.WaitCount))
+    dbPoolWaitDurationSeconds.Set(stats.WaitDuration.Seconds())
+}
+
+// IncrementLoginAttempt records one POST /login call by its outcome (e.g.
+// "success", "invalid_credentials", "locked_out", "mfa_required").
+func IncrementLoginAttempt(outcome string) {
+    loginAttemptsTotal.WithLabelValues(outcome).Inc()
+}
+
+// IncrementTokenOperation records one access/refresh token lifecycle event
+// (operation: "issue", "refresh", "revoke") by outcome ("success"/"failure").
+func IncrementTokenOperation(operation, outcome string) {
+    tokenOperationsTotal.WithLabelValues(operation, outcome).Inc()
+}
+
+// IncrementBudgetBreached records one budget alert threshold crossing that
+// actually fired (see evaluateBudgetAlerts's idempotent BudgetAlertEvent
+// create).
+func IncrementBudgetBreached() {
+    budgetsBreachedTotal.Inc()
 }
\ No newline at end of file
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+)
+
+// permissionCacheTTL bounds how long a role's resolved grant set is trusted
+// before RequirePermission re-reads role_permissions. Kept short since it's
+// the backstop for the perm_ver claim, not the primary invalidation path.
+const permissionCacheTTL = 60 * time.Second
+
+type permissionCacheEntry struct {
+	perms     map[models.Permission]bool
+	expiresAt time.Time
+}
+
+// permissionCache holds one entry per (role, role-version), so a role whose
+// grants changed (bumping its RoleVersion) gets a fresh key rather than
+// serving a stale entry until TTL expiry.
+var permissionCache sync.Map // string -> *permissionCacheEntry
+
+// rolePermissionSet returns role's granted permission set, from the cache
+// when a fresh entry exists for its current version, otherwise loading it
+// from role_permissions and caching it for permissionCacheTTL.
+func rolePermissionSet(role models.UserRole) map[models.Permission]bool {
+	version := models.CurrentRoleVersion(db.DB, role)
+	key := fmt.Sprintf("%s:%d", role, version)
+
+	if v, ok := permissionCache.Load(key); ok {
+		entry := v.(*permissionCacheEntry)
+		if time.Now().Before(entry.expiresAt) {
+			return entry.perms
+		}
+	}
+
+	var grants []models.RolePermission
+	db.DB.Where("role = ?", role).Find(&grants)
+
+	perms := make(map[models.Permission]bool, len(grants))
+	for _, g := range grants {
+		perms[g.Permission] = true
+	}
+
+	permissionCache.Store(key, &permissionCacheEntry{perms: perms, expiresAt: time.Now().Add(permissionCacheTTL)})
+	return perms
+}
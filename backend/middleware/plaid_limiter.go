@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlaidAPIRate caps how many Plaid-backed requests a single actor can make
+// per minute. This is independent of Plaid's own per-item rate limits -
+// it protects this server's shared Plaid client/secret pair from one noisy
+// caller burning through whatever quota Plaid itself enforces.
+var PlaidAPIRate = Rate{Burst: 60, Per: time.Minute}
+
+// PlaidMaxInFlight bounds how many Plaid-backed requests this server will
+// serve concurrently, regardless of how evenly PlaidAPIRate spaces them out
+// - Plaid's sandbox/dev tiers also cap concurrent in-flight calls, so an
+// otherwise within-quota burst still needs a hard concurrency ceiling.
+const PlaidMaxInFlight = 8
+
+var (
+	plaidInFlightSem   = make(chan struct{}, PlaidMaxInFlight)
+	plaidInFlightCount int64
+)
+
+// PlaidBackpressure wraps every Plaid-backed route with a bounded worker
+// pool (PlaidMaxInFlight concurrent requests) and a token bucket
+// (PlaidAPIRate, keyed like the rest of this package's RateLimit by actor
+// and route). A request that would exceed either is rejected with 429 and
+// Retry-After rather than queued - Plaid itself rate-limits this server,
+// so queuing here would just hide the wait instead of removing it.
+// Reports plaid_requests_total, plaid_rate_limited_total, plaid_inflight,
+// and plaid_latency_seconds so the shape of this backpressure is visible
+// on /metrics.
+func PlaidBackpressure() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		select {
+		case plaidInFlightSem <- struct{}{}:
+		default:
+			IncrementPlaidRateLimited()
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many concurrent Plaid requests, try again later"})
+			return
+		}
+		defer func() { <-plaidInFlightSem }()
+
+		SetPlaidInFlight(float64(atomic.AddInt64(&plaidInFlightCount, 1)))
+		defer func() { SetPlaidInFlight(float64(atomic.AddInt64(&plaidInFlightCount, -1))) }()
+
+		allowed, _, resetAt := activeBucketStore().take(ActorRouteKey(c), PlaidAPIRate)
+		if !allowed {
+			IncrementPlaidRateLimited()
+			c.Header("Retry-After", strconv.Itoa(secondsUntil(resetAt)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Plaid rate limit exceeded, try again later"})
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+		IncrementPlaidRequests(strconv.Itoa(c.Writer.Status()))
+		ObservePlaidLatency(time.Since(start).Seconds())
+	}
+}
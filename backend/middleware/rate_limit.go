@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/redis"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Rate is a token-bucket allowance: Burst requests refilled every Per.
+type Rate struct {
+	Burst int
+	Per   time.Duration
+}
+
+// SensitiveAdminRate caps destructive/role-changing admin actions hard
+// enough to blunt a compromised admin token or a panicked bulk-click, while
+// still allowing normal single-resource workflows.
+var SensitiveAdminRate = Rate{Burst: 10, Per: time.Minute}
+
+// StandardAdminRate is the looser cap for read-heavy admin endpoints
+// (dashboard, listings) where legitimate usage is bursty.
+var StandardAdminRate = Rate{Burst: 120, Per: time.Minute}
+
+// AdminAuthFailureRate bounds how many failed-auth attempts against
+// /api/admin routes a single IP may make before being throttled - defends
+// against bearer-token guessing/enumeration pre-authentication.
+var AdminAuthFailureRate = Rate{Burst: 20, Per: time.Minute}
+
+// PasswordResetRequestRate bounds how often POST /password/forgot can be
+// called for a given IP or a given email address, so it can't be used to
+// spam a mailbox or hammer the mailer.
+var PasswordResetRequestRate = Rate{Burst: 3, Per: time.Hour}
+
+// MFAFailureRate bounds how many wrong TOTP/backup codes a user can submit
+// before being locked out for a minute, so a stolen access/mfa token can't
+// be used to brute-force a 6-digit code.
+var MFAFailureRate = Rate{Burst: 5, Per: time.Minute}
+
+// LoginRate bounds how many /auth/login attempts a single IP may make, so
+// credential stuffing against many usernames from one source is throttled
+// independent of the per-username lockout in controllers/login_lockout.go.
+var LoginRate = Rate{Burst: 5, Per: time.Minute}
+
+// RegistrationRate bounds how many /auth/register calls a single IP may
+// make, so one source can't mass-create accounts.
+var RegistrationRate = Rate{Burst: 20, Per: time.Hour}
+
+// KeyFunc derives the bucket key a request is rate-limited under.
+type KeyFunc func(c *gin.Context) string
+
+// ActorRouteKey buckets by (authenticated actor user ID, route), so one
+// admin's limiter can't starve another's.
+func ActorRouteKey(c *gin.Context) string {
+	actor := "anon"
+	if claims, exists := c.Get("user"); exists {
+		if jwtClaims, ok := claims.(jwt.MapClaims); ok {
+			if sub, ok := jwtClaims["sub"].(float64); ok {
+				actor = strconv.FormatUint(uint64(sub), 10)
+			}
+		}
+	}
+	return actor + ":" + c.Request.Method + " " + c.FullPath()
+}
+
+// IPRouteKey buckets by (client IP, route), for rate limits that run ahead
+// of authentication (e.g. login, registration) where there's no actor yet.
+func IPRouteKey(c *gin.Context) string {
+	return c.ClientIP() + ":" + c.Request.Method + " " + c.FullPath()
+}
+
+// bucketStore is the pluggable backend a token bucket is kept in. The
+// default is an in-memory sync.Map; redisBucketStore swaps in Redis so the
+// limit is shared across replicas instead of per-process.
+type bucketStore interface {
+	// take consumes one token from key's bucket (creating it with rate.Burst
+	// tokens if absent), reporting whether the request is allowed, how many
+	// tokens remain, and when the bucket next fully refills.
+	take(key string, rate Rate) (allowed bool, remaining int, resetAt time.Time)
+}
+
+type memoryBucket struct {
+	mu      sync.Mutex
+	tokens  int
+	resetAt time.Time
+}
+
+type memoryBucketStore struct {
+	buckets sync.Map // string -> *memoryBucket
+}
+
+func (s *memoryBucketStore) take(key string, rate Rate) (bool, int, time.Time) {
+	now := time.Now()
+	v, _ := s.buckets.LoadOrStore(key, &memoryBucket{tokens: rate.Burst, resetAt: now.Add(rate.Per)})
+	b := v.(*memoryBucket)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.After(b.resetAt) {
+		b.tokens = rate.Burst
+		b.resetAt = now.Add(rate.Per)
+	}
+
+	if b.tokens <= 0 {
+		return false, 0, b.resetAt
+	}
+	b.tokens--
+	return true, b.tokens, b.resetAt
+}
+
+var defaultBucketStore bucketStore = &memoryBucketStore{}
+
+// redisBucketStore implements bucketStore atop Redis INCR+EXPIRE, so the
+// limit is shared across replicas instead of per-process. Falls back to
+// failing open (allowing the request) if Redis is unreachable - a rate
+// limiter outage shouldn't take down the admin API.
+type redisBucketStore struct{}
+
+func (redisBucketStore) take(key string, rate Rate) (bool, int, time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	redisKey := "ratelimit:" + key
+	count, err := redis.RDB.Incr(ctx, redisKey).Result()
+	if err != nil {
+		return true, rate.Burst - 1, time.Now().Add(rate.Per)
+	}
+	if count == 1 {
+		redis.RDB.Expire(ctx, redisKey, rate.Per)
+	}
+
+	resetAt := time.Now().Add(rate.Per)
+	if ttl, err := redis.RDB.TTL(ctx, redisKey).Result(); err == nil && ttl > 0 {
+		resetAt = time.Now().Add(ttl)
+	}
+
+	if int(count) > rate.Burst {
+		return false, 0, resetAt
+	}
+	return true, rate.Burst - int(count), resetAt
+}
+
+// activeBucketStore picks Redis when it's configured, falling back to the
+// in-memory store otherwise (e.g. local dev, tests).
+func activeBucketStore() bucketStore {
+	if redis.RDB != nil {
+		return redisBucketStore{}
+	}
+	return defaultBucketStore
+}
+
+// RateLimit enforces rate as a token bucket keyed by key(c). On the
+// bucket's last token it still serves the request; once exhausted it
+// responds 429 with Retry-After and X-RateLimit-Remaining/X-RateLimit-Reset,
+// and records an audit entry so repeated throttling shows up in review.
+func RateLimit(key KeyFunc, rate Rate) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, remaining, resetAt := activeBucketStore().take(key(c), rate)
+
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(secondsUntil(resetAt)))
+
+			userID, username := actorFromContext(c)
+			recordAudit(auditRecordInput{
+				ActorUserID:   userID,
+				ActorUsername: username,
+				Action:        c.Request.Method + " " + c.FullPath(),
+				TargetType:    "rate_limit",
+				Method:        c.Request.Method,
+				Endpoint:      c.FullPath(),
+				RemoteIP:      c.ClientIP(),
+				UserAgent:     c.Request.UserAgent(),
+				Reason:        "rate_limited",
+			})
+
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded, try again later"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CheckIPFailureLimit consumes one token from the per-IP bucket keyed by
+// scope (e.g. "admin_auth_failure"). If the bucket is empty it writes the
+// 429 response itself and returns false - the caller should stop handling
+// the request in that case, same contract as requireConfirmToken.
+func CheckIPFailureLimit(c *gin.Context, scope string, rate Rate) bool {
+	return CheckKeyLimit(c, scope+":"+c.ClientIP(), rate, "too many failed admin auth attempts, try again later")
+}
+
+// CheckKeyLimit consumes one token from the bucket keyed by key. If the
+// bucket is empty it writes the 429 response itself (using message) and
+// returns false - the caller should stop handling the request in that case.
+// This is the generic form CheckIPFailureLimit and password-reset
+// rate-limiting both build on, for buckets keyed by something other than
+// "scope:IP" (e.g. an email address).
+func CheckKeyLimit(c *gin.Context, key string, rate Rate, message string) bool {
+	allowed, _, resetAt := activeBucketStore().take(key, rate)
+	if !allowed {
+		c.Header("Retry-After", strconv.Itoa(secondsUntil(resetAt)))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": message})
+		return false
+	}
+	return true
+}
+
+func secondsUntil(t time.Time) int {
+	remaining := int(time.Until(t).Seconds())
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// actorFromContext reads the acting user's ID/username off the JWT claims
+// set by AuthMiddleware, returning zero values if the request never
+// authenticated.
+func actorFromContext(c *gin.Context) (userID uint, username string) {
+	claims, exists := c.Get("user")
+	if !exists {
+		return 0, ""
+	}
+	jwtClaims, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, ""
+	}
+	if sub, ok := jwtClaims["sub"].(float64); ok {
+		userID = uint(sub)
+	}
+	username, _ = jwtClaims["name"].(string)
+	return userID, username
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/redis"
+)
+
+// tokenBlacklistStore revokes individual access tokens by jti before their
+// natural expiry - e.g. so the token used to delete/change an account can't
+// keep hitting the API for the rest of its lifetime. Mirrors RateLimit's
+// bucketStore: in-memory by default, Redis-backed when configured so
+// revocations are shared across replicas.
+type tokenBlacklistStore interface {
+	// revoke blacklists jti for ttl (its remaining time until exp).
+	revoke(jti string, ttl time.Duration)
+	// isRevoked reports whether jti has been revoked and hasn't expired yet.
+	isRevoked(jti string) bool
+}
+
+type memoryTokenBlacklist struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time // jti -> expiry
+}
+
+func (s *memoryTokenBlacklist) revoke(jti string, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.revoked == nil {
+		s.revoked = make(map[string]time.Time)
+	}
+	s.revoked[jti] = time.Now().Add(ttl)
+}
+
+func (s *memoryTokenBlacklist) isRevoked(jti string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiry, ok := s.revoked[jti]
+	if !ok {
+		return false
+	}
+	if time.Now().After(expiry) {
+		delete(s.revoked, jti)
+		return false
+	}
+	return true
+}
+
+var defaultTokenBlacklist = &memoryTokenBlacklist{}
+
+// redisTokenBlacklist stores revoked jti's as Redis keys with the token's
+// remaining lifetime as TTL, so the entry disappears on its own once the
+// token would have expired anyway.
+type redisTokenBlacklist struct{}
+
+func (redisTokenBlacklist) revoke(jti string, ttl time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	redis.RDB.Set(ctx, "revoked:"+jti, "1", ttl)
+}
+
+func (redisTokenBlacklist) isRevoked(jti string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	n, err := redis.RDB.Exists(ctx, "revoked:"+jti).Result()
+	// Fail open: a blacklist lookup outage shouldn't lock every session out.
+	if err != nil {
+		return false
+	}
+	return n > 0
+}
+
+func activeTokenBlacklist() tokenBlacklistStore {
+	if redis.RDB != nil {
+		return redisTokenBlacklist{}
+	}
+	return defaultTokenBlacklist
+}
+
+// RevokeToken blacklists the access token carrying jti until exp, so it is
+// rejected by RequireAccessToken even though it hasn't naturally expired.
+func RevokeToken(jti string, exp time.Time) {
+	if jti == "" {
+		return
+	}
+	ttl := time.Until(exp)
+	if ttl <= 0 {
+		IncrementTokenOperation("revoke", "noop_expired")
+		return
+	}
+	activeTokenBlacklist().revoke(jti, ttl)
+	IncrementTokenOperation("revoke", "success")
+}
+
+// IsTokenRevoked reports whether jti has been revoked via RevokeToken.
+func IsTokenRevoked(jti string) bool {
+	if jti == "" {
+		return false
+	}
+	return activeTokenBlacklist().isRevoked(jti)
+}
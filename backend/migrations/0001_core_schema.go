@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the baseline schema migration covering every model that
+// previously went through db.ConnectDatabase's inline AutoMigrate call.
+// Running it against a database that already has these tables is a no-op
+// (AutoMigrate only adds what's missing), so upgrading an existing
+// deployment to the migrations subsystem just records this version as
+// applied without touching data.
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "core_schema",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(
+				&models.User{},
+				&models.Account{},
+				&models.Category{},
+				&models.Transaction{},
+				&models.TransactionSplit{},
+				&models.Budget{},
+				&models.BudgetItem{},
+				&models.BankConnection{},
+				&models.BankAccount{},
+				&models.BankSyncLog{},
+				&models.LedgerEntry{},
+				&models.Transfer{},
+				&models.RecurringRule{},
+				&models.IdempotencyKey{},
+				&models.AuditLog{},
+				&models.RolePermission{},
+				&models.OAuthIdentity{},
+				&models.UserOTP{},
+				&models.PasswordReset{},
+				&models.RoleVersion{},
+			)
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(
+				&models.RoleVersion{},
+				&models.PasswordReset{},
+				&models.UserOTP{},
+				&models.OAuthIdentity{},
+				&models.RolePermission{},
+				&models.AuditLog{},
+				&models.IdempotencyKey{},
+				&models.RecurringRule{},
+				&models.Transfer{},
+				&models.LedgerEntry{},
+				&models.BankSyncLog{},
+				&models.BankAccount{},
+				&models.BankConnection{},
+				&models.BudgetItem{},
+				&models.Budget{},
+				&models.TransactionSplit{},
+				&models.Transaction{},
+				&models.Category{},
+				&models.Account{},
+				&models.User{},
+			)
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding BudgetTemplate/
+// BudgetTemplateItem, which back controllers.RolloverBudget.
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "budget_templates",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(
+				&models.BudgetTemplate{},
+				&models.BudgetTemplateItem{},
+			)
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(
+				&models.BudgetTemplateItem{},
+				&models.BudgetTemplate{},
+			)
+		},
+	})
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding FxRate, the local cache
+// backing services/fx's daily conversion rates.
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "fx_rates",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.FxRate{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.FxRate{})
+		},
+	})
+}
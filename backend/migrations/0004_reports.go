@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding Report, the saved
+// grouping/filter/aggregation recipe controllers.RunReport replays.
+func init() {
+	register(Migration{
+		Version: 4,
+		Name:    "reports",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.Report{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.Report{})
+		},
+	})
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding Category.IsSystem (AutoMigrate
+// adds the missing column to the existing table) and CategoryOverride, the
+// per-user personalization/hide record for shared system categories.
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "category_overrides",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.Category{}, &models.CategoryOverride{})
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&models.CategoryOverride{}); err != nil {
+				return err
+			}
+			return db.Migrator().DropColumn(&models.Category{}, "IsSystem")
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding CategoryRule, the
+// merchant/description-pattern auto-categorization rules evaluated by
+// controllers.MatchCategoryRule.
+func init() {
+	register(Migration{
+		Version: 6,
+		Name:    "category_rules",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.CategoryRule{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.CategoryRule{})
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding AuthAuditEvent, the
+// hash-chained auth audit trail written asynchronously by
+// middleware.RecordAuthAuditEvent.
+func init() {
+	register(Migration{
+		Version: 7,
+		Name:    "auth_audit_events",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.AuthAuditEvent{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.AuthAuditEvent{})
+		},
+	})
+}
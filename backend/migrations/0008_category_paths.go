@@ -0,0 +1,83 @@
+package migrations
+
+import (
+	"strconv"
+
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding Category.Path/Depth (the
+// materialized path used for O(1) ancestor/descendant/subtree queries) and
+// backfilling both columns for every category that existed before this
+// migration, via a level-by-level walk from root categories (ParentID
+// IS NULL) down. New rows get Path/Depth stamped by models.Category's
+// AfterCreate hook instead, so this backfill only ever needs to run once.
+func init() {
+	register(Migration{
+		Version: 8,
+		Name:    "category_paths",
+		Up: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&models.Category{}); err != nil {
+				return err
+			}
+
+			type row struct {
+				ID       uint
+				ParentID *uint
+			}
+
+			level := []row{}
+			if err := db.Unscoped().Model(&models.Category{}).
+				Select("id, parent_id").
+				Where("parent_id IS NULL").
+				Find(&level).Error; err != nil {
+				return err
+			}
+
+			parentPaths := map[uint]string{}
+			depth := 0
+			for len(level) > 0 {
+				var nextIDs []uint
+				for _, cat := range level {
+					parentPath := ""
+					if cat.ParentID != nil {
+						parentPath = parentPaths[*cat.ParentID]
+					}
+					path := parentPath + strconv.FormatUint(uint64(cat.ID), 10) + "/"
+					if parentPath == "" {
+						path = "/" + strconv.FormatUint(uint64(cat.ID), 10) + "/"
+					}
+					parentPaths[cat.ID] = path
+
+					if err := db.Unscoped().Model(&models.Category{}).Where("id = ?", cat.ID).
+						UpdateColumns(map[string]interface{}{"path": path, "depth": depth}).Error; err != nil {
+						return err
+					}
+					nextIDs = append(nextIDs, cat.ID)
+				}
+
+				var next []row
+				if len(nextIDs) > 0 {
+					if err := db.Unscoped().Model(&models.Category{}).
+						Select("id, parent_id").
+						Where("parent_id IN ?", nextIDs).
+						Find(&next).Error; err != nil {
+						return err
+					}
+				}
+				level = next
+				depth++
+			}
+
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropColumn(&models.Category{}, "Path"); err != nil {
+				return err
+			}
+			return db.Migrator().DropColumn(&models.Category{}, "Depth")
+		},
+	})
+}
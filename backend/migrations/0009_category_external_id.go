@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding Category.ExternalID, the
+// stable identifier used to make category import/export idempotent across
+// environments. Existing rows predate the column and have no sensible
+// external_id to preserve, so each is backfilled with a freshly generated
+// UUID; new rows get one from models.Category's BeforeCreate hook instead.
+func init() {
+	register(Migration{
+		Version: 9,
+		Name:    "category_external_id",
+		Up: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&models.Category{}); err != nil {
+				return err
+			}
+
+			var categories []models.Category
+			if err := db.Unscoped().Select("id").Where("external_id = ?", "").Find(&categories).Error; err != nil {
+				return err
+			}
+			for _, category := range categories {
+				if err := db.Unscoped().Model(&models.Category{}).Where("id = ?", category.ID).
+					Update("external_id", uuid.NewString()).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropColumn(&models.Category{}, "ExternalID")
+		},
+	})
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding TransactionSplit.AccountID, the
+// column that lets a split carry its own account leg (for transfers and
+// multi-currency transactions) instead of only recategorizing a slice of the
+// parent transaction's amount. Existing splits have no second leg, so the
+// column is left nil for them; CreateTransaction continues to treat a nil
+// AccountID as "same account as the parent transaction".
+func init() {
+	register(Migration{
+		Version: 10,
+		Name:    "split_account_id",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.TransactionSplit{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropColumn(&models.TransactionSplit{}, "AccountID")
+		},
+	})
+}
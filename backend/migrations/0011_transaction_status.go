@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding Transaction.Status (the
+// Imported/Entered/Cleared/Reconciled/Voided reconciliation workflow) and the
+// AccountReconciliation history table it's checked off against. Existing
+// transactions predate the column and default to Entered, matching how a
+// transaction created outside a bank sync already behaves today.
+func init() {
+	register(Migration{
+		Version: 11,
+		Name:    "transaction_status",
+		Up: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&models.Transaction{}); err != nil {
+				return err
+			}
+			return db.AutoMigrate(&models.AccountReconciliation{})
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&models.AccountReconciliation{}); err != nil {
+				return err
+			}
+			return db.Migrator().DropColumn(&models.Transaction{}, "Status")
+		},
+	})
+}
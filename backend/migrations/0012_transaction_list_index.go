@@ -0,0 +1,22 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// init registers the schema migration adding the composite index
+// GetTransactions' keyset pagination relies on: (user_id, txn_date DESC, id
+// DESC) matches the WHERE user_id = ? ... ORDER BY txn_date DESC, id DESC
+// query exactly, so Postgres can satisfy a page request with an index scan
+// instead of sorting the user's whole transaction history every time.
+func init() {
+	register(Migration{
+		Version: 12,
+		Name:    "transaction_list_index",
+		Up: func(db *gorm.DB) error {
+			return db.Exec(`CREATE INDEX IF NOT EXISTS idx_transactions_user_txndate_id
+				ON transactions (user_id, txn_date DESC, id DESC)`).Error
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Exec(`DROP INDEX IF EXISTS idx_transactions_user_txndate_id`).Error
+		},
+	})
+}
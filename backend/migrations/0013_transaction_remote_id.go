@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding Transaction.RemoteID and the
+// (user_id, account_id, remote_id) unique index BulkCreateTransactions
+// checks before inserting each row. Existing transactions predate the
+// column and are left with a nil RemoteID, which never collides with a
+// bulk-imported row's (NULLs don't conflict in a unique index).
+func init() {
+	register(Migration{
+		Version: 13,
+		Name:    "transaction_remote_id",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.Transaction{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropColumn(&models.Transaction{}, "RemoteID")
+		},
+	})
+}
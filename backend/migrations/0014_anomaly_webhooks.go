@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding AnomalyWebhook, the
+// user-registered delivery endpoints services/anomaly.NotifyAnomalies POSTs
+// HMAC-signed anomaly payloads to.
+func init() {
+	register(Migration{
+		Version: 14,
+		Name:    "anomaly_webhooks",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.AnomalyWebhook{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.AnomalyWebhook{})
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding UserAISetting, the per-user
+// controllers/aidriver override (driver + API key) an admin can let an end
+// user set instead of sharing the server-wide AI_DRIVER default.
+func init() {
+	register(Migration{
+		Version: 15,
+		Name:    "user_ai_settings",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.UserAISetting{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.UserAISetting{})
+		},
+	})
+}
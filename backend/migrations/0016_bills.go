@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding the bill pay subsystem:
+// BillVendor/BillProduct (the vendor catalog), Bill (a user's subscription
+// to pay one), and BillPayment (its payment/reminder history).
+func init() {
+	register(Migration{
+		Version: 16,
+		Name:    "bills",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.BillVendor{}, &models.BillProduct{}, &models.Bill{}, &models.BillPayment{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.BillPayment{}, &models.Bill{}, &models.BillProduct{}, &models.BillVendor{})
+		},
+	})
+}
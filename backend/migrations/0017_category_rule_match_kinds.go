@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding CategoryRule's
+// amount_min_cents/amount_max_cents/match_account_id columns, backing the
+// new CategoryRuleMatchAmountRange and CategoryRuleMatchAccountID match
+// kinds alongside the existing text-based ones.
+func init() {
+	register(Migration{
+		Version: 17,
+		Name:    "category_rule_match_kinds",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.CategoryRule{})
+		},
+		Down: func(db *gorm.DB) error {
+			m := db.Migrator()
+			for _, col := range []string{"amount_min_cents", "amount_max_cents", "match_account_id"} {
+				if m.HasColumn(&models.CategoryRule{}, col) {
+					if err := m.DropColumn(&models.CategoryRule{}, col); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	})
+}
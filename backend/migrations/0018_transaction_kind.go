@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding Transaction.Kind, which lets
+// plaid_api.go's collapsePlaidTransfers mark a Transaction as a collapsed
+// internal transfer instead of an ordinary income/expense posting.
+func init() {
+	register(Migration{
+		Version: 18,
+		Name:    "transaction_kind",
+		Up: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&models.Transaction{}); err != nil {
+				return err
+			}
+			return db.Exec("UPDATE transactions SET kind = ? WHERE kind = ''", models.TransactionKindStandard).Error
+		},
+		Down: func(db *gorm.DB) error {
+			if db.Migrator().HasColumn(&models.Transaction{}, "kind") {
+				return db.Migrator().DropColumn(&models.Transaction{}, "kind")
+			}
+			return nil
+		},
+	})
+}
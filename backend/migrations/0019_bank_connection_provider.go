@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding BankConnection.Provider, so
+// aggregators.Get can dispatch a connection to its owning aggregator
+// instead of every handler assuming Plaid.
+func init() {
+	register(Migration{
+		Version: 19,
+		Name:    "bank_connection_provider",
+		Up: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&models.BankConnection{}); err != nil {
+				return err
+			}
+			return db.Exec("UPDATE bank_connections SET provider = ? WHERE provider = ''", "plaid").Error
+		},
+		Down: func(db *gorm.DB) error {
+			if db.Migrator().HasColumn(&models.BankConnection{}, "provider") {
+				return db.Migrator().DropColumn(&models.BankConnection{}, "provider")
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{
+		Version: 20,
+		Name:    "investments",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.Security{}, &models.Holding{}, &models.NetWorthSnapshot{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.NetWorthSnapshot{}, &models.Holding{}, &models.Security{})
+		},
+	})
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding Account.ParentAccountID (a
+// chart-of-accounts hierarchy) and the models.Split table (double-entry
+// decomposition of a Transaction across that hierarchy).
+func init() {
+	register(Migration{
+		Version: 21,
+		Name:    "account_hierarchy",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.Account{}, &models.Split{})
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&models.Split{}); err != nil {
+				return err
+			}
+			if db.Migrator().HasColumn(&models.Account{}, "parent_account_id") {
+				return db.Migrator().DropColumn(&models.Account{}, "parent_account_id")
+			}
+			return nil
+		},
+	})
+}
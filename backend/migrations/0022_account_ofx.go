@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding the OFX Direct Connect
+// metadata columns on Account (OFXURL, OFXOrg, OFXFID, OFXUser,
+// OFXPassword, OFXBankID, OFXAcctType) used by SyncAccountOFX.
+func init() {
+	register(Migration{
+		Version: 22,
+		Name:    "account_ofx",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.Account{})
+		},
+		Down: func(db *gorm.DB) error {
+			columns := []string{"ofx_url", "ofx_org", "ofx_fid", "ofx_user", "ofx_password", "ofx_bank_id", "ofx_acct_type"}
+			for _, column := range columns {
+				if db.Migrator().HasColumn(&models.Account{}, column) {
+					if err := db.Migrator().DropColumn(&models.Account{}, column); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	})
+}
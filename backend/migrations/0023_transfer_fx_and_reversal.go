@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding Transfer.ToAmountCents/FXRate
+// (cross-currency transfers) and Transfer.ReversedAt (so DeleteTransfer can
+// mark a reversed transfer without deleting its audit trail).
+func init() {
+	register(Migration{
+		Version: 23,
+		Name:    "transfer_fx_and_reversal",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.Transfer{})
+		},
+		Down: func(db *gorm.DB) error {
+			columns := []string{"to_amount_cents", "fx_rate", "reversed_at"}
+			for _, column := range columns {
+				if db.Migrator().HasColumn(&models.Transfer{}, column) {
+					if err := db.Migrator().DropColumn(&models.Transfer{}, column); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	})
+}
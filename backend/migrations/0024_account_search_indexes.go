@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding the (user_id, type) and
+// (user_id, name) indexes GetAccounts' search/filter/sort query relies on.
+func init() {
+	register(Migration{
+		Version: 24,
+		Name:    "account_search_indexes",
+		Up: func(db *gorm.DB) error {
+			// The composite indexes themselves are declared via gorm tags on
+			// Account.UserID/Type/Name; AutoMigrate creates whatever's new.
+			return db.AutoMigrate(&models.Account{})
+		},
+		Down: func(db *gorm.DB) error {
+			if db.Migrator().HasIndex(&models.Account{}, "idx_accounts_user_type") {
+				if err := db.Migrator().DropIndex(&models.Account{}, "idx_accounts_user_type"); err != nil {
+					return err
+				}
+			}
+			if db.Migrator().HasIndex(&models.Account{}, "idx_accounts_user_name") {
+				return db.Migrator().DropIndex(&models.Account{}, "idx_accounts_user_name")
+			}
+			return nil
+		},
+	})
+}
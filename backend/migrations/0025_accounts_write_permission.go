@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration granting the new
+// models.PermAccountsWrite permission to SuperAdmin/Admin, since
+// SeedDefaultRolePermissions only seeds a role's grants the first time it
+// has none - an existing install's Admin role would otherwise never pick up
+// a permission added after its initial seed.
+func init() {
+	register(Migration{
+		Version: 25,
+		Name:    "accounts_write_permission",
+		Up: func(db *gorm.DB) error {
+			for _, role := range []models.UserRole{models.UserRoleSuperAdmin, models.UserRoleAdmin} {
+				var count int64
+				if err := db.Model(&models.RolePermission{}).
+					Where("role = ? AND permission = ?", role, models.PermAccountsWrite).
+					Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					continue
+				}
+				if err := db.Create(&models.RolePermission{Role: role, Permission: models.PermAccountsWrite}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Where("permission = ?", models.PermAccountsWrite).Delete(&models.RolePermission{}).Error
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding the BudgetGroup/
+// BudgetGroupMember tables and Transaction's BudgetGroupID/SplitKind
+// columns, backing controllers.GetGroupBalances.
+func init() {
+	register(Migration{
+		Version: 26,
+		Name:    "budget_groups",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.BudgetGroup{}, &models.BudgetGroupMember{}, &models.Transaction{})
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&models.BudgetGroupMember{}, &models.BudgetGroup{}); err != nil {
+				return err
+			}
+			if db.Migrator().HasColumn(&models.Transaction{}, "budget_group_id") {
+				if err := db.Migrator().DropColumn(&models.Transaction{}, "budget_group_id"); err != nil {
+					return err
+				}
+			}
+			if db.Migrator().HasColumn(&models.Transaction{}, "split_kind") {
+				return db.Migrator().DropColumn(&models.Transaction{}, "split_kind")
+			}
+			return nil
+		},
+	})
+}
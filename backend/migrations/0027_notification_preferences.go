@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding the notification_preferences
+// table backing controllers.StartDigestScheduler.
+func init() {
+	register(Migration{
+		Version: 27,
+		Name:    "notification_preferences",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.NotificationPreference{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.NotificationPreference{})
+		},
+	})
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding the tax_rules table backing
+// controllers.GetFiscalDashboard's VAT/income-tax estimates.
+func init() {
+	register(Migration{
+		Version: 28,
+		Name:    "tax_rules",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.TaxRule{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.TaxRule{})
+		},
+	})
+}
@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration replacing integer-cents monetary
+// columns with NUMERIC(20,4) (TEXT on SQLite, via decimal.Decimal's own
+// database/sql support) on Transaction.Amount, TransactionSplit.Amount,
+// Account.CurrentBalance, and BudgetItem.PlannedAmount - see those fields'
+// doc comments for why. AutoMigrate only ever adds columns, so Up adds the
+// new decimal column next to its old *_cents column, backfills it
+// (cents / 100), then drops the old column; Down reverses that, rounding
+// back to the nearest cent.
+func init() {
+	register(Migration{
+		Version: 29,
+		Name:    "decimal_money",
+		Up: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&models.Transaction{}, &models.TransactionSplit{}, &models.Account{}, &models.BudgetItem{}); err != nil {
+				return err
+			}
+
+			backfills := []struct {
+				table, newCol, oldCol string
+			}{
+				{"transactions", "amount", "amount_cents"},
+				{"transaction_splits", "amount", "amount_cents"},
+				{"accounts", "current_balance", "current_balance_cents"},
+				{"budget_items", "planned_amount", "planned_cents"},
+			}
+			for _, b := range backfills {
+				if err := db.Exec("UPDATE " + b.table + " SET " + b.newCol + " = " + b.oldCol + " / 100.0").Error; err != nil {
+					return err
+				}
+			}
+
+			drops := []struct {
+				model  interface{}
+				column string
+			}{
+				{&models.Transaction{}, "amount_cents"},
+				{&models.TransactionSplit{}, "amount_cents"},
+				{&models.Account{}, "current_balance_cents"},
+				{&models.BudgetItem{}, "planned_cents"},
+			}
+			for _, d := range drops {
+				if db.Migrator().HasColumn(d.model, d.column) {
+					if err := db.Migrator().DropColumn(d.model, d.column); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			restores := []struct {
+				table, oldCol, newCol string
+			}{
+				{"transactions", "amount_cents", "amount"},
+				{"transaction_splits", "amount_cents", "amount"},
+				{"accounts", "current_balance_cents", "current_balance"},
+				{"budget_items", "planned_cents", "planned_amount"},
+			}
+			for _, r := range restores {
+				if err := db.Exec("ALTER TABLE " + r.table + " ADD COLUMN " + r.oldCol + " BIGINT NOT NULL DEFAULT 0").Error; err != nil {
+					return err
+				}
+				if err := db.Exec("UPDATE " + r.table + " SET " + r.oldCol + " = CAST(ROUND(" + r.newCol + " * 100) AS BIGINT)").Error; err != nil {
+					return err
+				}
+			}
+
+			drops := []struct {
+				model  interface{}
+				column string
+			}{
+				{&models.Transaction{}, "amount"},
+				{&models.TransactionSplit{}, "amount"},
+				{&models.Account{}, "current_balance"},
+				{&models.BudgetItem{}, "planned_amount"},
+			}
+			for _, d := range drops {
+				if db.Migrator().HasColumn(d.model, d.column) {
+					if err := db.Migrator().DropColumn(d.model, d.column); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	})
+}
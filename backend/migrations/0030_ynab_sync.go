@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding the ynab_connections and
+// ynab_sync_states tables backing integrations/ynab.Sync, plus the
+// Source/YnabTransactionID column on Transaction and the YnabAccountID/
+// YnabBudgetID columns on Account/Budget it matches synced rows against.
+func init() {
+	register(Migration{
+		Version: 30,
+		Name:    "ynab_sync",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.YnabConnection{}, &models.YnabSyncState{}, &models.Transaction{}, &models.Account{}, &models.Budget{})
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&models.YnabSyncState{}, &models.YnabConnection{}); err != nil {
+				return err
+			}
+			for _, d := range []struct {
+				model  interface{}
+				column string
+			}{
+				{&models.Transaction{}, "source"},
+				{&models.Transaction{}, "ynab_transaction_id"},
+				{&models.Account{}, "ynab_account_id"},
+				{&models.Budget{}, "ynab_budget_id"},
+			} {
+				if db.Migrator().HasColumn(d.model, d.column) {
+					if err := db.Migrator().DropColumn(d.model, d.column); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	})
+}
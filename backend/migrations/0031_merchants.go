@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"regexp"
+	"strings"
+
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// merchantsBackfillTrailingID/CityState mirror
+// controllers.merchantTrailingIDPattern/merchantCityStatePattern; kept as
+// their own copy here rather than imported, since migrations are meant to
+// stand alone and keep working even if the normalization rules in
+// controllers change later.
+var (
+	merchantsBackfillTrailingID = regexp.MustCompile(`(?:\*[A-Z0-9]{4,}|\s+#?\d{4,})$`)
+	merchantsBackfillCityState  = regexp.MustCompile(`(?i)\s+[A-Za-z.' ]+\s[A-Z]{2}$`)
+)
+
+func merchantsBackfillNormalize(description string) string {
+	normalized := merchantsBackfillTrailingID.ReplaceAllString(strings.TrimSpace(description), "")
+	normalized = merchantsBackfillCityState.ReplaceAllString(normalized, "")
+	return strings.TrimSpace(normalized)
+}
+
+// init registers the schema migration adding the merchants table and
+// Transaction.MerchantID, then backfilling MerchantID on every existing
+// transaction whose noise-stripped description matches a seeded system
+// merchant's patterns (a user's own merchants can't exist yet at migration
+// time, so there's nothing of theirs to backfill against).
+func init() {
+	register(Migration{
+		Version: 31,
+		Name:    "merchants",
+		Up: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&models.Merchant{}, &models.Transaction{}); err != nil {
+				return err
+			}
+
+			var merchants []models.Merchant
+			if err := db.Where("is_system = ?", true).Find(&merchants).Error; err != nil {
+				return err
+			}
+			if len(merchants) == 0 {
+				return nil
+			}
+
+			var transactions []models.Transaction
+			if err := db.Where("merchant_id IS NULL").Find(&transactions).Error; err != nil {
+				return err
+			}
+
+			for _, txn := range transactions {
+				normalized := merchantsBackfillNormalize(txn.Description)
+				for _, merchant := range merchants {
+					matched := false
+					for _, pattern := range merchant.Patterns {
+						if merchant.IsRegex {
+							re, err := regexp.Compile(pattern)
+							if err == nil && re.MatchString(normalized) {
+								matched = true
+							}
+						} else if strings.Contains(strings.ToLower(normalized), strings.ToLower(pattern)) {
+							matched = true
+						}
+						if matched {
+							break
+						}
+					}
+					if matched {
+						if err := db.Model(&models.Transaction{}).Where("id = ?", txn.ID).Update("merchant_id", merchant.ID).Error; err != nil {
+							return err
+						}
+						break
+					}
+				}
+			}
+
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&models.Merchant{}); err != nil {
+				return err
+			}
+			if db.Migrator().HasColumn(&models.Transaction{}, "merchant_id") {
+				return db.Migrator().DropColumn(&models.Transaction{}, "merchant_id")
+			}
+			return nil
+		},
+	})
+}
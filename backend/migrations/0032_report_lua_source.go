@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding Report.LuaSource, letting a
+// saved report hold a user-authored Lua script (run through
+// services/reports.Service via controllers.RunCustomReport) as an
+// alternative to its declarative Definition.
+func init() {
+	register(Migration{
+		Version: 32,
+		Name:    "report_lua_source",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.Report{})
+		},
+		Down: func(db *gorm.DB) error {
+			if db.Migrator().HasColumn(&models.Report{}, "lua_source") {
+				return db.Migrator().DropColumn(&models.Report{}, "lua_source")
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding the report_securities/prices
+// tables and Account/Transaction.SecurityID, letting an account or
+// transaction be denominated in something other than its plain Currency
+// string tag (see models.ReportSecurity's own doc comment).
+func init() {
+	register(Migration{
+		Version: 33,
+		Name:    "securities",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.ReportSecurity{}, &models.Price{}, &models.Account{}, &models.Transaction{})
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&models.Price{}); err != nil {
+				return err
+			}
+			if err := db.Migrator().DropTable(&models.ReportSecurity{}); err != nil {
+				return err
+			}
+			if db.Migrator().HasColumn(&models.Account{}, "security_id") {
+				if err := db.Migrator().DropColumn(&models.Account{}, "security_id"); err != nil {
+					return err
+				}
+			}
+			if db.Migrator().HasColumn(&models.Transaction{}, "security_id") {
+				return db.Migrator().DropColumn(&models.Transaction{}, "security_id")
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding the report_schedules table -
+// standing instructions to re-run a spend_summary/cashflow/budget_progress/
+// custom report on a cron cadence and deliver it by email, webhook, or
+// local storage (see services/reports/chore).
+func init() {
+	register(Migration{
+		Version: 34,
+		Name:    "report_schedules",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.ReportSchedule{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.ReportSchedule{})
+		},
+	})
+}
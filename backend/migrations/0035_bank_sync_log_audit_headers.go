@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding BankSyncLog.RequestID/
+// PSUIPAddress/TPPRedirectURI, the Berlin Group audit headers
+// controllers/psd2.go now records against every consent/accounts/sync call.
+func init() {
+	register(Migration{
+		Version: 35,
+		Name:    "bank_sync_log_audit_headers",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.BankSyncLog{})
+		},
+		Down: func(db *gorm.DB) error {
+			for _, column := range []string{"request_id", "psu_ip_address", "tpp_redirect_uri"} {
+				if db.Migrator().HasColumn(&models.BankSyncLog{}, column) {
+					if err := db.Migrator().DropColumn(&models.BankSyncLog{}, column); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	})
+}
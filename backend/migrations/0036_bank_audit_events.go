@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration creating bank_audit_events, the
+// append-only PSD2 consent-usage trail controllers/bank_audit.go writes to.
+func init() {
+	register(Migration{
+		Version: 36,
+		Name:    "bank_audit_events",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.BankAuditEvent{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.BankAuditEvent{})
+		},
+	})
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding bank_webhook_events (the
+// per-provider-event-id dedup table controllers/bank_webhook.go writes to)
+// and bank_balance_alerts (the notification rows a balance.threshold
+// webhook emits).
+func init() {
+	register(Migration{
+		Version: 37,
+		Name:    "bank_webhook_events",
+		Up: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&models.BankWebhookEvent{}); err != nil {
+				return err
+			}
+			return db.AutoMigrate(&models.BankBalanceAlert{})
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&models.BankBalanceAlert{}); err != nil {
+				return err
+			}
+			return db.Migrator().DropTable(&models.BankWebhookEvent{})
+		},
+	})
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding budget_predictions, the table
+// controllers.persistPredictions writes to on every successful
+// predictWithResilience call, and that GetPredictionHistory/
+// GetPredictionAccuracy read back from.
+func init() {
+	register(Migration{
+		Version: 38,
+		Name:    "budget_predictions",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.BudgetPrediction{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.BudgetPrediction{})
+		},
+	})
+}
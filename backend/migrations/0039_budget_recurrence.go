@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding Budget.TemplateID and
+// Budget.RolloverUnusedCents, which controllers.MaterializeBudgetTemplate
+// sets on every budget it materializes from a recurring BudgetTemplate, and
+// controllers.CreateBudget's overlap check and GetUpcomingBudgetPeriods/
+// RolloverBudgetByID read back.
+func init() {
+	register(Migration{
+		Version: 39,
+		Name:    "budget_recurrence",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.Budget{})
+		},
+		Down: func(db *gorm.DB) error {
+			if db.Migrator().HasColumn(&models.Budget{}, "template_id") {
+				if err := db.Migrator().DropColumn(&models.Budget{}, "template_id"); err != nil {
+					return err
+				}
+			}
+			if db.Migrator().HasColumn(&models.Budget{}, "rollover_unused_cents") {
+				if err := db.Migrator().DropColumn(&models.Budget{}, "rollover_unused_cents"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
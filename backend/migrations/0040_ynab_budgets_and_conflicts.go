@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding YnabConnection.ConflictStrategy,
+// Transaction.YnabSyncHash, and the new YnabConflict table - the pieces
+// integrations/ynab.Sync needs to pull budgets and resolve per-connection
+// transaction conflicts instead of always overwriting the local row.
+func init() {
+	register(Migration{
+		Version: 40,
+		Name:    "ynab_budgets_and_conflicts",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(
+				&models.YnabConnection{},
+				&models.Transaction{},
+				&models.YnabConflict{},
+			)
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&models.YnabConflict{}); err != nil {
+				return err
+			}
+			if db.Migrator().HasColumn(&models.Transaction{}, "ynab_sync_hash") {
+				if err := db.Migrator().DropColumn(&models.Transaction{}, "ynab_sync_hash"); err != nil {
+					return err
+				}
+			}
+			if db.Migrator().HasColumn(&models.YnabConnection{}, "conflict_strategy") {
+				if err := db.Migrator().DropColumn(&models.YnabConnection{}, "conflict_strategy"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
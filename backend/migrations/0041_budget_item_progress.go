@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding the budget_item_progress
+// table store/budgets.BudgetStore caches a BudgetItem's computed spend in,
+// invalidated by the AfterSave/AfterDelete hooks on Transaction/
+// TransactionSplit in models/budget_item_progress.go.
+func init() {
+	register(Migration{
+		Version: 41,
+		Name:    "budget_item_progress",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.BudgetItemProgress{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.BudgetItemProgress{})
+		},
+	})
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding the budget_alerts and
+// budget_alert_events tables controllers.evaluateBudgetAlerts/
+// GetNotificationsStream read and write.
+func init() {
+	register(Migration{
+		Version: 42,
+		Name:    "budget_alerts",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.BudgetAlert{}, &models.BudgetAlertEvent{})
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&models.BudgetAlertEvent{}); err != nil {
+				return err
+			}
+			return db.Migrator().DropTable(&models.BudgetAlert{})
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding BankConnection.NeedsReauth, the
+// flag controllers.PlaidHandler.handlePlaidItemWebhook sets on a
+// PENDING_EXPIRATION webhook.
+func init() {
+	register(Migration{
+		Version: 43,
+		Name:    "bank_connection_needs_reauth",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.BankConnection{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropColumn(&models.BankConnection{}, "NeedsReauth")
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration creating plaid_link_sessions, which
+// backs CreateLinkToken/PlaidOAuthCallback's handling of Plaid's OAuth
+// institution redirect flow (see models.PlaidLinkSession).
+func init() {
+	register(Migration{
+		Version: 44,
+		Name:    "plaid_link_sessions",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.PlaidLinkSession{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.PlaidLinkSession{})
+		},
+	})
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration creating plaid_reauth_alerts (see
+// models.PlaidReauthAlert).
+func init() {
+	register(Migration{
+		Version: 45,
+		Name:    "plaid_reauth_alerts",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.PlaidReauthAlert{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&models.PlaidReauthAlert{})
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// init registers the schema migration adding User.Scopes, the per-user
+// Permission grants middleware.RequireScope checks alongside whatever the
+// user's Role already carries via role_permissions.
+func init() {
+	register(Migration{
+		Version: 46,
+		Name:    "user_scopes",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&models.User{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropColumn(&models.User{}, "Scopes")
+		},
+	})
+}
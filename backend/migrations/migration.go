@@ -0,0 +1,135 @@
+// Package migrations replaces the previous ad-hoc AutoMigrate-on-every-boot
+// approach with a numbered, versioned set of schema migrations, each
+// recorded in a schema_migrations table as it's applied. Data seeders are a
+// separate concern (see seeder.go) recorded in their own seed_history
+// table, so "has the schema been migrated" and "has demo data been seeded"
+// can be answered, re-run, and rolled back independently.
+package migrations
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is one numbered, idempotent schema change. Up must be safe to
+// re-run (AutoMigrate-based steps already are); Down reverses it so an
+// operator can roll back a single bad migration without touching anything
+// applied after it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(db *gorm.DB) error
+	Down    func(db *gorm.DB) error
+}
+
+// SchemaMigration records that Migration.Version has been applied.
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string { return "schema_migrations" }
+
+// All is the registered, ordered set of schema migrations. Migrations
+// register themselves from an init() in their own file (see
+// 0001_core_schema.go) - append new ones there with the next Version and
+// never edit a migration once it has shipped.
+var All []Migration
+
+func register(m Migration) {
+	All = append(All, m)
+}
+
+// Migrate applies every migration in All that isn't yet recorded in
+// schema_migrations, in order, each inside its own transaction.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("create schema_migrations table: %w", err)
+	}
+
+	for _, m := range All {
+		var count int64
+		if err := db.Model(&SchemaMigration{}).Where("version = ?", m.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, err)
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback reverts the single migration identified by version: it runs
+// that migration's Down step and removes its schema_migrations row.
+func Rollback(db *gorm.DB, version int) error {
+	var target *Migration
+	for i := range All {
+		if All[i].Version == version {
+			target = &All[i]
+			break
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("no migration registered with version %d", version)
+	}
+	if target.Down == nil {
+		return fmt.Errorf("migration %d_%s has no down step", target.Version, target.Name)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := target.Down(tx); err != nil {
+			return err
+		}
+		return tx.Where("version = ?", version).Delete(&SchemaMigration{}).Error
+	})
+}
+
+// MigrationStatus reports whether a registered migration has been applied.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Status reports the applied/pending state of every registered migration,
+// for the `seed status` CLI.
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var applied []SchemaMigration
+	if err := db.Find(&applied).Error; err != nil {
+		return nil, err
+	}
+	appliedByVersion := make(map[int]SchemaMigration, len(applied))
+	for _, a := range applied {
+		appliedByVersion[a.Version] = a
+	}
+
+	statuses := make([]MigrationStatus, 0, len(All))
+	for _, m := range All {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if a, ok := appliedByVersion[m.Version]; ok {
+			status.Applied = true
+			appliedAt := a.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,86 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Seeder is a named, idempotent data-seeding step, recorded independently
+// of schema migrations in seed_history. Unlike a Migration, a Seeder can be
+// deliberately re-applied (operators re-running a demo dataset) rather than
+// only ever moving forward.
+type Seeder interface {
+	Name() string
+	Run(db *gorm.DB) error
+}
+
+// SeedHistory records that a Seeder has run, and when it last ran.
+type SeedHistory struct {
+	ID    uint      `gorm:"primaryKey"`
+	Name  string    `gorm:"uniqueIndex;not null"`
+	RanAt time.Time `gorm:"not null"`
+}
+
+func (SeedHistory) TableName() string { return "seed_history" }
+
+// RunSeeder executes seeder unless it has already run and force is false,
+// recording (or updating) its seed_history row inside the same transaction
+// as the seeder's own writes. It reports whether the seeder actually ran.
+func RunSeeder(db *gorm.DB, seeder Seeder, force bool) (ran bool, err error) {
+	if err := db.AutoMigrate(&SeedHistory{}); err != nil {
+		return false, err
+	}
+
+	var history SeedHistory
+	err = db.Where("name = ?", seeder.Name()).First(&history).Error
+	switch {
+	case err == nil && !force:
+		return false, nil
+	case err != nil && err != gorm.ErrRecordNotFound:
+		return false, err
+	}
+
+	txErr := db.Transaction(func(tx *gorm.DB) error {
+		if err := seeder.Run(tx); err != nil {
+			return err
+		}
+		history.Name = seeder.Name()
+		history.RanAt = time.Now()
+		return tx.Save(&history).Error
+	})
+	if txErr != nil {
+		return false, txErr
+	}
+	return true, nil
+}
+
+// SeederStatus reports whether a named seeder has run, and when.
+type SeederStatus struct {
+	Name  string
+	RanAt *time.Time
+}
+
+// SeedersStatus reports the run state of each seeder, for the `seed
+// status` CLI.
+func SeedersStatus(db *gorm.DB, seeders []Seeder) ([]SeederStatus, error) {
+	if err := db.AutoMigrate(&SeedHistory{}); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]SeederStatus, 0, len(seeders))
+	for _, seeder := range seeders {
+		var history SeedHistory
+		err := db.Where("name = ?", seeder.Name()).First(&history).Error
+		switch {
+		case err == nil:
+			ranAt := history.RanAt
+			statuses = append(statuses, SeederStatus{Name: seeder.Name(), RanAt: &ranAt})
+		case err == gorm.ErrRecordNotFound:
+			statuses = append(statuses, SeederStatus{Name: seeder.Name()})
+		default:
+			return nil, err
+		}
+	}
+	return statuses, nil
+}
@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
 
 type AccountType string
 
@@ -11,18 +16,79 @@ const (
 	AccountCredit     AccountType = "credit"
 	AccountInvestment AccountType = "investment"
 	AccountOther      AccountType = "other"
+
+	// The following classify a node in the ParentAccountID hierarchy by its
+	// position in a MoneyGo-style chart of accounts (asset/liability/income/
+	// expense/equity), rather than describing a concrete bank product the
+	// way the types above do - a "Checking" leaf account would typically
+	// hang off an AccountAsset (or AccountBank) parent. Added for
+	// GetAccountTree/income-statement-style reporting; existing accounts
+	// keep their original Type untouched.
+	AccountBank       AccountType = "bank"
+	AccountAsset      AccountType = "asset"
+	AccountLiability  AccountType = "liability"
+	AccountIncome     AccountType = "income"
+	AccountExpense    AccountType = "expense"
+	AccountTrading    AccountType = "trading"
+	AccountEquity     AccountType = "equity"
+	AccountReceivable AccountType = "receivable"
+	AccountPayable    AccountType = "payable"
 )
 
 type Account struct {
-	ID                  uint        `gorm:"primaryKey" json:"id"`
-	UserID              uint        `gorm:"index;not null" json:"user_id"`
-	User                User        `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
-	Name                string      `gorm:"not null" json:"name"`
-	Type                AccountType `gorm:"type:text;not null" json:"account_type"`
+	ID     uint `gorm:"primaryKey" json:"id"`
+	UserID uint `gorm:"index;not null;index:idx_accounts_user_type,priority:1;index:idx_accounts_user_name,priority:1" json:"user_id"`
+	User   User `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+
+	// ParentAccountID nests this account under another of the same user's
+	// accounts (e.g. a "Checking" leaf under an "Assets" parent), building a
+	// chart of accounts GetAccountTree walks. Nil for a top-level account.
+	// CreateAccount/UpdateAccount reject a parent that doesn't belong to
+	// userID or would introduce a cycle.
+	ParentAccountID *uint    `gorm:"index" json:"parent_account_id,omitempty"`
+	ParentAccount   *Account `gorm:"foreignKey:ParentAccountID" json:"-"`
+
+	Name                string      `gorm:"not null;index:idx_accounts_user_name,priority:2" json:"name"`
+	Type                AccountType `gorm:"type:text;not null;index:idx_accounts_user_type,priority:2" json:"account_type"`
 	Currency            string      `gorm:"size:3;not null;default:USD" json:"currency"`
 	InitialBalanceCents int64       `gorm:"default:0" json:"initial_balance_cents"`
-	CurrentBalanceCents int64       `gorm:"default:0" json:"current_balance_cents"`
-	Description         string      `json:"description"`
-	CreatedAt           time.Time   `json:"created_at"`
-	UpdatedAt           time.Time   `json:"updated_at"`
+	// CurrentBalance is in Currency's major unit (e.g. dollars, not cents) -
+	// see Transaction.Amount's doc comment for why balances moved off
+	// integer cents.
+	CurrentBalance decimal.Decimal `gorm:"type:numeric(20,4);not null;default:0" json:"current_balance"`
+	Description    string          `json:"description"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+
+	// Soft-delete (see models.User for the grace-period/restore contract).
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	PurgeAfter *time.Time     `json:"purge_after,omitempty"`
+
+	// OFX Direct Connect metadata, set when this account is linked to a
+	// bank's OFX server rather than (or in addition to) Plaid. OFXPassword
+	// is stored as the base64 nonce||ciphertext blob psd2.EncryptSecret
+	// produces, never plaintext; SyncAccountOFX decrypts it for the
+	// duration of one sync and discards it.
+	OFXURL      string `json:"-"`
+	OFXOrg      string `json:"-"`
+	OFXFID      string `json:"-"`
+	OFXUser     string `json:"-"`
+	OFXPassword string `json:"-"`
+	OFXBankID   string `json:"-"`
+	OFXAcctType string `json:"-"`
+
+	// YnabAccountID is this account's id in YNAB, set only for accounts
+	// integrations/ynab.Sync created or matched; nil for every account not
+	// linked to a YNAB budget. Mirrors Category.ExternalID's role for
+	// round-tripping against an external system's own ids.
+	YnabAccountID *string `json:"-" gorm:"uniqueIndex"`
+
+	// SecurityID is the ReportSecurity (currency or investment holding)
+	// Amount/CurrentBalance are denominated in, letting this account hold
+	// something other than Currency - see ReportSecurity's own doc comment.
+	// Nil for an account created before this column existed, in which case
+	// callers asking for a report_currency conversion fall back to treating
+	// Currency as already matching (see controllers.convertAmount).
+	SecurityID *uint           `gorm:"index" json:"security_id,omitempty"`
+	Security   *ReportSecurity `json:"security,omitempty"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// AnomalyWebhook is one endpoint a user has registered to be notified when
+// services/anomaly flags one of their transactions - see
+// controllers.GetAnomalies for the same detection run synchronously, and
+// services/anomaly.NotifyAnomalies for the async POST this table drives.
+type AnomalyWebhook struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	UserID uint   `gorm:"index;not null" json:"user_id"`
+	User   User   `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	URL    string `gorm:"not null" json:"url"`
+
+	// Secret signs each delivered payload's X-Anomaly-Signature header
+	// (HMAC-SHA256 over the raw JSON body), so the receiving endpoint can
+	// verify the POST actually came from this server. Never serialized back
+	// to the owner once set.
+	Secret string `gorm:"not null" json:"-"`
+
+	// MinScore is the lowest anomaly.Anomaly.Score this webhook wants
+	// delivered; raising it quiets noisy endpoints without disabling
+	// detection itself.
+	MinScore  float64   `gorm:"not null;default:3.5" json:"min_score"`
+	CreatedAt time.Time `json:"created_at"`
+}
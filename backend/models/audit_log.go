@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// AuditLog records a single action taken against the admin API. Records form
+// a hash chain (PrevHash -> Hash) so a later operator cannot quietly edit or
+// delete history without the break being detectable by walking the chain.
+type AuditLog struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ActorUserID   uint      `gorm:"index" json:"actor_user_id"`
+	ActorUsername string    `json:"actor_username"`
+	Action        string    `gorm:"index" json:"action"`
+	TargetType    string    `gorm:"index" json:"target_type"`
+	TargetID      string    `json:"target_id"`
+	Method        string    `json:"method"`
+	Endpoint      string    `json:"endpoint"`
+	RequestDiff   string    `gorm:"type:text" json:"request_diff"`
+	RemoteIP      string    `json:"remote_ip"`
+	UserAgent     string    `json:"user_agent"`
+	Reason        string    `json:"reason"`
+	PrevHash      string    `json:"prev_hash"`
+	Hash          string    `gorm:"index" json:"hash"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// AuthAuditEvent records one authentication-relevant occurrence (register,
+// login success/failure, logout, refresh, MFA step-up, password change,
+// admin role change). Like AuditLog it forms a hash chain (PrevHash ->
+// Hash) so history can't be silently edited; unlike AuditLog it is written
+// off the hot request path by the buffered worker in
+// middleware/auth_audit.go, since these events fire on every login attempt.
+type AuthAuditEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"index" json:"user_id"`
+	EventType string    `gorm:"index" json:"event_type"`
+	Outcome   string    `gorm:"index" json:"outcome"`
+	Details   string    `gorm:"type:text" json:"details"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	PrevHash  string    `json:"prev_hash"`
+	Hash      string    `gorm:"index" json:"hash"`
+	Timestamp time.Time `gorm:"index" json:"timestamp"`
+}
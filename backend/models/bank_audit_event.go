@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// BankAuditEvent is an append-only record of one action taken against a
+// BankConnection - unlike BankSyncLog, which only covers sync outcomes,
+// this also covers reads, disconnects, and consent lifecycle changes.
+// PSD2 requires a TPP to retain evidence of how and when consent was used,
+// including after the connection itself is gone (soft-deleted by
+// DisconnectBank), so rows here are never updated or deleted.
+type BankAuditEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+
+	UserID           uint  `gorm:"index;not null" json:"user_id"`
+	BankConnectionID *uint `gorm:"index" json:"bank_connection_id"` // nil for actions that predate a connection existing (e.g. a failed create)
+
+	// Action is one of: viewed, connected, reauthenticated, synced,
+	// disconnected, consent_expired.
+	Action string `gorm:"index;not null" json:"action"`
+
+	RemoteIP  string `json:"remote_ip"`
+	UserAgent string `json:"user_agent"`
+	RequestID string `json:"request_id,omitempty"`
+
+	// Before/After snapshot the fields that matter for a mutating action
+	// (e.g. consent_status, status); both are nil for a read-only action
+	// like "viewed".
+	Before JSONB `json:"before,omitempty" gorm:"type:jsonb"`
+	After  JSONB `json:"after,omitempty" gorm:"type:jsonb"`
+}
+
+// BankAuditAction enumerates the valid BankAuditEvent.Action values.
+const (
+	BankAuditActionViewed          = "viewed"
+	BankAuditActionConnected       = "connected"
+	BankAuditActionReauthenticated = "reauthenticated"
+	BankAuditActionSynced          = "synced"
+	BankAuditActionDisconnected    = "disconnected"
+	BankAuditActionConsentExpired  = "consent_expired"
+)
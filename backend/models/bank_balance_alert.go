@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// BankBalanceAlert is one balance.threshold webhook a bank pushed (see
+// controllers/bank_webhook.go): a lightweight notification the frontend can
+// list/mark read, distinct from NotificationPreference, which only holds a
+// user's opt-in settings rather than individual events.
+type BankBalanceAlert struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	UserID           uint   `gorm:"index;not null" json:"user_id"`
+	BankConnectionID uint   `gorm:"index;not null" json:"bank_connection_id"`
+	AccountID        string `json:"account_id"`
+
+	Balance   string `json:"balance"`
+	Threshold string `json:"threshold"`
+
+	ReadAt *time.Time `json:"read_at,omitempty"`
+}
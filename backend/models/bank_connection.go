@@ -47,6 +47,13 @@ type BankConnection struct {
 	BankName     string `json:"bank_name" gorm:"not null"`     // "sparebanken_norge" or "bulder_bank"
 	BankEndpoint string `json:"bank_endpoint" gorm:"not null"` // https://psd2.spvapi.no or https://psd2-bulder.spvapi.no
 
+	// Provider names which aggregators.BankAggregator owns this connection
+	// ("plaid", "gocardless", "truelayer"), so sync/disconnect code can look
+	// the right aggregator up from the registry instead of assuming Plaid.
+	// Existing rows default to "plaid", the only provider in use before this
+	// column existed.
+	Provider string `json:"provider" gorm:"index;not null;default:plaid"`
+
 	// PSD2 Consent Information
 	ConsentID         string    `json:"consent_id" gorm:"uniqueIndex"`
 	ConsentStatus     string    `json:"consent_status"` // received, valid, rejected, expired, etc.
@@ -59,6 +66,12 @@ type BankConnection struct {
 	NextSyncAt *time.Time `json:"next_sync_at"`
 	SyncCount  int        `json:"sync_count" gorm:"default:0"`
 
+	// NeedsReauth is set by a Plaid PENDING_EXPIRATION webhook (see
+	// controllers.PlaidHandler.handlePlaidItemWebhook): the item still
+	// syncs today, but its consent is about to lapse, so the UI should
+	// prompt the user to re-link before Status flips to "error" on its own.
+	NeedsReauth bool `json:"needs_reauth" gorm:"default:false"`
+
 	// OAuth and sensitive data (for banks that use OAuth like Sparebank 1)
 	Metadata JSONB `json:"metadata" gorm:"type:jsonb"`
 
@@ -84,6 +97,7 @@ type BankAccount struct {
 
 	// Sync Information
 	LastTransactionSync *time.Time `json:"last_transaction_sync"`
+	LastSyncCursor      string     `json:"last_sync_cursor,omitempty"` // most recent upstream transaction ID processed, for incremental fetches
 	IsActive            bool       `json:"is_active" gorm:"default:true"`
 
 	// Link to internal account (optional)
@@ -98,13 +112,22 @@ type BankSyncLog struct {
 	BankConnectionID uint           `json:"bank_connection_id" gorm:"not null;index"`
 	BankConnection   BankConnection `json:"-" gorm:"foreignKey:BankConnectionID"`
 
-	SyncType          string `json:"sync_type"` // transactions, accounts, balances
-	Status            string `json:"status"`    // success, failed, partial
-	TransactionsFound int    `json:"transactions_found"`
-	TransactionsAdded int    `json:"transactions_added"`
-	ErrorMessage      string `json:"error_message,omitempty"`
+	SyncType            string `json:"sync_type"` // transactions, accounts, balances
+	Status              string `json:"status"`    // success, failed, partial
+	TransactionsFound   int    `json:"transactions_found"`
+	TransactionsAdded   int    `json:"transactions_added"`
+	TransactionsUpdated int    `json:"transactions_updated"`
+	ErrorMessage        string `json:"error_message,omitempty"`
 
 	// API Usage tracking
 	APICallsUsed int `json:"api_calls_used"`
 	SyncDuration int `json:"sync_duration_ms"`
+
+	// Berlin Group/NextGenPSD2 audit trail - the three headers a PSU-initiated
+	// XS2A call is required to carry, recorded here so a disputed sync or
+	// consent action can be traced back to the exact request the bank saw
+	// (see services/psd2.AuditHeaders).
+	RequestID      string `json:"request_id,omitempty"`
+	PSUIPAddress   string `json:"psu_ip_address,omitempty"`
+	TPPRedirectURI string `json:"tpp_redirect_uri,omitempty"`
 }
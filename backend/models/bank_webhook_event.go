@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// BankWebhookEvent dedups the asynchronous consent/transaction/balance
+// events ASPSPs and Plaid push to POST /api/banks/webhooks/:provider: each
+// (Provider, EventID) pair is accepted at most once, so a bank's retry of
+// an event we already acknowledged is a no-op rather than reprocessed.
+// ProcessedAt is nil until the background dispatch worker (see
+// controllers/bank_webhook.go) has actually applied the event's side
+// effects; a row with ProcessedAt still nil after a restart means the
+// in-memory queue was lost and the event was never acted on.
+type BankWebhookEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Provider string `gorm:"uniqueIndex:idx_bank_webhook_provider_event;not null" json:"provider"`
+	EventID  string `gorm:"uniqueIndex:idx_bank_webhook_provider_event;not null" json:"event_id"`
+
+	EventType        string `json:"event_type"`
+	BankConnectionID *uint  `gorm:"index" json:"bank_connection_id"`
+
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
+
+// BankWebhookEventType enumerates the EventType values
+// controllers.ReceiveBankWebhook dispatches on.
+const (
+	BankWebhookEventConsentRevoked     = "consent.revoked"
+	BankWebhookEventTransactionCreated = "transaction.created"
+	BankWebhookEventBalanceThreshold   = "balance.threshold"
+)
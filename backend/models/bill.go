@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// BillVendor is a payee a user can pay bills against, e.g. "City Power &
+// Water" or "Acme Mobile" - grouped by Category so GET
+// /api/bills/vendors?category=utilities can list just the vendors a bill
+// pay UI would show for that kind of bill. Vendors are global (not
+// per-user), mirroring how models.Category's system rows work.
+type BillVendor struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	Name     string `gorm:"not null" json:"name"`
+	Category string `gorm:"index;not null" json:"category"` // e.g. "utilities", "subscription", "insurance"
+
+	Products []BillProduct `json:"products,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BillProduct is one payable product/plan a BillVendor offers, e.g.
+// "Residential Electric" or "Unlimited Data Plan" - what GET
+// /api/bills/vendors/:id/products lists.
+type BillProduct struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	BillVendorID uint   `gorm:"index;not null" json:"bill_vendor_id"`
+	Name         string `gorm:"not null" json:"name"`
+	Description  string `json:"description"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Bill is a user's subscription to a BillVendor/BillProduct: a recurring
+// (or one-off) obligation to pay a known amount, optionally auto-paid from
+// Account. POST /api/bills/pay debits Account and posts a Transaction;
+// IsRecurring bills get NextDueDate advanced by controllers.StartBillScheduler
+// instead of being deleted once paid.
+type Bill struct {
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	UserID        uint         `gorm:"index;not null" json:"user_id"`
+	User          User         `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	BillVendorID  uint         `gorm:"index;not null" json:"bill_vendor_id"`
+	BillVendor    BillVendor   `json:"bill_vendor,omitempty"`
+	BillProductID *uint        `gorm:"index" json:"bill_product_id,omitempty"`
+	BillProduct   *BillProduct `json:"bill_product,omitempty"`
+
+	// CustomerRef is the user's account/customer number at the vendor,
+	// normally captured via POST /api/bills/lookup before the Bill is
+	// created.
+	CustomerRef string `json:"customer_ref"`
+
+	Nickname    string     `json:"nickname"`
+	AmountCents int64      `gorm:"not null" json:"amount_cents"`
+	AccountID   uint       `gorm:"index;not null" json:"account_id"`
+	Account     Account    `json:"account,omitempty"`
+	IsRecurring bool       `gorm:"not null;default:false" json:"is_recurring"`
+	RRule       string     `json:"rrule,omitempty"`
+	NextDueDate *time.Time `gorm:"index" json:"next_due_date,omitempty"`
+	LastPaidAt  *time.Time `json:"last_paid_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BillPayment records one POST /api/bills/:id/pay: the Transaction it
+// debited Account through, so a bill's payment history can be reconstructed
+// without re-deriving it from Transaction.Description matching. A row with
+// Reminder true instead marks a due-but-unpaid bill the scheduler surfaced
+// (see controllers.runDueBillReminders) - TransactionID/AmountCents are nil
+// and 0 respectively in that case, since nothing was actually paid yet.
+type BillPayment struct {
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	BillID        uint         `gorm:"index;not null" json:"bill_id"`
+	Bill          Bill         `json:"-"`
+	TransactionID *uint        `gorm:"index" json:"transaction_id,omitempty"`
+	Transaction   *Transaction `json:"transaction,omitempty"`
+	AmountCents   int64        `gorm:"not null;default:0" json:"amount_cents"`
+	PaidAt        time.Time    `gorm:"not null" json:"paid_at"`
+	Reminder      bool         `gorm:"not null;default:false;index" json:"reminder"`
+}
@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+    "time"
+
+    "github.com/shopspring/decimal"
+    "gorm.io/gorm"
+)
 
 type Budget struct {
     ID          uint         `gorm:"primaryKey" json:"id"`
@@ -11,12 +16,35 @@ type Budget struct {
     Currency    string       `gorm:"size:3;not null;default:USD" json:"currency"`
     Items       []BudgetItem `json:"items,omitempty"`
     CreatedAt   time.Time    `json:"created_at"`
+
+    // YnabBudgetID is this budget's id in YNAB, set only for the budget
+    // integrations/ynab.Sync materializes BudgetItem rows into; nil for
+    // every budget entered directly in this app.
+    YnabBudgetID *string `json:"-" gorm:"uniqueIndex"`
+
+    // TemplateID is the BudgetTemplate controllers.MaterializeBudgetTemplate
+    // generated this budget from, nil for a budget entered directly by the
+    // user. CreateBudget's overlap check only applies between budgets that
+    // share this nil-ness, so a recurring rule materializing this period
+    // doesn't stop the user from also creating an unrelated manual budget
+    // for it, and vice versa.
+    TemplateID *uint `gorm:"index" json:"template_id,omitempty"`
+
+    // RolloverUnusedCents is the signed per-category remaining balance the
+    // template's RolloverMode carried in from the previous period when this
+    // budget was materialized, summed across categories for display. Zero
+    // for a manual budget or a template with BudgetRolloverNone.
+    RolloverUnusedCents int64 `gorm:"not null;default:0" json:"rollover_unused_cents"`
+
+    // Soft-delete (see models.User for the grace-period/restore contract).
+    DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+    PurgeAfter *time.Time     `json:"purge_after,omitempty"`
 }
 
 type BudgetItem struct {
-    ID           uint     `gorm:"primaryKey" json:"id"`
-    BudgetID     uint     `gorm:"index;not null" json:"budget_id"`
-    CategoryID   uint     `gorm:"index;not null" json:"category_id"`
-    Category     Category `json:"category,omitempty"`
-    PlannedCents int64    `gorm:"not null" json:"planned_cents"`
+    ID             uint            `gorm:"primaryKey" json:"id"`
+    BudgetID       uint            `gorm:"index;not null" json:"budget_id"`
+    CategoryID     uint            `gorm:"index;not null" json:"category_id"`
+    Category       Category        `json:"category,omitempty"`
+    PlannedAmount  decimal.Decimal `gorm:"type:numeric(20,4);not null" json:"planned_amount"`
 }
\ No newline at end of file
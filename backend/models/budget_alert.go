@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// BudgetAlertChannel is where a fired BudgetAlert is delivered.
+type BudgetAlertChannel string
+
+const (
+	BudgetAlertChannelEmail   BudgetAlertChannel = "email"
+	BudgetAlertChannelWebhook BudgetAlertChannel = "webhook"
+	BudgetAlertChannelInApp   BudgetAlertChannel = "in_app"
+)
+
+// BudgetAlert is one user-configured threshold on a BudgetItem: the moment
+// its spend crosses ThresholdPercent of PlannedAmount,
+// controllers.evaluateBudgetAlerts (run from GetBudgets) delivers a
+// notifier.Notifier notification over Channel, then waits CooldownSeconds
+// before firing the same alert again even if spend keeps climbing.
+type BudgetAlert struct {
+	ID               uint               `gorm:"primaryKey" json:"id"`
+	UserID           uint               `gorm:"index;not null" json:"user_id"`
+	User             User               `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	BudgetItemID     uint               `gorm:"index;not null" json:"budget_item_id"`
+	ThresholdPercent float64            `gorm:"not null" json:"threshold_percent"`
+	Channel          BudgetAlertChannel `gorm:"type:text;not null;default:in_app" json:"channel"`
+
+	// Target is Channel's delivery address: the webhook URL for
+	// Channel=webhook, an email override for Channel=email (blank means the
+	// account's own User.Email), unused for Channel=in_app.
+	Target string `json:"target,omitempty"`
+
+	// Secret signs each Channel=webhook delivery's X-Budget-Alert-Signature
+	// header (see notifier.WebhookNotifier), the same HMAC-over-raw-body
+	// scheme AnomalyWebhook.Secret uses. Never serialized back to the
+	// owner once set, and unused for every other Channel.
+	Secret string `json:"-"`
+
+	// CooldownSeconds is how long after LastFiredAt this alert is
+	// suppressed even if spend is still above ThresholdPercent, so a
+	// category that stays over budget for the rest of the period doesn't
+	// refire on every request. 0 means evaluateBudgetAlerts' own default
+	// (24h).
+	CooldownSeconds int64      `gorm:"not null;default:0" json:"cooldown_seconds"`
+	LastFiredAt     *time.Time `json:"last_fired_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BudgetAlertEvent is one firing of a BudgetAlert - both the idempotency
+// record that stops evaluateBudgetAlerts from notifying for the same
+// (alert, period, threshold) combination twice, and the in-app feed row
+// GET /notifications/stream replays, mirroring how BankBalanceAlert doubles
+// as both a delivery record and a feed entry.
+type BudgetAlertEvent struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	UserID           uint      `gorm:"index;not null" json:"user_id"`
+	BudgetAlertID    uint      `gorm:"uniqueIndex:idx_budget_alert_event_key;not null" json:"budget_alert_id"`
+	PeriodStart      time.Time `gorm:"type:date;uniqueIndex:idx_budget_alert_event_key;not null" json:"period_start"`
+	ThresholdPercent float64   `gorm:"uniqueIndex:idx_budget_alert_event_key;not null" json:"threshold_percent"`
+
+	Message   string     `json:"message"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
+
+func (BudgetAlertEvent) TableName() string { return "budget_alert_events" }
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// BudgetGroup is a set of users who share expenses - a household, trip, or
+// roommate split - distinct from the per-user Budget envelope above it. Its
+// membership is BudgetGroupMember rows; a Transaction tagged with
+// BudgetGroupID and TransactionSplitShared is divided among those members
+// by controllers.GetGroupBalances instead of affecting only its payer.
+type BudgetGroup struct {
+	ID        uint                `gorm:"primaryKey" json:"id"`
+	Name      string              `gorm:"not null" json:"name"`
+	OwnerID   uint                `gorm:"index;not null" json:"owner_id"`
+	Owner     User                `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	Members   []BudgetGroupMember `json:"members,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// BudgetGroupMember is one user's membership in a BudgetGroup, carrying the
+// weight GetGroupBalances uses to divide shared transactions: a member's
+// fair share of a shared expense is (weight / sum of the group's weights) x
+// the expense amount, so an even split is just every member at weight 1.
+type BudgetGroupMember struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	GroupID   uint      `gorm:"uniqueIndex:idx_group_member;not null" json:"group_id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_group_member;index;not null" json:"user_id"`
+	User      User      `json:"user,omitempty"`
+	Weight    float64   `gorm:"not null;default:1" json:"weight"`
+	CreatedAt time.Time `json:"created_at"`
+}
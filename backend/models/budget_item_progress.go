@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BudgetItemProgress is store/budgets.BudgetStore's materialized cache row:
+// one BudgetItem's SpentCents as of a given calendar day, so GetBudgets/
+// GetBudget can serve a same-day total from a single indexed lookup instead
+// of re-aggregating transactions/transaction_splits on every request. The
+// AfterSave/AfterDelete hooks below delete the rows a changed Transaction or
+// TransactionSplit might have made stale, so a cache hit is never older than
+// the last write those hooks saw.
+type BudgetItemProgress struct {
+	ID           uint      `gorm:"primaryKey" json:"-"`
+	BudgetItemID uint      `gorm:"uniqueIndex:idx_budget_item_progress_item_date;not null" json:"budget_item_id"`
+	AsOf         time.Time `gorm:"type:date;uniqueIndex:idx_budget_item_progress_item_date;not null" json:"as_of"`
+	SpentCents   int64     `gorm:"not null" json:"spent_cents"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (BudgetItemProgress) TableName() string { return "budget_item_progress" }
+
+// invalidateBudgetItemProgress drops every cached BudgetItemProgress row for
+// a budget item that could cover txnDate within one of userID's budgets,
+// regardless of which category the write landed on - simpler than tracking
+// a transaction's previous CategoryID across an update, and no pricier than
+// the query it's saving a future repeat of.
+func invalidateBudgetItemProgress(tx *gorm.DB, userID uint, txnDate time.Time) error {
+	return tx.Exec(`
+		DELETE FROM budget_item_progress
+		WHERE budget_item_id IN (
+			SELECT budget_items.id FROM budget_items
+			JOIN budgets ON budgets.id = budget_items.budget_id
+			WHERE budgets.user_id = ? AND budgets.period_start <= ? AND budgets.period_end >= ?
+		)`, userID, txnDate, txnDate).Error
+}
+
+// AfterSave invalidates any cached progress a create or update to t may have
+// made stale.
+func (t *Transaction) AfterSave(tx *gorm.DB) error {
+	return invalidateBudgetItemProgress(tx, t.UserID, t.TxnDate)
+}
+
+// AfterDelete invalidates any cached progress a delete of t may have made
+// stale.
+func (t *Transaction) AfterDelete(tx *gorm.DB) error {
+	return invalidateBudgetItemProgress(tx, t.UserID, t.TxnDate)
+}
+
+// AfterSave invalidates any cached progress a create or update to s may have
+// made stale, looking its parent Transaction's UserID/TxnDate up since s
+// doesn't carry either directly.
+func (s *TransactionSplit) AfterSave(tx *gorm.DB) error {
+	return s.invalidateParentBudgetItemProgress(tx)
+}
+
+// AfterDelete invalidates any cached progress a delete of s may have made
+// stale.
+func (s *TransactionSplit) AfterDelete(tx *gorm.DB) error {
+	return s.invalidateParentBudgetItemProgress(tx)
+}
+
+func (s *TransactionSplit) invalidateParentBudgetItemProgress(tx *gorm.DB) error {
+	var parent Transaction
+	if err := tx.Select("user_id", "txn_date").First(&parent, s.ParentTxnID).Error; err != nil {
+		return err
+	}
+	return invalidateBudgetItemProgress(tx, parent.UserID, parent.TxnDate)
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// BudgetPrediction is one category's forecast from a single
+// predictWithResilience call (see controllers/ai_cache.go), persisted the
+// moment it's computed so GetPredictionHistory/GetPredictionAccuracy can
+// later chart it against what the user actually spent once the month
+// closes. RawResponse keeps the full ai.PredictResponse the row was
+// derived from, in case a future model version needs more than the
+// columns below captured.
+type BudgetPrediction struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	UserID     uint `gorm:"index;not null" json:"user_id"`
+	CategoryID uint `gorm:"index;not null" json:"category_id"`
+
+	TargetMonth int `gorm:"not null" json:"target_month"`
+	TargetYear  int `gorm:"not null" json:"target_year"`
+
+	PredictedCents  int64   `json:"predicted_cents"`
+	ConfidenceScore float64 `json:"confidence_score"`
+	ModelVersion    string  `json:"model_version"`
+
+	GeneratedAt time.Time `json:"generated_at"`
+	RawResponse JSONB     `json:"raw_response,omitempty" gorm:"type:jsonb"`
+}
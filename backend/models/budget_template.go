@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// BudgetCadence is how often a BudgetTemplate's next Budget period is cut.
+type BudgetCadence string
+
+const (
+	BudgetCadenceWeekly    BudgetCadence = "weekly"
+	BudgetCadenceMonthly   BudgetCadence = "monthly"
+	BudgetCadenceQuarterly BudgetCadence = "quarterly"
+	BudgetCadenceYearly    BudgetCadence = "yearly"
+)
+
+// BudgetRolloverMode controls how a previous period's per-category
+// remaining_cents carries into the next period's PlannedCents when a
+// BudgetTemplate is rolled over.
+type BudgetRolloverMode string
+
+const (
+	// BudgetRolloverNone starts every period fresh from the template's
+	// planned amounts.
+	BudgetRolloverNone BudgetRolloverMode = "none"
+	// BudgetRolloverCarryRemaining adds the previous period's signed
+	// remaining_cents (unspent or overspent) into the new PlannedCents.
+	BudgetRolloverCarryRemaining BudgetRolloverMode = "carry_remaining"
+	// BudgetRolloverCarryOverspend only carries the previous period forward
+	// when it ran over budget, subtracting the overspend from the new
+	// PlannedCents; unspent surplus is dropped rather than rolled forward.
+	BudgetRolloverCarryOverspend BudgetRolloverMode = "carry_overspend"
+)
+
+// BudgetTemplate is the recipe controllers.RolloverBudget (or the rollover
+// scheduler) materializes into a new Budget+BudgetItem set once the
+// previous period ends.
+type BudgetTemplate struct {
+	ID           uint                 `gorm:"primaryKey" json:"id"`
+	UserID       uint                 `gorm:"index;not null" json:"user_id"`
+	User         User                 `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	Name         string               `json:"name"`
+	Currency     string               `gorm:"size:3;not null;default:USD" json:"currency"`
+	Cadence      BudgetCadence        `gorm:"size:20;not null" json:"cadence"`
+	RolloverMode BudgetRolloverMode   `gorm:"size:20;not null;default:none" json:"rollover_mode"`
+	Items        []BudgetTemplateItem `json:"items,omitempty"`
+
+	// LastBudgetID is the most recently materialized Budget, used both to
+	// compute the next period's start date and, under a rollover mode, to
+	// look up the previous period's remaining_cents per category.
+	LastBudgetID *uint `json:"last_budget_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BudgetTemplateItem is one category's planned amount within a
+// BudgetTemplate, mirroring BudgetItem.
+type BudgetTemplateItem struct {
+	ID               uint     `gorm:"primaryKey" json:"id"`
+	BudgetTemplateID uint     `gorm:"index;not null" json:"budget_template_id"`
+	CategoryID       uint     `gorm:"index;not null" json:"category_id"`
+	Category         Category `json:"category,omitempty"`
+	PlannedCents     int64    `gorm:"not null" json:"planned_cents"`
+}
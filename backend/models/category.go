@@ -1,6 +1,13 @@
 package models
 
-import "time"
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 type CategoryKind string
 
@@ -9,13 +16,119 @@ const (
 	CategoryIncome  CategoryKind = "income"
 )
 
+// categoryPathSeparator delimits each ancestor ID in Category.Path, e.g.
+// "/12/47/103/" for category 103 under 47 under root category 12. Every
+// path starts and ends with the separator, so a `path LIKE 'prefix%'` scan
+// can never false-positive on an ID that merely shares a numeric prefix
+// (12 vs 120) - the separator right after the shared digits is part of the
+// stored prefix either way.
+const categoryPathSeparator = "/"
+
+// categoryPathFor returns what a category's own Path column should be,
+// given its parent's Path (empty for a root category) and its own ID.
+func categoryPathFor(parentPath string, id uint) string {
+	if parentPath == "" {
+		parentPath = categoryPathSeparator
+	}
+	return parentPath + strconv.FormatUint(uint64(id), 10) + categoryPathSeparator
+}
+
+// CategoryIsDescendantPath reports whether a category whose own Path is
+// path sits anywhere below ancestorID, by checking whether ancestorID
+// appears as one of path's segments.
+func CategoryIsDescendantPath(path string, ancestorID uint) bool {
+	marker := categoryPathSeparator + strconv.FormatUint(uint64(ancestorID), 10) + categoryPathSeparator
+	return strings.Contains(path, marker)
+}
+
 type Category struct {
-	ID          uint         `gorm:"primaryKey" json:"id"`
-	UserID      uint         `gorm:"index;not null" json:"user_id"`
-	User        User         `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
-	Name        string       `gorm:"not null" json:"name"`
-	ParentID    *uint        `gorm:"index" json:"parent_id"`
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	UserID   uint   `gorm:"index;not null;uniqueIndex:idx_category_user_external_id" json:"user_id"`
+	User     User   `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	Name     string `gorm:"not null" json:"name"`
+	ParentID *uint  `gorm:"index" json:"parent_id"`
+
+	// ExternalID is a stable identifier for import/export round-tripping
+	// across environments, where the auto-increment ID won't match. Set
+	// once at create time (see BeforeCreate) and never reassigned.
+	ExternalID string `gorm:"uniqueIndex:idx_category_user_external_id;not null" json:"external_id"`
+
 	Kind        CategoryKind `gorm:"type:text;not null" json:"kind"`
 	Description *string      `gorm:"type:text" json:"description"`
 	CreatedAt   time.Time    `json:"created_at"`
+
+	// Path and Depth are a materialized path over ParentID (e.g. Path
+	// "/12/47/103/" for category 103 under 47 under root category 12, with
+	// Depth 2), stamped on insert by AfterCreate and kept correct across
+	// moves by db.ReparentCategory. They turn "is X an ancestor/descendant
+	// of Y" and "list X's subtree" into a single indexed `path LIKE
+	// 'prefix%'` scan instead of an O(depth) ParentID walk.
+	Path  string `gorm:"index;not null;default:''" json:"-"`
+	Depth int    `gorm:"not null;default:0" json:"depth"`
+
+	// IsSystem marks a category as part of the shared, curated taxonomy
+	// owned by the reserved system user (see seed.SystemCategorySeeder)
+	// instead of a real user's own chart of accounts. It's seeded once and
+	// visible to every user, subject to that user's own CategoryOverride.
+	IsSystem bool `gorm:"not null;default:false;index" json:"is_system"`
+
+	// Soft-delete (see models.User for the grace-period/restore contract).
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	PurgeAfter *time.Time     `json:"purge_after,omitempty"`
+}
+
+// BeforeCreate assigns a new category its ExternalID, unless the caller
+// (e.g. the importer, restoring an external_id from a previous export)
+// already supplied one.
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	if c.ExternalID == "" {
+		c.ExternalID = uuid.NewString()
+	}
+	return nil
+}
+
+// AfterCreate stamps a newly-inserted category's Path/Depth from its
+// parent's, now that auto-increment has assigned its ID. Runs inside the
+// same transaction GORM wraps Create in, so a category is never observable
+// without a correct path. Reparenting an existing category (ParentID
+// changing on an update) is handled separately by db.ReparentCategory,
+// which also rewrites every descendant's path in one statement.
+func (c *Category) AfterCreate(tx *gorm.DB) error {
+	parentPath, depth := "", 0
+	if c.ParentID != nil {
+		var parent Category
+		if err := tx.Select("path", "depth").Where("id = ?", *c.ParentID).First(&parent).Error; err != nil {
+			return err
+		}
+		parentPath, depth = parent.Path, parent.Depth+1
+	}
+
+	path := categoryPathFor(parentPath, c.ID)
+	if err := tx.Model(c).UpdateColumns(map[string]interface{}{"path": path, "depth": depth}).Error; err != nil {
+		return err
+	}
+	c.Path = path
+	c.Depth = depth
+	return nil
 }
+
+// CategoryOverride is one user's personalization of a system category:
+// a custom display name and/or description, and/or hiding it from that
+// user's view entirely. It never touches the underlying system Category
+// row, so every user can personalize independently without forking the
+// shared taxonomy.
+type CategoryOverride struct {
+	ID               uint     `gorm:"primaryKey" json:"id"`
+	UserID           uint     `gorm:"uniqueIndex:idx_category_override_user_system_category;not null" json:"user_id"`
+	User             User     `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	SystemCategoryID uint     `gorm:"uniqueIndex:idx_category_override_user_system_category;not null" json:"system_category_id"`
+	SystemCategory   Category `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	DisplayName      *string  `json:"display_name,omitempty"`
+	Description      *string  `json:"description,omitempty"`
+	Hidden           bool     `gorm:"not null;default:false" json:"hidden"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (CategoryOverride) TableName() string { return "category_overrides" }
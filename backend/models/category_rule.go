@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// CategoryRuleMatchField is which transaction field (or, for the two
+// Plaid-sync-only kinds below, which piece of sync context) a CategoryRule
+// is tested against.
+type CategoryRuleMatchField string
+
+const (
+	CategoryRuleMatchDescription CategoryRuleMatchField = "description"
+	CategoryRuleMatchMerchant    CategoryRuleMatchField = "merchant"
+	CategoryRuleMatchMemo        CategoryRuleMatchField = "memo"
+
+	// CategoryRuleMatchPlaidCategory matches Pattern (case-insensitive
+	// substring, or regex if IsRegex) against any entry of the Plaid
+	// category array a /transactions/sync item carries. Only
+	// evaluable during Plaid ingestion - see
+	// controllers.MatchCategoryRuleContext - since Transaction itself
+	// doesn't persist the raw Plaid categories.
+	CategoryRuleMatchPlaidCategory CategoryRuleMatchField = "plaid_category"
+
+	// CategoryRuleMatchAmountRange ignores Pattern/IsRegex and instead
+	// matches AmountMinCents/AmountMaxCents (either bound may be nil for
+	// an open range) against the transaction's signed AmountCents.
+	CategoryRuleMatchAmountRange CategoryRuleMatchField = "amount_range"
+
+	// CategoryRuleMatchAccountID ignores Pattern/IsRegex and matches
+	// MatchAccountID against the transaction's AccountID.
+	CategoryRuleMatchAccountID CategoryRuleMatchField = "account_id"
+)
+
+// CategoryRule is one user's auto-categorization rule: whenever a
+// transaction matches MatchField (by Pattern for the text-based kinds, or
+// by AmountMinCents/AmountMaxCents/MatchAccountID for the structured
+// kinds), CategoryID is a candidate for that transaction. Enabled rules are
+// evaluated in descending Priority order (ties broken by ID) and the first
+// match wins - see controllers.MatchCategoryRuleContext. Plaid sync (see
+// plaid_api.go's applyPlaidTransactionAdd) evaluates these before falling
+// back to the built-in matchPlaidCategory/matchByMerchantName tables, so a
+// user can fix a miscategorization without a code change.
+//
+// Transaction has no dedicated merchant/memo columns, so
+// CategoryRuleMatchDescription and CategoryRuleMatchMerchant both read
+// Transaction.Description (where bank imports already put the payee name,
+// see plaid_api.go's matchByMerchantName) and CategoryRuleMatchMemo reads
+// Transaction.Notes.
+type CategoryRule struct {
+	ID         uint                   `gorm:"primaryKey" json:"id"`
+	UserID     uint                   `gorm:"index;not null" json:"user_id"`
+	User       User                   `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	Pattern    string                 `json:"pattern"`
+	IsRegex    bool                   `gorm:"not null;default:false" json:"is_regex"`
+	MatchField CategoryRuleMatchField `gorm:"type:text;not null" json:"match_field"`
+	CategoryID uint                   `gorm:"index;not null" json:"category_id"`
+	Category   Category               `json:"category,omitempty"`
+	Priority   int                    `gorm:"not null;default:0" json:"priority"`
+	Enabled    bool                   `gorm:"not null;default:true" json:"enabled"`
+
+	// AmountMinCents/AmountMaxCents back CategoryRuleMatchAmountRange; both
+	// nil-able so a rule can be "under 5000" or "over -10000" as well as a
+	// closed range.
+	AmountMinCents *int64 `json:"amount_min_cents,omitempty"`
+	AmountMaxCents *int64 `json:"amount_max_cents,omitempty"`
+
+	// MatchAccountID backs CategoryRuleMatchAccountID.
+	MatchAccountID *uint    `gorm:"index" json:"match_account_id,omitempty"`
+	MatchAccount   *Account `json:"match_account,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// FxRate is one day's Base->Quote conversion rate, cached locally so
+// services/fx doesn't refetch the provider for every conversion. Date is
+// truncated to midnight UTC; (base, quote, date) is unique so a re-fetched
+// rate for a day already cached upserts in place rather than duplicating.
+type FxRate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Base      string    `gorm:"size:3;not null;uniqueIndex:idx_fx_rate_base_quote_date" json:"base"`
+	Quote     string    `gorm:"size:3;not null;uniqueIndex:idx_fx_rate_base_quote_date" json:"quote"`
+	Date      time.Time `gorm:"type:date;not null;uniqueIndex:idx_fx_rate_base_quote_date" json:"date"`
+	Rate      float64   `gorm:"not null" json:"rate"`
+	CreatedAt time.Time `json:"created_at"`
+}
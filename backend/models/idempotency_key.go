@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// IdempotencyKey records the outcome of a request made with an
+// Idempotency-Key header so a retried request can be answered from cache
+// instead of re-executing (and potentially double-posting).
+type IdempotencyKey struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	UserID         uint      `gorm:"uniqueIndex:idx_user_key;not null" json:"user_id"`
+	Key            string    `gorm:"uniqueIndex:idx_user_key;not null" json:"key"`
+	RequestHash    string    `gorm:"not null" json:"request_hash"`
+	ResponseStatus int       `gorm:"not null" json:"response_status"`
+	ResponseBody   string    `gorm:"type:text;not null" json:"response_body"`
+	ExpiresAt      time.Time `gorm:"index;not null" json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
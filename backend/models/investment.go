@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Security is a tradeable instrument a Holding references. Securities are
+// global (not per-user) reference data, the same way models.BillVendor's
+// catalog rows are shared across users rather than duplicated per account.
+type Security struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Ticker      string `gorm:"uniqueIndex;not null" json:"ticker"`
+	Name        string `json:"name"`
+	Type        string `json:"type"` // equity, etf, mutual_fund, cash, derivative, etc.
+	ISOCurrency string `gorm:"column:iso_currency;size:3;default:USD" json:"iso_currency"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Holding is one user's position in a Security inside one of their
+// Accounts, upserted by controllers.SyncPlaidInvestments from
+// /investments/holdings/get. PlaidAccountID/PlaidSecurityID are the
+// upstream identifiers a re-sync matches this row on instead of creating a
+// duplicate.
+type Holding struct {
+	ID         uint     `gorm:"primaryKey" json:"id"`
+	UserID     uint     `gorm:"index;not null" json:"user_id"`
+	AccountID  uint     `gorm:"index;not null" json:"account_id"`
+	Account    Account  `json:"account,omitempty"`
+	SecurityID uint     `gorm:"index;not null" json:"security_id"`
+	Security   Security `json:"security,omitempty"`
+
+	Quantity              float64 `json:"quantity"`
+	CostBasisCents        int64   `json:"cost_basis_cents"`
+	InstitutionValueCents int64   `json:"institution_value_cents"`
+	Currency              string  `gorm:"size:3;default:USD" json:"currency"`
+
+	PlaidAccountID  string `gorm:"index" json:"-"`
+	PlaidSecurityID string `gorm:"index" json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// LedgerDirection is the side of a double-entry posting.
+type LedgerDirection string
+
+const (
+	LedgerDebit  LedgerDirection = "debit"
+	LedgerCredit LedgerDirection = "credit"
+)
+
+// LedgerEntry is one posting against an account. Every Transaction (and every
+// Transfer) must produce two or more entries whose amounts sum to zero, so
+// account balances can always be recomputed by summing entries rather than
+// trusting a mutable running total.
+type LedgerEntry struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	AccountID   uint            `gorm:"index;not null" json:"account_id"`
+	Account     Account         `json:"account,omitempty"`
+	TxnID       *uint           `gorm:"index" json:"txn_id,omitempty"`
+	Transaction *Transaction    `json:"transaction,omitempty"`
+	AmountCents int64           `gorm:"not null" json:"amount_cents"` // signed: +credit, -debit
+	Direction   LedgerDirection `gorm:"type:text;not null" json:"direction"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Transfer records a single movement of money between two of a user's
+// accounts. It is backed by exactly two LedgerEntry rows (one debit, one
+// credit) created atomically; Transfer.ID is the stable "group id" GET/DELETE
+// /accounts/transfers/:group_id address, since DebitEntryID/CreditEntryID
+// already link the pair without a separate UUID.
+type Transfer struct {
+	ID            uint  `gorm:"primaryKey" json:"id"`
+	UserID        uint  `gorm:"index;not null" json:"user_id"`
+	User          User  `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	FromAccountID uint  `gorm:"index;not null" json:"from_account_id"`
+	ToAccountID   uint  `gorm:"index;not null" json:"to_account_id"`
+	AmountCents   int64 `gorm:"not null" json:"amount_cents"`
+	// ToAmountCents and FXRate are only set when FromAccount and ToAccount
+	// don't share a currency: AmountCents is debited from the source in its
+	// own currency, ToAmountCents is credited to the destination in its
+	// currency, and FXRate records ToAmountCents/AmountCents for display.
+	ToAmountCents *int64     `json:"to_amount_cents,omitempty"`
+	FXRate        *float64   `json:"fx_rate,omitempty"`
+	Description   string     `json:"description"`
+	TransferID    string     `gorm:"uniqueIndex;not null" json:"transfer_id"` // client-supplied idempotency key
+	DebitEntryID  uint       `json:"debit_entry_id"`
+	CreditEntryID uint       `json:"credit_entry_id"`
+	ReversedAt    *time.Time `json:"reversed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// MerchantPatterns is a JSON-encoded []string stored as TEXT, so it works
+// on SQLite as well as Postgres (unlike JSONB, which needs a native jsonb
+// column - see JSONB's own comment).
+type MerchantPatterns []string
+
+// Value implements driver.Valuer for MerchantPatterns.
+func (p MerchantPatterns) Value() (driver.Value, error) {
+	if p == nil {
+		return "[]", nil
+	}
+	encoded, err := json.Marshal([]string(p))
+	return string(encoded), err
+}
+
+// Scan implements sql.Scanner for MerchantPatterns.
+func (p *MerchantPatterns) Scan(value interface{}) error {
+	if value == nil {
+		*p = nil
+		return nil
+	}
+
+	var raw []byte
+	switch v := value.(type) {
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return errors.New("type assertion to []byte or string failed")
+	}
+	return json.Unmarshal(raw, (*[]string)(p))
+}
+
+// Merchant is a canonical payee that many differently-formatted raw
+// transaction descriptions ("AMZN Mktp US*A1B2C3", "SQ *AMAZON.COM") are
+// normalized to (see NormalizeDescription/MatchMerchant), so
+// GetTopMerchants can group by merchant instead of by raw description, and
+// new transactions can inherit DefaultCategoryID without a user having to
+// set up a CategoryRule for every spelling of the same payee.
+type Merchant struct {
+	ID     uint `gorm:"primaryKey" json:"id"`
+	UserID uint `gorm:"index;not null;uniqueIndex:idx_merchant_user_name" json:"user_id"`
+	User   User `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+
+	CanonicalName string `gorm:"not null;uniqueIndex:idx_merchant_user_name" json:"canonical_name"`
+
+	// Patterns matches against a transaction's noise-stripped description
+	// (NormalizeDescription) case-insensitively, substring by default or as
+	// a regular expression when IsRegex - the same two-mode matching
+	// CategoryRule.Pattern uses.
+	Patterns MerchantPatterns `gorm:"type:text" json:"patterns"`
+	IsRegex  bool             `json:"is_regex"`
+
+	// DefaultCategoryID is the category new, uncategorized transactions
+	// matching this merchant are suggested - see
+	// SuggestCategoryForMerchant, called from CreateTransaction after
+	// CategoryRule matching comes up empty.
+	DefaultCategoryID *uint     `gorm:"index" json:"default_category_id,omitempty"`
+	DefaultCategory   *Category `json:"default_category,omitempty"`
+
+	// IsSystem marks a merchant as part of the shared, curated seed set
+	// visible to every user, the same convention Category.IsSystem uses.
+	IsSystem  bool      `gorm:"not null;default:false;index" json:"is_system"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// NetWorthSnapshot is one day's point-in-time net worth, persisted daily by
+// controllers.StartNetWorthSnapshotScheduler so GET /api/networth can serve
+// a real time series instead of reconstructing one from transaction deltas
+// the way reports.go's runNetWorthTabulation does for its on-demand report.
+// CashCents sums Account.CurrentBalanceCents; InvestmentsCents sums
+// Holding.InstitutionValueCents.
+type NetWorthSnapshot struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	UserID           uint      `gorm:"uniqueIndex:idx_user_snapshot_date;not null" json:"user_id"`
+	SnapshotDate     time.Time `gorm:"type:date;uniqueIndex:idx_user_snapshot_date;not null" json:"snapshot_date"`
+	CashCents        int64     `json:"cash_cents"`
+	InvestmentsCents int64     `json:"investments_cents"`
+	TotalCents       int64     `json:"total_cents"`
+	CreatedAt        time.Time `json:"created_at"`
+}
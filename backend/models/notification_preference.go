@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// NotificationCadence is how often controllers.StartDigestScheduler emails a
+// user their spend/cashflow/budget digest.
+type NotificationCadence string
+
+const (
+	NotificationCadenceWeekly  NotificationCadence = "weekly"
+	NotificationCadenceMonthly NotificationCadence = "monthly"
+)
+
+// NotificationPreference is one user's opt-in to the periodic digest email
+// (see controllers.StartDigestScheduler) and to over-budget push alerts.
+// One row per user; absent means the user gets no digest and no alerts.
+type NotificationPreference struct {
+	UserID uint `gorm:"primaryKey" json:"user_id"`
+	User   User `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+
+	Cadence NotificationCadence `gorm:"type:text;not null;default:weekly" json:"cadence"`
+
+	// DayOfWeek is which day a weekly digest goes out, 0=Sunday..6=Saturday
+	// (time.Weekday's own numbering). Ignored for a monthly cadence, which
+	// always sends on the 1st.
+	DayOfWeek int `gorm:"not null;default:1" json:"day_of_week"`
+
+	// OverBudgetAlertsEnabled toggles an immediate push/email the moment a
+	// budget category crosses 100% progress, independent of the digest's
+	// own cadence.
+	OverBudgetAlertsEnabled bool `gorm:"not null;default:true" json:"over_budget_alerts_enabled"`
+
+	// LastSentAt is when StartDigestScheduler last emailed this user's
+	// digest, so a tick that finds "today is the right day" doesn't re-send
+	// if it already ran earlier the same day.
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// OAuthIdentity links an external identity provider account (Google,
+// GitHub, ...) to a local User, the same way bank_connection.go links a
+// bank account to a user. (provider, subject) is the provider's stable
+// identifier for the end user and is unique across all identities.
+type OAuthIdentity struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID uint `json:"user_id" gorm:"not null;index"`
+	User   User `json:"-" gorm:"foreignKey:UserID"`
+
+	Provider string `json:"provider" gorm:"not null;uniqueIndex:idx_oauth_provider_subject"` // "google" or "github"
+	Subject  string `json:"subject" gorm:"not null;uniqueIndex:idx_oauth_provider_subject"`  // provider's stable user ID
+	Email    string `json:"email"`
+}
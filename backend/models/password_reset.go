@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// PasswordReset is one outstanding POST /password/forgot request. The raw
+// token is only ever emailed to the user - TokenHash (sha256 of it) is what
+// gets looked up by POST /password/reset, same reasoning as
+// UserOTP.BackupCodes never storing the plaintext code.
+type PasswordReset struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	UserID uint `json:"user_id" gorm:"not null;index"`
+	User   User `json:"-" gorm:"foreignKey:UserID"`
+
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+}
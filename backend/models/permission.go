@@ -0,0 +1,41 @@
+package models
+
+// Permission is a fine-grained capability a role can be granted. Route
+// handlers are wired to exactly the permission they need (see
+// middleware.RequirePermission), replacing the old binary admin/non-admin
+// gate that sat in front of the whole admin API.
+type Permission string
+
+const (
+	PermUsersRead          Permission = "perm.users.read"
+	PermUsersWrite         Permission = "perm.users.write"
+	PermUsersDelete        Permission = "perm.users.delete"
+	PermTransactionsRead   Permission = "perm.transactions.read"
+	PermTransactionsDelete Permission = "perm.transactions.delete"
+	PermAccountsRead       Permission = "perm.accounts.read"
+	PermAccountsWrite      Permission = "perm.accounts.write"
+	PermAccountsDelete     Permission = "perm.accounts.delete"
+	PermCategoriesRead     Permission = "perm.categories.read"
+	PermCategoriesDelete   Permission = "perm.categories.delete"
+	PermBudgetsRead        Permission = "perm.budgets.read"
+	PermBudgetsDelete      Permission = "perm.budgets.delete"
+	PermAuditRead          Permission = "perm.audit.read"
+	PermDashboardRead      Permission = "perm.dashboard.read"
+	PermRoleAssign         Permission = "perm.role.assign"
+	PermRoleManage         Permission = "perm.role.manage"
+	PermSeedManage         Permission = "perm.seed.manage"
+	PermAIManage           Permission = "perm.ai.manage"
+)
+
+// AllPermissions lists every permission known to the system, used to
+// validate PUT /admin/roles/:name/permissions payloads.
+var AllPermissions = []Permission{
+	PermUsersRead, PermUsersWrite, PermUsersDelete,
+	PermTransactionsRead, PermTransactionsDelete,
+	PermAccountsRead, PermAccountsWrite, PermAccountsDelete,
+	PermCategoriesRead, PermCategoriesDelete,
+	PermBudgetsRead, PermBudgetsDelete,
+	PermAuditRead, PermDashboardRead,
+	PermRoleAssign, PermRoleManage,
+	PermSeedManage, PermAIManage,
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// PlaidLinkSession records one CreateLinkToken call that asked for Plaid's
+// OAuth institution redirect (RedirectURI set), so the browser round-trip
+// through the bank's own OAuth page - which leaves this backend entirely for
+// however long the user takes at the bank - has something durable to resume
+// from when it comes back to /api/plaid/oauth/callback. The in-memory/Redis
+// oauthFlowStore in oauth_controller.go covers the equivalent state for
+// social login, but that flow never leaves this process's request/response
+// cycle for more than a few seconds, so a DB row is the better fit here.
+type PlaidLinkSession struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	User      User      `json:"-" gorm:"foreignKey:UserID"`
+
+	// LinkToken is the link_token CreateLinkToken returned alongside State -
+	// the callback hands it back to the frontend so Link can be re-opened in
+	// OAuth-continuation mode.
+	LinkToken string `json:"-" gorm:"not null"`
+
+	// State is the nonce CreateLinkToken generated (via generateOAuthState)
+	// and the frontend threads through the bank's redirect_uri as
+	// oauth_state_id, so the callback can find this row again.
+	State string `json:"-" gorm:"uniqueIndex;not null"`
+
+	// RedirectURI is the redirect_uri this session's Link token was created
+	// with, echoed back so the callback can validate it's completing the
+	// same flow it started.
+	RedirectURI string `json:"-"`
+
+	// BankConnectionID is set when this Link session is re-authorizing an
+	// existing, login_required BankConnection rather than linking a new one -
+	// ExchangePublicToken's update mode keys off this the same way the
+	// frontend does.
+	BankConnectionID *uint `json:"-"`
+
+	// ExpiresAt bounds how long a user can take at the bank's OAuth page
+	// before the session is no longer honored.
+	ExpiresAt time.Time `json:"-"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// PlaidReauthAlert is a lightweight notification the frontend can list/mark
+// read - the same role BankBalanceAlert plays for balance thresholds - for
+// the first time a BankConnection's NeedsReauth flips to true, whether that
+// was a PENDING_EXPIRATION webhook (see
+// controllers.PlaidHandler.handlePlaidItemWebhook) or
+// controllers.runPlaidConsentExpiryScan catching an about-to-lapse consent
+// no webhook announced.
+type PlaidReauthAlert struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	UserID           uint `gorm:"index;not null" json:"user_id"`
+	BankConnectionID uint `gorm:"index;not null" json:"bank_connection_id"`
+
+	// Reason is why NeedsReauth was set: "consent_expiring" (the scheduler's
+	// poll-based scan) or "pending_expiration" (the matching webhook code).
+	Reason string `json:"reason"`
+
+	ReadAt *time.Time `json:"read_at,omitempty"`
+}
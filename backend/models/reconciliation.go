@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// AccountReconciliation records one completed reconciliation of an account
+// against a bank statement: who ran it, when, the statement's ending
+// balance, and how many transactions were flipped from Cleared to
+// Reconciled. It is an append-only history, not a hash chain like AuditLog -
+// reconciliations aren't security-sensitive, just useful to look back on.
+type AccountReconciliation struct {
+	ID                    uint      `gorm:"primaryKey" json:"id"`
+	AccountID             uint      `gorm:"index;not null" json:"account_id"`
+	UserID                uint      `gorm:"index;not null" json:"user_id"`
+	StatementDate         time.Time `gorm:"type:date;not null" json:"statement_date"`
+	StatementBalanceCents int64     `gorm:"not null" json:"statement_balance_cents"`
+	TransactionCount      int       `gorm:"not null" json:"transaction_count"`
+	CreatedAt             time.Time `json:"created_at"`
+}
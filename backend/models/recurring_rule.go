@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// RecurringRule describes a transaction that should be materialized
+// automatically on a schedule described by an RFC 5545 RRULE string (e.g.
+// "FREQ=MONTHLY;BYMONTHDAY=1").
+type RecurringRule struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"index;not null" json:"user_id"`
+	User        User       `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	AccountID   uint       `gorm:"index;not null" json:"account_id"`
+	CategoryID  *uint      `gorm:"index" json:"category_id"`
+	AmountCents int64      `gorm:"not null" json:"amount_cents"`
+	Description string     `json:"description"`
+	RRule       string     `gorm:"not null" json:"rrule"`
+	NextRun     time.Time  `gorm:"index;not null" json:"next_run"`
+	EndDate     *time.Time `json:"end_date"`
+
+	// LastMaterializedAt guards at-most-once materialization per occurrence:
+	// a run is only allowed to post when NextRun is still in the past AND
+	// LastMaterializedAt hasn't already advanced past it.
+	LastMaterializedAt *time.Time `json:"last_materialized_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
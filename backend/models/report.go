@@ -0,0 +1,80 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ReportDimension is what a Report groups its rows by.
+type ReportDimension string
+
+const (
+	// ReportDimensionCategoryMonth buckets expense/income by category per
+	// calendar month.
+	ReportDimensionCategoryMonth ReportDimension = "category_month"
+	// ReportDimensionBudgetVariance compares a budget's per-category
+	// planned_cents against actual spend over the budget's period.
+	ReportDimensionBudgetVariance ReportDimension = "budget_variance"
+	// ReportDimensionNetWorth reconstructs a month-by-month net worth
+	// series from accounts.current_balance_cents and each account's
+	// transaction history.
+	ReportDimensionNetWorth ReportDimension = "net_worth"
+	// ReportDimensionTopCounterparties ranks transaction descriptions by
+	// total spend.
+	ReportDimensionTopCounterparties ReportDimension = "top_counterparties"
+)
+
+// ReportDefinition describes how controllers.RunReport should group,
+// filter, and aggregate a Report, stored as a single JSON column the same
+// way StringList/JSONB are elsewhere in this package.
+type ReportDefinition struct {
+	Dimension   ReportDimension `json:"dimension"`
+	DateFrom    *time.Time      `json:"date_from,omitempty"`
+	DateTo      *time.Time      `json:"date_to,omitempty"`
+	CategoryIDs []uint          `json:"category_ids,omitempty"`
+	BudgetID    *uint           `json:"budget_id,omitempty"`
+	TopN        int             `json:"top_n,omitempty"`
+}
+
+// Value implements the driver.Valuer interface for ReportDefinition.
+func (d ReportDefinition) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan implements the sql.Scanner interface for ReportDefinition.
+func (d *ReportDefinition) Scan(value interface{}) error {
+	if value == nil {
+		*d = ReportDefinition{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(bytes, d)
+}
+
+// Report is a saved grouping/filter/aggregation recipe a user can re-run
+// via controllers.RunReport to get a Tabulation without the frontend
+// needing a one-off endpoint per chart.
+//
+// LuaSource is an alternative to Definition for reports the built-in
+// dimensions don't cover: when set, controllers.RunCustomReport executes it
+// through services/reports.Service instead of dispatching on
+// Definition.Dimension, and Definition is left at its zero value. The two
+// are mutually exclusive, not layered - a report is either a declarative
+// Definition or a LuaSource script.
+type Report struct {
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	UserID     uint             `gorm:"index;not null" json:"user_id"`
+	User       User             `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	Name       string           `gorm:"not null" json:"name"`
+	Definition ReportDefinition `gorm:"type:jsonb;not null" json:"definition,omitempty"`
+	LuaSource  *string          `gorm:"type:text" json:"lua_source,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ReportScheduleType is which report a ReportSchedule re-runs on each tick.
+type ReportScheduleType string
+
+const (
+	ReportScheduleSpendSummary   ReportScheduleType = "spend_summary"
+	ReportScheduleCashflow       ReportScheduleType = "cashflow"
+	ReportScheduleBudgetProgress ReportScheduleType = "budget_progress"
+	ReportScheduleCustom         ReportScheduleType = "custom"
+)
+
+// ReportDeliveryMethod is how a ReportSchedule's rendered CSV/PDF reaches
+// the user.
+type ReportDeliveryMethod string
+
+const (
+	ReportDeliveryEmail   ReportDeliveryMethod = "email"
+	ReportDeliveryWebhook ReportDeliveryMethod = "webhook"
+	ReportDeliveryStorage ReportDeliveryMethod = "storage"
+)
+
+// ReportScheduleParams is the subset of GetSpendSummary/GetCashflow/
+// GetBudgetProgress/RunCustomReport's query parameters a ReportSchedule
+// needs to replay itself headlessly, stored as a single JSON column the
+// same way ReportDefinition is.
+type ReportScheduleParams struct {
+	ReportID       *uint      `json:"report_id,omitempty"` // ReportScheduleCustom: which saved Report to run
+	BudgetID       *uint      `json:"budget_id,omitempty"` // ReportScheduleBudgetProgress: which Budget to run against
+	Period         string     `json:"period,omitempty"`    // resolvePeriodPreset key, e.g. "last_30_days"
+	FromDate       *time.Time `json:"from_date,omitempty"`
+	ToDate         *time.Time `json:"to_date,omitempty"`
+	GroupBy        string     `json:"group_by,omitempty"` // ReportScheduleCashflow: day|week|month|year
+	ReportCurrency string     `json:"report_currency,omitempty"`
+}
+
+// Value implements the driver.Valuer interface for ReportScheduleParams.
+func (p ReportScheduleParams) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan implements the sql.Scanner interface for ReportScheduleParams.
+func (p *ReportScheduleParams) Scan(value interface{}) error {
+	if value == nil {
+		*p = ReportScheduleParams{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(bytes, p)
+}
+
+// ReportSchedule is a standing instruction to re-run one of this user's
+// reports on a cron-like cadence and deliver the rendered result by email,
+// webhook, or to local storage. services/reports/chore claims and executes
+// due rows; Attempts/LastError back its exponential-backoff retry.
+type ReportSchedule struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+
+	UserID uint `gorm:"index;not null" json:"user_id"`
+	User   User `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+
+	ReportType ReportScheduleType   `gorm:"type:text;not null" json:"report_type"`
+	Params     ReportScheduleParams `gorm:"type:jsonb;not null" json:"params,omitempty"`
+
+	// Cron is a standard 5-field "minute hour day-of-month month
+	// day-of-week" expression, interpreted in Timezone (IANA name, e.g.
+	// "America/New_York" - defaults to "UTC").
+	Cron     string `gorm:"not null" json:"cron"`
+	Timezone string `gorm:"not null;default:UTC" json:"timezone"`
+
+	DeliveryMethod ReportDeliveryMethod `gorm:"type:text;not null" json:"delivery_method"`
+	// Target is an email address, a webhook URL, or a storage/ subpath,
+	// depending on DeliveryMethod.
+	Target string `gorm:"not null" json:"target"`
+
+	LastRunAt *time.Time `json:"last_run_at"`
+	NextRunAt time.Time  `gorm:"index;not null" json:"next_run_at"`
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"`
+	LastError string     `json:"last_error"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "gorm.io/gorm"
+
+// RolePermission grants a single Permission to a UserRole. The (role,
+// permission) pair is unique - a role either has a permission or it doesn't.
+type RolePermission struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Role       UserRole   `gorm:"uniqueIndex:idx_role_permission;not null" json:"role"`
+	Permission Permission `gorm:"uniqueIndex:idx_role_permission;not null" json:"permission"`
+}
+
+// defaultRolePermissions is the factory-default grant set:
+//   - SuperAdmin: every permission, including assigning/managing other
+//     roles' grants. Not editable via UpdateRolePermissions.
+//   - Admin: everything except perm.role.assign (granting roles, notably
+//     SuperAdmin) and perm.role.manage (editing what roles can do).
+//   - Auditor: read-only access plus the audit log.
+//   - User: no admin-API access.
+var defaultRolePermissions = map[UserRole][]Permission{
+	UserRoleSuperAdmin: AllPermissions,
+	UserRoleAdmin: {
+		PermUsersRead, PermUsersWrite, PermUsersDelete,
+		PermTransactionsRead, PermTransactionsDelete,
+		PermAccountsRead, PermAccountsWrite, PermAccountsDelete,
+		PermCategoriesRead, PermCategoriesDelete,
+		PermBudgetsRead, PermBudgetsDelete,
+		PermAuditRead, PermDashboardRead,
+	},
+	UserRoleAuditor: {
+		PermUsersRead, PermTransactionsRead, PermAccountsRead,
+		PermCategoriesRead, PermBudgetsRead, PermAuditRead, PermDashboardRead,
+	},
+	UserRoleUser: {},
+}
+
+// SeedDefaultRolePermissions populates role_permissions with the defaults
+// above, but only for roles that don't already have any grants - so an
+// operator's runtime edits via PUT /admin/roles/:name/permissions survive a
+// restart instead of being clobbered.
+func SeedDefaultRolePermissions(db *gorm.DB) error {
+	for _, role := range AllRoles {
+		var count int64
+		if err := db.Model(&RolePermission{}).Where("role = ?", role).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		for _, perm := range defaultRolePermissions[role] {
+			if err := db.Create(&RolePermission{Role: role, Permission: perm}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
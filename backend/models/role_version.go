@@ -0,0 +1,35 @@
+package models
+
+import "gorm.io/gorm"
+
+// RoleVersion counts how many times a role's grants have been changed via
+// UpdateRolePermissions. It's embedded in issued JWTs as perm_ver and used
+// as part of middleware's in-process permission cache key, so a permission
+// change takes effect within one cache TTL instead of being masked by a
+// previously-computed grant set for that role.
+type RoleVersion struct {
+	Role    UserRole `gorm:"primaryKey" json:"role"`
+	Version int      `gorm:"not null;default:1" json:"version"`
+}
+
+// CurrentRoleVersion returns role's current version, defaulting to 1 for a
+// role that has never had BumpRoleVersion called for it.
+func CurrentRoleVersion(db *gorm.DB, role UserRole) int {
+	var rv RoleVersion
+	if err := db.Where("role = ?", role).First(&rv).Error; err != nil {
+		return 1
+	}
+	return rv.Version
+}
+
+// BumpRoleVersion increments role's version, invalidating every
+// already-cached permission set (and informing holders of a perm_ver-stamped
+// JWT that their cached grants may be stale).
+func BumpRoleVersion(db *gorm.DB, role UserRole) error {
+	var rv RoleVersion
+	err := db.Where("role = ?", role).First(&rv).Error
+	if err != nil {
+		return db.Create(&RoleVersion{Role: role, Version: 2}).Error
+	}
+	return db.Model(&rv).Update("version", rv.Version+1).Error
+}
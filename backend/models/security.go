@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SecurityType is what kind of tradeable/holdable unit a ReportSecurity
+// represents.
+type SecurityType string
+
+const (
+	SecurityCurrency SecurityType = "currency"
+	SecurityStock    SecurityType = "stock"
+	SecurityFund     SecurityType = "fund"
+)
+
+// ReportSecurity is a unit of value an Account or Transaction can be
+// denominated in - a currency (USD, EUR) or an investment holding (a stock
+// ticker, a fund), mirroring moneygo's security/price model (see Account's
+// own "MoneyGo-style chart of accounts" comment for the earlier borrowing
+// this follows). Letting currencies be securities themselves, rather than a
+// separate enum, means a currency pair and a stock quote are both just a
+// Price row and share the same conversion path in controllers.convertAmount.
+//
+// Named ReportSecurity rather than plain Security to stay out of the way of
+// models.Security (investment.go), the global ticker reference data Plaid
+// holdings are priced against - the two model unrelated things (a per-user
+// reporting currency vs. a shared catalog of tradeable instruments) and
+// neither should be folded into the other.
+type ReportSecurity struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+
+	// UserID scopes a ReportSecurity to one user rather than being shared
+	// globally, since Symbol-only collisions (e.g. two users both importing
+	// a "USD" currency security) would otherwise need cross-user locking to
+	// resolve.
+	UserID    uint         `gorm:"index;not null;uniqueIndex:idx_report_security_user_symbol,priority:1" json:"user_id"`
+	User      User         `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	Name      string       `gorm:"not null" json:"name"`
+	Symbol    string       `gorm:"not null;uniqueIndex:idx_report_security_user_symbol,priority:2" json:"symbol"`
+	Type      SecurityType `gorm:"type:text;not null;default:currency" json:"type"`
+	Precision int          `gorm:"not null;default:2" json:"precision"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// Price is one ReportSecurity's value, denominated in another
+// ReportSecurity (CurrencyID), on a given Date - e.g. "1 AAPL = 193.50 USD"
+// or "1 EUR = 1.08 USD". controllers.convertAmount looks up the latest
+// Price on or before the date being converted, trying CurrencyID/SecurityID
+// in reverse if only the inverse pair was ever recorded.
+type Price struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+
+	SecurityID uint           `gorm:"index:idx_price_security_date,priority:1;not null" json:"security_id"`
+	Security   ReportSecurity `json:"-"`
+	CurrencyID uint           `gorm:"not null" json:"currency_id"`
+	Currency   ReportSecurity `gorm:"foreignKey:CurrencyID" json:"-"`
+
+	Date      time.Time       `gorm:"type:date;index:idx_price_security_date,priority:2;not null" json:"date"`
+	Value     decimal.Decimal `gorm:"type:numeric(20,8);not null" json:"value"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Split is one leg of a double-entry decomposition of a Transaction across
+// the hierarchical chart of accounts (see Account.ParentAccountID): a
+// Transaction that records its activity as Splits instead of relying only
+// on its own bare AccountID/AmountCents must have Splits whose AmountCents
+// sum to zero, enforced by ValidateSplitsBalance. This plays the same role
+// LedgerEntry already does for CreateTransfer and collapsed Plaid
+// transfers, but is keyed directly to one Transaction rather than being a
+// standalone journal row, matching the "Split" vocabulary a chart-of-
+// accounts UI (and MoneyGo-style reporting) expects.
+type Split struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TransactionID uint      `gorm:"index;not null" json:"transaction_id"`
+	AccountID     uint      `gorm:"index;not null" json:"account_id"`
+	Account       Account   `json:"account,omitempty"`
+	AmountCents   int64     `gorm:"not null" json:"amount_cents"`
+	CategoryID    *uint     `gorm:"index" json:"category_id,omitempty"`
+	Category      *Category `json:"category,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ValidateSplitsBalance returns an error unless splits has at least two
+// legs and their AmountCents sum to zero - the double-entry invariant every
+// Transaction recorded as Splits must satisfy.
+func ValidateSplitsBalance(splits []Split) error {
+	if len(splits) < 2 {
+		return errors.New("a double-entry transaction needs at least two splits")
+	}
+
+	var total int64
+	for _, s := range splits {
+		total += s.AmountCents
+	}
+	if total != 0 {
+		return fmt.Errorf("splits must sum to zero, got %d", total)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// TaxRule lets a user tag one of their own Categories with the VAT and
+// income-tax withholding rates that apply to it, so
+// controllers.GetFiscalDashboard can estimate a period's tax liability
+// instead of the user re-deriving it by hand every quarter.
+type TaxRule struct {
+	ID         uint     `gorm:"primaryKey" json:"id"`
+	UserID     uint     `gorm:"uniqueIndex:idx_tax_rule_user_category;not null" json:"user_id"`
+	User       User     `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	CategoryID uint     `gorm:"uniqueIndex:idx_tax_rule_user_category;not null" json:"category_id"`
+	Category   Category `json:"category,omitempty"`
+
+	// VATRate applies to this category's gross amount (e.g. 0.21 for a 21%
+	// VAT rate); IRPFRate applies to its contribution to net income (e.g.
+	// 0.15 for a 15% income-tax withholding). Both are fractions, not
+	// percentages, and default to 0 (no tax modeled) until the user sets
+	// them.
+	VATRate  float64 `gorm:"not null;default:0" json:"vat_rate"`
+	IRPFRate float64 `gorm:"not null;default:0" json:"irpf_rate"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
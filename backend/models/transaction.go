@@ -1,32 +1,178 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// TransactionStatus is the reconciliation state of a transaction, following
+// the Imported -> Entered -> Cleared -> Reconciled lifecycle (plus Voided)
+// that bank-reconciliation workflows expect.
+type TransactionStatus string
+
+const (
+	TransactionImported   TransactionStatus = "imported"
+	TransactionEntered    TransactionStatus = "entered"
+	TransactionCleared    TransactionStatus = "cleared"
+	TransactionReconciled TransactionStatus = "reconciled"
+	TransactionVoided     TransactionStatus = "voided"
+)
+
+// TransactionKind distinguishes an ordinary income/expense posting from one
+// that moves money between two of the same user's accounts.
+type TransactionKind string
+
+const (
+	TransactionKindStandard TransactionKind = "standard"
+
+	// TransactionKindTransfer marks a Transaction collapsed from two
+	// opposite-signed Plaid-synced transactions that matched as an internal
+	// transfer (see plaid_api.go's collapsePlaidTransfers) rather than two
+	// unrelated expenses/incomes. Its two LedgerEntry rows (found by
+	// LedgerEntry.TxnID) carry the actual per-account debit/credit; its own
+	// AccountID/Amount describe only the debit (source) side, for
+	// backward compatibility with code that reads Transaction directly.
+	TransactionKindTransfer TransactionKind = "transfer"
+
+	// TransactionKindInvestmentBuy/Sell/Dividend mark a Transaction
+	// imported by controllers.SyncPlaidInvestments from
+	// /investments/transactions/get rather than /transactions/sync - the
+	// brokerage-side counterpart of a buy/sell/dividend against a Holding,
+	// not an everyday expense or income.
+	TransactionKindInvestmentBuy      TransactionKind = "investment_buy"
+	TransactionKindInvestmentSell     TransactionKind = "investment_sell"
+	TransactionKindInvestmentDividend TransactionKind = "investment_dividend"
+)
+
+// TransactionSplitKind distinguishes a transaction that only affects its
+// own payer (UserID) from one shared across a BudgetGroup.
+type TransactionSplitKind string
+
+const (
+	TransactionSplitIndividual TransactionSplitKind = "individual"
+
+	// TransactionSplitShared marks a transaction as divided among its
+	// BudgetGroupID's members - see controllers.GetGroupBalances, which
+	// sums every such transaction in a group's date range and allocates
+	// each member's fair share by BudgetGroupMember.Weight.
+	TransactionSplitShared TransactionSplitKind = "shared"
+)
 
 type Transaction struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	UserID      uint      `gorm:"index;not null" json:"user_id"`
-	User        User      `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
-	AccountID   uint      `gorm:"index;not null" json:"account_id"`
-	Account     Account   `json:"account,omitempty"`
-	CategoryID  *uint     `gorm:"index" json:"category_id"`
-	Category    *Category `json:"category,omitempty"`
-	AmountCents int64     `gorm:"not null" json:"amount_cents"` // Amount in cents, +income, -expense
-	Description string    `json:"description"`
-	TxnDate     time.Time `gorm:"type:date;index;not null" json:"txn_date"`
-	Notes       string    `json:"notes"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"index;uniqueIndex:idx_user_external;uniqueIndex:idx_user_account_remote,priority:1;not null" json:"user_id"`
+	User       User      `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+	AccountID  uint      `gorm:"index;uniqueIndex:idx_user_account_remote,priority:2;not null" json:"account_id"`
+	Account    Account   `json:"account,omitempty"`
+	CategoryID *uint     `gorm:"index" json:"category_id"`
+	Category   *Category `json:"category,omitempty"`
+
+	// MerchantID is the canonical Merchant this transaction's Description
+	// normalized to (see MatchMerchant), set on insert and by the
+	// 0031_merchants backfill migration for rows created before this
+	// column existed. Nil when no merchant pattern matched.
+	MerchantID *uint     `gorm:"index" json:"merchant_id,omitempty"`
+	Merchant   *Merchant `json:"merchant,omitempty"`
+	// Amount is in the account's own Currency, +income, -expense. Stored as
+	// NUMERIC(20,4) on Postgres / TEXT on SQLite (decimal.Decimal's own
+	// database/sql Value/Scan implementation) rather than integer cents, so
+	// FX conversions, per-share splits, and tax-rate math don't accumulate
+	// rounding error (see migrations/0029_decimal_money.go).
+	Amount      decimal.Decimal   `gorm:"type:numeric(20,4);not null" json:"amount"`
+	Description string            `json:"description"`
+	TxnDate     time.Time         `gorm:"type:date;index;not null" json:"txn_date"`
+	Notes       string            `json:"notes"`
+	Status      TransactionStatus `gorm:"type:text;index;not null;default:entered" json:"status"`
+	Kind        TransactionKind   `gorm:"type:text;not null;default:standard" json:"kind"`
+	CreatedAt   time.Time         `json:"created_at"`
 
 	// Bank integration fields
 	BankTransactionID *string `gorm:"uniqueIndex" json:"bank_transaction_id,omitempty"`
 	Metadata          *string `gorm:"type:text" json:"metadata,omitempty"` // Changed to string for SQLite compatibility
 
+	// ExternalID/ExternalHash back the idempotent bank-sync ingestion path
+	// (see controllers/bank_sync.go): ExternalID is a stable
+	// sha256(bank_account_id||bank_txn_id), nil for transactions that
+	// didn't come from a bank sync so they never collide on the composite
+	// unique index. ExternalHash lets a re-sync tell an unchanged
+	// transaction apart from one the bank has since corrected.
+	ExternalID   *string `gorm:"uniqueIndex:idx_user_external" json:"external_id,omitempty"`
+	ExternalHash string  `json:"-"`
+
+	// Import fields
+	ImportHash *string `gorm:"uniqueIndex" json:"import_hash,omitempty"` // dedup key from importers.ContentHash
+
+	// RemoteID is the caller-supplied idempotency key for
+	// BulkCreateTransactions (see controllers/bulk_transactions_controller.go):
+	// unique per (user_id, account_id, remote_id), nil for transactions that
+	// didn't come from a bulk import so they never collide on the composite
+	// index. Lets an OFX/QIF/CSV pipeline retry a failed batch without
+	// double-posting the rows that already made it in.
+	RemoteID *string `gorm:"uniqueIndex:idx_user_account_remote,priority:3" json:"remote_id,omitempty"`
+
+	// Source tags which pipeline created this row ("manual" by default,
+	// "ynab" for rows integrations/ynab.Sync imported). YNAB is
+	// authoritative for its own rows: every sync overwrites Amount/
+	// CategoryID/Description/TxnDate on the matching YnabTransactionID
+	// rather than merging, so an in-app edit to a source=ynab transaction
+	// only lasts until the next sync. GetSpendSummary/GetBudgetProgress
+	// query Amount/CategoryID directly regardless of Source, so YNAB- and
+	// manually-entered transactions report together without special-casing.
+	Source string `gorm:"type:text;not null;default:manual;index" json:"source"`
+
+	// SecurityID overrides the ReportSecurity Amount is denominated in, for
+	// a transaction that isn't in its Account's own Security - e.g. buying
+	// shares inside a cash brokerage account. Nil means "use Account.
+	// SecurityID", the common case for an ordinary currency transaction. See
+	// controllers.convertAmount, which reads SecurityID before falling back
+	// to the joined Account's.
+	SecurityID *uint           `gorm:"index" json:"security_id,omitempty"`
+	Security   *ReportSecurity `json:"security,omitempty"`
+
+	// YnabTransactionID is this transaction's id in YNAB, set only for
+	// Source=ynab rows; integrations/ynab.Sync upserts by this column so a
+	// repeated sync updates the existing row instead of duplicating it.
+	YnabTransactionID *string `gorm:"uniqueIndex" json:"ynab_transaction_id,omitempty"`
+
+	// YnabSyncHash is a hash of Amount/Description/CategoryID/TxnDate as of
+	// the last integrations/ynab.Sync that wrote this row, empty for a
+	// transaction that didn't come from YNAB. A YnabConnection whose
+	// ConflictStrategy isn't server_wins compares this against the row's
+	// current values to tell an in-app edit made since that sync apart from
+	// one YNAB itself is responsible for.
+	YnabSyncHash string `json:"-"`
+
 	Splits []TransactionSplit `gorm:"foreignKey:ParentTxnID" json:"splits,omitempty"`
+
+	// BudgetGroupID/SplitKind mark this transaction as shared within a
+	// BudgetGroup rather than an individual expense that only affects its
+	// payer. BudgetGroupID is nil and SplitKind is
+	// TransactionSplitIndividual for every ordinary transaction.
+	BudgetGroupID *uint                `gorm:"index" json:"budget_group_id,omitempty"`
+	SplitKind     TransactionSplitKind `gorm:"type:text;not null;default:individual" json:"split_kind"`
+
+	// Soft-delete (see models.User for the grace-period/restore contract).
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	PurgeAfter *time.Time     `json:"purge_after,omitempty"`
 }
 
 type TransactionSplit struct {
-	ID          uint     `gorm:"primaryKey" json:"id"`
-	ParentTxnID uint     `gorm:"index;not null" json:"parent_txn_id"`
-	CategoryID  uint     `gorm:"index;not null" json:"category_id"`
-	Category    Category `json:"category,omitempty"`
-	AmountCents int64    `gorm:"not null" json:"amount_cents"`
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	ParentTxnID uint            `gorm:"index;not null" json:"parent_txn_id"`
+	CategoryID  uint            `gorm:"index;not null" json:"category_id"`
+	Category    Category        `json:"category,omitempty"`
+	Amount      decimal.Decimal `gorm:"type:numeric(20,4);not null" json:"amount"`
+
+	// AccountID pins this split to a specific account leg when it moves
+	// money between accounts rather than just recategorizing a slice of the
+	// parent transaction's own amount (e.g. the cash side of an ATM
+	// withdrawal, or the brokerage side of a currency purchase). Nil means
+	// the split is a pure category breakdown of the parent's own
+	// AccountID/Amount, matching the original single-account behavior.
+	// Amount for an account-carrying split is denominated in that account's
+	// own Currency, not the parent transaction's.
+	AccountID *uint    `gorm:"index" json:"account_id,omitempty"`
+	Account   *Account `json:"account,omitempty"`
 }
@@ -2,17 +2,26 @@ package models
 
 import (
 	"time"
-	//"gorm.io/gorm"
+
+	"gorm.io/gorm"
 )
 
-// UserRole defines the possible roles for users
+// UserRole defines the possible roles for users, in ascending order of
+// privilege. What each role can actually do is data, not code - see
+// RolePermission - so adding a tier here means seeding its grants in
+// defaultRolePermissions, not touching every handler.
 type UserRole string
 
 const (
-	UserRoleUser  UserRole = "user"
-	UserRoleAdmin UserRole = "admin"
+	UserRoleUser       UserRole = "user"
+	UserRoleAuditor    UserRole = "auditor"
+	UserRoleAdmin      UserRole = "admin"
+	UserRoleSuperAdmin UserRole = "superadmin"
 )
 
+// AllRoles lists every assignable role.
+var AllRoles = []UserRole{UserRoleUser, UserRoleAuditor, UserRoleAdmin, UserRoleSuperAdmin}
+
 type User struct {
 	ID           uint      `gorm:"primaryKey" json:"id"`
 	Username     string    `gorm:"uniqueIndex;not null" json:"username"`
@@ -22,5 +31,21 @@ type User struct {
 	Role         UserRole  `gorm:"default:'user';not null" json:"role"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
-	// DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Soft-delete: admin destructive operations mark DeletedAt/PurgeAfter
+	// instead of removing the row outright, giving a grace period to
+	// restore via POST /admin/users/:id/restore before the background
+	// purge worker hard-deletes it.
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	PurgeAfter *time.Time     `json:"purge_after,omitempty"`
+
+	// OTP is the user's TOTP enrollment, if any - nil until /mfa/enroll is
+	// called, and ConfirmedAt stays nil until /mfa/verify succeeds.
+	OTP *UserOTP `json:"-" gorm:"foreignKey:UserID"`
+
+	// Scopes are Permissions granted to this user directly, on top of
+	// whatever their Role already carries via role_permissions - e.g. a
+	// single "user" role account that also needs perm.audit.read without
+	// promoting them to auditor. See middleware.RequireScope.
+	Scopes StringList `json:"scopes,omitempty" gorm:"type:jsonb"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// UserAISetting lets a single user override the server-wide AI_DRIVER
+// default (see controllers/aidriver) with their own driver and API key -
+// e.g. bringing their own OpenAI key instead of sharing the operator's.
+// One row per user; absent means "use the server default".
+type UserAISetting struct {
+	UserID uint `gorm:"primaryKey" json:"user_id"`
+	User   User `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+
+	// Driver is an aidriver.Register name ("local", "openai", "ollama").
+	// Nil means fall back to the server-wide default.
+	Driver *string `json:"driver"`
+
+	// APIKey is only meaningful alongside Driver - never serialized back.
+	APIKey *string `json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
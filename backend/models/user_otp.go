@@ -0,0 +1,54 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// StringList stores a []string as a JSON array in a single column, the
+// same Value/Scan pattern bank_connection.go's JSONB uses for a map.
+type StringList []string
+
+// Value implements the driver.Valuer interface for StringList.
+func (s StringList) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// Scan implements the sql.Scanner interface for StringList.
+func (s *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(bytes, s)
+}
+
+// UserOTP holds one user's TOTP (RFC 6238) enrollment: the base32 secret
+// (AES-GCM encrypted at rest, see controllers/mfa_crypto.go), when
+// enrollment was confirmed (nil until the first successful /mfa/verify),
+// and hashed one-time backup codes for /mfa/recovery.
+type UserOTP struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID uint `json:"user_id" gorm:"not null;uniqueIndex"`
+	User   User `json:"-" gorm:"foreignKey:UserID"`
+
+	// Secret is the AES-GCM-encrypted base32 TOTP secret; decrypt with
+	// decryptTOTPSecret before use, never compare or log it directly.
+	Secret      string     `json:"-" gorm:"not null"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	BackupCodes StringList `json:"-" gorm:"type:jsonb"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// YnabConflictStrategy controls how integrations/ynab.Sync handles a
+// transaction that changed both in YNAB and, since the last sync, inside
+// this app.
+type YnabConflictStrategy string
+
+const (
+	// YnabConflictServerWins overwrites the local row with YNAB's version,
+	// the connection's original (and still default) behavior.
+	YnabConflictServerWins YnabConflictStrategy = "server_wins"
+	// YnabConflictLocalWins leaves the local row untouched and advances the
+	// sync cursor as if YNAB's change had been applied.
+	YnabConflictLocalWins YnabConflictStrategy = "local_wins"
+	// YnabConflictManualReview leaves the local row untouched and records a
+	// YnabConflict for the user to resolve via GetYnabConflicts instead of
+	// silently picking a side.
+	YnabConflictManualReview YnabConflictStrategy = "manual_review"
+)
+
+// YnabConnection is one user's link to a YNAB budget: the encrypted
+// personal access token integrations/ynab.Sync authenticates with, and
+// which of that token's budgets to pull from. A user has at most one.
+type YnabConnection struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	UserID uint `json:"user_id" gorm:"uniqueIndex;not null"`
+	User   User `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
+
+	// EncryptedToken is the base64 nonce||ciphertext blob
+	// ynab.EncryptToken produces, never the plaintext personal access
+	// token.
+	EncryptedToken string `json:"-" gorm:"not null"`
+
+	BudgetID string `json:"budget_id" gorm:"not null"` // YNAB's own budget id
+
+	// ConflictStrategy picks how Sync treats a transaction edited locally
+	// since its last sync when YNAB has also changed it; see
+	// YnabConflictStrategy's own constants.
+	ConflictStrategy YnabConflictStrategy `json:"conflict_strategy" gorm:"type:text;not null;default:server_wins"`
+
+	Status     string     `json:"status" gorm:"default:connected"` // connected, failed
+	LastSyncAt *time.Time `json:"last_sync_at"`
+}
+
+// YnabConflict is one transaction Sync declined to overwrite under
+// ConflictStrategy=manual_review because it changed locally since its last
+// sync from YNAB. The affected Transaction is left exactly as it was; only
+// GetYnabConflicts/ResolveYnabConflict touch it from here on.
+type YnabConflict struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt        time.Time `json:"created_at"`
+	YnabConnectionID uint      `json:"ynab_connection_id" gorm:"index;not null"`
+	TransactionID    uint      `json:"transaction_id" gorm:"index;not null"`
+
+	// Remote* is what YNAB reported for this transaction during the sync
+	// that raised the conflict, so the user has something to compare the
+	// still-untouched local Transaction against before picking a side.
+	RemoteDescription string          `json:"remote_description"`
+	RemoteAmount      decimal.Decimal `json:"remote_amount" gorm:"type:numeric(20,4)"`
+	RemoteCategoryID  *uint           `json:"remote_category_id,omitempty"`
+	RemoteTxnDate     time.Time       `json:"remote_txn_date" gorm:"type:date"`
+
+	Resolved bool `json:"resolved" gorm:"not null;default:false"`
+}
+
+// YnabSyncState holds YNAB's last_knowledge_of_server cursor for one
+// connection's budget, so integrations/ynab.Sync can ask YNAB's
+// transactions endpoint for only what changed since the previous sync
+// instead of re-fetching the whole budget every run.
+type YnabSyncState struct {
+	ID                    uint      `json:"id" gorm:"primaryKey"`
+	YnabConnectionID      uint      `json:"ynab_connection_id" gorm:"uniqueIndex;not null"`
+	LastKnowledgeOfServer int64     `json:"last_knowledge_of_server"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
@@ -0,0 +1,109 @@
+// Package notifier delivers a fired models.BudgetAlert to its configured
+// channel. It mirrors utils/mailer's shape: one small interface so callers
+// never talk to net/smtp or net/http directly, and one implementation per
+// channel.
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/utils/mailer"
+)
+
+// Alert is the fired BudgetAlert payload a Notifier delivers.
+type Alert struct {
+	BudgetItemID     uint      `json:"budget_item_id"`
+	CategoryName     string    `json:"category_name"`
+	ThresholdPercent float64   `json:"threshold_percent"`
+	ProgressPercent  float64   `json:"progress_percent"`
+	SpentCents       int64     `json:"spent_cents"`
+	PlannedCents     int64     `json:"planned_cents"`
+	PeriodStart      time.Time `json:"period_start"`
+	PeriodEnd        time.Time `json:"period_end"`
+}
+
+// Message renders alert as the plain-text line both EmailNotifier's body
+// and models.BudgetAlertEvent.Message use, so the email and the in-app
+// feed entry for the same firing read identically.
+func (a Alert) Message() string {
+	return fmt.Sprintf("%s has spent %s of its %s planned amount for %s - %s (%.0f%%, threshold %.0f%%)",
+		a.CategoryName, formatCents(a.SpentCents), formatCents(a.PlannedCents),
+		a.PeriodStart.Format("2006-01-02"), a.PeriodEnd.Format("2006-01-02"),
+		a.ProgressPercent, a.ThresholdPercent)
+}
+
+func formatCents(cents int64) string {
+	return fmt.Sprintf("$%.2f", float64(cents)/100)
+}
+
+// Notifier delivers one fired Alert to a single target - an email address
+// or a webhook URL, depending on the implementation.
+type Notifier interface {
+	Notify(target string, alert Alert) error
+}
+
+// EmailNotifier delivers a fired alert as a plain-text email through
+// mailer.Send's currently active mailer.Mailer.
+type EmailNotifier struct{}
+
+// NewEmailNotifier returns an EmailNotifier. It takes no arguments - unlike
+// WebhookNotifier, it has no per-delivery configuration of its own; swap
+// mailer.SetMailer to change where its mail actually goes (e.g. in tests).
+func NewEmailNotifier() *EmailNotifier {
+	return &EmailNotifier{}
+}
+
+func (n *EmailNotifier) Notify(target string, alert Alert) error {
+	subject := fmt.Sprintf("Budget alert: %s crossed %.0f%%", alert.CategoryName, alert.ThresholdPercent)
+	return mailer.Send(target, subject, alert.Message())
+}
+
+// WebhookNotifier POSTs a fired alert as JSON to target, signing the body
+// with Secret via an X-Budget-Alert-Signature HMAC-SHA256 header, the same
+// scheme services/anomaly's deliverWebhook uses for AnomalyWebhook, so the
+// receiving endpoint can verify the request came from this server.
+type WebhookNotifier struct {
+	Secret     string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier builds a WebhookNotifier that signs every delivery
+// with secret.
+func NewWebhookNotifier(secret string) *WebhookNotifier {
+	return &WebhookNotifier{Secret: secret, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *WebhookNotifier) Notify(target string, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	mac := hmac.New(sha256.New, []byte(n.Secret))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Budget-Alert-Signature", signature)
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notifier: webhook endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Package money provides a fixed-point currency amount shared across the
+// AI budget-prediction types (services/ai, controllers/aidriver), so a
+// forecast doesn't have to carry a float64 dollar figure alongside its
+// authoritative integer cents - the float loses precision on large sums
+// and every caller that wanted both had to keep them in sync by hand.
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Money is an amount of US dollars stored as integer cents, the same
+// representation models.Transaction used before chunk13-4 moved it to
+// shopspring/decimal for ledger-grade precision. Money stays cents-based
+// because it only ever holds a forecast or a display figure, never a
+// value summed across thousands of rows.
+type Money struct {
+	cents int64
+}
+
+// Zero is the zero-value Money, $0.00.
+var Zero = Money{}
+
+// FromCents wraps an integer cents amount as Money.
+func FromCents(cents int64) Money {
+	return Money{cents: cents}
+}
+
+// FromDollars converts a float64 dollar amount to Money, rounding to the
+// nearest cent - the one place a float is still allowed in, for callers
+// that only have a dollar figure (e.g. a request body) to start from.
+func FromDollars(dollars float64) Money {
+	return Money{cents: int64(math.Round(dollars * 100))}
+}
+
+// Cents returns m's value in integer cents.
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+// Dollars returns m's value as a float64 dollar amount, for callers that
+// need to do further floating-point math (e.g. a percentage chart) -
+// everything else should stay in cents.
+func (m Money) Dollars() float64 {
+	return float64(m.cents) / 100
+}
+
+// Add returns m + other.
+func (m Money) Add(other Money) Money {
+	return Money{cents: m.cents + other.cents}
+}
+
+// Sub returns m - other.
+func (m Money) Sub(other Money) Money {
+	return Money{cents: m.cents - other.cents}
+}
+
+// Percent returns m scaled by pct percent (e.g. Percent(50) halves m),
+// rounding to the nearest cent.
+func (m Money) Percent(pct float64) Money {
+	return Money{cents: int64(math.Round(float64(m.cents) * pct / 100))}
+}
+
+// IsZero reports whether m is $0.00.
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+// Display renders m as a dollar string, e.g. "$123.45" or "-$1.00".
+func (m Money) Display() string {
+	cents := m.cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s$%d.%02d", sign, cents/100, cents%100)
+}
+
+// String satisfies fmt.Stringer with the same rendering as Display.
+func (m Money) String() string {
+	return m.Display()
+}
+
+// moneyJSON is Money's wire format: the authoritative integer cents plus a
+// human-readable display string callers can render directly without
+// reimplementing Display().
+type moneyJSON struct {
+	Cents   int64  `json:"cents"`
+	Display string `json:"display"`
+}
+
+// MarshalJSON renders m as {"cents":…, "display":"$123.45"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Cents: m.cents, Display: m.Display()})
+}
+
+// UnmarshalJSON reads m back from {"cents":…}; display is derived, not
+// trusted input, so it's ignored on the way in.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var wire moneyJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	m.cents = wire.Cents
+	return nil
+}
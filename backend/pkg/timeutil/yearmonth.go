@@ -0,0 +1,95 @@
+// Package timeutil provides YearMonth, a calendar month used across the
+// AI budget-prediction types (services/ai, controllers/aidriver) in place
+// of a bare (month int, year int) pair - those were re-parsed and
+// re-validated with the same 2020-2030/1-12 bounds in every handler that
+// touched a target period.
+package timeutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// YearMonth is a calendar month, e.g. July 2026.
+type YearMonth struct {
+	Year  int
+	Month int // 1-12
+}
+
+// Of constructs a YearMonth directly from year/month, the same shape the
+// query-param parsing in controllers/ai_controller.go already produces.
+func Of(year, month int) YearMonth {
+	return YearMonth{Year: year, Month: month}
+}
+
+// FromTime returns the YearMonth containing t.
+func FromTime(t time.Time) YearMonth {
+	return YearMonth{Year: t.Year(), Month: int(t.Month())}
+}
+
+// Now returns the current YearMonth.
+func Now() YearMonth {
+	return FromTime(time.Now())
+}
+
+// Parse reads a YearMonth from its "YYYY-MM" string form.
+func Parse(s string) (YearMonth, error) {
+	t, err := time.Parse("2006-01", s)
+	if err != nil {
+		return YearMonth{}, fmt.Errorf("timeutil: invalid year-month %q: %w", s, err)
+	}
+	return FromTime(t), nil
+}
+
+// String renders ym as "YYYY-MM".
+func (ym YearMonth) String() string {
+	return fmt.Sprintf("%04d-%02d", ym.Year, ym.Month)
+}
+
+// Validate reports an error if ym's year falls outside [minYear, maxYear]
+// or its month outside 1-12 - the bound every target-month query param
+// used to check inline.
+func (ym YearMonth) Validate(minYear, maxYear int) error {
+	if ym.Month < 1 || ym.Month > 12 {
+		return fmt.Errorf("timeutil: month %d out of range 1-12", ym.Month)
+	}
+	if ym.Year < minYear || ym.Year > maxYear {
+		return fmt.Errorf("timeutil: year %d out of range %d-%d", ym.Year, minYear, maxYear)
+	}
+	return nil
+}
+
+// Time returns ym as the first instant of that month, UTC.
+func (ym YearMonth) Time() time.Time {
+	return time.Date(ym.Year, time.Month(ym.Month), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Next returns the YearMonth n months after ym.
+func (ym YearMonth) Next(n int) YearMonth {
+	return FromTime(ym.Time().AddDate(0, n, 0))
+}
+
+// Prev returns the YearMonth n months before ym.
+func (ym YearMonth) Prev(n int) YearMonth {
+	return ym.Next(-n)
+}
+
+// MarshalJSON renders ym as its "YYYY-MM" string form.
+func (ym YearMonth) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ym.String())
+}
+
+// UnmarshalJSON reads ym back from its "YYYY-MM" string form.
+func (ym *YearMonth) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*ym = parsed
+	return nil
+}
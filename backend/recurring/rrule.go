@@ -0,0 +1,124 @@
+// Package recurring implements a minimal RFC 5545 RRULE evaluator: just
+// enough (FREQ, INTERVAL, BYMONTHDAY, BYDAY, COUNT, UNTIL) to drive
+// recurring-transaction scheduling.
+package recurring
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdayAbbrev = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Rule is a parsed RRULE.
+type Rule struct {
+	Freq       string // DAILY, WEEKLY, MONTHLY, YEARLY
+	Interval   int
+	ByMonthDay int // 0 means unset
+	ByDay      []time.Weekday
+	Count      int // 0 means unset
+	Until      *time.Time
+}
+
+// Parse parses an RRULE string such as "FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=1".
+func Parse(rrule string) (*Rule, error) {
+	r := &Rule{Interval: 1}
+
+	for _, part := range strings.Split(rrule, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch key {
+		case "FREQ":
+			r.Freq = value
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid INTERVAL: %w", err)
+			}
+			r.Interval = n
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid BYMONTHDAY: %w", err)
+			}
+			r.ByMonthDay = n
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				if wd, ok := weekdayAbbrev[d]; ok {
+					r.ByDay = append(r.ByDay, wd)
+				}
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid COUNT: %w", err)
+			}
+			r.Count = n
+		case "UNTIL":
+			until, err := time.Parse("20060102T150405Z", value)
+			if err != nil {
+				until, err = time.Parse("2006-01-02", value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid UNTIL: %w", err)
+				}
+			}
+			r.Until = &until
+		}
+	}
+
+	switch r.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("unsupported FREQ: %q", r.Freq)
+	}
+
+	return r, nil
+}
+
+// Next returns the first occurrence strictly after `after`.
+func (r *Rule) Next(after time.Time) time.Time {
+	switch r.Freq {
+	case "DAILY":
+		return after.AddDate(0, 0, r.Interval)
+	case "WEEKLY":
+		if len(r.ByDay) == 0 {
+			return after.AddDate(0, 0, 7*r.Interval)
+		}
+		return nextByDay(after, r.ByDay, 7*r.Interval)
+	case "MONTHLY":
+		next := after.AddDate(0, r.Interval, 0)
+		if r.ByMonthDay != 0 {
+			next = time.Date(next.Year(), next.Month(), r.ByMonthDay, after.Hour(), after.Minute(), after.Second(), 0, after.Location())
+		}
+		return next
+	case "YEARLY":
+		return after.AddDate(r.Interval, 0, 0)
+	}
+	return after
+}
+
+// nextByDay finds the next date, within `within` days of `after`, that falls
+// on one of `days`; falls back to after+within if none match (malformed rule).
+func nextByDay(after time.Time, days []time.Weekday, within int) time.Time {
+	for i := 1; i <= within; i++ {
+		candidate := after.AddDate(0, 0, i)
+		for _, d := range days {
+			if candidate.Weekday() == d {
+				return candidate
+			}
+		}
+	}
+	return after.AddDate(0, 0, within)
+}
@@ -3,6 +3,9 @@ package routes
 import (
 	"Personal-Finance-Tracker-backend/controllers"
 	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/seed"
+	"Personal-Finance-Tracker-backend/services/reports/chore"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,17 +13,48 @@ import (
 func SetupRoutes(rg *gin.RouterGroup) {
 	// Accounts
 	rg.GET("/accounts", controllers.GetAccounts)
+	rg.GET("/accounts/tree", controllers.GetAccountTree)
+	rg.GET("/accounts/summary", controllers.GetAccountsSummary)
 	rg.GET("/accounts/:id", controllers.GetAccount)
+	rg.GET("/accounts/:id/balance", controllers.GetAccountBalanceAsOf)
 	rg.POST("/accounts", controllers.CreateAccount)
 	rg.PUT("/accounts/:id", controllers.UpdateAccount)
 	rg.DELETE("/accounts/:id", controllers.DeleteAccount)
+	rg.POST("/accounts/:id/reconcile", controllers.ReconcileAccount)
+	rg.POST("/accounts/:id/restore", controllers.RestoreAccount)
+	rg.POST("/accounts/:id/merge", controllers.MergeAccounts)
+	rg.POST("/accounts/:id/recompute", controllers.RecomputeAccountBalance)
 
 	// Transactions
 	rg.GET("/transactions", controllers.GetTransactions)
 	rg.GET("/transactions/:id", controllers.GetTransaction)
-	rg.POST("/transactions", controllers.CreateTransaction)
-	rg.PUT("/transactions/:id", controllers.UpdateTransaction)
-	rg.DELETE("/transactions/:id", controllers.DeleteTransaction)
+	rg.POST("/transactions", middleware.IdempotencyMiddleware(), controllers.CreateTransaction)
+	rg.POST("/transactions/bulk", controllers.BulkCreateTransactions)
+	rg.PUT("/transactions/:id", middleware.IdempotencyMiddleware(), controllers.UpdateTransaction)
+	rg.DELETE("/transactions/:id", middleware.IdempotencyMiddleware(), controllers.DeleteTransaction)
+	rg.PATCH("/transactions/:id/status", controllers.UpdateTransactionStatus)
+
+	// Transfers (double-entry movements between accounts)
+	rg.POST("/transfers", middleware.IdempotencyMiddleware(), controllers.CreateTransfer)
+	rg.POST("/accounts/transfers", middleware.IdempotencyMiddleware(), controllers.CreateTransfer)
+	rg.GET("/accounts/transfers/:group_id", controllers.GetTransfer)
+	rg.DELETE("/accounts/transfers/:group_id", controllers.DeleteTransfer)
+
+	// Ledger (reconstructed balances from LedgerEntry rows, not Account.CurrentBalanceCents)
+	rg.GET("/ledger/trial-balance", controllers.GetTrialBalance)
+	rg.GET("/ledger/account/:id", controllers.GetAccountLedgerBalance)
+
+	// Statement import (OFX/QIF/CSV)
+	rg.POST("/accounts/:id/import", controllers.ImportTransactions)
+	rg.POST("/accounts/:id/sync", controllers.SyncAccountOFX)
+
+	// Recurring transactions
+	rg.GET("/recurring", controllers.GetRecurringRules)
+	rg.POST("/recurring", controllers.CreateRecurringRule)
+	rg.PUT("/recurring/:id", controllers.UpdateRecurringRule)
+	rg.DELETE("/recurring/:id", controllers.DeleteRecurringRule)
+	rg.POST("/recurring/:id/run-now", controllers.RunRecurringRuleNow)
+	rg.POST("/recurring/detect", controllers.DetectRecurringTransactions)
 
 	// Categories
 	rg.GET("/categories", controllers.GetCategories)
@@ -30,67 +64,265 @@ func SetupRoutes(rg *gin.RouterGroup) {
 	rg.POST("/categories", controllers.CreateCategory)
 	rg.PUT("/categories/:id", controllers.UpdateCategory)
 	rg.DELETE("/categories/:id", controllers.DeleteCategory)
+	rg.POST("/categories/:id/restore", controllers.RestoreCategory)
+	rg.POST("/categories/:id/merge", controllers.MergeCategory)
+	rg.POST("/categories/import", controllers.ImportCategories)
+	rg.GET("/categories/export", controllers.ExportCategories)
+	rg.POST("/categories/seed", controllers.SeedCategories)
+	rg.POST("/categories/:id/hide", controllers.HideSystemCategory)
+	rg.POST("/categories/:id/unhide", controllers.UnhideSystemCategory)
+	rg.PATCH("/categories/:id/override", controllers.UpdateCategoryOverride)
+
+	// Auto-categorization rules (merchant/description pattern matching)
+	rg.GET("/category-rules", controllers.GetCategoryRules)
+	rg.POST("/category-rules", controllers.CreateCategoryRule)
+	rg.PUT("/category-rules/:id", controllers.UpdateCategoryRule)
+	rg.DELETE("/category-rules/:id", controllers.DeleteCategoryRule)
+	rg.POST("/categories/rules/apply", controllers.ApplyCategoryRulesBackfill)
+	rg.POST("/categories/rules/:id/test", controllers.TestCategoryRule)
+
+	// Merchants (canonical payees GetTopMerchants groups by and new
+	// transactions auto-suggest a category from)
+	rg.GET("/merchants", controllers.GetMerchants)
+	rg.POST("/merchants", controllers.CreateMerchant)
+	rg.PUT("/merchants/:id", controllers.UpdateMerchant)
+	rg.DELETE("/merchants/:id", controllers.DeleteMerchant)
+	rg.POST("/merchants/merge", controllers.MergeMerchants)
+	rg.POST("/merchants/:id/aliases", controllers.AddMerchantAlias)
+
+	// Bill pay (vendor catalog + a user's own recurring/one-off bills)
+	rg.GET("/bills/vendors", controllers.GetBillVendors)
+	rg.GET("/bills/vendors/:id/products", controllers.GetBillVendorProducts)
+	rg.POST("/bills/lookup", controllers.LookupBill)
+	rg.GET("/bills", controllers.GetBills)
+	rg.POST("/bills", controllers.CreateBill)
+	rg.POST("/bills/:id/pay", middleware.IdempotencyMiddleware(), controllers.PayBill)
 
 	// Budgets
 	rg.GET("/budgets", controllers.GetBudgets)
 	rg.GET("/budgets/current", controllers.GetCurrentBudget)
+	rg.GET("/budgets/upcoming", controllers.GetUpcomingBudgetPeriods)
 	rg.GET("/budgets/:id", controllers.GetBudget)
+	rg.GET("/budgets/:id/forecast", controllers.GetBudgetForecast)
 	rg.POST("/budgets", controllers.CreateBudget)
+	rg.POST("/budgets/:id/rollover", controllers.RolloverBudgetByID)
 	rg.PUT("/budgets/:id", controllers.UpdateBudget)
 	rg.DELETE("/budgets/:id", controllers.DeleteBudget)
 
+	// Budget alerts (configurable spend thresholds, evaluated from
+	// GetBudgets - see controllers/budget_alerts_controller.go) and the
+	// in-app feed their firings land in
+	rg.POST("/budgets/:id/items/:itemId/alerts", controllers.CreateBudgetAlert)
+	rg.GET("/budgets/alerts", controllers.GetBudgetAlerts)
+	rg.DELETE("/budgets/alerts/:id", controllers.DeleteBudgetAlert)
+	rg.GET("/notifications/stream", controllers.GetNotificationsStream)
+
+	// Budget templates (recurring budgets with rollover)
+	rg.GET("/budget-templates", controllers.GetBudgetTemplates)
+	rg.GET("/budget-templates/:id", controllers.GetBudgetTemplate)
+	rg.POST("/budget-templates", controllers.CreateBudgetTemplate)
+	rg.PUT("/budget-templates/:id", controllers.UpdateBudgetTemplate)
+	rg.DELETE("/budget-templates/:id", controllers.DeleteBudgetTemplate)
+	rg.POST("/budget-templates/:id/rollover", controllers.RolloverBudget)
+
+	// Budget groups (shared/split expenses) and their "who owes whom" analytics
+	rg.GET("/budget-groups", controllers.GetBudgetGroups)
+	rg.GET("/budget-groups/:id", controllers.GetBudgetGroup)
+	rg.POST("/budget-groups", controllers.CreateBudgetGroup)
+	rg.POST("/budget-groups/:id/members", controllers.AddGroupMember)
+	rg.DELETE("/budget-groups/:id/members/:user_id", controllers.RemoveGroupMember)
+	rg.GET("/budget-groups/:id/balances", controllers.GetGroupBalances)
+
+	// Digest email opt-in/cadence (see controllers/digest_scheduler.go)
+	rg.GET("/notification-preferences", controllers.GetNotificationPreferences)
+	rg.PUT("/notification-preferences", controllers.UpdateNotificationPreferences)
+
+	// Per-category VAT/IRPF rates and the fiscal dashboard derived from them
+	rg.GET("/tax-rules", controllers.GetTaxRules)
+	rg.POST("/tax-rules", controllers.UpsertTaxRule)
+	rg.DELETE("/tax-rules/:id", controllers.DeleteTaxRule)
+	rg.GET("/analytics/dashboard", controllers.GetFiscalDashboard)
+
+	// Foreign exchange rates (backs multi-currency budget spend conversion)
+	rg.GET("/fx/rates", controllers.GetFxRates)
+	rg.POST("/fx/rates/refresh", controllers.RefreshFxRates)
+
+	// OAuth/OIDC account linking - attaches a provider identity to the
+	// already-authenticated caller. The matching /:provider/login and
+	// /:provider/callback routes are public (see main.go's /oauth group),
+	// since the provider redirects the browser there with no auth header.
+	rg.GET("/auth/oauth/:provider/link", controllers.OAuthLinkStart)
+
+	// Saved reports (generic Tabulation runner backing ad-hoc charts)
+	rg.GET("/reports", controllers.GetReports)
+	rg.POST("/reports", controllers.CreateReport)
+	rg.PUT("/reports/:id", controllers.UpdateReport)
+	rg.DELETE("/reports/:id", controllers.DeleteReport)
+	rg.GET("/reports/:id/run", controllers.RunReport)
+	rg.POST("/reports/run", controllers.RunCustomReport)
+
 	// User Profile Management
 	rg.GET("/user/profile", controllers.GetUserProfile)
 	rg.PUT("/user/profile", controllers.UpdateUserProfile)
 	rg.PUT("/user/change-password", controllers.ChangePassword)
 	rg.DELETE("/user/account", controllers.DeleteUserAccount)
+	rg.POST("/user/account/undelete", controllers.UndeleteUserAccount)
+
+	// Multi-factor authentication (TOTP)
+	rg.POST("/mfa/enroll", controllers.EnrollMFA)
+	rg.POST("/mfa/verify", controllers.VerifyMFA)
+	rg.POST("/mfa/disable", controllers.DisableMFA)
+	rg.POST("/mfa/recovery", controllers.RecoverMFA)
 
 	// Reports & Analytics
 	rg.GET("/reports/spend-summary", controllers.GetSpendSummary)
 	rg.GET("/reports/cashflow", controllers.GetCashflow)
+	rg.GET("/reports/cashflow-forecast", controllers.GetCashflowForecast)
 	rg.GET("/reports/account-balances", controllers.GetAccountBalances)
 	rg.GET("/reports/budget-progress", controllers.GetBudgetProgress)
 	rg.GET("/reports/monthly-trends", controllers.GetMonthlyTrends)
 	rg.GET("/reports/top-merchants", controllers.GetTopMerchants)
 
+	// Report schedules (recurring spend-summary/cashflow/budget-progress/
+	// custom reports delivered by email, webhook, or local storage - see
+	// services/reports/chore for the cron evaluation and delivery itself)
+	rg.GET("/report-schedules", controllers.GetReportSchedules)
+	rg.POST("/report-schedules", controllers.CreateReportSchedule)
+	rg.PUT("/report-schedules/:id", controllers.UpdateReportSchedule)
+	rg.DELETE("/report-schedules/:id", controllers.DeleteReportSchedule)
+	rg.POST("/report-schedules/:id/run-now", chore.RunReportScheduleNow)
+
+	// Securities - currencies/investment holdings an Account/Transaction can
+	// be denominated in, and the historical prices GetAccountBalances/
+	// GetSpendSummary/GetCashflow's ?report_currency= conversion reads.
+	rg.GET("/securities", controllers.GetSecurities)
+	rg.POST("/securities", controllers.CreateSecurity)
+	rg.GET("/securities/:id/prices", controllers.GetSecurityPrices)
+	rg.POST("/securities/:id/prices/import", controllers.ImportSecurityPrices)
+
 	// AI-powered budget predictions
 	rg.GET("/ai/budget-predictions", controllers.GetBudgetPrediction)
+	rg.GET("/ai/budget-predictions/stream", controllers.GetBudgetPredictionStream)
+	rg.GET("/ai/budget-predictions/history", controllers.GetPredictionHistory)
+	rg.GET("/ai/budget-predictions/accuracy", controllers.GetPredictionAccuracy)
 	rg.GET("/ai/spending-patterns", controllers.GetSpendingPatterns)
+	rg.GET("/ai/spending-patterns/stream", controllers.GetSpendingPatternsStream)
+	rg.GET("/ai/anomalies", controllers.GetAnomalies)
+	rg.POST("/ai/anomaly-webhooks", controllers.CreateAnomalyWebhook)
+	rg.DELETE("/ai/anomaly-webhooks/:id", controllers.DeleteAnomalyWebhook)
 
-	// Bank Integration - Plaid only
+	// Bank Integration - PSD2 (Berlin Group XS2A) and Plaid
 	rg.GET("/banks/connections", controllers.GetBankConnections)
+	rg.POST("/banks/connections", controllers.CreateBankConnection)
+	rg.GET("/banks/connections/:id/callback", controllers.BankConnectionCallback)
+	rg.POST("/banks/connections/:id/sync", controllers.SyncBankConnection)
+	rg.POST("/banks/connections/:id/reauthenticate", controllers.ReauthenticateBankConnection)
 	rg.DELETE("/banks/connections/:id", controllers.DisconnectBank)
+	rg.GET("/banks/connections/:id/audit", controllers.GetBankConnectionAuditLog)
+	rg.GET("/banks/health", controllers.GetBankHealth)
+	rg.POST("/banks/connections/:id/reset", controllers.ResetBankConnectionBreaker)
+
+	// PSD2/Berlin Group - a leaner, provider-registry-driven sibling of the
+	// /banks/connections endpoints above for Norwegian banks Plaid doesn't
+	// cover well (see services/psd2.Providers)
+	rg.POST("/banks/psd2/consent", controllers.CreatePSD2Consent)
+	rg.GET("/banks/psd2/consent/:id/status", controllers.GetPSD2ConsentStatus)
+	rg.POST("/banks/psd2/accounts/:id/sync", controllers.SyncPSD2Account)
 
-	// Plaid - FREE Banking API (100 users/month)
-	rg.POST("/plaid/create_link_token", controllers.CreateLinkToken)
-	rg.POST("/plaid/exchange_public_token", controllers.ExchangePublicToken)
-	rg.POST("/plaid/sync/:id", controllers.SyncPlaidTransactions)
-	rg.GET("/plaid/accounts/:id", controllers.GetPlaidAccounts)
+	// Plaid - FREE Banking API (100 users/month). Every route sits behind
+	// PlaidBackpressure (bounded worker pool + token bucket, see
+	// middleware/plaid_limiter.go) so a burst of requests can't push this
+	// server past whatever concurrency/rate Plaid itself allows.
+	rg.POST("/plaid/create_link_token", middleware.PlaidBackpressure(), controllers.CreateLinkToken)
+	rg.POST("/plaid/exchange_public_token", middleware.PlaidBackpressure(), controllers.ExchangePublicToken)
+	rg.POST("/plaid/sync/:id", middleware.PlaidBackpressure(), controllers.SyncPlaidTransactions)
+	rg.GET("/plaid/accounts/:id", middleware.PlaidBackpressure(), controllers.GetPlaidAccounts)
+	rg.POST("/plaid/sync-investments/:id", middleware.PlaidBackpressure(), controllers.SyncPlaidInvestments)
+	rg.GET("/plaid/sync_status", middleware.PlaidBackpressure(), controllers.PlaidSyncStatus)
 
-	// Admin routes (require admin role)
+	// YNAB import/sync (connects via a personal access token; synced rows
+	// are tagged source=ynab and surface through the same reports endpoints
+	// as manually-entered data)
+	rg.POST("/integrations/ynab/connect", controllers.ConnectYnab)
+	rg.POST("/integrations/ynab/sync", controllers.SyncYnab)
+	rg.GET("/integrations/ynab/conflicts", controllers.GetYnabConflicts)
+	rg.POST("/integrations/ynab/conflicts/:id/resolve", controllers.ResolveYnabConflict)
+
+	// Net worth (cash + investment holdings time series)
+	rg.GET("/networth", controllers.GetNetWorth)
+
+	// Provider-agnostic bank linking (aggregators.Get picks Plaid/GoCardless/
+	// TrueLayer by ?provider=, default "plaid")
+	rg.POST("/banks/link-session", controllers.CreateBankLinkSession)
+	rg.POST("/banks/exchange-token", controllers.ExchangeBankToken)
+
+	// Admin routes. Each is wired to the one permission it needs (see
+	// models.RolePermission) rather than a single blanket admin gate.
 	admin := rg.Group("/admin")
-	admin.Use(middleware.RequireAdmin())
+	admin.Use(middleware.AuditLog())
 	{
+		standardLimit := middleware.RateLimit(middleware.ActorRouteKey, middleware.StandardAdminRate)
+		sensitiveLimit := middleware.RateLimit(middleware.ActorRouteKey, middleware.SensitiveAdminRate)
+
 		// Dashboard stats
-		admin.GET("/dashboard-stats", controllers.GetDashboardStats)
+		admin.GET("/dashboard-stats", middleware.RequirePermission(models.PermDashboardRead), standardLimit, controllers.GetDashboardStats)
+
+		// Audit log (tamper-evident hash chain of admin actions)
+		admin.GET("/audit-logs/verify", middleware.RequirePermission(models.PermAuditRead), controllers.VerifyAuditChain)
+		admin.GET("/audit-logs/:id", middleware.RequirePermission(models.PermAuditRead), controllers.GetAuditLog)
+		admin.GET("/audit-logs", middleware.RequirePermission(models.PermAuditRead), controllers.GetAuditLogs)
+
+		// Auth audit trail (tamper-evident hash chain of register/login/
+		// logout/refresh/MFA/password-change/role-change events, written
+		// asynchronously by middleware.RecordAuthAuditEvent)
+		admin.GET("/audit/verify", middleware.RequirePermission(models.PermAuditRead), controllers.VerifyAuthAuditChain)
+		admin.GET("/audit/:id", middleware.RequirePermission(models.PermAuditRead), controllers.GetAuthAuditEvent)
+		admin.GET("/audit", middleware.RequirePermission(models.PermAuditRead), controllers.GetAuthAuditEvents)
+
+		// PSD2 consent-usage trail (BankAuditEvent): viewed/connected/
+		// reauthenticated/synced/disconnected/consent_expired events across
+		// every user's bank connections
+		admin.GET("/banks/audit", middleware.RequirePermission(models.PermAuditRead), standardLimit, controllers.GetBankAuditEvents)
 
 		// User management
-		admin.GET("/users", controllers.GetAllUsers)
-		admin.GET("/users/:id", controllers.GetUserDetails)
-		admin.DELETE("/users/:id", controllers.DeleteUserAdmin)
-		admin.PUT("/users/:id/role", controllers.UpdateUserRole)
+		admin.GET("/users", middleware.RequirePermission(models.PermUsersRead), standardLimit, controllers.GetAllUsers)
+		admin.GET("/users/pending-purge", middleware.RequirePermission(models.PermUsersRead), standardLimit, controllers.GetPendingPurgeUsers)
+		admin.GET("/users/:id", middleware.RequirePermission(models.PermUsersRead), controllers.GetUserDetails)
+		admin.GET("/users/:id/usage", middleware.RequirePermission(models.PermUsersRead), controllers.GetUserUsageAdmin)
+		admin.DELETE("/users/:id", middleware.RequirePermission(models.PermUsersDelete), sensitiveLimit, controllers.DeleteUserAdmin)
+		admin.POST("/users/:id/restore", middleware.RequirePermission(models.PermUsersWrite), controllers.RestoreUserAdmin)
+		admin.POST("/users/:id/unlock", middleware.RequirePermission(models.PermUsersWrite), controllers.UnlockUserLogin)
+		admin.PUT("/users/:id/role", middleware.RequirePermission(models.PermRoleAssign), sensitiveLimit, controllers.UpdateUserRole)
+		admin.POST("/users/:id/scopes", middleware.RequirePermission(models.PermRoleAssign), sensitiveLimit, controllers.UpdateUserScopes)
+
+		// Role management (grant editing - SuperAdmin only by default)
+		admin.GET("/roles", middleware.RequirePermission(models.PermRoleManage), controllers.GetRoles)
+		admin.PUT("/roles/:name/permissions", middleware.RequirePermission(models.PermRoleManage), controllers.UpdateRolePermissions)
 
 		// Data oversight
-		admin.GET("/transactions", controllers.GetAllTransactions)
-		admin.GET("/accounts", controllers.GetAllAccounts)
-		admin.GET("/categories", controllers.GetAllCategories)
-		admin.GET("/budgets", controllers.GetAllBudgets)
-		admin.GET("/budgets/:id", controllers.GetBudgetDetails)
-
-		// Data deletion (admin override)
-		admin.DELETE("/transactions/:id", controllers.DeleteTransactionAdmin)
-		admin.DELETE("/accounts/:id", controllers.DeleteAccountAdmin)
-		admin.DELETE("/categories/:id", controllers.DeleteCategoryAdmin)
-		admin.DELETE("/budgets/:id", controllers.DeleteBudgetAdmin)
+		admin.GET("/transactions", middleware.RequirePermission(models.PermTransactionsRead), standardLimit, controllers.GetAllTransactions)
+		admin.GET("/accounts", middleware.RequirePermission(models.PermAccountsRead), standardLimit, controllers.GetAllAccounts)
+		admin.POST("/accounts/recompute-all", middleware.RequirePermission(models.PermAccountsWrite), sensitiveLimit, controllers.RecomputeAllAccountBalances)
+		admin.GET("/categories", middleware.RequirePermission(models.PermCategoriesRead), standardLimit, controllers.GetAllCategories)
+		admin.GET("/budgets", middleware.RequirePermission(models.PermBudgetsRead), standardLimit, controllers.GetAllBudgets)
+		admin.GET("/budgets/:id", middleware.RequirePermission(models.PermBudgetsRead), controllers.GetBudgetDetails)
+		admin.GET("/export/transactions", middleware.RequirePermission(models.PermTransactionsRead), standardLimit, controllers.ExportTransactionsAdmin)
+
+		// Data deletion (admin override) with soft-delete + grace-period restore
+		admin.DELETE("/transactions/:id", middleware.RequirePermission(models.PermTransactionsDelete), sensitiveLimit, controllers.DeleteTransactionAdmin)
+		admin.POST("/transactions/:id/restore", middleware.RequirePermission(models.PermTransactionsDelete), controllers.RestoreTransactionAdmin)
+		admin.GET("/accounts/:id/usage", middleware.RequirePermission(models.PermAccountsRead), controllers.GetAccountUsageAdmin)
+		admin.DELETE("/accounts/:id", middleware.RequirePermission(models.PermAccountsDelete), sensitiveLimit, controllers.DeleteAccountAdmin)
+		admin.POST("/accounts/:id/restore", middleware.RequirePermission(models.PermAccountsDelete), controllers.RestoreAccountAdmin)
+		admin.DELETE("/categories/:id", middleware.RequirePermission(models.PermCategoriesDelete), sensitiveLimit, controllers.DeleteCategoryAdmin)
+		admin.POST("/categories/:id/restore", middleware.RequirePermission(models.PermCategoriesDelete), controllers.RestoreCategoryAdmin)
+		admin.DELETE("/budgets/:id", middleware.RequirePermission(models.PermBudgetsDelete), sensitiveLimit, controllers.DeleteBudgetAdmin)
+		admin.POST("/budgets/:id/restore", middleware.RequirePermission(models.PermBudgetsDelete), controllers.RestoreBudgetAdmin)
+
+		// Demo data reseeding (dev environments only, see seed.ReseedHandler)
+		admin.POST("/seed/reseed", middleware.RequirePermission(models.PermSeedManage), sensitiveLimit, seed.ReseedHandler)
+
+		// Prediction cache admin controls (see controllers/ai_cache.go)
+		admin.DELETE("/ai/predictions/cache", middleware.RequirePermission(models.PermAIManage), sensitiveLimit, controllers.ClearPredictionCache)
 	}
 }
@@ -1,286 +1,246 @@
 package seed
 
 import (
+	cryptorand "crypto/rand"
+	"encoding/base64"
+	"fmt"
 	"log"
+	"math/rand"
 	"time"
 
+	"Personal-Finance-Tracker-backend/config"
 	"Personal-Finance-Tracker-backend/controllers"
 	"Personal-Finance-Tracker-backend/models"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
-// SeedDemoData adds sample data for testing/presentation
-func SeedDemoData(db *gorm.DB) {
-	// First, create the default admin user if it doesn't exist
-	createDefaultAdmin(db)
+// expenseCategoryNames and incomeCategoryNames are the fixed category set
+// every generated user gets; BudgetCents/CategoryWeights on a PersonaProfile
+// key into expenseCategoryNames.
+var expenseCategoryNames = []string{"Groceries", "Rent", "Transportation", "Entertainment"}
 
-	// Check if demo user exists
-	var count int64
-	db.Model(&models.User{}).Where("username = ?", "demo").Count(&count)
-	if count > 0 {
-		log.Println("ℹ️  Demo data already exists, skipping seed")
-		return
-	}
+const salaryCategoryName = "Salary"
 
-	log.Println("🌱 Seeding demo data...")
+// SeedDemoData generates demo data for Scenario s using a math/rand.Rand
+// seeded from s.Seed, so the same scenario always produces byte-identical
+// users, accounts, categories, budgets, and transactions. It always ensures
+// the "demo"/"demo123" login exists first, matching the previous behavior
+// relied on by the frontend demo walkthrough. Admin bootstrap is a separate
+// concern - see AdminSeeder - so this can be re-run per scenario without
+// touching the SuperAdmin account.
+func SeedDemoData(db *gorm.DB, s Scenario) error {
+	rng := rand.New(rand.NewSource(s.Seed))
 
-	// Use Argon2 hash (same as auth system)
 	hash, err := controllers.HashPassword("demo123")
 	if err != nil {
-		log.Fatalf("❌ Failed to hash demo password: %v", err)
+		return fmt.Errorf("hash demo password: %w", err)
 	}
 
-	// Create demo user
-	demoUser := models.User{
-		Username:     "demo",
-		Email:        "demo@example.com",
-		PasswordHash: hash,
-		Name:         "Demo User",
-		Role:         models.UserRoleUser, // Explicitly set as regular user
-	}
-	db.Create(&demoUser)
-
-	// Create additional demo users for clustering analysis
-	additionalUsers := []models.User{
-		{
-			Username:     "user_conservative",
-			Email:        "conservative@example.com",
-			PasswordHash: hash,
-			Name:         "Conservative User",
-			Role:         models.UserRoleUser,
-		},
-		{
-			Username:     "user_spender",
-			Email:        "spender@example.com",
+	var demoCount int64
+	db.Model(&models.User{}).Where("username = ?", "demo").Count(&demoCount)
+	if demoCount == 0 {
+		demoUser := models.User{
+			Username:     "demo",
+			Email:        "demo@example.com",
 			PasswordHash: hash,
-			Name:         "Big Spender",
+			Name:         "Demo User",
 			Role:         models.UserRoleUser,
-		},
-		{
-			Username:     "user_balanced",
-			Email:        "balanced@example.com",
-			PasswordHash: hash,
-			Name:         "Balanced User",
-			Role:         models.UserRoleUser,
-		},
-		{
-			Username:     "user_student",
-			Email:        "student@example.com",
-			PasswordHash: hash,
-			Name:         "Student User",
-			Role:         models.UserRoleUser,
-		},
+		}
+		if err := db.Create(&demoUser).Error; err != nil {
+			return fmt.Errorf("create demo user: %w", err)
+		}
+		if err := seedUserFinances(db, rng, demoUser, BuiltinPersonas["balanced"], s); err != nil {
+			return fmt.Errorf("seed demo user finances: %w", err)
+		}
+		log.Println("✅ Demo login seeded (demo/demo123)")
+	} else {
+		log.Println("ℹ️  Demo user already exists, skipping")
 	}
-	db.Create(&additionalUsers)
 
-	// Create accounts for additional users
-	allUsers := append([]models.User{demoUser}, additionalUsers...)
-	var allAccounts []models.Account
+	if s.Users == 0 || len(s.Personas) == 0 {
+		log.Printf("🌱 Scenario %q requests no synthetic users, nothing else to seed", s.Name)
+		return nil
+	}
+
+	log.Printf("🌱 Seeding scenario %q: %d users across personas %v over %d months", s.Name, s.Users, s.Personas, s.Months)
+
+	for i := 0; i < s.Users; i++ {
+		persona := BuiltinPersonas[s.Personas[i%len(s.Personas)]]
+		username := fmt.Sprintf("%s_%s_%04d", s.Name, persona.Name, i)
+
+		user := models.User{
+			Username:     username,
+			Email:        fmt.Sprintf("%s@demo.financetracker.local", username),
+			PasswordHash: hash,
+			Name:         fmt.Sprintf("%s Demo User %d", persona.Name, i),
+			Role:         models.UserRoleUser,
+		}
+		if err := db.Create(&user).Error; err != nil {
+			return fmt.Errorf("create synthetic user %s: %w", username, err)
+		}
 
-	for _, user := range allUsers {
-		userAccounts := []models.Account{
-			{UserID: user.ID, Name: "Checking", Type: "checking", Currency: "USD"},
-			{UserID: user.ID, Name: "Savings", Type: "savings", Currency: "USD"},
+		if err := seedUserFinances(db, rng, user, persona, s); err != nil {
+			return fmt.Errorf("seed finances for %s: %w", username, err)
 		}
-		db.Create(&userAccounts)
-		allAccounts = append(allAccounts, userAccounts...)
 	}
 
-	// Create demo accounts for main demo user
+	log.Printf("✅ Scenario %q seeded successfully", s.Name)
+	return nil
+}
+
+// seedUserFinances creates the accounts, categories, budget, and
+// transaction history for one user according to persona and scenario
+// parameters, using rng so the whole run stays reproducible.
+func seedUserFinances(db *gorm.DB, rng *rand.Rand, user models.User, persona PersonaProfile, s Scenario) error {
 	accounts := []models.Account{
-		{UserID: demoUser.ID, Name: "Main Checking", Type: "checking", Currency: "USD"},
-		{UserID: demoUser.ID, Name: "Savings", Type: "savings", Currency: "USD"},
-		{UserID: demoUser.ID, Name: "Credit Card", Type: "credit", Currency: "USD"},
+		{UserID: user.ID, Name: "Checking", Type: models.AccountChecking, Currency: s.Currency},
+		{UserID: user.ID, Name: "Savings", Type: models.AccountSavings, Currency: s.Currency},
 	}
-	db.Create(&accounts)
-
-	// Create categories for all users
-	var allCategories []models.Category
-	for _, user := range allUsers {
-		userCategories := []models.Category{
-			{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense},
-			{UserID: user.ID, Name: "Salary", Kind: models.CategoryIncome},
-			{UserID: user.ID, Name: "Rent", Kind: models.CategoryExpense},
-			{UserID: user.ID, Name: "Transportation", Kind: models.CategoryExpense},
-			{UserID: user.ID, Name: "Entertainment", Kind: models.CategoryExpense},
-		}
-		db.Create(&userCategories)
-		allCategories = append(allCategories, userCategories...)
+	if err := db.Create(&accounts).Error; err != nil {
+		return err
 	}
+	checking := accounts[0]
 
-	// Create demo categories for main demo user
-	categories := []models.Category{
-		{UserID: demoUser.ID, Name: "Groceries", Kind: models.CategoryExpense},
-		{UserID: demoUser.ID, Name: "Salary", Kind: models.CategoryIncome},
-		{UserID: demoUser.ID, Name: "Rent", Kind: models.CategoryExpense},
-		{UserID: demoUser.ID, Name: "Transportation", Kind: models.CategoryExpense},
-		{UserID: demoUser.ID, Name: "Entertainment", Kind: models.CategoryExpense},
+	categories := make([]models.Category, 0, len(expenseCategoryNames)+1)
+	categories = append(categories, models.Category{UserID: user.ID, Name: salaryCategoryName, Kind: models.CategoryIncome})
+	for _, name := range expenseCategoryNames {
+		categories = append(categories, models.Category{UserID: user.ID, Name: name, Kind: models.CategoryExpense})
 	}
-	db.Create(&categories)
-
-	// Create demo transactions
-	transactions := []models.Transaction{
-		{
-			UserID:      demoUser.ID,
-			AccountID:   accounts[0].ID,
-			CategoryID:  &categories[1].ID,
-			AmountCents: 300000,
-			Description: "October Salary",
-			TxnDate:     time.Now().AddDate(0, 0, -10),
-		},
-		{
-			UserID:      demoUser.ID,
-			AccountID:   accounts[0].ID,
-			CategoryID:  &categories[0].ID,
-			AmountCents: -5000,
-			Description: "Whole Foods",
-			TxnDate:     time.Now().AddDate(0, 0, -5),
-		},
-		{
-			UserID:      demoUser.ID,
-			AccountID:   accounts[0].ID,
-			CategoryID:  &categories[2].ID,
-			AmountCents: -150000,
-			Description: "Monthly Rent",
-			TxnDate:     time.Now().AddDate(0, 0, -1),
-		},
-		{
-			UserID:      demoUser.ID,
-			AccountID:   accounts[0].ID,
-			CategoryID:  &categories[3].ID,
-			AmountCents: -3000,
-			Description: "Uber",
-			TxnDate:     time.Now().AddDate(0, 0, -3),
-		},
-		{
-			UserID:      demoUser.ID,
-			AccountID:   accounts[0].ID,
-			CategoryID:  &categories[4].ID,
-			AmountCents: -8000,
-			Description: "Cinema Tickets",
-			TxnDate:     time.Now().AddDate(0, 0, -2),
-		},
+	if err := db.Create(&categories).Error; err != nil {
+		return err
 	}
-	db.Create(&transactions)
-
-	// Create budgets for additional users with different spending patterns
-	userTypes := map[string]map[string]int64{
-		"user_conservative": {
-			"Groceries":      25000, // $250 - conservative
-			"Rent":           80000, // $800 - lower rent
-			"Transportation": 10000, // $100 - minimal transport
-			"Entertainment":  5000,  // $50 - very little entertainment
-		},
-		"user_spender": {
-			"Groceries":      80000,  // $800 - premium groceries
-			"Rent":           250000, // $2500 - expensive housing
-			"Transportation": 50000,  // $500 - car payments
-			"Entertainment":  40000,  // $400 - lots of entertainment
-		},
-		"user_balanced": {
-			"Groceries":      45000,  // $450 - moderate
-			"Rent":           120000, // $1200 - average rent
-			"Transportation": 25000,  // $250 - reasonable transport
-			"Entertainment":  20000,  // $200 - balanced entertainment
-		},
-		"user_student": {
-			"Groceries":      15000, // $150 - tight budget
-			"Rent":           60000, // $600 - shared housing
-			"Transportation": 5000,  // $50 - public transport
-			"Entertainment":  10000, // $100 - limited entertainment
-		},
+	categoryByName := make(map[string]models.Category, len(categories))
+	for _, c := range categories {
+		categoryByName[c.Name] = c
 	}
 
-	for i, user := range additionalUsers {
-		username := user.Username
-		budgetAmounts := userTypes[username]
-
-		// Find user's categories
-		var userCategories []models.Category
-		db.Where("user_id = ?", user.ID).Find(&userCategories)
+	now := time.Now()
+	budget := models.Budget{
+		UserID:      user.ID,
+		PeriodStart: now.AddDate(0, 0, -15),
+		PeriodEnd:   now.AddDate(0, 0, 15),
+		Currency:    s.Currency,
+	}
+	if err := db.Create(&budget).Error; err != nil {
+		return err
+	}
 
-		// Create budget for this user
-		userBudget := models.Budget{
-			UserID:      user.ID,
-			PeriodStart: time.Now().AddDate(0, 0, -15),
-			PeriodEnd:   time.Now().AddDate(0, 0, 15),
-			Currency:    "USD",
+	var budgetItems []models.BudgetItem
+	for _, name := range expenseCategoryNames {
+		amount := persona.BudgetCents[name]
+		if amount <= 0 {
+			continue
 		}
-		db.Create(&userBudget)
-
-		// Create budget items based on user type
-		var userBudgetItems []models.BudgetItem
-		for _, category := range userCategories {
-			if category.Kind == models.CategoryExpense {
-				amount := budgetAmounts[category.Name]
-				if amount > 0 {
-					userBudgetItems = append(userBudgetItems, models.BudgetItem{
-						BudgetID:     userBudget.ID,
-						CategoryID:   category.ID,
-						PlannedCents: amount,
-					})
-				}
-			}
+		budgetItems = append(budgetItems, models.BudgetItem{
+			BudgetID:      budget.ID,
+			CategoryID:    categoryByName[name].ID,
+			PlannedAmount: decimal.NewFromInt(amount).Div(decimal.NewFromInt(100)),
+		})
+	}
+	if len(budgetItems) > 0 {
+		if err := db.Create(&budgetItems).Error; err != nil {
+			return err
 		}
+	}
+
+	return seedTransactionHistory(db, rng, user, checking, categoryByName, persona, s)
+}
 
-		if len(userBudgetItems) > 0 {
-			db.Create(&userBudgetItems)
+// seedTransactionHistory backfills s.Months of income and expense
+// transactions for one user, jittering amounts by persona.Volatility so
+// repeated months aren't identical while staying reproducible given rng.
+func seedTransactionHistory(db *gorm.DB, rng *rand.Rand, user models.User, account models.Account, categoryByName map[string]models.Category, persona PersonaProfile, s Scenario) error {
+	totalWeight := 0.0
+	for _, name := range expenseCategoryNames {
+		totalWeight += persona.CategoryWeights[name]
+	}
+
+	for month := 0; month < s.Months; month++ {
+		payday := time.Now().AddDate(0, -month, -rng.Intn(5))
+		salaryCategoryID := categoryByName[salaryCategoryName].ID
+		income := models.Transaction{
+			UserID:      user.ID,
+			AccountID:   account.ID,
+			CategoryID:  &salaryCategoryID,
+			Amount:      decimal.NewFromInt(jitter(rng, persona.IncomeCents, persona.Volatility)).Div(decimal.NewFromInt(100)),
+			Description: "Salary",
+			TxnDate:     payday,
+		}
+		if err := db.Create(&income).Error; err != nil {
+			return err
 		}
 
-		// Create some transactions for spending pattern diversity
-		var userAccounts []models.Account
-		db.Where("user_id = ?", user.ID).Find(&userAccounts)
-
-		if len(userAccounts) > 0 {
-			// Create varied transaction patterns based on user type
-			spendingMultiplier := []float64{0.8, 1.2, 1.0, 0.6}[i] // Conservative, Spender, Balanced, Student
-
-			for _, item := range userBudgetItems {
-				actualSpent := int64(float64(item.PlannedCents) * spendingMultiplier)
-				userTransactions := []models.Transaction{
-					{
-						UserID:      user.ID,
-						AccountID:   userAccounts[0].ID,
-						CategoryID:  &item.CategoryID,
-						AmountCents: -actualSpent,
-						Description: "Monthly expense",
-						TxnDate:     time.Now().AddDate(0, 0, -5),
-					},
-				}
-				db.Create(&userTransactions)
+		for i := 0; i < s.TransactionsPerMonth; i++ {
+			name := pickWeighted(rng, expenseCategoryNames, persona.CategoryWeights, totalWeight)
+			categoryID := categoryByName[name].ID
+			perTxnBudget := persona.BudgetCents[name] / int64(maxInt(1, s.TransactionsPerMonth/len(expenseCategoryNames)))
+			txn := models.Transaction{
+				UserID:      user.ID,
+				AccountID:   account.ID,
+				CategoryID:  &categoryID,
+				Amount:      decimal.NewFromInt(-jitter(rng, perTxnBudget, persona.Volatility)).Div(decimal.NewFromInt(100)),
+				Description: fmt.Sprintf("%s expense", name),
+				TxnDate:     time.Now().AddDate(0, -month, -rng.Intn(28)),
+			}
+			if err := db.Create(&txn).Error; err != nil {
+				return err
 			}
 		}
 	}
+	return nil
+}
 
-	// Create demo budget
-	budget := models.Budget{
-		UserID:      demoUser.ID,
-		PeriodStart: time.Now().AddDate(0, 0, -15),
-		PeriodEnd:   time.Now().AddDate(0, 0, 15),
-		Currency:    "USD",
+// jitter perturbs base by up to +/- volatility fraction, using rng, and
+// never returns a negative amount.
+func jitter(rng *rand.Rand, base int64, volatility float64) int64 {
+	if base <= 0 {
+		return 0
 	}
-	db.Create(&budget)
-
-	// Create budget items
-	budgetItems := []models.BudgetItem{
-		{BudgetID: budget.ID, CategoryID: categories[0].ID, PlannedCents: 40000},  // Groceries: $400
-		{BudgetID: budget.ID, CategoryID: categories[2].ID, PlannedCents: 150000}, // Rent: $1500
-		{BudgetID: budget.ID, CategoryID: categories[3].ID, PlannedCents: 20000},  // Transportation: $200
-		{BudgetID: budget.ID, CategoryID: categories[4].ID, PlannedCents: 15000},  // Entertainment: $150
+	delta := (rng.Float64()*2 - 1) * volatility
+	amount := float64(base) * (1 + delta)
+	if amount < 0 {
+		amount = 0
 	}
-	db.Create(&budgetItems)
+	return int64(amount)
+}
 
-	log.Println("✅ Demo data seeded successfully!")
-	log.Println("📧 Demo login credentials:")
-	log.Println("   Username: demo")
-	log.Println("   Password: demo123")
+// pickWeighted chooses one of names using weights[name] as relative weight.
+func pickWeighted(rng *rand.Rand, names []string, weights map[string]float64, totalWeight float64) string {
+	if totalWeight <= 0 {
+		return names[rng.Intn(len(names))]
+	}
+	target := rng.Float64() * totalWeight
+	for _, name := range names {
+		target -= weights[name]
+		if target <= 0 {
+			return name
+		}
+	}
+	return names[len(names)-1]
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
 }
 
-// createDefaultAdmin creates the default admin user if it doesn't exist
+// createDefaultAdmin creates the default SuperAdmin user if one doesn't
+// exist yet. It has to be SuperAdmin, not Admin: only a SuperAdmin holds
+// perm.role.assign/perm.role.manage, so a freshly migrated database needs
+// one to bootstrap every other role's grants.
+//
+// Username/password come from the active config.Provider
+// (auth.default_admin_username/auth.default_admin_password) instead of the
+// old hard-coded admin/admin123, so the bootstrap credential isn't baked
+// into source. If no password is configured, a random one is generated and
+// logged once - the operator is expected to rotate it immediately.
 func createDefaultAdmin(db *gorm.DB) {
 	var adminCount int64
-	db.Model(&models.User{}).Where("role = ?", models.UserRoleAdmin).Count(&adminCount)
+	db.Model(&models.User{}).Where("role = ?", models.UserRoleSuperAdmin).Count(&adminCount)
 
 	if adminCount > 0 {
 		log.Println("ℹ️  Admin user already exists, skipping creation")
@@ -289,19 +249,29 @@ func createDefaultAdmin(db *gorm.DB) {
 
 	log.Println("🔑 Creating default admin user...")
 
-	// Use the proper HashPassword function from controllers
-	hash, err := controllers.HashPassword("admin123")
+	username := config.GetOr("auth.default_admin_username", "admin")
+	password, configured := config.Get("auth.default_admin_password")
+	if !configured || password == "" {
+		var err error
+		password, err = generateRandomPassword()
+		if err != nil {
+			log.Printf("❌ Failed to generate admin password: %v", err)
+			return
+		}
+	}
+
+	hash, err := controllers.HashPassword(password)
 	if err != nil {
 		log.Printf("❌ Failed to hash admin password: %v", err)
 		return
 	}
 
 	admin := models.User{
-		Username:     "admin",
-		Email:        "admin@financetracker.com",
+		Username:     username,
+		Email:        config.GetOr("auth.default_admin_email", "admin@financetracker.com"),
 		PasswordHash: hash,
 		Name:         "System Administrator",
-		Role:         models.UserRoleAdmin,
+		Role:         models.UserRoleSuperAdmin,
 	}
 
 	if err := db.Create(&admin).Error; err != nil {
@@ -311,7 +281,21 @@ func createDefaultAdmin(db *gorm.DB) {
 
 	log.Println("✅ Default admin user created successfully!")
 	log.Println("👑 Admin login credentials:")
-	log.Println("   Username: admin")
-	log.Println("   Password: admin123")
-	log.Println("   ⚠️  Please change the admin password after first login!")
+	log.Printf("   Username: %s", username)
+	if configured {
+		log.Println("   Password: set via auth.default_admin_password")
+	} else {
+		log.Printf("   Password (generated, not stored anywhere else): %s", password)
+		log.Println("   ⚠️  Please change the admin password after first login!")
+	}
+}
+
+// generateRandomPassword returns a URL-safe, base64-encoded random password
+// suitable for a one-time admin bootstrap credential.
+func generateRandomPassword() (string, error) {
+	raw := make([]byte, 18)
+	if _, err := cryptorand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
 }
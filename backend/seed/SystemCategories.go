@@ -0,0 +1,127 @@
+package seed
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// systemCategoryUsername is the reserved, unloggable-in-practice account
+// that owns every system Category row (Category.IsSystem true). Giving
+// them a real owning user keeps the existing user_id FK and every
+// multi-tenancy query that filters on it working unchanged; only
+// IsSystem marks them as shared rather than personal.
+const systemCategoryUsername = "system-categories"
+
+// systemCategoryRow is one entry of the built-in shared taxonomy,
+// addressed the same way categoryImportRow addresses an import/export
+// row: a "/"-separated path whose last segment is the category's own
+// name and whose earlier segments must appear earlier in this same list.
+type systemCategoryRow struct {
+	Path string
+	Kind models.CategoryKind
+}
+
+// defaultSystemTaxonomy is the curated chart of accounts every user sees
+// by default, alongside their own categories, unless they hide or
+// override an entry via CategoryOverride.
+var defaultSystemTaxonomy = []systemCategoryRow{
+	{Path: "Housing", Kind: models.CategoryExpense},
+	{Path: "Housing/Rent & Mortgage", Kind: models.CategoryExpense},
+	{Path: "Housing/Utilities", Kind: models.CategoryExpense},
+	{Path: "Food", Kind: models.CategoryExpense},
+	{Path: "Food/Groceries", Kind: models.CategoryExpense},
+	{Path: "Food/Dining Out", Kind: models.CategoryExpense},
+	{Path: "Transportation", Kind: models.CategoryExpense},
+	{Path: "Health", Kind: models.CategoryExpense},
+	{Path: "Entertainment", Kind: models.CategoryExpense},
+	{Path: "Income", Kind: models.CategoryIncome},
+	{Path: "Income/Salary", Kind: models.CategoryIncome},
+	{Path: "Income/Other Income", Kind: models.CategoryIncome},
+}
+
+// SystemCategorySeeder creates the reserved system-categories user (if
+// missing) and the shared taxonomy it owns. Registered in seed_history as
+// "system_categories" so it only runs once per database unless an
+// operator explicitly forces it.
+type SystemCategorySeeder struct{}
+
+func (SystemCategorySeeder) Name() string { return "system_categories" }
+
+func (SystemCategorySeeder) Run(db *gorm.DB) error {
+	owner, err := systemCategoryOwner(db)
+	if err != nil {
+		return fmt.Errorf("get system category owner: %w", err)
+	}
+
+	byPath := make(map[string]uint, len(defaultSystemTaxonomy))
+	for _, row := range defaultSystemTaxonomy {
+		segments := strings.Split(row.Path, "/")
+		name := segments[len(segments)-1]
+
+		var parentID *uint
+		if len(segments) > 1 {
+			id, ok := byPath[strings.Join(segments[:len(segments)-1], "/")]
+			if !ok {
+				return fmt.Errorf("system category parent path not found: %s", row.Path)
+			}
+			parentID = &id
+		}
+
+		category := models.Category{UserID: owner.ID, IsSystem: true, Name: name, Kind: row.Kind, ParentID: parentID}
+		query := db.Where("user_id = ? AND is_system = ? AND name = ? AND kind = ?", owner.ID, true, name, row.Kind)
+		if parentID != nil {
+			query = query.Where("parent_id = ?", *parentID)
+		} else {
+			query = query.Where("parent_id IS NULL")
+		}
+		if err := query.Attrs(category).FirstOrCreate(&category).Error; err != nil {
+			return fmt.Errorf("seed system category %s: %w", row.Path, err)
+		}
+
+		byPath[row.Path] = category.ID
+	}
+
+	log.Printf("✅ Seeded %d system categories owned by %q", len(defaultSystemTaxonomy), systemCategoryUsername)
+	return nil
+}
+
+// systemCategoryOwner returns the reserved system-categories user,
+// creating it with a random, never-surfaced password if it doesn't exist
+// yet - nobody is meant to log in as it.
+func systemCategoryOwner(db *gorm.DB) (models.User, error) {
+	var owner models.User
+	err := db.Where("username = ?", systemCategoryUsername).First(&owner).Error
+	if err == nil {
+		return owner, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return models.User{}, err
+	}
+
+	password, err := generateRandomPassword()
+	if err != nil {
+		return models.User{}, fmt.Errorf("generate system user password: %w", err)
+	}
+	hash, err := controllers.HashPassword(password)
+	if err != nil {
+		return models.User{}, fmt.Errorf("hash system user password: %w", err)
+	}
+
+	owner = models.User{
+		Username:     systemCategoryUsername,
+		Email:        "system-categories@financetracker.internal",
+		PasswordHash: hash,
+		Name:         "System Categories",
+		Role:         models.UserRoleUser,
+	}
+	if err := db.Create(&owner).Error; err != nil {
+		return models.User{}, err
+	}
+	return owner, nil
+}
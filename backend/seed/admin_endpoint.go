@@ -0,0 +1,68 @@
+package seed
+
+import (
+	"net/http"
+	"os"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/migrations"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reseedRequest is the POST /admin/seed/reseed body. Scenario is required;
+// Seed/Months override the scenario's defaults when set. Force re-applies
+// the scenario's DemoDataSeeder even if it has already run.
+type reseedRequest struct {
+	Scenario string `json:"scenario" binding:"required"`
+	Seed     *int64 `json:"seed"`
+	Months   *int   `json:"months"`
+	Force    bool   `json:"force"`
+}
+
+// ReseedHandler triggers SeedDemoData for an arbitrary scenario from an
+// admin request. It's gated to dev environments (APP_ENV != "production")
+// as well as the perm.seed.manage permission, since reseeding can create
+// thousands of rows and is never something a production operator should do
+// from the API.
+func ReseedHandler(c *gin.Context) {
+	if os.Getenv("APP_ENV") == "production" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "reseeding is disabled in production"})
+		return
+	}
+
+	var req reseedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	scenario, err := ResolveScenario(req.Scenario, req.Seed, req.Months)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	seeder := DemoDataSeeder{Scenario: scenario}
+	ran, err := migrations.RunSeeder(db.DB, seeder, req.Force)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "reseed failed: " + err.Error()})
+		return
+	}
+	if !ran {
+		c.JSON(http.StatusOK, gin.H{
+			"status":   "already_seeded",
+			"scenario": scenario.Name,
+			"seeder":   seeder.Name(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "seeded",
+		"scenario": scenario.Name,
+		"seeder":   seeder.Name(),
+		"users":    scenario.Users,
+		"seed":     scenario.Seed,
+	})
+}
@@ -0,0 +1,116 @@
+package seed
+
+import (
+	"flag"
+	"fmt"
+
+	"Personal-Finance-Tracker-backend/config"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/migrations"
+)
+
+// RunCLI implements the `seed` subcommand:
+//
+//	seed --scenario=clustering-eval --seed=42 --months=12 [--force]
+//	seed status
+//
+// The first form (re-)runs the admin bootstrap seeder and the named
+// scenario's DemoDataSeeder, each recorded in seed_history; --force
+// re-applies a seeder that has already run. `seed status` instead prints
+// which schema migrations and seeders have run, without writing anything.
+func RunCLI(args []string) error {
+	if err := config.Init(); err != nil {
+		return fmt.Errorf("init config provider: %w", err)
+	}
+
+	if len(args) > 0 && args[0] == "status" {
+		return runStatus()
+	}
+
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	scenarioName := fs.String("scenario", "presentation", "built-in scenario to seed (presentation, clustering-eval, load-test, empty)")
+	seedFlag := fs.Int64("seed", 0, "RNG seed override (0 = use the scenario's default seed)")
+	monthsFlag := fs.Int("months", 0, "months of transaction history override (0 = use the scenario's default)")
+	force := fs.Bool("force", false, "re-apply the seeder even if it has already run")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var seedOverride *int64
+	if *seedFlag != 0 {
+		seedOverride = seedFlag
+	}
+	var monthsOverride *int
+	if *monthsFlag != 0 {
+		monthsOverride = monthsFlag
+	}
+
+	scenario, err := ResolveScenario(*scenarioName, seedOverride, monthsOverride)
+	if err != nil {
+		return err
+	}
+
+	db.ConnectDatabase()
+	if err := migrations.Migrate(db.DB); err != nil {
+		return fmt.Errorf("migrate schema: %w", err)
+	}
+
+	if ran, err := migrations.RunSeeder(db.DB, AdminSeeder{}, *force); err != nil {
+		return fmt.Errorf("run default_admin seeder: %w", err)
+	} else if ran {
+		fmt.Println("✅ default_admin seeder ran")
+	} else {
+		fmt.Println("ℹ️  default_admin seeder already applied, skipping (use --force to re-apply)")
+	}
+
+	if ran, err := migrations.RunSeeder(db.DB, SystemCategorySeeder{}, *force); err != nil {
+		return fmt.Errorf("run system_categories seeder: %w", err)
+	} else if ran {
+		fmt.Println("✅ system_categories seeder ran")
+	} else {
+		fmt.Println("ℹ️  system_categories seeder already applied, skipping (use --force to re-apply)")
+	}
+
+	seeder := DemoDataSeeder{Scenario: scenario}
+	ran, err := migrations.RunSeeder(db.DB, seeder, *force)
+	if err != nil {
+		return fmt.Errorf("run %s seeder: %w", seeder.Name(), err)
+	}
+	if ran {
+		fmt.Printf("✅ %s seeder ran\n", seeder.Name())
+	} else {
+		fmt.Printf("ℹ️  %s seeder already applied, skipping (use --force to re-apply)\n", seeder.Name())
+	}
+	return nil
+}
+
+func runStatus() error {
+	db.ConnectDatabase()
+
+	migrationStatuses, err := migrations.Status(db.DB)
+	if err != nil {
+		return fmt.Errorf("migration status: %w", err)
+	}
+	fmt.Println("Schema migrations:")
+	for _, m := range migrationStatuses {
+		state := "pending"
+		if m.Applied {
+			state = fmt.Sprintf("applied at %s", m.AppliedAt.Format("2006-01-02T15:04:05Z07:00"))
+		}
+		fmt.Printf("  [%04d] %-20s %s\n", m.Version, m.Name, state)
+	}
+
+	seederStatuses, err := migrations.SeedersStatus(db.DB, BuiltinSeeders())
+	if err != nil {
+		return fmt.Errorf("seeder status: %w", err)
+	}
+	fmt.Println("Seeders:")
+	for _, s := range seederStatuses {
+		state := "never run"
+		if s.RanAt != nil {
+			state = fmt.Sprintf("ran at %s", s.RanAt.Format("2006-01-02T15:04:05Z07:00"))
+		}
+		fmt.Printf("  %-28s %s\n", s.Name, state)
+	}
+	return nil
+}
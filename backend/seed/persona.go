@@ -0,0 +1,142 @@
+package seed
+
+// PersonaProfile describes the shape of one synthetic user's financial
+// behavior: how their budget is split across categories, how often and
+// how much they spend in each, how they get paid, and how much their
+// month-to-month spending wobbles around that baseline. Scenarios compose
+// personas to build a realistic, reproducible population instead of one
+// hand-picked transaction per budget item.
+type PersonaProfile struct {
+	Name string
+
+	// BudgetCents is the planned monthly amount per expense category, in
+	// cents. Categories not listed here are not budgeted for this persona.
+	BudgetCents map[string]int64
+
+	// CategoryWeights controls how transaction volume is distributed across
+	// expense categories; weights are relative, not required to sum to 1.
+	CategoryWeights map[string]float64
+
+	// IncomeCents and IncomeCadence describe the persona's pay. Cadence is
+	// "monthly" or "biweekly".
+	IncomeCents   int64
+	IncomeCadence string
+
+	// Volatility is a 0-1 fraction applied as the stddev of a per-transaction
+	// amount jitter (as a fraction of the category's average transaction
+	// size), so "spender" personas swing further month to month than
+	// "conservative" ones.
+	Volatility float64
+}
+
+// BuiltinPersonas are the named personas scenarios can reference by name.
+var BuiltinPersonas = map[string]PersonaProfile{
+	"conservative": {
+		Name: "conservative",
+		BudgetCents: map[string]int64{
+			"Groceries":      25000,
+			"Rent":           80000,
+			"Transportation": 10000,
+			"Entertainment":  5000,
+		},
+		CategoryWeights: map[string]float64{
+			"Groceries":      4,
+			"Rent":           1,
+			"Transportation": 3,
+			"Entertainment":  1,
+		},
+		IncomeCents:   320000,
+		IncomeCadence: "monthly",
+		Volatility:    0.10,
+	},
+	"spender": {
+		Name: "spender",
+		BudgetCents: map[string]int64{
+			"Groceries":      80000,
+			"Rent":           250000,
+			"Transportation": 50000,
+			"Entertainment":  40000,
+		},
+		CategoryWeights: map[string]float64{
+			"Groceries":      3,
+			"Rent":           1,
+			"Transportation": 2,
+			"Entertainment":  6,
+		},
+		IncomeCents:   650000,
+		IncomeCadence: "monthly",
+		Volatility:    0.35,
+	},
+	"balanced": {
+		Name: "balanced",
+		BudgetCents: map[string]int64{
+			"Groceries":      45000,
+			"Rent":           120000,
+			"Transportation": 25000,
+			"Entertainment":  20000,
+		},
+		CategoryWeights: map[string]float64{
+			"Groceries":      4,
+			"Rent":           1,
+			"Transportation": 3,
+			"Entertainment":  3,
+		},
+		IncomeCents:   420000,
+		IncomeCadence: "monthly",
+		Volatility:    0.18,
+	},
+	"student": {
+		Name: "student",
+		BudgetCents: map[string]int64{
+			"Groceries":      15000,
+			"Rent":           60000,
+			"Transportation": 5000,
+			"Entertainment":  10000,
+		},
+		CategoryWeights: map[string]float64{
+			"Groceries":      3,
+			"Rent":           1,
+			"Transportation": 2,
+			"Entertainment":  3,
+		},
+		IncomeCents:   140000,
+		IncomeCadence: "biweekly",
+		Volatility:    0.25,
+	},
+	"freelancer": {
+		Name: "freelancer",
+		BudgetCents: map[string]int64{
+			"Groceries":      35000,
+			"Rent":           100000,
+			"Transportation": 15000,
+			"Entertainment":  15000,
+		},
+		CategoryWeights: map[string]float64{
+			"Groceries":      3,
+			"Rent":           1,
+			"Transportation": 2,
+			"Entertainment":  2,
+		},
+		IncomeCents:   380000,
+		IncomeCadence: "monthly",
+		Volatility:    0.45,
+	},
+	"retiree": {
+		Name: "retiree",
+		BudgetCents: map[string]int64{
+			"Groceries":      30000,
+			"Rent":           40000,
+			"Transportation": 8000,
+			"Entertainment":  12000,
+		},
+		CategoryWeights: map[string]float64{
+			"Groceries":      4,
+			"Rent":           1,
+			"Transportation": 1,
+			"Entertainment":  2,
+		},
+		IncomeCents:   280000,
+		IncomeCadence: "monthly",
+		Volatility:    0.08,
+	},
+}
@@ -0,0 +1,95 @@
+package seed
+
+import "fmt"
+
+// Scenario parameterizes a demo-data generation run: how many synthetic
+// users to create, which personas they're drawn from, how much history to
+// backfill, and the RNG seed that makes the run reproducible. This replaces
+// the previous hard-coded slice of four "conservative/spender/balanced/
+// student" users, so ML clustering tests can ask for e.g. 500 users across
+// 6 personas instead.
+type Scenario struct {
+	Name string
+
+	// Users is the total number of synthetic users to create, split evenly
+	// across Personas (round-robin).
+	Users int
+
+	// Personas are the persona names (keys into BuiltinPersonas) to draw
+	// from. Required, non-empty.
+	Personas []string
+
+	// Months is how many months of transaction history to backfill per
+	// user.
+	Months int
+
+	// Currency is the ISO currency code used for every generated account
+	// and budget.
+	Currency string
+
+	// TransactionsPerMonth is the approximate number of transactions
+	// generated per user per month (the exact count jitters with the
+	// persona's Volatility).
+	TransactionsPerMonth int
+
+	// Seed drives the deterministic math/rand.Rand used for this run; the
+	// same Seed + Scenario always produces the same data.
+	Seed int64
+}
+
+// BuiltinScenarios are the named scenarios exposed via the CLI
+// (`seed --scenario=NAME`) and the admin reseed endpoint.
+var BuiltinScenarios = map[string]Scenario{
+	"presentation": {
+		Name:                 "presentation",
+		Users:                4,
+		Personas:             []string{"conservative", "spender", "balanced", "student"},
+		Months:               3,
+		Currency:             "USD",
+		TransactionsPerMonth: 12,
+		Seed:                 1,
+	},
+	"clustering-eval": {
+		Name:                 "clustering-eval",
+		Users:                500,
+		Personas:             []string{"conservative", "spender", "balanced", "student", "freelancer", "retiree"},
+		Months:               12,
+		Currency:             "USD",
+		TransactionsPerMonth: 30,
+		Seed:                 42,
+	},
+	"load-test": {
+		Name:                 "load-test",
+		Users:                5000,
+		Personas:             []string{"conservative", "spender", "balanced", "student", "freelancer", "retiree"},
+		Months:               6,
+		Currency:             "USD",
+		TransactionsPerMonth: 40,
+		Seed:                 7,
+	},
+	"empty": {
+		Name:                 "empty",
+		Users:                0,
+		Personas:             nil,
+		Months:               0,
+		Currency:             "USD",
+		TransactionsPerMonth: 0,
+		Seed:                 0,
+	},
+}
+
+// ResolveScenario looks up a built-in scenario by name, applying overrides
+// for the fields a caller (CLI flags, admin endpoint body) explicitly set.
+func ResolveScenario(name string, seedOverride *int64, monthsOverride *int) (Scenario, error) {
+	scenario, ok := BuiltinScenarios[name]
+	if !ok {
+		return Scenario{}, fmt.Errorf("unknown scenario %q", name)
+	}
+	if seedOverride != nil {
+		scenario.Seed = *seedOverride
+	}
+	if monthsOverride != nil {
+		scenario.Months = *monthsOverride
+	}
+	return scenario, nil
+}
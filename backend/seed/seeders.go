@@ -0,0 +1,44 @@
+package seed
+
+import (
+	"Personal-Finance-Tracker-backend/migrations"
+
+	"gorm.io/gorm"
+)
+
+// AdminSeeder bootstraps the default SuperAdmin account. Registered in
+// seed_history as "default_admin" so it only ever runs once per database
+// unless an operator explicitly forces it.
+type AdminSeeder struct{}
+
+func (AdminSeeder) Name() string { return "default_admin" }
+
+func (AdminSeeder) Run(db *gorm.DB) error {
+	createDefaultAdmin(db)
+	return nil
+}
+
+// DemoDataSeeder generates a Scenario's worth of synthetic users and
+// transaction history. Each scenario gets its own seed_history entry
+// ("demo_data_<scenario>"), so re-seeding "load-test" doesn't touch
+// "presentation" data and vice versa.
+type DemoDataSeeder struct {
+	Scenario Scenario
+}
+
+func (s DemoDataSeeder) Name() string { return "demo_data_" + s.Scenario.Name }
+
+func (s DemoDataSeeder) Run(db *gorm.DB) error {
+	return SeedDemoData(db, s.Scenario)
+}
+
+// BuiltinSeeders returns the AdminSeeder, SystemCategorySeeder, and a
+// DemoDataSeeder for every built-in scenario, for the `seed status` CLI
+// to report on.
+func BuiltinSeeders() []migrations.Seeder {
+	seeders := []migrations.Seeder{AdminSeeder{}, SystemCategorySeeder{}}
+	for _, name := range []string{"presentation", "clustering-eval", "load-test", "empty"} {
+		seeders = append(seeders, DemoDataSeeder{Scenario: BuiltinScenarios[name]})
+	}
+	return seeders
+}
@@ -0,0 +1,404 @@
+// Package ai holds the forecasting logic behind budget predictions and
+// spending-pattern analysis, split out of controllers.GetBudgetPrediction
+// and GetSpendingPatterns so the same Service can be driven by more than
+// one transport (the existing REST handlers, and a gRPC server for
+// mobile clients - see proto/ai.proto).
+package ai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/forecast"
+	"Personal-Finance-Tracker-backend/pkg/money"
+	"Personal-Finance-Tracker-backend/pkg/timeutil"
+
+	"github.com/shopspring/decimal"
+)
+
+// centsOf converts a decimal money amount into the integer cents this
+// package's forecasting/pattern types have always used.
+func centsOf(amount decimal.Decimal) int64 {
+	return amount.Mul(decimal.NewFromInt(100)).Round(0).IntPart()
+}
+
+// Service is the transport-agnostic entry point for AI-assisted
+// forecasting. It holds no state of its own - every method reads straight
+// from db.DB - so the zero value is ready to use.
+type Service struct{}
+
+// NewService constructs a Service.
+func NewService() *Service {
+	return &Service{}
+}
+
+// Predictor is the subset of Service's behavior the caching/circuit-breaker
+// layer in controllers/ai_cache.go depends on, so tests can substitute a
+// mock there to simulate upstream failures without touching the database.
+type Predictor interface {
+	Predict(ctx context.Context, req PredictRequest) (PredictResponse, error)
+}
+
+// PatternsAnalyzer is the AnalyzePatterns half of Service, split out the
+// same way Predictor is so controllers/aidriver can plug in an alternate
+// backend (OpenAI, Ollama) behind GetSpendingPatterns without that handler
+// caring which is active.
+type PatternsAnalyzer interface {
+	AnalyzePatterns(ctx context.Context, req PatternsRequest) (PatternsResponse, error)
+}
+
+// PredictRequest is Predict's input.
+type PredictRequest struct {
+	UserID           uint
+	TargetPeriod     timeutil.YearMonth
+	HistoricalMonths int
+}
+
+// BudgetPrediction is one category's forecast for the target month.
+// PredictedAmount/HistoricalAvg replaced a Cents/Dollars pair apiece as of
+// chunk16-5 - money.Money keeps the authoritative integer cents and
+// derives its own display string, instead of every caller recomputing
+// (and risking drift on) a float64 dollar figure alongside it.
+type BudgetPrediction struct {
+	CategoryID      uint
+	CategoryName    string
+	PredictedAmount money.Money
+	ConfidenceScore float64
+	HistoricalAvg   money.Money
+	TrendDirection  string
+	Reasoning       string
+}
+
+// PredictResponse is Predict's output.
+type PredictResponse struct {
+	Predictions          []BudgetPrediction
+	TargetPeriod         timeutil.YearMonth
+	UserID               uint
+	HistoricalDataPoints int
+	Message              string
+	GeneratedAt          time.Time
+}
+
+// Predict forecasts spend per category for req.TargetPeriod from the user's
+// own transaction history, using forecast.PredictCategory - this used to proxy
+// to a Python AI sidecar over HTTP.
+func (s *Service) Predict(ctx context.Context, req PredictRequest) (PredictResponse, error) {
+	from := time.Now().AddDate(0, -req.HistoricalMonths, 0)
+	to := time.Now()
+
+	type categoryMonthRow struct {
+		CategoryID   uint
+		CategoryName string
+		Month        string
+		Total        decimal.Decimal
+	}
+	var rows []categoryMonthRow
+	db.DB.WithContext(ctx).Table("transactions").
+		Select("categories.id as category_id, categories.name as category_name, "+monthGroupExpr("transactions.txn_date")+" as month, SUM(ABS(transactions.amount)) as total").
+		Joins("JOIN categories ON categories.id = transactions.category_id").
+		Where("transactions.user_id = ? AND transactions.txn_date >= ? AND transactions.txn_date <= ? AND transactions.amount < 0", req.UserID, from, to).
+		Group("categories.id, categories.name, month").
+		Order("month ASC").
+		Scan(&rows)
+
+	type categoryHistory struct {
+		name    string
+		history []forecast.MonthlyTotal
+	}
+	categories := map[uint]*categoryHistory{}
+	var categoryIDs []uint
+	for _, row := range rows {
+		acc, ok := categories[row.CategoryID]
+		if !ok {
+			acc = &categoryHistory{name: row.CategoryName}
+			categories[row.CategoryID] = acc
+			categoryIDs = append(categoryIDs, row.CategoryID)
+		}
+		acc.history = append(acc.history, forecast.MonthlyTotal{Month: row.Month, AmountCents: centsOf(row.Total)})
+	}
+	sort.Slice(categoryIDs, func(i, j int) bool { return categoryIDs[i] < categoryIDs[j] })
+
+	horizon := monthsBetween(to, req.TargetPeriod.Time())
+	if horizon < 1 {
+		horizon = 1
+	}
+
+	predictions := []BudgetPrediction{}
+	var historicalDataPoints int
+	for _, categoryID := range categoryIDs {
+		acc := categories[categoryID]
+		historicalDataPoints += len(acc.history)
+
+		pred := forecast.PredictCategory(acc.history, horizon)
+		if pred == (forecast.Prediction{}) {
+			continue
+		}
+
+		predictions = append(predictions, BudgetPrediction{
+			CategoryID:      categoryID,
+			CategoryName:    acc.name,
+			PredictedAmount: money.FromCents(pred.PredictedAmountCents),
+			ConfidenceScore: pred.ConfidenceScore,
+			HistoricalAvg:   money.FromCents(pred.HistoricalAvgCents),
+			TrendDirection:  pred.TrendDirection,
+			Reasoning:       predictionReasoning(len(acc.history), pred),
+		})
+	}
+
+	message := "Predictions generated successfully"
+	if len(predictions) == 0 {
+		message = "Insufficient historical data for predictions"
+	}
+
+	return PredictResponse{
+		Predictions:          predictions,
+		TargetPeriod:         req.TargetPeriod,
+		UserID:               req.UserID,
+		HistoricalDataPoints: historicalDataPoints,
+		Message:              message,
+		GeneratedAt:          time.Now().UTC(),
+	}, nil
+}
+
+// predictionReasoning explains a forecast in the same register the AI
+// sidecar's canned "reasoning" strings used, so callers see prose rather
+// than raw statistics.
+func predictionReasoning(months int, pred forecast.Prediction) string {
+	switch pred.TrendDirection {
+	case "increasing":
+		return fmt.Sprintf("Based on %d months of history, spending in this category has been trending up.", months)
+	case "decreasing":
+		return fmt.Sprintf("Based on %d months of history, spending in this category has been trending down.", months)
+	default:
+		return fmt.Sprintf("Based on %d months of history, spending in this category has held steady.", months)
+	}
+}
+
+// monthsBetween returns the number of calendar months from `from` to `to`,
+// e.g. the horizon a budget-prediction target month lies ahead of `now`.
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+// PatternsRequest is AnalyzePatterns' input.
+type PatternsRequest struct {
+	UserID           uint
+	HistoricalMonths int
+}
+
+// SeasonalTrends names the highest- and lowest-spending months seen.
+type SeasonalTrends struct {
+	HighestMonth string
+	LowestMonth  string
+}
+
+// WeekendVsWeekday splits total spend into its weekend and weekday share.
+type WeekendVsWeekday struct {
+	WeekendRatio float64
+	WeekdayRatio float64
+}
+
+// Patterns is the behavioral summary AnalyzePatterns computes.
+type Patterns struct {
+	SpendingVelocity    string
+	CategoryConsistency float64
+	SeasonalTrends      SeasonalTrends
+	WeekendVsWeekday    WeekendVsWeekday
+}
+
+// PatternsResponse is AnalyzePatterns' output.
+type PatternsResponse struct {
+	UserID          uint
+	Patterns        Patterns
+	Insights        []string
+	Recommendations []string
+	AnalyzedPeriod  string
+	ConfidenceScore float64
+}
+
+// AnalyzePatterns summarizes how the user's spending has behaved over
+// HistoricalMonths - trend, per-category consistency, seasonality and
+// weekend/weekday split - computed from their own transactions rather than
+// proxied to the AI sidecar.
+func (s *Service) AnalyzePatterns(ctx context.Context, req PatternsRequest) (PatternsResponse, error) {
+	from := time.Now().AddDate(0, -req.HistoricalMonths, 0)
+	to := time.Now()
+	analyzedPeriod := fmt.Sprintf("%d months", req.HistoricalMonths)
+
+	type txnRow struct {
+		Amount  decimal.Decimal
+		TxnDate time.Time
+	}
+	var txns []txnRow
+	db.DB.WithContext(ctx).Table("transactions").
+		Select("amount, txn_date").
+		Where("user_id = ? AND txn_date >= ? AND txn_date <= ? AND amount < 0", req.UserID, from, to).
+		Scan(&txns)
+
+	if len(txns) == 0 {
+		return PatternsResponse{
+			UserID:          req.UserID,
+			Insights:        []string{},
+			Recommendations: []string{},
+			AnalyzedPeriod:  analyzedPeriod,
+			ConfidenceScore: 0,
+		}, nil
+	}
+
+	var totalCents, weekendCents, weekdayCents int64
+	monthTotals := map[string]int64{}
+	for _, txn := range txns {
+		amount := -centsOf(txn.Amount)
+		totalCents += amount
+		if weekday := txn.TxnDate.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+			weekendCents += amount
+		} else {
+			weekdayCents += amount
+		}
+		monthTotals[txn.TxnDate.Format("2006-01")] += amount
+	}
+
+	var months []string
+	for month := range monthTotals {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	highestMonth, lowestMonth := months[0], months[0]
+	series := make([]forecast.MonthlyTotal, 0, len(months))
+	for _, month := range months {
+		if monthTotals[month] > monthTotals[highestMonth] {
+			highestMonth = month
+		}
+		if monthTotals[month] < monthTotals[lowestMonth] {
+			lowestMonth = month
+		}
+		series = append(series, forecast.MonthlyTotal{Month: month, AmountCents: monthTotals[month]})
+	}
+
+	overall := forecast.PredictCategory(series, 1)
+	velocity := "moderate"
+	switch overall.TrendDirection {
+	case "increasing":
+		velocity = "accelerating"
+	case "decreasing":
+		velocity = "slowing"
+	}
+
+	categoryConsistency := categorySpendConsistency(ctx, req.UserID, from, to)
+	weekendRatio := round2(float64(weekendCents) / float64(totalCents))
+	weekdayRatio := round2(float64(weekdayCents) / float64(totalCents))
+
+	insights := []string{
+		fmt.Sprintf("%s was your highest-spending month, %s your lowest", highestMonth, lowestMonth),
+	}
+	if categoryConsistency > 0.7 {
+		insights = append(insights, "Your spending is highly consistent month-to-month")
+	}
+	if weekendRatio > 0.4 {
+		insights = append(insights, "A large share of your spending happens on weekends")
+	}
+
+	recommendations := []string{}
+	if overall.TrendDirection == "increasing" {
+		recommendations = append(recommendations, "Consider a tighter budget next month - spending has been trending up")
+	}
+	if weekendRatio > 0.4 {
+		recommendations = append(recommendations, "Set weekend spending alerts to help keep discretionary spend in check")
+	}
+
+	return PatternsResponse{
+		UserID: req.UserID,
+		Patterns: Patterns{
+			SpendingVelocity:    velocity,
+			CategoryConsistency: round2(categoryConsistency),
+			SeasonalTrends:      SeasonalTrends{HighestMonth: highestMonth, LowestMonth: lowestMonth},
+			WeekendVsWeekday:    WeekendVsWeekday{WeekendRatio: weekendRatio, WeekdayRatio: weekdayRatio},
+		},
+		Insights:        insights,
+		Recommendations: recommendations,
+		AnalyzedPeriod:  analyzedPeriod,
+		ConfidenceScore: overall.ConfidenceScore,
+	}, nil
+}
+
+// categorySpendConsistency averages, across every category the user spent
+// in during [from, to], a 0-1 score derived from that category's
+// coefficient of variation across months - 1 is dead flat spend, 0 is
+// wildly erratic.
+func categorySpendConsistency(ctx context.Context, userID uint, from, to time.Time) float64 {
+	type categoryMonthRow struct {
+		CategoryID uint
+		Month      string
+		Total      decimal.Decimal
+	}
+	var rows []categoryMonthRow
+	db.DB.WithContext(ctx).Table("transactions").
+		Select("category_id, "+monthGroupExpr("txn_date")+" as month, SUM(ABS(amount)) as total").
+		Where("user_id = ? AND txn_date >= ? AND txn_date <= ? AND amount < 0", userID, from, to).
+		Group("category_id, month").
+		Scan(&rows)
+
+	byCategory := map[uint][]float64{}
+	for _, row := range rows {
+		byCategory[row.CategoryID] = append(byCategory[row.CategoryID], float64(centsOf(row.Total)))
+	}
+	if len(byCategory) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, amounts := range byCategory {
+		sum += consistencyScore(amounts)
+	}
+	return sum / float64(len(byCategory))
+}
+
+// consistencyScore turns a series' coefficient of variation (stddev/mean)
+// into a 0-1 score; a single month has nothing to vary against, so it
+// scores as fully consistent.
+func consistencyScore(amounts []float64) float64 {
+	if len(amounts) < 2 {
+		return 1
+	}
+	var sum float64
+	for _, amount := range amounts {
+		sum += amount
+	}
+	mean := sum / float64(len(amounts))
+	if mean == 0 {
+		return 1
+	}
+
+	var sumSquaredDiff float64
+	for _, amount := range amounts {
+		diff := amount - mean
+		sumSquaredDiff += diff * diff
+	}
+	stdDev := math.Sqrt(sumSquaredDiff / float64(len(amounts)))
+	coefficientOfVariation := stdDev / mean
+
+	score := 1 - math.Min(1, coefficientOfVariation)
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
+
+// monthGroupExpr returns the driver-appropriate SQL expression that
+// truncates a timestamp column to its "YYYY-MM" month bucket, mirroring
+// the same helper in controllers/reports.go and store/admin_store.go.
+func monthGroupExpr(column string) string {
+	if db.DB.Dialector.Name() == "sqlite" {
+		return "STRFTIME('%Y-%m', " + column + ")"
+	}
+	return "TO_CHAR(DATE_TRUNC('month', " + column + "), 'YYYY-MM')"
+}
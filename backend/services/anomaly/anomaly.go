@@ -0,0 +1,317 @@
+// Package anomaly flags transactions that deviate sharply from a user's own
+// historical spend in that category, using a robust z-score on the median
+// absolute deviation (MAD) rather than mean/stddev - a few large but
+// legitimate purchases (rent, a laptop) would otherwise inflate a normal
+// z-score's standard deviation and hide genuine outliers. Detect is used
+// both synchronously by controllers.GetAnomalies and from the ingestion
+// path (see NotifyAnomalies), which queues matching hits for delivery to
+// the user's registered models.AnomalyWebhook endpoints.
+package anomaly
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// DefaultThreshold is k in |x - median| / (1.4826*MAD) > k, the robust
+// z-score cutoff Detect applies when the caller passes threshold <= 0.
+const DefaultThreshold = 3.5
+
+// madScaleFactor (1.4826) scales MAD into a consistent estimator of the
+// standard deviation for normally-distributed data - the same constant
+// scipy.stats.median_abs_deviation(scale='normal') and Iglewicz & Hoaglin's
+// modified z-score use.
+const madScaleFactor = 1.4826
+
+// minCategorySample is the fewest transactions a category needs before
+// Detect scores it at all - MAD on a handful of points is too noisy to
+// trust and would flag the second-ever purchase in a new category as an
+// anomaly just for not matching the first.
+const minCategorySample = 5
+
+// Anomaly is one transaction Detect flagged as deviating from its
+// category's historical distribution.
+type Anomaly struct {
+	CategoryID    uint
+	TransactionID uint
+	AmountCents   int64
+	Score         float64
+	ExpectedLow   int64
+	ExpectedHigh  int64
+	DetectedAt    time.Time
+}
+
+type categoryTxnRow struct {
+	TransactionID uint
+	CategoryID    uint
+	Amount        decimal.Decimal
+}
+
+// amountCentsOf converts a decimal transaction amount into the integer
+// cents this package's Anomaly/webhook payload has always used.
+func amountCentsOf(amount decimal.Decimal) int64 {
+	return amount.Mul(decimal.NewFromInt(100)).Round(0).IntPart()
+}
+
+// Detect scans userID's categorized transactions from the last
+// historicalMonths months and flags every one whose robust z-score against
+// its own category's distribution exceeds threshold (DefaultThreshold if
+// threshold <= 0).
+func Detect(ctx context.Context, userID uint, historicalMonths int, threshold float64) ([]Anomaly, error) {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	from := time.Now().AddDate(0, -historicalMonths, 0)
+
+	var rows []categoryTxnRow
+	if err := db.DB.WithContext(ctx).Table("transactions").
+		Select("id as transaction_id, category_id, amount").
+		Where("user_id = ? AND txn_date >= ? AND category_id IS NOT NULL", userID, from).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	byCategory := map[uint][]categoryTxnRow{}
+	for _, row := range rows {
+		byCategory[row.CategoryID] = append(byCategory[row.CategoryID], row)
+	}
+
+	var anomalies []Anomaly
+	for categoryID, categoryRows := range byCategory {
+		anomalies = append(anomalies, detectInCategory(categoryID, categoryRows, threshold)...)
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool { return anomalies[i].Score > anomalies[j].Score })
+	return anomalies, nil
+}
+
+// detectInCategory runs the MAD test over one category's transactions.
+func detectInCategory(categoryID uint, rows []categoryTxnRow, threshold float64) []Anomaly {
+	if len(rows) < minCategorySample {
+		return nil
+	}
+
+	amounts := make([]float64, len(rows))
+	for i, row := range rows {
+		amounts[i] = math.Abs(float64(amountCentsOf(row.Amount)))
+	}
+	center := median(amounts)
+
+	deviations := make([]float64, len(amounts))
+	for i, amount := range amounts {
+		deviations[i] = math.Abs(amount - center)
+	}
+	mad := median(deviations)
+	if mad == 0 {
+		// Every amount in the category is identical - nothing to score
+		// against, and treating the tiniest float rounding as a deviation
+		// would flag every transaction at once.
+		return nil
+	}
+
+	low := int64(math.Round(center - threshold*madScaleFactor*mad))
+	high := int64(math.Round(center + threshold*madScaleFactor*mad))
+
+	var anomalies []Anomaly
+	for i, row := range rows {
+		score := math.Abs(amounts[i]-center) / (madScaleFactor * mad)
+		if score <= threshold {
+			continue
+		}
+		anomalies = append(anomalies, Anomaly{
+			CategoryID:    categoryID,
+			TransactionID: row.TransactionID,
+			AmountCents:   amountCentsOf(row.Amount),
+			Score:         round2(score),
+			ExpectedLow:   low,
+			ExpectedHigh:  high,
+			DetectedAt:    time.Now().UTC(),
+		})
+	}
+	return anomalies
+}
+
+// median returns the middle value of values (averaging the two middle
+// values for an even-length slice), without mutating the caller's slice.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
+
+// webhookDeliveryBufferSize bounds pending webhook deliveries queued before
+// NotifyAnomalies starts dropping them rather than blocking the
+// transaction-ingestion request that triggered detection.
+const webhookDeliveryBufferSize = 256
+
+type webhookDelivery struct {
+	webhook models.AnomalyWebhook
+	anomaly Anomaly
+}
+
+var (
+	deliveryCh     chan webhookDelivery
+	deliveryWg     sync.WaitGroup
+	deliveryOnce   sync.Once
+	deliveryMu     sync.Mutex
+	deliveryClosed bool
+	httpClient     = &http.Client{Timeout: 10 * time.Second}
+)
+
+// StartWebhookWorker starts the background goroutine that delivers queued
+// anomaly webhook payloads. Safe to call once from main at startup; a
+// second call is a no-op.
+func StartWebhookWorker() {
+	deliveryOnce.Do(func() {
+		deliveryCh = make(chan webhookDelivery, webhookDeliveryBufferSize)
+		deliveryWg.Add(1)
+		go webhookWorkerLoop()
+	})
+}
+
+// StopWebhookWorker closes the delivery channel and blocks until every
+// queued delivery has been attempted, mirroring
+// middleware.StopAuthAuditWorker.
+func StopWebhookWorker() {
+	deliveryMu.Lock()
+	if deliveryCh == nil || deliveryClosed {
+		deliveryMu.Unlock()
+		return
+	}
+	deliveryClosed = true
+	close(deliveryCh)
+	deliveryMu.Unlock()
+
+	deliveryWg.Wait()
+}
+
+func webhookWorkerLoop() {
+	defer deliveryWg.Done()
+	for d := range deliveryCh {
+		deliverWebhook(d.webhook, d.anomaly)
+	}
+}
+
+// NotifyAnomalies enqueues a delivery for every models.AnomalyWebhook
+// registered for userID whose MinScore each anomaly clears. It never blocks
+// the caller: if the worker hasn't been started (e.g. in tests), each
+// delivery is attempted inline instead, and if the buffer is full the
+// delivery is dropped and logged so a slow or unreachable endpoint can't
+// back up transaction ingestion.
+func NotifyAnomalies(userID uint, anomalies []Anomaly) {
+	if len(anomalies) == 0 {
+		return
+	}
+	var webhooks []models.AnomalyWebhook
+	if err := db.DB.Where("user_id = ?", userID).Find(&webhooks).Error; err != nil {
+		utils.Logger.Warn("anomaly webhook lookup failed", zap.Error(err))
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	for _, a := range anomalies {
+		for _, webhook := range webhooks {
+			if a.Score < webhook.MinScore {
+				continue
+			}
+			enqueue(webhookDelivery{webhook: webhook, anomaly: a})
+		}
+	}
+}
+
+func enqueue(d webhookDelivery) {
+	deliveryMu.Lock()
+	ch := deliveryCh
+	deliveryMu.Unlock()
+
+	if ch == nil {
+		deliverWebhook(d.webhook, d.anomaly)
+		return
+	}
+	select {
+	case ch <- d:
+	default:
+		utils.Logger.Warn("anomaly webhook delivery dropped: queue full")
+	}
+}
+
+// webhookPayload is the JSON body POSTed to a registered AnomalyWebhook.URL.
+type webhookPayload struct {
+	CategoryID    uint      `json:"category_id"`
+	TransactionID uint      `json:"transaction_id"`
+	Amount        int64     `json:"amount"`
+	Score         float64   `json:"score"`
+	ExpectedRange [2]int64  `json:"expected_range"`
+	DetectedAt    time.Time `json:"detected_at"`
+}
+
+// deliverWebhook POSTs a to webhook.URL, signing the body with
+// webhook.Secret via an X-Anomaly-Signature HMAC-SHA256 header so the
+// receiving endpoint can verify the request came from this server.
+func deliverWebhook(webhook models.AnomalyWebhook, a Anomaly) {
+	payload := webhookPayload{
+		CategoryID:    a.CategoryID,
+		TransactionID: a.TransactionID,
+		Amount:        a.AmountCents,
+		Score:         a.Score,
+		ExpectedRange: [2]int64{a.ExpectedLow, a.ExpectedHigh},
+		DetectedAt:    a.DetectedAt,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		utils.Logger.Warn("anomaly webhook payload marshal failed", zap.Error(err))
+		return
+	}
+
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		utils.Logger.Warn("anomaly webhook request build failed", zap.Error(err))
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Anomaly-Signature", signature)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		utils.Logger.Warn("anomaly webhook delivery failed", zap.String("url", webhook.URL), zap.Error(err))
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		utils.Logger.Warn("anomaly webhook endpoint returned non-2xx", zap.String("url", webhook.URL), zap.Int("status", resp.StatusCode))
+	}
+}
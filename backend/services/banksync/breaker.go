@@ -0,0 +1,174 @@
+package banksync
+
+import (
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+)
+
+// BreakerState is the circuit breaker state GetBankConnections and the
+// background sync scheduler key a connection's sync health off of.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// DefaultFailureThreshold is the number of consecutive failed syncs that
+// opens a connection's breaker, unless overridden by SetFailureThreshold.
+const DefaultFailureThreshold = 5
+
+// openCooldown is how long an open breaker waits before letting one
+// half-open probe sync through.
+const openCooldown = 1 * time.Hour
+
+// connectionBreaker tracks one BankConnection's consecutive sync failures
+// and breaker state.
+type connectionBreaker struct {
+	state               BreakerState
+	consecutiveFailures int
+	openedAt            time.Time
+}
+
+// breakerStore is the in-memory map of BankConnectionID -> connectionBreaker
+// behind the package-level functions below, guarded by a single mutex -
+// mirrors middleware.memoryTokenBlacklist's store-behind-package-funcs shape.
+type breakerStore struct {
+	mu        sync.Mutex
+	breakers  map[uint]*connectionBreaker
+	threshold int
+}
+
+var defaultBreakerStore = &breakerStore{
+	breakers:  make(map[uint]*connectionBreaker),
+	threshold: DefaultFailureThreshold,
+}
+
+// SetFailureThreshold overrides the default consecutive-failure count that
+// opens a breaker - exposed for tests.
+func SetFailureThreshold(n int) {
+	defaultBreakerStore.mu.Lock()
+	defer defaultBreakerStore.mu.Unlock()
+	defaultBreakerStore.threshold = n
+}
+
+func (s *breakerStore) get(connectionID uint) *connectionBreaker {
+	cb, ok := s.breakers[connectionID]
+	if !ok {
+		cb = &connectionBreaker{state: BreakerClosed}
+		s.breakers[connectionID] = cb
+	}
+	return cb
+}
+
+// RecordResult updates connectionID's breaker after one sync attempt and
+// returns its resulting state: a success closes the breaker and resets its
+// failure count, a failure opens it once consecutiveFailures reaches the
+// threshold.
+func RecordResult(connectionID uint, success bool) BreakerState {
+	defaultBreakerStore.mu.Lock()
+	defer defaultBreakerStore.mu.Unlock()
+
+	cb := defaultBreakerStore.get(connectionID)
+	if success {
+		cb.consecutiveFailures = 0
+		cb.state = BreakerClosed
+		return cb.state
+	}
+
+	cb.consecutiveFailures++
+	if cb.consecutiveFailures >= defaultBreakerStore.threshold {
+		cb.state = BreakerOpen
+		cb.openedAt = time.Now()
+	}
+	return cb.state
+}
+
+// AllowSync reports whether a sync attempt should proceed for connectionID:
+// true when the breaker is closed, or when an open breaker's cooldown has
+// elapsed - which also flips it to half_open, admitting exactly the one
+// probe attempt that state allows.
+func AllowSync(connectionID uint) bool {
+	defaultBreakerStore.mu.Lock()
+	defer defaultBreakerStore.mu.Unlock()
+
+	cb, ok := defaultBreakerStore.breakers[connectionID]
+	if !ok || cb.state == BreakerClosed {
+		return true
+	}
+	if cb.state == BreakerOpen && time.Since(cb.openedAt) >= openCooldown {
+		cb.state = BreakerHalfOpen
+		return true
+	}
+	return false
+}
+
+// Status returns the breaker state and consecutive-failure count recorded
+// for connectionID, defaulting to closed/0 for a connection the breaker
+// hasn't seen a result for yet.
+func Status(connectionID uint) (BreakerState, int) {
+	defaultBreakerStore.mu.Lock()
+	defer defaultBreakerStore.mu.Unlock()
+
+	cb, ok := defaultBreakerStore.breakers[connectionID]
+	if !ok {
+		return BreakerClosed, 0
+	}
+	return cb.state, cb.consecutiveFailures
+}
+
+// Reset manually closes connectionID's breaker, the way a user who has
+// fixed the underlying issue (expired credentials, bank-side outage) would
+// want to force the next sync through without waiting out the cooldown.
+func Reset(connectionID uint) {
+	defaultBreakerStore.mu.Lock()
+	defer defaultBreakerStore.mu.Unlock()
+	delete(defaultBreakerStore.breakers, connectionID)
+}
+
+// Recover seeds every connection's breaker from its most recent
+// BankSyncLog rows, so a process restart doesn't silently re-close breakers
+// that were open when it stopped. Call once at startup, before the sync
+// scheduler's first tick.
+func Recover() error {
+	var connectionIDs []uint
+	if err := db.DB.Model(&models.BankSyncLog{}).Distinct().Pluck("bank_connection_id", &connectionIDs).Error; err != nil {
+		return err
+	}
+
+	defaultBreakerStore.mu.Lock()
+	defer defaultBreakerStore.mu.Unlock()
+
+	for _, id := range connectionIDs {
+		var logs []models.BankSyncLog
+		if err := db.DB.Where("bank_connection_id = ?", id).
+			Order("created_at DESC").
+			Limit(defaultBreakerStore.threshold).
+			Find(&logs).Error; err != nil {
+			return err
+		}
+
+		consecutiveFailures := 0
+		for _, logEntry := range logs {
+			if logEntry.Status != "failed" {
+				break
+			}
+			consecutiveFailures++
+		}
+		if consecutiveFailures == 0 {
+			continue
+		}
+
+		cb := &connectionBreaker{consecutiveFailures: consecutiveFailures, state: BreakerClosed}
+		if consecutiveFailures >= defaultBreakerStore.threshold {
+			cb.state = BreakerOpen
+			cb.openedAt = time.Now()
+		}
+		defaultBreakerStore.breakers[id] = cb
+	}
+	return nil
+}
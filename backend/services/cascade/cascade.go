@@ -0,0 +1,160 @@
+// Package cascade holds the declarative, child-first deletion graphs the
+// admin DELETE endpoints use, so a row-count preview (?dry_run=true) and the
+// real deletion always walk the exact same steps in the exact same order -
+// a new model can't silently leak rows just because a handler forgot to
+// delete it, the way the old hand-written per-handler ordering could.
+package cascade
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"Personal-Finance-Tracker-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// DefaultPurgeGracePeriod is how long a soft-deleted admin resource is kept
+// around (restorable) before the purge worker hard-deletes it.
+const DefaultPurgeGracePeriod = 7 * 24 * time.Hour
+
+// DeleteStep is one row-count entry in a Plan.
+type DeleteStep struct {
+	Model string `json:"model"`
+	Count int64  `json:"count"`
+}
+
+// Plan is the ordered, child-first list of deletions CascadePlan computes
+// for one entity/ID pair. Dry-running a plan (returning it to the admin
+// without executing) and Executing it for real both walk the same
+// declarative graph, so a preview can never drift from what actually gets
+// deleted.
+type Plan struct {
+	Entity string       `json:"entity"`
+	ID     uint         `json:"id"`
+	Steps  []DeleteStep `json:"steps"`
+}
+
+// step is one entry of an entity's child-first dependency graph. soft marks
+// whether the step honors the caller's force flag and grace period (an
+// owned, independently-restorable resource like a budget or account) or is
+// always hard-deleted outright (a dependent row like a transaction split
+// that has no existence, or restore story, of its own).
+type step struct {
+	modelName string
+	model     func() interface{}
+	where     string
+	soft      bool
+}
+
+// graphs maps each supported entity to its child-first deletion order. Add
+// a new model's cascade here, not in a handler - this is the one place
+// future schema changes must touch.
+var graphs = map[string][]step{
+	"user": {
+		{modelName: "budget_items", model: func() interface{} { return &models.BudgetItem{} }, where: "budget_id IN (SELECT id FROM budgets WHERE user_id = ?)", soft: false},
+		{modelName: "budgets", model: func() interface{} { return &models.Budget{} }, where: "user_id = ?", soft: true},
+		{modelName: "transaction_splits", model: func() interface{} { return &models.TransactionSplit{} }, where: "parent_txn_id IN (SELECT id FROM transactions WHERE user_id = ?)", soft: false},
+		{modelName: "transactions", model: func() interface{} { return &models.Transaction{} }, where: "user_id = ?", soft: true},
+		{modelName: "categories", model: func() interface{} { return &models.Category{} }, where: "user_id = ?", soft: true},
+		{modelName: "accounts", model: func() interface{} { return &models.Account{} }, where: "user_id = ?", soft: true},
+		{modelName: "users", model: func() interface{} { return &models.User{} }, where: "id = ?", soft: true},
+	},
+	"account": {
+		{modelName: "transaction_splits", model: func() interface{} { return &models.TransactionSplit{} }, where: "parent_txn_id IN (SELECT id FROM transactions WHERE account_id = ?)", soft: false},
+		{modelName: "transactions", model: func() interface{} { return &models.Transaction{} }, where: "account_id = ?", soft: true},
+		{modelName: "accounts", model: func() interface{} { return &models.Account{} }, where: "id = ?", soft: true},
+	},
+	"budget": {
+		{modelName: "budget_items", model: func() interface{} { return &models.BudgetItem{} }, where: "budget_id = ?", soft: false},
+		{modelName: "budgets", model: func() interface{} { return &models.Budget{} }, where: "id = ?", soft: true},
+	},
+	"transaction": {
+		{modelName: "transaction_splits", model: func() interface{} { return &models.TransactionSplit{} }, where: "parent_txn_id = ?", soft: false},
+		{modelName: "transactions", model: func() interface{} { return &models.Transaction{} }, where: "id = ?", soft: true},
+	},
+}
+
+// CascadePlan counts, but does not delete, every row entity/id's deletion
+// graph would touch, child-first.
+func CascadePlan(ctx context.Context, tx *gorm.DB, entity string, id uint) (Plan, error) {
+	steps, ok := graphs[entity]
+	if !ok {
+		return Plan{}, fmt.Errorf("no cascade graph registered for entity %q", entity)
+	}
+
+	tx = tx.WithContext(ctx)
+	plan := Plan{Entity: entity, ID: id}
+	for _, s := range steps {
+		var count int64
+		if err := tx.Model(s.model()).Where(s.where, id).Count(&count).Error; err != nil {
+			return Plan{}, err
+		}
+		plan.Steps = append(plan.Steps, DeleteStep{Model: s.modelName, Count: count})
+	}
+	return plan, nil
+}
+
+// Execute deletes entity/id's cascade graph inside tx, in the same
+// child-first order CascadePlan counted it in. Steps marked soft in the
+// graph go through SoftDelete (honoring force); the rest are always
+// hard-deleted. The caller owns tx's lifecycle (commit/rollback).
+func Execute(ctx context.Context, tx *gorm.DB, entity string, id uint, force bool) error {
+	steps, ok := graphs[entity]
+	if !ok {
+		return fmt.Errorf("no cascade graph registered for entity %q", entity)
+	}
+
+	tx = tx.WithContext(ctx)
+	for _, s := range steps {
+		if s.soft {
+			if err := SoftDelete(tx, s.model(), s.where, []interface{}{id}, force); err != nil {
+				return err
+			}
+		} else if err := tx.Where(s.where, id).Delete(s.model()).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SoftDelete marks matching rows with a PurgeAfter deadline and soft-deletes
+// them (GORM's gorm.DeletedAt scope hides them from normal queries from then
+// on), or removes them outright when force is true.
+func SoftDelete(tx *gorm.DB, model interface{}, where string, args []interface{}, force bool) error {
+	if force {
+		return tx.Unscoped().Where(where, args...).Delete(model).Error
+	}
+	purgeAfter := time.Now().Add(DefaultPurgeGracePeriod)
+	if err := tx.Model(model).Where(where, args...).Update("purge_after", purgeAfter).Error; err != nil {
+		return err
+	}
+	return tx.Where(where, args...).Delete(model).Error
+}
+
+// Restore clears DeletedAt/PurgeAfter on matching rows, undoing a soft
+// delete made within its grace period.
+func Restore(tx *gorm.DB, model interface{}, where string, args []interface{}) error {
+	return tx.Unscoped().Model(model).Where(where, args...).Updates(map[string]interface{}{
+		"deleted_at":  nil,
+		"purge_after": nil,
+	}).Error
+}
+
+// PlanHash returns a hex-encoded sha256 digest of plan's canonical JSON
+// representation, for an optional ?confirm=<hash> guard: a caller can
+// require the admin to re-submit the exact hash of the plan they previewed
+// via ?dry_run=true, so a stale preview can't be used to delete rows they
+// never saw.
+func PlanHash(plan Plan) (string, error) {
+	b, err := json.Marshal(plan)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
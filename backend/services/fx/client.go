@@ -0,0 +1,82 @@
+package fx
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+)
+
+// maxLookback bounds how far RateOn walks backward to find a cached or
+// fetchable rate before giving up, so a currency pair with no history ever
+// returns an error instead of scanning indefinitely.
+const maxLookback = 14 * 24 * time.Hour
+
+var (
+	mu             sync.Mutex
+	activeProvider Provider = NewECBProvider()
+)
+
+// SetProvider swaps the active Provider, for tests or for pointing at a
+// paid feed in deployments that outgrow ECB's free reference rates.
+func SetProvider(p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+	activeProvider = p
+}
+
+func currentProvider() Provider {
+	mu.Lock()
+	defer mu.Unlock()
+	return activeProvider
+}
+
+// RateOn returns 1 base == rate quote on the nearest date at or before on,
+// checking the models.FxRate cache first and only calling the Provider on a
+// cache miss, caching what it fetches for next time.
+func RateOn(base, quote string, on time.Time) (float64, error) {
+	if base == quote {
+		return 1, nil
+	}
+
+	day := on.Truncate(24 * time.Hour)
+	for elapsed := time.Duration(0); elapsed <= maxLookback; elapsed += 24 * time.Hour {
+		candidate := day.Add(-elapsed)
+
+		var cached models.FxRate
+		err := db.DB.Where("base = ? AND quote = ? AND date = ?", base, quote, candidate).First(&cached).Error
+		if err == nil {
+			return cached.Rate, nil
+		}
+
+		rate, fetchErr := currentProvider().FetchRate(base, quote, candidate)
+		if fetchErr != nil {
+			continue
+		}
+
+		record := models.FxRate{Base: base, Quote: quote, Date: candidate, Rate: rate}
+		db.DB.Where(models.FxRate{Base: base, Quote: quote, Date: candidate}).FirstOrCreate(&record)
+		return rate, nil
+	}
+
+	return 0, fmt.Errorf("fx: no rate available for %s/%s within %s of %s", base, quote, maxLookback, on.Format("2006-01-02"))
+}
+
+// ConvertCents converts amount (in minor units of from) into to as of on,
+// falling back to the nearest prior date's rate when on has no published
+// rate yet (e.g. a same-day transaction before the provider's daily
+// update).
+func ConvertCents(amount int64, from, to string, on time.Time) (int64, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	rate, err := RateOn(from, to, on)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(float64(amount) * rate), nil
+}
@@ -0,0 +1,125 @@
+package fx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+
+	"Personal-Finance-Tracker-backend/config"
+)
+
+// DefaultProviderURL is the ECB's free, no-auth-required historical daily
+// reference rate feed, covering the last 90 days against EUR. Overridable
+// via the fx.ecb_feed_url config key for self-hosting the feed or pointing
+// at a different provider that serves the same envelope shape.
+const DefaultProviderURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+
+// ECBProvider fetches ECB reference rates, which are always quoted against
+// EUR; FetchRate derives any other base/quote pair as a cross rate through
+// EUR. The parsed feed is cached in-memory for the process lifetime since
+// ECB only publishes one update per banking day.
+type ECBProvider struct {
+	URL        string
+	HTTPClient *http.Client
+
+	cached *ecbEnvelope
+}
+
+// NewECBProvider returns a Provider pointed at DefaultProviderURL, or the
+// fx.ecb_feed_url config override if one is set.
+func NewECBProvider() *ECBProvider {
+	return &ECBProvider{
+		URL:        config.GetOr("fx.ecb_feed_url", DefaultProviderURL),
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type ecbEnvelope struct {
+	Days []ecbDay `xml:"Cube>Cube"`
+}
+
+type ecbDay struct {
+	Time  string    `xml:"time,attr"`
+	Rates []ecbRate `xml:"Cube"`
+}
+
+type ecbRate struct {
+	Currency string  `xml:"currency,attr"`
+	Rate     float64 `xml:"rate,attr"`
+}
+
+// FetchRate returns 1 base == rate quote on date. EUR participates as the
+// feed's implicit 1.0 base; any other pair is derived as a cross rate.
+func (p *ECBProvider) FetchRate(base, quote string, date time.Time) (float64, error) {
+	if base == quote {
+		return 1, nil
+	}
+
+	eurRates, err := p.eurRatesOn(date)
+	if err != nil {
+		return 0, err
+	}
+
+	baseRate := 1.0
+	if base != "EUR" {
+		r, ok := eurRates[base]
+		if !ok {
+			return 0, fmt.Errorf("fx: no ECB rate for %s on %s", base, date.Format("2006-01-02"))
+		}
+		baseRate = r
+	}
+
+	quoteRate := 1.0
+	if quote != "EUR" {
+		r, ok := eurRates[quote]
+		if !ok {
+			return 0, fmt.Errorf("fx: no ECB rate for %s on %s", quote, date.Format("2006-01-02"))
+		}
+		quoteRate = r
+	}
+
+	return quoteRate / baseRate, nil
+}
+
+// eurRatesOn returns the day's currency->EUR-relative-rate map, fetching
+// and caching the full 90-day feed on first use.
+func (p *ECBProvider) eurRatesOn(date time.Time) (map[string]float64, error) {
+	if p.cached == nil {
+		envelope, err := p.fetch()
+		if err != nil {
+			return nil, err
+		}
+		p.cached = envelope
+	}
+
+	dateStr := date.Format("2006-01-02")
+	for _, day := range p.cached.Days {
+		if day.Time == dateStr {
+			rates := make(map[string]float64, len(day.Rates))
+			for _, r := range day.Rates {
+				rates[r.Currency] = r.Rate
+			}
+			return rates, nil
+		}
+	}
+	return nil, fmt.Errorf("fx: ECB feed has no entry for %s", dateStr)
+}
+
+func (p *ECBProvider) fetch() (*ecbEnvelope, error) {
+	resp, err := p.HTTPClient.Get(p.URL)
+	if err != nil {
+		return nil, fmt.Errorf("fx: fetch ECB feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fx: ECB feed returned %d", resp.StatusCode)
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("fx: decode ECB feed: %w", err)
+	}
+	return &envelope, nil
+}
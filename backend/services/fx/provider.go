@@ -0,0 +1,13 @@
+// Package fx resolves a historical Base->Quote conversion rate for a given
+// date, caching each day's rate in models.FxRate so a configurable upstream
+// Provider (ECB reference rates by default) is only hit once per day per
+// currency pair.
+package fx
+
+import "time"
+
+// Provider fetches a single day's exchange rate from an upstream source.
+// Rate is expressed as 1 base == Rate quote.
+type Provider interface {
+	FetchRate(base, quote string, date time.Time) (float64, error)
+}
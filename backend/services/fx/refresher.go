@@ -0,0 +1,127 @@
+package fx
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+)
+
+// refreshInterval is how often the background refresher re-warms the
+// models.FxRate cache. ECB only publishes one update per banking day, so
+// there's no benefit to polling more often than this.
+const refreshInterval = 6 * time.Hour
+
+var (
+	refresherOnce sync.Once
+	refresherStop chan struct{}
+	refresherWg   sync.WaitGroup
+)
+
+// StartRateRefresher starts the background goroutine that keeps today's
+// rate cached for every currency pair in active use (every distinct
+// account/budget currency against every other), so a request needing a
+// less common pair's rate doesn't pay the provider's fetch latency inline.
+// It is a no-op if already started - safe to call once from main at
+// startup.
+func StartRateRefresher() {
+	refresherOnce.Do(func() {
+		refresherStop = make(chan struct{})
+		refresherWg.Add(1)
+		go refresherLoop()
+	})
+}
+
+// StopRateRefresher stops the background refresher and blocks until its
+// current cycle, if any, finishes. Call it once, during shutdown.
+func StopRateRefresher() {
+	if refresherStop == nil {
+		return
+	}
+	close(refresherStop)
+	refresherWg.Wait()
+}
+
+func refresherLoop() {
+	defer refresherWg.Done()
+	refreshActivePairs()
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			refreshActivePairs()
+		case <-refresherStop:
+			return
+		}
+	}
+}
+
+// refreshActivePairs fetches and caches today's rate for every ordered
+// pair among activeCurrencies, logging and continuing past any pair the
+// provider can't resolve rather than letting one bad pair block the rest.
+func refreshActivePairs() {
+	for _, result := range RefreshNow() {
+		if result.Error != "" {
+			log.Printf("fx: background refresh %s/%s: %s", result.Base, result.Quote, result.Error)
+		}
+	}
+}
+
+// RefreshResult is one currency pair's outcome from RefreshNow.
+type RefreshResult struct {
+	Base  string  `json:"base"`
+	Quote string  `json:"quote"`
+	Rate  float64 `json:"rate,omitempty"`
+	Error string  `json:"error,omitempty"`
+}
+
+// RefreshNow fetches and caches today's rate for every ordered pair among
+// the currencies currently in use (see activeCurrencies) - the same set
+// the background refresher loop already keeps warm on refreshInterval.
+// Exported so POST /fx/rates/refresh can force an immediate refresh
+// instead of waiting for the next tick.
+func RefreshNow() []RefreshResult {
+	currencies := activeCurrencies()
+	now := time.Now()
+
+	var results []RefreshResult
+	for _, base := range currencies {
+		for _, quote := range currencies {
+			if base == quote {
+				continue
+			}
+			result := RefreshResult{Base: base, Quote: quote}
+			rate, err := RateOn(base, quote, now)
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Rate = rate
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// activeCurrencies returns the distinct currencies in use across accounts
+// and budgets, so the refresher only warms pairs a request could actually
+// need a rate for.
+func activeCurrencies() []string {
+	var accountCurrencies []string
+	db.DB.Table("accounts").Distinct("currency").Pluck("currency", &accountCurrencies)
+
+	var budgetCurrencies []string
+	db.DB.Table("budgets").Distinct("currency").Pluck("currency", &budgetCurrencies)
+
+	seen := make(map[string]bool)
+	var currencies []string
+	for _, c := range append(accountCurrencies, budgetCurrencies...) {
+		if c != "" && !seen[c] {
+			seen[c] = true
+			currencies = append(currencies, c)
+		}
+	}
+	return currencies
+}
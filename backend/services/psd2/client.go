@@ -0,0 +1,289 @@
+// Package psd2 implements the Berlin Group NextGenPSD2 XS2A consent and
+// account information flow against the two banks this integration supports.
+// Both endpoints are fixed per bank, not discovered or configured per
+// deployment - see Providers.
+package psd2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Client talks to one bank's XS2A API over HTTP.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a Client for baseURL with a bounded request timeout;
+// the XS2A endpoints are third-party and must not be allowed to hang a
+// request indefinitely.
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// ConsentResult is what initiating a consent gives the caller back: the
+// resource ID to poll/reference later, the SCA redirect URL to send the PSU
+// to, and the consent's initial status ("received" until SCA completes).
+type ConsentResult struct {
+	ConsentID   string
+	RedirectURL string
+	Status      string
+}
+
+type consentAccess struct {
+	Accounts     []string `json:"accounts"`
+	Balances     []string `json:"balances"`
+	Transactions []string `json:"transactions"`
+}
+
+type consentRequestBody struct {
+	Access                   consentAccess `json:"access"`
+	RecurringIndicator       bool          `json:"recurringIndicator"`
+	ValidUntil               string        `json:"validUntil"`
+	FrequencyPerDay          int           `json:"frequencyPerDay"`
+	CombinedServiceIndicator bool          `json:"combinedServiceIndicator"`
+}
+
+type consentResponseBody struct {
+	ConsentID     string `json:"consentId"`
+	ConsentStatus string `json:"consentStatus"`
+	Links         struct {
+		ScaRedirect struct {
+			Href string `json:"href"`
+		} `json:"scaRedirect"`
+	} `json:"_links"`
+}
+
+// ConsentOptions are the Berlin Group consent-request fields a caller can
+// tune per request, rather than InitiateConsent hardcoding them:
+// FrequencyPerDay (how many times a day the consent is re-checked once
+// valid), CombinedServiceIndicator (this consent is combined with a
+// payment initiation service), and RecurringIndicator (access is wanted
+// beyond a single pull).
+type ConsentOptions struct {
+	FrequencyPerDay          int
+	CombinedServiceIndicator bool
+	RecurringIndicator       bool
+}
+
+// AuditHeaders carries the Berlin Group regulatory fields a PSU-initiated
+// XS2A call is expected to echo back to the bank - X-Request-ID
+// (correlates a request across retries/logs) and PSU-IP-Address (the
+// end-user's originating IP) - and that controllers/psd2.go records onto
+// the resulting models.BankSyncLog row for audit. Left zero-valued, neither
+// header is sent, which is what every pre-existing caller of this package
+// does.
+type AuditHeaders struct {
+	RequestID    string
+	PSUIPAddress string
+}
+
+func (a AuditHeaders) apply(req *http.Request) {
+	if a.RequestID != "" {
+		req.Header.Set("X-Request-ID", a.RequestID)
+	}
+	if a.PSUIPAddress != "" {
+		req.Header.Set("PSU-IP-Address", a.PSUIPAddress)
+	}
+}
+
+// InitiateConsent requests account list, balances, and transactions access
+// for validUntil, re-checked frequencyPerDay times a day once valid, with
+// RecurringIndicator true and CombinedServiceIndicator false. The bank
+// replies with a consent resource plus the SCA redirect URL the PSU must
+// complete to move it from "received" to "valid". See
+// InitiateConsentWithOptions for a caller that needs those two indicators
+// configurable.
+func (c *Client) InitiateConsent(redirectURI string, validUntil time.Time, frequencyPerDay int) (ConsentResult, error) {
+	return c.InitiateConsentWithOptions(redirectURI, validUntil, ConsentOptions{
+		FrequencyPerDay:    frequencyPerDay,
+		RecurringIndicator: true,
+	}, AuditHeaders{})
+}
+
+// InitiateConsentWithOptions is InitiateConsent with every Berlin Group
+// consent-request field and audit headers under the caller's control - used
+// by controllers/psd2.go's POST /api/banks/psd2/consent, which exposes
+// opts directly from the request body.
+func (c *Client) InitiateConsentWithOptions(redirectURI string, validUntil time.Time, opts ConsentOptions, audit AuditHeaders) (ConsentResult, error) {
+	body := consentRequestBody{
+		Access: consentAccess{
+			Accounts:     []string{},
+			Balances:     []string{},
+			Transactions: []string{},
+		},
+		RecurringIndicator:       opts.RecurringIndicator,
+		ValidUntil:               validUntil.Format("2006-01-02"),
+		FrequencyPerDay:          opts.FrequencyPerDay,
+		CombinedServiceIndicator: opts.CombinedServiceIndicator,
+	}
+
+	var parsed consentResponseBody
+	if err := c.doAudited(http.MethodPost, "/v1/consents", redirectURI, body, &parsed, audit); err != nil {
+		return ConsentResult{}, err
+	}
+
+	return ConsentResult{
+		ConsentID:   parsed.ConsentID,
+		RedirectURL: parsed.Links.ScaRedirect.Href,
+		Status:      parsed.ConsentStatus,
+	}, nil
+}
+
+// ConsentStatus polls the current status of a previously-initiated consent
+// ("received", "valid", "rejected", "expired", "revokedByPsu", ...).
+func (c *Client) ConsentStatus(consentID string) (string, error) {
+	return c.ConsentStatusWithAudit(consentID, AuditHeaders{})
+}
+
+// ConsentStatusWithAudit is ConsentStatus with audit headers attached, used
+// by controllers/psd2.go's GET /api/banks/psd2/consent/:id/status.
+func (c *Client) ConsentStatusWithAudit(consentID string, audit AuditHeaders) (string, error) {
+	var parsed consentResponseBody
+	if err := c.doAudited(http.MethodGet, "/v1/consents/"+consentID+"/status", "", nil, &parsed, audit); err != nil {
+		return "", err
+	}
+	return parsed.ConsentStatus, nil
+}
+
+// Account is one bank account exposed by a valid consent.
+type Account struct {
+	ResourceID string `json:"resourceId"`
+	IBAN       string `json:"iban"`
+	Name       string `json:"name"`
+	Currency   string `json:"currency"`
+	Product    string `json:"product"`
+}
+
+type accountListResponse struct {
+	Accounts []Account `json:"accounts"`
+}
+
+// Accounts lists the accounts a valid consent grants access to.
+func (c *Client) Accounts(consentID string) ([]Account, error) {
+	return c.AccountsWithAudit(consentID, AuditHeaders{})
+}
+
+// AccountsWithAudit is Accounts with audit headers attached.
+func (c *Client) AccountsWithAudit(consentID string, audit AuditHeaders) ([]Account, error) {
+	var parsed accountListResponse
+	if err := c.doWithConsentAudited(http.MethodGet, "/v1/accounts", consentID, nil, &parsed, audit); err != nil {
+		return nil, err
+	}
+	return parsed.Accounts, nil
+}
+
+// Transaction is one booked transaction on an account.
+type Transaction struct {
+	TransactionID string `json:"transactionId"`
+	BookingDate   string `json:"bookingDate"`
+	Amount        struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	} `json:"transactionAmount"`
+	RemittanceInformationUnstructured string `json:"remittanceInformationUnstructured"`
+	CreditorName                      string `json:"creditorName"`
+	DebtorName                        string `json:"debtorName"`
+}
+
+// Counterparty returns whichever of CreditorName/DebtorName the bank
+// populated - a debit shows the creditor being paid, a credit shows the
+// debtor paying in.
+func (t Transaction) Counterparty() string {
+	if t.CreditorName != "" {
+		return t.CreditorName
+	}
+	return t.DebtorName
+}
+
+type transactionsResponse struct {
+	Transactions struct {
+		Booked []Transaction `json:"booked"`
+	} `json:"transactions"`
+}
+
+// Transactions fetches booked transactions on accountID since dateFrom,
+// under consentID.
+func (c *Client) Transactions(consentID, accountID string, dateFrom time.Time) ([]Transaction, error) {
+	return c.TransactionsWithAudit(consentID, accountID, dateFrom, AuditHeaders{})
+}
+
+// TransactionsWithAudit is Transactions with audit headers attached, used by
+// controllers/psd2.go's POST /api/banks/psd2/accounts/:id/sync.
+func (c *Client) TransactionsWithAudit(consentID, accountID string, dateFrom time.Time, audit AuditHeaders) ([]Transaction, error) {
+	path := fmt.Sprintf("/v1/accounts/%s/transactions?dateFrom=%s&bookingStatus=booked", accountID, dateFrom.Format("2006-01-02"))
+	var parsed transactionsResponse
+	if err := c.doWithConsentAudited(http.MethodGet, path, consentID, nil, &parsed, audit); err != nil {
+		return nil, err
+	}
+	return parsed.Transactions.Booked, nil
+}
+
+func (c *Client) doWithConsentAudited(method, path, consentID string, body, out interface{}, audit AuditHeaders) error {
+	req, err := c.newRequest(method, path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Consent-ID", consentID)
+	audit.apply(req)
+	return c.execute(req, out)
+}
+
+func (c *Client) doAudited(method, path, redirectURI string, body, out interface{}, audit AuditHeaders) error {
+	req, err := c.newRequest(method, path, body)
+	if err != nil {
+		return err
+	}
+	if redirectURI != "" {
+		req.Header.Set("TPP-Redirect-URI", redirectURI)
+	}
+	audit.apply(req)
+	return c.execute(req, out)
+}
+
+func (c *Client) newRequest(method, path string, body interface{}) (*http.Request, error) {
+	var reader *bytes.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("psd2: encode request body: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, c.BaseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("psd2: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+func (c *Client) execute(req *http.Request, out interface{}) error {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("psd2: %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("psd2: %s %s returned status %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("psd2: decode response from %s: %w", req.URL.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package psd2
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// EncryptionKeyEnv names the env var holding the 32-byte, base64-encoded
+// AES-256 key used to encrypt secrets (client certs, OAuth tokens) before
+// they're written to BankConnection.Metadata.
+const EncryptionKeyEnv = "PSD2_ENCRYPTION_KEY"
+
+// EncryptSecret AES-GCM encrypts plaintext with the key configured via
+// PSD2_ENCRYPTION_KEY and returns a base64-encoded nonce||ciphertext blob
+// safe to store as a Metadata JSONB string value.
+func EncryptSecret(plaintext string) (string, error) {
+	gcm, err := cipherFromEnv()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("psd2: generate nonce: %w", err)
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptSecret reverses EncryptSecret.
+func DecryptSecret(encoded string) (string, error) {
+	gcm, err := cipherFromEnv()
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.New("psd2: stored secret is not valid base64")
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(raw) < nonceSize {
+		return "", errors.New("psd2: stored secret is shorter than a nonce")
+	}
+
+	nonce, ciphertext := raw[:nonceSize], raw[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("psd2: decrypt secret: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+func cipherFromEnv() (cipher.AEAD, error) {
+	encoded := os.Getenv(EncryptionKeyEnv)
+	if encoded == "" {
+		return nil, errors.New("psd2: " + EncryptionKeyEnv + " is not set")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("psd2: " + EncryptionKeyEnv + " must be base64-encoded")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("psd2: build AES cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
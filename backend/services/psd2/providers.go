@@ -0,0 +1,36 @@
+package psd2
+
+// Provider is one bank's PSD2/Berlin Group registration: the bank_name a
+// connection or consent request names it by, and its hardcoded XS2A base
+// URL. Providers is the registry controllers/psd2.go and bank_sync.go look
+// bank names up in, so supporting a new bank means adding an entry here,
+// not touching either controller.
+type Provider struct {
+	BankName string
+	Endpoint string
+}
+
+var providerList = []Provider{
+	{BankName: "sparebanken_norge", Endpoint: "https://psd2.spvapi.no"},
+	{BankName: "bulder_bank", Endpoint: "https://psd2-bulder.spvapi.no"},
+}
+
+// Providers indexes providerList by BankName.
+var Providers = func() map[string]Provider {
+	m := make(map[string]Provider, len(providerList))
+	for _, p := range providerList {
+		m[p.BankName] = p
+	}
+	return m
+}()
+
+// BankEndpoints is the flat bank_name -> endpoint form bank_sync.go's
+// CreateBankConnection/ReauthenticateBankConnection look endpoints up in;
+// derived from Providers so the two can never drift apart.
+var BankEndpoints = func() map[string]string {
+	m := make(map[string]string, len(providerList))
+	for _, p := range providerList {
+		m[p.BankName] = p.Endpoint
+	}
+	return m
+}()
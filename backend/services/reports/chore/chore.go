@@ -0,0 +1,279 @@
+// Package chore runs models.ReportSchedule rows: every tick it claims
+// whichever schedules are due, renders their report through
+// controllers.RenderScheduledReport into CSV and PDF, and delivers both by
+// email, webhook, or local storage - tracking attempts/last error and
+// backing off exponentially on failure, the same way
+// controllers.MaterializeRecurringRule tracks a recurring rule's own
+// progress.
+package chore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/cronexpr"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/utils"
+	"Personal-Finance-Tracker-backend/utils/mailer"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// httpClient is used for webhook delivery, mirroring
+// services/anomaly.httpClient.
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// minBackoff/maxBackoff bound retryDelay's exponential backoff: a failed
+// delivery retries in 5 minutes the first time, doubling on each
+// subsequent failure up to a 6-hour ceiling.
+const (
+	minBackoff = 5 * time.Minute
+	maxBackoff = 6 * time.Hour
+)
+
+// Start launches the background goroutine that ticks every interval,
+// claiming and running whichever ReportSchedule rows are due. Safe to
+// restart the process at any time: a schedule's NextRunAt is only moved
+// forward once its delivery attempt (success or failure) has finished.
+func Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			RunDuePass()
+		}
+	}()
+}
+
+// RunDuePass claims every schedule whose NextRunAt has passed and
+// processes each one. Exported so RunScheduleNow (manual "run now" trigger)
+// and tests can drive a single pass without waiting on the ticker.
+func RunDuePass() {
+	now := time.Now()
+	for _, schedule := range claimDueSchedules(now) {
+		processSchedule(schedule, now)
+	}
+}
+
+// RunScheduleNow immediately processes one schedule regardless of its
+// NextRunAt, for controllers.RunReportScheduleNow's manual trigger.
+func RunScheduleNow(schedule models.ReportSchedule) {
+	processSchedule(schedule, time.Now())
+}
+
+// claimDueSchedules locks and returns every due ReportSchedule, pushing
+// each one's NextRunAt forward by an hour inside the same transaction that
+// locked it - SELECT ... FOR UPDATE SKIP LOCKED on Postgres, so a second
+// app instance (or a slow-running prior pass) can't grab the same row
+// mid-delivery; plain transaction isolation on SQLite, which has no SKIP
+// LOCKED syntax but - being single-writer - doesn't need one (see
+// controllers.MaterializeRecurringRule's doc comment for the same
+// Postgres/SQLite split). processSchedule overwrites this provisional
+// NextRunAt with the real cron-derived one once delivery finishes.
+func claimDueSchedules(now time.Time) []models.ReportSchedule {
+	var claimed []models.ReportSchedule
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var due []models.ReportSchedule
+		query := tx
+		if tx.Dialector.Name() == "postgres" {
+			query = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		if err := query.Where("next_run_at <= ?", now).Find(&due).Error; err != nil {
+			return err
+		}
+
+		provisional := now.Add(time.Hour)
+		for i := range due {
+			if err := tx.Model(&models.ReportSchedule{}).Where("id = ?", due[i].ID).
+				Update("next_run_at", provisional).Error; err != nil {
+				return err
+			}
+			due[i].NextRunAt = provisional
+		}
+		claimed = due
+		return nil
+	})
+	if err != nil {
+		utils.Logger.Warn("report schedule chore: failed to claim due schedules", zap.Error(err))
+		return nil
+	}
+	return claimed
+}
+
+// processSchedule renders and delivers schedule's report, then persists
+// the outcome: on success, Attempts/LastError reset and NextRunAt advances
+// to the next cron match; on failure, Attempts increments, LastError is
+// recorded, and NextRunAt becomes now+retryDelay(Attempts).
+func processSchedule(schedule models.ReportSchedule, now time.Time) {
+	err := deliverSchedule(schedule, now)
+
+	updates := map[string]interface{}{"last_run_at": now}
+	if err != nil {
+		schedule.Attempts++
+		updates["attempts"] = schedule.Attempts
+		updates["last_error"] = err.Error()
+		updates["next_run_at"] = now.Add(retryDelay(schedule.Attempts))
+		utils.Logger.Warn("report schedule chore: delivery failed",
+			zap.Uint("schedule_id", schedule.ID), zap.Int("attempts", schedule.Attempts), zap.Error(err))
+	} else {
+		updates["attempts"] = 0
+		updates["last_error"] = ""
+		updates["next_run_at"] = nextRunAt(schedule, now)
+	}
+
+	if updateErr := db.DB.Model(&models.ReportSchedule{}).Where("id = ?", schedule.ID).Updates(updates).Error; updateErr != nil {
+		utils.Logger.Error("report schedule chore: failed to persist run outcome",
+			zap.Uint("schedule_id", schedule.ID), zap.Error(updateErr))
+	}
+}
+
+// nextRunAt computes a successfully-delivered schedule's next cron match,
+// falling back to 24 hours out if its Cron or Timezone no longer parses
+// (e.g. hand-edited directly in the database).
+func nextRunAt(schedule models.ReportSchedule, now time.Time) time.Time {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	next, err := cronexpr.Next(schedule.Cron, now, loc)
+	if err != nil {
+		utils.Logger.Warn("report schedule chore: invalid cron expression, falling back to +24h",
+			zap.Uint("schedule_id", schedule.ID), zap.String("cron", schedule.Cron), zap.Error(err))
+		return now.Add(24 * time.Hour)
+	}
+	return next
+}
+
+// retryDelay doubles from minBackoff on each successive failed attempt,
+// capped at maxBackoff.
+func retryDelay(attempts int) time.Duration {
+	delay := minBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// deliverSchedule renders schedule's report (CSV always, PDF alongside it)
+// and dispatches both through schedule.DeliveryMethod.
+func deliverSchedule(schedule models.ReportSchedule, now time.Time) error {
+	render, err := controllers.RenderScheduledReport(schedule)
+	if err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+
+	csvData, err := renderCSV(render)
+	if err != nil {
+		return fmt.Errorf("render csv: %w", err)
+	}
+	pdfData := renderPDF(render)
+
+	switch schedule.DeliveryMethod {
+	case models.ReportDeliveryEmail:
+		return deliverEmail(schedule, render, csvData, pdfData)
+	case models.ReportDeliveryWebhook:
+		return deliverWebhook(schedule, render, csvData, pdfData)
+	case models.ReportDeliveryStorage:
+		return deliverStorage(schedule, render, csvData, pdfData, now)
+	default:
+		return fmt.Errorf("unsupported delivery_method: %q", schedule.DeliveryMethod)
+	}
+}
+
+// deliverEmail sends render's CSV as the primary attachment, noting the
+// PDF's availability in the body - utils/mailer.SendWithAttachment only
+// carries one attachment per message, and CSV is the more broadly useful
+// of the two for a user who wants to pull the numbers into a spreadsheet.
+func deliverEmail(schedule models.ReportSchedule, render controllers.ScheduledReportRender, csvData, pdfData []byte) error {
+	body := fmt.Sprintf("%s\n\n%s\n\nSee the attached CSV for the full data (a PDF copy is also available via GET /api/report-schedules/%d/run-now).",
+		render.Title, render.Subtitle, schedule.ID)
+	return mailer.SendWithAttachment(schedule.Target, render.Title, body, "report.csv", "text/csv", csvData)
+}
+
+// deliverWebhook POSTs render as JSON (the CSV/PDF encoded alongside it,
+// base64, so a receiving endpoint can choose which to store) to
+// schedule.Target.
+func deliverWebhook(schedule models.ReportSchedule, render controllers.ScheduledReportRender, csvData, pdfData []byte) error {
+	body, err := webhookPayloadJSON(render, csvData, pdfData)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, schedule.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("deliver: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// webhookPayloadJSON is deliverWebhook's request body: the flattened
+// report plus its CSV/PDF renderings, both base64-encoded so a plain JSON
+// body can carry binary PDF bytes.
+func webhookPayloadJSON(render controllers.ScheduledReportRender, csvData, pdfData []byte) ([]byte, error) {
+	payload := struct {
+		Title     string     `json:"title"`
+		Subtitle  string     `json:"subtitle"`
+		Headers   []string   `json:"headers"`
+		Rows      [][]string `json:"rows"`
+		CSVBase64 string     `json:"csv_base64"`
+		PDFBase64 string     `json:"pdf_base64"`
+	}{
+		Title:     render.Title,
+		Subtitle:  render.Subtitle,
+		Headers:   render.Headers,
+		Rows:      render.Rows,
+		CSVBase64: base64.StdEncoding.EncodeToString(csvData),
+		PDFBase64: base64.StdEncoding.EncodeToString(pdfData),
+	}
+	return json.Marshal(payload)
+}
+
+// deliverStorage writes render's CSV and PDF into
+// REPORT_SCHEDULE_STORAGE_DIR (default "storage/reports"), under
+// schedule.Target/<timestamp>.{csv,pdf}.
+func deliverStorage(schedule models.ReportSchedule, render controllers.ScheduledReportRender, csvData, pdfData []byte, now time.Time) error {
+	baseDir := os.Getenv("REPORT_SCHEDULE_STORAGE_DIR")
+	if baseDir == "" {
+		baseDir = "storage/reports"
+	}
+
+	dir := filepath.Join(baseDir, schedule.Target)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create storage dir: %w", err)
+	}
+
+	stamp := now.Format("20060102T150405")
+	if err := os.WriteFile(filepath.Join(dir, stamp+".csv"), csvData, 0o644); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, stamp+".pdf"), pdfData, 0o644); err != nil {
+		return fmt.Errorf("write pdf: %w", err)
+	}
+	return nil
+}
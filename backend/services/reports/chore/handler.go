@@ -0,0 +1,44 @@
+package chore
+
+import (
+	"net/http"
+	"strconv"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// RunReportScheduleNow renders and delivers one schedule's report
+// immediately, regardless of its NextRunAt, and reports the outcome - unlike
+// waiting on Start's next tick to see whether delivery succeeded. Lives here
+// rather than in controllers since it needs RunScheduleNow, and chore
+// already imports controllers for RenderScheduledReport; the reverse import
+// would be a cycle.
+func RunReportScheduleNow(c *gin.Context) {
+	claims, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	userID := uint(claims.(jwt.MapClaims)["sub"].(float64))
+	scheduleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report schedule ID"})
+		return
+	}
+
+	var schedule models.ReportSchedule
+	if err := db.DB.Where("id = ? AND user_id = ?", scheduleID, userID).First(&schedule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "report schedule not found"})
+		return
+	}
+
+	RunScheduleNow(schedule)
+
+	var updated models.ReportSchedule
+	db.DB.First(&updated, schedule.ID)
+	c.JSON(http.StatusOK, updated)
+}
@@ -0,0 +1,140 @@
+package chore
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"Personal-Finance-Tracker-backend/controllers"
+)
+
+// renderCSV flattens render into the same account_id/description/etc
+// comma-separated shape exporters.Writer produces for transaction exports
+// - here: title/subtitle as a leading comment-free header row, then the
+// column headers, then one row per render.Rows entry.
+func renderCSV(render controllers.ScheduledReportRender) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write([]string{render.Title, render.Subtitle}); err != nil {
+		return nil, err
+	}
+	if err := w.Write(render.Headers); err != nil {
+		return nil, err
+	}
+	for _, row := range render.Rows {
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pdfFontSize/pdfLineHeight/pdfLeftMargin/pdfPageHeight/pdfPageWidth lay
+// out renderPDF's single page: US Letter in PDF points (72/inch).
+const (
+	pdfFontSize   = 10
+	pdfLineHeight = 14
+	pdfLeftMargin = 50
+	pdfPageWidth  = 612
+	pdfPageHeight = 792
+	pdfTopMargin  = 740
+)
+
+// renderPDF renders render into a minimal single-page PDF: a title line, a
+// subtitle line, and a monospace-ish tabular dump of Headers/Rows, one line
+// per row. This hand-rolls the PDF byte format directly (objects, a
+// content stream of Tj text-show operators, and a byte-accurate xref
+// table) rather than pulling in jung-kurt/gofpdf or signintech/gopdf,
+// since there's no go.mod here to add either as a dependency to (see
+// controllers/digest_scheduler.go's doc comment on the same constraint
+// for cron libraries). Rows beyond what fits on one page are dropped with
+// a "... N more rows" marker rather than silently truncated.
+func renderPDF(render controllers.ScheduledReportRender) []byte {
+	lines := []string{render.Title, render.Subtitle, "", formatPDFRow(render.Headers)}
+	maxLines := (pdfTopMargin - pdfFontSize) / pdfLineHeight
+
+	shown := 0
+	for _, row := range render.Rows {
+		if len(lines)+1 >= maxLines {
+			break
+		}
+		lines = append(lines, formatPDFRow(row))
+		shown++
+	}
+	if shown < len(render.Rows) {
+		lines = append(lines, fmt.Sprintf("... %d more rows", len(render.Rows)-shown))
+	}
+
+	var content bytes.Buffer
+	content.WriteString("BT\n")
+	fmt.Fprintf(&content, "/F1 %d Tf\n", pdfFontSize)
+	y := pdfTopMargin
+	for _, line := range lines {
+		fmt.Fprintf(&content, "1 0 0 1 %d %d Tm\n", pdfLeftMargin, y)
+		fmt.Fprintf(&content, "(%s) Tj\n", escapePDFText(line))
+		y -= pdfLineHeight
+	}
+	content.WriteString("ET\n")
+
+	return assemblePDF(content.Bytes())
+}
+
+// formatPDFRow joins a row's cells with enough padding to stay roughly
+// aligned in a non-monospace PDF viewer - good enough for a report
+// attachment, not meant to replace a real table layout engine.
+func formatPDFRow(cells []string) string {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		padded[i] = fmt.Sprintf("%-18s", cell)
+	}
+	return strings.TrimRight(strings.Join(padded, " "), " ")
+}
+
+// escapePDFText escapes the three characters PDF's literal-string syntax
+// ( ... ) treats specially.
+func escapePDFText(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `(`, `\(`)
+	s = strings.ReplaceAll(s, `)`, `\)`)
+	return s
+}
+
+// assemblePDF wraps a content stream into a complete single-page PDF
+// document: catalog, pages tree, one page (Letter-sized, Helvetica),
+// the content stream itself, and a byte-accurate xref table.
+func assemblePDF(content []byte) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+
+	buf.WriteString("%PDF-1.4\n")
+
+	writeObj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		buf.WriteString(body)
+	}
+
+	writeObj(fmt.Sprintf("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n"))
+	writeObj(fmt.Sprintf("2 0 obj\n<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n"))
+	writeObj(fmt.Sprintf(
+		"3 0 obj\n<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>\nendobj\n",
+		pdfPageWidth, pdfPageHeight))
+	writeObj("4 0 obj\n<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>\nendobj\n")
+	writeObj(fmt.Sprintf("5 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(content), content))
+
+	xrefStart := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(offsets)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", len(offsets)+1, xrefStart)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,216 @@
+// Package reports executes user-authored Lua scripts (see models.Report)
+// inside a sandboxed gopher-lua VM to produce a Tabulation - a custom,
+// multi-series chart the built-in report endpoints
+// (controllers.GetSpendSummary/GetCashflow/GetBudgetProgress) don't cover.
+// Borrows moneygo's approach: the script only sees the authenticated user's
+// own data through a handful of read-only functions and can only shape
+// output through a handful of write-only ones, with no io/os/package
+// library loaded into the VM at all.
+package reports
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ScriptTimeout bounds how long a single script may run before its
+// context is canceled - gopher-lua checks ctx.Done() between VM
+// instructions (see (*lua.LState).SetContext), so this is the closest
+// thing to a CPU ceiling a pure-Lua sandbox gets.
+const ScriptTimeout = 5 * time.Second
+
+// maxRegistrySize bounds the VM's value stack; gopher-lua has no separate
+// heap-size knob, so this is the closest thing to a memory ceiling.
+const maxRegistrySize = 1 << 16
+
+// Tabulation is the multi-series, multi-period numeric grid a report
+// script produces: Labels names each column ("2026-01", "Mon", ...), and
+// each Series entry is one named row of values aligned to those labels, in
+// Units. Matches the shape GetMonthlyTrends/GetCashflow already return, so
+// the frontend renders a custom report through the same chart component as
+// a built-in one.
+type Tabulation struct {
+	Title    string             `json:"title"`
+	Subtitle string             `json:"subtitle"`
+	Units    string             `json:"units"`
+	Labels   []string           `json:"labels"`
+	Series   map[string][]int64 `json:"series"`
+}
+
+// Service runs report scripts. It holds no state of its own - every field
+// a script can see or set lives on the *runState built fresh for each
+// Run call - but exists as a type (rather than package-level functions)
+// for the same reason services/ai.Service does: a future transport can
+// depend on an interface instead of this package directly.
+type Service struct{}
+
+// NewService returns a Service ready to run scripts.
+func NewService() *Service {
+	return &Service{}
+}
+
+// runState is the data one Run call's Lua functions close over: userID
+// scopes every query, and tab accumulates what the script writes via
+// title/subtitle/units/labels/series.
+type runState struct {
+	userID uint
+	tab    Tabulation
+}
+
+// Run executes luaSource as userID's sandboxed report script, within
+// ScriptTimeout, and returns the Tabulation it produced.
+func (s *Service) Run(ctx context.Context, userID uint, luaSource string) (Tabulation, error) {
+	ctx, cancel := context.WithTimeout(ctx, ScriptTimeout)
+	defer cancel()
+
+	state := &runState{userID: userID, tab: Tabulation{Series: map[string][]int64{}}}
+
+	L := lua.NewState(lua.Options{
+		SkipOpenLibs:        true,
+		RegistrySize:        maxRegistrySize,
+		RegistryMaxSize:     maxRegistrySize,
+		IncludeGoStackTrace: false,
+	})
+	defer L.Close()
+	L.SetContext(ctx)
+
+	// Only Base/Table/String/Math are opened - no io, os, or package/
+	// require, so a script has no way to touch the filesystem, the
+	// network, or the rest of the process.
+	for _, lib := range []struct {
+		name string
+		fn   lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		if err := L.CallByParam(lua.P{Fn: L.NewFunction(lib.fn), NRet: 0, Protect: true}, lua.LString(lib.name)); err != nil {
+			return Tabulation{}, fmt.Errorf("reports: open %s library: %w", lib.name, err)
+		}
+	}
+
+	registerAPI(L, state)
+
+	if err := L.DoString(luaSource); err != nil {
+		return Tabulation{}, fmt.Errorf("reports: script error: %w", err)
+	}
+
+	return state.tab, nil
+}
+
+// registerAPI installs the sandboxed globals a report script runs
+// against: categories/accounts/transactions to read state.userID's own
+// data, and title/subtitle/units/labels/series to build state.tab.
+func registerAPI(L *lua.LState, state *runState) {
+	L.SetGlobal("categories", L.NewFunction(func(L *lua.LState) int {
+		var categories []models.Category
+		db.DB.Where("user_id = ?", state.userID).Find(&categories)
+
+		result := L.NewTable()
+		for _, category := range categories {
+			row := L.NewTable()
+			row.RawSetString("id", lua.LNumber(category.ID))
+			row.RawSetString("name", lua.LString(category.Name))
+			row.RawSetString("kind", lua.LString(category.Kind))
+			result.Append(row)
+		}
+		L.Push(result)
+		return 1
+	}))
+
+	L.SetGlobal("accounts", L.NewFunction(func(L *lua.LState) int {
+		var accounts []models.Account
+		db.DB.Where("user_id = ?", state.userID).Find(&accounts)
+
+		result := L.NewTable()
+		for _, account := range accounts {
+			row := L.NewTable()
+			row.RawSetString("id", lua.LNumber(account.ID))
+			row.RawSetString("name", lua.LString(account.Name))
+			row.RawSetString("type", lua.LString(account.Type))
+			row.RawSetString("currency", lua.LString(account.Currency))
+			result.Append(row)
+		}
+		L.Push(result)
+		return 1
+	}))
+
+	// transactions([from], [to]) returns state.userID's transactions, both
+	// args optional "YYYY-MM-DD" date strings.
+	L.SetGlobal("transactions", L.NewFunction(func(L *lua.LState) int {
+		from := L.OptString(1, "")
+		to := L.OptString(2, "")
+
+		query := db.DB.Where("user_id = ?", state.userID)
+		if from != "" {
+			query = query.Where("txn_date >= ?", from)
+		}
+		if to != "" {
+			query = query.Where("txn_date <= ?", to)
+		}
+
+		var transactions []models.Transaction
+		query.Find(&transactions)
+
+		result := L.NewTable()
+		for _, txn := range transactions {
+			row := L.NewTable()
+			row.RawSetString("id", lua.LNumber(txn.ID))
+			row.RawSetString("account_id", lua.LNumber(txn.AccountID))
+			if txn.CategoryID != nil {
+				row.RawSetString("category_id", lua.LNumber(*txn.CategoryID))
+			}
+			amount, _ := txn.Amount.Float64()
+			row.RawSetString("amount", lua.LNumber(amount))
+			row.RawSetString("description", lua.LString(txn.Description))
+			row.RawSetString("txn_date", lua.LString(txn.TxnDate.Format("2006-01-02")))
+			result.Append(row)
+		}
+		L.Push(result)
+		return 1
+	}))
+
+	L.SetGlobal("title", L.NewFunction(func(L *lua.LState) int {
+		state.tab.Title = L.CheckString(1)
+		return 0
+	}))
+	L.SetGlobal("subtitle", L.NewFunction(func(L *lua.LState) int {
+		state.tab.Subtitle = L.CheckString(1)
+		return 0
+	}))
+	L.SetGlobal("units", L.NewFunction(func(L *lua.LState) int {
+		state.tab.Units = L.CheckString(1)
+		return 0
+	}))
+	L.SetGlobal("labels", L.NewFunction(func(L *lua.LState) int {
+		values := L.CheckTable(1)
+		var labels []string
+		values.ForEach(func(_, value lua.LValue) {
+			labels = append(labels, value.String())
+		})
+		state.tab.Labels = labels
+		return 0
+	}))
+	L.SetGlobal("series", L.NewFunction(func(L *lua.LState) int {
+		name := L.CheckString(1)
+		values := L.CheckTable(2)
+		var series []int64
+		values.ForEach(func(_, value lua.LValue) {
+			if number, ok := value.(lua.LNumber); ok {
+				series = append(series, int64(number))
+			} else {
+				series = append(series, 0)
+			}
+		})
+		state.tab.Series[name] = series
+		return 0
+	}))
+}
@@ -0,0 +1,213 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// UserAggregateCounts is the per-user resource tally GetUserDetails reports
+// alongside the user record.
+type UserAggregateCounts struct {
+	Accounts     int64
+	Transactions int64
+	Categories   int64
+	Budgets      int64
+}
+
+// BudgetPeriod is the slice of a budget GetBudgetSpentTotals needs to
+// attribute spend to it: whose transactions count, and over what range.
+type BudgetPeriod struct {
+	ID          uint
+	UserID      uint
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+}
+
+// CategorySpend is one category's transaction activity within a budget
+// period, as rolled up by GetCategorySpendAggregates.
+type CategorySpend struct {
+	SpentCents       int64
+	TransactionCount int64
+}
+
+// AdminStore wraps the admin-only aggregate queries that used to call db.DB
+// directly, the same way Store does for the regular transaction endpoints.
+// Handlers move behind this interface incrementally rather than all at
+// once - GetUserDetails first, with the raw-SQL admin listings
+// (GetAllTransactions/GetAllAccounts/GetAllCategories) to follow.
+type AdminStore interface {
+	// GetUserAggregateCounts replaces GetUserDetails' four separate COUNT
+	// queries with one struct so the handler can stay a single round trip to
+	// the store.
+	GetUserAggregateCounts(ctx context.Context, userID uint) (UserAggregateCounts, error)
+
+	// GetBudgetPlannedTotals replaces GetAllBudgets' per-budget
+	// SUM(planned_cents) query with one GROUP BY over the page's budget IDs.
+	GetBudgetPlannedTotals(ctx context.Context, budgetIDs []uint) (map[uint]int64, error)
+
+	// GetBudgetSpentTotals replaces GetAllBudgets' per-budget SUM over
+	// transactions with a single query grouped by user and month, rolled up
+	// per budget in memory against each budget's own period.
+	GetBudgetSpentTotals(ctx context.Context, budgets []BudgetPeriod) (map[uint]int64, error)
+
+	// GetCategorySpendAggregates replaces GetBudgetDetails' per-item
+	// COUNT+SUM queries with one GROUP BY over the budget's category IDs.
+	GetCategorySpendAggregates(ctx context.Context, userID uint, categoryIDs []uint, periodStart, periodEnd time.Time) (map[uint]CategorySpend, error)
+}
+
+func (s *GormStore) GetUserAggregateCounts(ctx context.Context, userID uint) (UserAggregateCounts, error) {
+	db := s.db.WithContext(ctx)
+
+	var counts UserAggregateCounts
+	err := db.Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM accounts WHERE user_id = ?) AS accounts,
+			(SELECT COUNT(*) FROM transactions WHERE user_id = ?) AS transactions,
+			(SELECT COUNT(*) FROM categories WHERE user_id = ?) AS categories,
+			(SELECT COUNT(*) FROM budgets WHERE user_id = ?) AS budgets
+	`, userID, userID, userID, userID).Scan(&counts).Error
+	if err != nil {
+		return UserAggregateCounts{}, err
+	}
+	return counts, nil
+}
+
+func (s *GormStore) GetBudgetPlannedTotals(ctx context.Context, budgetIDs []uint) (map[uint]int64, error) {
+	totals := make(map[uint]int64, len(budgetIDs))
+	if len(budgetIDs) == 0 {
+		return totals, nil
+	}
+
+	var rows []struct {
+		BudgetID uint
+		Total    int64
+	}
+	err := s.db.WithContext(ctx).Table("budget_items").
+		Select("budget_id, COALESCE(SUM(planned_cents), 0) as total").
+		Where("budget_id IN ?", budgetIDs).
+		Group("budget_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		totals[row.BudgetID] = row.Total
+	}
+	return totals, nil
+}
+
+func (s *GormStore) GetBudgetSpentTotals(ctx context.Context, budgets []BudgetPeriod) (map[uint]int64, error) {
+	totals := make(map[uint]int64, len(budgets))
+	if len(budgets) == 0 {
+		return totals, nil
+	}
+
+	userIDs := make(map[uint]struct{}, len(budgets))
+	minStart, maxEnd := budgets[0].PeriodStart, budgets[0].PeriodEnd
+	for _, b := range budgets {
+		userIDs[b.UserID] = struct{}{}
+		if b.PeriodStart.Before(minStart) {
+			minStart = b.PeriodStart
+		}
+		if b.PeriodEnd.After(maxEnd) {
+			maxEnd = b.PeriodEnd
+		}
+	}
+	ids := make([]uint, 0, len(userIDs))
+	for id := range userIDs {
+		ids = append(ids, id)
+	}
+
+	db := s.db.WithContext(ctx)
+	var rows []struct {
+		UserID uint
+		Month  string
+		Total  decimal.Decimal
+	}
+	monthExpr := monthGroupExpr(db, "txn_date")
+	err := db.Table("transactions").
+		Select(monthExpr+" as month, user_id, COALESCE(SUM(ABS(amount)), 0) as total").
+		Where("user_id IN ? AND txn_date >= ? AND txn_date <= ? AND amount < 0", ids, minStart, maxEnd).
+		Group("user_id, month").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	monthly := make(map[uint]map[string]int64, len(userIDs))
+	for _, row := range rows {
+		if monthly[row.UserID] == nil {
+			monthly[row.UserID] = make(map[string]int64)
+		}
+		monthly[row.UserID][row.Month] = centsOfDecimal(row.Total)
+	}
+
+	for _, b := range budgets {
+		var total int64
+		for _, month := range monthsBetween(b.PeriodStart, b.PeriodEnd) {
+			total += monthly[b.UserID][month]
+		}
+		totals[b.ID] = total
+	}
+	return totals, nil
+}
+
+func (s *GormStore) GetCategorySpendAggregates(ctx context.Context, userID uint, categoryIDs []uint, periodStart, periodEnd time.Time) (map[uint]CategorySpend, error) {
+	aggregates := make(map[uint]CategorySpend, len(categoryIDs))
+	if len(categoryIDs) == 0 {
+		return aggregates, nil
+	}
+
+	var rows []struct {
+		CategoryID uint
+		Spent      decimal.Decimal
+		TxnCount   int64
+	}
+	err := s.db.WithContext(ctx).Table("transactions").
+		Select("category_id, COALESCE(SUM(ABS(amount)), 0) as spent, COUNT(*) as txn_count").
+		Where("user_id = ? AND category_id IN ? AND txn_date >= ? AND txn_date <= ? AND amount < 0", userID, categoryIDs, periodStart, periodEnd).
+		Group("category_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		aggregates[row.CategoryID] = CategorySpend{SpentCents: centsOfDecimal(row.Spent), TransactionCount: row.TxnCount}
+	}
+	return aggregates, nil
+}
+
+// centsOfDecimal converts a decimal money amount into the integer cents
+// these admin aggregate APIs have always returned, so the switch to
+// decimal-backed storage doesn't change their response shape.
+func centsOfDecimal(amount decimal.Decimal) int64 {
+	return amount.Mul(decimal.NewFromInt(100)).Round(0).IntPart()
+}
+
+// monthGroupExpr returns the driver-appropriate SQL expression that
+// truncates a timestamp column to its "YYYY-MM" month bucket, mirroring
+// controllers.monthGroupExpr.
+func monthGroupExpr(db *gorm.DB, column string) string {
+	if db.Dialector.Name() == "sqlite" {
+		return "STRFTIME('%Y-%m', " + column + ")"
+	}
+	return "TO_CHAR(DATE_TRUNC('month', " + column + "), 'YYYY-MM')"
+}
+
+// monthsBetween enumerates the "YYYY-MM" buckets a [start, end] period
+// spans, inclusive, so a budget's spend can be rolled up from the
+// per-user-per-month totals GetBudgetSpentTotals computes once for every
+// budget on the page.
+func monthsBetween(start, end time.Time) []string {
+	var months []string
+	cur := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, start.Location())
+	last := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, end.Location())
+	for !cur.After(last) {
+		months = append(months, cur.Format("2006-01"))
+		cur = cur.AddDate(0, 1, 0)
+	}
+	return months
+}
@@ -0,0 +1,172 @@
+// Package budgets decouples budget-progress aggregation from GORM behind a
+// small Store interface, the same way the top-level store package does for
+// transactions.
+package budgets
+
+import (
+	"context"
+	"time"
+
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ItemProgress is one BudgetItem's spend for its budget's period, as served
+// by ComputeProgress.
+type ItemProgress struct {
+	BudgetItemID uint
+	CategoryID   uint
+	SpentCents   int64
+}
+
+// BudgetStore wraps the spend-aggregation query GetBudgets/GetBudget need,
+// so they stop running a pair of per-item subqueries (direct transactions
+// plus transaction_splits) on every request.
+type BudgetStore interface {
+	// ComputeProgress returns every one of budgetID's BudgetItems with its
+	// SpentCents for the budget's period. A same-day BudgetItemProgress
+	// cache row (see the GORM hooks in models/budget_item_progress.go that
+	// invalidate it) is served as-is; anything not cached is recomputed in
+	// one grouped query and written back for the next call.
+	ComputeProgress(ctx context.Context, userID, budgetID uint) ([]ItemProgress, error)
+}
+
+// GormBudgetStore is the production BudgetStore backed by GORM.
+type GormBudgetStore struct {
+	db *gorm.DB
+}
+
+// NewGormBudgetStore wraps an existing *gorm.DB, the same per-request
+// pattern as store.NewGormStore.
+func NewGormBudgetStore(db *gorm.DB) *GormBudgetStore {
+	return &GormBudgetStore{db: db}
+}
+
+func (s *GormBudgetStore) ComputeProgress(ctx context.Context, userID, budgetID uint) ([]ItemProgress, error) {
+	db := s.db.WithContext(ctx)
+
+	var budget models.Budget
+	if err := db.Where("id = ? AND user_id = ?", budgetID, userID).First(&budget).Error; err != nil {
+		return nil, err
+	}
+
+	var items []models.BudgetItem
+	if err := db.Where("budget_id = ?", budgetID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	asOf := time.Now().Truncate(24 * time.Hour)
+	itemIDs := make([]uint, len(items))
+	for i, item := range items {
+		itemIDs[i] = item.ID
+	}
+
+	var cached []models.BudgetItemProgress
+	if err := db.Where("budget_item_id IN ? AND as_of = ?", itemIDs, asOf).Find(&cached).Error; err != nil {
+		return nil, err
+	}
+	spentByItem := make(map[uint]int64, len(items))
+	for _, c := range cached {
+		spentByItem[c.BudgetItemID] = c.SpentCents
+	}
+
+	var missing []models.BudgetItem
+	for _, item := range items {
+		if _, ok := spentByItem[item.ID]; !ok {
+			missing = append(missing, item)
+		}
+	}
+
+	if len(missing) > 0 {
+		fresh, err := computeSpentCents(db, userID, missing, budget.PeriodStart, budget.PeriodEnd)
+		if err != nil {
+			return nil, err
+		}
+		for itemID, cents := range fresh {
+			spentByItem[itemID] = cents
+
+			var row models.BudgetItemProgress
+			found := db.Where("budget_item_id = ? AND as_of = ?", itemID, asOf).First(&row).Error == nil
+
+			row.BudgetItemID = itemID
+			row.AsOf = asOf
+			row.SpentCents = cents
+
+			if found {
+				if err := db.Save(&row).Error; err != nil {
+					return nil, err
+				}
+			} else if err := db.Create(&row).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	progress := make([]ItemProgress, 0, len(items))
+	for _, item := range items {
+		progress = append(progress, ItemProgress{
+			BudgetItemID: item.ID,
+			CategoryID:   item.CategoryID,
+			SpentCents:   spentByItem[item.ID],
+		})
+	}
+	return progress, nil
+}
+
+// categorySpendRow is one category's raw decimal spend total, before
+// conversion to cents.
+type categorySpendRow struct {
+	CategoryID uint
+	Spent      decimal.Decimal
+}
+
+// computeSpentCents issues one grouped query - a UNION of direct
+// transactions and transaction_splits rows, summed by category_id - for
+// every item in items, instead of a separate pair of subqueries per item.
+func computeSpentCents(db *gorm.DB, userID uint, items []models.BudgetItem, periodStart, periodEnd time.Time) (map[uint]int64, error) {
+	categoryIDs := make([]uint, len(items))
+	itemByCategory := make(map[uint]uint, len(items))
+	for i, item := range items {
+		categoryIDs[i] = item.CategoryID
+		itemByCategory[item.CategoryID] = item.ID
+	}
+
+	var rows []categorySpendRow
+	err := db.Raw(`
+		SELECT category_id, COALESCE(SUM(ABS(amount)), 0) AS spent
+		FROM (
+			SELECT category_id, amount
+			FROM transactions
+			WHERE user_id = ? AND category_id IN ? AND txn_date >= ? AND txn_date <= ? AND amount < 0
+			UNION ALL
+			SELECT transaction_splits.category_id, transaction_splits.amount
+			FROM transaction_splits
+			JOIN transactions ON transactions.id = transaction_splits.parent_txn_id
+			WHERE transactions.user_id = ? AND transaction_splits.category_id IN ? AND transactions.txn_date >= ? AND transactions.txn_date <= ? AND transaction_splits.amount < 0
+		) combined
+		GROUP BY category_id`,
+		userID, categoryIDs, periodStart, periodEnd,
+		userID, categoryIDs, periodStart, periodEnd,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	spentByItem := make(map[uint]int64, len(items))
+	for _, item := range items {
+		spentByItem[item.ID] = 0
+	}
+	for _, row := range rows {
+		itemID, ok := itemByCategory[row.CategoryID]
+		if !ok {
+			continue
+		}
+		spentByItem[itemID] = row.Spent.Mul(decimal.NewFromInt(100)).Round(0).IntPart()
+	}
+	return spentByItem, nil
+}
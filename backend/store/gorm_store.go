@@ -0,0 +1,415 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// GormStore is the production Store backed by GORM.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore wraps an existing *gorm.DB. Controllers should keep reading
+// db.DB at request time (it is reassigned for tests) rather than capturing it
+// once at startup, so NewGormStore is usually called per-request.
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db}
+}
+
+// RecalculateBalance recomputes Account.CurrentBalance from scratch as
+// InitialBalance + sum(ordinary Transaction/TransactionSplit rows) +
+// sum(standalone LedgerEntry rows), rather than trusting any running total
+// a write path maintained itself. Every balance-affecting path - ordinary
+// transaction create/update/delete, CSV import, recurring-rule
+// materialization, and account-to-account transfers - calls this same
+// function so they all converge on the same number regardless of write
+// order, instead of each recomputing its own partial view and clobbering
+// the others' effect on the next write.
+//
+// A LedgerEntry with a TxnID is a transfer that was merged into an
+// ordinary Transaction (see plaid_api.mergePlaidTransferPair) and is
+// already counted via that Transaction row above, so only TxnID IS NULL
+// rows - the standalone postings CreateTransfer/DeleteTransfer make - are
+// added here; counting both would double-count the merged ones.
+func RecalculateBalance(tx *gorm.DB, accountID uint) error {
+	var account models.Account
+	if err := tx.First(&account, accountID).Error; err != nil {
+		return err
+	}
+
+	// Voided transactions never happened as far as the ledger is concerned,
+	// so they're excluded here even though GetTransactions still returns them
+	// (filterable via ?status=voided) for audit purposes.
+	var total decimal.NullDecimal
+	tx.Model(&models.Transaction{}).
+		Where("account_id = ? AND status <> ?", accountID, models.TransactionVoided).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total)
+
+	// Splits that carry their own AccountID are a second ledger leg on a
+	// different account (e.g. the cash side of an ATM withdrawal), so they
+	// count towards that account's balance too. Splits with no AccountID are
+	// pure category breakdowns of the parent's own amount, already counted
+	// above, and must not be double-counted here.
+	var splitTotal decimal.NullDecimal
+	tx.Model(&models.TransactionSplit{}).
+		Joins("JOIN transactions ON transactions.id = transaction_splits.parent_txn_id").
+		Where("transaction_splits.account_id = ? AND transactions.status <> ?", accountID, models.TransactionVoided).
+		Select("COALESCE(SUM(transaction_splits.amount), 0)").
+		Scan(&splitTotal)
+
+	var ledgerCentsTotal sql.NullInt64
+	tx.Model(&models.LedgerEntry{}).
+		Where("account_id = ? AND txn_id IS NULL", accountID).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Scan(&ledgerCentsTotal)
+	ledgerTotal := decimal.NewFromInt(ledgerCentsTotal.Int64).Div(decimal.NewFromInt(100))
+
+	newBalance := decimal.NewFromInt(account.InitialBalanceCents).Div(decimal.NewFromInt(100)).
+		Add(total.Decimal).Add(splitTotal.Decimal).Add(ledgerTotal)
+	return tx.Model(&account).Update("current_balance", newBalance).Error
+}
+
+func (s *GormStore) CreateTransactionTx(ctx context.Context, arg CreateTransactionArg) (models.Transaction, error) {
+	splitKind := arg.SplitKind
+	if splitKind == "" {
+		splitKind = models.TransactionSplitIndividual
+	}
+
+	transaction := models.Transaction{
+		UserID:        arg.UserID,
+		AccountID:     arg.AccountID,
+		CategoryID:    arg.CategoryID,
+		Amount:        arg.Amount,
+		Description:   arg.Description,
+		TxnDate:       arg.TxnDate,
+		Notes:         arg.Notes,
+		BudgetGroupID: arg.BudgetGroupID,
+		SplitKind:     splitKind,
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&transaction).Error; err != nil {
+			return err
+		}
+
+		accountsToRecalc := map[uint]bool{arg.AccountID: true}
+		for i := range arg.Splits {
+			arg.Splits[i].ParentTxnID = transaction.ID
+			if err := tx.Create(&arg.Splits[i]).Error; err != nil {
+				return err
+			}
+			if arg.Splits[i].AccountID != nil {
+				accountsToRecalc[*arg.Splits[i].AccountID] = true
+			}
+		}
+		transaction.Splits = arg.Splits
+
+		for accountID := range accountsToRecalc {
+			if err := RecalculateBalance(tx, accountID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	s.db.WithContext(ctx).Preload("Account").Preload("Category").Preload("Splits.Category").First(&transaction, transaction.ID)
+	return transaction, nil
+}
+
+func (s *GormStore) GetTransactions(ctx context.Context, userID uint, filters TransactionFilters) ([]models.Transaction, error) {
+	query := s.db.WithContext(ctx).Where("user_id = ?", userID)
+
+	if filters.AccountID != "" {
+		query = query.Where("account_id = ?", filters.AccountID)
+	}
+	if filters.CategoryID != "" {
+		query = query.Where("category_id = ?", filters.CategoryID)
+	}
+	if filters.From != nil {
+		query = query.Where("txn_date >= ?", *filters.From)
+	}
+	if filters.To != nil {
+		query = query.Where("txn_date <= ?", *filters.To)
+	}
+	if filters.MinAmount != nil {
+		query = query.Where("amount >= ?", *filters.MinAmount)
+	}
+	if filters.MaxAmount != nil {
+		query = query.Where("amount <= ?", *filters.MaxAmount)
+	}
+	if filters.Search != "" {
+		query = query.Where("description ILIKE ? OR notes ILIKE ?", "%"+filters.Search+"%", "%"+filters.Search+"%")
+	}
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.CursorTxnDate != nil && filters.CursorID != nil {
+		query = query.Where("(txn_date < ?) OR (txn_date = ? AND id < ?)",
+			*filters.CursorTxnDate, *filters.CursorTxnDate, *filters.CursorID)
+	}
+	if !filters.IncludeNotes {
+		query = query.Omit("notes")
+	}
+
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = 51 // defaultPageLimit (50) + 1 lookahead row, matching controllers.ParseCursor's default
+	}
+
+	var transactions []models.Transaction
+	err := query.
+		Order("txn_date DESC, id DESC").
+		Limit(limit).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachTransactionAssociations(ctx, transactions, filters.IncludeSplits); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// attachTransactionAssociations fills in Account/Category (and, if
+// includeSplits, Splits.Category) for a page of transactions with exactly
+// one IN (...) query per association, so cost is bounded by the number of
+// distinct accounts/categories touched rather than by page size - unlike
+// GORM's own Preload, which re-issues a query per preload per call and
+// doesn't let a caller skip the splits join entirely.
+func (s *GormStore) attachTransactionAssociations(ctx context.Context, transactions []models.Transaction, includeSplits bool) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+	db := s.db.WithContext(ctx)
+
+	accountIDs := map[uint]bool{}
+	categoryIDs := map[uint]bool{}
+	for _, t := range transactions {
+		accountIDs[t.AccountID] = true
+		if t.CategoryID != nil {
+			categoryIDs[*t.CategoryID] = true
+		}
+	}
+
+	var accounts []models.Account
+	if err := db.Where("id IN ?", uintKeys(accountIDs)).Find(&accounts).Error; err != nil {
+		return err
+	}
+	accountByID := make(map[uint]models.Account, len(accounts))
+	for _, a := range accounts {
+		accountByID[a.ID] = a
+	}
+
+	categoryByID := map[uint]models.Category{}
+	if len(categoryIDs) > 0 {
+		var categories []models.Category
+		if err := db.Where("id IN ?", uintKeys(categoryIDs)).Find(&categories).Error; err != nil {
+			return err
+		}
+		for _, cat := range categories {
+			categoryByID[cat.ID] = cat
+		}
+	}
+
+	splitsByParent := map[uint][]models.TransactionSplit{}
+	if includeSplits {
+		txnIDs := make([]uint, len(transactions))
+		for i, t := range transactions {
+			txnIDs[i] = t.ID
+		}
+
+		var splits []models.TransactionSplit
+		if err := db.Where("parent_txn_id IN ?", txnIDs).Find(&splits).Error; err != nil {
+			return err
+		}
+
+		splitCategoryIDs := map[uint]bool{}
+		for _, sp := range splits {
+			splitCategoryIDs[sp.CategoryID] = true
+		}
+		splitCategoryByID := map[uint]models.Category{}
+		if len(splitCategoryIDs) > 0 {
+			var splitCategories []models.Category
+			if err := db.Where("id IN ?", uintKeys(splitCategoryIDs)).Find(&splitCategories).Error; err != nil {
+				return err
+			}
+			for _, cat := range splitCategories {
+				splitCategoryByID[cat.ID] = cat
+			}
+		}
+
+		for i := range splits {
+			splits[i].Category = splitCategoryByID[splits[i].CategoryID]
+			splitsByParent[splits[i].ParentTxnID] = append(splitsByParent[splits[i].ParentTxnID], splits[i])
+		}
+	}
+
+	for i := range transactions {
+		transactions[i].Account = accountByID[transactions[i].AccountID]
+		if transactions[i].CategoryID != nil {
+			if cat, ok := categoryByID[*transactions[i].CategoryID]; ok {
+				transactions[i].Category = &cat
+			}
+		}
+		if includeSplits {
+			transactions[i].Splits = splitsByParent[transactions[i].ID]
+		}
+	}
+	return nil
+}
+
+func uintKeys(m map[uint]bool) []uint {
+	keys := make([]uint, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (s *GormStore) GetTransaction(ctx context.Context, userID uint, id uint) (models.Transaction, error) {
+	var transaction models.Transaction
+	err := s.db.WithContext(ctx).
+		Preload("Account").
+		Preload("Category").
+		Preload("Splits.Category").
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&transaction).Error
+	return transaction, err
+}
+
+func (s *GormStore) UpdateTransactionTx(ctx context.Context, arg UpdateTransactionArg) (models.Transaction, error) {
+	var transaction models.Transaction
+
+	accountsToRecalc := map[uint]bool{}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND user_id = ?", arg.ID, arg.UserID).First(&transaction).Error; err != nil {
+			return err
+		}
+		accountsToRecalc[transaction.AccountID] = true
+
+		var splits []models.TransactionSplit
+		if err := tx.Where("parent_txn_id = ?", transaction.ID).Find(&splits).Error; err != nil {
+			return err
+		}
+		for _, split := range splits {
+			if split.AccountID != nil {
+				accountsToRecalc[*split.AccountID] = true
+			}
+		}
+
+		if arg.AccountID != 0 {
+			transaction.AccountID = arg.AccountID
+			accountsToRecalc[arg.AccountID] = true
+		}
+		transaction.CategoryID = arg.CategoryID
+		if !arg.Amount.IsZero() {
+			transaction.Amount = arg.Amount
+		}
+		if arg.Description != "" {
+			transaction.Description = arg.Description
+		}
+		if arg.TxnDate != nil {
+			transaction.TxnDate = *arg.TxnDate
+		}
+		if arg.Notes != "" {
+			transaction.Notes = arg.Notes
+		}
+
+		if err := tx.Save(&transaction).Error; err != nil {
+			return err
+		}
+
+		for accountID := range accountsToRecalc {
+			if err := RecalculateBalance(tx, accountID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	s.db.WithContext(ctx).Preload("Account").Preload("Category").Preload("Splits.Category").First(&transaction, transaction.ID)
+	return transaction, nil
+}
+
+func (s *GormStore) DeleteTransactionTx(ctx context.Context, userID uint, id uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var transaction models.Transaction
+		if err := tx.Where("id = ? AND user_id = ?", id, userID).First(&transaction).Error; err != nil {
+			return err
+		}
+
+		var splits []models.TransactionSplit
+		if err := tx.Where("parent_txn_id = ?", id).Find(&splits).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("parent_txn_id = ?", id).Delete(&models.TransactionSplit{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&transaction).Error; err != nil {
+			return err
+		}
+
+		accountsToRecalc := map[uint]bool{transaction.AccountID: true}
+		for _, split := range splits {
+			if split.AccountID != nil {
+				accountsToRecalc[*split.AccountID] = true
+			}
+		}
+		for accountID := range accountsToRecalc {
+			if err := RecalculateBalance(tx, accountID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *GormStore) UpdateTransactionStatusTx(ctx context.Context, arg UpdateTransactionStatusArg) (models.Transaction, error) {
+	var transaction models.Transaction
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND user_id = ?", arg.ID, arg.UserID).First(&transaction).Error; err != nil {
+			return err
+		}
+
+		wasVoided := transaction.Status == models.TransactionVoided
+		if err := tx.Model(&transaction).Update("status", arg.Status).Error; err != nil {
+			return err
+		}
+
+		// Voided transactions are excluded from the account balance, so
+		// flipping into or out of Voided changes it.
+		if wasVoided || arg.Status == models.TransactionVoided {
+			if err := RecalculateBalance(tx, transaction.AccountID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	transaction.Status = arg.Status
+	return transaction, nil
+}
+
+var ErrNotFound = errors.New("not found")
@@ -0,0 +1,105 @@
+// Package store decouples transaction-related controller logic from GORM so
+// it can be unit tested against a mock instead of a real database.
+package store
+
+import (
+	"context"
+	"time"
+
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreateTransactionArg is the input to CreateTransactionTx.
+type CreateTransactionArg struct {
+	UserID      uint
+	AccountID   uint
+	CategoryID  *uint
+	Amount      decimal.Decimal
+	Description string
+	TxnDate     time.Time
+	Notes       string
+	Splits      []models.TransactionSplit
+
+	// BudgetGroupID/SplitKind mark the created transaction as shared within
+	// a BudgetGroup (see controllers.GetGroupBalances) rather than an
+	// ordinary individual expense. SplitKind defaults to
+	// models.TransactionSplitIndividual when left zero-valued.
+	BudgetGroupID *uint
+	SplitKind     models.TransactionSplitKind
+}
+
+// TransactionFilters narrows GetTransactions to a subset of rows.
+type TransactionFilters struct {
+	AccountID  string
+	CategoryID string
+	From       *time.Time
+	To         *time.Time
+	MinAmount  *decimal.Decimal
+	MaxAmount  *decimal.Decimal
+	Search     string
+	// Status restricts to a single models.TransactionStatus. Voided
+	// transactions are excluded from balance calculations but, unlike
+	// Reconciled/Cleared/etc., remain visible here unless a caller asks for
+	// one status specifically.
+	Status string
+
+	// Keyset pagination, matching the txn_date DESC, id DESC ordering and
+	// the (user_id, txn_date, id) index GetTransactions relies on. Both nil
+	// means "first page". Limit is the exact row count to fetch; callers
+	// that need to know whether a next page exists should ask for one extra
+	// row and trim it off themselves (see controllers.GetTransactions).
+	CursorTxnDate *time.Time
+	CursorID      *uint
+	Limit         int
+
+	// IncludeNotes/IncludeSplits gate the two priciest/least-used parts of a
+	// transaction row off the default list response (see the `?fields=`
+	// projection in controllers.GetTransactions). Both default to false, so
+	// callers that want the old always-everything behavior must opt in
+	// explicitly.
+	IncludeNotes  bool
+	IncludeSplits bool
+}
+
+// UpdateTransactionArg is the input to UpdateTransactionTx.
+type UpdateTransactionArg struct {
+	ID          uint
+	UserID      uint
+	AccountID   uint
+	CategoryID  *uint
+	Amount      decimal.Decimal
+	Description string
+	TxnDate     *time.Time
+	Notes       string
+}
+
+// UpdateTransactionStatusArg is the input to UpdateTransactionStatusTx.
+type UpdateTransactionStatusArg struct {
+	ID     uint
+	UserID uint
+	Status models.TransactionStatus
+}
+
+// Store wraps the queries CreateTransaction, GetTransactions,
+// UpdateTransaction, and DeleteTransaction need, so controllers don't talk to
+// GORM directly.
+type Store interface {
+	// CreateTransactionTx creates a transaction (and its splits, if any) and
+	// updates the owning account's balance atomically.
+	CreateTransactionTx(ctx context.Context, arg CreateTransactionArg) (models.Transaction, error)
+	GetTransactions(ctx context.Context, userID uint, filters TransactionFilters) ([]models.Transaction, error)
+	GetTransaction(ctx context.Context, userID uint, id uint) (models.Transaction, error)
+	// UpdateTransactionTx updates a transaction and recomputes the owning
+	// account's balance atomically.
+	UpdateTransactionTx(ctx context.Context, arg UpdateTransactionArg) (models.Transaction, error)
+	// DeleteTransactionTx deletes a transaction (and its splits) and
+	// recomputes the owning account's balance atomically.
+	DeleteTransactionTx(ctx context.Context, userID uint, id uint) error
+	// UpdateTransactionStatusTx updates a transaction's reconciliation status
+	// and, if that flips it into or out of Voided, recomputes the owning
+	// account's balance in the same atomic transaction - so a crash between
+	// the two can't leave the balance stale.
+	UpdateTransactionStatusTx(ctx context.Context, arg UpdateTransactionStatusArg) (models.Transaction, error)
+}
@@ -0,0 +1,125 @@
+// Package mailer sends outbound transactional email (currently just
+// password-reset links). It's deliberately tiny - one interface so
+// controllers never talk to net/smtp directly, and one env-driven
+// implementation of it for production.
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/smtp"
+	"os"
+)
+
+// Mailer sends a single plain-text email, optionally with one attachment.
+// Implementations should treat a misconfigured/unreachable mail server as
+// a normal error, not a panic - callers decide whether that should block
+// the request or just get logged.
+type Mailer interface {
+	Send(to, subject, body string) error
+	SendWithAttachment(to, subject, body, filename, contentType string, data []byte) error
+}
+
+// smtpMailer sends mail through an SMTP relay configured via
+// SMTP_HOST/SMTP_PORT/SMTP_USER/SMTP_PASS/SMTP_FROM, the same
+// env-var-per-setting convention ConnectDatabase/InitRedis use.
+type smtpMailer struct {
+	host, port, user, pass, from string
+}
+
+// NewSMTPMailer builds a Mailer from the SMTP_* environment variables. It
+// does not validate connectivity up front - the first Send call will fail
+// loudly if the configuration is bad.
+func NewSMTPMailer() Mailer {
+	return &smtpMailer{
+		host: os.Getenv("SMTP_HOST"),
+		port: os.Getenv("SMTP_PORT"),
+		user: os.Getenv("SMTP_USER"),
+		pass: os.Getenv("SMTP_PASS"),
+		from: os.Getenv("SMTP_FROM"),
+	}
+}
+
+func (m *smtpMailer) Send(to, subject, body string) error {
+	if m.host == "" {
+		return fmt.Errorf("mailer: SMTP_HOST is not configured")
+	}
+
+	addr := m.host + ":" + m.port
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", m.from, to, subject, body)
+
+	var auth smtp.Auth
+	if m.user != "" {
+		auth = smtp.PlainAuth("", m.user, m.pass, m.host)
+	}
+
+	return smtp.SendMail(addr, auth, m.from, []string{to}, []byte(msg))
+}
+
+// SendWithAttachment sends a plain-text email with a single file
+// attachment, hand-built as a two-part multipart/mixed MIME message since
+// there's no go.mod here to add a MIME library to.
+func (m *smtpMailer) SendWithAttachment(to, subject, body, filename, contentType string, data []byte) error {
+	if m.host == "" {
+		return fmt.Errorf("mailer: SMTP_HOST is not configured")
+	}
+
+	const boundary = "pft-report-boundary"
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	fmt.Fprintf(&buf, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	buf.WriteString(body)
+	buf.WriteString("\r\n\r\n")
+
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	fmt.Fprintf(&buf, "Content-Type: %s\r\n", contentType)
+	fmt.Fprintf(&buf, "Content-Transfer-Encoding: base64\r\n")
+	fmt.Fprintf(&buf, "Content-Disposition: attachment; filename=%q\r\n\r\n", filename)
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for i := 0; i < len(encoded); i += 76 {
+		end := i + 76
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		buf.WriteString(encoded[i:end])
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	var auth smtp.Auth
+	if m.user != "" {
+		auth = smtp.PlainAuth("", m.user, m.pass, m.host)
+	}
+
+	addr := m.host + ":" + m.port
+	return smtp.SendMail(addr, auth, m.from, []string{to}, buf.Bytes())
+}
+
+// active is the Mailer every caller uses; defaults to NewSMTPMailer() but
+// can be swapped (e.g. in tests) via SetMailer.
+var active Mailer = NewSMTPMailer()
+
+// SetMailer overrides the package-level Mailer, e.g. for a test fake that
+// records sent messages instead of talking to a real SMTP server.
+func SetMailer(m Mailer) {
+	active = m
+}
+
+// Send sends an email through the currently active Mailer.
+func Send(to, subject, body string) error {
+	return active.Send(to, subject, body)
+}
+
+// SendWithAttachment sends an email with a file attachment through the
+// currently active Mailer.
+func SendWithAttachment(to, subject, body, filename, contentType string, data []byte) error {
+	return active.SendWithAttachment(to, subject, body, filename, contentType, data)
+}
@@ -0,0 +1,52 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvProvider_ResolvesScreamingSnakeCaseKey(t *testing.T) {
+	t.Setenv("AUTH_REGISTRATION_OPEN", "false")
+
+	value, ok := config.EnvProvider{}.Get("auth.registration_open")
+	require.True(t, ok)
+	assert.Equal(t, "false", value)
+}
+
+func TestEnvProvider_UnsetKeyIsNotFound(t *testing.T) {
+	_, ok := config.EnvProvider{}.Get("does.not.exist")
+	assert.False(t, ok)
+}
+
+func TestFileProvider_ReadsKeyValueLines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.env")
+	require.NoError(t, os.WriteFile(path, []byte("# comment\nseed.demo_enabled=true\nauth.registration_open = false\n"), 0o600))
+
+	provider, err := config.NewFileProvider(path)
+	require.NoError(t, err)
+
+	value, ok := provider.Get("seed.demo_enabled")
+	require.True(t, ok)
+	assert.Equal(t, "true", value)
+
+	value, ok = provider.Get("auth.registration_open")
+	require.True(t, ok)
+	assert.Equal(t, "false", value)
+}
+
+func TestFileProvider_MissingFileErrors(t *testing.T) {
+	_, err := config.NewFileProvider(filepath.Join(t.TempDir(), "missing.env"))
+	assert.Error(t, err)
+}
+
+func TestIsEnabled_FallsBackToDefaultWhenUnconfigured(t *testing.T) {
+	assert.True(t, config.IsEnabled("some.unconfigured.flag", true))
+	assert.False(t, config.IsEnabled("some.unconfigured.flag", false))
+}
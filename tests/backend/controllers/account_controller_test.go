@@ -2,73 +2,81 @@ package controllers_test
 
 import (
 	"bytes"
-    "encoding/json"
-    "net/http"
-    "net/http/httptest"
-    "testing"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
 
-    "Personal-Finance-Tracker-backend/controllers"
-    "Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/controllers"
 	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
 
-    "github.com/gin-gonic/gin"
-    "gorm.io/driver/sqlite"
-    "gorm.io/gorm"
-    "gorm.io/gorm/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 
 	"github.com/stretchr/testify/assert"
-
 )
 
 // setupTestDB creates an in-memory SQLite database for testing
 func SetupTestDB() *gorm.DB {
-    database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-        Logger: logger.Default.LogMode(logger.Silent),
-    })
-    if err != nil {
-        panic("Failed to connect to test database: " + err.Error())
-    }
-
-    // Migrate ALL tables
-    err = database.AutoMigrate(
-        &models.User{},
-        &models.Account{},
-        &models.Category{},
-        &models.Transaction{},
-        &models.TransactionSplit{},
-        &models.Budget{},
-        &models.BudgetItem{},
-    )
-    if err != nil {
-        panic("Failed to migrate test database: " + err.Error())
-    }
-
-    return database
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		panic("Failed to connect to test database: " + err.Error())
+	}
+
+	// Migrate ALL tables
+	err = database.AutoMigrate(
+		&models.User{},
+		&models.Account{},
+		&models.Category{},
+		&models.Transaction{},
+		&models.TransactionSplit{},
+		&models.Budget{},
+		&models.BudgetItem{},
+		&models.LedgerEntry{},
+		&models.Transfer{},
+		&models.RecurringRule{},
+		&models.IdempotencyKey{},
+		&models.AuditLog{},
+		&models.BudgetPrediction{},
+		&models.BudgetAlert{},
+		&models.BudgetAlertEvent{},
+	)
+	if err != nil {
+		panic("Failed to migrate test database: " + err.Error())
+	}
+
+	return database
 }
 
 // setupRouter creates a Gin router for testing
 func SetupRouter() *gin.Engine {
-    gin.SetMode(gin.TestMode)
-    return gin.New()
+	gin.SetMode(gin.TestMode)
+	return gin.New()
 }
 
 // createTestUser creates a test user in the database
 func CreateTestUser(database *gorm.DB) *models.User {
-    hash, _ := controllers.HashPassword("testpassword")
-    user := models.User{
-        Username:     "testuser",
-        Email:        "testuser@example.com",
-        PasswordHash: hash,
-        Role:         models.UserRoleUser,
-    }
-    database.Create(&user)
-    return &user
+	hash, _ := controllers.HashPassword("testpassword")
+	user := models.User{
+		Username:     "testuser",
+		Email:        "testuser@example.com",
+		PasswordHash: hash,
+		Role:         models.UserRoleUser,
+	}
+	database.Create(&user)
+	return &user
 }
 
 // getTestToken generates a JWT token for testing
 func GetTestToken(userID uint, username string) string {
-    token, _ := controllers.GenerateToken(userID, username, "user")
-    return token
+	token, _ := controllers.GenerateToken(userID, username, "user")
+	return token
 }
 
 func TestCreateAccount(t *testing.T) {
@@ -124,7 +132,7 @@ func TestCreateAccount(t *testing.T) {
 	assert.Equal(t, "Test Checking", response.Name, "Account name should match")
 	assert.Equal(t, "checking", string(response.Type), "Account type should match")
 	assert.Equal(t, int64(1000), response.InitialBalanceCents, "Initial balance should match")
-	assert.Equal(t, int64(1000), response.CurrentBalanceCents, "Current balance should equal initial balance")
+	assert.True(t, decimal.NewFromInt(10).Equal(response.CurrentBalance), "Current balance should equal initial balance")
 
 	// Verify data was actually saved to database
 	var savedAccount models.Account
@@ -145,14 +153,14 @@ func TestGetAccounts(t *testing.T) {
 		Name:                "Checking",
 		Type:                "Checking",
 		InitialBalanceCents: 5000,
-		CurrentBalanceCents: 5000,
+		CurrentBalance:      decimal.NewFromInt(5000).Div(decimal.NewFromInt(100)),
 	})
 	database.Create(&models.Account{
 		UserID:              user.ID,
 		Name:                "Savings",
 		Type:                "Saving",
 		InitialBalanceCents: 5000,
-		CurrentBalanceCents: 5000,
+		CurrentBalance:      decimal.NewFromInt(5000).Div(decimal.NewFromInt(100)),
 	})
 
 	router := SetupRouter()
@@ -190,7 +198,7 @@ func TestUpdateAccount(t *testing.T) {
 		Name:                "Old Name",
 		Type:                "checking",
 		InitialBalanceCents: 5000,
-		CurrentBalanceCents: 5000,
+		CurrentBalance:      decimal.NewFromInt(5000).Div(decimal.NewFromInt(100)),
 	}
 	database.Create(&account)
 
@@ -235,7 +243,7 @@ func TestDeleteAccount(t *testing.T) {
 		Name:                "To Delete",
 		Type:                "checking",
 		InitialBalanceCents: 5000,
-		CurrentBalanceCents: 5000,
+		CurrentBalance:      decimal.NewFromInt(5000).Div(decimal.NewFromInt(100)),
 	}
 	database.Create(&account)
 
@@ -0,0 +1,73 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportTransactionsAdmin_CSV(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+	database.Create(&models.Transaction{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		Amount:      decimal.NewFromInt(-5000).Div(decimal.NewFromInt(100)),
+		Description: "Coffee",
+		TxnDate:     time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+	})
+
+	router := SetupRouter()
+	router.GET("/api/admin/export/transactions", controllers.ExportTransactionsAdmin)
+
+	req, _ := http.NewRequest("GET", "/api/admin/export/transactions?format=csv", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.True(t, strings.Contains(body, "account_id,account_name,txn_date,amount_cents,payee,category,fitid"))
+	assert.True(t, strings.Contains(body, "Coffee"))
+}
+
+func TestExportTransactionsAdmin_OFX(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+	database.Create(&models.Transaction{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		Amount:      decimal.NewFromInt(-5000).Div(decimal.NewFromInt(100)),
+		Description: "Coffee",
+		TxnDate:     time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+	})
+
+	router := SetupRouter()
+	router.GET("/api/admin/export/transactions", controllers.ExportTransactionsAdmin)
+
+	req, _ := http.NewRequest("GET", "/api/admin/export/transactions?format=ofx", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.True(t, strings.Contains(body, "<STMTTRN>"))
+	assert.True(t, strings.Contains(body, "</OFX>"))
+}
@@ -0,0 +1,105 @@
+package controllers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/store"
+
+	"github.com/shopspring/decimal"
+)
+
+// seedBudgetBenchData populates ~1k users, one budget (with one item) per
+// user, and ~100k transactions spread across them - representative of the
+// admin budget listing's worst case in production - then hands back the
+// budgets GetBudgetPlannedTotals/GetBudgetSpentTotals would be asked to
+// total for a single page.
+func seedBudgetBenchData(b *testing.B) (*store.GormStore, []store.BudgetPeriod, []uint) {
+	b.Helper()
+	database := SetupTestDB()
+
+	const userCount = 1000
+	const txnsPerUser = 100
+
+	periodStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	budgets := make([]store.BudgetPeriod, 0, userCount)
+	budgetIDs := make([]uint, 0, userCount)
+
+	for i := 0; i < userCount; i++ {
+		user := models.User{
+			Username:     "benchuser",
+			Email:        "bench@example.com",
+			PasswordHash: "hash",
+			Role:         models.UserRoleUser,
+		}
+		database.Create(&user)
+
+		account := models.Account{UserID: user.ID, Name: "Checking", Type: models.AccountChecking}
+		database.Create(&account)
+
+		category := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+		database.Create(&category)
+
+		budget := models.Budget{
+			UserID:      user.ID,
+			PeriodStart: periodStart,
+			PeriodEnd:   periodEnd,
+			Currency:    "USD",
+		}
+		database.Create(&budget)
+		database.Create(&models.BudgetItem{BudgetID: budget.ID, CategoryID: category.ID, PlannedAmount: decimal.NewFromInt(50000).Div(decimal.NewFromInt(100))})
+		budgets = append(budgets, store.BudgetPeriod{ID: budget.ID, UserID: user.ID, PeriodStart: periodStart, PeriodEnd: periodEnd})
+		budgetIDs = append(budgetIDs, budget.ID)
+
+		txns := make([]models.Transaction, 0, txnsPerUser)
+		for t := 0; t < txnsPerUser; t++ {
+			txns = append(txns, models.Transaction{
+				UserID:      user.ID,
+				AccountID:   account.ID,
+				CategoryID:  &category.ID,
+				Description: "bench txn",
+				Amount:      decimal.NewFromInt(-1000).Div(decimal.NewFromInt(100)),
+				Status:      models.TransactionEntered,
+				TxnDate:     periodStart.AddDate(0, 0, t%28),
+			})
+		}
+		database.CreateInBatches(txns, 100)
+	}
+
+	return store.NewGormStore(database), budgets, budgetIDs
+}
+
+// BenchmarkGetBudgetPlannedTotals demonstrates the planned-total rollup for
+// an admin budget listing page stays a single grouped query regardless of
+// how many budgets/budget_items exist, instead of one SUM query per budget.
+func BenchmarkGetBudgetPlannedTotals(b *testing.B) {
+	gormStore, _, budgetIDs := seedBudgetBenchData(b)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := gormStore.GetBudgetPlannedTotals(ctx, budgetIDs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGetBudgetSpentTotals demonstrates the spent-total rollup staying
+// two queries total (one per-user-per-month GROUP BY, plus the page query
+// that already ran) against a ~100k-row transactions table, instead of one
+// SUM query per budget.
+func BenchmarkGetBudgetSpentTotals(b *testing.B) {
+	gormStore, budgets, _ := seedBudgetBenchData(b)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := gormStore.GetBudgetSpentTotals(ctx, budgets); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
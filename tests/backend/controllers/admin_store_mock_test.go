@@ -0,0 +1,59 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/mocks"
+	"Personal-Finance-Tracker-backend/store"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetUserDetailsWithMockStore asserts GetUserDetails reads its
+// statistics through store.AdminStore rather than issuing its own COUNT
+// queries, without touching a database.
+func TestGetUserDetailsWithMockStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockAdminStore(ctrl)
+	originalStore := controllers.AdminStore
+	controllers.AdminStore = func() store.AdminStore { return mockStore }
+	defer func() { controllers.AdminStore = originalStore }()
+
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+
+	mockStore.EXPECT().
+		GetUserAggregateCounts(gomock.Any(), user.ID).
+		Return(store.UserAggregateCounts{Accounts: 2, Transactions: 5, Categories: 3, Budgets: 1}, nil)
+
+	router := SetupRouter()
+	router.GET("/api/admin/users/:id", controllers.GetUserDetails)
+
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/admin/users/%d", user.ID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Statistics struct {
+			Accounts     int64 `json:"accounts"`
+			Transactions int64 `json:"transactions"`
+			Categories   int64 `json:"categories"`
+			Budgets      int64 `json:"budgets"`
+		} `json:"statistics"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, int64(2), response.Statistics.Accounts)
+	assert.Equal(t, int64(5), response.Statistics.Transactions)
+}
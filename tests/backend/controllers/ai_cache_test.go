@@ -0,0 +1,227 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/mocks"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/pkg/money"
+	"Personal-Finance-Tracker-backend/services/ai"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *AIControllerTestSuite) TestGetBudgetPrediction_TTLExpiryRecallsPredictor() {
+	resetAIResilience()
+	defer resetAIResilience()
+	originalTTL := controllers.AIPredictionCacheTTL
+	controllers.AIPredictionCacheTTL = 20 * time.Millisecond
+	defer func() { controllers.AIPredictionCacheTTL = originalTTL }()
+
+	_, token := seedAIResilienceUser(suite.T(), suite.database, "ttl-expiry-user")
+
+	ctrl := gomock.NewController(suite.T())
+	defer ctrl.Finish()
+
+	originalPredictor := controllers.AIPredictor
+	defer func() { controllers.AIPredictor = originalPredictor }()
+
+	healthy := ai.PredictResponse{
+		Predictions: []ai.BudgetPrediction{{CategoryID: 1, CategoryName: "Groceries", PredictedAmount: money.FromCents(5000)}},
+		Message:     "Predictions generated successfully",
+	}
+	mockPredictor := mocks.NewMockAIPredictor(ctrl)
+	mockPredictor.EXPECT().Predict(gomock.Any(), gomock.Any()).Return(healthy, nil).Times(2)
+	controllers.AIPredictor = func() ai.Predictor { return mockPredictor }
+
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	time.Sleep(controllers.AIPredictionCacheTTL + 10*time.Millisecond)
+
+	// Past the TTL, the cache entry is no longer fresh, so this request
+	// should reach the predictor again instead of serving the stale entry -
+	// mockPredictor.EXPECT(...).Times(2) fails at ctrl.Finish() otherwise.
+	req2, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req2.Header.Set("Authorization", "Bearer "+token)
+	w2 := httptest.NewRecorder()
+	suite.router.ServeHTTP(w2, req2)
+	suite.Equal(http.StatusOK, w2.Code)
+}
+
+func (suite *AIControllerTestSuite) TestGetBudgetPrediction_NewTransactionBustsCache() {
+	resetAIResilience()
+	defer resetAIResilience()
+
+	user, token := seedAIResilienceUser(suite.T(), suite.database, "fingerprint-bust-user")
+
+	ctrl := gomock.NewController(suite.T())
+	defer ctrl.Finish()
+
+	originalPredictor := controllers.AIPredictor
+	defer func() { controllers.AIPredictor = originalPredictor }()
+
+	healthy := ai.PredictResponse{
+		Predictions: []ai.BudgetPrediction{{CategoryID: 1, CategoryName: "Groceries", PredictedAmount: money.FromCents(5000)}},
+		Message:     "Predictions generated successfully",
+	}
+	mockPredictor := mocks.NewMockAIPredictor(ctrl)
+	mockPredictor.EXPECT().Predict(gomock.Any(), gomock.Any()).Return(healthy, nil).Times(2)
+	controllers.AIPredictor = func() ai.Predictor { return mockPredictor }
+
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	// A new transaction changes transactionsFingerprint, which should miss
+	// the otherwise-still-fresh cache entry and recall the predictor.
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: models.AccountChecking, Currency: "USD"}
+	require.NoError(suite.T(), suite.database.Create(&account).Error)
+	require.NoError(suite.T(), suite.database.Create(&models.Transaction{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		Amount:      decimal.NewFromInt(-1200).Div(decimal.NewFromInt(100)),
+		Description: "coffee",
+		TxnDate:     time.Now(),
+		Status:      models.TransactionEntered,
+	}).Error)
+
+	req2, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req2.Header.Set("Authorization", "Bearer "+token)
+	w2 := httptest.NewRecorder()
+	suite.router.ServeHTTP(w2, req2)
+	suite.Equal(http.StatusOK, w2.Code)
+}
+
+func (suite *AIControllerTestSuite) TestGetBudgetPrediction_ETagRoundTripReturns304() {
+	resetAIResilience()
+	defer resetAIResilience()
+
+	_, token := seedAIResilienceUser(suite.T(), suite.database, "etag-roundtrip-user")
+
+	ctrl := gomock.NewController(suite.T())
+	defer ctrl.Finish()
+
+	originalPredictor := controllers.AIPredictor
+	defer func() { controllers.AIPredictor = originalPredictor }()
+
+	healthy := ai.PredictResponse{
+		Predictions: []ai.BudgetPrediction{{CategoryID: 1, CategoryName: "Groceries", PredictedAmount: money.FromCents(5000)}},
+		Message:     "Predictions generated successfully",
+	}
+	mockPredictor := mocks.NewMockAIPredictor(ctrl)
+	mockPredictor.EXPECT().Predict(gomock.Any(), gomock.Any()).Return(healthy, nil).Times(1)
+	controllers.AIPredictor = func() ai.Predictor { return mockPredictor }
+
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	suite.NotEmpty(etag)
+
+	req2, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req2.Header.Set("Authorization", "Bearer "+token)
+	req2.Header.Set("If-None-Match", etag)
+	w2 := httptest.NewRecorder()
+	suite.router.ServeHTTP(w2, req2)
+	suite.Equal(http.StatusNotModified, w2.Code)
+	suite.Empty(w2.Body.Bytes())
+}
+
+func (suite *AIControllerTestSuite) TestGetBudgetPrediction_ConcurrentRequestsCollapseToOnePredictorCall() {
+	resetAIResilience()
+	defer resetAIResilience()
+
+	_, token := seedAIResilienceUser(suite.T(), suite.database, "singleflight-user")
+
+	ctrl := gomock.NewController(suite.T())
+	defer ctrl.Finish()
+
+	originalPredictor := controllers.AIPredictor
+	defer func() { controllers.AIPredictor = originalPredictor }()
+
+	healthy := ai.PredictResponse{
+		Predictions: []ai.BudgetPrediction{{CategoryID: 1, CategoryName: "Groceries", PredictedAmount: money.FromCents(5000)}},
+		Message:     "Predictions generated successfully",
+	}
+	mockPredictor := mocks.NewMockAIPredictor(ctrl)
+	mockPredictor.EXPECT().Predict(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx interface{}, req interface{}) (ai.PredictResponse, error) {
+			time.Sleep(20 * time.Millisecond)
+			return healthy, nil
+		}).Times(1)
+	controllers.AIPredictor = func() ai.Predictor { return mockPredictor }
+
+	const concurrency = 5
+	var wg sync.WaitGroup
+	codes := make([]int, concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			suite.router.ServeHTTP(w, req)
+			codes[i] = w.Code
+		}(i)
+	}
+	wg.Wait()
+
+	for _, code := range codes {
+		suite.Equal(http.StatusOK, code)
+	}
+}
+
+func (suite *AIControllerTestSuite) TestGetBudgetPrediction_RefreshBypassesFreshCache() {
+	resetAIResilience()
+	defer resetAIResilience()
+
+	_, token := seedAIResilienceUser(suite.T(), suite.database, "refresh-bypass-user")
+
+	ctrl := gomock.NewController(suite.T())
+	defer ctrl.Finish()
+
+	originalPredictor := controllers.AIPredictor
+	defer func() { controllers.AIPredictor = originalPredictor }()
+
+	healthy := ai.PredictResponse{
+		Predictions: []ai.BudgetPrediction{{CategoryID: 1, CategoryName: "Groceries", PredictedAmount: money.FromCents(5000)}},
+		Message:     "Predictions generated successfully",
+	}
+	mockPredictor := mocks.NewMockAIPredictor(ctrl)
+	mockPredictor.EXPECT().Predict(gomock.Any(), gomock.Any()).Return(healthy, nil).Times(2)
+	controllers.AIPredictor = func() ai.Predictor { return mockPredictor }
+
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	// ?refresh=true should call the predictor again even though the first
+	// response is still well within AIPredictionCacheTTL.
+	req2, _ := http.NewRequest("GET", "/api/ai/budget-predictions?refresh=true", nil)
+	req2.Header.Set("Authorization", "Bearer "+token)
+	w2 := httptest.NewRecorder()
+	suite.router.ServeHTTP(w2, req2)
+	suite.Equal(http.StatusOK, w2.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w2.Body.Bytes(), &response)
+	suite.Equal(false, response["stale"])
+}
@@ -1,273 +1,71 @@
 package controllers_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
-	"os"
-	"strings"
 	"testing"
 	"time"
 
 	"Personal-Finance-Tracker-backend/controllers"
 	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/mocks"
 	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/pkg/money"
+	"Personal-Finance-Tracker-backend/services/ai"
+	"Personal-Finance-Tracker-backend/services/anomaly"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
 )
 
-// AIControllerTestSuite defines the test suite for AI controller tests
+// AIControllerTestSuite is a thin HTTP integration test over the AI
+// endpoints - the forecasting logic itself is covered directly against
+// services/ai.Service in tests/backend/services/ai, so this only checks
+// that the Gin handlers wire query params and the JSON response shape
+// correctly.
 type AIControllerTestSuite struct {
 	suite.Suite
-	database     *gorm.DB
-	user         *models.User
-	userToken    string
-	router       *gin.Engine
-	mockAIServer *httptest.Server
-	originalHost string
-	originalPort string
+	database  *gorm.DB
+	user      *models.User
+	userToken string
+	router    *gin.Engine
 }
 
-// SetupSuite is called once before all tests in the suite
 func (suite *AIControllerTestSuite) SetupSuite() {
-	// Setup database
 	suite.database = SetupTestDB()
 	db.DB = suite.database
 
-	// Create test user
 	suite.user = CreateTestUser(suite.database)
 	suite.userToken = GetTestToken(suite.user.ID, suite.user.Username)
 
-	// Store original environment variables
-	suite.originalHost = os.Getenv("AI_SERVICE_HOST")
-	suite.originalPort = os.Getenv("AI_SERVICE_PORT")
-
-	// Setup mock AI server
-	suite.setupMockAIServer()
-
-	// Setup router
 	suite.router = SetupRouter()
-	suite.setupAIRoutes()
-}
-
-// TearDownSuite is called once after all tests in the suite
-func (suite *AIControllerTestSuite) TearDownSuite() {
-	// Restore original environment variables
-	if suite.originalHost != "" {
-		os.Setenv("AI_SERVICE_HOST", suite.originalHost)
-	} else {
-		os.Unsetenv("AI_SERVICE_HOST")
-	}
-
-	if suite.originalPort != "" {
-		os.Setenv("AI_SERVICE_PORT", suite.originalPort)
-	} else {
-		os.Unsetenv("AI_SERVICE_PORT")
-	}
-
-	// Close mock server
-	if suite.mockAIServer != nil {
-		suite.mockAIServer.Close()
-	}
-}
-
-// setupMockAIServer creates a mock HTTP server for AI service responses
-func (suite *AIControllerTestSuite) setupMockAIServer() {
-	suite.mockAIServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.URL.Path {
-		case "/predict-budget":
-			suite.handleBudgetPrediction(w, r)
-		case "/analyze-patterns":
-			suite.handleSpendingPatterns(w, r)
-		default:
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": "endpoint not found",
-			})
-		}
-	}))
-
-	// Extract host and port from mock server URL
-	serverURL := strings.TrimPrefix(suite.mockAIServer.URL, "http://")
-	parts := strings.Split(serverURL, ":")
-
-	os.Setenv("AI_SERVICE_HOST", parts[0])
-	os.Setenv("AI_SERVICE_PORT", parts[1])
-}
-
-// handleBudgetPrediction simulates AI service budget prediction response
-func (suite *AIControllerTestSuite) handleBudgetPrediction(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		return
-	}
-
-	var request map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "invalid request body",
-		})
-		return
-	}
-
-	// Check for required fields
-	userID, hasUserID := request["user_id"]
-	targetMonth, hasMonth := request["target_month"]
-	targetYear, hasYear := request["target_year"]
-
-	if !hasUserID || !hasMonth || !hasYear {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "missing required fields",
-		})
-		return
-	}
-
-	// Simulate different responses based on user_id for testing
-	switch int(userID.(float64)) {
-	case 999: // Test user that causes AI service error
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "AI service internal error",
-		})
-		return
-	case 998: // Test user with no historical data
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"predictions":            []interface{}{},
-			"target_month":           targetMonth,
-			"target_year":            targetYear,
-			"user_id":                userID,
-			"historical_data_points": 0,
-			"message":                "Insufficient historical data for predictions",
-		})
-		return
-	default:
-		// Normal successful response
-		predictions := []map[string]interface{}{
-			{
-				"category_id":              1,
-				"category_name":            "Groceries",
-				"predicted_amount_cents":   45000,
-				"predicted_amount_dollars": 450.0,
-				"confidence_score":         0.85,
-				"historical_avg_cents":     42000,
-				"historical_avg_dollars":   420.0,
-				"trend_direction":          "increasing",
-				"reasoning":                "Based on historical spending patterns and seasonal trends",
-			},
-			{
-				"category_id":              2,
-				"category_name":            "Transportation",
-				"predicted_amount_cents":   25000,
-				"predicted_amount_dollars": 250.0,
-				"confidence_score":         0.75,
-				"historical_avg_cents":     28000,
-				"historical_avg_dollars":   280.0,
-				"trend_direction":          "decreasing",
-				"reasoning":                "Recent reduction in commuting expenses",
-			},
-		}
-
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"predictions":            predictions,
-			"target_month":           targetMonth,
-			"target_year":            targetYear,
-			"user_id":                userID,
-			"historical_data_points": 12,
-			"message":                "Predictions generated successfully",
-		})
-	}
-}
-
-// handleSpendingPatterns simulates AI service spending patterns response
-func (suite *AIControllerTestSuite) handleSpendingPatterns(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		return
-	}
-
-	var request map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "invalid request body",
-		})
-		return
-	}
-
-	userID, hasUserID := request["user_id"]
-	if !hasUserID {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "missing user_id",
-		})
-		return
-	}
-
-	// Simulate different responses based on user_id
-	switch int(userID.(float64)) {
-	case 999: // Test user that causes AI service error
-		w.WriteHeader(http.StatusServiceUnavailable)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "AI service temporarily unavailable",
-		})
-		return
-	default:
-		// Normal successful response
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"user_id": userID,
-			"patterns": map[string]interface{}{
-				"spending_velocity":    "moderate",
-				"category_consistency": 0.78,
-				"seasonal_trends": map[string]interface{}{
-					"highest_month": "December",
-					"lowest_month":  "February",
-				},
-				"weekend_vs_weekday": map[string]interface{}{
-					"weekend_ratio": 0.35,
-					"weekday_ratio": 0.65,
-				},
-			},
-			"insights": []string{
-				"Your grocery spending is highly consistent month-to-month",
-				"Entertainment expenses spike significantly on weekends",
-				"Transportation costs are lower in winter months",
-			},
-			"recommendations": []string{
-				"Consider budgeting 15% more for December expenses",
-				"Set weekend spending alerts for entertainment category",
-			},
-			"analyzed_period":  "12 months",
-			"confidence_score": 0.82,
-		})
-	}
-}
-
-// setupAIRoutes sets up AI routes for testing
-func (suite *AIControllerTestSuite) setupAIRoutes() {
 	api := suite.router.Group("/api")
 	api.Use(controllers.AuthMiddleware())
 	{
 		api.GET("/ai/budget-predictions", controllers.GetBudgetPrediction)
+		api.GET("/ai/budget-predictions/stream", controllers.GetBudgetPredictionStream)
+		api.GET("/ai/budget-predictions/history", controllers.GetPredictionHistory)
+		api.GET("/ai/budget-predictions/accuracy", controllers.GetPredictionAccuracy)
 		api.GET("/ai/spending-patterns", controllers.GetSpendingPatterns)
+		api.GET("/ai/spending-patterns/stream", controllers.GetSpendingPatternsStream)
+		api.GET("/ai/anomalies", controllers.GetAnomalies)
+		api.POST("/ai/anomaly-webhooks", controllers.CreateAnomalyWebhook)
+		api.DELETE("/ai/anomaly-webhooks/:id", controllers.DeleteAnomalyWebhook)
 	}
 }
 
-// SetupTest is called before each test
-func (suite *AIControllerTestSuite) SetupTest() {
-	// Clean up data before each test if needed
-	// For AI controller tests, we mainly test the HTTP proxy functionality
-}
-
-// ============================================
-// TEST 1: Budget Prediction Success Cases
-// ============================================
 func (suite *AIControllerTestSuite) TestGetBudgetPrediction_Success() {
 	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
 	req.Header.Set("Authorization", "Bearer "+suite.userToken)
@@ -279,37 +77,20 @@ func (suite *AIControllerTestSuite) TestGetBudgetPrediction_Success() {
 	var response map[string]interface{}
 	json.Unmarshal(w.Body.Bytes(), &response)
 
-	// Verify response structure
 	suite.Contains(response, "predictions")
-	suite.Contains(response, "target_month")
-	suite.Contains(response, "target_year")
+	suite.Contains(response, "target_period")
 	suite.Contains(response, "user_id")
 	suite.Contains(response, "historical_data_points")
 	suite.Contains(response, "message")
 	suite.Contains(response, "generated_at")
-
-	// Verify predictions content
-	predictions := response["predictions"].([]interface{})
-	suite.Equal(2, len(predictions))
-
-	// Check first prediction
-	pred1 := predictions[0].(map[string]interface{})
-	suite.Equal("Groceries", pred1["category_name"])
-	suite.Equal(float64(450), pred1["predicted_amount_dollars"])
-	suite.Equal(float64(0.85), pred1["confidence_score"])
-	suite.Equal("increasing", pred1["trend_direction"])
-
-	// Verify user ID matches
 	suite.Equal(float64(suite.user.ID), response["user_id"])
 
-	// Verify current month/year defaults
 	now := time.Now()
-	suite.Equal(float64(now.Month()), response["target_month"])
-	suite.Equal(float64(now.Year()), response["target_year"])
+	suite.Equal(now.Format("2006-01"), response["target_period"])
 }
 
 func (suite *AIControllerTestSuite) TestGetBudgetPrediction_WithQueryParameters() {
-	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions?target_month=6&target_year=2025&historical_months=18", nil)
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions?target_period=2025-06&historical_months=18", nil)
 	req.Header.Set("Authorization", "Bearer "+suite.userToken)
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
@@ -318,16 +99,11 @@ func (suite *AIControllerTestSuite) TestGetBudgetPrediction_WithQueryParameters(
 
 	var response map[string]interface{}
 	json.Unmarshal(w.Body.Bytes(), &response)
-
-	// Verify query parameters were processed
-	suite.Equal(float64(6), response["target_month"])
-	suite.Equal(float64(2025), response["target_year"])
-	// Historical months parameter is passed to AI service but not returned directly
+	suite.Equal("2025-06", response["target_period"])
 }
 
-func (suite *AIControllerTestSuite) TestGetBudgetPrediction_InvalidQueryParameters() {
-	// Test with invalid parameters - should use defaults
-	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions?target_month=15&target_year=1900&historical_months=100", nil)
+func (suite *AIControllerTestSuite) TestGetBudgetPrediction_InvalidQueryParametersFallBackToDefaults() {
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions?target_period=2026-15&historical_months=100", nil)
 	req.Header.Set("Authorization", "Bearer "+suite.userToken)
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
@@ -337,62 +113,18 @@ func (suite *AIControllerTestSuite) TestGetBudgetPrediction_InvalidQueryParamete
 	var response map[string]interface{}
 	json.Unmarshal(w.Body.Bytes(), &response)
 
-	// Should use current month/year as defaults for invalid values
 	now := time.Now()
-	suite.Equal(float64(now.Month()), response["target_month"])
-	suite.Equal(float64(now.Year()), response["target_year"])
+	suite.Equal(now.Format("2006-01"), response["target_period"])
 }
 
-// ============================================
-// TEST 2: Budget Prediction Error Cases
-// ============================================
 func (suite *AIControllerTestSuite) TestGetBudgetPrediction_Unauthorized() {
 	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
 
 	suite.Equal(http.StatusUnauthorized, w.Code)
-
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	suite.Contains(response["error"], "missing authorization header")
-}
-
-func (suite *AIControllerTestSuite) TestGetBudgetPrediction_NoHistoricalData() {
-	// Create a special user with ID 998 that triggers "no historical data" response
-	userWithNoData := models.User{
-		ID:           998,
-		Username:     "nodata",
-		Email:        "nodata@example.com",
-		PasswordHash: "hash",
-		Role:         models.UserRoleUser,
-	}
-	suite.database.Create(&userWithNoData)
-
-	token := GetTestToken(userWithNoData.ID, userWithNoData.Username)
-
-	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req)
-
-	suite.Equal(http.StatusOK, w.Code)
-
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	predictions := response["predictions"].([]interface{})
-	suite.Equal(0, len(predictions))
-	suite.Equal(float64(0), response["historical_data_points"])
-	suite.Contains(response["message"], "Insufficient historical data")
-
-	// Cleanup
-	suite.database.Delete(&userWithNoData)
 }
 
-// ============================================
-// TEST 3: Spending Patterns Success Cases
-// ============================================
 func (suite *AIControllerTestSuite) TestGetSpendingPatterns_Success() {
 	req, _ := http.NewRequest("GET", "/api/ai/spending-patterns", nil)
 	req.Header.Set("Authorization", "Bearer "+suite.userToken)
@@ -404,248 +136,419 @@ func (suite *AIControllerTestSuite) TestGetSpendingPatterns_Success() {
 	var response map[string]interface{}
 	json.Unmarshal(w.Body.Bytes(), &response)
 
-	// Verify response structure
 	suite.Contains(response, "user_id")
 	suite.Contains(response, "patterns")
 	suite.Contains(response, "insights")
 	suite.Contains(response, "recommendations")
 	suite.Contains(response, "analyzed_period")
 	suite.Contains(response, "confidence_score")
+	suite.Equal(float64(suite.user.ID), response["user_id"])
+}
 
-	// Check patterns structure
-	patterns := response["patterns"].(map[string]interface{})
-	suite.Contains(patterns, "spending_velocity")
-	suite.Contains(patterns, "category_consistency")
-	suite.Contains(patterns, "seasonal_trends")
-	suite.Contains(patterns, "weekend_vs_weekday")
+func (suite *AIControllerTestSuite) TestGetSpendingPatterns_Unauthorized() {
+	req, _ := http.NewRequest("GET", "/api/ai/spending-patterns", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
 
-	// Check insights and recommendations are arrays
-	insights := response["insights"].([]interface{})
-	suite.Greater(len(insights), 0)
+	suite.Equal(http.StatusUnauthorized, w.Code)
+}
 
-	recommendations := response["recommendations"].([]interface{})
-	suite.Greater(len(recommendations), 0)
+// resetAIResilience restores the circuit breaker/cache to a clean, default
+// -tuned state between resilience test cases, so one test tripping the
+// breaker can't leak into the next.
+func resetAIResilience() {
+	controllers.ResetAIResilienceState()
+	controllers.AIBreakerFailureThreshold = 5
+	controllers.AIBreakerWindow = 2 * time.Minute
+	controllers.AIBreakerCooldown = 30 * time.Second
+}
 
-	// Verify user ID
-	suite.Equal(float64(suite.user.ID), response["user_id"])
+// seedAIResilienceUser creates a second user distinct from suite.user (who
+// is reserved for the non-resilience tests above), with one expense
+// transaction so the moving-average fallback has something to average.
+func seedAIResilienceUser(t *testing.T, database *gorm.DB, username string) (*models.User, string) {
+	t.Helper()
+	user := models.User{Username: username, Email: username + "@example.com", PasswordHash: "hash", Role: models.UserRoleUser}
+	require.NoError(t, database.Create(&user).Error)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: models.AccountChecking, Currency: "USD"}
+	require.NoError(t, database.Create(&account).Error)
+	category := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	require.NoError(t, database.Create(&category).Error)
+	require.NoError(t, database.Create(&models.Transaction{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		CategoryID:  &category.ID,
+		Amount:      decimal.NewFromInt(-5000).Div(decimal.NewFromInt(100)),
+		Description: "groceries",
+		TxnDate:     time.Now(),
+		Status:      models.TransactionEntered,
+	}).Error)
+
+	return &user, GetTestToken(user.ID, user.Username)
 }
 
-func (suite *AIControllerTestSuite) TestGetSpendingPatterns_WithHistoricalMonths() {
-	req, _ := http.NewRequest("GET", "/api/ai/spending-patterns?historical_months=6", nil)
-	req.Header.Set("Authorization", "Bearer "+suite.userToken)
+func (suite *AIControllerTestSuite) TestGetBudgetPrediction_CacheHitOnFailureServesLastKnownPrediction() {
+	resetAIResilience()
+	defer resetAIResilience()
+
+	user, token := seedAIResilienceUser(suite.T(), suite.database, "cache-hit-user")
+
+	ctrl := gomock.NewController(suite.T())
+	defer ctrl.Finish()
+
+	originalPredictor := controllers.AIPredictor
+	defer func() { controllers.AIPredictor = originalPredictor }()
+
+	healthy := ai.PredictResponse{
+		Predictions: []ai.BudgetPrediction{{CategoryID: 1, CategoryName: "Groceries", PredictedAmount: money.FromCents(5000)}},
+		UserID:      user.ID,
+		Message:     "Predictions generated successfully",
+	}
+	mockPredictor := mocks.NewMockAIPredictor(ctrl)
+	mockPredictor.EXPECT().Predict(gomock.Any(), gomock.Any()).Return(healthy, nil).Times(1)
+	mockPredictor.EXPECT().Predict(gomock.Any(), gomock.Any()).Return(ai.PredictResponse{}, errors.New("ai backend unreachable")).Times(1)
+	controllers.AIPredictor = func() ai.Predictor { return mockPredictor }
+
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
 
+	var first map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &first)
+	suite.Equal(false, first["stale"])
+
+	// Force past the fresh cache entry so this second request actually
+	// reaches the (failing) mock predictor instead of short-circuiting on
+	// the TTL cache from the first call (ai_cache.go's AIPredictionCacheTTL).
+	req2, _ := http.NewRequest("GET", "/api/ai/budget-predictions?refresh=true", nil)
+	req2.Header.Set("Authorization", "Bearer "+token)
+	w2 := httptest.NewRecorder()
+	suite.router.ServeHTTP(w2, req2)
+	suite.Equal(http.StatusOK, w2.Code)
+
+	var second map[string]interface{}
+	json.Unmarshal(w2.Body.Bytes(), &second)
+	suite.Equal(true, second["stale"])
+	suite.Equal(first["predictions"], second["predictions"])
+}
+
+func (suite *AIControllerTestSuite) TestGetBudgetPrediction_StaleOnErrorFallsBackToMovingAverage() {
+	resetAIResilience()
+	defer resetAIResilience()
+
+	_, token := seedAIResilienceUser(suite.T(), suite.database, "stale-fallback-user")
+
+	ctrl := gomock.NewController(suite.T())
+	defer ctrl.Finish()
+
+	originalPredictor := controllers.AIPredictor
+	defer func() { controllers.AIPredictor = originalPredictor }()
+
+	mockPredictor := mocks.NewMockAIPredictor(ctrl)
+	mockPredictor.EXPECT().Predict(gomock.Any(), gomock.Any()).Return(ai.PredictResponse{}, errors.New("ai backend unreachable")).Times(1)
+	controllers.AIPredictor = func() ai.Predictor { return mockPredictor }
+
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
 	suite.Equal(http.StatusOK, w.Code)
 
 	var response map[string]interface{}
 	json.Unmarshal(w.Body.Bytes(), &response)
+	suite.Equal(true, response["stale"])
 
-	// Should still get successful response
-	suite.Contains(response, "patterns")
-	suite.Equal(float64(suite.user.ID), response["user_id"])
+	predictions, ok := response["predictions"].([]interface{})
+	suite.Require().True(ok)
+	suite.Require().Len(predictions, 1)
+	prediction := predictions[0].(map[string]interface{})
+	suite.Equal("Groceries", prediction["CategoryName"])
 }
 
-// ============================================
-// TEST 4: Spending Patterns Error Cases
-// ============================================
-func (suite *AIControllerTestSuite) TestGetSpendingPatterns_Unauthorized() {
-	req, _ := http.NewRequest("GET", "/api/ai/spending-patterns", nil)
+func (suite *AIControllerTestSuite) TestGetBudgetPrediction_OpenBreakerFastFailsWithoutCallingPredictor() {
+	resetAIResilience()
+	defer resetAIResilience()
+	controllers.AIBreakerFailureThreshold = 1
+
+	_, token := seedAIResilienceUser(suite.T(), suite.database, "open-breaker-user")
+
+	ctrl := gomock.NewController(suite.T())
+	defer ctrl.Finish()
+
+	originalPredictor := controllers.AIPredictor
+	defer func() { controllers.AIPredictor = originalPredictor }()
+
+	mockPredictor := mocks.NewMockAIPredictor(ctrl)
+	mockPredictor.EXPECT().Predict(gomock.Any(), gomock.Any()).
+		Return(ai.PredictResponse{}, errors.New("ai backend unreachable")).
+		Times(1) // only the first call should ever reach the predictor
+	controllers.AIPredictor = func() ai.Predictor { return mockPredictor }
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Equal(http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		suite.Equal(true, response["stale"])
+	}
+}
+
+func (suite *AIControllerTestSuite) TestGetBudgetPrediction_HalfOpenRecoveryClosesBreakerOnSuccess() {
+	resetAIResilience()
+	defer resetAIResilience()
+	controllers.AIBreakerFailureThreshold = 1
+	controllers.AIBreakerCooldown = 20 * time.Millisecond
+
+	_, token := seedAIResilienceUser(suite.T(), suite.database, "half-open-user")
+
+	ctrl := gomock.NewController(suite.T())
+	defer ctrl.Finish()
+
+	originalPredictor := controllers.AIPredictor
+	defer func() { controllers.AIPredictor = originalPredictor }()
+
+	healthy := ai.PredictResponse{
+		Predictions: []ai.BudgetPrediction{{CategoryID: 2, CategoryName: "Recovered", PredictedAmount: money.FromCents(1000)}},
+		Message:     "Predictions generated successfully",
+	}
+	mockPredictor := mocks.NewMockAIPredictor(ctrl)
+	gomock.InOrder(
+		mockPredictor.EXPECT().Predict(gomock.Any(), gomock.Any()).Return(ai.PredictResponse{}, errors.New("ai backend unreachable")),
+		mockPredictor.EXPECT().Predict(gomock.Any(), gomock.Any()).Return(healthy, nil),
+	)
+	controllers.AIPredictor = func() ai.Predictor { return mockPredictor }
+
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
 
-	suite.Equal(http.StatusUnauthorized, w.Code)
+	time.Sleep(controllers.AIBreakerCooldown + 10*time.Millisecond)
+
+	req2, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req2.Header.Set("Authorization", "Bearer "+token)
+	w2 := httptest.NewRecorder()
+	suite.router.ServeHTTP(w2, req2)
+	suite.Equal(http.StatusOK, w2.Code)
 
 	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	suite.Contains(response["error"], "missing authorization header")
+	json.Unmarshal(w2.Body.Bytes(), &response)
+	suite.Equal(false, response["stale"])
 }
 
-// ============================================
-// TEST 5: AI Service Error Handling
-// ============================================
-func (suite *AIControllerTestSuite) TestAIService_InternalError() {
-	// Create a special user with ID 999 that triggers AI service errors
-	userWithError := models.User{
-		ID:           999,
-		Username:     "erroruser",
-		Email:        "error@example.com",
-		PasswordHash: "hash",
-		Role:         models.UserRoleUser,
+// seedAnomalyCategory gives user a category with 5 ordinary transactions and
+// one outlier far enough from the rest to clear anomaly.DefaultThreshold,
+// and returns the outlier's transaction ID.
+func seedAnomalyCategory(t *testing.T, database *gorm.DB, user *models.User) uint {
+	t.Helper()
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: models.AccountChecking, Currency: "USD"}
+	require.NoError(t, database.Create(&account).Error)
+	category := models.Category{UserID: user.ID, Name: "Dining", Kind: models.CategoryExpense}
+	require.NoError(t, database.Create(&category).Error)
+
+	amounts := []int64{-2000, -2100, -1900, -2050, -1950, -50000}
+	var outlierID uint
+	for i, amount := range amounts {
+		txn := models.Transaction{
+			UserID:      user.ID,
+			AccountID:   account.ID,
+			CategoryID:  &category.ID,
+			Amount:      decimal.NewFromInt(amount).Div(decimal.NewFromInt(100)),
+			Description: "dining",
+			TxnDate:     time.Now().AddDate(0, 0, -i),
+			Status:      models.TransactionEntered,
+		}
+		require.NoError(t, database.Create(&txn).Error)
+		if amount == -50000 {
+			outlierID = txn.ID
+		}
 	}
-	suite.database.Create(&userWithError)
+	return outlierID
+}
 
-	token := GetTestToken(userWithError.ID, userWithError.Username)
+func (suite *AIControllerTestSuite) TestGetAnomalies_FlagsOutlier() {
+	user, token := seedAIResilienceUser(suite.T(), suite.database, "anomaly-get-user")
+	outlierID := seedAnomalyCategory(suite.T(), suite.database, user)
 
-	// Test budget prediction error
-	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
+	req, _ := http.NewRequest("GET", "/api/ai/anomalies", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
-
-	suite.Equal(http.StatusInternalServerError, w.Code)
+	suite.Equal(http.StatusOK, w.Code)
 
 	var response map[string]interface{}
 	json.Unmarshal(w.Body.Bytes(), &response)
-	suite.Contains(response["error"], "AI service internal error")
+	results, ok := response["anomalies"].([]interface{})
+	suite.Require().True(ok)
+	suite.Require().NotEmpty(results)
 
-	// Test spending patterns error
-	req, _ = http.NewRequest("GET", "/api/ai/spending-patterns", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w = httptest.NewRecorder()
+	first := results[0].(map[string]interface{})
+	suite.Equal(float64(outlierID), first["transaction_id"])
+	suite.Greater(first["score"].(float64), anomaly.DefaultThreshold)
+}
+
+func (suite *AIControllerTestSuite) TestGetAnomalies_Unauthorized() {
+	req, _ := http.NewRequest("GET", "/api/ai/anomalies", nil)
+	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
 
-	suite.Equal(http.StatusServiceUnavailable, w.Code)
+	suite.Equal(http.StatusUnauthorized, w.Code)
+}
 
-	json.Unmarshal(w.Body.Bytes(), &response)
-	suite.Contains(response["error"], "AI service temporarily unavailable")
+// TestAnomalyWebhookDelivery_SignsPayloadWithRegisteredSecret registers a
+// webhook pointed at an httptest.Server standing in for the user's own
+// endpoint, runs a real anomaly.Detect against seeded data, and confirms
+// NotifyAnomalies delivers an HMAC-SHA256-signed POST the receiver can
+// verify - the same roundtrip a production webhook consumer would do.
+func (suite *AIControllerTestSuite) TestAnomalyWebhookDelivery_SignsPayloadWithRegisteredSecret() {
+	user, token := seedAIResilienceUser(suite.T(), suite.database, "anomaly-webhook-user")
+	seedAnomalyCategory(suite.T(), suite.database, user)
+
+	received := make(chan []byte, 1)
+	var receivedSignature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receivedSignature = r.Header.Get("X-Anomaly-Signature")
+		received <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
 
-	// Cleanup
-	suite.database.Delete(&userWithError)
+	createBody, _ := json.Marshal(map[string]interface{}{"url": server.URL, "min_score": 1.0})
+	req, _ := http.NewRequest("POST", "/api/ai/anomaly-webhooks", bytes.NewReader(createBody))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusCreated, w.Code)
+
+	var createResp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &createResp)
+	secret := createResp["secret"].(string)
+	suite.Require().NotEmpty(secret)
+
+	anomalies, err := anomaly.Detect(context.Background(), user.ID, 12, anomaly.DefaultThreshold)
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(anomalies)
+
+	anomaly.NotifyAnomalies(user.ID, anomalies)
+
+	select {
+	case body := <-received:
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := hex.EncodeToString(mac.Sum(nil))
+		suite.Equal(expected, receivedSignature)
+
+		var payload map[string]interface{}
+		json.Unmarshal(body, &payload)
+		suite.Equal(float64(anomalies[0].TransactionID), payload["transaction_id"])
+	case <-time.After(2 * time.Second):
+		suite.Fail("webhook was never delivered")
+	}
 }
 
-func (suite *AIControllerTestSuite) TestAIService_Unavailable() {
-	// Temporarily close the mock server to simulate service unavailable
-	suite.mockAIServer.Close()
+func (suite *AIControllerTestSuite) TestGetPredictionHistory_FiltersByCategoryID() {
+	user, token := seedAIResilienceUser(suite.T(), suite.database, "history-user")
 
-	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions", nil)
-	req.Header.Set("Authorization", "Bearer "+suite.userToken)
+	require.NoError(suite.T(), suite.database.Create(&models.BudgetPrediction{
+		UserID: user.ID, CategoryID: 1, TargetMonth: 7, TargetYear: 2026,
+		PredictedCents: 5000, ModelVersion: "local", GeneratedAt: time.Now(),
+	}).Error)
+	require.NoError(suite.T(), suite.database.Create(&models.BudgetPrediction{
+		UserID: user.ID, CategoryID: 2, TargetMonth: 7, TargetYear: 2026,
+		PredictedCents: 3000, ModelVersion: "local", GeneratedAt: time.Now(),
+	}).Error)
+
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions/history", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
-
-	suite.Equal(http.StatusServiceUnavailable, w.Code)
+	suite.Equal(http.StatusOK, w.Code)
 
 	var response map[string]interface{}
 	json.Unmarshal(w.Body.Bytes(), &response)
-	suite.Contains(response["error"], "AI service unavailable")
+	all, ok := response["predictions"].([]interface{})
+	suite.Require().True(ok)
+	suite.Len(all, 2)
+
+	req, _ = http.NewRequest("GET", "/api/ai/budget-predictions/history?category_id=1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
 
-	// Restart the mock server for subsequent tests
-	suite.setupMockAIServer()
+	json.Unmarshal(w.Body.Bytes(), &response)
+	filtered, ok := response["predictions"].([]interface{})
+	suite.Require().True(ok)
+	suite.Require().Len(filtered, 1)
+	suite.Equal(float64(1), filtered[0].(map[string]interface{})["category_id"])
 }
 
-// ============================================
-// TEST 6: Query Parameter Validation
-// ============================================
-func (suite *AIControllerTestSuite) TestBudgetPrediction_QueryParameterValidation() {
-	testCases := []struct {
-		name          string
-		queryParams   string
-		expectedMonth int
-		expectedYear  int
-		description   string
-	}{
-		{
-			name:          "Valid parameters",
-			queryParams:   "target_month=3&target_year=2024&historical_months=6",
-			expectedMonth: 3,
-			expectedYear:  2024,
-			description:   "Should accept valid parameters",
-		},
-		{
-			name:          "Month out of range high",
-			queryParams:   "target_month=13",
-			expectedMonth: int(time.Now().Month()),
-			expectedYear:  time.Now().Year(),
-			description:   "Should use defaults for month > 12",
-		},
-		{
-			name:          "Month out of range low",
-			queryParams:   "target_month=0",
-			expectedMonth: int(time.Now().Month()),
-			expectedYear:  time.Now().Year(),
-			description:   "Should use defaults for month < 1",
-		},
-		{
-			name:          "Year out of range low",
-			queryParams:   "target_year=2010",
-			expectedMonth: int(time.Now().Month()),
-			expectedYear:  time.Now().Year(),
-			description:   "Should use defaults for year < 2020",
-		},
-		{
-			name:          "Year out of range high",
-			queryParams:   "target_year=2040",
-			expectedMonth: int(time.Now().Month()),
-			expectedYear:  time.Now().Year(),
-			description:   "Should use defaults for year > 2030",
-		},
-	}
+func (suite *AIControllerTestSuite) TestGetPredictionHistory_Unauthorized() {
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions/history", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
 
-	for _, tc := range testCases {
-		suite.Run(tc.name, func() {
-			url := "/api/ai/budget-predictions"
-			if tc.queryParams != "" {
-				url += "?" + tc.queryParams
-			}
+	suite.Equal(http.StatusUnauthorized, w.Code)
+}
 
-			req, _ := http.NewRequest("GET", url, nil)
-			req.Header.Set("Authorization", "Bearer "+suite.userToken)
-			w := httptest.NewRecorder()
-			suite.router.ServeHTTP(w, req)
+func (suite *AIControllerTestSuite) TestGetPredictionAccuracy_ComputesMAPEAndBiasAgainstRealizedSpend() {
+	user, token := seedAIResilienceUser(suite.T(), suite.database, "accuracy-user")
 
-			suite.Equal(http.StatusOK, w.Code, tc.description)
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: models.AccountChecking, Currency: "USD"}
+	require.NoError(suite.T(), suite.database.Create(&account).Error)
+	category := models.Category{UserID: user.ID, Name: "Entertainment", Kind: models.CategoryExpense}
+	require.NoError(suite.T(), suite.database.Create(&category).Error)
 
-			var response map[string]interface{}
-			json.Unmarshal(w.Body.Bytes(), &response)
+	now := time.Now()
+	require.NoError(suite.T(), suite.database.Create(&models.Transaction{
+		UserID: user.ID, AccountID: account.ID, CategoryID: &category.ID,
+		Amount: decimal.NewFromFloat(-50.00), Description: "movies",
+		TxnDate: now, Status: models.TransactionEntered,
+	}).Error)
+	require.NoError(suite.T(), suite.database.Create(&models.BudgetPrediction{
+		UserID: user.ID, CategoryID: category.ID,
+		TargetMonth: int(now.Month()), TargetYear: now.Year(),
+		PredictedCents: 6000, ConfidenceScore: 0.5, ModelVersion: "local", GeneratedAt: now,
+	}).Error)
+
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions/accuracy?months=1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
 
-			suite.Equal(float64(tc.expectedMonth), response["target_month"], tc.description+" - month")
-			suite.Equal(float64(tc.expectedYear), response["target_year"], tc.description+" - year")
-		})
-	}
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	rows, ok := response["accuracy"].([]interface{})
+	suite.Require().True(ok)
+	suite.Require().Len(rows, 1)
+
+	row := rows[0].(map[string]interface{})
+	suite.Equal(float64(category.ID), row["category_id"])
+	suite.Equal(float64(1), row["samples"])
+	suite.Equal(1000.0, row["bias_cents"])
+	suite.Equal(20.0, row["mape_percent"])
+	suite.Equal(1000.0, row["rmse_cents"])
 }
 
-func (suite *AIControllerTestSuite) TestSpendingPatterns_HistoricalMonthsValidation() {
-	testCases := []struct {
-		name        string
-		queryParam  string
-		description string
-		shouldWork  bool
-	}{
-		{
-			name:        "Valid historical months",
-			queryParam:  "historical_months=12",
-			description: "Should accept valid historical months",
-			shouldWork:  true,
-		},
-		{
-			name:        "Historical months too low",
-			queryParam:  "historical_months=0",
-			description: "Should use default for historical_months < 1",
-			shouldWork:  true,
-		},
-		{
-			name:        "Historical months too high",
-			queryParam:  "historical_months=50",
-			description: "Should use default for historical_months > 36",
-			shouldWork:  true,
-		},
-		{
-			name:        "Invalid historical months",
-			queryParam:  "historical_months=invalid",
-			description: "Should use default for non-numeric historical_months",
-			shouldWork:  true,
-		},
-	}
+func (suite *AIControllerTestSuite) TestGetPredictionAccuracy_Unauthorized() {
+	req, _ := http.NewRequest("GET", "/api/ai/budget-predictions/accuracy", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
 
-	for _, tc := range testCases {
-		suite.Run(tc.name, func() {
-			url := "/api/ai/spending-patterns?" + tc.queryParam
-
-			req, _ := http.NewRequest("GET", url, nil)
-			req.Header.Set("Authorization", "Bearer "+suite.userToken)
-			w := httptest.NewRecorder()
-			suite.router.ServeHTTP(w, req)
-
-			if tc.shouldWork {
-				suite.Equal(http.StatusOK, w.Code, tc.description)
-			} else {
-				suite.NotEqual(http.StatusOK, w.Code, tc.description)
-			}
-		})
-	}
+	suite.Equal(http.StatusUnauthorized, w.Code)
 }
 
-// TestAIControllerTestSuite runs the AI controller test suite
 func TestAIControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(AIControllerTestSuite))
 }
@@ -0,0 +1,134 @@
+package controllers_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+)
+
+// parseSSEFrames splits a raw SSE body into its id:/retry:/event:/data:
+// frames, in the order they were written - used to assert
+// GetSpendingPatternsStream emits partial/insight/recommendation/done in
+// order with a monotonically increasing id and a retry hint, the way a
+// reconnecting EventSource depends on.
+type sseFrame struct {
+	id    int
+	retry int
+	event string
+	data  string
+}
+
+func parseSSEFrames(body string) []sseFrame {
+	var frames []sseFrame
+	for _, block := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		var frame sseFrame
+		for _, line := range strings.Split(block, "\n") {
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				frame.id, _ = strconv.Atoi(strings.TrimPrefix(line, "id: "))
+			case strings.HasPrefix(line, "retry: "):
+				frame.retry, _ = strconv.Atoi(strings.TrimPrefix(line, "retry: "))
+			case strings.HasPrefix(line, "event: "):
+				frame.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				frame.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// streamGet issues a real HTTP GET against an httptest.Server wrapping
+// suite.router - c.Stream relies on http.CloseNotifier, which
+// httptest.NewRecorder doesn't implement, so these tests need a live
+// connection the way a real EventSource client would use.
+func (suite *AIControllerTestSuite) streamGet(path string, headers map[string]string) (int, string) {
+	server := httptest.NewServer(suite.router)
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+path, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := server.Client().Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+	return resp.StatusCode, string(body)
+}
+
+func (suite *AIControllerTestSuite) TestGetSpendingPatternsStream_EmitsEventsInOrderWithIDAndRetry() {
+	status, body := suite.streamGet("/api/ai/spending-patterns/stream", map[string]string{
+		"Authorization": "Bearer " + suite.userToken,
+	})
+	suite.Equal(http.StatusOK, status)
+
+	frames := parseSSEFrames(body)
+	suite.Require().NotEmpty(frames)
+
+	suite.Equal("partial", frames[0].event)
+	suite.Equal("done", frames[len(frames)-1].event)
+
+	for i, frame := range frames {
+		suite.Equal(i+1, frame.id)
+		suite.Equal(3000, frame.retry)
+		suite.NotEmpty(frame.data)
+	}
+}
+
+func (suite *AIControllerTestSuite) TestGetSpendingPatterns_AcceptEventStreamHeaderDelegatesToStream() {
+	status, body := suite.streamGet("/api/ai/spending-patterns", map[string]string{
+		"Authorization": "Bearer " + suite.userToken,
+		"Accept":        "text/event-stream",
+	})
+	suite.Equal(http.StatusOK, status)
+	suite.Contains(body, "event: done")
+}
+
+func (suite *AIControllerTestSuite) TestGetSpendingPatternsStream_Unauthorized() {
+	status, _ := suite.streamGet("/api/ai/spending-patterns/stream", nil)
+	suite.Equal(http.StatusUnauthorized, status)
+}
+
+func (suite *AIControllerTestSuite) TestGetBudgetPredictionStream_EmitsOnePredictionEventPerCategoryThenDone() {
+	status, body := suite.streamGet("/api/ai/budget-predictions/stream", map[string]string{
+		"Authorization": "Bearer " + suite.userToken,
+	})
+	suite.Equal(http.StatusOK, status)
+
+	frames := parseSSEFrames(body)
+	suite.Require().NotEmpty(frames)
+
+	suite.Equal("done", frames[len(frames)-1].event)
+	for _, frame := range frames[:len(frames)-1] {
+		suite.Equal("prediction", frame.event)
+	}
+
+	for i, frame := range frames {
+		suite.Equal(i+1, frame.id)
+		suite.Equal(3000, frame.retry)
+		suite.NotEmpty(frame.data)
+	}
+}
+
+func (suite *AIControllerTestSuite) TestGetBudgetPrediction_AcceptEventStreamHeaderDelegatesToStream() {
+	status, body := suite.streamGet("/api/ai/budget-predictions", map[string]string{
+		"Authorization": "Bearer " + suite.userToken,
+		"Accept":        "text/event-stream",
+	})
+	suite.Equal(http.StatusOK, status)
+	suite.Contains(body, "event: done")
+}
+
+func (suite *AIControllerTestSuite) TestGetBudgetPredictionStream_Unauthorized() {
+	status, _ := suite.streamGet("/api/ai/budget-predictions/stream", nil)
+	suite.Equal(http.StatusUnauthorized, status)
+}
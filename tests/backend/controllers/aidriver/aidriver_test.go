@@ -0,0 +1,119 @@
+package aidriver_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/controllers/aidriver"
+	"Personal-Finance-Tracker-backend/pkg/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterAndNew_RoundTrips(t *testing.T) {
+	aidriver.Register("test-roundtrip", func(cfg aidriver.Config) (aidriver.Driver, error) {
+		return stubDriver{cfg: cfg}, nil
+	})
+
+	d, err := aidriver.New("test-roundtrip", aidriver.Config{APIKey: "abc"})
+	require.NoError(t, err)
+	require.NotNil(t, d)
+
+	resp, err := d.PredictBudget(context.Background(), aidriver.PredictRequest{UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", resp.Message)
+}
+
+func TestNew_UnknownDriverReturnsError(t *testing.T) {
+	_, err := aidriver.New("does-not-exist", aidriver.Config{})
+	require.Error(t, err)
+}
+
+func TestRegister_SameNameTwiceUsesLatestFactory(t *testing.T) {
+	aidriver.Register("test-overwrite", func(aidriver.Config) (aidriver.Driver, error) {
+		return stubDriver{cfg: aidriver.Config{APIKey: "first"}}, nil
+	})
+	aidriver.Register("test-overwrite", func(aidriver.Config) (aidriver.Driver, error) {
+		return stubDriver{cfg: aidriver.Config{APIKey: "second"}}, nil
+	})
+
+	d, err := aidriver.New("test-overwrite", aidriver.Config{})
+	require.NoError(t, err)
+	resp, err := d.PredictBudget(context.Background(), aidriver.PredictRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "second", resp.Message)
+}
+
+func TestLocalDriver_RegisteredUnderLocalAndSidecar(t *testing.T) {
+	for _, name := range []string{"local", "sidecar"} {
+		d, err := aidriver.New(name, aidriver.Config{})
+		require.NoErrorf(t, err, "driver %q", name)
+		require.NotNil(t, d)
+	}
+}
+
+// stubDriver reports the Config it was built with via PredictResponse.Message
+// so tests can assert New passed Config through to the registered factory.
+type stubDriver struct {
+	cfg aidriver.Config
+}
+
+func (s stubDriver) PredictBudget(ctx context.Context, req aidriver.PredictRequest) (aidriver.PredictResponse, error) {
+	return aidriver.PredictResponse{Message: s.cfg.APIKey}, nil
+}
+
+func (s stubDriver) AnalyzePatterns(ctx context.Context, req aidriver.PatternsRequest) (aidriver.PatternsResponse, error) {
+	return aidriver.PatternsResponse{}, nil
+}
+
+func TestOpenAIDriver_PredictBudget_ParsesFunctionCallArguments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		args, err := json.Marshal(map[string]interface{}{
+			"Predictions": []map[string]interface{}{
+				{"CategoryID": 1, "CategoryName": "Dining", "PredictedAmount": map[string]interface{}{"cents": 5000}},
+			},
+			"TargetPeriod": "2026-07",
+			"UserID":       1,
+		})
+		require.NoError(t, err)
+
+		resp := map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{
+					"message": map[string]interface{}{
+						"tool_calls": []map[string]interface{}{
+							{"function": map[string]interface{}{"arguments": string(args)}},
+						},
+					},
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer server.Close()
+
+	d, err := aidriver.New("openai", aidriver.Config{APIKey: "test-key", BaseURL: server.URL})
+	require.NoError(t, err)
+
+	resp, err := d.PredictBudget(context.Background(), aidriver.PredictRequest{
+		UserID: 1, TargetPeriod: timeutil.Of(2026, 7), HistoricalMonths: 12,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Predictions, 1)
+	assert.Equal(t, uint(1), resp.Predictions[0].CategoryID)
+	assert.Equal(t, "Dining", resp.Predictions[0].CategoryName)
+	assert.Equal(t, int64(5000), resp.Predictions[0].PredictedAmount.Cents())
+	assert.Equal(t, 2026, resp.TargetPeriod.Year)
+}
+
+func TestOpenAIDriver_RequiresAPIKey(t *testing.T) {
+	_, err := aidriver.New("openai", aidriver.Config{})
+	require.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package aidriver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/controllers/aidriver"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAIToolCallResponse writes a minimal valid openai chat-completions
+// response carrying a PredictResponse-shaped tool call, the same payload
+// TestOpenAIDriver_PredictBudget_ParsesFunctionCallArguments expects.
+func openAIToolCallResponse(t *testing.T, w http.ResponseWriter) {
+	t.Helper()
+	args, err := json.Marshal(map[string]interface{}{
+		"Predictions":  []map[string]interface{}{},
+		"TargetPeriod": "2026-07", "UserID": 1,
+	})
+	require.NoError(t, err)
+	resp := map[string]interface{}{
+		"choices": []map[string]interface{}{
+			{"message": map[string]interface{}{
+				"tool_calls": []map[string]interface{}{
+					{"function": map[string]interface{}{"arguments": string(args)}},
+				},
+			}},
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(t, json.NewEncoder(w).Encode(resp))
+}
+
+func TestOpenAIDriver_RetriesOn5xxThenSucceeds(t *testing.T) {
+	t.Setenv("AI_SERVICE_RETRIES", "3")
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		openAIToolCallResponse(t, w)
+	}))
+	defer server.Close()
+
+	d, err := aidriver.New("openai", aidriver.Config{APIKey: "test-key", BaseURL: server.URL})
+	require.NoError(t, err)
+
+	_, err = d.PredictBudget(context.Background(), aidriver.PredictRequest{UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestOpenAIDriver_ExhaustsRetriesReturnsUpstreamUnavailable(t *testing.T) {
+	t.Setenv("AI_SERVICE_RETRIES", "1")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	d, err := aidriver.New("openai", aidriver.Config{APIKey: "test-key", BaseURL: server.URL})
+	require.NoError(t, err)
+
+	_, err = d.PredictBudget(context.Background(), aidriver.PredictRequest{UserID: 1})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, aidriver.ErrUpstreamUnavailable))
+}
+
+func TestOpenAIDriver_4xxIsNotRetriedAndSurfacesStatusCode(t *testing.T) {
+	t.Setenv("AI_SERVICE_RETRIES", "3")
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	d, err := aidriver.New("openai", aidriver.Config{APIKey: "test-key", BaseURL: server.URL})
+	require.NoError(t, err)
+
+	_, err = d.PredictBudget(context.Background(), aidriver.PredictRequest{UserID: 1})
+	require.Error(t, err)
+
+	var statusErr *aidriver.UpstreamStatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestOllamaDriver_SendsAIServiceAPIKeyAsBearerToken(t *testing.T) {
+	os.Setenv("AI_SERVICE_API_KEY", "gateway-secret")
+	defer os.Unsetenv("AI_SERVICE_API_KEY")
+
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"response": `{"Predictions":[],"TargetPeriod":"2026-07","UserID":1}`,
+		})
+	}))
+	defer server.Close()
+
+	d, err := aidriver.New("ollama", aidriver.Config{BaseURL: server.URL})
+	require.NoError(t, err)
+
+	_, err = d.PredictBudget(context.Background(), aidriver.PredictRequest{UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer gateway-secret", gotAuth)
+}
@@ -3,19 +3,31 @@ package controllers_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 )
 
 // AuthEndpointsTestSuite defines the test suite for auth endpoint validation tests
 type AuthEndpointsTestSuite struct {
 	suite.Suite
 	router *gin.Engine
+	// liveRouter wires the real Register/Login handlers (with the
+	// production rate-limit middleware) against an in-memory DB, for the
+	// lockout/throttling tests that a pure-validation mock can't exercise.
+	liveRouter *gin.Engine
+	database   *gorm.DB
 }
 
 // SetupSuite is called once before all tests in the suite
@@ -38,6 +50,24 @@ func (suite *AuthEndpointsTestSuite) SetupSuite() {
 			auth.POST("/login", suite.mockLoginHandler)
 		}
 	}
+
+	suite.database = SetupTestDB()
+	db.DB = suite.database
+	suite.Require().NoError(db.DB.AutoMigrate(&models.AuthAuditEvent{}))
+	hash, err := controllers.HashPassword("lockouttarget123")
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.DB.Create(&models.User{
+		Username:     "lockouttarget",
+		Email:        "lockouttarget@example.com",
+		PasswordHash: hash,
+	}).Error)
+
+	suite.liveRouter = gin.New()
+	liveAuth := suite.liveRouter.Group("/api/auth")
+	{
+		liveAuth.POST("/register", middleware.RateLimit(middleware.IPRouteKey, middleware.RegistrationRate), controllers.Register)
+		liveAuth.POST("/login", middleware.RateLimit(middleware.IPRouteKey, middleware.LoginRate), controllers.Login)
+	}
 }
 
 // mockRegisterHandler simulates registration validation without database
@@ -407,6 +437,176 @@ func (suite *AuthEndpointsTestSuite) TestCompleteRegistrationLoginFlow() {
 	suite.Equal(http.StatusUnauthorized, recorder.Code)
 }
 
+// TestLoginRateLimit_ThrottlesByIP fires one request over the login burst
+// (5/min) from a single IP against the real Login handler and expects a
+// 429 with Retry-After once the bucket is exhausted.
+func (suite *AuthEndpointsTestSuite) TestLoginRateLimit_ThrottlesByIP() {
+	var last *httptest.ResponseRecorder
+	for i := 0; i < 6; i++ {
+		body, _ := json.Marshal(map[string]interface{}{
+			"username": "nonexistent",
+			"password": "whatever123",
+		})
+		req, _ := http.NewRequest("POST", "/api/auth/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "198.51.100.10:12345"
+		w := httptest.NewRecorder()
+		suite.liveRouter.ServeHTTP(w, req)
+		last = w
+	}
+
+	suite.Equal(http.StatusTooManyRequests, last.Code)
+	suite.NotEmpty(last.Header().Get("Retry-After"))
+}
+
+// TestRegistrationRateLimit_ThrottlesByIP fires one request over the
+// registration burst (20/hour) from a single IP and expects a 429.
+func (suite *AuthEndpointsTestSuite) TestRegistrationRateLimit_ThrottlesByIP() {
+	var last *httptest.ResponseRecorder
+	for i := 0; i < 21; i++ {
+		body, _ := json.Marshal(map[string]interface{}{
+			"username": "regflood",
+			"email":    "regflood@example.com",
+			"password": "password123",
+		})
+		req, _ := http.NewRequest("POST", "/api/auth/register", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "198.51.100.20:12345"
+		w := httptest.NewRecorder()
+		suite.liveRouter.ServeHTTP(w, req)
+		last = w
+	}
+
+	suite.Equal(http.StatusTooManyRequests, last.Code)
+	suite.NotEmpty(last.Header().Get("Retry-After"))
+}
+
+// TestLoginLockout_LocksOutAfterRepeatedFailures fires 11 failed logins for
+// one username - each from a distinct IP so the per-IP LoginRate limiter
+// (5/min) doesn't trip first. The 10th failure (the threshold) locks the
+// username in controllers/login_lockout.go, so the attempt after it gets a
+// 429, and even a correct password keeps being rejected while locked.
+func (suite *AuthEndpointsTestSuite) TestLoginLockout_LocksOutAfterRepeatedFailures() {
+	var last *httptest.ResponseRecorder
+	for i := 0; i < 11; i++ {
+		body, _ := json.Marshal(map[string]interface{}{
+			"username": "lockouttarget",
+			"password": "wrong-password",
+		})
+		req, _ := http.NewRequest("POST", "/api/auth/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = fmt.Sprintf("203.0.113.%d:12345", i+1)
+		w := httptest.NewRecorder()
+		suite.liveRouter.ServeHTTP(w, req)
+		last = w
+	}
+
+	suite.Equal(http.StatusTooManyRequests, last.Code)
+	suite.NotEmpty(last.Header().Get("Retry-After"))
+
+	// Even the correct password is rejected while locked out.
+	body, _ := json.Marshal(map[string]interface{}{
+		"username": "lockouttarget",
+		"password": "lockouttarget123",
+	})
+	req, _ := http.NewRequest("POST", "/api/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "203.0.113.99:12345"
+	w := httptest.NewRecorder()
+	suite.liveRouter.ServeHTTP(w, req)
+	suite.Equal(http.StatusTooManyRequests, w.Code)
+}
+
+// TestAuthAuditEvent_RecordsLoginOutcomes exercises a failed then a
+// successful login against the real Login handler and checks that both
+// land in the auth audit hash chain with the right event_type/outcome and
+// a non-empty hash. StartAuthAuditWorker is never called in this test
+// binary, so RecordAuthAuditEvent falls back to writing inline - no need
+// to wait for a background worker to drain.
+func (suite *AuthEndpointsTestSuite) TestAuthAuditEvent_RecordsLoginOutcomes() {
+	suite.database.Where("1 = 1").Delete(&models.AuthAuditEvent{})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"username": "lockouttarget",
+		"password": "wrong-password",
+	})
+	req, _ := http.NewRequest("POST", "/api/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "203.0.113.201:12345"
+	w := httptest.NewRecorder()
+	suite.liveRouter.ServeHTTP(w, req)
+	suite.Equal(http.StatusUnauthorized, w.Code)
+
+	body, _ = json.Marshal(map[string]interface{}{
+		"username": "lockouttarget",
+		"password": "lockouttarget123",
+	})
+	req, _ = http.NewRequest("POST", "/api/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "203.0.113.202:12345"
+	w = httptest.NewRecorder()
+	suite.liveRouter.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	var events []models.AuthAuditEvent
+	suite.database.Order("id ASC").Find(&events)
+	suite.Require().Len(events, 2)
+
+	suite.Equal("login", events[0].EventType)
+	suite.Equal("failure", events[0].Outcome)
+	suite.Empty(events[0].PrevHash)
+	suite.NotEmpty(events[0].Hash)
+
+	suite.Equal("login", events[1].EventType)
+	suite.Equal("success", events[1].Outcome)
+	suite.Equal(events[0].Hash, events[1].PrevHash)
+	suite.NotEmpty(events[1].Hash)
+}
+
+// TestAuthAuditChain_VerifyDetectsTamper checks that
+// VerifyAuthAuditChain reports valid:true over an untouched chain and
+// pinpoints the first record once one is tampered with.
+func (suite *AuthEndpointsTestSuite) TestAuthAuditChain_VerifyDetectsTamper() {
+	suite.database.Where("1 = 1").Delete(&models.AuthAuditEvent{})
+
+	for i := 0; i < 3; i++ {
+		body, _ := json.Marshal(map[string]interface{}{
+			"username": "lockouttarget",
+			"password": "lockouttarget123",
+		})
+		req, _ := http.NewRequest("POST", "/api/auth/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = fmt.Sprintf("203.0.113.%d:12345", 210+i)
+		w := httptest.NewRecorder()
+		suite.liveRouter.ServeHTTP(w, req)
+		suite.Equal(http.StatusOK, w.Code)
+	}
+
+	verifyRouter := gin.New()
+	verifyRouter.GET("/verify", controllers.VerifyAuthAuditChain)
+
+	req, _ := http.NewRequest("GET", "/verify", nil)
+	w := httptest.NewRecorder()
+	verifyRouter.ServeHTTP(w, req)
+	var result map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &result))
+	suite.Equal(true, result["valid"])
+
+	var events []models.AuthAuditEvent
+	suite.database.Order("id ASC").Find(&events)
+	suite.Require().True(len(events) >= 2)
+
+	tampered := events[0]
+	tampered.Outcome = "success"
+	suite.database.Save(&tampered)
+
+	w = httptest.NewRecorder()
+	verifyRouter.ServeHTTP(w, req)
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &result))
+	suite.Equal(false, result["valid"])
+	suite.Equal(float64(tampered.ID), result["broken_at"])
+}
+
 // TestAuthEndpointsTestSuite runs the auth endpoints test suite
 func TestAuthEndpointsTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthEndpointsTestSuite))
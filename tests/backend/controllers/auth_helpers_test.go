@@ -1,7 +1,11 @@
 package controllers_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -23,6 +27,22 @@ func (suite *AuthHelpersTestSuite) SetupSuite() {
 	// Set test environment
 	os.Setenv("JWT_SECRET", "test_secret_key_for_testing")
 	gin.SetMode(gin.TestMode)
+
+	// AuthMiddleware now confirms the token's subject still exists (so a
+	// soft-deleted/pending-deletion account is rejected) - back it with an
+	// in-memory DB containing the user id TestAuthMiddleware's tokens use.
+	// Its password hash is real (not a placeholder) so the session tests
+	// below can log in through the actual Login handler.
+	db.DB = SetupTestDB()
+	db.DB.AutoMigrate(&models.UserOTP{})
+	passwordHash, err := controllers.HashPassword("sessionpassword123")
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.DB.Create(&models.User{
+		ID:           123,
+		Username:     "testuser",
+		Email:        "testuser@example.com",
+		PasswordHash: passwordHash,
+	}).Error)
 }
 
 func (suite *AuthHelpersTestSuite) TestHashPassword() {
@@ -47,16 +67,20 @@ func (suite *AuthHelpersTestSuite) TestVerifyPassword() {
 	suite.NoError(err)
 
 	// Test correct password
-	valid := controllers.VerifyPassword(password, hash)
+	valid, needsRehash, err := controllers.VerifyPassword(password, hash)
 	suite.True(valid)
+	suite.False(needsRehash, "a hash just produced by HashPassword should already match the active profile")
+	suite.NoError(err)
 
 	// Test wrong password
-	invalid := controllers.VerifyPassword(wrongPassword, hash)
+	invalid, _, err := controllers.VerifyPassword(wrongPassword, hash)
 	suite.False(invalid)
+	suite.NoError(err)
 
 	// Test with malformed hash
-	invalidHash := controllers.VerifyPassword(password, "invalid$hash$format")
+	invalidHash, _, err := controllers.VerifyPassword(password, "invalid$hash$format")
 	suite.False(invalidHash)
+	suite.NoError(err)
 }
 
 func (suite *AuthHelpersTestSuite) TestGenerateToken() {
@@ -174,6 +198,141 @@ func (suite *AuthHelpersTestSuite) TestAuthMiddleware() {
 	}
 }
 
+func (suite *AuthHelpersTestSuite) newSessionRouter() *gin.Engine {
+	router := gin.New()
+	router.POST("/auth/login", controllers.Login)
+	router.POST("/auth/refresh", controllers.RefreshSession)
+	router.POST("/auth/logout", controllers.Logout)
+	router.POST("/auth/logout-all", controllers.AuthMiddleware(), controllers.LogoutAll)
+	return router
+}
+
+func (suite *AuthHelpersTestSuite) login(router *gin.Engine) (token, refreshToken string) {
+	body, _ := json.Marshal(map[string]string{"username": "testuser", "password": "sessionpassword123"})
+	req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	suite.Require().Equal(http.StatusOK, recorder.Code)
+
+	var response struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	suite.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &response))
+	suite.Require().NotEmpty(response.RefreshToken)
+	return response.Token, response.RefreshToken
+}
+
+// TestRefreshRotation covers that /auth/refresh rotates a refresh token:
+// the presented token yields a fresh pair, and the old token no longer
+// works once it's been rotated away from.
+func (suite *AuthHelpersTestSuite) TestRefreshRotation() {
+	router := suite.newSessionRouter()
+	_, refreshToken := suite.login(router)
+
+	refreshBody, _ := json.Marshal(map[string]string{"refresh_token": refreshToken})
+	req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(refreshBody))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	var rotated struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	suite.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &rotated))
+	suite.NotEmpty(rotated.Token)
+	suite.NotEmpty(rotated.RefreshToken)
+	suite.NotEqual(refreshToken, rotated.RefreshToken)
+
+	// The rotated-away-from token must no longer be accepted.
+	staleReq, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(refreshBody))
+	staleReq.Header.Set("Content-Type", "application/json")
+	staleRecorder := httptest.NewRecorder()
+	router.ServeHTTP(staleRecorder, staleReq)
+	suite.Equal(http.StatusUnauthorized, staleRecorder.Code)
+}
+
+// TestRefreshReuseDetection covers that replaying an already-rotated
+// refresh token revokes the whole family, so even the token it was rotated
+// into stops working (RFC 6819 reuse detection).
+func (suite *AuthHelpersTestSuite) TestRefreshReuseDetection() {
+	router := suite.newSessionRouter()
+	_, refreshToken := suite.login(router)
+
+	refreshBody, _ := json.Marshal(map[string]string{"refresh_token": refreshToken})
+	firstReq, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(refreshBody))
+	firstReq.Header.Set("Content-Type", "application/json")
+	firstRecorder := httptest.NewRecorder()
+	router.ServeHTTP(firstRecorder, firstReq)
+	suite.Require().Equal(http.StatusOK, firstRecorder.Code)
+
+	var rotated struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	suite.Require().NoError(json.Unmarshal(firstRecorder.Body.Bytes(), &rotated))
+
+	// Replay the original (already-rotated-away-from) token.
+	replayReq, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(refreshBody))
+	replayReq.Header.Set("Content-Type", "application/json")
+	replayRecorder := httptest.NewRecorder()
+	router.ServeHTTP(replayRecorder, replayReq)
+	suite.Equal(http.StatusUnauthorized, replayRecorder.Code)
+
+	// The token the family rotated into must also be dead now.
+	rotatedBody, _ := json.Marshal(map[string]string{"refresh_token": rotated.RefreshToken})
+	rotatedReq, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(rotatedBody))
+	rotatedReq.Header.Set("Content-Type", "application/json")
+	rotatedRecorder := httptest.NewRecorder()
+	router.ServeHTTP(rotatedRecorder, rotatedReq)
+	suite.Equal(http.StatusUnauthorized, rotatedRecorder.Code)
+}
+
+// TestLogoutEndsSession covers that /auth/logout kills the presented
+// refresh token's session so it can no longer be redeemed via /auth/refresh.
+func (suite *AuthHelpersTestSuite) TestLogoutEndsSession() {
+	router := suite.newSessionRouter()
+	_, refreshToken := suite.login(router)
+
+	logoutBody, _ := json.Marshal(map[string]string{"refresh_token": refreshToken})
+	logoutReq, _ := http.NewRequest("POST", "/auth/logout", bytes.NewBuffer(logoutBody))
+	logoutReq.Header.Set("Content-Type", "application/json")
+	logoutRecorder := httptest.NewRecorder()
+	router.ServeHTTP(logoutRecorder, logoutReq)
+	suite.Equal(http.StatusOK, logoutRecorder.Code)
+
+	refreshReq, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(logoutBody))
+	refreshReq.Header.Set("Content-Type", "application/json")
+	refreshRecorder := httptest.NewRecorder()
+	router.ServeHTTP(refreshRecorder, refreshReq)
+	suite.Equal(http.StatusUnauthorized, refreshRecorder.Code)
+}
+
+// TestLogoutAllEndsEverySession covers that /auth/logout-all kills every
+// session belonging to the caller, not just the one it was called with.
+func (suite *AuthHelpersTestSuite) TestLogoutAllEndsEverySession() {
+	router := suite.newSessionRouter()
+	token, refreshTokenA := suite.login(router)
+	_, refreshTokenB := suite.login(router)
+
+	logoutAllReq, _ := http.NewRequest("POST", "/auth/logout-all", nil)
+	logoutAllReq.Header.Set("Authorization", "Bearer "+token)
+	logoutAllRecorder := httptest.NewRecorder()
+	router.ServeHTTP(logoutAllRecorder, logoutAllReq)
+	suite.Equal(http.StatusOK, logoutAllRecorder.Code)
+
+	for _, rt := range []string{refreshTokenA, refreshTokenB} {
+		body, _ := json.Marshal(map[string]string{"refresh_token": rt})
+		req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		suite.Equal(http.StatusUnauthorized, recorder.Code)
+	}
+}
+
 // TestAuthHelpersTestSuite runs the auth helpers test suite
 func TestAuthHelpersTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthHelpersTestSuite))
@@ -0,0 +1,89 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/aggregators"
+	_ "Personal-Finance-Tracker-backend/aggregators/gocardless"
+	_ "Personal-Finance-Tracker-backend/aggregators/saltedge"
+	_ "Personal-Finance-Tracker-backend/aggregators/truelayer"
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateBankLinkSession_AcrossProviders runs the same behavioral
+// assertion - a stub aggregator's CreateLinkSession/ExchangeToken surface
+// as a 500 with their "not implemented yet" error - against every
+// provider-agnostic aggregator this repo registers besides Plaid (which has
+// its own plaid_api_test.go/plaid_oauth_test.go coverage through the
+// dedicated /api/plaid/* routes). Table-driven with t.Run/t.Parallel so
+// adding a fourth aggregator later is one more table row, not a new test
+// function.
+func TestCreateBankLinkSession_AcrossProviders(t *testing.T) {
+	providers := []string{"gocardless", "truelayer", "saltedge"}
+	for _, provider := range providers {
+		require.NoError(t, aggregators.Activate(provider, aggregators.Config{}))
+	}
+
+	database := SetupTestDB()
+	require.NoError(t, database.AutoMigrate(&models.BankConnection{}, &models.BankAccount{}))
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	router := SetupRouter()
+	authGroup := router.Group("/api")
+	authGroup.Use(controllers.AuthMiddleware())
+	authGroup.POST("/banks/link-session", controllers.CreateBankLinkSession)
+	authGroup.POST("/banks/exchange-token", controllers.ExchangeBankToken)
+
+	for _, provider := range providers {
+		provider := provider
+		t.Run(provider, func(t *testing.T) {
+			t.Parallel()
+
+			linkReq, _ := http.NewRequest("POST", "/api/banks/link-session?provider="+provider, nil)
+			linkReq.Header.Set("Authorization", "Bearer "+token)
+			linkW := httptest.NewRecorder()
+			router.ServeHTTP(linkW, linkReq)
+			assert.Equal(t, http.StatusInternalServerError, linkW.Code, linkW.Body.String())
+			assert.Contains(t, linkW.Body.String(), provider+" aggregator not implemented yet")
+
+			exchangeReq, _ := http.NewRequest("POST", "/api/banks/exchange-token?provider="+provider, bytes.NewReader([]byte(`{"public_token":"stub-token"}`)))
+			exchangeReq.Header.Set("Content-Type", "application/json")
+			exchangeReq.Header.Set("Authorization", "Bearer "+token)
+			exchangeW := httptest.NewRecorder()
+			router.ServeHTTP(exchangeW, exchangeReq)
+			assert.Equal(t, http.StatusInternalServerError, exchangeW.Code, exchangeW.Body.String())
+			assert.Contains(t, exchangeW.Body.String(), provider+" aggregator not implemented yet")
+		})
+	}
+}
+
+// TestCreateBankLinkSession_UnknownProviderRejected confirms an
+// unregistered ?provider= is rejected before reaching any aggregator.
+func TestCreateBankLinkSession_UnknownProviderRejected(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	router := SetupRouter()
+	authGroup := router.Group("/api")
+	authGroup.Use(controllers.AuthMiddleware())
+	authGroup.POST("/banks/link-session", controllers.CreateBankLinkSession)
+
+	req, _ := http.NewRequest("POST", "/api/banks/link-session?provider=does-not-exist", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
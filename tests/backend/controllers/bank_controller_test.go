@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"Personal-Finance-Tracker-backend/controllers"
 	"Personal-Finance-Tracker-backend/db"
 	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/banksync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/suite"
@@ -53,6 +55,7 @@ func (suite *BankControllerTestSuite) SetupSuite() {
 		&models.BankConnection{},
 		&models.BankAccount{},
 		&models.BankSyncLog{},
+		&models.BankAuditEvent{},
 	)
 	suite.Require().NoError(err)
 
@@ -94,8 +97,14 @@ func (suite *BankControllerTestSuite) SetupSuite() {
 	authGroup.Use(controllers.AuthMiddleware())
 	{
 		authGroup.GET("/banks/connections", controllers.GetBankConnections)
+		authGroup.POST("/banks/connections", controllers.CreateBankConnection)
+		authGroup.GET("/banks/connections/:id/callback", controllers.BankConnectionCallback)
+		authGroup.POST("/banks/connections/:id/sync", controllers.SyncBankConnection)
+		authGroup.POST("/banks/connections/:id/reauthenticate", controllers.ReauthenticateBankConnection)
 		authGroup.DELETE("/banks/connections/:id", controllers.DisconnectBank)
-		authGroup.POST("/banks/connections", controllers.CreateBankConnection) // Deprecated endpoint
+		authGroup.GET("/banks/connections/:id/audit", controllers.GetBankConnectionAuditLog)
+		authGroup.GET("/banks/health", controllers.GetBankHealth)
+		authGroup.POST("/banks/connections/:id/reset", controllers.ResetBankConnectionBreaker)
 	}
 }
 
@@ -103,6 +112,7 @@ func (suite *BankControllerTestSuite) SetupTest() {
 	// Clean up existing data properly
 	suite.database.Unscoped().Where("1 = 1").Delete(&models.BankAccount{})
 	suite.database.Unscoped().Where("1 = 1").Delete(&models.BankConnection{})
+	suite.database.Unscoped().Where("1 = 1").Delete(&models.BankAuditEvent{})
 
 	// Create test bank connections for normal user with unique consent IDs
 	suite.bankConnection1 = models.BankConnection{
@@ -418,11 +428,26 @@ func (suite *BankControllerTestSuite) TestDisconnectBank_UserIsolation() {
 }
 
 // ============================================
-// TEST 3: Create Bank Connection (Deprecated)
+// TEST 3: Create Bank Connection (PSD2 consent initiation)
 // ============================================
-func (suite *BankControllerTestSuite) TestCreateBankConnection_Deprecated() {
+// These only exercise the validation that runs before any call out to the
+// bank's XS2A API - a real consent round-trip needs network access this
+// suite doesn't have.
+func (suite *BankControllerTestSuite) TestCreateBankConnection_MissingBankName() {
 	req, _ := http.NewRequest("POST", "/api/banks/connections", nil)
 	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *BankControllerTestSuite) TestCreateBankConnection_UnsupportedBankName() {
+	body := []byte(`{"bank_name": "some_unsupported_bank"}`)
+	req, _ := http.NewRequest("POST", "/api/banks/connections", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
 
@@ -431,10 +456,135 @@ func (suite *BankControllerTestSuite) TestCreateBankConnection_Deprecated() {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	suite.NoError(err)
+	suite.Equal("unsupported bank_name", response["error"])
+}
+
+func (suite *BankControllerTestSuite) TestCreateBankConnection_Unauthorized() {
+	body := []byte(`{"bank_name": "sparebanken_norge"}`)
+	req, _ := http.NewRequest("POST", "/api/banks/connections", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
 
-	suite.Equal("This endpoint is deprecated. Please use Plaid Link instead.", response["error"])
-	suite.Equal("Use /api/plaid/create_link_token to connect banks via Plaid", response["message"])
-	suite.Equal("All bank connections now use Plaid for security and reliability", response["hint"])
+	suite.Equal(http.StatusUnauthorized, w.Code)
+}
+
+// ============================================
+// TEST 3b: Sync Bank Connection
+// ============================================
+func (suite *BankControllerTestSuite) TestSyncBankConnection_RejectsWithoutValidConsent() {
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/banks/connections/%d/sync", suite.bankConnection2.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	// bankConnection2's consent is "expired", not "valid"
+	suite.Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *BankControllerTestSuite) TestSyncBankConnection_NotFound() {
+	req, _ := http.NewRequest("POST", "/api/banks/connections/99999/sync", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *BankControllerTestSuite) TestSyncBankConnection_ThrottledWithinFrequencyWindow() {
+	// bankConnection1 has a valid consent and a LastSyncAt an hour ago, well
+	// inside its 4/day (6h) minimum interval.
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/banks/connections/%d/sync", suite.bankConnection1.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusTooManyRequests, w.Code)
+}
+
+// ============================================
+// TEST 3c: Reauthenticate Bank Connection
+// ============================================
+// Like TestCreateBankConnection_*, these only exercise the validation that
+// runs before any call out to the bank's XS2A API.
+func (suite *BankControllerTestSuite) TestReauthenticateBankConnection_NotFound() {
+	req, _ := http.NewRequest("POST", "/api/banks/connections/99999/reauthenticate", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *BankControllerTestSuite) TestReauthenticateBankConnection_RejectsRevokedConsent() {
+	revoked := models.BankConnection{
+		UserID:            suite.normalUser.ID,
+		BankName:          "sparebanken_norge",
+		BankEndpoint:      "https://psd2.spvapi.no",
+		ConsentID:         fmt.Sprintf("revoked_consent_%d", time.Now().UnixNano()),
+		ConsentStatus:     "revoked",
+		ConsentValidUntil: time.Now().Add(-1 * 24 * time.Hour),
+		Status:            "revoked",
+	}
+	suite.database.Create(&revoked)
+
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/banks/connections/%d/reauthenticate", revoked.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	suite.Equal("connection consent status does not support reauthentication", response["error"])
+}
+
+func (suite *BankControllerTestSuite) TestReauthenticateBankConnection_Unauthorized() {
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/banks/connections/%d/reauthenticate", suite.bankConnection2.ID), nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+}
+
+func (suite *BankControllerTestSuite) TestReauthenticateBankConnection_UserIsolation() {
+	hashedPassword, _ := controllers.HashPassword("password123")
+	otherUser := models.User{
+		Username:     "other3",
+		Email:        "other3@example.com",
+		PasswordHash: hashedPassword,
+		Role:         models.UserRoleUser,
+	}
+	suite.database.Create(&otherUser)
+
+	otherConnection := models.BankConnection{
+		UserID:            otherUser.ID,
+		BankName:          "other_bank",
+		BankEndpoint:      "https://other.bank.com",
+		ConsentID:         fmt.Sprintf("other_consent_3_%d", time.Now().UnixNano()),
+		ConsentStatus:     "expired",
+		ConsentValidUntil: time.Now().Add(-1 * 24 * time.Hour),
+		Status:            "expired",
+	}
+	suite.database.Create(&otherConnection)
+
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/banks/connections/%d/reauthenticate", otherConnection.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	suite.Equal("bank connection not found", response["error"])
+
+	// Verify the other user's connection was untouched.
+	var connection models.BankConnection
+	err := suite.database.First(&connection, otherConnection.ID).Error
+	suite.NoError(err)
+	suite.Equal("expired", connection.ConsentStatus)
 }
 
 // ============================================
@@ -528,6 +678,170 @@ func (suite *BankControllerTestSuite) TestBankConnectionsWithDifferentStatuses()
 	suite.NotNil(metadata)
 }
 
+// ============================================
+// BANK AUDIT EVENTS
+// ============================================
+func (suite *BankControllerTestSuite) TestGetBankConnections_RecordsViewedAuditEvent() {
+	req, _ := http.NewRequest("GET", "/api/banks/connections", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var events []models.BankAuditEvent
+	suite.database.Where("user_id = ? AND action = ?", suite.normalUser.ID, models.BankAuditActionViewed).Find(&events)
+	suite.Require().Len(events, 1)
+	suite.Nil(events[0].BankConnectionID)
+}
+
+func (suite *BankControllerTestSuite) TestDisconnectBank_RecordsDisconnectedAuditEvent() {
+	req, _ := http.NewRequest("DELETE", fmt.Sprintf("/api/banks/connections/%d", suite.bankConnection1.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var event models.BankAuditEvent
+	err := suite.database.Where("user_id = ? AND action = ?", suite.normalUser.ID, models.BankAuditActionDisconnected).First(&event).Error
+	suite.Require().NoError(err)
+	suite.Require().NotNil(event.BankConnectionID)
+	suite.Equal(suite.bankConnection1.ID, *event.BankConnectionID)
+	suite.Equal("valid", event.Before["consent_status"])
+}
+
+func (suite *BankControllerTestSuite) TestGetBankConnectionAuditLog_ScopedToOwner() {
+	hashedPassword, err := controllers.HashPassword("password123")
+	suite.Require().NoError(err)
+	otherUser := models.User{Username: "bankauditother", Email: "bankauditother@example.com", PasswordHash: hashedPassword, Role: models.UserRoleUser}
+	suite.database.Create(&otherUser)
+
+	suite.database.Create(&models.BankAuditEvent{
+		UserID:           suite.normalUser.ID,
+		BankConnectionID: &suite.bankConnection1.ID,
+		Action:           models.BankAuditActionSynced,
+	})
+	suite.database.Create(&models.BankAuditEvent{
+		UserID:           otherUser.ID,
+		BankConnectionID: &suite.bankConnection1.ID,
+		Action:           models.BankAuditActionSynced,
+	})
+
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/banks/connections/%d/audit", suite.bankConnection1.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	events := response["events"].([]interface{})
+	suite.Len(events, 1)
+}
+
+func (suite *BankControllerTestSuite) TestGetBankConnectionAuditLog_Unauthorized() {
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/banks/connections/%d/audit", suite.bankConnection1.ID), nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+}
+
+// ============================================
+// BANK HEALTH / CIRCUIT BREAKER
+// ============================================
+func (suite *BankControllerTestSuite) TestGetBankHealth_ReflectsBreakerState() {
+	banksync.SetFailureThreshold(1)
+	defer banksync.SetFailureThreshold(banksync.DefaultFailureThreshold)
+	banksync.Reset(suite.bankConnection1.ID)
+	banksync.RecordResult(suite.bankConnection1.ID, false)
+
+	req, _ := http.NewRequest("GET", "/api/banks/health", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	connections := response["connections"].([]interface{})
+
+	var found map[string]interface{}
+	for _, conn := range connections {
+		c := conn.(map[string]interface{})
+		if uint(c["connection_id"].(float64)) == suite.bankConnection1.ID {
+			found = c
+			break
+		}
+	}
+	suite.Require().NotNil(found)
+	suite.Equal("open", found["breaker_state"])
+	suite.Equal(float64(1), found["consecutive_failures"])
+}
+
+func (suite *BankControllerTestSuite) TestGetBankConnections_ReportsDegradedWhenBreakerOpen() {
+	banksync.SetFailureThreshold(1)
+	defer banksync.SetFailureThreshold(banksync.DefaultFailureThreshold)
+	banksync.Reset(suite.bankConnection1.ID)
+	banksync.RecordResult(suite.bankConnection1.ID, false)
+	defer banksync.Reset(suite.bankConnection1.ID)
+
+	req, _ := http.NewRequest("GET", "/api/banks/connections", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	connections := response["connections"].([]interface{})
+
+	var found map[string]interface{}
+	for _, conn := range connections {
+		c := conn.(map[string]interface{})
+		if uint(c["id"].(float64)) == suite.bankConnection1.ID {
+			found = c
+			break
+		}
+	}
+	suite.Require().NotNil(found)
+	suite.Equal("degraded", found["status"])
+}
+
+func (suite *BankControllerTestSuite) TestResetBankConnectionBreaker_ClosesBreaker() {
+	banksync.SetFailureThreshold(1)
+	defer banksync.SetFailureThreshold(banksync.DefaultFailureThreshold)
+	banksync.Reset(suite.bankConnection1.ID)
+	banksync.RecordResult(suite.bankConnection1.ID, false)
+
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/banks/connections/%d/reset", suite.bankConnection1.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	suite.Equal("closed", response["breaker_state"])
+
+	state, _ := banksync.Status(suite.bankConnection1.ID)
+	suite.Equal(banksync.BreakerClosed, state)
+}
+
+func (suite *BankControllerTestSuite) TestResetBankConnectionBreaker_NotFound() {
+	req, _ := http.NewRequest("POST", "/api/banks/connections/99999/reset", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
 // ============================================
 // UTILITY FUNCTIONS
 // ============================================
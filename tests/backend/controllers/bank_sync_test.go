@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// BankSyncIngestionTestSuite exercises SyncBankConnection end to end against
+// a fake XS2A server, verifying the idempotent-ingestion contract: re-syncing
+// the same window never duplicates a transaction, and a transaction the bank
+// corrects is updated in place rather than inserted again.
+type BankSyncIngestionTestSuite struct {
+	suite.Suite
+	database     *gorm.DB
+	router       *gin.Engine
+	server       *httptest.Server
+	transactions []map[string]interface{}
+	user         models.User
+	token        string
+	connection   models.BankConnection
+}
+
+func (suite *BankSyncIngestionTestSuite) SetupSuite() {
+	testDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	db.DB = testDB
+	suite.database = testDB
+
+	suite.Require().NoError(testDB.AutoMigrate(
+		&models.User{},
+		&models.Account{},
+		&models.Transaction{},
+		&models.BankConnection{},
+		&models.BankAccount{},
+		&models.BankSyncLog{},
+	))
+
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"transactions": map[string]interface{}{"booked": suite.transactions},
+		})
+	}))
+
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.New()
+	authGroup := suite.router.Group("/api")
+	authGroup.Use(controllers.AuthMiddleware())
+	{
+		authGroup.POST("/banks/connections/:id/sync", controllers.SyncBankConnection)
+	}
+}
+
+func (suite *BankSyncIngestionTestSuite) TearDownSuite() {
+	suite.server.Close()
+	sqlDB, _ := suite.database.DB()
+	sqlDB.Close()
+}
+
+func (suite *BankSyncIngestionTestSuite) SetupTest() {
+	suite.database.Unscoped().Where("1 = 1").Delete(&models.Transaction{})
+	suite.database.Unscoped().Where("1 = 1").Delete(&models.BankAccount{})
+	suite.database.Unscoped().Where("1 = 1").Delete(&models.BankConnection{})
+	suite.database.Unscoped().Where("1 = 1").Delete(&models.Account{})
+	suite.database.Unscoped().Where("1 = 1").Delete(&models.User{})
+
+	hashedPassword, _ := controllers.HashPassword("password123")
+	suite.user = models.User{Username: "sync_user", Email: "sync_user@example.com", PasswordHash: hashedPassword, Role: models.UserRoleUser}
+	suite.database.Create(&suite.user)
+
+	var err error
+	suite.token, err = controllers.GenerateToken(suite.user.ID, suite.user.Username, string(suite.user.Role))
+	suite.Require().NoError(err)
+
+	internalAccount := models.Account{UserID: suite.user.ID, Name: "Checking", Type: models.AccountChecking, Currency: "NOK"}
+	suite.database.Create(&internalAccount)
+
+	suite.connection = models.BankConnection{
+		UserID:          suite.user.ID,
+		BankName:        "sparebanken_norge",
+		BankEndpoint:    suite.server.URL,
+		ConsentID:       "consent-1",
+		ConsentStatus:   "valid",
+		FrequencyPerDay: 4,
+		Status:          "connected",
+	}
+	suite.database.Create(&suite.connection)
+
+	bankAccount := models.BankAccount{
+		BankConnectionID:  suite.connection.ID,
+		AccountID:         "ext-acc-1",
+		InternalAccountID: &internalAccount.ID,
+		IsActive:          true,
+	}
+	suite.database.Create(&bankAccount)
+
+	suite.transactions = nil
+}
+
+func bookedTxn(id, amount, description string) map[string]interface{} {
+	return map[string]interface{}{
+		"transactionId": id,
+		"bookingDate":   "2026-01-10",
+		"transactionAmount": map[string]string{
+			"amount":   amount,
+			"currency": "NOK",
+		},
+		"remittanceInformationUnstructured": description,
+	}
+}
+
+func (suite *BankSyncIngestionTestSuite) sync() map[string]interface{} {
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/banks/connections/%d/sync", suite.connection.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	var response map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	return response["sync_log"].(map[string]interface{})
+}
+
+func (suite *BankSyncIngestionTestSuite) TestSync_InsertsNewTransactionsOnce() {
+	suite.transactions = []map[string]interface{}{
+		bookedTxn("txn-1", "-50.00", "Groceries"),
+		bookedTxn("txn-2", "-10.00", "Coffee"),
+	}
+
+	logEntry := suite.sync()
+	suite.Equal(float64(2), logEntry["transactions_found"])
+	suite.Equal(float64(2), logEntry["transactions_added"])
+	suite.Equal(float64(0), logEntry["transactions_updated"])
+
+	var count int64
+	suite.database.Model(&models.Transaction{}).Count(&count)
+	suite.Equal(int64(2), count)
+}
+
+func (suite *BankSyncIngestionTestSuite) TestSync_RerunOverSameWindowIsNoOp() {
+	suite.transactions = []map[string]interface{}{bookedTxn("txn-1", "-50.00", "Groceries")}
+	suite.sync()
+
+	// Back-date LastSyncAt so the frequency throttle doesn't block the
+	// second manual sync in this test.
+	suite.database.Model(&models.BankConnection{}).Where("id = ?", suite.connection.ID).
+		Update("last_sync_at", timePtr(time.Now().Add(-25*time.Hour)))
+
+	logEntry := suite.sync()
+	suite.Equal(float64(0), logEntry["transactions_added"])
+	suite.Equal(float64(0), logEntry["transactions_updated"])
+
+	var count int64
+	suite.database.Model(&models.Transaction{}).Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *BankSyncIngestionTestSuite) TestSync_UpdatesTransactionWhenBankCorrectsIt() {
+	suite.transactions = []map[string]interface{}{bookedTxn("txn-1", "-50.00", "Groceries")}
+	suite.sync()
+
+	suite.database.Model(&models.BankConnection{}).Where("id = ?", suite.connection.ID).
+		Update("last_sync_at", timePtr(time.Now().Add(-25*time.Hour)))
+
+	suite.transactions = []map[string]interface{}{bookedTxn("txn-1", "-55.00", "Groceries (corrected)")}
+	logEntry := suite.sync()
+	suite.Equal(float64(0), logEntry["transactions_added"])
+	suite.Equal(float64(1), logEntry["transactions_updated"])
+
+	var txn models.Transaction
+	suite.database.Where("user_id = ?", suite.user.ID).First(&txn)
+	suite.True(decimal.NewFromInt(-5500).Div(decimal.NewFromInt(100)).Equal(txn.Amount))
+	suite.Equal("Groceries (corrected)", txn.Description)
+}
+
+func TestBankSyncIngestionTestSuite(t *testing.T) {
+	suite.Run(t, new(BankSyncIngestionTestSuite))
+}
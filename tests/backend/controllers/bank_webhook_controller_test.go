@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type BankWebhookControllerTestSuite struct {
+	suite.Suite
+	database   *gorm.DB
+	router     *gin.Engine
+	connection models.BankConnection
+}
+
+const bankWebhookTestSecret = "test-webhook-secret"
+
+func (suite *BankWebhookControllerTestSuite) SetupSuite() {
+	testDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+
+	db.DB = testDB
+	suite.database = testDB
+
+	err = testDB.AutoMigrate(
+		&models.User{},
+		&models.BankConnection{},
+		&models.BankAccount{},
+		&models.BankWebhookEvent{},
+		&models.BankBalanceAlert{},
+	)
+	suite.Require().NoError(err)
+
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.New()
+	suite.router.POST("/api/banks/webhooks/:provider", controllers.ReceiveBankWebhook)
+}
+
+func (suite *BankWebhookControllerTestSuite) TearDownSuite() {
+	if suite.database != nil {
+		sqlDB, _ := suite.database.DB()
+		sqlDB.Close()
+	}
+}
+
+func (suite *BankWebhookControllerTestSuite) SetupTest() {
+	suite.database.Exec("DELETE FROM bank_webhook_events")
+	suite.database.Exec("DELETE FROM bank_balance_alerts")
+	suite.database.Exec("DELETE FROM bank_connections")
+
+	suite.connection = models.BankConnection{
+		UserID:        1,
+		BankName:      "sparebanken_norge",
+		BankEndpoint:  "https://psd2.spvapi.no",
+		ConsentID:     fmt.Sprintf("webhook_consent_%d", len(suite.T().Name())),
+		ConsentStatus: "valid",
+		Status:        "connected",
+		Metadata:      models.JSONB{"webhook_secret": bankWebhookTestSecret},
+	}
+	suite.Require().NoError(suite.database.Create(&suite.connection).Error)
+}
+
+func (suite *BankWebhookControllerTestSuite) signedRequest(body string) *http.Request {
+	mac := hmac.New(sha256.New, []byte(bankWebhookTestSecret))
+	mac.Write([]byte(body))
+	sig := hex.EncodeToString(mac.Sum(nil))
+
+	req, _ := http.NewRequest("POST", "/api/banks/webhooks/sparebanken_norge", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Signature", sig)
+	return req
+}
+
+func (suite *BankWebhookControllerTestSuite) TestReceiveBankWebhook_ConsentRevoked_UpdatesConnection() {
+	body := fmt.Sprintf(`{"event_id":"evt-revoked-1","event_type":"consent.revoked","consent_id":%q}`, suite.connection.ConsentID)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, suite.signedRequest(body))
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var updated models.BankConnection
+	suite.Require().NoError(suite.database.First(&updated, suite.connection.ID).Error)
+	suite.Equal("revoked", updated.ConsentStatus)
+	suite.Equal("expired", updated.Status)
+}
+
+func (suite *BankWebhookControllerTestSuite) TestReceiveBankWebhook_ReplayedEventID_IsNoOp() {
+	body := fmt.Sprintf(`{"event_id":"evt-replay-1","event_type":"consent.revoked","consent_id":%q}`, suite.connection.ConsentID)
+
+	w1 := httptest.NewRecorder()
+	suite.router.ServeHTTP(w1, suite.signedRequest(body))
+	suite.Equal(http.StatusOK, w1.Code)
+
+	var countAfterFirst int64
+	suite.database.Model(&models.BankWebhookEvent{}).Where("event_id = ?", "evt-replay-1").Count(&countAfterFirst)
+	suite.Equal(int64(1), countAfterFirst)
+
+	w2 := httptest.NewRecorder()
+	suite.router.ServeHTTP(w2, suite.signedRequest(body))
+	suite.Equal(http.StatusOK, w2.Code)
+
+	var countAfterReplay int64
+	suite.database.Model(&models.BankWebhookEvent{}).Where("event_id = ?", "evt-replay-1").Count(&countAfterReplay)
+	suite.Equal(int64(1), countAfterReplay)
+}
+
+func (suite *BankWebhookControllerTestSuite) TestReceiveBankWebhook_BadSignature_RejectsWithoutWritingDB() {
+	body := fmt.Sprintf(`{"event_id":"evt-bad-sig-1","event_type":"consent.revoked","consent_id":%q}`, suite.connection.ConsentID)
+
+	var eventsBefore, connectionsBefore int64
+	suite.database.Model(&models.BankWebhookEvent{}).Count(&eventsBefore)
+	suite.database.Model(&models.BankConnection{}).Count(&connectionsBefore)
+
+	req, _ := http.NewRequest("POST", "/api/banks/webhooks/sparebanken_norge", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Signature", "0000000000000000000000000000000000000000000000000000000000000000")
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+
+	var eventsAfter, connectionsAfter int64
+	suite.database.Model(&models.BankWebhookEvent{}).Count(&eventsAfter)
+	suite.database.Model(&models.BankConnection{}).Count(&connectionsAfter)
+	suite.Equal(eventsBefore, eventsAfter)
+	suite.Equal(connectionsBefore, connectionsAfter)
+
+	var updated models.BankConnection
+	suite.Require().NoError(suite.database.First(&updated, suite.connection.ID).Error)
+	suite.Equal("valid", updated.ConsentStatus)
+}
+
+func (suite *BankWebhookControllerTestSuite) TestReceiveBankWebhook_BalanceThreshold_RecordsAlert() {
+	body := fmt.Sprintf(`{"event_id":"evt-balance-1","event_type":"balance.threshold","consent_id":%q,"account_id":"acc-1","balance":"50.00","threshold":"100.00"}`, suite.connection.ConsentID)
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, suite.signedRequest(body))
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var alert models.BankBalanceAlert
+	suite.Require().NoError(suite.database.Where("bank_connection_id = ?", suite.connection.ID).First(&alert).Error)
+	suite.Equal("50.00", alert.Balance)
+	suite.Equal("100.00", alert.Threshold)
+}
+
+func (suite *BankWebhookControllerTestSuite) TestReceiveBankWebhook_UnknownConnection_Acknowledges() {
+	body := `{"event_id":"evt-unknown-1","event_type":"consent.revoked","consent_id":"no-such-consent"}`
+
+	req, _ := http.NewRequest("POST", "/api/banks/webhooks/sparebanken_norge", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var resp map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+	suite.Equal(true, resp["acknowledged"])
+}
+
+func TestBankWebhookControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(BankWebhookControllerTestSuite))
+}
@@ -0,0 +1,168 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateBudgetAlert(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&groceries)
+	budget := models.Budget{UserID: user.ID, PeriodStart: time.Now(), PeriodEnd: time.Now().AddDate(0, 1, -1), Currency: "USD"}
+	database.Create(&budget)
+	item := models.BudgetItem{BudgetID: budget.ID, CategoryID: groceries.ID, PlannedAmount: decimal.NewFromInt(400)}
+	database.Create(&item)
+
+	router := SetupRouter()
+	router.POST("/api/budgets/:id/items/:itemId/alerts", controllers.AuthMiddleware(), controllers.CreateBudgetAlert)
+
+	alertData := map[string]interface{}{
+		"threshold_percent": 90,
+		"channel":           "in_app",
+	}
+	body, _ := json.Marshal(alertData)
+
+	path := "/api/budgets/" + strconv.Itoa(int(budget.ID)) + "/items/" + strconv.Itoa(int(item.ID)) + "/alerts"
+	req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code, "Should create budget alert successfully")
+
+	var response models.BudgetAlert
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, item.ID, response.BudgetItemID)
+	assert.Equal(t, models.BudgetAlertChannelInApp, response.Channel)
+	assert.Equal(t, 90.0, response.ThresholdPercent)
+}
+
+func TestCreateBudgetAlert_RequiresTargetForWebhookChannel(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&groceries)
+	budget := models.Budget{UserID: user.ID, PeriodStart: time.Now(), PeriodEnd: time.Now().AddDate(0, 1, -1), Currency: "USD"}
+	database.Create(&budget)
+	item := models.BudgetItem{BudgetID: budget.ID, CategoryID: groceries.ID, PlannedAmount: decimal.NewFromInt(400)}
+	database.Create(&item)
+
+	router := SetupRouter()
+	router.POST("/api/budgets/:id/items/:itemId/alerts", controllers.AuthMiddleware(), controllers.CreateBudgetAlert)
+
+	alertData := map[string]interface{}{"threshold_percent": 90, "channel": "webhook"}
+	body, _ := json.Marshal(alertData)
+
+	path := "/api/budgets/" + strconv.Itoa(int(budget.ID)) + "/items/" + strconv.Itoa(int(item.ID)) + "/alerts"
+	req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetBudgets_FiresInAppAlertWhenThresholdCrossed(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&groceries)
+	now := time.Now()
+	budget := models.Budget{UserID: user.ID, PeriodStart: now.AddDate(0, 0, -5), PeriodEnd: now.AddDate(0, 0, 25), Currency: "USD"}
+	database.Create(&budget)
+	item := models.BudgetItem{BudgetID: budget.ID, CategoryID: groceries.ID, PlannedAmount: decimal.NewFromInt(100)}
+	database.Create(&item)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+	txn := models.Transaction{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		CategoryID:  &groceries.ID,
+		Amount:      decimal.NewFromInt(-95),
+		TxnDate:     now,
+		Description: "Big grocery run",
+	}
+	database.Create(&txn)
+
+	alert := models.BudgetAlert{UserID: user.ID, BudgetItemID: item.ID, ThresholdPercent: 90, Channel: models.BudgetAlertChannelInApp}
+	database.Create(&alert)
+
+	router := SetupRouter()
+	router.GET("/api/budgets", controllers.AuthMiddleware(), controllers.GetBudgets)
+
+	req, _ := http.NewRequest("GET", "/api/budgets", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var events []models.BudgetAlertEvent
+	database.Where("budget_alert_id = ?", alert.ID).Find(&events)
+	assert.Equal(t, 1, len(events), "Crossing the threshold should record exactly one event")
+
+	// A second request within the alert's cooldown shouldn't fire again.
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, req)
+	assert.Equal(t, http.StatusOK, w2.Code)
+
+	var eventsAfterSecondRequest []models.BudgetAlertEvent
+	database.Where("budget_alert_id = ?", alert.ID).Find(&eventsAfterSecondRequest)
+	assert.Equal(t, 1, len(eventsAfterSecondRequest), "Same period/threshold shouldn't fire a second event")
+}
+
+func TestDeleteBudgetAlert(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&groceries)
+	budget := models.Budget{UserID: user.ID, PeriodStart: time.Now(), PeriodEnd: time.Now().AddDate(0, 1, -1), Currency: "USD"}
+	database.Create(&budget)
+	item := models.BudgetItem{BudgetID: budget.ID, CategoryID: groceries.ID, PlannedAmount: decimal.NewFromInt(400)}
+	database.Create(&item)
+	alert := models.BudgetAlert{UserID: user.ID, BudgetItemID: item.ID, ThresholdPercent: 80, Channel: models.BudgetAlertChannelInApp}
+	database.Create(&alert)
+
+	router := SetupRouter()
+	router.DELETE("/api/budgets/alerts/:id", controllers.AuthMiddleware(), controllers.DeleteBudgetAlert)
+
+	req, _ := http.NewRequest("DELETE", "/api/budgets/alerts/"+strconv.Itoa(int(alert.ID)), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var count int64
+	database.Model(&models.BudgetAlert{}).Where("id = ?", alert.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
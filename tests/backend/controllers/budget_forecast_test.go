@@ -0,0 +1,169 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetBudgetForecast_SeasonalProjectionOverridesLinearForFrontLoadedSpend
+// seeds six prior periods where the Rent category's entire spend always
+// lands in the first few days of the period, so seasonalElapsedShare should
+// come back near 1.0 and the seasonal projection should track spent-so-far
+// almost exactly instead of extrapolating it out across the rest of the
+// period the way the linear run-rate does.
+func TestGetBudgetForecast_SeasonalProjectionOverridesLinearForFrontLoadedSpend(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	rent := models.Category{UserID: user.ID, Name: "Rent", Kind: models.CategoryExpense}
+	database.Create(&rent)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+
+	now := time.Now()
+	periodStart := now.AddDate(0, 0, -9)
+	periodEnd := now.AddDate(0, 0, 20)
+	budget := models.Budget{
+		UserID:      user.ID,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Currency:    "USD",
+	}
+	database.Create(&budget)
+	item := models.BudgetItem{BudgetID: budget.ID, CategoryID: rent.ID, PlannedAmount: decimal.NewFromInt(300)}
+	database.Create(&item)
+
+	// Six prior 30-day periods, each with its entire spend landing on day 2
+	// (well within the current period's 10-day elapsed window) and nothing
+	// afterward, so each historical period's elapsed-share fraction is 1.0.
+	for i := 1; i <= 6; i++ {
+		histStart := periodStart.AddDate(0, 0, -30*i)
+		database.Create(&models.Transaction{
+			UserID:      user.ID,
+			AccountID:   account.ID,
+			CategoryID:  &rent.ID,
+			Amount:      decimal.NewFromInt(-50),
+			TxnDate:     histStart.AddDate(0, 0, 1),
+			Description: "Rent",
+		})
+	}
+
+	// Current period: the same front-loaded 50 spent on day 2, and nothing
+	// since. A flat linear run-rate would extrapolate this across the full
+	// 30-day period; the seasonal projection should recognize the spend is
+	// already essentially done for the period.
+	database.Create(&models.Transaction{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		CategoryID:  &rent.ID,
+		Amount:      decimal.NewFromInt(-50),
+		TxnDate:     periodStart.AddDate(0, 0, 1),
+		Description: "Rent",
+	})
+
+	router := SetupRouter()
+	router.GET("/api/budgets/:id/forecast", controllers.AuthMiddleware(), controllers.GetBudgetForecast)
+
+	req, _ := http.NewRequest("GET", "/api/budgets/"+strconv.Itoa(int(budget.ID))+"/forecast", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Items []struct {
+			SpentSoFarCents        int64    `json:"spent_so_far_cents"`
+			ProjectedCents         int64    `json:"projected_cents"`
+			SeasonalProjectedCents *int64   `json:"seasonal_projected_cents"`
+			SeasonalElapsedShare   *float64 `json:"seasonal_elapsed_share"`
+		} `json:"items"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, len(response.Items))
+
+	gotItem := response.Items[0]
+	assert.Equal(t, int64(5000), gotItem.SpentSoFarCents)
+	if assert.NotNil(t, gotItem.SeasonalElapsedShare) {
+		assert.InDelta(t, 1.0, *gotItem.SeasonalElapsedShare, 0.01)
+	}
+	if assert.NotNil(t, gotItem.SeasonalProjectedCents) {
+		assert.Equal(t, gotItem.SpentSoFarCents, *gotItem.SeasonalProjectedCents, "seasonal projection should track spend that's already front-loaded, not extrapolate it")
+	}
+	assert.Greater(t, gotItem.ProjectedCents, *gotItem.SeasonalProjectedCents, "linear run-rate should still extrapolate further than the seasonal projection")
+}
+
+// TestGetBudgetForecast_FallsBackToLinearWithoutHistory confirms that a
+// category with no spend in any lookback period omits the seasonal fields
+// entirely rather than dividing by a zero/undefined share.
+func TestGetBudgetForecast_FallsBackToLinearWithoutHistory(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&groceries)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+
+	now := time.Now()
+	periodStart := now.AddDate(0, 0, -9)
+	periodEnd := now.AddDate(0, 0, 20)
+	budget := models.Budget{
+		UserID:      user.ID,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Currency:    "USD",
+	}
+	database.Create(&budget)
+	item := models.BudgetItem{BudgetID: budget.ID, CategoryID: groceries.ID, PlannedAmount: decimal.NewFromInt(300)}
+	database.Create(&item)
+
+	database.Create(&models.Transaction{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		CategoryID:  &groceries.ID,
+		Amount:      decimal.NewFromInt(-200),
+		TxnDate:     periodStart.AddDate(0, 0, 2),
+		Description: "Grocery run",
+	})
+
+	router := SetupRouter()
+	router.GET("/api/budgets/:id/forecast", controllers.AuthMiddleware(), controllers.GetBudgetForecast)
+
+	req, _ := http.NewRequest("GET", "/api/budgets/"+strconv.Itoa(int(budget.ID))+"/forecast", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Items []struct {
+			SeasonalProjectedCents *int64   `json:"seasonal_projected_cents"`
+			SeasonalElapsedShare   *float64 `json:"seasonal_elapsed_share"`
+			Risk                   string   `json:"risk"`
+		} `json:"items"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, len(response.Items))
+	assert.Nil(t, response.Items[0].SeasonalProjectedCents)
+	assert.Nil(t, response.Items[0].SeasonalElapsedShare)
+	assert.NotEmpty(t, response.Items[0].Risk)
+}
@@ -0,0 +1,292 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateBudgetTemplate(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&groceries)
+
+	router := SetupRouter()
+	router.POST("/api/budget-templates", controllers.AuthMiddleware(), controllers.CreateBudgetTemplate)
+
+	templateData := map[string]interface{}{
+		"name":          "Monthly essentials",
+		"currency":      "USD",
+		"cadence":       "monthly",
+		"rollover_mode": "carry_remaining",
+		"items": []map[string]interface{}{
+			{"category_id": groceries.ID, "planned_cents": 40000},
+		},
+	}
+	body, _ := json.Marshal(templateData)
+
+	req, _ := http.NewRequest("POST", "/api/budget-templates", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code, "Should create budget template successfully")
+
+	var response models.BudgetTemplate
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, user.ID, response.UserID)
+	assert.Equal(t, models.BudgetRolloverCarryRemaining, response.RolloverMode)
+	assert.Equal(t, 1, len(response.Items))
+}
+
+func TestRolloverBudget_CarriesRemainingIntoNextPeriod(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&groceries)
+
+	// Previous period's materialized budget: planned 40000, spent 10000, so
+	// 30000 of unspent remaining should carry into the next period.
+	now := time.Now()
+	previousStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	previousEnd := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	previousBudget := models.Budget{
+		UserID:      user.ID,
+		PeriodStart: previousStart,
+		PeriodEnd:   previousEnd,
+		Currency:    "USD",
+	}
+	database.Create(&previousBudget)
+	previousItem := models.BudgetItem{BudgetID: previousBudget.ID, CategoryID: groceries.ID, PlannedAmount: decimal.NewFromInt(40000).Div(decimal.NewFromInt(100))}
+	database.Create(&previousItem)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+
+	spentTxn := models.Transaction{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		CategoryID:  &groceries.ID,
+		Amount:      decimal.NewFromInt(-10000).Div(decimal.NewFromInt(100)),
+		TxnDate:     previousStart.AddDate(0, 0, 5),
+		Description: "Grocery run",
+	}
+	database.Create(&spentTxn)
+
+	template := models.BudgetTemplate{
+		UserID:       user.ID,
+		Name:         "Monthly essentials",
+		Currency:     "USD",
+		Cadence:      models.BudgetCadenceMonthly,
+		RolloverMode: models.BudgetRolloverCarryRemaining,
+		LastBudgetID: &previousBudget.ID,
+	}
+	database.Create(&template)
+	templateItem := models.BudgetTemplateItem{BudgetTemplateID: template.ID, CategoryID: groceries.ID, PlannedCents: 40000}
+	database.Create(&templateItem)
+
+	router := SetupRouter()
+	router.POST("/api/budget-templates/:id/rollover", controllers.AuthMiddleware(), controllers.RolloverBudget)
+
+	req, _ := http.NewRequest("POST", "/api/budget-templates/"+strconv.Itoa(int(template.ID))+"/rollover", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code, "Should materialize the next period's budget")
+
+	var response models.Budget
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 1, len(response.Items))
+	assert.True(t, decimal.NewFromInt(70000).Div(decimal.NewFromInt(100)).Equal(response.Items[0].PlannedAmount),
+		"40000 planned plus 30000 carried-over remaining")
+
+	var updatedTemplate models.BudgetTemplate
+	database.First(&updatedTemplate, template.ID)
+	assert.Equal(t, response.ID, *updatedTemplate.LastBudgetID, "Template should advance to the new budget")
+}
+
+func TestGetUpcomingBudgetPeriods_ReturnsNextPeriodForEachTemplate(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	template := models.BudgetTemplate{
+		UserID:   user.ID,
+		Name:     "Weekly groceries",
+		Currency: "USD",
+		Cadence:  models.BudgetCadenceWeekly,
+	}
+	database.Create(&template)
+
+	router := SetupRouter()
+	router.GET("/api/budgets/upcoming", controllers.AuthMiddleware(), controllers.GetUpcomingBudgetPeriods)
+
+	req, _ := http.NewRequest("GET", "/api/budgets/upcoming", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []struct {
+		TemplateID  uint   `json:"template_id"`
+		Cadence     string `json:"cadence"`
+		PeriodStart string `json:"period_start"`
+		PeriodEnd   string `json:"period_end"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 1, len(response))
+	assert.Equal(t, template.ID, response[0].TemplateID)
+	assert.Equal(t, "weekly", response[0].Cadence)
+}
+
+func TestRolloverBudgetByID_MaterializesNextPeriodFromExistingBudget(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&groceries)
+
+	template := models.BudgetTemplate{
+		UserID:   user.ID,
+		Name:     "Monthly essentials",
+		Currency: "USD",
+		Cadence:  models.BudgetCadenceMonthly,
+	}
+	database.Create(&template)
+	templateItem := models.BudgetTemplateItem{BudgetTemplateID: template.ID, CategoryID: groceries.ID, PlannedCents: 40000}
+	database.Create(&templateItem)
+
+	currentBudget := models.Budget{
+		UserID:      user.ID,
+		PeriodStart: time.Now(),
+		PeriodEnd:   time.Now().AddDate(0, 1, -1),
+		Currency:    "USD",
+		TemplateID:  &template.ID,
+	}
+	database.Create(&currentBudget)
+	database.Model(&template).Update("last_budget_id", currentBudget.ID)
+
+	router := SetupRouter()
+	router.POST("/api/budgets/:id/rollover", controllers.AuthMiddleware(), controllers.RolloverBudgetByID)
+
+	req, _ := http.NewRequest("POST", "/api/budgets/"+strconv.Itoa(int(currentBudget.ID))+"/rollover", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code, "Should materialize the template's next period")
+
+	var response models.Budget
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NotEqual(t, currentBudget.ID, response.ID)
+	assert.Equal(t, template.ID, *response.TemplateID)
+}
+
+func TestRolloverBudgetByID_RejectsNonRecurringBudget(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	manualBudget := models.Budget{
+		UserID:      user.ID,
+		PeriodStart: time.Now(),
+		PeriodEnd:   time.Now().AddDate(0, 1, -1),
+		Currency:    "USD",
+	}
+	database.Create(&manualBudget)
+
+	router := SetupRouter()
+	router.POST("/api/budgets/:id/rollover", controllers.AuthMiddleware(), controllers.RolloverBudgetByID)
+
+	req, _ := http.NewRequest("POST", "/api/budgets/"+strconv.Itoa(int(manualBudget.ID))+"/rollover", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetBudgetForecast(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&groceries)
+
+	now := time.Now()
+	periodStart := now.AddDate(0, 0, -9)
+	periodEnd := now.AddDate(0, 0, 20)
+	budget := models.Budget{
+		UserID:      user.ID,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Currency:    "USD",
+	}
+	database.Create(&budget)
+	item := models.BudgetItem{BudgetID: budget.ID, CategoryID: groceries.ID, PlannedAmount: decimal.NewFromInt(30000).Div(decimal.NewFromInt(100))}
+	database.Create(&item)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+
+	// 20000 spent over the first 10 elapsed days projects well past the
+	// 30000 planned amount over the full 30-day period.
+	spentTxn := models.Transaction{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		CategoryID:  &groceries.ID,
+		Amount:      decimal.NewFromInt(-20000).Div(decimal.NewFromInt(100)),
+		TxnDate:     periodStart.AddDate(0, 0, 2),
+		Description: "Grocery run",
+	}
+	database.Create(&spentTxn)
+
+	router := SetupRouter()
+	router.GET("/api/budgets/:id/forecast", controllers.AuthMiddleware(), controllers.GetBudgetForecast)
+
+	req, _ := http.NewRequest("GET", "/api/budgets/"+strconv.Itoa(int(budget.ID))+"/forecast", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Items []struct {
+			SpentSoFarCents    int64 `json:"spent_so_far_cents"`
+			ProjectedCents     int64 `json:"projected_cents"`
+			ProjectedOverspend bool  `json:"projected_overspend"`
+		} `json:"items"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 1, len(response.Items))
+	assert.Equal(t, int64(20000), response.Items[0].SpentSoFarCents)
+	assert.True(t, response.Items[0].ProjectedOverspend, "Run-rate should project past the planned amount")
+}
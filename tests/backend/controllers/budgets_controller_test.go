@@ -5,13 +5,16 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
 	"Personal-Finance-Tracker-backend/controllers"
 	"Personal-Finance-Tracker-backend/db"
 	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/fx"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -78,11 +81,59 @@ func TestCreateBudget(t *testing.T) {
 	assert.Equal(t, 2, len(response.Items))
 
 	// Verify total planned amount
-	var totalPlanned int64
+	totalPlanned := decimal.Zero
 	for _, item := range response.Items {
-		totalPlanned += item.PlannedCents
+		totalPlanned = totalPlanned.Add(item.PlannedAmount)
 	}
-	assert.Equal(t, int64(100000), totalPlanned, "Total planned should equal 100000")
+	assert.True(t, decimal.NewFromInt(100000).Div(decimal.NewFromInt(100)).Equal(totalPlanned), "Total planned should equal 100000 cents")
+}
+
+// TestCreateBudget_OverlapCheckIgnoresTemplateMaterializedBudgets verifies a
+// manual budget can be created for the same period a recurring template
+// already materialized - the overlap check only compares manual budgets
+// against each other.
+func TestCreateBudget_OverlapCheckIgnoresTemplateMaterializedBudgets(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&groceries)
+
+	now := time.Now()
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, -1)
+	templateID := uint(1)
+	materialized := models.Budget{
+		UserID:      user.ID,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Currency:    "USD",
+		TemplateID:  &templateID,
+	}
+	database.Create(&materialized)
+
+	router := SetupRouter()
+	router.POST("/api/budgets", controllers.AuthMiddleware(), controllers.CreateBudget)
+
+	budgetData := map[string]interface{}{
+		"period_start": periodStart.Format("2006-01-02"),
+		"period_end":   periodEnd.Format("2006-01-02"),
+		"currency":     "USD",
+		"items": []map[string]interface{}{
+			{"category_id": groceries.ID, "planned_cents": 40000},
+		},
+	}
+	body, _ := json.Marshal(budgetData)
+
+	req, _ := http.NewRequest("POST", "/api/budgets", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code, "Manual budget shouldn't collide with a template-materialized one")
 }
 
 // ============================================
@@ -158,7 +209,7 @@ func TestGetBudget(t *testing.T) {
 		Name:                "Checking",
 		Type:                "checking",
 		InitialBalanceCents: 100000,
-		CurrentBalanceCents: 100000,
+		CurrentBalance:      decimal.NewFromInt(100000).Div(decimal.NewFromInt(100)),
 	}
 	database.Create(&account)
 
@@ -173,9 +224,9 @@ func TestGetBudget(t *testing.T) {
 	database.Create(&budget)
 
 	budgetItem := models.BudgetItem{
-		BudgetID:     budget.ID,
-		CategoryID:   category.ID,
-		PlannedCents: 40000,
+		BudgetID:      budget.ID,
+		CategoryID:    category.ID,
+		PlannedAmount: decimal.NewFromInt(40000).Div(decimal.NewFromInt(100)),
 	}
 	database.Create(&budgetItem)
 
@@ -184,7 +235,7 @@ func TestGetBudget(t *testing.T) {
 		UserID:      user.ID,
 		AccountID:   account.ID,
 		CategoryID:  &category.ID,
-		AmountCents: -15000,
+		Amount:      decimal.NewFromInt(-15000).Div(decimal.NewFromInt(100)),
 		Description: "Groceries 1",
 		TxnDate:     now,
 	}
@@ -197,7 +248,7 @@ func TestGetBudget(t *testing.T) {
 		UserID:      user.ID,
 		AccountID:   account.ID,
 		CategoryID:  &category.ID,
-		AmountCents: -10000,
+		Amount:      decimal.NewFromInt(-10000).Div(decimal.NewFromInt(100)),
 		Description: "Groceries 2",
 		TxnDate:     now,
 	}
@@ -234,6 +285,72 @@ func TestGetBudget(t *testing.T) {
 	}
 }
 
+// TestGetBudget_ConvertsForeignCurrencySpend verifies that a transaction
+// posted against a NOK account is converted into the budget's USD currency
+// before being summed into total_spent_cents.
+func TestGetBudget_ConvertsForeignCurrencySpend(t *testing.T) {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.FxRate{})
+	db.DB = database
+	fx.SetProvider(stubFxProvider{}) // always answers 9.5 NOK per USD
+
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	category := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&category)
+
+	usdAccount := models.Account{UserID: user.ID, Name: "US Checking", Type: "checking", Currency: "USD"}
+	database.Create(&usdAccount)
+
+	nokAccount := models.Account{UserID: user.ID, Name: "Norwegian Checking", Type: "checking", Currency: "NOK"}
+	database.Create(&nokAccount)
+
+	now := time.Now()
+	budget := models.Budget{
+		UserID:      user.ID,
+		PeriodStart: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1),
+		Currency:    "USD",
+	}
+	database.Create(&budget)
+
+	budgetItem := models.BudgetItem{BudgetID: budget.ID, CategoryID: category.ID, PlannedAmount: decimal.NewFromInt(40000).Div(decimal.NewFromInt(100))}
+	database.Create(&budgetItem)
+
+	database.Create(&models.Transaction{
+		UserID:      user.ID,
+		AccountID:   usdAccount.ID,
+		CategoryID:  &category.ID,
+		Amount:      decimal.NewFromInt(-10000).Div(decimal.NewFromInt(100)),
+		Description: "US groceries",
+		TxnDate:     now,
+	})
+	database.Create(&models.Transaction{
+		UserID:      user.ID,
+		AccountID:   nokAccount.ID,
+		CategoryID:  &category.ID,
+		Amount:      decimal.NewFromInt(-9500).Div(decimal.NewFromInt(100)), // 9500 NOK -> 1000 USD cents at the stubbed 9.5 rate
+		Description: "Norwegian groceries",
+		TxnDate:     now,
+	})
+
+	router := SetupRouter()
+	router.GET("/api/budgets/:id", controllers.AuthMiddleware(), controllers.GetBudget)
+
+	req, _ := http.NewRequest("GET", "/api/budgets/"+strconv.Itoa(int(budget.ID)), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, float64(11000), response["total_spent_cents"],
+		"10000 USD cents plus the NOK transaction converted to 1000 USD cents")
+}
+
 // ============================================
 // TEST 4: Update Budget
 // ============================================
@@ -261,9 +378,9 @@ func TestUpdateBudget(t *testing.T) {
 	database.Create(&budget)
 
 	budgetItem := models.BudgetItem{
-		BudgetID:     budget.ID,
-		CategoryID:   category.ID,
-		PlannedCents: 30000,
+		BudgetID:      budget.ID,
+		CategoryID:    category.ID,
+		PlannedAmount: decimal.NewFromInt(30000).Div(decimal.NewFromInt(100)),
 	}
 	database.Create(&budgetItem)
 
@@ -301,7 +418,7 @@ func TestUpdateBudget(t *testing.T) {
 	}
 
 	assert.Equal(t, 1, len(response.Items), "Budget should have 1 item after update")
-	assert.Equal(t, int64(50000), response.Items[0].PlannedCents,
+	assert.True(t, decimal.NewFromInt(50000).Div(decimal.NewFromInt(100)).Equal(response.Items[0].PlannedAmount),
 		"Planned amount should be updated to $500")
 }
 
@@ -330,9 +447,9 @@ func TestDeleteBudget(t *testing.T) {
 	database.Create(&budget)
 
 	budgetItem := models.BudgetItem{
-		BudgetID:     budget.ID,
-		CategoryID:   category.ID,
-		PlannedCents: 30000,
+		BudgetID:      budget.ID,
+		CategoryID:    category.ID,
+		PlannedAmount: decimal.NewFromInt(30000).Div(decimal.NewFromInt(100)),
 	}
 	database.Create(&budgetItem)
 
@@ -0,0 +1,131 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type bulkRowResponse struct {
+	Status string `json:"status"`
+	ID     uint   `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type bulkResponse struct {
+	DryRun   bool              `json:"dry_run"`
+	ImportID string            `json:"import_id"`
+	Created  int               `json:"created"`
+	Results  []bulkRowResponse `json:"results"`
+}
+
+func TestBulkCreateTransactions_SkipsDuplicateRemoteIDs(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{
+		UserID:              user.ID,
+		Name:                "Test Account",
+		Type:                "checking",
+		InitialBalanceCents: 0,
+		CurrentBalance:      decimal.Zero,
+	}
+	database.Create(&account)
+
+	remoteID := "bank-row-1"
+	database.Create(&models.Transaction{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		Amount:      decimal.NewFromInt(-500).Div(decimal.NewFromInt(100)),
+		Description: "Already imported",
+		RemoteID:    &remoteID,
+	})
+
+	router := SetupRouter()
+	router.POST("/api/transactions/bulk", controllers.AuthMiddleware(), controllers.BulkCreateTransactions)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"import_id": "import-1",
+		"transactions": []map[string]interface{}{
+			{"account_id": account.ID, "amount": -5.00, "description": "Already imported", "txn_date": "2024-01-01", "remote_id": remoteID},
+			{"account_id": account.ID, "amount": -25.00, "description": "New row", "txn_date": "2024-01-02", "remote_id": "bank-row-2"},
+		},
+	})
+
+	req, _ := http.NewRequest("POST", "/api/transactions/bulk", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "Expected 200 OK status")
+
+	var response bulkResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 1, response.Created, "Only the new row should be created")
+	assert.Len(t, response.Results, 2, "Should report one result per input row")
+	assert.Equal(t, "duplicate", response.Results[0].Status)
+	assert.Equal(t, "created", response.Results[1].Status)
+
+	var count int64
+	database.Model(&models.Transaction{}).Where("account_id = ?", account.ID).Count(&count)
+	assert.Equal(t, int64(2), count, "Duplicate row should not be re-inserted")
+
+	var updatedAccount models.Account
+	database.First(&updatedAccount, account.ID)
+	assert.True(t, decimal.NewFromInt(-3000).Div(decimal.NewFromInt(100)).Equal(updatedAccount.CurrentBalance), "Balance should only reflect the pre-existing row plus the one newly created row")
+}
+
+func TestBulkCreateTransactions_DryRunWritesNothing(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{
+		UserID:              user.ID,
+		Name:                "Test Account",
+		Type:                "checking",
+		InitialBalanceCents: 0,
+		CurrentBalance:      decimal.Zero,
+	}
+	database.Create(&account)
+
+	router := SetupRouter()
+	router.POST("/api/transactions/bulk", controllers.AuthMiddleware(), controllers.BulkCreateTransactions)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"import_id": "import-2",
+		"transactions": []map[string]interface{}{
+			{"account_id": account.ID, "amount": -12.00, "description": "Preview only", "txn_date": "2024-01-03", "remote_id": "bank-row-3"},
+		},
+	})
+
+	req, _ := http.NewRequest("POST", "/api/transactions/bulk?dry_run=true", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "Expected 200 OK status")
+
+	var response bulkResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.True(t, response.DryRun)
+	assert.Equal(t, "created", response.Results[0].Status)
+
+	var count int64
+	database.Model(&models.Transaction{}).Where("account_id = ?", account.ID).Count(&count)
+	assert.Equal(t, int64(0), count, "Dry run should not write any transactions")
+}
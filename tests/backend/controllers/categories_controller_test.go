@@ -13,6 +13,7 @@ import (
 	"Personal-Finance-Tracker-backend/db"
 	"Personal-Finance-Tracker-backend/models"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,8 +27,8 @@ func TestCreateCategory(t *testing.T) {
 	router.POST("/api/categories", controllers.AuthMiddleware(), controllers.CreateCategory)
 
 	categoryData := map[string]interface{}{
-		"name": "Category 1",
-		"kind": "expense",
+		"name":        "Category 1",
+		"kind":        "expense",
 		"description": "Test category",
 	}
 	body, _ := json.Marshal(categoryData)
@@ -72,7 +73,7 @@ func TestCreateSubcategory(t *testing.T) {
 		"parent_id": parent.ID,
 	}
 	body, _ := json.Marshal(subcategoryData)
-	
+
 	req, _ := http.NewRequest("POST", "/api/categories", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -97,9 +98,9 @@ func TestCreateCategory_ParentKindMismatch(t *testing.T) {
 	token := GetTestToken(user.ID, user.Username)
 
 	incomeParent := models.Category{
-		UserID:    user.ID,
-		Name:      "Parent Category",
-		Kind:      models.CategoryIncome,
+		UserID: user.ID,
+		Name:   "Parent Category",
+		Kind:   models.CategoryIncome,
 	}
 	database.Create(&incomeParent)
 
@@ -132,29 +133,29 @@ func TestCreateCategory_MaxDepth(t *testing.T) {
 	user := CreateTestUser(database)
 	token := GetTestToken(user.ID, user.Username)
 
-    // Create 3-level hierarchy: Food > Groceries > Vegetables
-    food := models.Category{
-        UserID: user.ID,
-        Name:   "Food",
-        Kind:   models.CategoryExpense,
-    }
-    database.Create(&food)
-
-    groceries := models.Category{
-        UserID:   user.ID,
-        Name:     "Groceries",
-        Kind:     models.CategoryExpense,
-        ParentID: &food.ID,
-    }
-    database.Create(&groceries)
-
-    vegetables := models.Category{
-        UserID:   user.ID,
-        Name:     "Vegetables",
-        Kind:     models.CategoryExpense,
-        ParentID: &groceries.ID,
-    }
-    database.Create(&vegetables)
+	// Create 3-level hierarchy: Food > Groceries > Vegetables
+	food := models.Category{
+		UserID: user.ID,
+		Name:   "Food",
+		Kind:   models.CategoryExpense,
+	}
+	database.Create(&food)
+
+	groceries := models.Category{
+		UserID:   user.ID,
+		Name:     "Groceries",
+		Kind:     models.CategoryExpense,
+		ParentID: &food.ID,
+	}
+	database.Create(&groceries)
+
+	vegetables := models.Category{
+		UserID:   user.ID,
+		Name:     "Vegetables",
+		Kind:     models.CategoryExpense,
+		ParentID: &groceries.ID,
+	}
+	database.Create(&vegetables)
 
 	router := SetupRouter()
 	router.POST("/api/categories", controllers.AuthMiddleware(), controllers.CreateCategory)
@@ -175,9 +176,9 @@ func TestCreateCategory_MaxDepth(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code, "Expected 400 Bad Request status")
 
 	var response map[string]interface{}
-    json.Unmarshal(w.Body.Bytes(), &response)
-    assert.Contains(t, response["error"].(string), "nesting too deep",
-        "Should reject 4th level nesting")
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Contains(t, response["error"].(string), "nesting too deep",
+		"Should reject 4th level nesting")
 }
 
 func TestCreateCategory_DuplicateName(t *testing.T) {
@@ -187,9 +188,9 @@ func TestCreateCategory_DuplicateName(t *testing.T) {
 	token := GetTestToken(user.ID, user.Username)
 
 	existingCategory := models.Category{
-		UserID:    user.ID,
-		Name:      "Category 1",
-		Kind:      models.CategoryExpense,
+		UserID: user.ID,
+		Name:   "Category 1",
+		Kind:   models.CategoryExpense,
 	}
 	database.Create(&existingCategory)
 
@@ -211,7 +212,6 @@ func TestCreateCategory_DuplicateName(t *testing.T) {
 	// Line 210
 	assert.Equal(t, http.StatusConflict, w.Code, "Expected 409 Conflict status")
 
-
 	var response map[string]interface{}
 	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.Contains(t, response["error"].(string), "category with this name already exists")
@@ -266,7 +266,7 @@ func TestUpdateCategory(t *testing.T) {
 	db.DB = database
 	user := CreateTestUser(database)
 	token := GetTestToken(user.ID, user.Username)
-	
+
 	category := models.Category{
 		UserID:    user.ID,
 		Name:      "Old Name",
@@ -284,7 +284,7 @@ func TestUpdateCategory(t *testing.T) {
 	}
 	body, _ := json.Marshal(updateData)
 
-    req, _ := http.NewRequest("PUT", "/api/categories/"+strconv.FormatUint(uint64(category.ID), 10), bytes.NewBuffer(body))
+	req, _ := http.NewRequest("PUT", "/api/categories/"+strconv.FormatUint(uint64(category.ID), 10), bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
@@ -331,7 +331,7 @@ func TestUpdateCategory_CircularReference(t *testing.T) {
 	}
 	body, _ := json.Marshal(updateData)
 
-    req, _ := http.NewRequest("PUT", "/api/categories/"+strconv.FormatUint(uint64(food.ID), 10), bytes.NewBuffer(body))
+	req, _ := http.NewRequest("PUT", "/api/categories/"+strconv.FormatUint(uint64(food.ID), 10), bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
@@ -367,7 +367,7 @@ func TestUpdateCategory_SelfParent(t *testing.T) {
 	}
 	body, _ := json.Marshal(updateData)
 
-    req, _ := http.NewRequest("PUT", "/api/categories/"+strconv.FormatUint(uint64(category.ID), 10), bytes.NewBuffer(body))
+	req, _ := http.NewRequest("PUT", "/api/categories/"+strconv.FormatUint(uint64(category.ID), 10), bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
@@ -406,7 +406,7 @@ func TestDeleteCategory_WithSubcategories(t *testing.T) {
 	router := SetupRouter()
 	router.DELETE("/api/categories/:id", controllers.AuthMiddleware(), controllers.DeleteCategory)
 
-    req, _ := http.NewRequest("DELETE", "/api/categories/"+strconv.FormatUint(uint64(parent.ID), 10), nil)
+	req, _ := http.NewRequest("DELETE", "/api/categories/"+strconv.FormatUint(uint64(parent.ID), 10), nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -444,7 +444,7 @@ func TestDeleteCategory_ForceDelete(t *testing.T) {
 	router := SetupRouter()
 	router.DELETE("/api/categories/:id", controllers.AuthMiddleware(), controllers.DeleteCategory)
 
-    req, _ := http.NewRequest("DELETE", "/api/categories/"+strconv.FormatUint(uint64(parent.ID), 10)+"?force=true", nil)
+	req, _ := http.NewRequest("DELETE", "/api/categories/"+strconv.FormatUint(uint64(parent.ID), 10)+"?force=true", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -454,4 +454,308 @@ func TestDeleteCategory_ForceDelete(t *testing.T) {
 	var count int64
 	database.Model(&models.Category{}).Where("id IN ?", []uint{parent.ID, subcategory.ID}).Count(&count)
 	assert.Equal(t, int64(0), count, "Both parent and child should be deleted")
-}
\ No newline at end of file
+}
+
+func TestDeleteCategory_ReassignTo(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	oldCategory := models.Category{UserID: user.ID, Name: "Old", Kind: models.CategoryExpense}
+	database.Create(&oldCategory)
+	newCategory := models.Category{UserID: user.ID, Name: "New", Kind: models.CategoryExpense}
+	database.Create(&newCategory)
+
+	child := models.Category{UserID: user.ID, Name: "Child", Kind: models.CategoryExpense, ParentID: &oldCategory.ID}
+	database.Create(&child)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+	txn := models.Transaction{UserID: user.ID, AccountID: account.ID, CategoryID: &oldCategory.ID, Amount: decimal.NewFromInt(-1000).Div(decimal.NewFromInt(100)), TxnDate: time.Now()}
+	database.Create(&txn)
+
+	budget := models.Budget{UserID: user.ID, PeriodStart: time.Now(), PeriodEnd: time.Now().AddDate(0, 1, 0), Currency: "USD"}
+	database.Create(&budget)
+	budgetItem := models.BudgetItem{BudgetID: budget.ID, CategoryID: oldCategory.ID, PlannedAmount: decimal.NewFromInt(5000).Div(decimal.NewFromInt(100))}
+	database.Create(&budgetItem)
+
+	router := SetupRouter()
+	router.DELETE("/api/categories/:id", controllers.AuthMiddleware(), controllers.DeleteCategory)
+
+	req, _ := http.NewRequest("DELETE", "/api/categories/"+strconv.FormatUint(uint64(oldCategory.ID), 10)+"?reassign_to="+strconv.FormatUint(uint64(newCategory.ID), 10), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "Expected 200 OK status")
+
+	var response struct {
+		Reassigned struct {
+			Transactions int64 `json:"transactions"`
+			Splits       int64 `json:"splits"`
+			BudgetItems  int64 `json:"budget_items"`
+			Children     int64 `json:"children"`
+		} `json:"reassigned"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, int64(1), response.Reassigned.Transactions)
+	assert.Equal(t, int64(1), response.Reassigned.BudgetItems)
+	assert.Equal(t, int64(1), response.Reassigned.Children)
+
+	var updatedTxn models.Transaction
+	database.First(&updatedTxn, txn.ID)
+	assert.Equal(t, newCategory.ID, *updatedTxn.CategoryID)
+
+	var updatedItem models.BudgetItem
+	database.First(&updatedItem, budgetItem.ID)
+	assert.Equal(t, newCategory.ID, updatedItem.CategoryID)
+
+	var updatedChild models.Category
+	database.First(&updatedChild, child.ID)
+	assert.Equal(t, newCategory.ID, *updatedChild.ParentID)
+
+	var deletedCount int64
+	database.Model(&models.Category{}).Where("id = ?", oldCategory.ID).Count(&deletedCount)
+	assert.Equal(t, int64(0), deletedCount, "old category should be deleted")
+}
+
+func TestMergeCategory(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	source := models.Category{UserID: user.ID, Name: "Dining", Kind: models.CategoryExpense}
+	database.Create(&source)
+	target := models.Category{UserID: user.ID, Name: "Food", Kind: models.CategoryExpense}
+	database.Create(&target)
+
+	child := models.Category{UserID: user.ID, Name: "Fast Food", Kind: models.CategoryExpense, ParentID: &source.ID}
+	database.Create(&child)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+	txn := models.Transaction{UserID: user.ID, AccountID: account.ID, CategoryID: &source.ID, Amount: decimal.NewFromInt(-1000).Div(decimal.NewFromInt(100)), TxnDate: time.Now()}
+	database.Create(&txn)
+
+	budget := models.Budget{UserID: user.ID, PeriodStart: time.Now(), PeriodEnd: time.Now().AddDate(0, 1, 0), Currency: "USD"}
+	database.Create(&budget)
+	budgetItem := models.BudgetItem{BudgetID: budget.ID, CategoryID: source.ID, PlannedAmount: decimal.NewFromInt(5000).Div(decimal.NewFromInt(100))}
+	database.Create(&budgetItem)
+
+	router := SetupRouter()
+	router.POST("/api/categories/:id/merge", controllers.AuthMiddleware(), controllers.MergeCategory)
+
+	mergeData := map[string]interface{}{"target_id": target.ID}
+	body, _ := json.Marshal(mergeData)
+	req, _ := http.NewRequest("POST", "/api/categories/"+strconv.FormatUint(uint64(source.ID), 10)+"/merge", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "Expected 200 OK status")
+
+	var response struct {
+		Merged struct {
+			Transactions int64 `json:"transactions"`
+			BudgetItems  int64 `json:"budget_items"`
+			Children     int64 `json:"children"`
+		} `json:"merged"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, int64(1), response.Merged.Transactions)
+	assert.Equal(t, int64(1), response.Merged.BudgetItems)
+	assert.Equal(t, int64(1), response.Merged.Children)
+
+	var updatedTxn models.Transaction
+	database.First(&updatedTxn, txn.ID)
+	assert.Equal(t, target.ID, *updatedTxn.CategoryID)
+
+	var updatedChild models.Category
+	database.First(&updatedChild, child.ID)
+	assert.Equal(t, target.ID, *updatedChild.ParentID)
+
+	var deletedCount int64
+	database.Model(&models.Category{}).Where("id = ?", source.ID).Count(&deletedCount)
+	assert.Equal(t, int64(0), deletedCount, "source category should be deleted")
+}
+
+func TestMergeCategory_RejectsMergeIntoOwnDescendant(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	parent := models.Category{UserID: user.ID, Name: "Food", Kind: models.CategoryExpense}
+	database.Create(&parent)
+	child := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense, ParentID: &parent.ID}
+	database.Create(&child)
+
+	router := SetupRouter()
+	router.POST("/api/categories/:id/merge", controllers.AuthMiddleware(), controllers.MergeCategory)
+
+	mergeData := map[string]interface{}{"target_id": child.ID}
+	body, _ := json.Marshal(mergeData)
+	req, _ := http.NewRequest("POST", "/api/categories/"+strconv.FormatUint(uint64(parent.ID), 10)+"/merge", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Expected 400 Bad Request status")
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Contains(t, response["error"].(string), "descendant", "Error message should indicate descendant cycle")
+
+	var stillExists int64
+	database.Model(&models.Category{}).Where("id = ?", parent.ID).Count(&stillExists)
+	assert.Equal(t, int64(1), stillExists, "parent category should not be deleted on rejected merge")
+}
+
+func TestDeleteCategory_ForceDeleteTombstonesDescendants(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	parent := models.Category{UserID: user.ID, Name: "Parent Category", Kind: models.CategoryExpense}
+	database.Create(&parent)
+	subcategory := models.Category{UserID: user.ID, Name: "Subcategory", Kind: models.CategoryExpense, ParentID: &parent.ID}
+	database.Create(&subcategory)
+
+	router := SetupRouter()
+	router.DELETE("/api/categories/:id", controllers.AuthMiddleware(), controllers.DeleteCategory)
+
+	req, _ := http.NewRequest("DELETE", "/api/categories/"+strconv.FormatUint(uint64(parent.ID), 10)+"?force=true", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var tombstoned models.Category
+	err := database.Unscoped().Where("id = ?", subcategory.ID).First(&tombstoned).Error
+	assert.NoError(t, err, "force-deleted subcategory should still exist, just tombstoned")
+	assert.True(t, tombstoned.DeletedAt.Valid)
+	assert.NotNil(t, tombstoned.PurgeAfter)
+}
+
+func TestDeleteCategory_Purge(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	category := models.Category{UserID: user.ID, Name: "Misc", Kind: models.CategoryExpense}
+	database.Create(&category)
+
+	router := SetupRouter()
+	router.DELETE("/api/categories/:id", controllers.AuthMiddleware(), controllers.DeleteCategory)
+
+	req, _ := http.NewRequest("DELETE", "/api/categories/"+strconv.FormatUint(uint64(category.ID), 10)+"?purge=true", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var count int64
+	database.Unscoped().Model(&models.Category{}).Where("id = ?", category.ID).Count(&count)
+	assert.Equal(t, int64(0), count, "?purge=true should hard-delete immediately")
+}
+
+func TestRestoreCategory(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	category := models.Category{UserID: user.ID, Name: "Misc", Kind: models.CategoryExpense}
+	database.Create(&category)
+	database.Delete(&category)
+
+	router := SetupRouter()
+	router.POST("/api/categories/:id/restore", controllers.AuthMiddleware(), controllers.RestoreCategory)
+
+	req, _ := http.NewRequest("POST", "/api/categories/"+strconv.FormatUint(uint64(category.ID), 10)+"/restore", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var restored models.Category
+	err := database.Where("id = ?", category.ID).First(&restored).Error
+	assert.NoError(t, err, "category should be visible again through the normal scope")
+}
+
+func TestRestoreCategory_RejectsWhenParentStillDeleted(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	parent := models.Category{UserID: user.ID, Name: "Parent", Kind: models.CategoryExpense}
+	database.Create(&parent)
+	child := models.Category{UserID: user.ID, Name: "Child", Kind: models.CategoryExpense, ParentID: &parent.ID}
+	database.Create(&child)
+	database.Delete(&child)
+	database.Delete(&parent)
+
+	router := SetupRouter()
+	router.POST("/api/categories/:id/restore", controllers.AuthMiddleware(), controllers.RestoreCategory)
+
+	req, _ := http.NewRequest("POST", "/api/categories/"+strconv.FormatUint(uint64(child.ID), 10)+"/restore", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code, "restoring a child should fail while its parent is still deleted")
+
+	req, _ = http.NewRequest("POST", "/api/categories/"+strconv.FormatUint(uint64(child.ID), 10)+"/restore?restore_ancestors=true", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "?restore_ancestors=true should restore the parent too")
+
+	var restoredParent models.Category
+	err := database.Where("id = ?", parent.ID).First(&restoredParent).Error
+	assert.NoError(t, err)
+}
+
+func TestGetCategories_IncludeDeleted(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	category := models.Category{UserID: user.ID, Name: "Misc", Kind: models.CategoryExpense}
+	database.Create(&category)
+	database.Delete(&category)
+
+	router := SetupRouter()
+	router.GET("/api/categories", controllers.AuthMiddleware(), controllers.GetCategories)
+
+	req, _ := http.NewRequest("GET", "/api/categories", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var withoutDeleted []models.Category
+	json.Unmarshal(w.Body.Bytes(), &withoutDeleted)
+	assert.Empty(t, withoutDeleted)
+
+	req, _ = http.NewRequest("GET", "/api/categories?include_deleted=true", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var withDeleted []models.Category
+	json.Unmarshal(w.Body.Bytes(), &withDeleted)
+	assert.Len(t, withDeleted, 1)
+	assert.Equal(t, "Misc", withDeleted[0].Name)
+}
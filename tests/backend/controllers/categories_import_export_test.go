@@ -0,0 +1,139 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportCategories_Template(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	router := SetupRouter()
+	router.POST("/api/categories/import", controllers.AuthMiddleware(), controllers.ImportCategories)
+
+	req, _ := http.NewRequest("POST", "/api/categories/import?template=personal", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Created int `json:"created"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 14, response.Created)
+
+	var electric models.Category
+	err := database.Where("user_id = ? AND name = ?", user.ID, "Electric").First(&electric).Error
+	assert.NoError(t, err, "a 3-level-deep template category should have been created")
+
+	var utilities models.Category
+	database.First(&utilities, *electric.ParentID)
+	assert.Equal(t, "Utilities", utilities.Name)
+}
+
+func TestImportCategories_CSV(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	router := SetupRouter()
+	router.POST("/api/categories/import", controllers.AuthMiddleware(), controllers.ImportCategories)
+
+	csv := "path,kind,description\n" +
+		"Hobbies,expense,\n" +
+		"Hobbies/Photography,expense,Camera gear and prints\n" +
+		"Hobbies/Photography/Extra,expense,\n" // 4 levels deep, should error
+
+	req := buildImportRequest(t, "/api/categories/import", "chart.csv", csv, token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Created int `json:"created"`
+		Results []struct {
+			Path   string `json:"path"`
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		} `json:"results"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	assert.Equal(t, 2, response.Created)
+	if assert.Len(t, response.Results, 3) {
+		assert.Equal(t, "error", response.Results[2].Status)
+		assert.Contains(t, response.Results[2].Error, "nesting too deep")
+	}
+}
+
+func TestImportCategories_DuplicateRowSkipped(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	existing := models.Category{UserID: user.ID, Name: "Housing", Kind: models.CategoryExpense}
+	database.Create(&existing)
+
+	router := SetupRouter()
+	router.POST("/api/categories/import", controllers.AuthMiddleware(), controllers.ImportCategories)
+
+	req, _ := http.NewRequest("POST", "/api/categories/import?template=personal", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Results []struct {
+			Path   string `json:"path"`
+			Status string `json:"status"`
+		} `json:"results"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "skipped", response.Results[0].Status, "the pre-existing Housing category should be skipped, not duplicated")
+
+	var housingCount int64
+	database.Model(&models.Category{}).Where("user_id = ? AND name = ?", user.ID, "Housing").Count(&housingCount)
+	assert.Equal(t, int64(1), housingCount)
+}
+
+func TestExportCategories_CSV(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	parent := models.Category{UserID: user.ID, Name: "Housing", Kind: models.CategoryExpense}
+	database.Create(&parent)
+	child := models.Category{UserID: user.ID, Name: "Rent", Kind: models.CategoryExpense, ParentID: &parent.ID}
+	database.Create(&child)
+
+	router := SetupRouter()
+	router.GET("/api/categories/export", controllers.AuthMiddleware(), controllers.ExportCategories)
+
+	req, _ := http.NewRequest("GET", "/api/categories/export", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Housing/Rent,expense")
+	assert.Equal(t, `attachment; filename="categories.csv"`, w.Header().Get("Content-Disposition"))
+}
@@ -0,0 +1,145 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCategories_IncludesSystemCategories(t *testing.T) {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.CategoryOverride{})
+	db.DB = database
+
+	systemOwner := models.User{Username: "system-categories", Email: "system@example.com", Role: models.UserRoleUser}
+	database.Create(&systemOwner)
+	systemCategory := models.Category{UserID: systemOwner.ID, IsSystem: true, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&systemCategory)
+
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+	ownCategory := models.Category{UserID: user.ID, Name: "Hobbies", Kind: models.CategoryExpense}
+	database.Create(&ownCategory)
+
+	router := SetupRouter()
+	router.GET("/api/categories", controllers.AuthMiddleware(), controllers.GetCategories)
+
+	req, _ := http.NewRequest("GET", "/api/categories", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.Category
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response, 2, "should include both the user's own category and the system category")
+}
+
+func TestHideSystemCategory_RemovesItFromGetCategories(t *testing.T) {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.CategoryOverride{})
+	db.DB = database
+
+	systemOwner := models.User{Username: "system-categories", Email: "system@example.com", Role: models.UserRoleUser}
+	database.Create(&systemOwner)
+	systemCategory := models.Category{UserID: systemOwner.ID, IsSystem: true, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&systemCategory)
+
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	router := SetupRouter()
+	router.GET("/api/categories", controllers.AuthMiddleware(), controllers.GetCategories)
+	router.POST("/api/categories/:id/hide", controllers.AuthMiddleware(), controllers.HideSystemCategory)
+
+	hideReq, _ := http.NewRequest("POST", "/api/categories/"+strconv.FormatUint(uint64(systemCategory.ID), 10)+"/hide", nil)
+	hideReq.Header.Set("Authorization", "Bearer "+token)
+	hideW := httptest.NewRecorder()
+	router.ServeHTTP(hideW, hideReq)
+	assert.Equal(t, http.StatusOK, hideW.Code)
+
+	listReq, _ := http.NewRequest("GET", "/api/categories", nil)
+	listReq.Header.Set("Authorization", "Bearer "+token)
+	listW := httptest.NewRecorder()
+	router.ServeHTTP(listW, listReq)
+
+	var response []models.Category
+	json.Unmarshal(listW.Body.Bytes(), &response)
+	assert.Len(t, response, 0, "hidden system category should not be returned")
+
+	var override models.CategoryOverride
+	err := database.Where("user_id = ? AND system_category_id = ?", user.ID, systemCategory.ID).First(&override).Error
+	assert.NoError(t, err)
+	assert.True(t, override.Hidden)
+}
+
+func TestUpdateCategoryOverride_AppliesDisplayNameAndDescription(t *testing.T) {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.CategoryOverride{})
+	db.DB = database
+
+	systemOwner := models.User{Username: "system-categories", Email: "system@example.com", Role: models.UserRoleUser}
+	database.Create(&systemOwner)
+	systemCategory := models.Category{UserID: systemOwner.ID, IsSystem: true, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&systemCategory)
+
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	router := SetupRouter()
+	router.GET("/api/categories", controllers.AuthMiddleware(), controllers.GetCategories)
+	router.PATCH("/api/categories/:id/override", controllers.AuthMiddleware(), controllers.UpdateCategoryOverride)
+
+	overrideBody, _ := json.Marshal(map[string]interface{}{
+		"display_name": "Food & Drink",
+		"description":  "Renamed for my household",
+	})
+	overrideReq, _ := http.NewRequest("PATCH", "/api/categories/"+strconv.FormatUint(uint64(systemCategory.ID), 10)+"/override", bytes.NewBuffer(overrideBody))
+	overrideReq.Header.Set("Content-Type", "application/json")
+	overrideReq.Header.Set("Authorization", "Bearer "+token)
+	overrideW := httptest.NewRecorder()
+	router.ServeHTTP(overrideW, overrideReq)
+	assert.Equal(t, http.StatusOK, overrideW.Code)
+
+	listReq, _ := http.NewRequest("GET", "/api/categories", nil)
+	listReq.Header.Set("Authorization", "Bearer "+token)
+	listW := httptest.NewRecorder()
+	router.ServeHTTP(listW, listReq)
+
+	var response []models.Category
+	json.Unmarshal(listW.Body.Bytes(), &response)
+	assert.Len(t, response, 1)
+	assert.Equal(t, "Food & Drink", response[0].Name)
+	assert.Equal(t, "Renamed for my household", *response[0].Description)
+}
+
+func TestHideSystemCategory_RejectsNonSystemCategory(t *testing.T) {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.CategoryOverride{})
+	db.DB = database
+
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+	ownCategory := models.Category{UserID: user.ID, Name: "Hobbies", Kind: models.CategoryExpense}
+	database.Create(&ownCategory)
+
+	router := SetupRouter()
+	router.POST("/api/categories/:id/hide", controllers.AuthMiddleware(), controllers.HideSystemCategory)
+
+	req, _ := http.NewRequest("POST", "/api/categories/"+strconv.FormatUint(uint64(ownCategory.ID), 10)+"/hide", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code, "hiding a user's own (non-system) category should fail")
+}
@@ -0,0 +1,107 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCategoryRule_AutoCategorizesNewTransaction(t *testing.T) {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.CategoryRule{})
+	db.DB = database
+
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+	category := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&category)
+
+	router := SetupRouter()
+	router.POST("/api/category-rules", controllers.AuthMiddleware(), controllers.CreateCategoryRule)
+	router.POST("/api/transactions", controllers.AuthMiddleware(), controllers.CreateTransaction)
+
+	ruleBody, _ := json.Marshal(map[string]interface{}{
+		"pattern":     "whole foods",
+		"match_field": "description",
+		"category_id": category.ID,
+	})
+	ruleReq, _ := http.NewRequest("POST", "/api/category-rules", bytes.NewBuffer(ruleBody))
+	ruleReq.Header.Set("Content-Type", "application/json")
+	ruleReq.Header.Set("Authorization", "Bearer "+token)
+	ruleW := httptest.NewRecorder()
+	router.ServeHTTP(ruleW, ruleReq)
+	assert.Equal(t, http.StatusCreated, ruleW.Code)
+
+	txnBody, _ := json.Marshal(map[string]interface{}{
+		"account_id":   account.ID,
+		"amount_cents": -2500,
+		"description":  "WHOLE FOODS MARKET #123",
+		"txn_date":     "2026-07-01",
+	})
+	txnReq, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(txnBody))
+	txnReq.Header.Set("Content-Type", "application/json")
+	txnReq.Header.Set("Authorization", "Bearer "+token)
+	txnW := httptest.NewRecorder()
+	router.ServeHTTP(txnW, txnReq)
+	assert.Equal(t, http.StatusCreated, txnW.Code)
+
+	var response models.Transaction
+	json.Unmarshal(txnW.Body.Bytes(), &response)
+	if assert.NotNil(t, response.CategoryID) {
+		assert.Equal(t, category.ID, *response.CategoryID)
+	}
+}
+
+func TestTestCategoryRule_DoesNotMutateTransactions(t *testing.T) {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.CategoryRule{})
+	db.DB = database
+
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+	category := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&category)
+	rule := models.CategoryRule{UserID: user.ID, Pattern: "whole foods", MatchField: models.CategoryRuleMatchDescription, CategoryID: category.ID, Enabled: true}
+	database.Create(&rule)
+
+	txnDate, _ := time.Parse("2006-01-02", "2026-07-01")
+	txn := models.Transaction{UserID: user.ID, AccountID: account.ID, Amount: decimal.NewFromInt(-2500).Div(decimal.NewFromInt(100)), Description: "Whole Foods #9", TxnDate: txnDate}
+	database.Create(&txn)
+
+	router := SetupRouter()
+	router.POST("/api/categories/rules/:id/test", controllers.AuthMiddleware(), controllers.TestCategoryRule)
+
+	req, _ := http.NewRequest("POST", "/api/categories/rules/"+strconv.FormatUint(uint64(rule.ID), 10)+"/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		MatchedCount int `json:"matched_count"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 1, response.MatchedCount)
+
+	var stored models.Transaction
+	database.First(&stored, txn.ID)
+	assert.Nil(t, stored.CategoryID, "test endpoint must not categorize transactions")
+}
@@ -0,0 +1,132 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCategoryTree_NestsChildrenUnderParents(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	housing := models.Category{UserID: user.ID, Name: "Housing", Kind: models.CategoryExpense}
+	database.Create(&housing)
+	rent := models.Category{UserID: user.ID, Name: "Rent", Kind: models.CategoryExpense, ParentID: &housing.ID}
+	database.Create(&rent)
+
+	router := SetupRouter()
+	router.GET("/api/categories/tree", controllers.AuthMiddleware(), controllers.GetCategoryTree)
+
+	req, _ := http.NewRequest("GET", "/api/categories/tree", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var roots []controllers.CategoryNode
+	json.Unmarshal(w.Body.Bytes(), &roots)
+	if assert.Len(t, roots, 1) {
+		assert.Equal(t, "Housing", roots[0].Name)
+		if assert.Len(t, roots[0].Children, 1) {
+			assert.Equal(t, "Rent", roots[0].Children[0].Name)
+		}
+	}
+}
+
+func TestGetCategoryTree_IncludeTotalsRollsUpToParent(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	housing := models.Category{UserID: user.ID, Name: "Housing", Kind: models.CategoryExpense}
+	database.Create(&housing)
+	rent := models.Category{UserID: user.ID, Name: "Rent", Kind: models.CategoryExpense, ParentID: &housing.ID}
+	database.Create(&rent)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+	database.Create(&models.Transaction{
+		UserID: user.ID, AccountID: account.ID, CategoryID: &rent.ID,
+		Amount: decimal.NewFromInt(-150000).Div(decimal.NewFromInt(100)), Description: "Rent", TxnDate: time.Now(),
+	})
+	database.Create(&models.Transaction{
+		UserID: user.ID, AccountID: account.ID, CategoryID: &housing.ID,
+		Amount: decimal.NewFromInt(-2000).Div(decimal.NewFromInt(100)), Description: "Misc", TxnDate: time.Now(),
+	})
+
+	router := SetupRouter()
+	router.GET("/api/categories/tree", controllers.AuthMiddleware(), controllers.GetCategoryTree)
+
+	req, _ := http.NewRequest("GET", "/api/categories/tree?include_totals=true", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var roots []controllers.CategoryNode
+	json.Unmarshal(w.Body.Bytes(), &roots)
+	if assert.Len(t, roots, 1) {
+		assert.Equal(t, int64(2000), roots[0].SelfCents)
+		assert.Equal(t, int64(152000), roots[0].SubtreeCents, "subtree total should roll up the child's spend")
+		if assert.Len(t, roots[0].Children, 1) {
+			assert.Equal(t, int64(150000), roots[0].Children[0].SelfCents)
+			assert.Equal(t, int64(150000), roots[0].Children[0].SubtreeCents)
+		}
+	}
+}
+
+func TestGetCategoryTree_MaxDepthFlattensButKeepsSubtreeTotals(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	housing := models.Category{UserID: user.ID, Name: "Housing", Kind: models.CategoryExpense}
+	database.Create(&housing)
+	utilities := models.Category{UserID: user.ID, Name: "Utilities", Kind: models.CategoryExpense, ParentID: &housing.ID}
+	database.Create(&utilities)
+	electric := models.Category{UserID: user.ID, Name: "Electric", Kind: models.CategoryExpense, ParentID: &utilities.ID}
+	database.Create(&electric)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+	database.Create(&models.Transaction{
+		UserID: user.ID, AccountID: account.ID, CategoryID: &electric.ID,
+		Amount: decimal.NewFromInt(-5000).Div(decimal.NewFromInt(100)), Description: "Electric bill", TxnDate: time.Now(),
+	})
+
+	router := SetupRouter()
+	router.GET("/api/categories/tree", controllers.AuthMiddleware(), controllers.GetCategoryTree)
+
+	req, _ := http.NewRequest("GET", "/api/categories/tree?include_totals=true&max_depth=2", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var roots []controllers.CategoryNode
+	json.Unmarshal(w.Body.Bytes(), &roots)
+	if assert.Len(t, roots, 1) {
+		if assert.Len(t, roots[0].Children, 1) {
+			assert.Equal(t, "Utilities", roots[0].Children[0].Name)
+			assert.Empty(t, roots[0].Children[0].Children, "grandchildren should be pruned at max_depth=2")
+			assert.Equal(t, int64(5000), roots[0].Children[0].SubtreeCents, "pruned descendant spend should still be folded into subtree_cents")
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedDefaultCategories_Tree(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+
+	created, err := controllers.SeedDefaultCategories(user.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 13, created)
+
+	var salary models.Category
+	err = database.Where("user_id = ? AND name = ?", user.ID, "Salary").First(&salary).Error
+	assert.NoError(t, err)
+	assert.Equal(t, models.CategoryIncome, salary.Kind)
+
+	var income models.Category
+	database.First(&income, *salary.ParentID)
+	assert.Equal(t, "Income", income.Name)
+	assert.Nil(t, income.ParentID)
+
+	var groceries models.Category
+	err = database.Where("user_id = ? AND name = ?", user.ID, "Groceries").First(&groceries).Error
+	assert.NoError(t, err)
+	assert.Equal(t, models.CategoryExpense, groceries.Kind)
+}
+
+func TestSeedDefaultCategories_Idempotent(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+
+	created, err := controllers.SeedDefaultCategories(user.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 13, created)
+
+	createdAgain, err := controllers.SeedDefaultCategories(user.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, createdAgain, "re-seeding must not create duplicates")
+
+	var count int64
+	database.Model(&models.Category{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Equal(t, int64(13), count)
+}
+
+func TestSeedDefaultCategories_LocaleFallback(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+
+	created, err := controllers.SeedDefaultCategories(user.ID, "xx")
+	assert.NoError(t, err, "an unknown locale should fall back to en rather than error")
+	assert.Equal(t, 13, created)
+}
+
+func TestSeedDefaultCategories_Locale(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+
+	created, err := controllers.SeedDefaultCategories(user.ID, "nb")
+	assert.NoError(t, err)
+	assert.Equal(t, 13, created)
+
+	var lonn models.Category
+	err = database.Where("user_id = ? AND name = ?", user.ID, "Lønn").First(&lonn).Error
+	assert.NoError(t, err)
+}
+
+func TestSeedCategories_Endpoint(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	router := SetupRouter()
+	router.POST("/api/categories/seed", controllers.AuthMiddleware(), controllers.SeedCategories)
+
+	req, _ := http.NewRequest("POST", "/api/categories/seed", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Created int `json:"created"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 13, response.Created)
+}
@@ -0,0 +1,97 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/fx"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubFxProvider struct{}
+
+func (stubFxProvider) FetchRate(base, quote string, date time.Time) (float64, error) {
+	return 9.5, nil
+}
+
+func TestGetFxRates(t *testing.T) {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.FxRate{})
+	db.DB = database
+	fx.SetProvider(stubFxProvider{})
+
+	router := SetupRouter()
+	router.GET("/api/fx/rates", controllers.GetFxRates)
+
+	req, _ := http.NewRequest("GET", "/api/fx/rates?base=USD&quote=NOK&from=2026-07-01&to=2026-07-03", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Base  string `json:"base"`
+		Quote string `json:"quote"`
+		Rates []struct {
+			Date string  `json:"date"`
+			Rate float64 `json:"rate"`
+		} `json:"rates"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "USD", response.Base)
+	assert.Equal(t, "NOK", response.Quote)
+	assert.Equal(t, 3, len(response.Rates), "should return one rate per day in the range")
+	assert.Equal(t, 9.5, response.Rates[0].Rate)
+}
+
+func TestRefreshFxRates(t *testing.T) {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.FxRate{})
+	db.DB = database
+	fx.SetProvider(stubFxProvider{})
+
+	user := CreateTestUser(database)
+	database.Create(&models.Account{UserID: user.ID, Name: "US Checking", Type: "checking", Currency: "USD"})
+	database.Create(&models.Account{UserID: user.ID, Name: "Norwegian Checking", Type: "checking", Currency: "NOK"})
+
+	router := SetupRouter()
+	router.POST("/api/fx/rates/refresh", controllers.RefreshFxRates)
+
+	req, _ := http.NewRequest("POST", "/api/fx/rates/refresh", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Results []struct {
+			Base  string  `json:"base"`
+			Quote string  `json:"quote"`
+			Rate  float64 `json:"rate"`
+		} `json:"results"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NotEmpty(t, response.Results, "should refresh at least the USD/NOK pair in use")
+}
+
+func TestGetFxRates_RequiresDateRange(t *testing.T) {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.FxRate{})
+	db.DB = database
+
+	router := SetupRouter()
+	router.GET("/api/fx/rates", controllers.GetFxRates)
+
+	req, _ := http.NewRequest("GET", "/api/fx/rates?base=USD&quote=NOK", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
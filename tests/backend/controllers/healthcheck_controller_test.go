@@ -1,17 +1,23 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/controllers/health"
 	"Personal-Finance-Tracker-backend/db"
 	"Personal-Finance-Tracker-backend/models"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 	"gorm.io/driver/sqlite"
@@ -135,9 +141,10 @@ func (suite *HealthCheckControllerTestSuite) TestDetailedHealthCheck_Success() {
 	suite.NoError(err, "Timestamp should be in RFC3339 format")
 
 	// Verify database service check
-	dbStatus, exists := response.Services["database"]
+	dbResult, exists := response.Services["database"]
 	suite.True(exists, "Database service should be checked")
-	suite.Equal("healthy", dbStatus, "Database should be healthy in tests")
+	suite.Equal("healthy", dbResult.Status, "Database should be healthy in tests")
+	suite.True(dbResult.Critical, "Database check should be marked critical")
 }
 
 func (suite *HealthCheckControllerTestSuite) TestDetailedHealthCheck_DatabaseConnected() {
@@ -153,7 +160,7 @@ func (suite *HealthCheckControllerTestSuite) TestDetailedHealthCheck_DatabaseCon
 
 	// Database should be reported as healthy since we use in-memory SQLite
 	suite.Equal("healthy", response.Status)
-	suite.Equal("healthy", response.Services["database"])
+	suite.Equal("healthy", response.Services["database"].Status)
 }
 
 // ============================================
@@ -411,7 +418,92 @@ func (suite *HealthCheckControllerTestSuite) TestDetailedHealthCheck_DatabaseSta
 
 	// Should successfully get database status
 	suite.Equal("healthy", response.Status)
-	suite.Equal("healthy", response.Services["database"])
+	suite.Equal("healthy", response.Services["database"].Status)
+}
+
+// ============================================
+// TEST 9: Pluggable Checker Registry
+// ============================================
+func TestHealthRegistry_CriticalFailureIsUnhealthy(t *testing.T) {
+	reg := health.NewRegistry(0)
+	reg.Register(health.NewPingChecker("database", time.Second, true, func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}))
+
+	results := reg.CheckAll(context.Background())
+	dbResult := results["database"]
+
+	assert.Equal(t, "unhealthy", dbResult.Status)
+	assert.True(t, health.AnyCriticalFailed(results))
+	assert.Equal(t, "unhealthy", health.Overall(results))
+}
+
+func TestHealthRegistry_NonCriticalFailureDegradesOnly(t *testing.T) {
+	reg := health.NewRegistry(0)
+	reg.Register(health.NewPingChecker("database", time.Second, true, func(ctx context.Context) error {
+		return nil
+	}))
+	reg.Register(health.NewPingChecker("smtp", time.Second, false, func(ctx context.Context) error {
+		return errors.New("smtp timeout")
+	}))
+
+	results := reg.CheckAll(context.Background())
+
+	assert.False(t, health.AnyCriticalFailed(results), "non-critical failure should not fail readiness")
+	assert.Equal(t, "degraded", health.Overall(results))
+	assert.Equal(t, "degraded", results["smtp"].Status)
+	assert.Equal(t, "healthy", results["database"].Status)
+}
+
+func TestHealthRegistry_ConcurrentChecksRunInParallel(t *testing.T) {
+	reg := health.NewRegistry(0)
+	const checkDelay = 100 * time.Millisecond
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("dep-%d", i)
+		reg.Register(health.NewPingChecker(name, time.Second, false, func(ctx context.Context) error {
+			time.Sleep(checkDelay)
+			return nil
+		}))
+	}
+
+	start := time.Now()
+	results := reg.CheckAll(context.Background())
+	elapsed := time.Since(start)
+
+	assert.Len(t, results, 5)
+	// If checks ran sequentially this would take >= 500ms; concurrently it
+	// should stay close to a single checkDelay.
+	assert.Less(t, elapsed, 3*checkDelay, "checks should run concurrently, not sequentially")
+}
+
+func TestHealthRegistry_RespectsPerCheckTimeout(t *testing.T) {
+	reg := health.NewRegistry(0)
+	reg.Register(health.NewPingChecker("slow-dep", 10*time.Millisecond, false, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	results := reg.CheckAll(context.Background())
+
+	assert.Equal(t, "degraded", results["slow-dep"].Status)
+	assert.NotEmpty(t, results["slow-dep"].Error)
+}
+
+func TestHealthRegistry_CachesResultsWithinTTL(t *testing.T) {
+	reg := health.NewRegistry(50 * time.Millisecond)
+	var calls int32
+	reg.Register(health.NewPingChecker("database", time.Second, true, func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}))
+
+	reg.CheckAll(context.Background())
+	reg.CheckAll(context.Background())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "second call within TTL should reuse cached results")
+
+	time.Sleep(60 * time.Millisecond)
+	reg.CheckAll(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "call after TTL expiry should re-run the checker")
 }
 
 // ============================================
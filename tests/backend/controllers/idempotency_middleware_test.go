@@ -0,0 +1,98 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIdempotencyMiddlewareReplaysCachedResponse asserts that replaying the
+// same POST with the same Idempotency-Key and body returns the original
+// response without creating a second Transaction row.
+func TestIdempotencyMiddlewareReplaysCachedResponse(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+
+	router := SetupRouter()
+	router.POST("/api/transactions", controllers.AuthMiddleware(), middleware.IdempotencyMiddleware(), controllers.CreateTransaction)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"account_id":   account.ID,
+		"amount_cents": -1500,
+		"description":  "Coffee",
+		"txn_date":     time.Now().Format("2006-01-02"),
+	})
+
+	makeRequest := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Idempotency-Key", "fixed-key-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := makeRequest()
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	second := makeRequest()
+	assert.Equal(t, first.Code, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+
+	var count int64
+	database.Model(&models.Transaction{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestIdempotencyMiddlewareConflictsOnBodyMismatch asserts that reusing the
+// same key with a different request body is rejected rather than replayed.
+func TestIdempotencyMiddlewareConflictsOnBodyMismatch(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+
+	router := SetupRouter()
+	router.POST("/api/transactions", controllers.AuthMiddleware(), middleware.IdempotencyMiddleware(), controllers.CreateTransaction)
+
+	makeRequest := func(amount int) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{
+			"account_id":   account.ID,
+			"amount_cents": amount,
+			"description":  "Coffee",
+			"txn_date":     time.Now().Format("2006-01-02"),
+		})
+		req, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Idempotency-Key", "fixed-key-2")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := makeRequest(-1500)
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	second := makeRequest(-2000)
+	assert.Equal(t, http.StatusConflict, second.Code)
+}
@@ -0,0 +1,103 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildImportRequest(t *testing.T, url, filename, content, token string) *http.Request {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("file", filename)
+	assert.NoError(t, err)
+	part.Write([]byte(content))
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", url, &buf)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestImportTransactionsCSV(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking", InitialBalanceCents: 1000, CurrentBalance: decimal.NewFromInt(1000).Div(decimal.NewFromInt(100))}
+	database.Create(&account)
+
+	router := SetupRouter()
+	router.POST("/api/accounts/:id/import", controllers.AuthMiddleware(), controllers.ImportTransactions)
+
+	csv := "date,payee,amount\n2024-01-02,Coffee Shop,-4.50\n2024-01-03,Paycheck,1000.00\n"
+
+	req := buildImportRequest(t, "/api/accounts/1/import", "statement.csv", csv, token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "Expected 200 OK status")
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, float64(2), response["imported"], "Both rows should import")
+
+	var transactionCount int64
+	database.Model(&models.Transaction{}).Where("account_id = ?", account.ID).Count(&transactionCount)
+	assert.Equal(t, int64(2), transactionCount, "Both transactions should be persisted")
+
+	var updatedAccount models.Account
+	database.First(&updatedAccount, account.ID)
+	assert.True(t, decimal.NewFromInt(1000+99550).Div(decimal.NewFromInt(100)).Equal(updatedAccount.CurrentBalance), "Account balance should reflect imported transactions")
+
+	// Re-importing the same statement should be detected as all duplicates.
+	req2 := buildImportRequest(t, "/api/accounts/1/import", "statement.csv", csv, token)
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, req2)
+
+	assert.Equal(t, http.StatusOK, w2.Code)
+	var response2 map[string]interface{}
+	json.Unmarshal(w2.Body.Bytes(), &response2)
+	assert.Equal(t, float64(0), response2["imported"], "Re-importing the same file should yield zero new rows")
+
+	database.Model(&models.Transaction{}).Where("account_id = ?", account.ID).Count(&transactionCount)
+	assert.Equal(t, int64(2), transactionCount, "No duplicate transactions should be created")
+}
+
+func TestImportTransactionsDryRun(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking", InitialBalanceCents: 0, CurrentBalance: decimal.Zero}
+	database.Create(&account)
+
+	router := SetupRouter()
+	router.POST("/api/accounts/:id/import", controllers.AuthMiddleware(), controllers.ImportTransactions)
+
+	csv := "date,payee,amount\n2024-01-02,Coffee Shop,-4.50\n"
+
+	req := buildImportRequest(t, "/api/accounts/1/import?dry_run=true", "statement.csv", csv, token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var transactionCount int64
+	database.Model(&models.Transaction{}).Where("account_id = ?", account.ID).Count(&transactionCount)
+	assert.Equal(t, int64(0), transactionCount, "Dry run must not write any transactions")
+}
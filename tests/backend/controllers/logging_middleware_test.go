@@ -0,0 +1,74 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// TestRequestLoggerMiddlewareGeneratesAndEchoesRequestID asserts a request
+// with no X-Request-ID gets a generated one back on the response, and that
+// the same value is reachable from the handler via c.MustGet("request_id").
+func TestRequestLoggerMiddlewareGeneratesAndEchoesRequestID(t *testing.T) {
+	router := gin.New()
+	router.Use(middleware.RequestLoggerMiddleware(zap.NewNop()))
+
+	var seenInHandler string
+	router.GET("/ping", func(c *gin.Context) {
+		seenInHandler = c.MustGet("request_id").(string)
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	req, _ := http.NewRequest("GET", "/ping", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	echoed := w.Header().Get(middleware.RequestIDHeader)
+	assert.NotEmpty(t, echoed)
+	assert.Equal(t, echoed, seenInHandler)
+}
+
+// TestRequestLoggerMiddlewarePropagatesInboundRequestID asserts a caller's
+// own X-Request-ID is reused rather than overwritten, so a retried request
+// still correlates with its first attempt.
+func TestRequestLoggerMiddlewarePropagatesInboundRequestID(t *testing.T) {
+	router := gin.New()
+	router.Use(middleware.RequestLoggerMiddleware(zap.NewNop()))
+	router.GET("/ping", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	req, _ := http.NewRequest("GET", "/ping", nil)
+	req.Header.Set(middleware.RequestIDHeader, "caller-supplied-id")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "caller-supplied-id", w.Header().Get(middleware.RequestIDHeader))
+}
+
+// TestRecoveryMiddlewareIncludesRequestIDOnPanic asserts a panicking handler
+// still returns the request's correlation ID in the JSON error body, not
+// just the response header, so a caller reporting a 500 can quote one ID.
+func TestRecoveryMiddlewareIncludesRequestIDOnPanic(t *testing.T) {
+	router := gin.New()
+	router.Use(middleware.RecoveryMiddleware(zap.NewNop()))
+	router.Use(middleware.RequestLoggerMiddleware(zap.NewNop()))
+	router.GET("/boom", func(c *gin.Context) {
+		panic("kaboom")
+	})
+
+	req, _ := http.NewRequest("GET", "/boom", nil)
+	req.Header.Set(middleware.RequestIDHeader, "panic-request-id")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "panic-request-id")
+}
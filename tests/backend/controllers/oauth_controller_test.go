@@ -0,0 +1,205 @@
+package controllers_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOIDCProvider is a throwaway OIDC issuer - discovery document, token
+// endpoint, and JWKS - that InitOIDCProvider can register against like a
+// real one (Google, or any generic OIDC issuer), so OAuthCallback's full
+// code-exchange-then-verify-id-token path can be exercised without a real
+// network call.
+type fakeOIDCProvider struct {
+	server     *httptest.Server
+	signingKey *rsa.PrivateKey
+	kid        string
+	clientID   string
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &fakeOIDCProvider{signingKey: key, kid: "test-oidc-kid", clientID: "test-client-id"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discoveryHandler)
+	mux.HandleFunc("/token", p.tokenHandler)
+	mux.HandleFunc("/jwks", p.jwksHandler)
+	p.server = httptest.NewServer(mux)
+	return p
+}
+
+func (p *fakeOIDCProvider) close() { p.server.Close() }
+
+func (p *fakeOIDCProvider) discoveryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{
+		"issuer": %q,
+		"authorization_endpoint": %q,
+		"token_endpoint": %q,
+		"jwks_uri": %q,
+		"userinfo_endpoint": %q
+	}`, p.server.URL, p.server.URL+"/authorize", p.server.URL+"/token", p.server.URL+"/jwks", p.server.URL+"/userinfo")
+}
+
+// tokenHandler signs a fresh ID token on every call, so it reflects
+// whatever subject/email the test wants the callback to see.
+func (p *fakeOIDCProvider) tokenHandler(w http.ResponseWriter, r *http.Request) {
+	claims := jwt.MapClaims{
+		"iss":   p.server.URL,
+		"aud":   p.clientID,
+		"sub":   "oidc-subject-1",
+		"email": "oidc-user@example.com",
+		"name":  "OIDC Test User",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = p.kid
+	signed, err := token.SignedString(p.signingKey)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"access_token":"fake-access-token","id_token":%q}`, signed)
+}
+
+func (p *fakeOIDCProvider) jwksHandler(w http.ResponseWriter, r *http.Request) {
+	n := base64.RawURLEncoding.EncodeToString(p.signingKey.PublicKey.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.signingKey.PublicKey.E)).Bytes())
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"keys":[{"kty":"RSA","kid":%q,"n":%q,"e":%q}]}`, p.kid, n, e)
+}
+
+func TestOAuthLogin_RedirectsToAuthorizeEndpointWithPKCE(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.close()
+
+	providerName := "test-oidc-login"
+	require.NoError(t, controllers.InitOIDCProvider(providerName, provider.server.URL, provider.clientID, "test-client-secret", "https://app.example.com/callback"))
+
+	database := SetupTestDB()
+	db.DB = database
+
+	router := SetupRouter()
+	router.GET("/oauth/:provider/login", controllers.OAuthLogin)
+
+	req, _ := http.NewRequest("GET", "/oauth/"+providerName+"/login", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	q := location.Query()
+	assert.NotEmpty(t, q.Get("state"))
+	assert.NotEmpty(t, q.Get("code_challenge"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+}
+
+func TestOAuthLogin_UnknownProviderNotFound(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+
+	router := SetupRouter()
+	router.GET("/oauth/:provider/login", controllers.OAuthLogin)
+
+	req, _ := http.NewRequest("GET", "/oauth/does-not-exist/login", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOAuthCallback_NewUserMintsJWTAndCreatesIdentity(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.close()
+
+	providerName := "test-oidc-callback-new"
+	require.NoError(t, controllers.InitOIDCProvider(providerName, provider.server.URL, provider.clientID, "test-client-secret", "https://app.example.com/callback"))
+
+	database := SetupTestDB()
+	database.AutoMigrate(&models.OAuthIdentity{})
+	db.DB = database
+
+	router := SetupRouter()
+	router.GET("/oauth/:provider/login", controllers.OAuthLogin)
+	router.GET("/oauth/:provider/callback", controllers.OAuthCallback)
+
+	loginReq, _ := http.NewRequest("GET", "/oauth/"+providerName+"/login", nil)
+	loginW := httptest.NewRecorder()
+	router.ServeHTTP(loginW, loginReq)
+	require.Equal(t, http.StatusFound, loginW.Code)
+	location, err := url.Parse(loginW.Header().Get("Location"))
+	require.NoError(t, err)
+	state := location.Query().Get("state")
+	require.NotEmpty(t, state)
+
+	callbackReq, _ := http.NewRequest("GET", "/oauth/"+providerName+"/callback?state="+url.QueryEscape(state)+"&code=fake-auth-code", nil)
+	callbackW := httptest.NewRecorder()
+	router.ServeHTTP(callbackW, callbackReq)
+
+	require.Equal(t, http.StatusOK, callbackW.Code, callbackW.Body.String())
+	var resp struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+		User         struct {
+			ID    uint   `json:"id"`
+			Email string `json:"email"`
+		} `json:"user"`
+	}
+	require.NoError(t, json.Unmarshal(callbackW.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEmpty(t, resp.RefreshToken)
+	assert.Equal(t, "oidc-user@example.com", resp.User.Email)
+
+	var user models.User
+	require.NoError(t, database.First(&user, resp.User.ID).Error)
+	assert.Empty(t, user.PasswordHash)
+
+	var identity models.OAuthIdentity
+	require.NoError(t, database.Where("provider = ? AND subject = ?", providerName, "oidc-subject-1").First(&identity).Error)
+	assert.Equal(t, user.ID, identity.UserID)
+}
+
+func TestOAuthCallback_InvalidStateRejected(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.close()
+
+	providerName := "test-oidc-callback-badstate"
+	require.NoError(t, controllers.InitOIDCProvider(providerName, provider.server.URL, provider.clientID, "test-client-secret", "https://app.example.com/callback"))
+
+	database := SetupTestDB()
+	db.DB = database
+
+	router := SetupRouter()
+	router.GET("/oauth/:provider/callback", controllers.OAuthCallback)
+
+	req, _ := http.NewRequest("GET", "/oauth/"+providerName+"/callback?state=not-a-real-state&code=fake-auth-code", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
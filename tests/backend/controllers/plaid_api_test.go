@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"Personal-Finance-Tracker-backend/controllers"
 	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
 	"Personal-Finance-Tracker-backend/models"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -78,10 +81,10 @@ func (suite *PlaidAPITestSuite) SetupSuite() {
 	authGroup := suite.router.Group("/api")
 	authGroup.Use(controllers.AuthMiddleware())
 	{
-		authGroup.POST("/plaid/create_link_token", controllers.CreateLinkToken)
-		authGroup.POST("/plaid/exchange_public_token", controllers.ExchangePublicToken)
-		authGroup.POST("/plaid/sync/:id", controllers.SyncPlaidTransactions)
-		authGroup.GET("/plaid/accounts/:id", controllers.GetPlaidAccounts)
+		authGroup.POST("/plaid/create_link_token", middleware.PlaidBackpressure(), controllers.CreateLinkToken)
+		authGroup.POST("/plaid/exchange_public_token", middleware.PlaidBackpressure(), controllers.ExchangePublicToken)
+		authGroup.POST("/plaid/sync/:id", middleware.PlaidBackpressure(), controllers.SyncPlaidTransactions)
+		authGroup.GET("/plaid/accounts/:id", middleware.PlaidBackpressure(), controllers.GetPlaidAccounts)
 	}
 }
 
@@ -357,26 +360,21 @@ func (suite *PlaidAPITestSuite) TestSyncPlaidTransactions_NoAccessToken() {
 }
 
 func (suite *PlaidAPITestSuite) TestSyncPlaidTransactions_ValidConnection() {
-	// Test with valid connection that has access token
-	// Note: This test currently demonstrates a bug in the controller where it panics
-	// instead of returning a proper error when plaidClient is nil
-
-	defer func() {
-		if r := recover(); r != nil {
-			// Expect a panic due to nil plaidClient access
-			suite.Contains(fmt.Sprintf("%v", r), "nil pointer")
-		}
-	}()
-
+	// Even with a valid connection, activePlaidHandler is nil in this suite
+	// (InitPlaidClient is never called), so this should return the same
+	// clean "Plaid client not initialized" error every other endpoint does
+	// instead of panicking - see controllers.PlaidClient/PlaidHandler.
 	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/plaid/sync/%d", suite.bankConnection.ID), nil)
 	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
 	w := httptest.NewRecorder()
-
-	// This will panic due to nil plaidClient, demonstrating a controller bug
 	suite.router.ServeHTTP(w, req)
 
-	// This line won't be reached due to the panic
-	suite.Fail("Expected panic did not occur")
+	suite.Equal(http.StatusInternalServerError, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	suite.NoError(err)
+	suite.Equal("Plaid client not initialized", response["error"])
 }
 
 // ============================================
@@ -476,26 +474,21 @@ func (suite *PlaidAPITestSuite) TestGetPlaidAccounts_NoAccessToken() {
 }
 
 func (suite *PlaidAPITestSuite) TestGetPlaidAccounts_ValidConnection() {
-	// Test with valid connection that has access token
-	// Note: This test currently demonstrates a bug in the controller where it panics
-	// instead of returning a proper error when plaidClient is nil
-
-	defer func() {
-		if r := recover(); r != nil {
-			// Expect a panic due to nil plaidClient access
-			suite.Contains(fmt.Sprintf("%v", r), "nil pointer")
-		}
-	}()
-
+	// Even with a valid connection, activePlaidHandler is nil in this suite
+	// (InitPlaidClient is never called), so this should return the same
+	// clean "Plaid client not initialized" error every other endpoint does
+	// instead of panicking - see controllers.PlaidClient/PlaidHandler.
 	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/plaid/accounts/%d", suite.bankConnection.ID), nil)
 	req.Header.Set("Authorization", "Bearer "+suite.normalToken)
 	w := httptest.NewRecorder()
-
-	// This will panic due to nil plaidClient, demonstrating a controller bug
 	suite.router.ServeHTTP(w, req)
 
-	// This line won't be reached due to the panic
-	suite.Fail("Expected panic did not occur")
+	suite.Equal(http.StatusInternalServerError, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	suite.NoError(err)
+	suite.Equal("Plaid client not initialized", response["error"])
 }
 
 // ============================================
@@ -706,29 +699,62 @@ func (suite *PlaidAPITestSuite) TestCategorySetup() {
 // ============================================
 // TEST 9: Performance and Reliability
 // ============================================
+
+// TestPlaidEndpoints_ConcurrentRequests drives a burst of concurrent
+// requests at a Plaid-backed route and confirms middleware.PlaidBackpressure
+// holds its shape under load: every response is either the handler's own
+// error (Plaid isn't initialized in this suite) or a 429 from the
+// concurrency semaphore/token bucket, at least one 429 is actually observed
+// at this volume, and the plaid_* counters on /metrics move accordingly.
 func (suite *PlaidAPITestSuite) TestPlaidEndpoints_ConcurrentRequests() {
-	// Test that endpoints can handle concurrent requests
-	const numRequests = 5
-	done := make(chan bool, numRequests)
+	const numRequests = 300
+	var wg sync.WaitGroup
+	codes := make([]int, numRequests)
 
 	for i := 0; i < numRequests; i++ {
-		go func() {
-			defer func() { done <- true }()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
 
 			req, _ := http.NewRequest("POST", "/api/plaid/create_link_token", nil)
 			req.Header.Set("Authorization", "Bearer "+suite.normalToken)
 			w := httptest.NewRecorder()
 			suite.router.ServeHTTP(w, req)
-
-			// Should consistently return the same error (Plaid not initialized)
-			suite.Equal(http.StatusInternalServerError, w.Code)
-		}()
+			codes[i] = w.Code
+		}(i)
 	}
-
-	// Wait for all requests to complete
-	for i := 0; i < numRequests; i++ {
-		<-done
+	wg.Wait()
+
+	var tooManyRequests, handlerErrors int
+	for _, code := range codes {
+		switch code {
+		case http.StatusTooManyRequests:
+			tooManyRequests++
+		case http.StatusInternalServerError:
+			handlerErrors++
+		default:
+			suite.Failf("unexpected status code", "got %d", code)
+		}
 	}
+
+	// 300 requests, all fired at once, comfortably exceed both
+	// middleware.PlaidMaxInFlight (8 concurrent) and PlaidAPIRate's burst
+	// (60/minute) - some must have been turned away.
+	suite.Greater(tooManyRequests, 0, "expected at least one 429 from PlaidBackpressure at this volume")
+	suite.Greater(handlerErrors, 0, "expected at least one request to reach the handler")
+	suite.Equal(numRequests, tooManyRequests+handlerErrors)
+
+	metricsReq, _ := http.NewRequest("GET", "/metrics", nil)
+	metricsW := httptest.NewRecorder()
+	metricsRouter := gin.New()
+	metricsRouter.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	metricsRouter.ServeHTTP(metricsW, metricsReq)
+
+	body := metricsW.Body.String()
+	suite.Contains(body, "plaid_requests_total")
+	suite.Contains(body, "plaid_rate_limited_total")
+	suite.Contains(body, "plaid_inflight")
+	suite.Contains(body, "plaid_latency_seconds")
 }
 
 // ============================================
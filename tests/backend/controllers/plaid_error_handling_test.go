@@ -0,0 +1,158 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/controllers/plaidfake"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/plaid/plaid-go/v29/plaid"
+	"github.com/stretchr/testify/assert"
+)
+
+// plaidAccountsResponseFixture builds a plaid.AccountsGetResponse from raw
+// Plaid /accounts/get JSON, the AccountsGet counterpart to
+// plaidSyncResponseFixture in plaid_sync_test.go.
+func plaidAccountsResponseFixture(t *testing.T, body string) plaid.AccountsGetResponse {
+	t.Helper()
+	var resp plaid.AccountsGetResponse
+	assert.NoError(t, json.Unmarshal([]byte(body), &resp))
+	return resp
+}
+
+// TestSyncPlaidTransactions_RateLimitExceededRetriesThenSucceeds confirms
+// plaidSyncConnection's withPlaidRateLimitRetry transparently retries a
+// RATE_LIMIT_EXCEEDED response instead of failing the sync outright, as
+// long as a later attempt succeeds within its retry budget.
+func TestSyncPlaidTransactions_RateLimitExceededRetriesThenSucceeds(t *testing.T) {
+	t.Parallel()
+	var calls int32
+	fakeClient := &plaidfake.Client{
+		TransactionsSyncFunc: func(req plaid.TransactionsSyncRequest) (plaid.TransactionsSyncResponse, error) {
+			if atomic.AddInt32(&calls, 1) <= 2 {
+				return plaid.TransactionsSyncResponse{}, plaidfake.APIError("RATE_LIMIT_EXCEEDED", "too many requests")
+			}
+			return plaidSyncResponseFixture(t, `{"added":[],"modified":[],"removed":[],"next_cursor":"cursor-after-retry","has_more":false}`), nil
+		},
+	}
+
+	_, connection, _, router, token := setupPlaidSyncFixtures(t, fakeClient)
+	w := doPlaidSync(t, router, token, connection.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&calls), int32(3), "expected the rate-limited attempts to be retried")
+}
+
+// TestSyncPlaidTransactions_ItemLoginRequiredFlagsNeedsReauth confirms an
+// ITEM_LOGIN_REQUIRED response isn't retried (unlike RATE_LIMIT_EXCEEDED)
+// and instead flags the connection NeedsReauth, the same outcome a
+// PENDING_EXPIRATION webhook or runPlaidConsentExpiryScan already produce.
+func TestSyncPlaidTransactions_ItemLoginRequiredFlagsNeedsReauth(t *testing.T) {
+	t.Parallel()
+	var calls int32
+	fakeClient := &plaidfake.Client{
+		TransactionsSyncFunc: func(req plaid.TransactionsSyncRequest) (plaid.TransactionsSyncResponse, error) {
+			atomic.AddInt32(&calls, 1)
+			return plaid.TransactionsSyncResponse{}, plaidfake.APIError("ITEM_LOGIN_REQUIRED", "the login details are no longer valid")
+		},
+	}
+
+	database, connection, _, router, token := setupPlaidSyncFixtures(t, fakeClient)
+	w := doPlaidSync(t, router, token, connection.ID)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "ITEM_LOGIN_REQUIRED should not be retried")
+
+	var updated models.BankConnection
+	assert.NoError(t, database.First(&updated, connection.ID).Error)
+	assert.True(t, updated.NeedsReauth)
+	assert.Equal(t, "error", updated.Status)
+	lastError, _ := updated.Metadata["last_error"].(map[string]interface{})
+	if assert.NotNil(t, lastError) {
+		assert.Equal(t, "ITEM_LOGIN_REQUIRED", lastError["error_code"])
+	}
+}
+
+// TestExchangePublicToken_InvalidAccessTokenSurfacesErrorCode confirms a
+// Plaid API error's error_code reaches the caller as plaid_error_code
+// instead of only being embedded in a free-text error string.
+func TestExchangePublicToken_InvalidAccessTokenSurfacesErrorCode(t *testing.T) {
+	t.Parallel()
+	fakeClient := &plaidfake.Client{
+		ItemPublicTokenExchangeFunc: func(req plaid.ItemPublicTokenExchangeRequest) (plaid.ItemPublicTokenExchangeResponse, error) {
+			return plaid.ItemPublicTokenExchangeResponse{}, plaidfake.APIError("INVALID_ACCESS_TOKEN", "access token is invalid")
+		},
+	}
+
+	_, _, token, router := setupPlaidOAuthFixtures(t, fakeClient)
+
+	body := []byte(`{"public_token":"public-sandbox-invalid"}`)
+	req, _ := http.NewRequest("POST", "/api/plaid/exchange_public_token", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var response struct {
+		PlaidErrorCode string `json:"plaid_error_code"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "INVALID_ACCESS_TOKEN", response.PlaidErrorCode)
+}
+
+// TestGetPlaidAccounts_ReturnsFixtures exercises GET
+// /api/plaid/accounts/:id's success path end to end against a scripted
+// plaidfake.Client response, the account-fetch counterpart to
+// TestSyncPlaidTransactions_SuccessfulSyncAppliesCategorization.
+func TestGetPlaidAccounts_ReturnsFixtures(t *testing.T) {
+	t.Parallel()
+	fakeClient := &plaidfake.Client{
+		AccountsGetFunc: func(req plaid.AccountsGetRequest) (plaid.AccountsGetResponse, error) {
+			return plaidAccountsResponseFixture(t, `{"accounts":[{"account_id":"plaid-acc-1","name":"Checking","balances":{"current":1234.56,"iso_currency_code":"USD"}}],"item":{"item_id":"item-sync-test"}}`), nil
+		},
+	}
+
+	database := SetupTestDB()
+	database.AutoMigrate(&models.BankConnection{}, &models.BankAccount{})
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	connection := models.BankConnection{
+		UserID:            user.ID,
+		BankName:          "Test Bank",
+		BankEndpoint:      "plaid://api",
+		Provider:          "plaid",
+		Status:            "connected",
+		ConsentID:         "item-accounts-test",
+		ConsentValidUntil: time.Now().Add(90 * 24 * time.Hour),
+		Metadata: models.JSONB{
+			"access_token": "access-sandbox-accounts-test",
+			"item_id":      "item-accounts-test",
+		},
+	}
+	database.Create(&connection)
+
+	handler := controllers.NewPlaidHandler(fakeClient, database)
+	router := SetupRouter()
+	authGroup := router.Group("/api")
+	authGroup.Use(controllers.AuthMiddleware())
+	authGroup.GET("/plaid/accounts/:id", handler.GetPlaidAccounts)
+
+	req, _ := http.NewRequest("GET", "/api/plaid/accounts/"+strconv.FormatUint(uint64(connection.ID), 10), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "plaid-acc-1")
+}
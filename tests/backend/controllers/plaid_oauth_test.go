@@ -0,0 +1,147 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/controllers/plaidfake"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/plaid/plaid-go/v29/plaid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// setupPlaidOAuthFixtures seeds a user and wires a router with the Plaid
+// OAuth callback route (unauthenticated, like main.go registers it) plus the
+// authenticated exchange route, backed by a PlaidHandler built from client.
+func setupPlaidOAuthFixtures(t *testing.T, client controllers.PlaidClient) (*gorm.DB, *models.User, string, *gin.Engine) {
+	t.Helper()
+	database := SetupTestDB()
+	database.AutoMigrate(&models.BankConnection{}, &models.BankAccount{}, &models.PlaidLinkSession{})
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	handler := controllers.NewPlaidHandler(client, database)
+
+	router := SetupRouter()
+	router.GET("/api/plaid/oauth/callback", handler.PlaidOAuthCallback)
+	authGroup := router.Group("/api")
+	authGroup.Use(controllers.AuthMiddleware())
+	authGroup.POST("/plaid/exchange_public_token", handler.ExchangePublicToken)
+
+	return database, user, token, router
+}
+
+func TestPlaidOAuthCallback_ExpiredSessionRejected(t *testing.T) {
+	t.Parallel()
+	database, user, _, router := setupPlaidOAuthFixtures(t, &plaidfake.Client{})
+
+	session := models.PlaidLinkSession{
+		UserID:      user.ID,
+		LinkToken:   "link-sandbox-expired",
+		State:       "state-expired",
+		RedirectURI: "https://app.example.com/banks/oauth",
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	}
+	assert.NoError(t, database.Create(&session).Error)
+
+	req, _ := http.NewRequest("GET", "/api/plaid/oauth/callback?oauth_state_id=state-expired", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPlaidOAuthCallback_UnknownStateRejected(t *testing.T) {
+	t.Parallel()
+	_, _, _, router := setupPlaidOAuthFixtures(t, &plaidfake.Client{})
+
+	req, _ := http.NewRequest("GET", "/api/plaid/oauth/callback?oauth_state_id=no-such-state", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPlaidOAuthCallback_ValidStateRedirectsWithLinkToken(t *testing.T) {
+	t.Parallel()
+	database, user, _, router := setupPlaidOAuthFixtures(t, &plaidfake.Client{})
+
+	session := models.PlaidLinkSession{
+		UserID:      user.ID,
+		LinkToken:   "link-sandbox-valid",
+		State:       "state-valid",
+		RedirectURI: "https://app.example.com/banks/oauth",
+		ExpiresAt:   time.Now().Add(30 * time.Minute),
+	}
+	assert.NoError(t, database.Create(&session).Error)
+
+	req, _ := http.NewRequest("GET", "/api/plaid/oauth/callback?oauth_state_id=state-valid", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Contains(t, w.Header().Get("Location"), "link_token=link-sandbox-valid")
+}
+
+// TestExchangePublicToken_UpdateModeReauthorizesExistingConnection exercises
+// ExchangePublicToken's update-mode path: bank_connection_id set should
+// refresh the existing, login_required BankConnection's access token and
+// status rather than creating a second connection.
+func TestExchangePublicToken_UpdateModeReauthorizesExistingConnection(t *testing.T) {
+	t.Parallel()
+	fakeClient := &plaidfake.Client{
+		ItemPublicTokenExchangeFunc: func(req plaid.ItemPublicTokenExchangeRequest) (plaid.ItemPublicTokenExchangeResponse, error) {
+			var resp plaid.ItemPublicTokenExchangeResponse
+			assert.NoError(t, json.Unmarshal([]byte(`{"access_token":"access-sandbox-refreshed","item_id":"item-reauth-test","request_id":"req-1"}`), &resp))
+			return resp, nil
+		},
+	}
+
+	database, user, token, router := setupPlaidOAuthFixtures(t, fakeClient)
+
+	connection := models.BankConnection{
+		UserID:            user.ID,
+		BankName:          "Test Bank",
+		BankEndpoint:      "plaid://api",
+		Provider:          "plaid",
+		Status:            "login_required",
+		NeedsReauth:       true,
+		ConsentID:         "item-reauth-test-stale",
+		ConsentValidUntil: time.Now().Add(-time.Hour),
+		Metadata: models.JSONB{
+			"access_token": "access-sandbox-stale",
+			"item_id":      "item-reauth-test-stale",
+		},
+	}
+	assert.NoError(t, database.Create(&connection).Error)
+
+	body, _ := json.Marshal(gin.H{
+		"public_token":       "public-sandbox-reauth",
+		"bank_connection_id": connection.ID,
+	})
+	req, _ := http.NewRequest("POST", "/api/plaid/exchange_public_token", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.BankConnection
+	assert.NoError(t, database.First(&updated, connection.ID).Error)
+	assert.Equal(t, "connected", updated.Status)
+	assert.False(t, updated.NeedsReauth)
+	assert.Equal(t, "access-sandbox-refreshed", updated.Metadata["access_token"])
+
+	var count int64
+	database.Model(&models.BankConnection{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Equal(t, int64(1), count, "update mode should not create a second BankConnection")
+}
@@ -0,0 +1,107 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/controllers/plaidfake"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/plaid/plaid-go/v29/plaid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSyncPlaidTransactions_RecordsLastSyncAtAndSyncCount confirms a
+// successful sync advances LastSyncAt/SyncCount the same way the PSD2 sync
+// path does, since StartPlaidSyncScheduler's due-connection query depends
+// on LastSyncAt having moved.
+func TestSyncPlaidTransactions_RecordsLastSyncAtAndSyncCount(t *testing.T) {
+	fakeClient := &plaidfake.Client{
+		TransactionsSyncFunc: func(req plaid.TransactionsSyncRequest) (plaid.TransactionsSyncResponse, error) {
+			return plaidSyncResponseFixture(t, `{"added":[],"modified":[],"removed":[],"next_cursor":"cursor-1","has_more":false}`), nil
+		},
+	}
+
+	database, connection, _, router, token := setupPlaidSyncFixtures(t, fakeClient)
+	assert.Nil(t, connection.LastSyncAt)
+
+	before := time.Now()
+	w := doPlaidSync(t, router, token, connection.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.BankConnection
+	assert.NoError(t, database.First(&updated, connection.ID).Error)
+	if assert.NotNil(t, updated.LastSyncAt) {
+		assert.True(t, !updated.LastSyncAt.Before(before))
+	}
+	assert.Equal(t, 1, updated.SyncCount)
+}
+
+// TestPlaidSyncStatus_ReportsPerConnectionHealth exercises
+// GET /api/plaid/sync_status end to end, confirming it surfaces
+// last_synced_at, last_error, and needs_reauth for the caller's own Plaid
+// connections only.
+func TestPlaidSyncStatus_ReportsPerConnectionHealth(t *testing.T) {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.BankConnection{})
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	lastSync := time.Now().Add(-time.Hour)
+	connection := models.BankConnection{
+		UserID:            user.ID,
+		BankName:          "Test Bank",
+		BankEndpoint:      "plaid://api",
+		Provider:          "plaid",
+		Status:            "expiring",
+		NeedsReauth:       true,
+		ConsentID:         "item-status-test",
+		ConsentValidUntil: time.Now().Add(3 * 24 * time.Hour),
+		LastSyncAt:        &lastSync,
+		Metadata: models.JSONB{
+			"access_token": "access-sandbox-status-test",
+			"item_id":      "item-status-test",
+			"last_error": map[string]string{
+				"error_code":    "ITEM_LOGIN_REQUIRED",
+				"error_message": "the login details are no longer valid",
+			},
+		},
+	}
+	assert.NoError(t, database.Create(&connection).Error)
+
+	handler := controllers.NewPlaidHandler(&plaidfake.Client{}, database)
+	router := SetupRouter()
+	authGroup := router.Group("/api")
+	authGroup.Use(controllers.AuthMiddleware())
+	authGroup.GET("/plaid/sync_status", handler.PlaidSyncStatus)
+
+	req, _ := http.NewRequest("GET", "/api/plaid/sync_status", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Connections []struct {
+			ConnectionID uint   `json:"connection_id"`
+			NeedsReauth  bool   `json:"needs_reauth"`
+			Status       string `json:"status"`
+			LastError    struct {
+				ErrorCode string `json:"error_code"`
+			} `json:"last_error"`
+		} `json:"connections"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Connections, 1) {
+		assert.Equal(t, connection.ID, response.Connections[0].ConnectionID)
+		assert.True(t, response.Connections[0].NeedsReauth)
+		assert.Equal(t, "expiring", response.Connections[0].Status)
+		assert.Equal(t, "ITEM_LOGIN_REQUIRED", response.Connections[0].LastError.ErrorCode)
+	}
+}
+
@@ -0,0 +1,290 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/controllers/plaidfake"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/plaid/plaid-go/v29/plaid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// plaidSyncResponseFixture builds a plaid.TransactionsSyncResponse from raw
+// Plaid /transactions/sync JSON, so tests can script pages without depending
+// on plaid-go's generated constructors.
+func plaidSyncResponseFixture(t *testing.T, body string) plaid.TransactionsSyncResponse {
+	t.Helper()
+	var resp plaid.TransactionsSyncResponse
+	assert.NoError(t, json.Unmarshal([]byte(body), &resp))
+	return resp
+}
+
+// setupPlaidSyncFixtures seeds a user, a "Groceries" category, a linked
+// account, and a BankConnection+BankAccount pair ready for
+// PlaidHandler.SyncPlaidTransactions, returning the pieces tests assert
+// against plus a router with the sync route already wired to handler.
+func setupPlaidSyncFixtures(t *testing.T, client controllers.PlaidClient) (*gorm.DB, *models.BankConnection, *models.Category, *gin.Engine, string) {
+	t.Helper()
+	database := SetupTestDB()
+	database.AutoMigrate(&models.BankConnection{}, &models.BankAccount{})
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	database.Create(&groceries)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+
+	connection := models.BankConnection{
+		UserID:            user.ID,
+		BankName:          "Test Bank",
+		BankEndpoint:      "plaid://api",
+		Provider:          "plaid",
+		Status:            "connected",
+		ConsentID:         "item-sync-test",
+		ConsentValidUntil: time.Now().Add(90 * 24 * time.Hour),
+		Metadata: models.JSONB{
+			"access_token": "access-sandbox-sync-test",
+			"item_id":      "item-sync-test",
+		},
+	}
+	database.Create(&connection)
+
+	bankAccount := models.BankAccount{
+		BankConnectionID:  connection.ID,
+		AccountID:         "plaid-acc-1",
+		AccountName:       "Checking",
+		InternalAccountID: &account.ID,
+	}
+	database.Create(&bankAccount)
+
+	handler := controllers.NewPlaidHandler(client, database)
+
+	router := SetupRouter()
+	authGroup := router.Group("/api")
+	authGroup.Use(controllers.AuthMiddleware())
+	authGroup.POST("/plaid/sync/:id", handler.SyncPlaidTransactions)
+
+	return database, &connection, &groceries, router, token
+}
+
+func doPlaidSync(t *testing.T, router *gin.Engine, token string, connectionID uint) *httptest.ResponseRecorder {
+	t.Helper()
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/plaid/sync/%d", connectionID), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestSyncPlaidTransactions_SuccessfulSyncAppliesCategorization exercises the
+// full /transactions/sync path through a PlaidHandler built with a
+// plaidfake.Client, confirming applyPlaidTransactionAdd both imports the
+// transaction and maps its Plaid category array onto the user's own
+// "Groceries" category via matchPlaidCategory.
+func TestSyncPlaidTransactions_SuccessfulSyncAppliesCategorization(t *testing.T) {
+	t.Parallel()
+	fakeClient := &plaidfake.Client{
+		TransactionsSyncFunc: func(req plaid.TransactionsSyncRequest) (plaid.TransactionsSyncResponse, error) {
+			return plaidSyncResponseFixture(t, `{
+				"added": [{
+					"transaction_id": "txn-1",
+					"account_id": "plaid-acc-1",
+					"amount": 42.50,
+					"iso_currency_code": "USD",
+					"category": ["Food and Drink", "Groceries"],
+					"date": "2026-07-01",
+					"name": "Whole Foods"
+				}],
+				"modified": [],
+				"removed": [],
+				"next_cursor": "cursor-1",
+				"has_more": false
+			}`), nil
+		},
+	}
+
+	database, connection, groceries, router, token := setupPlaidSyncFixtures(t, fakeClient)
+
+	w := doPlaidSync(t, router, token, connection.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Success           bool `json:"success"`
+		TransactionsAdded int  `json:"transactions_added"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, 1, response.TransactionsAdded)
+
+	var imported models.Transaction
+	assert.NoError(t, database.Where("bank_transaction_id = ?", "txn-1").First(&imported).Error)
+	if assert.NotNil(t, imported.CategoryID) {
+		assert.Equal(t, groceries.ID, *imported.CategoryID)
+	}
+}
+
+// TestSyncPlaidTransactions_DuplicateTransactionNotReimported re-runs the
+// same sync twice with a TransactionsSyncFunc that keeps returning the same
+// transaction_id regardless of cursor, the way a retried/duplicated webhook
+// delivery would - applyPlaidTransactionAdd's existing-by-BankTransactionID
+// check should make the second sync a no-op.
+func TestSyncPlaidTransactions_DuplicateTransactionNotReimported(t *testing.T) {
+	t.Parallel()
+	fakeClient := &plaidfake.Client{
+		TransactionsSyncFunc: func(req plaid.TransactionsSyncRequest) (plaid.TransactionsSyncResponse, error) {
+			return plaidSyncResponseFixture(t, `{
+				"added": [{
+					"transaction_id": "txn-dup",
+					"account_id": "plaid-acc-1",
+					"amount": 10.00,
+					"iso_currency_code": "USD",
+					"category": ["Food and Drink", "Groceries"],
+					"date": "2026-07-02",
+					"name": "Corner Store"
+				}],
+				"modified": [],
+				"removed": [],
+				"next_cursor": "cursor-1",
+				"has_more": false
+			}`), nil
+		},
+	}
+
+	database, connection, _, router, token := setupPlaidSyncFixtures(t, fakeClient)
+
+	first := doPlaidSync(t, router, token, connection.ID)
+	assert.Equal(t, http.StatusOK, first.Code)
+
+	second := doPlaidSync(t, router, token, connection.ID)
+	assert.Equal(t, http.StatusOK, second.Code)
+
+	var response struct {
+		TransactionsAdded int `json:"transactions_added"`
+	}
+	assert.NoError(t, json.Unmarshal(second.Body.Bytes(), &response))
+	assert.Equal(t, 0, response.TransactionsAdded, "a second sync over the same transaction_id should not re-import it")
+
+	var count int64
+	database.Model(&models.Transaction{}).Where("bank_transaction_id = ?", "txn-dup").Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestSyncPlaidTransactions_MultiPagePersistsFinalCursor drives a
+// TransactionsSyncFunc across two pages (has_more true, then false),
+// confirming both pages' transactions land and the connection's persisted
+// metadata cursor ends on the final page's next_cursor, not the first's.
+func TestSyncPlaidTransactions_MultiPagePersistsFinalCursor(t *testing.T) {
+	t.Parallel()
+	fakeClient := &plaidfake.Client{
+		TransactionsSyncFunc: func(req plaid.TransactionsSyncRequest) (plaid.TransactionsSyncResponse, error) {
+			if req.GetCursor() == "" {
+				return plaidSyncResponseFixture(t, `{
+					"added": [{
+						"transaction_id": "txn-page-1",
+						"account_id": "plaid-acc-1",
+						"amount": 5.00,
+						"iso_currency_code": "USD",
+						"category": ["Food and Drink", "Groceries"],
+						"date": "2026-07-03",
+						"name": "Page One Store"
+					}],
+					"modified": [],
+					"removed": [],
+					"next_cursor": "cursor-page-1",
+					"has_more": true
+				}`), nil
+			}
+			return plaidSyncResponseFixture(t, `{
+				"added": [{
+					"transaction_id": "txn-page-2",
+					"account_id": "plaid-acc-1",
+					"amount": 7.00,
+					"iso_currency_code": "USD",
+					"category": ["Food and Drink", "Groceries"],
+					"date": "2026-07-04",
+					"name": "Page Two Store"
+				}],
+				"modified": [],
+				"removed": [],
+				"next_cursor": "cursor-page-2",
+				"has_more": false
+			}`), nil
+		},
+	}
+
+	database, connection, _, router, token := setupPlaidSyncFixtures(t, fakeClient)
+
+	w := doPlaidSync(t, router, token, connection.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		TransactionsAdded int    `json:"transactions_added"`
+		NextCursor        string `json:"next_cursor"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.TransactionsAdded)
+	assert.Equal(t, "cursor-page-2", response.NextCursor)
+
+	var updated models.BankConnection
+	assert.NoError(t, database.First(&updated, connection.ID).Error)
+	assert.Equal(t, "cursor-page-2", updated.Metadata["next_cursor"])
+
+	var count int64
+	database.Model(&models.Transaction{}).Where("bank_transaction_id IN ?", []string{"txn-page-1", "txn-page-2"}).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+// TestSyncPlaidTransactions_PartialFailureResumesFromLastCommittedPage
+// simulates a second page failing outright (as a network error mid-sync
+// would) and confirms the first page's transaction and cursor were already
+// committed/persisted - so a retried sync resumes from cursor-page-1 instead
+// of re-requesting (and re-importing) the first page.
+func TestSyncPlaidTransactions_PartialFailureResumesFromLastCommittedPage(t *testing.T) {
+	t.Parallel()
+	fakeClient := &plaidfake.Client{
+		TransactionsSyncFunc: func(req plaid.TransactionsSyncRequest) (plaid.TransactionsSyncResponse, error) {
+			if req.GetCursor() == "" {
+				return plaidSyncResponseFixture(t, `{
+					"added": [{
+						"transaction_id": "txn-partial-1",
+						"account_id": "plaid-acc-1",
+						"amount": 3.00,
+						"iso_currency_code": "USD",
+						"category": ["Food and Drink", "Groceries"],
+						"date": "2026-07-05",
+						"name": "Partial One Store"
+					}],
+					"modified": [],
+					"removed": [],
+					"next_cursor": "cursor-partial-1",
+					"has_more": true
+				}`), nil
+			}
+			return plaid.TransactionsSyncResponse{}, fmt.Errorf("simulated network failure on page 2")
+		},
+	}
+
+	database, connection, _, router, token := setupPlaidSyncFixtures(t, fakeClient)
+
+	w := doPlaidSync(t, router, token, connection.ID)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var updated models.BankConnection
+	assert.NoError(t, database.First(&updated, connection.ID).Error)
+	assert.Equal(t, "cursor-partial-1", updated.Metadata["next_cursor"])
+
+	var count int64
+	database.Model(&models.Transaction{}).Where("bank_transaction_id = ?", "txn-partial-1").Count(&count)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,293 @@
+package controllers_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/controllers/plaidfake"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/plaid/plaid-go/v29/plaid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// plaidWebhookTestKey is a throwaway EC P-256 key standing in for one of
+// Plaid's /webhook_verification_key/get keys, so tests can sign a
+// Plaid-Verification JWT the same way Plaid itself would.
+type plaidWebhookTestKey struct {
+	kid string
+	key *ecdsa.PrivateKey
+}
+
+func newPlaidWebhookTestKey(t *testing.T) plaidWebhookTestKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	return plaidWebhookTestKey{kid: "test-kid-1", key: key}
+}
+
+// jwkResponseBody renders the public half of the key as the
+// WebhookVerificationKeyGetResponse JSON WebhookVerificationKeyGetFunc hands
+// back.
+func (k plaidWebhookTestKey) jwkResponseBody() string {
+	x := base64.RawURLEncoding.EncodeToString(k.key.PublicKey.X.Bytes())
+	y := base64.RawURLEncoding.EncodeToString(k.key.PublicKey.Y.Bytes())
+	return fmt.Sprintf(`{"key":{"alg":"ES256","crv":"P-256","kty":"EC","use":"sig","kid":%q,"x":%q,"y":%q}}`, k.kid, x, y)
+}
+
+// expiredJWKResponseBody is jwkResponseBody with Plaid's expired_at set -
+// the key has rotated out, and Plaid says so in the JWK itself rather than
+// by omitting it from the response.
+func (k plaidWebhookTestKey) expiredJWKResponseBody() string {
+	x := base64.RawURLEncoding.EncodeToString(k.key.PublicKey.X.Bytes())
+	y := base64.RawURLEncoding.EncodeToString(k.key.PublicKey.Y.Bytes())
+	return fmt.Sprintf(`{"key":{"alg":"ES256","crv":"P-256","kty":"EC","use":"sig","kid":%q,"x":%q,"y":%q,"expired_at":%q}}`,
+		k.kid, x, y, time.Now().Add(-24*time.Hour).Format(time.RFC3339))
+}
+
+// sign builds the Plaid-Verification JWT for body, with iat backdated by age
+// (0 for "just now").
+func (k plaidWebhookTestKey) sign(t *testing.T, body []byte, age time.Duration) string {
+	t.Helper()
+	hash := sha256.Sum256(body)
+	claims := jwt.MapClaims{
+		"iat":                 time.Now().Add(-age).Unix(),
+		"request_body_sha256": hex.EncodeToString(hash[:]),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = k.kid
+	signed, err := token.SignedString(k.key)
+	assert.NoError(t, err)
+	return signed
+}
+
+// setupPlaidWebhookFixtures seeds a BankConnection keyed by item_id
+// "webhook-item" and returns a PlaidHandler-backed router with
+// WebhookVerificationKeyGet scripted to return key's public half.
+func setupPlaidWebhookFixtures(t *testing.T, key plaidWebhookTestKey) (*gorm.DB, *models.BankConnection, *gin.Engine) {
+	t.Helper()
+	database := SetupTestDB()
+	database.AutoMigrate(&models.BankConnection{})
+	user := CreateTestUser(database)
+
+	connection := models.BankConnection{
+		UserID:            user.ID,
+		BankName:          "Test Bank",
+		BankEndpoint:      "plaid://api",
+		Provider:          "plaid",
+		Status:            "connected",
+		ConsentID:         "webhook-item",
+		ConsentValidUntil: time.Now().Add(90 * 24 * time.Hour),
+		Metadata: models.JSONB{
+			"access_token": "access-sandbox-webhook-test",
+			"item_id":      "webhook-item",
+		},
+	}
+	database.Create(&connection)
+
+	fakeClient := &plaidfake.Client{
+		WebhookVerificationKeyGetFunc: func(req plaid.WebhookVerificationKeyGetRequest) (plaid.WebhookVerificationKeyGetResponse, error) {
+			var resp plaid.WebhookVerificationKeyGetResponse
+			assert.NoError(t, json.Unmarshal([]byte(key.jwkResponseBody()), &resp))
+			return resp, nil
+		},
+		TransactionsSyncFunc: func(req plaid.TransactionsSyncRequest) (plaid.TransactionsSyncResponse, error) {
+			var resp plaid.TransactionsSyncResponse
+			assert.NoError(t, json.Unmarshal([]byte(`{"added":[],"modified":[],"removed":[],"next_cursor":"cursor-1","has_more":false}`), &resp))
+			return resp, nil
+		},
+	}
+	handler := controllers.NewPlaidHandler(fakeClient, database)
+
+	router := SetupRouter()
+	router.POST("/api/plaid/webhook", handler.PlaidWebhook)
+
+	return database, &connection, router
+}
+
+// setupPlaidWebhookFixturesWithExpiredKey is setupPlaidWebhookFixtures, but
+// WebhookVerificationKeyGet returns key marked expired_at - Plaid's own
+// signal that the kid this webhook was signed with has since rotated out.
+func setupPlaidWebhookFixturesWithExpiredKey(t *testing.T, key plaidWebhookTestKey) *gin.Engine {
+	t.Helper()
+	database := SetupTestDB()
+	database.AutoMigrate(&models.BankConnection{})
+	user := CreateTestUser(database)
+
+	database.Create(&models.BankConnection{
+		UserID:            user.ID,
+		BankName:          "Test Bank",
+		BankEndpoint:      "plaid://api",
+		Provider:          "plaid",
+		Status:            "connected",
+		ConsentID:         "webhook-item",
+		ConsentValidUntil: time.Now().Add(90 * 24 * time.Hour),
+		Metadata: models.JSONB{
+			"access_token": "access-sandbox-webhook-test",
+			"item_id":      "webhook-item",
+		},
+	})
+
+	fakeClient := &plaidfake.Client{
+		WebhookVerificationKeyGetFunc: func(req plaid.WebhookVerificationKeyGetRequest) (plaid.WebhookVerificationKeyGetResponse, error) {
+			var resp plaid.WebhookVerificationKeyGetResponse
+			assert.NoError(t, json.Unmarshal([]byte(key.expiredJWKResponseBody()), &resp))
+			return resp, nil
+		},
+	}
+	handler := controllers.NewPlaidHandler(fakeClient, database)
+
+	router := SetupRouter()
+	router.POST("/api/plaid/webhook", handler.PlaidWebhook)
+
+	return router
+}
+
+func postPlaidWebhook(router *gin.Engine, body string, verification string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/api/plaid/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if verification != "" {
+		req.Header.Set("Plaid-Verification", verification)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPlaidWebhook_ValidSignatureTriggersSync(t *testing.T) {
+	t.Parallel()
+	key := newPlaidWebhookTestKey(t)
+	database, connection, router := setupPlaidWebhookFixtures(t, key)
+
+	body := `{"webhook_type":"TRANSACTIONS","webhook_code":"SYNC_UPDATES_AVAILABLE","item_id":"webhook-item"}`
+	verification := key.sign(t, []byte(body), 0)
+
+	w := postPlaidWebhook(router, body, verification)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.BankConnection
+	assert.NoError(t, database.First(&updated, connection.ID).Error)
+}
+
+func TestPlaidWebhook_ExpiredSignatureRejected(t *testing.T) {
+	t.Parallel()
+	key := newPlaidWebhookTestKey(t)
+	_, _, router := setupPlaidWebhookFixtures(t, key)
+
+	body := `{"webhook_type":"TRANSACTIONS","webhook_code":"SYNC_UPDATES_AVAILABLE","item_id":"webhook-item"}`
+	verification := key.sign(t, []byte(body), 10*time.Minute)
+
+	w := postPlaidWebhook(router, body, verification)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestPlaidWebhook_ExpiredSigningKeyRejected(t *testing.T) {
+	t.Parallel()
+	key := newPlaidWebhookTestKey(t)
+	router := setupPlaidWebhookFixturesWithExpiredKey(t, key)
+
+	body := `{"webhook_type":"TRANSACTIONS","webhook_code":"SYNC_UPDATES_AVAILABLE","item_id":"webhook-item"}`
+	verification := key.sign(t, []byte(body), 0)
+
+	w := postPlaidWebhook(router, body, verification)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestPlaidWebhook_BodyHashMismatchRejected(t *testing.T) {
+	t.Parallel()
+	key := newPlaidWebhookTestKey(t)
+	_, _, router := setupPlaidWebhookFixtures(t, key)
+
+	signedBody := `{"webhook_type":"TRANSACTIONS","webhook_code":"SYNC_UPDATES_AVAILABLE","item_id":"webhook-item"}`
+	verification := key.sign(t, []byte(signedBody), 0)
+
+	tamperedBody := `{"webhook_type":"TRANSACTIONS","webhook_code":"SYNC_UPDATES_AVAILABLE","item_id":"other-item"}`
+	w := postPlaidWebhook(router, tamperedBody, verification)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestPlaidWebhook_UnknownItemIDAcknowledgedWithoutDispatch(t *testing.T) {
+	t.Parallel()
+	key := newPlaidWebhookTestKey(t)
+	_, _, router := setupPlaidWebhookFixtures(t, key)
+
+	body := `{"webhook_type":"TRANSACTIONS","webhook_code":"SYNC_UPDATES_AVAILABLE","item_id":"no-such-item"}`
+	verification := key.sign(t, []byte(body), 0)
+
+	w := postPlaidWebhook(router, body, verification)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Acknowledged bool `json:"acknowledged"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Acknowledged)
+}
+
+func TestPlaidWebhook_ItemErrorPersistsLastErrorAndStatus(t *testing.T) {
+	t.Parallel()
+	key := newPlaidWebhookTestKey(t)
+	database, connection, router := setupPlaidWebhookFixtures(t, key)
+
+	body := `{"webhook_type":"ITEM","webhook_code":"ERROR","item_id":"webhook-item","error":{"error_code":"ITEM_LOGIN_REQUIRED","error_message":"the login details are no longer valid"}}`
+	verification := key.sign(t, []byte(body), 0)
+
+	w := postPlaidWebhook(router, body, verification)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.BankConnection
+	assert.NoError(t, database.First(&updated, connection.ID).Error)
+	assert.Equal(t, "error", updated.Status)
+	lastError, ok := updated.Metadata["last_error"].(map[string]interface{})
+	if assert.True(t, ok, "metadata should carry last_error") {
+		assert.Equal(t, "ITEM_LOGIN_REQUIRED", lastError["error_code"])
+	}
+}
+
+func TestPlaidWebhook_PendingExpirationSetsNeedsReauth(t *testing.T) {
+	t.Parallel()
+	key := newPlaidWebhookTestKey(t)
+	database, connection, router := setupPlaidWebhookFixtures(t, key)
+
+	body := `{"webhook_type":"ITEM","webhook_code":"PENDING_EXPIRATION","item_id":"webhook-item"}`
+	verification := key.sign(t, []byte(body), 0)
+
+	w := postPlaidWebhook(router, body, verification)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.BankConnection
+	assert.NoError(t, database.First(&updated, connection.ID).Error)
+	assert.Equal(t, "expiring", updated.Status)
+	assert.True(t, updated.NeedsReauth)
+}
+
+func TestPlaidWebhook_UserPermissionRevokedSetsStatus(t *testing.T) {
+	t.Parallel()
+	key := newPlaidWebhookTestKey(t)
+	database, connection, router := setupPlaidWebhookFixtures(t, key)
+
+	body := `{"webhook_type":"ITEM","webhook_code":"USER_PERMISSION_REVOKED","item_id":"webhook-item"}`
+	verification := key.sign(t, []byte(body), 0)
+
+	w := postPlaidWebhook(router, body, verification)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.BankConnection
+	assert.NoError(t, database.First(&updated, connection.ID).Error)
+	assert.Equal(t, "revoked", updated.Status)
+}
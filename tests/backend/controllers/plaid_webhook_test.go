@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type PlaidWebhookTestSuite struct {
+	suite.Suite
+	database *gorm.DB
+	router   *gin.Engine
+}
+
+func (suite *PlaidWebhookTestSuite) SetupSuite() {
+	testDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+
+	db.DB = testDB
+	suite.database = testDB
+
+	err = testDB.AutoMigrate(&models.User{}, &models.BankConnection{})
+	suite.Require().NoError(err)
+
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.New()
+	suite.router.POST("/api/plaid/webhook", controllers.PlaidWebhook)
+}
+
+func (suite *PlaidWebhookTestSuite) TearDownSuite() {
+	if suite.database != nil {
+		sqlDB, _ := suite.database.DB()
+		sqlDB.Close()
+	}
+}
+
+func (suite *PlaidWebhookTestSuite) TestPlaidWebhook_MissingVerificationHeader() {
+	body := `{"webhook_type":"TRANSACTIONS","webhook_code":"SYNC_UPDATES_AVAILABLE","item_id":"test_item"}`
+	req, _ := http.NewRequest("POST", "/api/plaid/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+}
+
+func (suite *PlaidWebhookTestSuite) TestPlaidWebhook_InvalidVerificationToken() {
+	body := `{"webhook_type":"TRANSACTIONS","webhook_code":"SYNC_UPDATES_AVAILABLE","item_id":"test_item"}`
+	req, _ := http.NewRequest("POST", "/api/plaid/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Plaid-Verification", "not-a-jwt")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+}
+
+func TestPlaidWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(PlaidWebhookTestSuite))
+}
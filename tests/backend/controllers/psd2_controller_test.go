@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type PSD2ControllerTestSuite struct {
+	suite.Suite
+	database   *gorm.DB
+	router     *gin.Engine
+	normalUser models.User
+	otherUser  models.User
+	token      string
+	connection models.BankConnection
+	account    models.BankAccount
+}
+
+func (suite *PSD2ControllerTestSuite) SetupSuite() {
+	testDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	db.DB = testDB
+	suite.database = testDB
+
+	suite.Require().NoError(testDB.AutoMigrate(
+		&models.User{},
+		&models.Account{},
+		&models.BankConnection{},
+		&models.BankAccount{},
+		&models.BankSyncLog{},
+	))
+
+	hashedPassword, err := controllers.HashPassword("password123")
+	suite.Require().NoError(err)
+
+	suite.normalUser = models.User{Username: "psd2user", Email: "psd2user@example.com", PasswordHash: hashedPassword, Role: models.UserRoleUser}
+	suite.otherUser = models.User{Username: "psd2other", Email: "psd2other@example.com", PasswordHash: hashedPassword, Role: models.UserRoleUser}
+	suite.database.Create(&suite.normalUser)
+	suite.database.Create(&suite.otherUser)
+
+	suite.token, err = controllers.GenerateToken(suite.normalUser.ID, suite.normalUser.Username, string(suite.normalUser.Role))
+	suite.Require().NoError(err)
+
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.New()
+
+	authGroup := suite.router.Group("/api")
+	authGroup.Use(controllers.AuthMiddleware())
+	{
+		authGroup.POST("/banks/psd2/consent", controllers.CreatePSD2Consent)
+		authGroup.GET("/banks/psd2/consent/:id/status", controllers.GetPSD2ConsentStatus)
+		authGroup.POST("/banks/psd2/accounts/:id/sync", controllers.SyncPSD2Account)
+	}
+}
+
+func (suite *PSD2ControllerTestSuite) SetupTest() {
+	suite.database.Unscoped().Where("1 = 1").Delete(&models.BankAccount{})
+	suite.database.Unscoped().Where("1 = 1").Delete(&models.BankConnection{})
+	suite.database.Unscoped().Where("1 = 1").Delete(&models.BankSyncLog{})
+
+	suite.connection = models.BankConnection{
+		UserID:            suite.normalUser.ID,
+		BankName:          "sparebanken_norge",
+		BankEndpoint:      "https://psd2.spvapi.no",
+		ConsentID:         fmt.Sprintf("psd2_consent_%d", time.Now().UnixNano()),
+		ConsentStatus:     "valid",
+		ConsentValidUntil: time.Now().Add(90 * 24 * time.Hour),
+		Status:            "connected",
+	}
+	suite.database.Create(&suite.connection)
+
+	suite.account = models.BankAccount{
+		BankConnectionID: suite.connection.ID,
+		AccountID:        "psd2_acc_1",
+		IBAN:             "NO9386011117947",
+		AccountName:      "Checking",
+		Currency:         "NOK",
+		AccountType:      "checking",
+		IsActive:         true,
+	}
+	suite.database.Create(&suite.account)
+}
+
+func (suite *PSD2ControllerTestSuite) TearDownSuite() {
+	if suite.database != nil {
+		sqlDB, _ := suite.database.DB()
+		sqlDB.Close()
+	}
+}
+
+// ============================================
+// CreatePSD2Consent
+// ============================================
+func (suite *PSD2ControllerTestSuite) TestCreatePSD2Consent_MissingBankName() {
+	req, _ := http.NewRequest("POST", "/api/banks/psd2/consent", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *PSD2ControllerTestSuite) TestCreatePSD2Consent_UnsupportedBankName() {
+	body := []byte(`{"bank_name": "some_unsupported_bank"}`)
+	req, _ := http.NewRequest("POST", "/api/banks/psd2/consent", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	suite.Equal("unsupported bank_name", response["error"])
+}
+
+func (suite *PSD2ControllerTestSuite) TestCreatePSD2Consent_Unauthorized() {
+	body := []byte(`{"bank_name": "bulder_bank"}`)
+	req, _ := http.NewRequest("POST", "/api/banks/psd2/consent", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+}
+
+// ============================================
+// GetPSD2ConsentStatus
+// ============================================
+func (suite *PSD2ControllerTestSuite) TestGetPSD2ConsentStatus_NotFound() {
+	req, _ := http.NewRequest("GET", "/api/banks/psd2/consent/99999/status", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *PSD2ControllerTestSuite) TestGetPSD2ConsentStatus_UserIsolation() {
+	otherConnection := models.BankConnection{
+		UserID:            suite.otherUser.ID,
+		BankName:          "bulder_bank",
+		BankEndpoint:      "https://psd2-bulder.spvapi.no",
+		ConsentID:         fmt.Sprintf("other_psd2_consent_%d", time.Now().UnixNano()),
+		ConsentStatus:     "valid",
+		ConsentValidUntil: time.Now().Add(90 * 24 * time.Hour),
+		Status:            "connected",
+	}
+	suite.database.Create(&otherConnection)
+
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/banks/psd2/consent/%d/status", otherConnection.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+// ============================================
+// SyncPSD2Account
+// ============================================
+func (suite *PSD2ControllerTestSuite) TestSyncPSD2Account_NotFound() {
+	req, _ := http.NewRequest("POST", "/api/banks/psd2/accounts/99999/sync", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *PSD2ControllerTestSuite) TestSyncPSD2Account_RejectsWithoutValidConsent() {
+	suite.database.Model(&suite.connection).Update("consent_status", "expired")
+
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/banks/psd2/accounts/%d/sync", suite.account.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *PSD2ControllerTestSuite) TestSyncPSD2Account_UserIsolation() {
+	otherConnection := models.BankConnection{
+		UserID:            suite.otherUser.ID,
+		BankName:          "bulder_bank",
+		BankEndpoint:      "https://psd2-bulder.spvapi.no",
+		ConsentID:         fmt.Sprintf("other_psd2_acc_consent_%d", time.Now().UnixNano()),
+		ConsentStatus:     "valid",
+		ConsentValidUntil: time.Now().Add(90 * 24 * time.Hour),
+		Status:            "connected",
+	}
+	suite.database.Create(&otherConnection)
+	otherAccount := models.BankAccount{
+		BankConnectionID: otherConnection.ID,
+		AccountID:        "other_acc",
+		IsActive:         true,
+	}
+	suite.database.Create(&otherAccount)
+
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/banks/psd2/accounts/%d/sync", otherAccount.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func TestPSD2ControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(PSD2ControllerTestSuite))
+}
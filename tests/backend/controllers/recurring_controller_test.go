@@ -0,0 +1,57 @@
+package controllers_test
+
+import (
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaterializeRecurringRule(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking", InitialBalanceCents: 1000, CurrentBalance: decimal.NewFromInt(1000).Div(decimal.NewFromInt(100))}
+	database.Create(&account)
+
+	rule := models.RecurringRule{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		AmountCents: -500,
+		Description: "Gym membership",
+		RRule:       "FREQ=MONTHLY;BYMONTHDAY=1",
+		NextRun:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	database.Create(&rule)
+
+	transaction, err := controllers.MaterializeRecurringRule(rule.ID)
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(-500).Div(decimal.NewFromInt(100)).Equal(transaction.Amount))
+
+	var updatedAccount models.Account
+	database.First(&updatedAccount, account.ID)
+	assert.True(t, decimal.NewFromInt(500).Div(decimal.NewFromInt(100)).Equal(updatedAccount.CurrentBalance), "Balance should reflect the materialized occurrence")
+
+	var updatedRule models.RecurringRule
+	database.First(&updatedRule, rule.ID)
+	assert.Equal(t, 2024, updatedRule.NextRun.Year())
+	assert.Equal(t, time.February, updatedRule.NextRun.Month(), "NextRun should advance to the following month")
+	assert.NotNil(t, updatedRule.LastMaterializedAt)
+
+	// Re-running the same (already-materialized) occurrence must not double-post.
+	updatedRule.NextRun = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	database.Save(&updatedRule)
+
+	_, err = controllers.MaterializeRecurringRule(rule.ID)
+	assert.NoError(t, err)
+
+	var transactionCount int64
+	database.Model(&models.Transaction{}).Where("account_id = ?", account.ID).Count(&transactionCount)
+	assert.Equal(t, int64(1), transactionCount, "Replaying an already-materialized occurrence should not double-post")
+}
@@ -0,0 +1,115 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupReportsTestDB() *models.User {
+	database := SetupTestDB()
+	database.AutoMigrate(&models.Report{})
+	db.DB = database
+	return CreateTestUser(database)
+}
+
+func TestRunReport_CategoryMonth(t *testing.T) {
+	user := setupReportsTestDB()
+	token := GetTestToken(user.ID, user.Username)
+
+	groceries := models.Category{UserID: user.ID, Name: "Groceries", Kind: models.CategoryExpense}
+	db.DB.Create(&groceries)
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	db.DB.Create(&account)
+
+	db.DB.Create(&models.Transaction{
+		UserID: user.ID, AccountID: account.ID, CategoryID: &groceries.ID,
+		Amount: decimal.NewFromInt(-5000).Div(decimal.NewFromInt(100)), Description: "Store", TxnDate: time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC),
+	})
+	db.DB.Create(&models.Transaction{
+		UserID: user.ID, AccountID: account.ID, CategoryID: &groceries.ID,
+		Amount: decimal.NewFromInt(-3000).Div(decimal.NewFromInt(100)), Description: "Store", TxnDate: time.Date(2026, 7, 5, 0, 0, 0, 0, time.UTC),
+	})
+
+	report := models.Report{
+		UserID: user.ID, Name: "Category by month",
+		Definition: models.ReportDefinition{Dimension: models.ReportDimensionCategoryMonth},
+	}
+	db.DB.Create(&report)
+
+	router := SetupRouter()
+	router.GET("/api/reports/:id/run", controllers.AuthMiddleware(), controllers.RunReport)
+
+	req, _ := http.NewRequest("GET", "/api/reports/"+strconv.Itoa(int(report.ID))+"/run", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var tab controllers.Tabulation
+	json.Unmarshal(w.Body.Bytes(), &tab)
+	assert.Equal(t, int64(8000), tab.Subtotals["total_cents"])
+	if assert.Len(t, tab.Children, 1) {
+		assert.Equal(t, "Groceries", tab.Children[0].Label)
+		assert.Len(t, tab.Children[0].Series, 2)
+	}
+}
+
+func TestRunReport_TopCounterparties(t *testing.T) {
+	user := setupReportsTestDB()
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	db.DB.Create(&account)
+	db.DB.Create(&models.Transaction{UserID: user.ID, AccountID: account.ID, Amount: decimal.NewFromInt(-1000).Div(decimal.NewFromInt(100)), Description: "Coffee Shop", TxnDate: time.Now()})
+	db.DB.Create(&models.Transaction{UserID: user.ID, AccountID: account.ID, Amount: decimal.NewFromInt(-9000).Div(decimal.NewFromInt(100)), Description: "Landlord", TxnDate: time.Now()})
+
+	report := models.Report{
+		UserID: user.ID, Name: "Top counterparties",
+		Definition: models.ReportDefinition{Dimension: models.ReportDimensionTopCounterparties, TopN: 1},
+	}
+	db.DB.Create(&report)
+
+	router := SetupRouter()
+	router.GET("/api/reports/:id/run", controllers.AuthMiddleware(), controllers.RunReport)
+
+	req, _ := http.NewRequest("GET", "/api/reports/"+strconv.Itoa(int(report.ID))+"/run", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var tab controllers.Tabulation
+	json.Unmarshal(w.Body.Bytes(), &tab)
+	if assert.Len(t, tab.Children, 1) {
+		assert.Equal(t, "Landlord", tab.Children[0].Label)
+		assert.Equal(t, int64(9000), tab.Children[0].Subtotals["total_cents"])
+	}
+}
+
+func TestRunReport_UnknownIDIsNotFound(t *testing.T) {
+	user := setupReportsTestDB()
+	token := GetTestToken(user.ID, user.Username)
+
+	router := SetupRouter()
+	router.GET("/api/reports/:id/run", controllers.AuthMiddleware(), controllers.RunReport)
+
+	req, _ := http.NewRequest("GET", "/api/reports/99999/run", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,106 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/middleware"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTokenBlacklist_RevokeThenIsRevoked is a unit-level round trip of the
+// jti blacklist DeleteUserAccount/DeleteUserAdmin use to kill an access
+// token before its natural expiry: a jti that was never revoked reads back
+// false, and one revoked via RevokeToken reads back true until its exp.
+func TestTokenBlacklist_RevokeThenIsRevoked(t *testing.T) {
+	jti := "test-jti-" + time.Now().Format(time.RFC3339Nano)
+	assert.False(t, middleware.IsTokenRevoked(jti))
+
+	middleware.RevokeToken(jti, time.Now().Add(time.Minute))
+	assert.True(t, middleware.IsTokenRevoked(jti))
+}
+
+// TestTokenBlacklist_RevokeOfAlreadyExpiredTokenIsNoop confirms RevokeToken
+// doesn't bother blacklisting a jti whose exp has already passed - there's
+// nothing left for IsTokenRevoked to protect against.
+func TestTokenBlacklist_RevokeOfAlreadyExpiredTokenIsNoop(t *testing.T) {
+	jti := "test-jti-expired-" + time.Now().Format(time.RFC3339Nano)
+	middleware.RevokeToken(jti, time.Now().Add(-time.Minute))
+	assert.False(t, middleware.IsTokenRevoked(jti))
+}
+
+// TestAuthMiddleware_RevokedJTIRejectedBeforeExpiry confirms
+// RequireAccessToken (AuthMiddleware) rejects a token whose jti has been
+// revoked even though the token itself is still within its exp - the same
+// path DeleteUserAccount/DeleteUserAdmin rely on to stop a token dead
+// instead of waiting out its remaining accessTokenTTL.
+func TestAuthMiddleware_RevokedJTIRejectedBeforeExpiry(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	router := SetupRouter()
+	router.GET("/api/protected", controllers.AuthMiddleware(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	req, _ := http.NewRequest("GET", "/api/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "token should work before revocation")
+
+	parsed, err := controllers.ParseToken(token)
+	require.NoError(t, err)
+	claims := parsed.Claims.(jwt.MapClaims)
+	jti, _ := claims["jti"].(string)
+	require.NotEmpty(t, jti)
+	expUnix, _ := claims["exp"].(float64)
+	middleware.RevokeToken(jti, time.Unix(int64(expUnix), 0))
+
+	req, _ = http.NewRequest("GET", "/api/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "revoked jti should be rejected even though the token hasn't expired")
+}
+
+// TestAuthMiddleware_RevocationIsPerTokenNotPerUser confirms revoking one
+// access token's jti doesn't affect a different token for the same user
+// (e.g. one issued to another device) - the blacklist is keyed by jti, not
+// by subject.
+func TestAuthMiddleware_RevocationIsPerTokenNotPerUser(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	revokedToken := GetTestToken(user.ID, user.Username)
+	otherToken := GetTestToken(user.ID, user.Username)
+	require.NotEqual(t, revokedToken, otherToken, "two tokens for the same user must carry distinct jtis")
+
+	parsed, err := controllers.ParseToken(revokedToken)
+	require.NoError(t, err)
+	claims := parsed.Claims.(jwt.MapClaims)
+	jti, _ := claims["jti"].(string)
+	expUnix, _ := claims["exp"].(float64)
+	middleware.RevokeToken(jti, time.Unix(int64(expUnix), 0))
+
+	router := SetupRouter()
+	router.GET("/api/protected", controllers.AuthMiddleware(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	req, _ := http.NewRequest("GET", "/api/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+otherToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "a different token for the same user must still be accepted")
+}
@@ -3,6 +3,7 @@ package controllers_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"Personal-Finance-Tracker-backend/db"
 	"Personal-Finance-Tracker-backend/models"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,7 +28,7 @@ func TestCreateTransaction(t *testing.T) {
 		Name:                "Test Account",
 		Type:                "checking",
 		InitialBalanceCents: 1000,
-		CurrentBalanceCents: 1000,
+		CurrentBalance:      decimal.NewFromInt(1000).Div(decimal.NewFromInt(100)),
 	}
 	database.Create(&account)
 
@@ -41,11 +43,11 @@ func TestCreateTransaction(t *testing.T) {
 	router.POST("/api/transactions", controllers.AuthMiddleware(), controllers.CreateTransaction)
 
 	txnData := map[string]interface{}{
-		"account_id":   account.ID,
-		"category_id":  category.ID,
-		"amount_cents": -200,
-		"description":  "Whole Foods",
-		"txn_date":     time.Now().Format("2006-01-02"),
+		"account_id":  account.ID,
+		"category_id": category.ID,
+		"amount":      -2.00,
+		"description": "Whole Foods",
+		"txn_date":    time.Now().Format("2006-01-02"),
 	}
 	body, _ := json.Marshal(txnData)
 
@@ -59,12 +61,12 @@ func TestCreateTransaction(t *testing.T) {
 
 	var response models.Transaction
 	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, int64(-200), response.AmountCents, "Transaction amount should match")
+	assert.True(t, decimal.NewFromInt(-200).Div(decimal.NewFromInt(100)).Equal(response.Amount), "Transaction amount should match")
 	assert.Equal(t, "Whole Foods", response.Description, "Transaction description should match")
 
 	var updatedAccount models.Account
 	database.First(&updatedAccount, account.ID)
-	assert.Equal(t, int64(800), updatedAccount.CurrentBalanceCents, "Account balance should be updated")
+	assert.True(t, decimal.NewFromInt(800).Div(decimal.NewFromInt(100)).Equal(updatedAccount.CurrentBalance), "Account balance should be updated")
 }
 
 func TestGetTransactions(t *testing.T) {
@@ -78,21 +80,21 @@ func TestGetTransactions(t *testing.T) {
 		Name:                "Test Account",
 		Type:                "checking",
 		InitialBalanceCents: 100000,
-		CurrentBalanceCents: 100000,
+		CurrentBalance:      decimal.NewFromInt(100000).Div(decimal.NewFromInt(100)),
 	}
 	database.Create(&account)
 
 	database.Create(&models.Transaction{
 		UserID:      user.ID,
 		AccountID:   account.ID,
-		AmountCents: -5000,
+		Amount:      decimal.NewFromInt(-5000).Div(decimal.NewFromInt(100)),
 		Description: "Transaction 1",
 		TxnDate:     time.Now(),
 	})
 	database.Create(&models.Transaction{
 		UserID:      user.ID,
 		AccountID:   account.ID,
-		AmountCents: -3000,
+		Amount:      decimal.NewFromInt(-3000).Div(decimal.NewFromInt(100)),
 		Description: "Transaction 2",
 		TxnDate:     time.Now(),
 	})
@@ -107,9 +109,13 @@ func TestGetTransactions(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code, "Expected 200 OK status")
 
-	var transactions []models.Transaction
-	json.Unmarshal(w.Body.Bytes(), &transactions)
-	assert.Len(t, transactions, 2, "Should return 2 transactions")
+	var response struct {
+		Data       []models.Transaction `json:"data"`
+		NextCursor string               `json:"next_cursor"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Data, 2, "Should return 2 transactions")
+	assert.Empty(t, response.NextCursor, "Should not have a next page")
 }
 
 func TestUpdateTransaction(t *testing.T) {
@@ -123,14 +129,14 @@ func TestUpdateTransaction(t *testing.T) {
 		Name:                "Test Account",
 		Type:                "checking",
 		InitialBalanceCents: 100000,
-		CurrentBalanceCents: 95000,
+		CurrentBalance:      decimal.NewFromInt(95000).Div(decimal.NewFromInt(100)),
 	}
 	database.Create(&account)
 
 	transaction := models.Transaction{
 		UserID:      user.ID,
 		AccountID:   account.ID,
-		AmountCents: -5000,
+		Amount:      decimal.NewFromInt(-5000).Div(decimal.NewFromInt(100)),
 		Description: "Old Description",
 		TxnDate:     time.Now(),
 	}
@@ -140,8 +146,8 @@ func TestUpdateTransaction(t *testing.T) {
 	router.PUT("/api/transactions/:id", controllers.AuthMiddleware(), controllers.UpdateTransaction)
 
 	updateData := map[string]interface{}{
-		"description":  "New Description",
-		"amount_cents": -6000,
+		"description": "New Description",
+		"amount":      -60.00,
 	}
 	body, _ := json.Marshal(updateData)
 
@@ -156,11 +162,11 @@ func TestUpdateTransaction(t *testing.T) {
 	var updatedTransaction models.Transaction
 	database.First(&updatedTransaction, transaction.ID)
 	assert.Equal(t, "New Description", updatedTransaction.Description, "Transaction description should be updated")
-	assert.Equal(t, int64(-6000), updatedTransaction.AmountCents, "Transaction amount should be updated")
+	assert.True(t, decimal.NewFromInt(-6000).Div(decimal.NewFromInt(100)).Equal(updatedTransaction.Amount), "Transaction amount should be updated")
 
 	var updatedAccount models.Account
 	database.First(&updatedAccount, account.ID)
-	assert.Equal(t, int64(94000), updatedAccount.CurrentBalanceCents, "Account balance should be updated accordingly")
+	assert.True(t, decimal.NewFromInt(94000).Div(decimal.NewFromInt(100)).Equal(updatedAccount.CurrentBalance), "Account balance should be updated accordingly")
 }
 
 func TestDeleteTransaction(t *testing.T) {
@@ -174,14 +180,14 @@ func TestDeleteTransaction(t *testing.T) {
 		Name:                "Test Account",
 		Type:                "checking",
 		InitialBalanceCents: 100000,
-		CurrentBalanceCents: 95000,
+		CurrentBalance:      decimal.NewFromInt(95000).Div(decimal.NewFromInt(100)),
 	}
 	database.Create(&account)
 
 	transaction := models.Transaction{
 		UserID:      user.ID,
 		AccountID:   account.ID,
-		AmountCents: -5000,
+		Amount:      decimal.NewFromInt(-5000).Div(decimal.NewFromInt(100)),
 		Description: "To delete",
 		TxnDate:     time.Now(),
 	}
@@ -203,5 +209,238 @@ func TestDeleteTransaction(t *testing.T) {
 
 	var updatedAccount models.Account
 	database.First(&updatedAccount, account.ID)
-	assert.Equal(t, int64(100000), updatedAccount.CurrentBalanceCents, "Account balance should be restored")
+	assert.True(t, decimal.NewFromInt(100000).Div(decimal.NewFromInt(100)).Equal(updatedAccount.CurrentBalance), "Account balance should be restored")
+}
+
+func TestGetTransactions_KeysetPagination(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{
+		UserID:              user.ID,
+		Name:                "Test Account",
+		Type:                "checking",
+		InitialBalanceCents: 100000,
+		CurrentBalance:      decimal.NewFromInt(100000).Div(decimal.NewFromInt(100)),
+	}
+	database.Create(&account)
+
+	// Three transactions on distinct days so txn_date DESC ordering is
+	// unambiguous.
+	base := time.Now()
+	for i := 0; i < 3; i++ {
+		database.Create(&models.Transaction{
+			UserID:      user.ID,
+			AccountID:   account.ID,
+			Amount:      decimal.NewFromInt(-1000).Div(decimal.NewFromInt(100)),
+			Description: fmt.Sprintf("Transaction %d", i),
+			TxnDate:     base.AddDate(0, 0, -i),
+		})
+	}
+
+	router := SetupRouter()
+	router.GET("/api/transactions", controllers.AuthMiddleware(), controllers.GetTransactions)
+
+	req, _ := http.NewRequest("GET", "/api/transactions?limit=2", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "Expected 200 OK status")
+
+	var page1 struct {
+		Data       []models.Transaction `json:"data"`
+		NextCursor string               `json:"next_cursor"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &page1)
+	assert.Len(t, page1.Data, 2, "First page should be capped at the requested limit")
+	assert.NotEmpty(t, page1.NextCursor, "First page should point to a next page")
+
+	req2, _ := http.NewRequest("GET", "/api/transactions?limit=2&cursor="+page1.NextCursor, nil)
+	req2.Header.Set("Authorization", "Bearer "+token)
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, req2)
+
+	assert.Equal(t, http.StatusOK, w2.Code, "Expected 200 OK status")
+
+	var page2 struct {
+		Data       []models.Transaction `json:"data"`
+		NextCursor string               `json:"next_cursor"`
+	}
+	json.Unmarshal(w2.Body.Bytes(), &page2)
+	assert.Len(t, page2.Data, 1, "Second page should hold the one remaining transaction")
+	assert.Empty(t, page2.NextCursor, "Second page should be the last one")
+}
+
+func TestCreateTransaction_CrossAccountSplitsBalanceBothAccounts(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	checking := models.Account{
+		UserID:              user.ID,
+		Name:                "Checking",
+		Type:                "checking",
+		Currency:            "USD",
+		InitialBalanceCents: 100000,
+		CurrentBalance:      decimal.NewFromInt(100000).Div(decimal.NewFromInt(100)),
+	}
+	database.Create(&checking)
+
+	cash := models.Account{
+		UserID:              user.ID,
+		Name:                "Cash",
+		Type:                "cash",
+		Currency:            "USD",
+		InitialBalanceCents: 0,
+		CurrentBalance:      decimal.NewFromInt(0).Div(decimal.NewFromInt(100)),
+	}
+	database.Create(&cash)
+
+	category := models.Category{
+		UserID: user.ID,
+		Name:   "ATM Withdrawal",
+		Kind:   models.CategoryExpense,
+	}
+	database.Create(&category)
+
+	router := SetupRouter()
+	router.POST("/api/transactions", controllers.AuthMiddleware(), controllers.CreateTransaction)
+
+	// Withdrawing 200 from checking into cash: the split names the cash
+	// account explicitly, so the two legs must net to zero in USD.
+	txnData := map[string]interface{}{
+		"account_id":  checking.ID,
+		"amount":      -200.00,
+		"description": "ATM withdrawal",
+		"txn_date":    time.Now().Format("2006-01-02"),
+		"splits": []map[string]interface{}{
+			{"category_id": category.ID, "amount": 200.00, "account_id": cash.ID},
+		},
+	}
+	body, _ := json.Marshal(txnData)
+
+	req, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code, "Expected 201 Created status")
+
+	var updatedChecking models.Account
+	database.First(&updatedChecking, checking.ID)
+	assert.True(t, decimal.NewFromInt(80000).Div(decimal.NewFromInt(100)).Equal(updatedChecking.CurrentBalance), "Checking should be debited")
+
+	var updatedCash models.Account
+	database.First(&updatedCash, cash.ID)
+	assert.True(t, decimal.NewFromInt(20000).Div(decimal.NewFromInt(100)).Equal(updatedCash.CurrentBalance), "Cash should be credited")
+}
+
+func TestCreateTransaction_RejectsUnbalancedCrossAccountSplits(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	checking := models.Account{
+		UserID:              user.ID,
+		Name:                "Checking",
+		Type:                "checking",
+		Currency:            "USD",
+		InitialBalanceCents: 100000,
+		CurrentBalance:      decimal.NewFromInt(100000).Div(decimal.NewFromInt(100)),
+	}
+	database.Create(&checking)
+
+	cash := models.Account{
+		UserID:              user.ID,
+		Name:                "Cash",
+		Type:                "cash",
+		Currency:            "USD",
+		InitialBalanceCents: 0,
+		CurrentBalance:      decimal.NewFromInt(0).Div(decimal.NewFromInt(100)),
+	}
+	database.Create(&cash)
+
+	category := models.Category{
+		UserID: user.ID,
+		Name:   "ATM Withdrawal",
+		Kind:   models.CategoryExpense,
+	}
+	database.Create(&category)
+
+	router := SetupRouter()
+	router.POST("/api/transactions", controllers.AuthMiddleware(), controllers.CreateTransaction)
+
+	txnData := map[string]interface{}{
+		"account_id":  checking.ID,
+		"amount":      -200.00,
+		"description": "ATM withdrawal",
+		"txn_date":    time.Now().Format("2006-01-02"),
+		"splits": []map[string]interface{}{
+			{"category_id": category.ID, "amount": 190.00, "account_id": cash.ID},
+		},
+	}
+	body, _ := json.Marshal(txnData)
+
+	req, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code, "Expected 400 for an unbalanced cross-account transaction")
+}
+
+// TestUpdateTransactionStatus_VoidRecomputesBalanceAtomically exercises the
+// status transition that, per UpdateTransactionStatusTx, recomputes the
+// owning account's balance in the same DB transaction as the status write.
+func TestUpdateTransactionStatus_VoidRecomputesBalanceAtomically(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{
+		UserID:              user.ID,
+		Name:                "Checking",
+		Type:                "checking",
+		InitialBalanceCents: 1000,
+		CurrentBalance:      decimal.NewFromInt(800).Div(decimal.NewFromInt(100)),
+	}
+	database.Create(&account)
+
+	transaction := models.Transaction{
+		UserID:      user.ID,
+		AccountID:   account.ID,
+		Amount:      decimal.NewFromInt(-200).Div(decimal.NewFromInt(100)),
+		Description: "Coffee",
+		TxnDate:     time.Now(),
+		Status:      models.TransactionCleared,
+	}
+	database.Create(&transaction)
+
+	router := SetupRouter()
+	router.PATCH("/api/transactions/:id/status", controllers.AuthMiddleware(), controllers.UpdateTransactionStatus)
+
+	body, _ := json.Marshal(map[string]interface{}{"status": models.TransactionVoided})
+	req, _ := http.NewRequest("PATCH", fmt.Sprintf("/api/transactions/%d/status", transaction.ID), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "Expected 200 OK status")
+
+	var response models.Transaction
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, models.TransactionVoided, response.Status)
+
+	var updatedAccount models.Account
+	database.First(&updatedAccount, account.ID)
+	assert.True(t, decimal.NewFromInt(1000).Div(decimal.NewFromInt(100)).Equal(updatedAccount.CurrentBalance), "Voiding the only transaction should leave the balance at the initial balance")
 }
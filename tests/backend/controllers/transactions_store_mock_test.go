@@ -0,0 +1,114 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/mocks"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/store"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateTransactionWithMockStore asserts the exact store call
+// CreateTransaction makes and verifies the HTTP response, without touching a
+// database.
+func TestCreateTransactionWithMockStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockStore(ctrl)
+	originalStore := controllers.TransactionStore
+	controllers.TransactionStore = func() store.Store { return mockStore }
+	defer func() { controllers.TransactionStore = originalStore }()
+
+	// Account/category ownership checks in CreateTransaction still go
+	// through db.DB, so point it at an in-memory database seeded with the
+	// account/category the request references.
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+
+	expected := models.Transaction{ID: 42, UserID: user.ID, AccountID: account.ID, Amount: decimal.NewFromInt(-1500).Div(decimal.NewFromInt(100)), Description: "Coffee"}
+	mockStore.EXPECT().
+		CreateTransactionTx(gomock.Any(), gomock.Any()).
+		Return(expected, nil)
+
+	router := SetupRouter()
+	router.POST("/api/transactions", controllers.AuthMiddleware(), controllers.CreateTransaction)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"account_id":   account.ID,
+		"amount_cents": -1500,
+		"description":  "Coffee",
+		"txn_date":     time.Now().Format("2006-01-02"),
+	})
+
+	req, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.Transaction
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, uint(42), response.ID)
+}
+
+// TestCreateTransactionStoreError simulates a store-level failure (e.g. a
+// deadlock or constraint violation) and asserts it surfaces as a 500.
+func TestCreateTransactionStoreError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockStore(ctrl)
+	originalStore := controllers.TransactionStore
+	controllers.TransactionStore = func() store.Store { return mockStore }
+	defer func() { controllers.TransactionStore = originalStore }()
+
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	account := models.Account{UserID: user.ID, Name: "Checking", Type: "checking"}
+	database.Create(&account)
+
+	mockStore.EXPECT().
+		CreateTransactionTx(gomock.Any(), gomock.Any()).
+		Return(models.Transaction{}, errors.New("deadlock detected"))
+
+	router := SetupRouter()
+	router.POST("/api/transactions", controllers.AuthMiddleware(), controllers.CreateTransaction)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"account_id":   account.ID,
+		"amount_cents": -1500,
+		"description":  "Coffee",
+		"txn_date":     time.Now().Format("2006-01-02"),
+	})
+
+	req, _ := http.NewRequest("POST", "/api/transactions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,77 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/controllers"
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTransfer(t *testing.T) {
+	database := SetupTestDB()
+	db.DB = database
+	user := CreateTestUser(database)
+	token := GetTestToken(user.ID, user.Username)
+
+	checking := models.Account{UserID: user.ID, Name: "Checking", Type: "checking", InitialBalanceCents: 10000, CurrentBalance: decimal.NewFromInt(10000).Div(decimal.NewFromInt(100))}
+	savings := models.Account{UserID: user.ID, Name: "Savings", Type: "savings", InitialBalanceCents: 0, CurrentBalance: decimal.Zero}
+	database.Create(&checking)
+	database.Create(&savings)
+
+	router := SetupRouter()
+	router.POST("/api/transfers", controllers.AuthMiddleware(), controllers.CreateTransfer)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"from_account_id": checking.ID,
+		"to_account_id":   savings.ID,
+		"amount_cents":    2500,
+		"transfer_id":     "transfer-1",
+	})
+
+	req, _ := http.NewRequest("POST", "/api/transfers", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code, "Expected 201 Created status")
+
+	var updatedChecking, updatedSavings models.Account
+	database.First(&updatedChecking, checking.ID)
+	database.First(&updatedSavings, savings.ID)
+	assert.True(t, decimal.NewFromInt(7500).Div(decimal.NewFromInt(100)).Equal(updatedChecking.CurrentBalance), "Source balance should be debited")
+	assert.True(t, decimal.NewFromInt(2500).Div(decimal.NewFromInt(100)).Equal(updatedSavings.CurrentBalance), "Destination balance should be credited")
+
+	var entries []models.LedgerEntry
+	database.Find(&entries)
+	assert.Len(t, entries, 2, "Transfer should create exactly two ledger entries")
+	var sum int64
+	for _, e := range entries {
+		sum += e.AmountCents
+	}
+	assert.Equal(t, int64(0), sum, "Ledger entries must sum to zero")
+
+	// Replaying the same transfer_id must not double-post.
+	req2, _ := http.NewRequest("POST", "/api/transfers", bytes.NewBuffer(body))
+	req2.Header.Set("Content-Type", "application/json")
+	req2.Header.Set("Authorization", "Bearer "+token)
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, req2)
+
+	assert.Equal(t, http.StatusOK, w2.Code, "Replayed transfer should return the original, not create a new one")
+
+	var transferCount int64
+	database.Model(&models.Transfer{}).Count(&transferCount)
+	assert.Equal(t, int64(1), transferCount, "Replaying a transfer_id should not create a second transfer")
+
+	database.Model(&models.Account{}).First(&updatedChecking, checking.ID)
+	assert.True(t, decimal.NewFromInt(7500).Div(decimal.NewFromInt(100)).Equal(updatedChecking.CurrentBalance), "Balance should not change on replay")
+}
@@ -0,0 +1,85 @@
+package forecast_test
+
+import (
+	"testing"
+
+	"Personal-Finance-Tracker-backend/forecast"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func monthlyTotals(amounts []int64) []forecast.MonthlyTotal {
+	totals := make([]forecast.MonthlyTotal, len(amounts))
+	for i, amount := range amounts {
+		totals[i] = forecast.MonthlyTotal{Month: "2025-01", AmountCents: amount}
+	}
+	return totals
+}
+
+func TestPredictCategory_TooFewMonthsReturnsZeroValue(t *testing.T) {
+	history := monthlyTotals([]int64{10000, 12000})
+
+	prediction := forecast.PredictCategory(history, 1)
+
+	assert.Equal(t, forecast.Prediction{}, prediction)
+}
+
+func TestPredictCategory_WeightedMovingAverageFallbackForShortHistory(t *testing.T) {
+	// 6 months, steadily increasing spend - too short for the Holt-Winters
+	// branch (needs 24 months), so this exercises the weighted moving
+	// average fallback.
+	history := monthlyTotals([]int64{10000, 10500, 11000, 11500, 12000, 12500})
+
+	prediction := forecast.PredictCategory(history, 1)
+
+	assert.Equal(t, "increasing", prediction.TrendDirection)
+	assert.Greater(t, prediction.PredictedAmountCents, int64(0))
+	assert.GreaterOrEqual(t, prediction.ConfidenceScore, 0.0)
+	assert.LessOrEqual(t, prediction.ConfidenceScore, 1.0)
+}
+
+func TestPredictCategory_FlatHistoryIsStable(t *testing.T) {
+	history := monthlyTotals([]int64{10000, 10000, 10000, 10000, 10000})
+
+	prediction := forecast.PredictCategory(history, 1)
+
+	assert.Equal(t, "stable", prediction.TrendDirection)
+	assert.Equal(t, int64(10000), prediction.HistoricalAvgCents)
+	// A perfectly flat series should fit exactly, so confidence caps at 1.
+	assert.Equal(t, 1.0, prediction.ConfidenceScore)
+}
+
+func TestPredictCategory_HoltWintersBranchFollowsSeasonalPattern(t *testing.T) {
+	// Three years of a repeating 12-month seasonal pattern with a clear
+	// upward drift each year, so both the seasonal and trend components
+	// have something to pick up on.
+	base := []int64{
+		20000, 18000, 19000, 21000, 22000, 25000,
+		30000, 31000, 24000, 21000, 23000, 35000, // December spike
+	}
+	var history []forecast.MonthlyTotal
+	for year := 0; year < 3; year++ {
+		for _, amount := range base {
+			history = append(history, forecast.MonthlyTotal{
+				Month:       "2025-01",
+				AmountCents: amount + int64(year)*5000,
+			})
+		}
+	}
+
+	prediction := forecast.PredictCategory(history, 1)
+
+	assert.Equal(t, "increasing", prediction.TrendDirection)
+	assert.Greater(t, prediction.PredictedAmountCents, int64(0))
+	assert.Greater(t, prediction.ConfidenceScore, 0.5, "a clean repeating seasonal pattern should fit well")
+}
+
+func TestPredictCategory_NeverPredictsNegativeSpend(t *testing.T) {
+	// A sharply declining series could extrapolate past zero without a
+	// floor.
+	history := monthlyTotals([]int64{50000, 30000, 10000, 1000, 100})
+
+	prediction := forecast.PredictCategory(history, 3)
+
+	assert.GreaterOrEqual(t, prediction.PredictedAmountCents, int64(0))
+}
@@ -0,0 +1,58 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"Personal-Finance-Tracker-backend/migrations"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type MigrationTestSuite struct {
+	suite.Suite
+	database *gorm.DB
+}
+
+func (suite *MigrationTestSuite) SetupTest() {
+	testDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.database = testDB
+}
+
+func (suite *MigrationTestSuite) TestMigrate_AppliesAllRegisteredMigrationsOnce() {
+	suite.Require().NoError(migrations.Migrate(suite.database))
+
+	statuses, err := migrations.Status(suite.database)
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(statuses)
+	for _, s := range statuses {
+		suite.True(s.Applied, "migration %d_%s should be applied", s.Version, s.Name)
+		suite.NotNil(s.AppliedAt)
+	}
+
+	// Re-running should be a no-op: same applied count, no duplicate rows.
+	suite.Require().NoError(migrations.Migrate(suite.database))
+	var count int64
+	suite.database.Model(&migrations.SchemaMigration{}).Count(&count)
+	suite.Equal(int64(len(migrations.All)), count)
+}
+
+func (suite *MigrationTestSuite) TestRollback_UnknownVersionErrors() {
+	err := migrations.Rollback(suite.database, 99999)
+	suite.Error(err)
+}
+
+func (suite *MigrationTestSuite) TestRollback_RemovesSchemaMigrationRow() {
+	suite.Require().NoError(migrations.Migrate(suite.database))
+	suite.Require().NoError(migrations.Rollback(suite.database, 1))
+
+	var count int64
+	suite.database.Model(&migrations.SchemaMigration{}).Where("version = ?", 1).Count(&count)
+	suite.Equal(int64(0), count)
+}
+
+func TestMigrationTestSuite(t *testing.T) {
+	suite.Run(t, new(MigrationTestSuite))
+}
@@ -0,0 +1,41 @@
+package money_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney_Display(t *testing.T) {
+	assert.Equal(t, "$123.45", money.FromCents(12345).Display())
+	assert.Equal(t, "-$1.00", money.FromCents(-100).Display())
+	assert.Equal(t, "$0.00", money.Zero.Display())
+}
+
+func TestMoney_AddSubPercent(t *testing.T) {
+	a := money.FromCents(1000)
+	b := money.FromCents(300)
+
+	assert.Equal(t, int64(1300), a.Add(b).Cents())
+	assert.Equal(t, int64(700), a.Sub(b).Cents())
+	assert.Equal(t, int64(500), a.Percent(50).Cents())
+}
+
+func TestMoney_JSONRoundTrip(t *testing.T) {
+	original := money.FromCents(12345)
+
+	encoded, err := json.Marshal(original)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"cents":12345,"display":"$123.45"}`, string(encoded))
+
+	var decoded money.Money
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, original, decoded)
+}
+
+func TestMoney_FromDollarsRounds(t *testing.T) {
+	assert.Equal(t, int64(1235), money.FromDollars(12.346).Cents())
+}
@@ -0,0 +1,46 @@
+package timeutil_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/pkg/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYearMonth_ParseAndString(t *testing.T) {
+	ym, err := timeutil.Parse("2026-07")
+	assert.NoError(t, err)
+	assert.Equal(t, timeutil.Of(2026, 7), ym)
+	assert.Equal(t, "2026-07", ym.String())
+}
+
+func TestYearMonth_ParseInvalid(t *testing.T) {
+	_, err := timeutil.Parse("not-a-month")
+	assert.Error(t, err)
+}
+
+func TestYearMonth_Validate(t *testing.T) {
+	assert.NoError(t, timeutil.Of(2026, 7).Validate(2020, 2030))
+	assert.Error(t, timeutil.Of(2019, 7).Validate(2020, 2030))
+	assert.Error(t, timeutil.Of(2026, 13).Validate(2020, 2030))
+}
+
+func TestYearMonth_NextPrev(t *testing.T) {
+	ym := timeutil.Of(2026, 12)
+	assert.Equal(t, timeutil.Of(2027, 1), ym.Next(1))
+	assert.Equal(t, timeutil.Of(2026, 1), ym.Next(1).Prev(12))
+}
+
+func TestYearMonth_JSONRoundTrip(t *testing.T) {
+	ym := timeutil.Of(2026, 7)
+
+	encoded, err := json.Marshal(ym)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `"2026-07"`, string(encoded))
+
+	var decoded timeutil.YearMonth
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, ym, decoded)
+}
@@ -0,0 +1,124 @@
+package seed_test
+
+import (
+	"testing"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/migrations"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/seed"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type SeedTestSuite struct {
+	suite.Suite
+	database *gorm.DB
+}
+
+func (suite *SeedTestSuite) SetupTest() {
+	testDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+
+	err = testDB.AutoMigrate(
+		&models.User{},
+		&models.Account{},
+		&models.Category{},
+		&models.Transaction{},
+		&models.Budget{},
+		&models.BudgetItem{},
+	)
+	suite.Require().NoError(err)
+
+	db.DB = testDB
+	suite.database = testDB
+}
+
+func (suite *SeedTestSuite) TestResolveScenario_UnknownNameErrors() {
+	_, err := seed.ResolveScenario("does-not-exist", nil, nil)
+	suite.Error(err)
+}
+
+func (suite *SeedTestSuite) TestResolveScenario_OverridesSeedAndMonths() {
+	overrideSeed := int64(99)
+	overrideMonths := 1
+	scenario, err := seed.ResolveScenario("presentation", &overrideSeed, &overrideMonths)
+	suite.Require().NoError(err)
+
+	suite.Equal(int64(99), scenario.Seed)
+	suite.Equal(1, scenario.Months)
+	// Everything else still comes from the built-in scenario.
+	suite.Equal(seed.BuiltinScenarios["presentation"].Users, scenario.Users)
+}
+
+func (suite *SeedTestSuite) TestSeedDemoData_EmptyScenarioOnlyCreatesDemoLogin() {
+	// Admin bootstrap is a separate seeder (AdminSeeder); SeedDemoData on
+	// its own only ever creates the "demo"/"demo123" login plus whatever
+	// the scenario's synthetic users are.
+	err := seed.SeedDemoData(suite.database, seed.BuiltinScenarios["empty"])
+	suite.Require().NoError(err)
+
+	var userCount int64
+	suite.database.Model(&models.User{}).Count(&userCount)
+	suite.Equal(int64(1), userCount)
+}
+
+func (suite *SeedTestSuite) TestAdminSeeder_RunsOnceUnlessForced() {
+	ran, err := migrations.RunSeeder(suite.database, seed.AdminSeeder{}, false)
+	suite.Require().NoError(err)
+	suite.True(ran)
+
+	ran, err = migrations.RunSeeder(suite.database, seed.AdminSeeder{}, false)
+	suite.Require().NoError(err)
+	suite.False(ran, "second run without --force should be a no-op")
+
+	var adminCount int64
+	suite.database.Model(&models.User{}).Where("role = ?", models.UserRoleSuperAdmin).Count(&adminCount)
+	suite.Equal(int64(1), adminCount)
+
+	status, err := migrations.SeedersStatus(suite.database, []migrations.Seeder{seed.AdminSeeder{}})
+	suite.Require().NoError(err)
+	suite.Require().Len(status, 1)
+	suite.NotNil(status[0].RanAt)
+}
+
+func (suite *SeedTestSuite) TestSeedDemoData_IsReproducibleForSameSeed() {
+	scenario := seed.Scenario{
+		Name:                 "repro-check",
+		Users:                2,
+		Personas:             []string{"conservative", "spender"},
+		Months:               1,
+		Currency:             "USD",
+		TransactionsPerMonth: 3,
+		Seed:                 123,
+	}
+
+	err := seed.SeedDemoData(suite.database, scenario)
+	suite.Require().NoError(err)
+
+	var firstRunAmounts []int64
+	suite.database.Model(&models.Transaction{}).Order("id").Pluck("amount_cents", &firstRunAmounts)
+
+	// Re-run against a fresh database with the same seed and scenario.
+	freshDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), freshDB.AutoMigrate(
+		&models.User{}, &models.Account{}, &models.Category{},
+		&models.Transaction{}, &models.Budget{}, &models.BudgetItem{},
+	))
+
+	err = seed.SeedDemoData(freshDB, scenario)
+	suite.Require().NoError(err)
+
+	var secondRunAmounts []int64
+	freshDB.Model(&models.Transaction{}).Order("id").Pluck("amount_cents", &secondRunAmounts)
+
+	suite.Equal(firstRunAmounts, secondRunAmounts, "same scenario + seed should produce identical transaction amounts")
+}
+
+func TestSeedTestSuite(t *testing.T) {
+	suite.Run(t, new(SeedTestSuite))
+}
@@ -0,0 +1,152 @@
+package ai_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/pkg/timeutil"
+	"Personal-Finance-Tracker-backend/services/ai"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAITestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(
+		&models.User{}, &models.Account{}, &models.Category{}, &models.Transaction{},
+	))
+	db.DB = database
+	return database
+}
+
+func seedUser(t *testing.T, database *gorm.DB, username string) *models.User {
+	t.Helper()
+	user := models.User{Username: username, Email: username + "@example.com", PasswordHash: "hash", Role: models.UserRoleUser}
+	require.NoError(t, database.Create(&user).Error)
+	return &user
+}
+
+func seedAccountAndCategory(t *testing.T, database *gorm.DB, userID uint) (*models.Account, *models.Category) {
+	t.Helper()
+	account := models.Account{UserID: userID, Name: "Checking", Type: models.AccountChecking, Currency: "USD"}
+	require.NoError(t, database.Create(&account).Error)
+	category := models.Category{UserID: userID, Name: "Groceries", Kind: models.CategoryExpense}
+	require.NoError(t, database.Create(&category).Error)
+	return &account, &category
+}
+
+// seedMonthlySpend creates one expense transaction per month, indexed from
+// the current month backwards - amountsCents[0] lands in the current
+// month, amountsCents[i] lands i months before it. Anchored to the 1st of
+// the month rather than today's day-of-month, since AddDate on e.g. the
+// 29th-31st can roll a short target month over into the next one (Mar 29
+// minus 1 month lands on Feb 29, which doesn't exist in a non-leap year,
+// so time.Time rolls it to Mar 1) and collide with a neighboring month.
+func seedMonthlySpend(t *testing.T, database *gorm.DB, userID uint, accountID, categoryID uint, amountsCents []int64) {
+	t.Helper()
+	firstOfMonth := time.Now()
+	now := time.Date(firstOfMonth.Year(), firstOfMonth.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i, amount := range amountsCents {
+		require.NoError(t, database.Create(&models.Transaction{
+			UserID:      userID,
+			AccountID:   accountID,
+			CategoryID:  &categoryID,
+			Amount:      decimal.NewFromInt(-amount).Div(decimal.NewFromInt(100)),
+			Description: "groceries",
+			TxnDate:     now.AddDate(0, -i, 0),
+			Status:      models.TransactionEntered,
+		}).Error)
+	}
+}
+
+func TestPredict_ReturnsPerCategoryForecast(t *testing.T) {
+	database := setupAITestDB(t)
+	user := seedUser(t, database, "predict-user")
+	account, category := seedAccountAndCategory(t, database, user.ID)
+
+	// Oldest to newest (amountsCents is indexed backwards from now), so
+	// spend rises from 40000 to 45000 over the six months.
+	seedMonthlySpend(t, database, user.ID, account.ID, category.ID, []int64{
+		45000, 44000, 43000, 42000, 41000, 40000,
+	})
+
+	now := time.Now()
+	resp, err := ai.NewService().Predict(context.Background(), ai.PredictRequest{
+		UserID:           user.ID,
+		TargetPeriod:     timeutil.FromTime(now),
+		HistoricalMonths: 12,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Predictions, 1)
+	pred := resp.Predictions[0]
+	assert.Equal(t, "Groceries", pred.CategoryName)
+	assert.Equal(t, "increasing", pred.TrendDirection)
+	assert.Greater(t, pred.PredictedAmount.Cents(), int64(0))
+	assert.Equal(t, 6, resp.HistoricalDataPoints)
+	assert.Equal(t, "Predictions generated successfully", resp.Message)
+}
+
+func TestPredict_NoHistoryReturnsEmptyPredictions(t *testing.T) {
+	database := setupAITestDB(t)
+	user := seedUser(t, database, "nodata-user")
+
+	resp, err := ai.NewService().Predict(context.Background(), ai.PredictRequest{
+		UserID:           user.ID,
+		TargetPeriod:     timeutil.Now(),
+		HistoricalMonths: 12,
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, resp.Predictions)
+	assert.Equal(t, 0, resp.HistoricalDataPoints)
+	assert.Contains(t, resp.Message, "Insufficient historical data")
+}
+
+func TestAnalyzePatterns_SummarizesSpend(t *testing.T) {
+	database := setupAITestDB(t)
+	user := seedUser(t, database, "patterns-user")
+	account, category := seedAccountAndCategory(t, database, user.ID)
+
+	seedMonthlySpend(t, database, user.ID, account.ID, category.ID, []int64{
+		40000, 41000, 42000, 43000,
+	})
+
+	resp, err := ai.NewService().AnalyzePatterns(context.Background(), ai.PatternsRequest{
+		UserID:           user.ID,
+		HistoricalMonths: 12,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, user.ID, resp.UserID)
+	assert.NotEmpty(t, resp.Patterns.SpendingVelocity)
+	assert.NotEmpty(t, resp.Patterns.SeasonalTrends.HighestMonth)
+	assert.NotEmpty(t, resp.Insights)
+	assert.GreaterOrEqual(t, resp.ConfidenceScore, 0.0)
+	assert.LessOrEqual(t, resp.ConfidenceScore, 1.0)
+}
+
+func TestAnalyzePatterns_NoHistoryReturnsZeroValue(t *testing.T) {
+	database := setupAITestDB(t)
+	user := seedUser(t, database, "patterns-nodata-user")
+
+	resp, err := ai.NewService().AnalyzePatterns(context.Background(), ai.PatternsRequest{
+		UserID:           user.ID,
+		HistoricalMonths: 12,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, ai.Patterns{}, resp.Patterns)
+	assert.Empty(t, resp.Insights)
+	assert.Empty(t, resp.Recommendations)
+	assert.Equal(t, 0.0, resp.ConfidenceScore)
+}
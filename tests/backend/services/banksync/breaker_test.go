@@ -0,0 +1,113 @@
+package banksync_test
+
+import (
+	"testing"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/banksync"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupBreakerTestDB(t *testing.T) {
+	t.Helper()
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&models.BankSyncLog{}))
+	db.DB = database
+}
+
+func TestRecordResult_OpensBreakerAfterThreshold(t *testing.T) {
+	banksync.SetFailureThreshold(3)
+	defer banksync.SetFailureThreshold(banksync.DefaultFailureThreshold)
+
+	const connectionID = uint(1001)
+	banksync.Reset(connectionID)
+
+	var state banksync.BreakerState
+	for i := 0; i < 3; i++ {
+		state = banksync.RecordResult(connectionID, false)
+	}
+
+	assert.Equal(t, banksync.BreakerOpen, state)
+	gotState, failures := banksync.Status(connectionID)
+	assert.Equal(t, banksync.BreakerOpen, gotState)
+	assert.Equal(t, 3, failures)
+}
+
+func TestRecordResult_SuccessClosesBreaker(t *testing.T) {
+	banksync.SetFailureThreshold(2)
+	defer banksync.SetFailureThreshold(banksync.DefaultFailureThreshold)
+
+	const connectionID = uint(1002)
+	banksync.Reset(connectionID)
+
+	banksync.RecordResult(connectionID, false)
+	banksync.RecordResult(connectionID, false)
+	state, _ := banksync.Status(connectionID)
+	require.Equal(t, banksync.BreakerOpen, state)
+
+	state = banksync.RecordResult(connectionID, true)
+	assert.Equal(t, banksync.BreakerClosed, state)
+
+	gotState, failures := banksync.Status(connectionID)
+	assert.Equal(t, banksync.BreakerClosed, gotState)
+	assert.Equal(t, 0, failures)
+}
+
+func TestAllowSync_BlocksWhileOpen(t *testing.T) {
+	banksync.SetFailureThreshold(1)
+	defer banksync.SetFailureThreshold(banksync.DefaultFailureThreshold)
+
+	const connectionID = uint(1003)
+	banksync.Reset(connectionID)
+
+	banksync.RecordResult(connectionID, false)
+	assert.False(t, banksync.AllowSync(connectionID))
+}
+
+func TestAllowSync_ClosedByDefault(t *testing.T) {
+	const connectionID = uint(1004)
+	banksync.Reset(connectionID)
+
+	assert.True(t, banksync.AllowSync(connectionID))
+}
+
+func TestReset_ClosesBreaker(t *testing.T) {
+	banksync.SetFailureThreshold(1)
+	defer banksync.SetFailureThreshold(banksync.DefaultFailureThreshold)
+
+	const connectionID = uint(1005)
+	banksync.Reset(connectionID)
+	banksync.RecordResult(connectionID, false)
+
+	state, _ := banksync.Status(connectionID)
+	require.Equal(t, banksync.BreakerOpen, state)
+
+	banksync.Reset(connectionID)
+	state, failures := banksync.Status(connectionID)
+	assert.Equal(t, banksync.BreakerClosed, state)
+	assert.Equal(t, 0, failures)
+}
+
+func TestRecover_ReopensBreakerFromPersistedFailures(t *testing.T) {
+	setupBreakerTestDB(t)
+	banksync.SetFailureThreshold(2)
+	defer banksync.SetFailureThreshold(banksync.DefaultFailureThreshold)
+
+	const connectionID = uint(2001)
+	banksync.Reset(connectionID)
+
+	require.NoError(t, db.DB.Create(&models.BankSyncLog{BankConnectionID: connectionID, Status: "failed"}).Error)
+	require.NoError(t, db.DB.Create(&models.BankSyncLog{BankConnectionID: connectionID, Status: "failed"}).Error)
+
+	require.NoError(t, banksync.Recover())
+
+	state, failures := banksync.Status(connectionID)
+	assert.Equal(t, banksync.BreakerOpen, state)
+	assert.Equal(t, 2, failures)
+}
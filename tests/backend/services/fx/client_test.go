@@ -0,0 +1,93 @@
+package fx_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/fx"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// stubProvider only answers for the single (base, quote, date) it was
+// primed with, so a test can tell a cache hit (no FetchRate call) apart
+// from a fallback to an earlier date (a FetchRate call for that date).
+type stubProvider struct {
+	rates map[string]float64
+	calls int
+}
+
+func (s *stubProvider) key(base, quote string, date time.Time) string {
+	return base + "|" + quote + "|" + date.Format("2006-01-02")
+}
+
+func (s *stubProvider) FetchRate(base, quote string, date time.Time) (float64, error) {
+	s.calls++
+	rate, ok := s.rates[s.key(base, quote, date)]
+	if !ok {
+		return 0, fmt.Errorf("stub: no rate for %s", s.key(base, quote, date))
+	}
+	return rate, nil
+}
+
+func setupFxTestDB(t *testing.T) {
+	t.Helper()
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&models.FxRate{}))
+	db.DB = database
+}
+
+func TestConvertCents_SameCurrencyIsNoOp(t *testing.T) {
+	setupFxTestDB(t)
+
+	converted, err := fx.ConvertCents(12345, "USD", "USD", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(12345), converted)
+}
+
+func TestConvertCents_UsesProviderThenCaches(t *testing.T) {
+	setupFxTestDB(t)
+
+	on := time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC)
+	stub := &stubProvider{rates: map[string]float64{
+		"USD|NOK|2026-07-20": 10.5,
+	}}
+	fx.SetProvider(stub)
+
+	converted, err := fx.ConvertCents(10000, "USD", "NOK", on)
+	require.NoError(t, err)
+	assert.Equal(t, int64(105000), converted)
+	assert.Equal(t, 1, stub.calls, "first conversion should hit the provider")
+
+	// A second conversion on the same day should be served from the
+	// models.FxRate cache, not the provider.
+	_, err = fx.ConvertCents(5000, "USD", "NOK", on)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stub.calls, "second conversion should be served from cache")
+
+	var cached models.FxRate
+	require.NoError(t, db.DB.Where("base = ? AND quote = ? AND date = ?", "USD", "NOK", on).First(&cached).Error)
+	assert.Equal(t, 10.5, cached.Rate)
+}
+
+func TestRateOn_FallsBackToNearestPriorDate(t *testing.T) {
+	setupFxTestDB(t)
+
+	published := time.Date(2026, 7, 17, 0, 0, 0, 0, time.UTC) // a Friday
+	weekend := time.Date(2026, 7, 19, 0, 0, 0, 0, time.UTC)   // no rate published
+	stub := &stubProvider{rates: map[string]float64{
+		"USD|NOK|" + published.Format("2006-01-02"): 10.5,
+	}}
+	fx.SetProvider(stub)
+
+	rate, err := fx.RateOn("USD", "NOK", weekend)
+	require.NoError(t, err)
+	assert.Equal(t, 10.5, rate, "should fall back to the last published rate before the requested date")
+}
@@ -0,0 +1,84 @@
+package fx_test
+
+import (
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/db"
+	"Personal-Finance-Tracker-backend/models"
+	"Personal-Finance-Tracker-backend/services/fx"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartRateRefresher_WarmsActiveCurrencyPairs(t *testing.T) {
+	setupFxTestDB(t)
+	require.NoError(t, db.DB.AutoMigrate(&models.User{}, &models.Account{}, &models.Budget{}))
+
+	user := models.User{Username: "refresher-user", Email: "refresher@example.com"}
+	require.NoError(t, db.DB.Create(&user).Error)
+	require.NoError(t, db.DB.Create(&models.Account{UserID: user.ID, Name: "EUR checking", Type: models.AccountChecking, Currency: "EUR"}).Error)
+	require.NoError(t, db.DB.Create(&models.Budget{
+		UserID:      user.ID,
+		PeriodStart: time.Now().AddDate(0, 0, -1),
+		PeriodEnd:   time.Now().AddDate(0, 0, 1),
+		Currency:    "NOK",
+	}).Error)
+
+	today := time.Now().Truncate(24 * time.Hour)
+	stub := &stubProvider{rates: map[string]float64{
+		"EUR|NOK|" + today.Format("2006-01-02"): 11.2,
+		"NOK|EUR|" + today.Format("2006-01-02"): 1 / 11.2,
+	}}
+	fx.SetProvider(stub)
+
+	fx.StartRateRefresher()
+	defer fx.StopRateRefresher()
+
+	require.Eventually(t, func() bool {
+		var count int64
+		db.DB.Model(&models.FxRate{}).Where("base = ? AND quote = ?", "EUR", "NOK").Count(&count)
+		return count > 0
+	}, time.Second, 10*time.Millisecond, "refresher should have cached EUR->NOK")
+
+	var cached models.FxRate
+	require.NoError(t, db.DB.Where("base = ? AND quote = ?", "EUR", "NOK").First(&cached).Error)
+	assert.Equal(t, 11.2, cached.Rate)
+}
+
+func TestRefreshNow_ReturnsOneResultPerOrderedPair(t *testing.T) {
+	setupFxTestDB(t)
+	require.NoError(t, db.DB.AutoMigrate(&models.User{}, &models.Account{}))
+
+	user := models.User{Username: "refresh-now-user", Email: "refresh-now@example.com"}
+	require.NoError(t, db.DB.Create(&user).Error)
+	require.NoError(t, db.DB.Create(&models.Account{UserID: user.ID, Name: "USD checking", Type: models.AccountChecking, Currency: "USD"}).Error)
+	require.NoError(t, db.DB.Create(&models.Account{UserID: user.ID, Name: "NOK checking", Type: models.AccountChecking, Currency: "NOK"}).Error)
+
+	today := time.Now().Truncate(24 * time.Hour)
+	stub := &stubProvider{rates: map[string]float64{
+		"USD|NOK|" + today.Format("2006-01-02"): 10.5,
+		// NOK->USD is deliberately left unset, so that pair's RefreshResult
+		// carries an Error rather than a Rate.
+	}}
+	fx.SetProvider(stub)
+
+	results := fx.RefreshNow()
+	require.Len(t, results, 2, "one result per ordered pair among the two active currencies")
+
+	var sawOK, sawErr bool
+	for _, result := range results {
+		if result.Base == "USD" && result.Quote == "NOK" {
+			assert.Equal(t, 10.5, result.Rate)
+			assert.Empty(t, result.Error)
+			sawOK = true
+		}
+		if result.Base == "NOK" && result.Quote == "USD" {
+			assert.NotEmpty(t, result.Error, "unresolvable pair should surface an error instead of panicking")
+			sawErr = true
+		}
+	}
+	assert.True(t, sawOK, "expected a successful USD->NOK result")
+	assert.True(t, sawErr, "expected a failed NOK->USD result")
+}
@@ -0,0 +1,152 @@
+package psd2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Personal-Finance-Tracker-backend/services/psd2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitiateConsent_ParsesConsentAndRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/consents", r.URL.Path)
+		assert.Equal(t, "https://app.example.com/callback", r.Header.Get("TPP-Redirect-URI"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, true, body["recurringIndicator"])
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"consentId":     "consent-123",
+			"consentStatus": "received",
+			"_links": map[string]interface{}{
+				"scaRedirect": map[string]string{"href": "https://bank.example.com/sca/consent-123"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := psd2.NewClient(server.URL)
+	result, err := client.InitiateConsent("https://app.example.com/callback", time.Now().AddDate(0, 0, 90), 4)
+	require.NoError(t, err)
+
+	assert.Equal(t, "consent-123", result.ConsentID)
+	assert.Equal(t, "received", result.Status)
+	assert.Equal(t, "https://bank.example.com/sca/consent-123", result.RedirectURL)
+}
+
+func TestConsentStatus_ReturnsCurrentStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/consents/consent-123/status", r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]string{"consentStatus": "valid"})
+	}))
+	defer server.Close()
+
+	client := psd2.NewClient(server.URL)
+	status, err := client.ConsentStatus("consent-123")
+	require.NoError(t, err)
+	assert.Equal(t, "valid", status)
+}
+
+func TestAccounts_SendsConsentIDHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "consent-123", r.Header.Get("Consent-ID"))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"accounts": []map[string]string{
+				{"resourceId": "acc-1", "iban": "NO93", "name": "Checking", "currency": "NOK"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := psd2.NewClient(server.URL)
+	accounts, err := client.Accounts("consent-123")
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	assert.Equal(t, "acc-1", accounts[0].ResourceID)
+}
+
+func TestTransactions_ReturnsBookedEntries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/accounts/acc-1/transactions", r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"transactions": map[string]interface{}{
+				"booked": []map[string]interface{}{
+					{
+						"transactionId": "txn-1",
+						"bookingDate":   "2026-01-15",
+						"transactionAmount": map[string]string{
+							"amount":   "-123.45",
+							"currency": "NOK",
+						},
+						"remittanceInformationUnstructured": "Groceries",
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := psd2.NewClient(server.URL)
+	transactions, err := client.Transactions("consent-123", "acc-1", time.Now().AddDate(0, 0, -30))
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "txn-1", transactions[0].TransactionID)
+	assert.Equal(t, "-123.45", transactions[0].Amount.Amount)
+}
+
+func TestInitiateConsentWithOptions_SendsRequestedFlagsAndAuditHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "req-abc", r.Header.Get("X-Request-ID"))
+		assert.Equal(t, "203.0.113.5", r.Header.Get("PSU-IP-Address"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, false, body["recurringIndicator"])
+		assert.Equal(t, true, body["combinedServiceIndicator"])
+		assert.Equal(t, float64(2), body["frequencyPerDay"])
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"consentId":     "consent-456",
+			"consentStatus": "received",
+		})
+	}))
+	defer server.Close()
+
+	client := psd2.NewClient(server.URL)
+	result, err := client.InitiateConsentWithOptions(
+		"https://app.example.com/callback",
+		time.Now().AddDate(0, 0, 90),
+		psd2.ConsentOptions{FrequencyPerDay: 2, CombinedServiceIndicator: true, RecurringIndicator: false},
+		psd2.AuditHeaders{RequestID: "req-abc", PSUIPAddress: "203.0.113.5"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "consent-456", result.ConsentID)
+}
+
+func TestExecute_NonSuccessStatusReturnsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := psd2.NewClient(server.URL)
+	_, err := client.ConsentStatus("consent-123")
+	assert.Error(t, err)
+}
+
+func TestProviders_MatchesBankEndpoints(t *testing.T) {
+	require.Len(t, psd2.Providers, len(psd2.BankEndpoints))
+	for bankName, provider := range psd2.Providers {
+		assert.Equal(t, psd2.BankEndpoints[bankName], provider.Endpoint)
+	}
+	assert.Equal(t, "https://psd2.spvapi.no", psd2.Providers["sparebanken_norge"].Endpoint)
+	assert.Equal(t, "https://psd2-bulder.spvapi.no", psd2.Providers["bulder_bank"].Endpoint)
+}
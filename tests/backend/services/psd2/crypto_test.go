@@ -0,0 +1,65 @@
+package psd2_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"Personal-Finance-Tracker-backend/services/psd2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestEncryptSecret_RoundTrips(t *testing.T) {
+	t.Setenv(psd2.EncryptionKeyEnv, testKey(t))
+
+	encrypted, err := psd2.EncryptSecret("super-secret-oauth-token")
+	require.NoError(t, err)
+	assert.NotEqual(t, "super-secret-oauth-token", encrypted)
+
+	decrypted, err := psd2.DecryptSecret(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "super-secret-oauth-token", decrypted)
+}
+
+func TestEncryptSecret_DifferentCiphertextEachTime(t *testing.T) {
+	t.Setenv(psd2.EncryptionKeyEnv, testKey(t))
+
+	first, err := psd2.EncryptSecret("same-plaintext")
+	require.NoError(t, err)
+	second, err := psd2.EncryptSecret("same-plaintext")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second, "each encryption should use a fresh nonce")
+}
+
+func TestEncryptSecret_MissingKeyErrors(t *testing.T) {
+	t.Setenv(psd2.EncryptionKeyEnv, "")
+
+	_, err := psd2.EncryptSecret("anything")
+	assert.Error(t, err)
+}
+
+func TestDecryptSecret_TamperedCiphertextFails(t *testing.T) {
+	t.Setenv(psd2.EncryptionKeyEnv, testKey(t))
+
+	encrypted, err := psd2.EncryptSecret("original-value")
+	require.NoError(t, err)
+
+	raw, err := base64.StdEncoding.DecodeString(encrypted)
+	require.NoError(t, err)
+	raw[len(raw)-1] ^= 0xFF
+	tampered := base64.StdEncoding.EncodeToString(raw)
+
+	_, err = psd2.DecryptSecret(tampered)
+	assert.Error(t, err)
+}